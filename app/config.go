@@ -2,14 +2,24 @@ package app
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/go-ozzo/ozzo-validation"
 	"github.com/spf13/viper"
+	"github.com/tomochain/tomox-sdk/signer"
+	"github.com/tomochain/tomox-sdk/utils/ratelimit"
 )
 
 // Config stores the application-wide configurations
 var Config appConfig
 
+// EngineSimulated is the Engine configuration value that runs the in-process matching engine
+const EngineSimulated = "simulated"
+
+// DatastoreMemory is the Datastore configuration value that keeps the collections in memory
+const DatastoreMemory = "memory"
+
 type appConfig struct {
 	// the path to the error message file. Defaults to "config/errors.yaml"
 	ErrorFile string `mapstructure:"error_file"`
@@ -20,12 +30,27 @@ type appConfig struct {
 	MongoURL        string `mapstructure:"mongo_url"`
 	MongoDBPassword string `mapstructure:"mongo_password"`
 	MongoDBUsername string `mapstructure:"mongo_username"`
+	// the timeout of the connection and of each query to the database. Defaults to 30s
+	MongoTimeout time.Duration `mapstructure:"mongo_timeout"`
 
 	// the data source name (DSN) for connecting to the database. required.
 	DBName string `mapstructure:"db_name"`
 
 	ApiAuthKey string `mapstructure:"api_auth_key"`
 
+	// the addresses allowed to place and cancel the orders of the operator wallets with the
+	// operator endpoints, which sign the orders on the server
+	TradingOperators []string `mapstructure:"trading_operators"`
+
+	// Signer selects the signer of the relayer transactions: a keystore file, an external
+	// signing process on a local socket, or a random in-memory key for development
+	Signer signer.Config `mapstructure:"signer"`
+
+	// the passphrase encrypting the private keys of the wallets stored in the database is read
+	// from the WalletPassphraseEnv environment variable, or else from the WalletPassphraseFile file
+	WalletPassphraseEnv  string `mapstructure:"wallet_passphrase_env"`
+	WalletPassphraseFile string `mapstructure:"wallet_passphrase_file"`
+
 	// the RabbitMQURL is the URI of rabbitmq to use
 	RabbitMQURL string `mapstructure:"rabbitmq_url"`
 
@@ -34,15 +59,75 @@ type appConfig struct {
 
 	Tomochain map[string]string `mapstructure:"tomochain"`
 
+	// the HTTP URLs of the TomoX nodes. The calls go to the first healthy node, and fail over to
+	// the next ones when it can not be reached. Defaults to tomochain.http_url
+	TomoXNodes []string `mapstructure:"tomox_nodes"`
+	// the timeout of each call to a TomoX node. Defaults to 10s
+	TomoXTimeout time.Duration `mapstructure:"tomox_timeout"`
+
+	// Engine selects the matching engine. Orders are sent to the TomoX node by default,
+	// and matched in process by the MatchingEngine when set to "simulated"
+	Engine string `mapstructure:"engine"`
+
+	// RateLimit are the limits of the endpoint groups (order, query and websocket) for each IP
+	// and each authenticated address. The groups and the limits that are not set are not limited
+	RateLimit map[string]ratelimit.Limits `mapstructure:"rate_limit"`
+
+	// Datastore selects where the DAOs keep the collections. They are stored in MongoDB by
+	// default, and in memory when set to "memory", which requires the simulated engine as
+	// the TomoX node writes the orders and the trades to MongoDB
+	Datastore string `mapstructure:"datastore"`
+
+	// Cluster runs the server as one of the replicas of a cluster behind a load balancer. The
+	// websocket events are then sent to all the replicas through a RabbitMQ fanout exchange, and
+	// only the replica elected as the leader watches the change streams and runs the crons
+	Cluster bool `mapstructure:"cluster"`
+
 	Env string `mapstructure:"env"`
 }
 
 func (config appConfig) Validate() error {
+	// the simulated engine and the memory datastore keep their state in the process, so the
+	// replicas of a cluster would each match on their own orderbook
+	if config.Cluster {
+		return validation.ValidateStruct(&config,
+			validation.Field(&config.MongoURL, validation.Required),
+			validation.Field(&config.Engine, validation.NotIn(EngineSimulated).Error("can not be simulated in a cluster")),
+			validation.Field(&config.Datastore, validation.NotIn(DatastoreMemory).Error("can not be memory in a cluster")),
+		)
+	}
+
+	if config.Datastore == DatastoreMemory {
+		return validation.ValidateStruct(&config,
+			validation.Field(&config.Engine, validation.In(EngineSimulated).Error("must be simulated with the memory datastore")),
+		)
+	}
+
 	return validation.ValidateStruct(&config,
 		validation.Field(&config.MongoURL, validation.Required),
 	)
 }
 
+// NodeURLs returns the URLs of the TomoX nodes, which is tomochain.http_url when no node is
+// configured
+func (config appConfig) NodeURLs() []string {
+	if len(config.TomoXNodes) > 0 {
+		return config.TomoXNodes
+	}
+
+	return []string{config.Tomochain["http_url"]}
+}
+
+// TradingOperatorAddresses returns the addresses of the trading operators
+func (config appConfig) TradingOperatorAddresses() []common.Address {
+	addresses := []common.Address{}
+	for _, addr := range config.TradingOperators {
+		addresses = append(addresses, common.HexToAddress(addr))
+	}
+
+	return addresses
+}
+
 // LoadConfig loads configuration from the given list of paths and populates it into the Config variable.
 // The configuration file(s) should be named as app.yaml.
 // Environment variables with the prefix "RESTFUL_" in their names are also read automatically.
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidateCluster(t *testing.T) {
+	config := appConfig{MongoURL: "mongodb://localhost", Cluster: true}
+	assert.NoError(t, config.Validate())
+
+	// each replica would match the orders on its own orderbook
+	config.Engine = EngineSimulated
+	assert.Error(t, config.Validate())
+
+	config.Datastore = DatastoreMemory
+	assert.Error(t, config.Validate())
+
+	config.Cluster = false
+	assert.NoError(t, config.Validate())
+}
@@ -0,0 +1,244 @@
+// Package client is a Go client of the REST and websocket APIs of the SDK. It signs the orders
+// with a wallet, keeps track of the order nonces of the wallet, and streams the websocket
+// channels with subscriptions that are sent again when the connection is restored
+package client
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+var logger = utils.Logger
+
+// DefaultTimeout is the timeout of the REST requests of a client created without an HTTP client
+const DefaultTimeout = 30 * time.Second
+
+// Error is an error returned by the API, with the HTTP status of the response, the error
+// code of the rejected orders and the details of some errors
+type Error struct {
+	StatusCode int
+	Message    string
+	Code       string
+	Details    json.RawMessage
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Message)
+}
+
+// Client calls the REST API of a server. The requests are signed with the API key of the
+// client when it has one, or else with its wallet, so that they are authenticated for the
+// address of the wallet
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	wallet     *types.Wallet
+	apiKey     *types.APIKey
+
+	orderNonces   *NonceManager
+	lendingNonces *NonceManager
+}
+
+// Option configures a client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client the requests are sent with
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey signs the requests with an API key and its secret instead of the wallet. The
+// orders are still signed with the wallet
+func WithAPIKey(key, secret string) Option {
+	return func(c *Client) {
+		c.apiKey = &types.APIKey{Key: key, Secret: secret}
+	}
+}
+
+// New returns a client of the server at baseURL, e.g. https://api.example.com, which signs
+// the requests and the orders with w. w can be nil to only call the public endpoints
+func New(baseURL string, w *types.Wallet, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		wallet:     w,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.orderNonces = NewNonceManager(c.GetOrderNonce)
+	c.lendingNonces = NewNonceManager(c.GetLendingNonce)
+
+	return c, nil
+}
+
+// Wallet returns the wallet of the client
+func (c *Client) Wallet() *types.Wallet {
+	return c.wallet
+}
+
+// get sends a GET request to path with query, and decodes the data of the response into result
+func (c *Client) get(path string, query url.Values, result interface{}) error {
+	return c.do(http.MethodGet, path, query, nil, result)
+}
+
+// post sends a POST request to path with the JSON encoding of body, and decodes the data of the
+// response into result
+func (c *Client) post(path string, body interface{}, result interface{}) error {
+	return c.do(http.MethodPost, path, nil, body, result)
+}
+
+func (c *Client) do(method, path string, query url.Values, body interface{}, result interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+
+	var b []byte
+	if body != nil {
+		var err error
+		b, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	u := *c.baseURL
+	u.Path = c.baseURL.Path + path
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	err = c.sign(req, &types.SignedRequest{
+		Method: method,
+		Path:   u.EscapedPath(),
+		Query:  query,
+		Body:   b,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return decodeError(res.StatusCode, data)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	envelope := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+
+	err = json.Unmarshal(data, &envelope)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(envelope.Data, result)
+}
+
+// sign adds the Timestamp, Nonce and signature headers of a request, with the API key of the
+// client or its wallet. The requests of a client without either are sent unsigned
+func (c *Client) sign(req *http.Request, r *types.SignedRequest) error {
+	if c.apiKey == nil && c.wallet == nil {
+		return nil
+	}
+
+	nonce := make([]byte, 16)
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+
+	r.Timestamp = time.Now().Unix()
+	r.Nonce = hex.EncodeToString(nonce)
+
+	req.Header.Set("Timestamp", strconv.FormatInt(r.Timestamp, 10))
+	req.Header.Set("Nonce", r.Nonce)
+
+	if c.apiKey != nil {
+		req.Header.Set("API-Key", c.apiKey.Key)
+		req.Header.Set("API-Signature", c.apiKey.Sign(r))
+		return nil
+	}
+
+	signature, err := r.Sign(c.wallet)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Signature", signature)
+	return nil
+}
+
+// decodeError returns the error of a response, which is {"error": <message>} with an
+// optional "code" and "details"
+func decodeError(status int, data []byte) error {
+	res := struct {
+		Error   string          `json:"error"`
+		Code    string          `json:"code"`
+		Details json.RawMessage `json:"details"`
+	}{}
+
+	err := json.Unmarshal(data, &res)
+	if err != nil || res.Error == "" {
+		return &Error{StatusCode: status, Message: strings.TrimSpace(string(data))}
+	}
+
+	return &Error{StatusCode: status, Message: res.Error, Code: res.Code, Details: res.Details}
+}
+
+// requireWallet returns an error if the client has no wallet to sign with
+func (c *Client) requireWallet() error {
+	if c.wallet == nil {
+		return ErrNoWallet
+	}
+
+	return nil
+}
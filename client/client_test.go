@@ -0,0 +1,210 @@
+package client
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// testServer is a REST API answering the nonce of every address with nonce, and accepting the
+// orders unless reject is set
+type testServer struct {
+	t      *testing.T
+	nonce  uint64
+	reject bool
+
+	mu          sync.Mutex
+	nonceCalls  int
+	orders      []*types.Order
+	signers     []common.Address
+	lastHeaders http.Header
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.t.Error(err)
+	}
+
+	s.lastHeaders = r.Header
+
+	timestamp, _ := strconv.ParseInt(r.Header.Get("Timestamp"), 10, 64)
+	req := &types.SignedRequest{
+		Method:    r.Method,
+		Path:      r.URL.EscapedPath(),
+		Query:     r.URL.Query(),
+		Body:      body,
+		Timestamp: timestamp,
+		Nonce:     r.Header.Get("Nonce"),
+	}
+
+	signer, err := req.RecoverAddress(r.Header.Get("Signature"))
+	if err == nil {
+		s.signers = append(s.signers, signer)
+	}
+
+	switch r.URL.Path {
+	case "/api/orders/nonce":
+		s.nonceCalls++
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": s.nonce})
+
+	case "/api/orders":
+		if s.reject {
+			writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+				"error": "Invalid nonce",
+				"code":  "INVALID_NONCE",
+			})
+			return
+		}
+
+		o := &types.Order{}
+		err := json.Unmarshal(body, o)
+		if err != nil {
+			s.t.Error(err)
+		}
+
+		s.orders = append(s.orders, o)
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"data": o})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("404 page not found"))
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func newTestOrder() *types.Order {
+	return &types.Order{
+		ExchangeAddress: common.HexToAddress("0x0342d186212b04E69eA682b3bed8e232b6b3361a"),
+		BaseToken:       common.HexToAddress("0x4d7eA2cE949216D6b120f3AA10164173615A2b6C"),
+		QuoteToken:      common.HexToAddress("0x1888a8db0b7db59413ce07150b3373972bf818d3"),
+		Amount:          big.NewInt(1000),
+		PricePoint:      big.NewInt(100),
+		Side:            types.BUY,
+	}
+}
+
+func TestNewOrder(t *testing.T) {
+	s := &testServer{t: t, nonce: 5}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	w := types.NewWallet()
+	c, err := New(server.URL, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err = c.NewOrder(newTestOrder())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the nonce is fetched once, then counted locally
+	assert.Equal(t, 1, s.nonceCalls)
+	assert.Equal(t, 3, len(s.orders))
+
+	for i, o := range s.orders {
+		assert.Equal(t, int64(5+i), o.Nonce.Int64())
+		assert.Equal(t, w.Address, o.UserAddress)
+		assert.Equal(t, o.ComputeHash(), o.Hash)
+
+		ok, err := o.VerifySignature()
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	// every request is signed by the wallet
+	assert.Equal(t, 4, len(s.signers))
+	for _, signer := range s.signers {
+		assert.Equal(t, w.Address, signer)
+	}
+}
+
+func TestNewOrderRejected(t *testing.T) {
+	s := &testServer{t: t, nonce: 5, reject: true}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	c, err := New(server.URL, types.NewWallet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.NewOrder(newTestOrder())
+	if assert.IsType(t, &Error{}, err) {
+		e := err.(*Error)
+		assert.Equal(t, http.StatusBadRequest, e.StatusCode)
+		assert.Equal(t, "Invalid nonce", e.Message)
+		assert.Equal(t, "INVALID_NONCE", e.Code)
+	}
+
+	// the nonce of the rejected order is fetched again
+	s.reject = false
+	o, err := c.NewOrder(newTestOrder())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), o.Nonce.Int64())
+	assert.Equal(t, 2, s.nonceCalls)
+}
+
+func TestAPIKey(t *testing.T) {
+	s := &testServer{t: t, nonce: 5}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	c, err := New(server.URL, types.NewWallet(), WithAPIKey("key", "secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = c.GetOrderNonce(common.Address{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "key", s.lastHeaders.Get("API-Key"))
+	assert.NotEmpty(t, s.lastHeaders.Get("API-Signature"))
+	assert.NotEmpty(t, s.lastHeaders.Get("Nonce"))
+	assert.Empty(t, s.lastHeaders.Get("Signature"))
+}
+
+func TestNoWallet(t *testing.T) {
+	s := &testServer{t: t, nonce: 5}
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	c, err := New(server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := c.GetOrderNonce(common.Address{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), n)
+	assert.Empty(t, s.lastHeaders.Get("Signature"))
+
+	_, err = c.NewOrder(newTestOrder())
+	assert.Equal(t, ErrNoWallet, err)
+
+	_, err = c.GetOrder(common.Hash{})
+	if assert.IsType(t, &Error{}, err) {
+		assert.Equal(t, "404 page not found", err.(*Error).Message)
+	}
+}
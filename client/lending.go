@@ -0,0 +1,154 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// GetLendingNonce returns the nonce of the next lending order of addr
+func (c *Client) GetLendingNonce(addr common.Address) (uint64, error) {
+	var n uint64
+	err := c.get("/api/lending/nonce", url.Values{"address": {addr.Hex()}}, &n)
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// GetLendingOrders returns a page of the lending orders matching spec, most recent first. The
+// page size defaults to types.DefaultLimit when it is not positive
+func (c *Client) GetLendingOrders(spec types.LendingSpec, pageOffset, pageSize int) (*types.LendingRes, error) {
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+
+	set("address", spec.UserAddress)
+	set("lendingToken", spec.LendingToken)
+	set("collateralToken", spec.CollateralToken)
+	set("term", spec.Term)
+	set("lendingStatus", spec.Status)
+	set("lendingSide", spec.Side)
+	set("lendingType", spec.Type)
+	set("hash", spec.Hash)
+
+	if spec.DateFrom != 0 {
+		q.Set("from", strconv.FormatInt(spec.DateFrom, 10))
+	}
+
+	if spec.DateTo != 0 {
+		q.Set("to", strconv.FormatInt(spec.DateTo, 10))
+	}
+
+	q.Set("pageOffset", strconv.Itoa(pageOffset))
+	if pageSize > 0 {
+		q.Set("pageSize", strconv.Itoa(pageSize))
+	}
+
+	res := &types.LendingRes{}
+	err := c.get("/api/lending/orders", q, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// NewLendingOrder signs and places a lending order. The user address defaults to the address
+// of the wallet, and the nonce to the next lending nonce of the user
+func (c *Client) NewLendingOrder(o *types.LendingOrder) (*types.LendingOrder, error) {
+	err := c.requireWallet()
+	if err != nil {
+		return nil, err
+	}
+
+	if (o.UserAddress == common.Address{}) {
+		o.UserAddress = c.wallet.Address
+	}
+
+	if o.Status == "" {
+		o.Status = types.OrderStatusNew
+	}
+
+	if o.Type == "" {
+		o.Type = types.TypeLimit
+	}
+
+	if o.Nonce == nil {
+		o.Nonce, err = c.lendingNonces.Next(o.UserAddress)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = o.Sign(c.wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.LendingOrder{}
+	err = c.post("/api/lending", o, res)
+	if err != nil {
+		c.lendingNonces.Reset(o.UserAddress)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CancelLendingOrder signs and sends the cancel of a lending order with the next lending nonce
+// of its user, and returns the hash of the lending order
+func (c *Client) CancelLendingOrder(o *types.LendingOrder) (common.Hash, error) {
+	err := c.requireWallet()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	nonce, err := c.lendingNonces.Next(o.UserAddress)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	cancel := *o
+	cancel.Status = types.LendingStatusCancelled
+	cancel.Nonce = nonce
+
+	// the cancel is signed with its own hash, but refers to the lending order by its hash
+	err = cancel.Sign(c.wallet)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	cancel.Hash = o.Hash
+
+	var hash common.Hash
+	err = c.post("/api/lending/cancel", &cancel, &hash)
+	if err != nil {
+		c.lendingNonces.Reset(o.UserAddress)
+		return common.Hash{}, err
+	}
+
+	return hash, nil
+}
+
+// GetLendingOrderBook returns the lending orderbook of a lending token and term
+func (c *Client) GetLendingOrderBook(term uint64, lendingToken common.Address) (*types.LendingOrderBook, error) {
+	q := url.Values{
+		"term":         {strconv.FormatUint(term, 10)},
+		"lendingToken": {lendingToken.Hex()},
+	}
+
+	ob := &types.LendingOrderBook{}
+	err := c.get("/api/lending/orderbook", q, ob)
+	if err != nil {
+		return nil, err
+	}
+
+	return ob, nil
+}
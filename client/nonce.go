@@ -0,0 +1,53 @@
+package client
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceManager hands out the nonces of the orders of an address. The first nonce is fetched
+// from the server, and the following ones are counted locally, so that orders sent at once get
+// distinct nonces without a request each. The nonce is fetched again after Reset, which should
+// be called when an order is rejected, as its nonce was not consumed
+type NonceManager struct {
+	fetch func(addr common.Address) (uint64, error)
+
+	mu     sync.Mutex
+	nonces map[common.Address]uint64
+}
+
+// NewNonceManager returns a nonce manager fetching the nonces with fetch
+func NewNonceManager(fetch func(addr common.Address) (uint64, error)) *NonceManager {
+	return &NonceManager{
+		fetch:  fetch,
+		nonces: make(map[common.Address]uint64),
+	}
+}
+
+// Next returns the next nonce of addr and reserves it
+func (m *NonceManager) Next(addr common.Address) (*big.Int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n, ok := m.nonces[addr]
+	if !ok {
+		var err error
+		n, err = m.fetch(addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m.nonces[addr] = n + 1
+	return new(big.Int).SetUint64(n), nil
+}
+
+// Reset forgets the nonce of addr, which is fetched again by the next call to Next
+func (m *NonceManager) Reset(addr common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.nonces, addr)
+}
@@ -0,0 +1,270 @@
+package client
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// ErrNoWallet is returned when an order is placed or cancelled by a client without a wallet
+var ErrNoWallet = errors.New("Client has no wallet to sign with")
+
+// GetOrderNonce returns the nonce of the next order of addr
+func (c *Client) GetOrderNonce(addr common.Address) (uint64, error) {
+	var n uint64
+	err := c.get("/api/orders/nonce", url.Values{"address": {addr.Hex()}}, &n)
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// GetOrders returns a page of the orders matching spec, most recent first. The page size
+// defaults to types.DefaultLimit when it is not positive
+func (c *Client) GetOrders(spec types.OrderSpec, pageOffset, pageSize int) (*types.OrderRes, error) {
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+
+	set("address", spec.UserAddress)
+	set("baseToken", spec.BaseToken)
+	set("quoteToken", spec.QuoteToken)
+	set("orderStatus", spec.Status)
+	set("orderSide", spec.Side)
+	set("orderType", spec.OrderType)
+	set("hash", spec.OrderHash)
+	set("clientOrderId", spec.ClientOrderID)
+
+	if spec.DateFrom != 0 {
+		q.Set("from", strconv.FormatInt(spec.DateFrom, 10))
+	}
+
+	if spec.DateTo != 0 {
+		q.Set("to", strconv.FormatInt(spec.DateTo, 10))
+	}
+
+	q.Set("pageOffset", strconv.Itoa(pageOffset))
+	if pageSize > 0 {
+		q.Set("pageSize", strconv.Itoa(pageSize))
+	}
+
+	res := &types.OrderRes{}
+	err := c.get("/api/orders", q, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetOrder returns the order of a hash
+func (c *Client) GetOrder(hash common.Hash) (*types.Order, error) {
+	o := &types.Order{}
+	err := c.get("/api/orders/"+hash.Hex(), nil, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// signOrder fills the user address, status and nonce of an order that does not have them
+// and signs it with the wallet
+func (c *Client) signOrder(o *types.Order) error {
+	err := c.requireWallet()
+	if err != nil {
+		return err
+	}
+
+	if (o.UserAddress == common.Address{}) {
+		o.UserAddress = c.wallet.Address
+	}
+
+	if o.Status == "" {
+		o.Status = types.OrderStatusNew
+	}
+
+	if o.Type == "" {
+		o.Type = types.TypeLimitOrder
+	}
+
+	if o.Nonce == nil {
+		o.Nonce, err = c.orderNonces.Next(o.UserAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	return o.Sign(c.wallet)
+}
+
+// signOrderCancel signs the cancel of an order with the next nonce of the wallet
+func (c *Client) signOrderCancel(o *types.Order) (*types.OrderCancel, error) {
+	err := c.requireWallet()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := c.orderNonces.Next(o.UserAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	oc := &types.OrderCancel{
+		OrderHash:       o.Hash,
+		Nonce:           nonce,
+		OrderID:         o.OrderID,
+		Status:          types.OrderStatusCancelled,
+		UserAddress:     o.UserAddress,
+		ExchangeAddress: o.ExchangeAddress,
+	}
+
+	err = oc.Sign(c.wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	return oc, nil
+}
+
+// NewOrder signs and places an order. The user address defaults to the address of the wallet,
+// and the nonce to the next nonce of the user. The nonces are fetched again if the order is
+// rejected, as its nonce was not consumed
+func (c *Client) NewOrder(o *types.Order) (*types.Order, error) {
+	err := c.signOrder(o)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.Order{}
+	err = c.post("/api/orders", o, res)
+	if err != nil {
+		c.orderNonces.Reset(o.UserAddress)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// NewOrders signs and places a batch of orders, and returns the result of each of them
+func (c *Client) NewOrders(orders []*types.Order) ([]*types.OrderBatchResult, error) {
+	for _, o := range orders {
+		err := c.signOrder(o)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := []*types.OrderBatchResult{}
+	err := c.post("/api/orders/batch", orders, &res)
+	if err != nil {
+		c.resetOrderNonces(orders)
+		return nil, err
+	}
+
+	for _, r := range res {
+		if !r.Accepted {
+			c.resetOrderNonces(orders)
+			break
+		}
+	}
+
+	return res, nil
+}
+
+func (c *Client) resetOrderNonces(orders []*types.Order) {
+	for _, o := range orders {
+		c.orderNonces.Reset(o.UserAddress)
+	}
+}
+
+// CancelOrder signs and sends the cancel of an order, and returns the hash of the cancel
+func (c *Client) CancelOrder(o *types.Order) (common.Hash, error) {
+	oc, err := c.signOrderCancel(o)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	var hash common.Hash
+	err = c.post("/api/orders/cancel", oc, &hash)
+	if err != nil {
+		c.orderNonces.Reset(o.UserAddress)
+		return common.Hash{}, err
+	}
+
+	return hash, nil
+}
+
+// ReplaceOrder cancels an order and places another one in its place. When the replacing
+// order fails after the order was cancelled, the error has the code REPLACE_ORDER_FAILED and
+// is returned with the result of the cancellation, as the order is not restored
+func (c *Client) ReplaceOrder(o *types.Order, replacement *types.Order) (*types.OrderReplaceResult, error) {
+	oc, err := c.signOrderCancel(o)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.signOrder(replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.OrderReplaceResult{}
+	err = c.post("/api/orders/replace", &types.OrderReplace{Cancel: oc, Order: replacement}, res)
+	if err != nil {
+		c.orderNonces.Reset(o.UserAddress)
+
+		if apiErr, ok := err.(*Error); ok && apiErr.Code == "REPLACE_ORDER_FAILED" && len(apiErr.Details) > 0 {
+			if json.Unmarshal(apiErr.Details, res) == nil {
+				return res, err
+			}
+		}
+
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetOrderBook returns the orderbook of a pair, with the depth and grouping of opts
+func (c *Client) GetOrderBook(baseToken, quoteToken common.Address, opts types.OrderBookOptions) (*types.OrderBook, error) {
+	q := url.Values{
+		"baseToken":  {baseToken.Hex()},
+		"quoteToken": {quoteToken.Hex()},
+	}
+
+	if opts.Depth > 0 {
+		q.Set("depth", strconv.Itoa(opts.Depth))
+	}
+
+	if opts.Grouping != nil {
+		q.Set("grouping", strconv.Itoa(*opts.Grouping))
+	}
+
+	ob := &types.OrderBook{}
+	err := c.get("/api/orderbook", q, ob)
+	if err != nil {
+		return nil, err
+	}
+
+	return ob, nil
+}
+
+// GetPairs returns the pairs of the exchange
+func (c *Client) GetPairs() ([]types.Pair, error) {
+	pairs := []types.Pair{}
+	err := c.get("/api/pairs", nil, &pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,478 @@
+package client
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+const (
+	// minReconnectDelay is the delay before the first reconnection of a stream, doubled after
+	// each failed reconnection up to maxReconnectDelay
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+
+	// loginTimeout is the time a stream waits for each answer of the server when it logs in
+	loginTimeout = 10 * time.Second
+
+	// the channels of the websocket API, as named by the ws package of the server
+	authChannel             = "auth"
+	orderChannel            = "orders"
+	orderBookChannel        = "orderbook"
+	tradeChannel            = "trades"
+	ohlcvChannel            = "ohlcv"
+	lendingOrderChannel     = "lending_orders"
+	lendingOrderBookChannel = "lending_orderbook"
+)
+
+// Handler handles the events of a channel
+type Handler func(ev *types.WebsocketEvent)
+
+// Stream is a websocket connection to the server. The subscriptions of a stream are sent again
+// when the connection is lost and restored, after logging in again if the stream has a wallet.
+// The handlers are called one at a time, in the order the events are received
+type Stream struct {
+	url    string
+	wallet *types.Wallet
+	dialer *websocket.Dialer
+
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	subs     []*types.WebsocketMessage
+	handlers map[string][]Handler
+	closed   bool
+}
+
+// NewStream returns a stream of the websocket API at url, e.g. wss://api.example.com/socket,
+// which logs in with w when w is not nil. The stream connects when it is started
+func NewStream(url string, w *types.Wallet) *Stream {
+	return &Stream{
+		url:      url,
+		wallet:   w,
+		dialer:   websocket.DefaultDialer,
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// NewStream returns a stream of the websocket API of the server of the client, which logs in
+// with the wallet of the client
+func (c *Client) NewStream() *Stream {
+	u := *c.baseURL
+	u.Scheme = strings.Replace(u.Scheme, "http", "ws", 1)
+	u.Path = c.baseURL.Path + "/socket"
+	u.RawQuery = ""
+
+	return NewStream(u.String(), c.wallet)
+}
+
+// Start connects the stream, and keeps reconnecting it in the background when the connection
+// is lost until the stream is closed
+func (s *Stream) Start() error {
+	err := s.connect()
+	if err != nil {
+		return err
+	}
+
+	go s.run()
+	return nil
+}
+
+// Close closes the connection of the stream, which is not restored
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.conn == nil {
+		return nil
+	}
+
+	return s.conn.Close()
+}
+
+// connect dials the server, logs in, and sends the subscriptions of the stream
+func (s *Stream) connect() error {
+	conn, _, err := s.dialer.Dial(s.url, nil)
+	if err != nil {
+		return err
+	}
+
+	if s.wallet != nil {
+		err = login(conn, s.wallet)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		conn.Close()
+		return errors.New("Stream is closed")
+	}
+
+	for _, m := range s.subs {
+		err = conn.WriteJSON(m)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	s.conn = conn
+	return nil
+}
+
+// login asks a challenge to the server, and logs in by signing it with w
+func login(conn *websocket.Conn, w *types.Wallet) error {
+	err := conn.WriteJSON(&types.WebsocketMessage{
+		Channel: authChannel,
+		Event:   types.WebsocketEvent{Type: types.CHALLENGE},
+	})
+	if err != nil {
+		return err
+	}
+
+	challenge := &types.LoginChallenge{}
+	err = readAuthEvent(conn, types.CHALLENGE, challenge)
+	if err != nil {
+		return err
+	}
+
+	l := &types.Login{Nonce: challenge.Nonce}
+	err = l.Sign(w)
+	if err != nil {
+		return err
+	}
+
+	err = conn.WriteJSON(&types.WebsocketMessage{
+		Channel: authChannel,
+		Event:   types.WebsocketEvent{Type: types.LOGIN, Payload: l},
+	})
+	if err != nil {
+		return err
+	}
+
+	return readAuthEvent(conn, types.SUCCESS_EVENT, nil)
+}
+
+// readAuthEvent reads the next event of the auth channel and decodes its payload into v. An
+// ERROR event is returned as an error
+func readAuthEvent(conn *websocket.Conn, expected types.SubscriptionEvent, v interface{}) error {
+	conn.SetReadDeadline(time.Now().Add(loginTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	for {
+		m := &types.WebsocketMessage{}
+		err := conn.ReadJSON(m)
+		if err != nil {
+			return err
+		}
+
+		if m.Channel != authChannel {
+			continue
+		}
+
+		if m.Event.Type != expected {
+			b, _ := json.Marshal(m.Event.Payload)
+			return errors.New("Login failed: " + string(b))
+		}
+
+		if v == nil {
+			return nil
+		}
+
+		return DecodePayload(&m.Event, v)
+	}
+}
+
+// run reads the events of the stream and reconnects it when the connection is lost
+func (s *Stream) run() {
+	for {
+		s.mu.Lock()
+		conn := s.conn
+		s.mu.Unlock()
+
+		s.read(conn)
+
+		if !s.reconnect() {
+			return
+		}
+	}
+}
+
+// read dispatches the events of a connection to the handlers of their channel until the
+// connection fails
+func (s *Stream) read(conn *websocket.Conn) {
+	for {
+		m := &types.WebsocketMessage{}
+		err := conn.ReadJSON(m)
+		if err != nil {
+			if !s.isClosed() {
+				logger.Warningf("Websocket connection to %s lost: %v", s.url, err)
+			}
+
+			conn.Close()
+			return
+		}
+
+		s.mu.Lock()
+		handlers := s.handlers[m.Channel]
+		s.mu.Unlock()
+
+		for _, h := range handlers {
+			h(&m.Event)
+		}
+	}
+}
+
+// reconnect connects the stream again, waiting 1s after the first failure and doubling the
+// delay after each one up to 1min. It returns false once the stream is closed
+func (s *Stream) reconnect() bool {
+	delay := minReconnectDelay
+
+	for {
+		if s.isClosed() {
+			return false
+		}
+
+		err := s.connect()
+		if err == nil {
+			return true
+		}
+
+		logger.Warningf("Failed to reconnect to %s, retrying in %v: %v", s.url, delay, err)
+		time.Sleep(delay)
+
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+func (s *Stream) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// On registers a handler of the events of a channel, without subscribing to it
+func (s *Stream) On(channel string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[channel] = append(s.handlers[channel], h)
+}
+
+// Send sends an event on a channel. Unlike the subscriptions, the event is not sent again when
+// the connection is restored
+func (s *Stream) Send(channel string, t types.SubscriptionEvent, payload interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return errors.New("Stream is not connected")
+	}
+
+	return s.conn.WriteJSON(&types.WebsocketMessage{
+		Channel: channel,
+		Event:   types.WebsocketEvent{Type: t, Payload: payload},
+	})
+}
+
+// Subscribe subscribes to a channel with payload and registers h as a handler of its events.
+// The subscription is sent again each time the connection is restored
+func (s *Stream) Subscribe(channel string, payload interface{}, h Handler) error {
+	m := &types.WebsocketMessage{
+		Channel: channel,
+		Event:   types.WebsocketEvent{Type: types.SUBSCRIBE, Payload: payload},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subs = append(s.subs, m)
+	if h != nil {
+		s.handlers[channel] = append(s.handlers[channel], h)
+	}
+
+	if s.conn == nil {
+		return nil
+	}
+
+	return s.conn.WriteJSON(m)
+}
+
+// SubscribeOrders subscribes to the events of the orders of addr, which requires the stream
+// to be logged in as addr
+func (s *Stream) SubscribeOrders(addr common.Address, h Handler) error {
+	return s.Subscribe(orderChannel, addr.Hex(), h)
+}
+
+// SubscribeLendingOrders subscribes to the events of the lending orders of addr, which
+// requires the stream to be logged in as addr
+func (s *Stream) SubscribeLendingOrders(addr common.Address, h Handler) error {
+	return s.Subscribe(lendingOrderChannel, addr.Hex(), h)
+}
+
+// SubscribeTrades subscribes to the trades of a pair. The INIT and UPDATE events hold a list of
+// types.Trade
+func (s *Stream) SubscribeTrades(baseToken, quoteToken common.Address, h Handler) error {
+	return s.Subscribe(tradeChannel, &types.SubscriptionPayload{
+		BaseToken:  baseToken,
+		QuoteToken: quoteToken,
+	}, h)
+}
+
+// SubscribeOHLCV subscribes to the ticks of a pair for a duration and units, e.g. 1 and "hour".
+// The INIT and UPDATE events hold a list of types.Tick
+func (s *Stream) SubscribeOHLCV(baseToken, quoteToken common.Address, duration int64, units string, h Handler) error {
+	return s.Subscribe(ohlcvChannel, &types.SubscriptionPayload{
+		BaseToken:  baseToken,
+		QuoteToken: quoteToken,
+		Duration:   duration,
+		Units:      units,
+	}, h)
+}
+
+// OrderBookHandler handles the snapshots and the updates of an orderbook
+type OrderBookHandler func(t types.SubscriptionEvent, ob *types.OrderBook)
+
+// SubscribeOrderBook subscribes to the orderbook of a pair, with the depth and grouping of
+// opts. The updates are checked against the sequence of the last snapshot or update: the ones
+// already included in the snapshot are dropped, and a RESYNC is sent when one is missed, so h
+// gets a new snapshot instead of the updates which follow it
+func (s *Stream) SubscribeOrderBook(baseToken, quoteToken common.Address, opts types.OrderBookOptions, h OrderBookHandler) error {
+	seq := &sequenceTracker{}
+
+	return s.Subscribe(orderBookChannel, &types.SubscriptionPayload{
+		BaseToken:  baseToken,
+		QuoteToken: quoteToken,
+		Depth:      opts.Depth,
+		Grouping:   opts.Grouping,
+	}, func(ev *types.WebsocketEvent) {
+		if ev.Type != types.INIT && ev.Type != types.UPDATE {
+			return
+		}
+
+		ob := &types.OrderBook{}
+		err := DecodePayload(ev, ob)
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+
+		if !seq.accept(ev.Type, ob.Sequence) {
+			return
+		}
+
+		if seq.missed {
+			err = s.Send(orderBookChannel, types.RESYNC, &types.SubscriptionPayload{
+				BaseToken:  baseToken,
+				QuoteToken: quoteToken,
+			})
+			if err != nil {
+				logger.Error(err)
+			}
+
+			return
+		}
+
+		h(ev.Type, ob)
+	})
+}
+
+// LendingOrderBookHandler handles the snapshots and the updates of a lending orderbook
+type LendingOrderBookHandler func(t types.SubscriptionEvent, ob *types.LendingOrderBook)
+
+// SubscribeLendingOrderBook subscribes to the lending orderbook of a lending token and term,
+// and checks the sequences of its updates as SubscribeOrderBook does
+func (s *Stream) SubscribeLendingOrderBook(term uint64, lendingToken common.Address, h LendingOrderBookHandler) error {
+	seq := &sequenceTracker{}
+
+	return s.Subscribe(lendingOrderBookChannel, &types.SubscriptionPayload{
+		Term:         term,
+		LendingToken: lendingToken,
+	}, func(ev *types.WebsocketEvent) {
+		if ev.Type != types.INIT && ev.Type != types.UPDATE {
+			return
+		}
+
+		ob := &types.LendingOrderBook{}
+		err := DecodePayload(ev, ob)
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+
+		if !seq.accept(ev.Type, ob.Sequence) {
+			return
+		}
+
+		if seq.missed {
+			err = s.Send(lendingOrderBookChannel, types.RESYNC, &types.SubscriptionPayload{
+				Term:         term,
+				LendingToken: lendingToken,
+			})
+			if err != nil {
+				logger.Error(err)
+			}
+
+			return
+		}
+
+		h(ev.Type, ob)
+	})
+}
+
+// sequenceTracker follows the sequences of the updates of an orderbook. Once an update is
+// missed, the updates are ignored until the next snapshot
+type sequenceTracker struct {
+	initialized bool
+	missed      bool
+	last        uint64
+}
+
+// accept returns false if an event should be ignored: an update received before the first
+// snapshot, already included in the snapshot, or following a missed update. missed is set when
+// an update is missed, and cleared by the next snapshot
+func (t *sequenceTracker) accept(ev types.SubscriptionEvent, seq uint64) bool {
+	if ev == types.INIT {
+		t.initialized = true
+		t.missed = false
+		t.last = seq
+		return true
+	}
+
+	if !t.initialized || t.missed || seq <= t.last {
+		return false
+	}
+
+	if seq != t.last+1 {
+		t.missed = true
+		return true
+	}
+
+	t.last = seq
+	return true
+}
+
+// DecodePayload decodes the payload of an event into v
+func DecodePayload(ev *types.WebsocketEvent, v interface{}) error {
+	b, err := json.Marshal(ev.Payload)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// newTestSocket returns a websocket server which logs in the connections signing its challenge,
+// and sends the connections to conns once logged in
+func newTestSocket(t *testing.T, conns chan<- *websocket.Conn) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		m := &types.WebsocketMessage{}
+		err = conn.ReadJSON(m)
+		if err != nil || m.Event.Type != types.CHALLENGE {
+			t.Errorf("Expected a challenge, got %v (%v)", m, err)
+			return
+		}
+
+		conn.WriteJSON(&types.WebsocketMessage{
+			Channel: "auth",
+			Event:   types.WebsocketEvent{Type: types.CHALLENGE, Payload: &types.LoginChallenge{Nonce: "challenge"}},
+		})
+
+		err = conn.ReadJSON(m)
+		if err != nil || m.Event.Type != types.LOGIN {
+			t.Errorf("Expected a login, got %v (%v)", m, err)
+			return
+		}
+
+		l := &types.Login{}
+		err = DecodePayload(&m.Event, l)
+		if err != nil {
+			t.Error(err)
+		}
+
+		assert.Equal(t, "challenge", l.Nonce)
+		assert.NoError(t, l.VerifySignature())
+
+		conn.WriteJSON(&types.WebsocketMessage{
+			Channel: "auth",
+			Event:   types.WebsocketEvent{Type: types.SUCCESS_EVENT},
+		})
+
+		conns <- conn
+	}))
+}
+
+func TestStreamResubscribes(t *testing.T) {
+	conns := make(chan *websocket.Conn, 2)
+	server := newTestSocket(t, conns)
+	defer server.Close()
+
+	c, err := New(server.URL, types.NewWallet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := c.NewStream()
+	err = s.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Close()
+
+	events := make(chan *types.WebsocketEvent, 1)
+	err = s.SubscribeOrders(c.Wallet().Address, func(ev *types.WebsocketEvent) {
+		events <- ev
+	})
+	assert.NoError(t, err)
+
+	conn := <-conns
+	m := &types.WebsocketMessage{}
+	assert.NoError(t, conn.ReadJSON(m))
+	assert.Equal(t, "orders", m.Channel)
+	assert.Equal(t, types.SUBSCRIBE, m.Event.Type)
+	assert.Equal(t, c.Wallet().Address.Hex(), m.Event.Payload)
+
+	// the stream logs in and subscribes again once the connection is restored
+	conn.Close()
+
+	select {
+	case conn = <-conns:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stream did not reconnect")
+	}
+
+	m = &types.WebsocketMessage{}
+	assert.NoError(t, conn.ReadJSON(m))
+	assert.Equal(t, "orders", m.Channel)
+	assert.Equal(t, types.SUBSCRIBE, m.Event.Type)
+
+	conn.WriteJSON(&types.WebsocketMessage{
+		Channel: "orders",
+		Event:   types.WebsocketEvent{Type: types.ORDER_ADDED},
+	})
+
+	select {
+	case ev := <-events:
+		assert.Equal(t, types.SubscriptionEvent(types.ORDER_ADDED), ev.Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Event was not handled")
+	}
+}
+
+func TestStreamOrderBookResync(t *testing.T) {
+	conns := make(chan *websocket.Conn, 1)
+	server := newTestSocket(t, conns)
+	defer server.Close()
+
+	c, err := New(server.URL, types.NewWallet())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := c.NewStream()
+	err = s.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Close()
+
+	baseToken := common.HexToAddress("0x4d7eA2cE949216D6b120f3AA10164173615A2b6C")
+	quoteToken := common.HexToAddress("0x1888a8db0b7db59413ce07150b3373972bf818d3")
+
+	sequences := make(chan uint64, 10)
+	err = s.SubscribeOrderBook(baseToken, quoteToken, types.OrderBookOptions{}, func(ev types.SubscriptionEvent, ob *types.OrderBook) {
+		sequences <- ob.Sequence
+	})
+	assert.NoError(t, err)
+
+	conn := <-conns
+	m := &types.WebsocketMessage{}
+	assert.NoError(t, conn.ReadJSON(m))
+	assert.Equal(t, types.SUBSCRIBE, m.Event.Type)
+
+	send := func(ev types.SubscriptionEvent, seq uint64) {
+		conn.WriteJSON(&types.WebsocketMessage{
+			Channel: "orderbook",
+			Event:   types.WebsocketEvent{Type: ev, Payload: &types.OrderBook{Sequence: seq}},
+		})
+	}
+
+	send(types.INIT, 10)
+	send(types.UPDATE, 9)
+	send(types.UPDATE, 11)
+	send(types.UPDATE, 13)
+	send(types.UPDATE, 14)
+
+	// the update already in the snapshot is dropped, and the missed one triggers a resync
+	assert.NoError(t, conn.ReadJSON(m))
+	assert.Equal(t, types.RESYNC, m.Event.Type)
+
+	send(types.INIT, 14)
+	send(types.UPDATE, 15)
+
+	for _, expected := range []uint64{10, 11, 14, 15} {
+		select {
+		case seq := <-sequences:
+			assert.Equal(t, expected, seq)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Orderbook was not handled")
+		}
+	}
+}
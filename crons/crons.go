@@ -2,7 +2,7 @@ package crons
 
 import (
 	"github.com/robfig/cron"
-	"github.com/tomochain/tomox-sdk/engine"
+	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/services"
 )
 
@@ -12,7 +12,8 @@ type CronService struct {
 	PriceBoardService        *services.PriceBoardService
 	PairService              *services.PairService
 	RelayService             *services.RelayerService
-	Engine                   *engine.Engine
+	OrderService             *services.OrderService
+	Engine                   interfaces.Engine
 	lendingPriceBoardService *services.LendingPriceBoardService
 	lendingPairService       *services.LendingPairService
 	lendingOhlcvService      *services.LendingOhlcvService
@@ -24,7 +25,8 @@ func NewCronService(
 	priceBoardService *services.PriceBoardService,
 	pairService *services.PairService,
 	relayService *services.RelayerService,
-	engine *engine.Engine,
+	orderService *services.OrderService,
+	engine interfaces.Engine,
 	lendingPriceBoardService *services.LendingPriceBoardService,
 	lendingPairService *services.LendingPairService,
 	lendingOhlcvService *services.LendingOhlcvService,
@@ -34,6 +36,7 @@ func NewCronService(
 		PriceBoardService:        priceBoardService,
 		PairService:              pairService,
 		RelayService:             relayService,
+		OrderService:             orderService,
 		Engine:                   engine,
 		lendingPriceBoardService: lendingPriceBoardService,
 		lendingPairService:       lendingPairService,
@@ -52,5 +55,6 @@ func (s *CronService) InitCrons() {
 	s.startMarketsCron(c)    // Cron to fetch markets data
 	s.startLendingPriceBoardCron(c)
 	s.startLendingMarketsCron(c)
+	s.startOrderExpiryCron(c) // Cron to cancel the orders whose time in force has expired
 	c.Start()
 }
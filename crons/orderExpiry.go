@@ -0,0 +1,16 @@
+package crons
+
+import (
+	"github.com/robfig/cron"
+)
+
+// startOrderExpiryCron cancels the orders whose time in force has expired
+func (s *CronService) startOrderExpiryCron(c *cron.Cron) {
+	c.AddFunc("*/3 * * * * *", s.cancelExpiredOrders())
+}
+
+func (s *CronService) cancelExpiredOrders() func() {
+	return func() {
+		s.OrderService.CancelExpiredOrders()
+	}
+}
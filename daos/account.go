@@ -5,12 +5,12 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // AccountDao contains:
@@ -19,32 +19,33 @@ import (
 type AccountDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
 // NewAccountDao returns a new instance of AddressDao
-func NewAccountDao() *AccountDao {
+func NewAccountDao(db Store) *AccountDao {
 	dbName := app.Config.DBName
 	collection := "accounts"
-	index := mgo.Index{
+	index := Index{
 		Key:    []string{"address"},
 		Unique: true,
 	}
 
-	err := db.Session.DB(dbName).C(collection).EnsureIndex(index)
+	err := db.EnsureIndex(dbName, collection, index)
 	if err != nil {
 		panic(err)
 	}
 
-	return &AccountDao{collection, dbName}
+	return &AccountDao{collection, dbName, db}
 }
 
 // Create function performs the DB insertion task for Balance collection
 func (dao *AccountDao) Create(a *types.Account) error {
-	a.ID = bson.NewObjectId()
+	a.ID = primitive.NewObjectID()
 	a.CreatedAt = time.Now()
 	a.UpdatedAt = time.Now()
 
-	err := db.Create(dao.dbName, dao.collectionName, a)
+	err := dao.db.Create(dao.dbName, dao.collectionName, a)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -58,14 +59,14 @@ func (dao *AccountDao) FindOrCreate(addr common.Address) (*types.Account, error)
 	query := bson.M{"address": addr.Hex()}
 	updated := &types.Account{}
 
-	change := mgo.Change{
-		Update:    types.AccountBSONUpdate{Account: a},
+	change := Change{
+		Update:    &types.AccountBSONUpdate{Account: a},
 		Upsert:    true,
 		Remove:    false,
 		ReturnNew: true,
 	}
 
-	err := db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
+	err := dao.db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -75,15 +76,15 @@ func (dao *AccountDao) FindOrCreate(addr common.Address) (*types.Account, error)
 }
 
 func (dao *AccountDao) GetAll() (res []types.Account, err error) {
-	err = db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
+	err = dao.db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
 	return
 }
 
-func (dao *AccountDao) GetByID(id bson.ObjectId) (*types.Account, error) {
+func (dao *AccountDao) GetByID(id primitive.ObjectID) (*types.Account, error) {
 	res := []types.Account{}
 	q := bson.M{"_id": id}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -95,7 +96,7 @@ func (dao *AccountDao) GetByID(id bson.ObjectId) (*types.Account, error) {
 func (dao *AccountDao) GetByAddress(owner common.Address) (*types.Account, error) {
 	res := []types.Account{}
 	q := bson.M{"address": owner.Hex()}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -111,7 +112,7 @@ func (dao *AccountDao) GetByAddress(owner common.Address) (*types.Account, error
 func (dao *AccountDao) GetTokenBalances(owner common.Address) (map[common.Address]*types.TokenBalance, error) {
 	q := bson.M{"address": owner.Hex()}
 	res := []types.Account{}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -162,7 +163,7 @@ func (dao *AccountDao) GetTokenBalance(owner common.Address, token common.Addres
 	}
 
 	var res []*types.Account
-	err := db.Aggregate(dao.dbName, dao.collectionName, q, &res)
+	err := dao.db.Aggregate(dao.dbName, dao.collectionName, q, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +192,7 @@ func (dao *AccountDao) UpdateTokenBalance(owner, token common.Address, tokenBala
 		},
 	}
 
-	err := db.Update(dao.dbName, dao.collectionName, q, updateQuery)
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, updateQuery)
 	return err
 }
 
@@ -203,7 +204,7 @@ func (dao *AccountDao) UpdateBalance(owner common.Address, token common.Address,
 		"$set": bson.M{"tokenBalances." + token.Hex() + ".balance": balance.String()},
 	}
 
-	err := db.Update(dao.dbName, dao.collectionName, q, updateQuery)
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, updateQuery)
 	return err
 }
 
@@ -226,7 +227,7 @@ func (dao *AccountDao) Transfer(token common.Address, fromAddress common.Address
 		"$set": bson.M{"tokenBalances." + token.Hex() + ".balance": (math.Sub(currentTokenBalanceFromAddress[token].Balance, amount)).String()},
 	}
 
-	err = db.Update(dao.dbName, dao.collectionName, qFrom, updateQueryFrom)
+	err = dao.db.Update(dao.dbName, dao.collectionName, qFrom, updateQueryFrom)
 
 	currentTokenBalanceToAddress, err := dao.GetTokenBalances(toAddress)
 
@@ -241,20 +242,20 @@ func (dao *AccountDao) Transfer(token common.Address, fromAddress common.Address
 		"$set": bson.M{"tokenBalances." + token.Hex() + ".balance": (math.Add(currentTokenBalanceToAddress[token].Balance, amount)).String()},
 	}
 
-	err = db.Update(dao.dbName, dao.collectionName, qTo, updateQueryTo)
+	err = dao.db.Update(dao.dbName, dao.collectionName, qTo, updateQueryTo)
 
 	return err
 }
 
 // Drop drops all the order documents in the current database
 func (dao *AccountDao) Drop() {
-	db.DropCollection(dao.dbName, dao.collectionName)
+	dao.db.DropCollection(dao.dbName, dao.collectionName)
 }
 
 func (dao *AccountDao) GetFavoriteTokens(owner common.Address) (map[common.Address]bool, error) {
 	res := []types.Account{}
 	q := bson.M{"address": owner.Hex()}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -277,7 +278,7 @@ func (dao *AccountDao) AddFavoriteToken(owner, token common.Address) error {
 		"$set": bson.M{"favoriteTokens." + token.Hex(): true},
 	}
 
-	err := db.Update(dao.dbName, dao.collectionName, q, updateQuery)
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, updateQuery)
 
 	return err
 }
@@ -291,7 +292,7 @@ func (dao *AccountDao) DeleteFavoriteToken(owner, token common.Address) error {
 		"$set": bson.M{"favoriteTokens." + token.Hex(): false},
 	}
 
-	err := db.Update(dao.dbName, dao.collectionName, q, updateQuery)
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, updateQuery)
 
 	return err
 }
@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package daos
 
 import (
@@ -6,13 +9,13 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/testutils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// var db *Database
+var db *Database
 
 // func TestMain(m *testing.M) {
 // 	db := &Database{}
@@ -26,12 +29,16 @@ func init() {
 	temp, _ := ioutil.TempDir("", "test")
 	server.SetPath(temp)
 
-	session := server.Session()
-	db = &Database{Session: session}
+	d, err := Connect(server.URI())
+	if err != nil {
+		panic(err)
+	}
+
+	db = d
 }
 
 func TestAccountDao(t *testing.T) {
-	dao := NewAccountDao()
+	dao := NewAccountDao(db)
 	dao.Drop()
 
 	address := common.HexToAddress("0xe8e84ee367bc63ddb38d3d01bccef106c194dc47")
@@ -55,7 +62,7 @@ func TestAccountDao(t *testing.T) {
 	}
 
 	account := &types.Account{
-		ID:      bson.NewObjectId(),
+		ID:      primitive.NewObjectID(),
 		Address: address,
 		TokenBalances: map[common.Address]*types.TokenBalance{
 			tokenAddress1: tokenBalance1,
@@ -78,7 +85,7 @@ func TestAccountDao(t *testing.T) {
 }
 
 func TestAccountGetAllTokenBalances(t *testing.T) {
-	dao := NewAccountDao()
+	dao := NewAccountDao(db)
 	dao.Drop()
 
 	address := common.HexToAddress("0xe8e84ee367bc63ddb38d3d01bccef106c194dc47")
@@ -102,7 +109,7 @@ func TestAccountGetAllTokenBalances(t *testing.T) {
 	}
 
 	account := &types.Account{
-		ID:      bson.NewObjectId(),
+		ID:      primitive.NewObjectID(),
 		Address: address,
 		TokenBalances: map[common.Address]*types.TokenBalance{
 			tokenAddress1: tokenBalance1,
@@ -126,7 +133,7 @@ func TestAccountGetAllTokenBalances(t *testing.T) {
 }
 
 func TestGetTokenBalance(t *testing.T) {
-	dao := NewAccountDao()
+	dao := NewAccountDao(db)
 	dao.Drop()
 
 	address := common.HexToAddress("0xe8e84ee367bc63ddb38d3d01bccef106c194dc47")
@@ -150,7 +157,7 @@ func TestGetTokenBalance(t *testing.T) {
 	}
 
 	account := &types.Account{
-		ID:      bson.NewObjectId(),
+		ID:      primitive.NewObjectID(),
 		Address: address,
 		TokenBalances: map[common.Address]*types.TokenBalance{
 			tokenAddress1: tokenBalance1,
@@ -173,7 +180,7 @@ func TestGetTokenBalance(t *testing.T) {
 }
 
 func TestUpdateAccountBalance(t *testing.T) {
-	dao := NewAccountDao()
+	dao := NewAccountDao(db)
 	dao.Drop()
 
 	address := common.HexToAddress("0xe8e84ee367bc63ddb38d3d01bccef106c194dc47")
@@ -197,7 +204,7 @@ func TestUpdateAccountBalance(t *testing.T) {
 	}
 
 	account := &types.Account{
-		ID:      bson.NewObjectId(),
+		ID:      primitive.NewObjectID(),
 		Address: address,
 		TokenBalances: map[common.Address]*types.TokenBalance{
 			tokenAddress1: tokenBalance1,
@@ -0,0 +1,229 @@
+package daos
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrNoAPIKeyPassphrase is returned when an API key is stored without a passphrase
+var ErrNoAPIKeyPassphrase = errors.New("No passphrase to encrypt the API key secrets with")
+
+// APIKeyDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+// passphrase: passphrase the secrets are encrypted with
+type APIKeyDao struct {
+	collectionName string
+	dbName         string
+	db             Store
+	passphrase     string
+
+	// secrets caches the decrypted secrets by key, as each decryption takes a scrypt derivation
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+type APIKeyDaoOption = func(*APIKeyDao) error
+
+// APIKeyDaoPassphraseOption sets the passphrase the secrets are encrypted with, which is the
+// passphrase of the wallet keys. The keys are read without their secret, and can not be
+// created, when it is not set
+func APIKeyDaoPassphraseOption(passphrase string) func(dao *APIKeyDao) error {
+	return func(dao *APIKeyDao) error {
+		dao.passphrase = passphrase
+		return nil
+	}
+}
+
+// NewAPIKeyDao returns a new instance of APIKeyDao
+func NewAPIKeyDao(db Store, opts ...APIKeyDaoOption) *APIKeyDao {
+	dao := &APIKeyDao{db: db}
+	dao.collectionName = "api_keys"
+	dao.dbName = app.Config.DBName
+	dao.secrets = make(map[string]string)
+
+	for _, op := range opts {
+		err := op(dao)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	index := Index{
+		Key:    []string{"key"},
+		Unique: true,
+	}
+
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
+	if err != nil {
+		panic(err)
+	}
+
+	i1 := Index{
+		Key: []string{"userAddress"},
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i1)
+	if err != nil {
+		panic(err)
+	}
+
+	return dao
+}
+
+// Create inserts an API key, with its secret encrypted with the passphrase of the DAO
+func (dao *APIKeyDao) Create(k *types.APIKey) error {
+	if dao.passphrase == "" {
+		logger.Error(ErrNoAPIKeyPassphrase)
+		return ErrNoAPIKeyPassphrase
+	}
+
+	err := k.Encrypt(dao.passphrase)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	k.ID = primitive.NewObjectID()
+	k.CreatedAt = time.Now()
+	k.UpdatedAt = time.Now()
+
+	err = dao.db.Create(dao.dbName, dao.collectionName, k)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByKey returns an API key with its secret, or nil if it does not exist
+func (dao *APIKeyDao) GetByKey(key string) (*types.APIKey, error) {
+	res := []*types.APIKey{}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{"key": key}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	err = dao.decrypt(res[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return res[0], nil
+}
+
+// GetByUserAddress returns the API keys of an user address, revoked ones included. Their
+// secrets are not decrypted
+func (dao *APIKeyDao) GetByUserAddress(addr common.Address) ([]*types.APIKey, error) {
+	res := []*types.APIKey{}
+
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, bson.M{"userAddress": addr.Hex()}, []string{"-createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Revoke revokes an API key of an user address. It returns ErrNotFound if the
+// address has no such key
+func (dao *APIKeyDao) Revoke(addr common.Address, key string) error {
+	q := bson.M{"key": key, "userAddress": addr.Hex()}
+	update := bson.M{"$set": bson.M{"revoked": true, "updatedAt": time.Now()}}
+
+	return dao.db.Update(dao.dbName, dao.collectionName, q, update)
+}
+
+// EncryptPlaintextSecrets encrypts the secrets of the API keys stored in plaintext, and returns
+// the number of keys encrypted
+func (dao *APIKeyDao) EncryptPlaintextSecrets() (int, error) {
+	q := bson.M{"secret": bson.M{"$exists": true}}
+	res := []*types.APIKey{}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	if len(res) == 0 {
+		return 0, nil
+	}
+
+	if dao.passphrase == "" {
+		logger.Error(ErrNoAPIKeyPassphrase)
+		return 0, ErrNoAPIKeyPassphrase
+	}
+
+	for i, k := range res {
+		err = k.Encrypt(dao.passphrase)
+		if err != nil {
+			logger.Error(err)
+			return i, err
+		}
+
+		update := bson.M{
+			"$set":   bson.M{"keystore": k.Keystore},
+			"$unset": bson.M{"secret": ""},
+		}
+
+		err = dao.db.Update(dao.dbName, dao.collectionName, bson.M{"_id": k.ID}, update)
+		if err != nil {
+			logger.Error(err)
+			return i, err
+		}
+	}
+
+	return len(res), nil
+}
+
+// decrypt decrypts the secret of an API key read from the store, unless the DAO has no
+// passphrase
+func (dao *APIKeyDao) decrypt(k *types.APIKey) error {
+	if k == nil || k.Keystore == "" || dao.passphrase == "" {
+		return nil
+	}
+
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	if secret, ok := dao.secrets[k.Key]; ok {
+		k.Secret = secret
+		return nil
+	}
+
+	err := k.Decrypt(dao.passphrase)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	dao.secrets[k.Key] = k.Secret
+	return nil
+}
+
+// Drop drops all the API keys in the current database
+func (dao *APIKeyDao) Drop() error {
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
@@ -4,10 +4,9 @@ import (
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // AssociationDao contains:
@@ -16,35 +15,36 @@ import (
 type AssociationDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
 // NewBalanceDao returns a new instance of AddressDao
-func NewAssociationDao() *AssociationDao {
+func NewAssociationDao(db Store) *AssociationDao {
 	dbName := app.Config.DBName
 	// we save deposit information and use config for retrieving params.
 	collection := "associations"
-	index := mgo.Index{
+	index := Index{
 		Key:    []string{"chain", "address"},
 		Unique: true,
 	}
 
 	// chain and associatedAddress also is uniqued
-	index1 := mgo.Index{
+	index1 := Index{
 		Key:    []string{"chain", "associatedAddress"},
 		Unique: true,
 	}
 
-	err := db.Session.DB(dbName).C(collection).EnsureIndex(index)
+	err := db.EnsureIndex(dbName, collection, index)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dbName).C(collection).EnsureIndex(index1)
+	err = db.EnsureIndex(dbName, collection, index1)
 	if err != nil {
 		panic(err)
 	}
 
-	return &AssociationDao{collection, dbName}
+	return &AssociationDao{collection, dbName, db}
 }
 
 // return the lowercase of the key
@@ -54,13 +54,13 @@ func (dao *AssociationDao) getAddressKey(address common.Address) string {
 
 // Drop drops all the order documents in the current database
 func (dao *AssociationDao) Drop() {
-	db.DropCollection(dao.dbName, dao.collectionName)
+	dao.db.DropCollection(dao.dbName, dao.collectionName)
 }
 
 // SaveDepositTransaction update the transaction envelope for association item
 func (dao *AssociationDao) SaveDepositTransaction(chain types.Chain, sourceAccount common.Address, txEnvelope string) error {
 	// txEnvolope is rlp of result
-	err := db.Update(dao.dbName, dao.collectionName, bson.M{
+	err := dao.db.Update(dao.dbName, dao.collectionName, bson.M{
 		"chain":   chain.String(),
 		"address": dao.getAddressKey(sourceAccount),
 	}, bson.M{
@@ -73,13 +73,13 @@ func (dao *AssociationDao) SaveDepositTransaction(chain types.Chain, sourceAccou
 
 func (dao *AssociationDao) GetAssociationByChainAddress(chain types.Chain, userAddress common.Address) (*types.AddressAssociationRecord, error) {
 	var response types.AddressAssociationRecord
-	err := db.GetOne(dao.dbName, dao.collectionName, bson.M{
+	err := dao.db.GetOne(dao.dbName, dao.collectionName, bson.M{
 		"chain":   chain.String(),
 		"address": dao.getAddressKey(userAddress),
 	}, &response)
 
 	// if not found, just return nil instead of error
-	if err == mgo.ErrNotFound {
+	if err == ErrNotFound {
 		return nil, nil
 	}
 
@@ -92,13 +92,13 @@ func (dao *AssociationDao) GetAssociationByChainAddress(chain types.Chain, userA
 
 func (dao *AssociationDao) GetAssociationByChainAssociatedAddress(chain types.Chain, associatedAddress common.Address) (*types.AddressAssociationRecord, error) {
 	var response types.AddressAssociationRecord
-	err := db.GetOne(dao.dbName, dao.collectionName, bson.M{
+	err := dao.db.GetOne(dao.dbName, dao.collectionName, bson.M{
 		"chain":             chain.String(),
 		"associatedAddress": dao.getAddressKey(associatedAddress),
 	}, &response)
 
 	// if not found, just return nil instead of error
-	if err == mgo.ErrNotFound {
+	if err == ErrNotFound {
 		return nil, nil
 	}
 
@@ -112,7 +112,7 @@ func (dao *AssociationDao) GetAssociationByChainAssociatedAddress(chain types.Ch
 // SaveAssociation using upsert to update for existing users, only update allowed fields
 func (dao *AssociationDao) SaveAssociation(record *types.AddressAssociationRecord) error {
 	associatedAddress := strings.ToLower(record.AssociatedAddress)
-	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{
 		"chain":             record.Chain,
 		"associatedAddress": associatedAddress,
 	}, bson.M{
@@ -131,7 +131,7 @@ func (dao *AssociationDao) SaveAssociation(record *types.AddressAssociationRecor
 }
 
 func (dao *AssociationDao) SaveAssociationStatus(chain types.Chain, sourceAccount common.Address, status string) error {
-	err := db.Update(dao.dbName, dao.collectionName, bson.M{
+	err := dao.db.Update(dao.dbName, dao.collectionName, bson.M{
 		"chain":   chain.String(),
 		"address": dao.getAddressKey(sourceAccount),
 	}, bson.M{
@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package daos
 
 import (
@@ -5,7 +8,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/globalsign/mgo"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
@@ -16,13 +18,13 @@ func TestDepositHistory(t *testing.T) {
 	if err != nil {
 		panic(err)
 	}
-	session, err := mgo.Dial(app.Config.MongoURL)
+	d, err := Connect(app.Config.MongoURL)
 	if err != nil {
 		panic(err)
 	}
 
-	db = &Database{session}
-	associationDao := NewAssociationDao()
+	db = d
+	associationDao := NewAssociationDao(db)
 
 	// test get history
 	chain := types.ChainEthereum
@@ -0,0 +1,163 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ClientOrderDao stores the client order IDs of the users until they expire, so that a
+// retried order submission is not published again
+type ClientOrderDao struct {
+	collectionName string
+	dbName         string
+	db             Store
+}
+
+// NewClientOrderDao returns a new instance of ClientOrderDao
+func NewClientOrderDao(db Store) *ClientOrderDao {
+	dao := &ClientOrderDao{db: db}
+	dao.collectionName = "client_orders"
+	dao.dbName = app.Config.DBName
+
+	index := Index{
+		Key:    []string{"userAddress", "clientOrderId"},
+		Unique: true,
+	}
+
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
+	if err != nil {
+		panic(err)
+	}
+
+	// the client orders are removed by mongoDB once they expire
+	i1 := Index{
+		Key:         []string{"expiresAt"},
+		ExpireAfter: time.Second,
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i1)
+	if err != nil {
+		panic(err)
+	}
+
+	return dao
+}
+
+// Reserve stores a client order. If the client order ID of the user is already held by
+// another client order, it returns that client order instead of storing c
+func (dao *ClientOrderDao) Reserve(c *types.ClientOrder) (*types.ClientOrder, error) {
+	for i := 0; i < 2; i++ {
+		err := dao.db.Create(dao.dbName, dao.collectionName, c)
+		if err == nil {
+			return nil, nil
+		}
+
+		if !IsDup(err) {
+			logger.Error(err)
+			return nil, err
+		}
+
+		existing, err := dao.GetByClientOrderID(c.UserAddress, c.ClientOrderID)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing != nil {
+			return existing, nil
+		}
+
+		// the client order holding the ID expired but was not removed yet
+		q := bson.M{
+			"userAddress":   c.UserAddress.Hex(),
+			"clientOrderId": c.ClientOrderID,
+			"expiresAt":     bson.M{"$lte": time.Now()},
+		}
+
+		err = dao.db.RemoveAll(dao.dbName, dao.collectionName, q)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+	}
+
+	return nil, errors.New("Failed to reserve the client order ID " + c.ClientOrderID)
+}
+
+// GetByClientOrderID returns the client order of a client order ID of an user, or nil if
+// the user did not use it or it expired
+func (dao *ClientOrderDao) GetByClientOrderID(addr common.Address, clientOrderID string) (*types.ClientOrder, error) {
+	q := bson.M{
+		"userAddress":   addr.Hex(),
+		"clientOrderId": clientOrderID,
+		"expiresAt":     bson.M{"$gt": time.Now()},
+	}
+
+	res := []*types.ClientOrder{}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0], nil
+}
+
+// Accept marks a reserved client order as ACCEPTED once its order is published
+func (dao *ClientOrderDao) Accept(c *types.ClientOrder) error {
+	q := bson.M{
+		"userAddress":   c.UserAddress.Hex(),
+		"clientOrderId": c.ClientOrderID,
+		"orderHash":     c.OrderHash.Hex(),
+	}
+
+	update := bson.M{"$set": bson.M{"status": types.ClientOrderStatusAccepted}}
+
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, update)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	c.Status = types.ClientOrderStatusAccepted
+	return nil
+}
+
+// Release removes a PENDING client order whose order could not be published, so that the
+// submission can be retried
+func (dao *ClientOrderDao) Release(c *types.ClientOrder) error {
+	q := bson.M{
+		"userAddress":   c.UserAddress.Hex(),
+		"clientOrderId": c.ClientOrderID,
+		"orderHash":     c.OrderHash.Hex(),
+		"status":        types.ClientOrderStatusPending,
+	}
+
+	err := dao.db.RemoveItem(dao.dbName, dao.collectionName, q)
+	if err != nil && err != ErrNotFound {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Drop drops all the client orders in the current database
+func (dao *ClientOrderDao) Drop() error {
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
@@ -2,12 +2,13 @@ package daos
 
 import (
 	"strconv"
+	"time"
 
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -17,6 +18,8 @@ const (
 	bitcoinAddressIndexKey  = "bitcoin_address_index"
 	bitcoinLastBlockKey     = "bitcoin_last_block"
 	defaultBlockIndex       = 0
+	resumeTokenKeyPrefix    = "resume_token_"
+	leaseKeyPrefix          = "lease_"
 )
 
 // ConfigDao contains:
@@ -25,24 +28,25 @@ const (
 type ConfigDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
 // NewBalanceDao returns a new instance of AddressDao
-func NewConfigDao() *ConfigDao {
+func NewConfigDao(db Store) *ConfigDao {
 	dbName := app.Config.DBName
 	// we save deposit information and use config for retrieving params.
 	collection := "config"
-	index := mgo.Index{
+	index := Index{
 		Key:    []string{"key"},
 		Unique: true,
 	}
 
-	err := db.Session.DB(dbName).C(collection).EnsureIndex(index)
+	err := db.EnsureIndex(dbName, collection, index)
 	if err != nil {
 		panic(err)
 	}
 
-	return &ConfigDao{collection, dbName}
+	return &ConfigDao{collection, dbName, db}
 }
 
 func (dao *ConfigDao) GetSchemaVersion() uint64 {
@@ -67,7 +71,7 @@ func (dao *ConfigDao) getAddressIndexKey(chain types.Chain) (string, error) {
 
 func (dao *ConfigDao) getValueFromKey(key string) (interface{}, error) {
 	var response types.KeyValue
-	err := db.GetOne(dao.dbName, dao.collectionName, bson.M{"key": key}, &response)
+	err := dao.db.GetOne(dao.dbName, dao.collectionName, bson.M{"key": key}, &response)
 	if err != nil {
 		logger.Errorf("Got error: %v", err)
 		return nil, errors.Errorf("Value not found for key: %s", key)
@@ -84,6 +88,8 @@ func (dao *ConfigDao) getUint64ValueFromKey(key string) (uint64, error) {
 	switch v := value.(type) {
 	case int:
 		return uint64(value.(int)), nil
+	case int32:
+		return uint64(value.(int32)), nil
 	case int64:
 		return uint64(value.(int64)), nil
 	case string:
@@ -109,7 +115,7 @@ func (dao *ConfigDao) IncrementAddressIndex(chain types.Chain) error {
 		return err
 	}
 
-	err = db.Update(dao.dbName, dao.collectionName, bson.M{"key": key}, bson.M{
+	err = dao.db.Update(dao.dbName, dao.collectionName, bson.M{"key": key}, bson.M{
 		"$inc": bson.M{
 			"value": 1,
 		},
@@ -142,7 +148,7 @@ func (dao *ConfigDao) SaveLastProcessedBlock(chain types.Chain, block uint64) er
 		return errors.New("Invalid chain")
 	}
 
-	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"key": key}, bson.M{
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{"key": key}, bson.M{
 		"$set": bson.M{
 			"value": block,
 		},
@@ -151,9 +157,110 @@ func (dao *ConfigDao) SaveLastProcessedBlock(chain types.Chain, block uint64) er
 	return err
 }
 
+// GetResumeToken returns the resume token of the last event handled from a change stream, or
+// nil if none was saved
+func (dao *ConfigDao) GetResumeToken(stream string) ([]byte, error) {
+	var response types.KeyValue
+	err := dao.db.GetOne(dao.dbName, dao.collectionName, bson.M{"key": resumeTokenKeyPrefix + stream}, &response)
+	if err == ErrNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	switch v := response.Value.(type) {
+	case primitive.Binary:
+		return v.Data, nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, errors.Errorf("Can not process type %T!\n", v)
+	}
+}
+
+// SaveResumeToken saves the resume token of the last event handled from a change stream, so
+// that the stream is resumed after it when it is opened again. A nil token removes it
+func (dao *ConfigDao) SaveResumeToken(stream string, token []byte) error {
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{"key": resumeTokenKeyPrefix + stream}, bson.M{
+		"$set": bson.M{
+			"value": token,
+		},
+	})
+
+	return err
+}
+
+// AcquireLease takes the lease of a name for an owner, or renews it if the owner holds it, until
+// ttl from now. It tells whether the owner holds the lease, which is not the case if another
+// owner holds it and it did not expire
+func (dao *ConfigDao) AcquireLease(name string, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	var lease types.Lease
+	err := dao.db.GetOne(dao.dbName, dao.collectionName, bson.M{"key": leaseKeyPrefix + name}, &lease)
+	if err != nil && err != ErrNotFound {
+		logger.Error(err)
+		return false, err
+	}
+
+	if err == nil && lease.Owner != owner && lease.ExpiresAt.After(now) {
+		return false, nil
+	}
+
+	query := bson.M{
+		"key": leaseKeyPrefix + name,
+		"$or": []bson.M{
+			{"owner": owner},
+			{"expiresAt": bson.M{"$lte": now}},
+		},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"owner":     owner,
+			"expiresAt": now.Add(ttl),
+		},
+	}
+
+	// the lease is inserted again if another owner took it meanwhile, which breaks the unique
+	// index of the key
+	_, err = dao.db.Upsert(dao.dbName, dao.collectionName, query, update)
+	if IsDup(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseLease releases the lease of a name if the owner holds it, so that another owner can take
+// it without waiting for it to expire
+func (dao *ConfigDao) ReleaseLease(name string, owner string) error {
+	query := bson.M{
+		"key":   leaseKeyPrefix + name,
+		"owner": owner,
+	}
+
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, bson.M{
+		"$set": bson.M{"expiresAt": time.Unix(0, 0)},
+	})
+
+	if err == ErrNotFound {
+		return nil
+	}
+
+	return err
+}
+
 // Drop drops all the order documents in the current database
 func (dao *ConfigDao) Drop() {
-	db.DropCollection(dao.dbName, dao.collectionName)
+	dao.db.DropCollection(dao.dbName, dao.collectionName)
 }
 
 // ResetBlockCounters changes last processed bitcoin and ethereum block to default value.
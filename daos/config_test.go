@@ -1,9 +1,11 @@
+//go:build integration
+// +build integration
+
 package daos
 
 import (
 	"testing"
 
-	"github.com/globalsign/mgo"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
 )
@@ -13,13 +15,13 @@ func TestConfigIncrementIndex(t *testing.T) {
 	if err != nil {
 		panic(err)
 	}
-	session, err := mgo.Dial(app.Config.MongoURL)
+	d, err := Connect(app.Config.MongoURL)
 	if err != nil {
 		panic(err)
 	}
 
-	db = &Database{session}
-	configDao := NewConfigDao()
+	db = d
+	configDao := NewConfigDao(db)
 	configDao.IncrementAddressIndex(types.ChainEthereum)
 	index, err := configDao.GetAddressIndex(types.ChainEthereum)
 	t.Logf("Current Address Index: %d, err  :%v", index, err)
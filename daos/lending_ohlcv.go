@@ -0,0 +1,184 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// LendingOhlcvDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type LendingOhlcvDao struct {
+	collectionName string
+	dbName         string
+	db             Store
+}
+
+type LendingOhlcvDaoOption = func(*LendingOhlcvDao) error
+
+func LendingOhlcvDaoDBOption(dbName string) func(dao *LendingOhlcvDao) error {
+	return func(dao *LendingOhlcvDao) error {
+		dao.dbName = dbName
+		return nil
+	}
+}
+
+// NewLendingOhlcvDao returns a new instance of LendingOhlcvDao
+func NewLendingOhlcvDao(db Store, opts ...LendingOhlcvDaoOption) *LendingOhlcvDao {
+	dao := &LendingOhlcvDao{db: db}
+	dao.collectionName = "lending_ohlcv"
+	dao.dbName = app.Config.DBName
+
+	for _, op := range opts {
+		err := op(dao)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	index := Index{
+		Key:    []string{"term", "lendingToken", "duration", "unit", "timestamp"},
+		Unique: true,
+	}
+
+	i1 := Index{
+		Key: []string{"closeTime"},
+	}
+
+	i2 := Index{
+		Key: []string{"updatedAt"},
+	}
+
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i1)
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i2)
+	if err != nil {
+		panic(err)
+	}
+
+	return dao
+}
+
+// UpsertTicks inserts the ticks, or replaces them if they are already stored
+func (dao *LendingOhlcvDao) UpsertTicks(ticks []*types.LendingTick) error {
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	pairs := []interface{}{}
+	for _, t := range ticks {
+		query := bson.M{
+			"term":         t.LendingID.Term,
+			"lendingToken": t.LendingID.LendingToken.Hex(),
+			"duration":     t.Duration,
+			"unit":         t.Unit,
+			"timestamp":    t.Timestamp,
+		}
+
+		pairs = append(pairs, query, bson.M{"$set": types.NewLendingTickRecord(t)})
+	}
+
+	err := dao.db.BulkUpsert(dao.dbName, dao.collectionName, pairs...)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetTicks returns the ticks whose timestamp is after from
+func (dao *LendingOhlcvDao) GetTicks(from int64) ([]*types.LendingTick, error) {
+	var res []*types.LendingTickRecord
+
+	q := bson.M{"timestamp": bson.M{"$gte": from}}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ticks := []*types.LendingTick{}
+	for _, r := range res {
+		ticks = append(ticks, r.LendingTick())
+	}
+
+	return ticks, nil
+}
+
+// GetTicksUpdatedSince returns the ticks stored since a time
+func (dao *LendingOhlcvDao) GetTicksUpdatedSince(since time.Time) ([]*types.LendingTick, error) {
+	var res []*types.LendingTickRecord
+
+	q := bson.M{"updatedAt": bson.M{"$gte": since}}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ticks := []*types.LendingTick{}
+	for _, r := range res {
+		ticks = append(ticks, r.LendingTick())
+	}
+
+	return ticks, nil
+}
+
+// GetLastTick returns the tick that includes the last trade, or nil if no tick is stored
+func (dao *LendingOhlcvDao) GetLastTick() (*types.LendingTick, error) {
+	var res []*types.LendingTickRecord
+
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"-closeTime"}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0].LendingTick(), nil
+}
+
+// DeleteTicks removes the ticks of a duration and unit whose timestamp is before to
+func (dao *LendingOhlcvDao) DeleteTicks(duration int64, unit string, to int64) error {
+	q := bson.M{
+		"duration":  duration,
+		"unit":      unit,
+		"timestamp": bson.M{"$lt": to},
+	}
+
+	err := dao.db.RemoveAll(dao.dbName, dao.collectionName, q)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Drop drops all the lending ticks in the current database
+func (dao *LendingOhlcvDao) Drop() error {
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
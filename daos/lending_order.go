@@ -1,8 +1,7 @@
 package daos
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"math/big"
 	"sort"
 	"strconv"
@@ -11,13 +10,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/tomox"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/math"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // LendingOrderDao contains:
@@ -26,15 +26,26 @@ import (
 type LendingOrderDao struct {
 	collectionName string
 	dbName         string
+	db             Store
+	tomoxClient    *tomox.Client
 }
 
 // LendingOrderDaoOption opts for database option
 type LendingOrderDaoOption = func(*LendingOrderDao) error
 
-// NewLendingOrderDao returns a new instance of LendingOrderDao
-func NewLendingOrderDao(opts ...LendingOrderDaoOption) *LendingOrderDao {
-	dao := &LendingOrderDao{}
-	dao.collectionName = "lending_items"
+// LendingOrderDaoTomoXOption sets the client of the TomoX nodes the lending orders are sent to
+func LendingOrderDaoTomoXOption(client *tomox.Client) func(dao *LendingOrderDao) error {
+	return func(dao *LendingOrderDao) error {
+		dao.tomoxClient = client
+		return nil
+	}
+}
+
+// newLendingOrderDao returns a LendingOrderDao of a collection. The lending orders are sent to
+// the TomoX nodes of the configuration unless a client is set by LendingOrderDaoTomoXOption
+func newLendingOrderDao(db Store, collectionName string, opts ...LendingOrderDaoOption) *LendingOrderDao {
+	dao := &LendingOrderDao{db: db}
+	dao.collectionName = collectionName
 	dao.dbName = app.Config.DBName
 
 	for _, op := range opts {
@@ -44,61 +55,72 @@ func NewLendingOrderDao(opts ...LendingOrderDaoOption) *LendingOrderDao {
 		}
 	}
 
-	index := mgo.Index{
+	if dao.tomoxClient == nil {
+		dao.tomoxClient = tomox.NewClient(app.Config.NodeURLs(), app.Config.TomoXTimeout)
+	}
+
+	return dao
+}
+
+// NewLendingOrderDao returns a new instance of LendingOrderDao
+func NewLendingOrderDao(db Store, opts ...LendingOrderDaoOption) *LendingOrderDao {
+	dao := newLendingOrderDao(db, "lending_items", opts...)
+
+	index := Index{
 		Key:    []string{"hash"},
 		Unique: true,
 	}
 
-	i1 := mgo.Index{
+	i1 := Index{
 		Key: []string{"userAddress"},
 	}
 
-	i2 := mgo.Index{
+	i2 := Index{
 		Key: []string{"status"},
 	}
 
-	i3 := mgo.Index{
+	i3 := Index{
 		Key: []string{"collateralToken"},
 	}
 
-	i4 := mgo.Index{
+	i4 := Index{
 		Key: []string{"lendingToken"},
 	}
-	i5 := mgo.Index{
+	i5 := Index{
 		Key: []string{"createdAt"},
 	}
-	indexes := []mgo.Index{}
-	indexes, err := db.Session.DB(dao.dbName).C(dao.collectionName).Indexes()
+	indexes := []Index{}
+	indexes, err := db.Indexes(dao.dbName, dao.collectionName)
 	if err == nil {
 		if !existedIndex("index_lending_item_hash", indexes) {
-			err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(index)
+			err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
 			if err != nil {
 				panic(err)
 			}
 		}
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i1)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i1)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i2)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i2)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i3)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i3)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i4)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i4)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i5)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i5)
 	if err != nil {
 		panic(err)
 	}
@@ -107,67 +129,33 @@ func NewLendingOrderDao(opts ...LendingOrderDaoOption) *LendingOrderDao {
 }
 
 // NewTopupDao topup dao
-func NewTopupDao(opts ...LendingOrderDaoOption) *LendingOrderDao {
-	dao := &LendingOrderDao{}
-	dao.collectionName = "lending_topups"
-	dao.dbName = app.Config.DBName
-
-	for _, op := range opts {
-		err := op(dao)
-		if err != nil {
-			panic(err)
-		}
-	}
-	return dao
+func NewTopupDao(db Store, opts ...LendingOrderDaoOption) *LendingOrderDao {
+	return newLendingOrderDao(db, "lending_topups", opts...)
 }
 
 // NewRepayDao repay dao
-func NewRepayDao(opts ...LendingOrderDaoOption) *LendingOrderDao {
-	dao := &LendingOrderDao{}
-	dao.collectionName = "lending_repays"
-	dao.dbName = app.Config.DBName
-
-	for _, op := range opts {
-		err := op(dao)
-		if err != nil {
-			panic(err)
-		}
-	}
-	return dao
+func NewRepayDao(db Store, opts ...LendingOrderDaoOption) *LendingOrderDao {
+	return newLendingOrderDao(db, "lending_repays", opts...)
 }
 
 // NewRecallDao recall dao
-func NewRecallDao(opts ...LendingOrderDaoOption) *LendingOrderDao {
-	dao := &LendingOrderDao{}
-	dao.collectionName = "lending_recalls"
-	dao.dbName = app.Config.DBName
-
-	for _, op := range opts {
-		err := op(dao)
-		if err != nil {
-			panic(err)
-		}
-	}
-	return dao
-}
-
-// GetCollection get collection name
-func (dao *LendingOrderDao) GetCollection() *mgo.Collection {
-	return db.GetCollection(dao.dbName, dao.collectionName)
+func NewRecallDao(db Store, opts ...LendingOrderDaoOption) *LendingOrderDao {
+	return newLendingOrderDao(db, "lending_recalls", opts...)
 }
 
 // Watch watch chaging database
-func (dao *LendingOrderDao) Watch() (*mgo.ChangeStream, *mgo.Session, error) {
-	return db.Watch(dao.dbName, dao.collectionName, mgo.ChangeStreamOptions{
-		FullDocument:   mgo.UpdateLookup,
-		MaxAwaitTimeMS: 500,
-		BatchSize:      1000,
+func (dao *LendingOrderDao) Watch(resumeAfter []byte) (interfaces.ChangeStream, error) {
+	return dao.db.Watch(dao.dbName, dao.collectionName, WatchOptions{
+		FullDocument: true,
+		MaxAwaitTime: 500 * time.Millisecond,
+		BatchSize:    1000,
+		ResumeAfter:  resumeAfter,
 	})
 }
 
 // Create function performs the DB insertion task for LendingOrder collection
 func (dao *LendingOrderDao) Create(o *types.LendingOrder) error {
-	o.ID = bson.NewObjectId()
+	o.ID = primitive.NewObjectID()
 	o.CreatedAt = time.Now()
 	o.UpdatedAt = time.Now()
 
@@ -175,7 +163,7 @@ func (dao *LendingOrderDao) Create(o *types.LendingOrder) error {
 		o.Status = "OPEN"
 	}
 
-	err := db.Create(dao.dbName, dao.collectionName, o)
+	err := dao.db.Create(dao.dbName, dao.collectionName, o)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -189,7 +177,7 @@ func (dao *LendingOrderDao) Create(o *types.LendingOrder) error {
 func (dao *LendingOrderDao) GetOrderCountByUserAddress(addr common.Address) (int, error) {
 	q := bson.M{"userAddress": addr.Hex()}
 
-	total, err := db.Count(dao.dbName, dao.collectionName, q)
+	total, err := dao.db.Count(dao.dbName, dao.collectionName, q)
 
 	if err != nil {
 		logger.Error(err)
@@ -201,9 +189,9 @@ func (dao *LendingOrderDao) GetOrderCountByUserAddress(addr common.Address) (int
 
 // GetByID function fetches a single document from order collection based on mongoDB ID.
 // Returns LendingOrder type struct
-func (dao *LendingOrderDao) GetByID(id bson.ObjectId) (*types.LendingOrder, error) {
+func (dao *LendingOrderDao) GetByID(id primitive.ObjectID) (*types.LendingOrder, error) {
 	var response *types.LendingOrder
-	err := db.GetByID(dao.dbName, dao.collectionName, id, &response)
+	err := dao.db.GetByID(dao.dbName, dao.collectionName, id, &response)
 	return response, err
 }
 
@@ -213,7 +201,7 @@ func (dao *LendingOrderDao) GetByHash(hash common.Hash) (*types.LendingOrder, er
 	q := bson.M{"hash": hash.Hex()}
 	res := []types.LendingOrder{}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -228,7 +216,7 @@ func (dao *LendingOrderDao) GetByHash(hash common.Hash) (*types.LendingOrder, er
 
 // Drop drops all the order documents in the current database
 func (dao *LendingOrderDao) Drop() error {
-	err := db.DropCollection(dao.dbName, dao.collectionName)
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -242,15 +230,15 @@ func (dao *LendingOrderDao) getSideLendingOrderBook(term uint64, lendingToken co
 	sides := []map[string]string{}
 
 	var lendingOrders []types.LendingOrder
-	c := dao.GetCollection()
 
 	// TODO: need to have limit
-	err := c.Find(bson.M{
+	q := bson.M{
 		"status":       bson.M{"$in": []string{types.OrderStatusOpen, types.OrderStatusPartialFilled}},
 		"term":         strconv.FormatUint(term, 10),
 		"lendingToken": lendingToken.Hex(),
 		"side":         side,
-	}).Sort("-createdAt").All(&lendingOrders)
+	}
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, 0, 0, &lendingOrders)
 
 	pa := make(map[string]*big.Int)
 	for _, lendingOrder := range lendingOrders {
@@ -297,79 +285,49 @@ func (dao *LendingOrderDao) GetLendingOrderBookInDb(term uint64, lendingToken co
 }
 
 func (dao *LendingOrderDao) GetLendingOrderBook(term uint64, lendingToken common.Address) ([]map[string]string, []map[string]string, error) {
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-	defer rpcClient.Close()
-
-	var result interface{}
+	ctx := context.Background()
 
-	err = rpcClient.Call(&result, "tomox_getInvests", lendingToken.Hex(), term)
 	asks := []map[string]string{}
-	if result != nil && err == nil {
-		for k, v := range result.(map[string]interface{}) {
-			s := map[string]string{
-				"interest": k,
-				"amount":   fmt.Sprintf("%.0f", v.(float64)),
-			}
-			asks = append(asks, s)
-		}
-
-		sort.SliceStable(asks, func(i, j int) bool {
-			return math.ToBigInt(asks[i]["interest"]).Cmp(math.ToBigInt(asks[j]["interest"])) == -1
-		})
+	result, err := dao.tomoxClient.GetInvests(ctx, lendingToken, term)
+	if err != nil {
+		logger.Error(err)
 	}
 
-	err = rpcClient.Call(&result, "tomox_getBorrows", lendingToken.Hex(), term)
-	bids := []map[string]string{}
-	if result != nil && err == nil {
-		for k, v := range result.(map[string]interface{}) {
-			s := map[string]string{
-				"interest": k,
-				"amount":   fmt.Sprintf("%.0f", v.(float64)),
-			}
-			bids = append(bids, s)
+	for k, v := range result {
+		s := map[string]string{
+			"interest": k,
+			"amount":   v.String(),
 		}
-		sort.SliceStable(bids, func(i, j int) bool {
-			return math.ToBigInt(bids[i]["interest"]).Cmp(math.ToBigInt(bids[j]["interest"])) == 1
-		})
+		asks = append(asks, s)
 	}
 
+	sort.SliceStable(asks, func(i, j int) bool {
+		return math.ToBigInt(asks[i]["interest"]).Cmp(math.ToBigInt(asks[j]["interest"])) == -1
+	})
+
+	bids := []map[string]string{}
+	result, err = dao.tomoxClient.GetBorrows(ctx, lendingToken, term)
 	if err != nil {
 		logger.Error(err)
-		return bids, asks, nil
 	}
 
-	return bids, asks, nil
-}
+	for k, v := range result {
+		s := map[string]string{
+			"interest": k,
+			"amount":   v.String(),
+		}
+		bids = append(bids, s)
+	}
+
+	sort.SliceStable(bids, func(i, j int) bool {
+		return math.ToBigInt(bids[i]["interest"]).Cmp(math.ToBigInt(bids[j]["interest"])) == 1
+	})
 
-// LendingOrderMsg for tomox rpc
-type LendingOrderMsg struct {
-	AccountNonce    hexutil.Uint64 `json:"nonce"    gencodec:"required"`
-	Quantity        hexutil.Big    `json:"quantity,omitempty"`
-	RelayerAddress  common.Address `json:"relayerAddress,omitempty"`
-	UserAddress     common.Address `json:"userAddress,omitempty"`
-	CollateralToken common.Address `json:"collateralToken,omitempty"`
-	LendingToken    common.Address `json:"lendingToken,omitempty"`
-	Interest        hexutil.Uint64 `json:"interest,omitempty"`
-	Term            hexutil.Uint64 `json:"term,omitempty"`
-	Status          string         `json:"status,omitempty"`
-	Side            string         `json:"side,omitempty"`
-	Type            string         `json:"type,omitempty"`
-	LendingID       hexutil.Uint64 `json:"lendingID,omitempty"`
-	LendingTradeID  hexutil.Uint64 `json:"tradeId,omitempty"`
-	AutoTopUp       bool           `json:"autoTopUp,omitempty"`
-	// Signature values
-	V hexutil.Big `json:"v" gencodec:"required"`
-	R hexutil.Big `json:"r" gencodec:"required"`
-	S hexutil.Big `json:"s" gencodec:"required"`
-
-	// This is only used when marshaling to JSON.
-	Hash common.Hash `json:"hash" rlp:"-"`
+	return bids, asks, nil
 }
 
 // AddNewLendingOrder add order
 func (dao *LendingOrderDao) AddNewLendingOrder(o *types.LendingOrder) error {
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-	defer rpcClient.Close()
 	bigstr := o.Nonce.String()
 	n, err := strconv.ParseInt(bigstr, 10, 64)
 	if err != nil {
@@ -381,7 +339,7 @@ func (dao *LendingOrderDao) AddNewLendingOrder(o *types.LendingOrder) error {
 
 	autoTopUp := (uint64(o.AutoTopUp) == uint64(1))
 
-	msg := LendingOrderMsg{
+	msg := &tomox.LendingOrderMsg{
 		AccountNonce:    hexutil.Uint64(uint64(n)),
 		Quantity:        hexutil.Big(*o.Quantity),
 		RelayerAddress:  o.RelayerAddress,
@@ -399,9 +357,8 @@ func (dao *LendingOrderDao) AddNewLendingOrder(o *types.LendingOrder) error {
 		R:               hexutil.Big(*R),
 		S:               hexutil.Big(*S),
 	}
-	var result interface{}
 	logger.Info("tomox_sendLending", o.Status, o.Hash.Hex())
-	err = rpcClient.Call(&result, "tomox_sendLending", msg)
+	err = dao.tomoxClient.SendLending(context.Background(), msg)
 
 	if err != nil {
 		logger.Error(err)
@@ -418,9 +375,6 @@ func (dao *LendingOrderDao) AddNewLendingOrder(o *types.LendingOrder) error {
 
 // CancelLendingOrder cancel order
 func (dao *LendingOrderDao) CancelLendingOrder(o *types.LendingOrder) error {
-
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-	defer rpcClient.Close()
 	bigstr := o.Nonce.String()
 	n, err := strconv.ParseInt(bigstr, 10, 64)
 	if err != nil {
@@ -430,7 +384,7 @@ func (dao *LendingOrderDao) CancelLendingOrder(o *types.LendingOrder) error {
 	R := o.Signature.R.Big()
 	S := o.Signature.S.Big()
 
-	msg := LendingOrderMsg{
+	msg := &tomox.LendingOrderMsg{
 		AccountNonce:    hexutil.Uint64(uint64(n)),
 		Status:          o.Status,
 		Hash:            o.Hash,
@@ -445,9 +399,8 @@ func (dao *LendingOrderDao) CancelLendingOrder(o *types.LendingOrder) error {
 		R:               hexutil.Big(*R),
 		S:               hexutil.Big(*S),
 	}
-	var result interface{}
 	logger.Info("tomox_sendLending", o.Status, o.Hash.Hex(), o.LendingID, o.UserAddress.Hex(), n)
-	err = rpcClient.Call(&result, "tomox_sendLending", msg)
+	err = dao.tomoxClient.SendLending(context.Background(), msg)
 
 	if err != nil {
 		logger.Error(err)
@@ -463,9 +416,6 @@ func (dao *LendingOrderDao) CancelLendingOrder(o *types.LendingOrder) error {
 
 // RepayLendingOrder send repay transaction
 func (dao *LendingOrderDao) RepayLendingOrder(o *types.LendingOrder) error {
-
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-	defer rpcClient.Close()
 	bigstr := o.Nonce.String()
 	n, err := strconv.ParseInt(bigstr, 10, 64)
 	if err != nil {
@@ -475,7 +425,7 @@ func (dao *LendingOrderDao) RepayLendingOrder(o *types.LendingOrder) error {
 	R := o.Signature.R.Big()
 	S := o.Signature.S.Big()
 
-	msg := LendingOrderMsg{
+	msg := &tomox.LendingOrderMsg{
 		AccountNonce:   hexutil.Uint64(uint64(n)),
 		Status:         o.Status,
 		UserAddress:    o.UserAddress,
@@ -488,9 +438,8 @@ func (dao *LendingOrderDao) RepayLendingOrder(o *types.LendingOrder) error {
 		R:              hexutil.Big(*R),
 		S:              hexutil.Big(*S),
 	}
-	var result interface{}
 	logger.Info("tomox_sendLending", o.Status, o.Hash.Hex(), o.LendingTradeID, o.UserAddress.Hex(), n)
-	err = rpcClient.Call(&result, "tomox_sendLending", msg)
+	err = dao.tomoxClient.SendLending(context.Background(), msg)
 
 	if err != nil {
 		logger.Error(err)
@@ -506,9 +455,6 @@ func (dao *LendingOrderDao) RepayLendingOrder(o *types.LendingOrder) error {
 
 // TopupLendingOrder send top up lending transaction
 func (dao *LendingOrderDao) TopupLendingOrder(o *types.LendingOrder) error {
-
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-	defer rpcClient.Close()
 	bigstr := o.Nonce.String()
 	n, err := strconv.ParseInt(bigstr, 10, 64)
 	if err != nil {
@@ -518,7 +464,7 @@ func (dao *LendingOrderDao) TopupLendingOrder(o *types.LendingOrder) error {
 	R := o.Signature.R.Big()
 	S := o.Signature.S.Big()
 
-	msg := LendingOrderMsg{
+	msg := &tomox.LendingOrderMsg{
 		AccountNonce:   hexutil.Uint64(uint64(n)),
 		Status:         o.Status,
 		UserAddress:    o.UserAddress,
@@ -532,9 +478,8 @@ func (dao *LendingOrderDao) TopupLendingOrder(o *types.LendingOrder) error {
 		R:              hexutil.Big(*R),
 		S:              hexutil.Big(*S),
 	}
-	var result interface{}
 	logger.Info("tomox_sendLending", o.Status, o.Hash.Hex(), o.LendingTradeID, o.UserAddress.Hex(), n)
-	err = rpcClient.Call(&result, "tomox_sendLending", msg)
+	err = dao.tomoxClient.SendLending(context.Background(), msg)
 
 	if err != nil {
 		logger.Error(err)
@@ -550,51 +495,29 @@ func (dao *LendingOrderDao) TopupLendingOrder(o *types.LendingOrder) error {
 
 // GetLendingNonce get nonce of lending order
 func (dao *LendingOrderDao) GetLendingNonce(userAddress common.Address) (uint64, error) {
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-
-	defer rpcClient.Close()
-
-	if err != nil {
-		logger.Error(err)
-		return 0, err
-	}
-
-	var result interface{}
+	n, err := dao.tomoxClient.GetLendingOrderCount(context.Background(), userAddress)
 	if err != nil {
 		logger.Error(err)
 		return 0, err
 	}
 
-	err = rpcClient.Call(&result, "tomox_getLendingOrderCount", userAddress)
-
-	if err != nil {
-		logger.Error(err)
-		return 0, err
-	}
-	logger.Info("OrderNonce:", result)
-	s := result.(string)
-	s = strings.TrimPrefix(s, "0x")
-	n, err := strconv.ParseUint(s, 16, 32)
-	if err != nil {
-		return 0, nil
-	}
-
+	logger.Info("OrderNonce:", n)
 	return n, nil
 }
 
 // GetLendingOrderBookInterest get amount from interest
 func (dao *LendingOrderDao) GetLendingOrderBookInterest(term uint64, lendingToken common.Address, interest uint64, side string) (*big.Int, error) {
 	var orders []types.LendingOrder
-	c := dao.GetCollection()
 
 	//TODO: need to have limit
-	err := c.Find(bson.M{
+	q := bson.M{
 		"status":       bson.M{"$in": []string{types.OrderStatusOpen, types.OrderStatusPartialFilled}},
 		"term":         strconv.FormatUint(term, 10),
 		"lendingToken": lendingToken.Hex(),
 		"side":         side,
 		"interest":     strconv.FormatUint(interest, 10),
-	}).Sort("-createdAt").All(&orders)
+	}
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, 0, 0, &orders)
 
 	amount := big.NewInt(0)
 
@@ -612,7 +535,7 @@ func (dao *LendingOrderDao) UpdateStatus(h common.Hash, status string) error {
 		"status": status,
 	}}
 
-	err := db.Update(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -625,7 +548,7 @@ func (dao *LendingOrderDao) UpdateStatus(h common.Hash, status string) error {
 func (dao *LendingOrderDao) UpdateFilledAmount(hash common.Hash, value *big.Int) error {
 	q := bson.M{"hash": hash.Hex()}
 	res := []types.LendingOrder{}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -650,7 +573,7 @@ func (dao *LendingOrderDao) UpdateFilledAmount(hash common.Hash, value *big.Int)
 		"filledAmount": filledAmount.String(),
 	}}
 
-	err = db.Update(dao.dbName, dao.collectionName, q, update)
+	err = dao.db.Update(dao.dbName, dao.collectionName, q, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -701,7 +624,7 @@ func (dao *LendingOrderDao) GetLendingOrders(lendingSpec types.LendingSpec, sort
 	}
 	var res types.LendingRes
 	lendings := []*types.LendingOrder{}
-	c, err := db.GetEx(dao.dbName, dao.collectionName, q, sort, offset, size, &lendings)
+	c, err := dao.db.GetEx(dao.dbName, dao.collectionName, q, sort, offset, size, &lendings)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -716,33 +639,11 @@ func (dao *LendingOrderDao) GetLendingOrders(lendingSpec types.LendingSpec, sort
 
 // GetLastTokenPrice get last token price
 func (dao *LendingOrderDao) GetLastTokenPrice(bToken common.Address, qToken common.Address) (*big.Int, error) {
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-
-	defer rpcClient.Close()
-
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	var result interface{}
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	err = rpcClient.Call(&result, "tomox_getLastEpochPrice", bToken, qToken)
-
+	price, err := dao.tomoxClient.GetLastEpochPrice(context.Background(), bToken, qToken)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
-	s := result.(string)
-	s = strings.TrimPrefix(s, "0x")
-	n, ok := new(big.Int).SetString(s, 16)
-	if !ok {
-		return nil, errors.New("error parse price")
-	}
 
-	return n, nil
+	return price, nil
 }
@@ -5,10 +5,10 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // LendingPairDao contains:
@@ -17,6 +17,7 @@ import (
 type LendingPairDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
 // LendingPairDaoOption option
@@ -31,8 +32,8 @@ func LendingPairDaoDBOption(dbName string) func(dao *LendingPairDao) error {
 }
 
 // NewLendingPairDao returns a new instance of AddressDao
-func NewLendingPairDao(options ...LendingPairDaoOption) *LendingPairDao {
-	dao := &LendingPairDao{}
+func NewLendingPairDao(db Store, options ...LendingPairDaoOption) *LendingPairDao {
+	dao := &LendingPairDao{db: db}
 	dao.collectionName = "lending_pairs"
 	dao.dbName = app.Config.DBName
 
@@ -42,11 +43,11 @@ func NewLendingPairDao(options ...LendingPairDaoOption) *LendingPairDao {
 			panic(err)
 		}
 	}
-	index := mgo.Index{
+	index := Index{
 		Key:    []string{"lendingTokenAddress", "term", "relayerAddress"},
 		Unique: true,
 	}
-	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(index)
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
 	if err != nil {
 		panic(err)
 	}
@@ -56,11 +57,11 @@ func NewLendingPairDao(options ...LendingPairDaoOption) *LendingPairDao {
 
 // Create function performs the DB insertion task for pair collection
 func (dao *LendingPairDao) Create(pair *types.LendingPair) error {
-	pair.ID = bson.NewObjectId()
+	pair.ID = primitive.NewObjectID()
 	pair.CreatedAt = time.Now()
 	pair.UpdatedAt = time.Now()
 
-	err := db.Create(dao.dbName, dao.collectionName, pair)
+	err := dao.db.Create(dao.dbName, dao.collectionName, pair)
 	return err
 }
 
@@ -68,7 +69,7 @@ func (dao *LendingPairDao) Create(pair *types.LendingPair) error {
 // for GetAll return continous memory
 func (dao *LendingPairDao) GetAll() ([]types.LendingPair, error) {
 	var res []types.LendingPair
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +90,7 @@ func (dao *LendingPairDao) GetAll() ([]types.LendingPair, error) {
 
 func (dao *LendingPairDao) GetAllByCoinbase(addr common.Address) ([]types.LendingPair, error) {
 	var res []types.LendingPair
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{"relayerAddress": addr.Hex()}, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{"relayerAddress": addr.Hex()}, 0, 0, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -98,28 +99,28 @@ func (dao *LendingPairDao) GetAllByCoinbase(addr common.Address) ([]types.Lendin
 }
 
 // GetByID function fetches details of a pair using pair's mongo ID.
-func (dao *LendingPairDao) GetByID(id bson.ObjectId) (*types.LendingPair, error) {
+func (dao *LendingPairDao) GetByID(id primitive.ObjectID) (*types.LendingPair, error) {
 	var response *types.LendingPair
-	err := db.GetByID(dao.dbName, dao.collectionName, id, &response)
+	err := dao.db.GetByID(dao.dbName, dao.collectionName, id, &response)
 	return response, err
 }
 
 // DeleteByLendingKey delete token by term and lending token address
 func (dao *LendingPairDao) DeleteByLendingKey(term uint64, lendingAddress common.Address) error {
 	query := bson.M{"lendingTokenAddress": lendingAddress.Hex(), "term": strconv.FormatUint(term, 10)}
-	return db.RemoveItem(dao.dbName, dao.collectionName, query)
+	return dao.db.RemoveItem(dao.dbName, dao.collectionName, query)
 }
 
 func (dao *LendingPairDao) DeleteByLendingKeyAndCoinbase(term uint64, lendingAddress common.Address, addr common.Address) error {
 	query := bson.M{"relayerAddress": addr.Hex(), "lendingTokenAddress": lendingAddress.Hex(), "term": strconv.FormatUint(term, 10)}
-	return db.RemoveItem(dao.dbName, dao.collectionName, query)
+	return dao.db.RemoveItem(dao.dbName, dao.collectionName, query)
 }
 
 // GetByLendingID get pair from lending token and term
 func (dao *LendingPairDao) GetByLendingID(term uint64, lendingAddress common.Address) (*types.LendingPair, error) {
 	var res types.LendingPair
 	query := bson.M{"lendingTokenAddress": lendingAddress.Hex(), "term": strconv.FormatUint(term, 10)}
-	err := db.GetOne(dao.dbName, dao.collectionName, query, &res)
+	err := dao.db.GetOne(dao.dbName, dao.collectionName, query, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -5,10 +5,11 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // LendingTradeDao contains:
@@ -17,74 +18,71 @@ import (
 type LendingTradeDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
 // NewLendingTradeDao returns a new instance of LendingTradeDao.
-func NewLendingTradeDao() *LendingTradeDao {
+func NewLendingTradeDao(db Store) *LendingTradeDao {
 	dbName := app.Config.DBName
 	collection := "lending_trades"
 
-	i1 := mgo.Index{
+	i1 := Index{
 		Key: []string{"collateralToken"},
 	}
 
-	i2 := mgo.Index{
+	i2 := Index{
 		Key: []string{"lendingToken"},
 	}
 
-	i3 := mgo.Index{
+	i3 := Index{
 		Key: []string{"createdAt"},
 	}
 
-	i4 := mgo.Index{
+	i4 := Index{
 		Key:    []string{"hash"},
 		Unique: true,
 		Sparse: true,
 	}
 
-	i5 := mgo.Index{
+	i5 := Index{
 		Key:    []string{"borrowingHash"},
 		Sparse: true,
 	}
 
-	i6 := mgo.Index{
+	i6 := Index{
 		Key:    []string{"investingHash"},
 		Sparse: true,
 	}
 
-	i7 := mgo.Index{
+	i7 := Index{
 		Key: []string{"createdAt", "status", "collateralToken", "lendingToken"},
 	}
 
-	indexes := []mgo.Index{}
-	indexes, err := db.Session.DB(dbName).C(collection).Indexes()
+	indexes := []Index{}
+	indexes, err := db.Indexes(dbName, collection)
 	if err == nil {
 		if !existedIndex("index_lending_trade_hash", indexes) {
-			db.Session.DB(dbName).C(collection).EnsureIndex(i4)
+			db.EnsureIndex(dbName, collection, i4)
 		}
 	}
 
-	db.Session.DB(dbName).C(collection).EnsureIndex(i1)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i2)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i3)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i5)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i6)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i7)
+	db.EnsureIndex(dbName, collection, i1)
+	db.EnsureIndex(dbName, collection, i2)
+	db.EnsureIndex(dbName, collection, i3)
+	db.EnsureIndex(dbName, collection, i5)
+	db.EnsureIndex(dbName, collection, i6)
+	db.EnsureIndex(dbName, collection, i7)
 
-	return &LendingTradeDao{collection, dbName}
-}
-
-// GetCollection get trade collection name
-func (dao *LendingTradeDao) GetCollection() *mgo.Collection {
-	return db.GetCollection(dao.dbName, dao.collectionName)
+	return &LendingTradeDao{collection, dbName, db}
 }
 
 // Watch changing database
-func (dao *LendingTradeDao) Watch() (*mgo.ChangeStream, *mgo.Session, error) {
-	return db.Watch(dao.dbName, dao.collectionName, mgo.ChangeStreamOptions{
-		FullDocument:   mgo.UpdateLookup,
-		MaxAwaitTimeMS: 500,
-		BatchSize:      1000,
+func (dao *LendingTradeDao) Watch(resumeAfter []byte) (interfaces.ChangeStream, error) {
+	return dao.db.Watch(dao.dbName, dao.collectionName, WatchOptions{
+		FullDocument: true,
+		MaxAwaitTime: 500 * time.Millisecond,
+		BatchSize:    1000,
+		ResumeAfter:  resumeAfter,
 	})
 }
 
@@ -95,13 +93,13 @@ func (dao *LendingTradeDao) Create(trades ...*types.LendingTrade) error {
 	y := make([]interface{}, len(trades))
 
 	for _, trade := range trades {
-		trade.ID = bson.NewObjectId()
+		trade.ID = primitive.NewObjectID()
 		trade.CreatedAt = time.Now()
 		trade.UpdatedAt = time.Now()
 		y = append(y, trade)
 	}
 
-	err := db.Create(dao.dbName, dao.collectionName, y...)
+	err := dao.db.Create(dao.dbName, dao.collectionName, y...)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -113,7 +111,7 @@ func (dao *LendingTradeDao) Create(trades ...*types.LendingTrade) error {
 // Update update lending trade record
 func (dao *LendingTradeDao) Update(trade *types.LendingTrade) error {
 	trade.UpdatedAt = time.Now()
-	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": trade.TradeID}, trade)
+	err := dao.db.Update(dao.dbName, dao.collectionName, bson.M{"_id": trade.TradeID}, trade)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -123,10 +121,10 @@ func (dao *LendingTradeDao) Update(trade *types.LendingTrade) error {
 }
 
 // Upsert update lending trade record by id
-func (dao *LendingTradeDao) Upsert(id bson.ObjectId, t *types.LendingTrade) error {
+func (dao *LendingTradeDao) Upsert(id primitive.ObjectID, t *types.LendingTrade) error {
 	t.UpdatedAt = time.Now()
 
-	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"_id": id}, t)
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{"_id": id}, t)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -139,7 +137,7 @@ func (dao *LendingTradeDao) Upsert(id bson.ObjectId, t *types.LendingTrade) erro
 func (dao *LendingTradeDao) UpsertByHash(h common.Hash, t *types.LendingTrade) error {
 	t.UpdatedAt = time.Now()
 
-	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"hash": h}, t)
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{"hash": h}, t)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -151,7 +149,7 @@ func (dao *LendingTradeDao) UpsertByHash(h common.Hash, t *types.LendingTrade) e
 // GetAll function fetches all the trades in mongodb
 func (dao *LendingTradeDao) GetAll() ([]types.LendingTrade, error) {
 	var response []types.LendingTrade
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &response)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &response)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -164,7 +162,7 @@ func (dao *LendingTradeDao) GetAll() ([]types.LendingTrade, error) {
 func (dao *LendingTradeDao) Aggregate(q []bson.M) ([]*types.Tick, error) {
 	var res []*types.Tick
 
-	err := db.Aggregate(dao.dbName, dao.collectionName, q, &res)
+	err := dao.db.Aggregate(dao.dbName, dao.collectionName, q, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -178,7 +176,7 @@ func (dao *LendingTradeDao) GetByHash(h common.Hash) (*types.LendingTrade, error
 	q := bson.M{"hash": h.Hex()}
 
 	res := []*types.LendingTrade{}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -211,7 +209,7 @@ func (dao *LendingTradeDao) GetLendingTradeByOrderBook(term uint64, lendingToken
 
 	sort := []string{"-createdAt"}
 
-	err := db.GetAndSort(dao.dbName, dao.collectionName, q, sort, 0, n, &res)
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, sort, 0, n, &res)
 
 	if err != nil {
 		logger.Error(err)
@@ -278,7 +276,7 @@ func (dao *LendingTradeDao) GetSortedLendingTradesByUserAddress(a, bt, qt common
 
 	sort := []string{"-createdAt"}
 
-	err := db.GetAndSort(dao.dbName, dao.collectionName, q, sort, 0, limit[0], &res)
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, sort, 0, limit[0], &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -292,7 +290,7 @@ func (dao *LendingTradeDao) GetByUserAddress(a common.Address) ([]*types.Lending
 	var res []*types.LendingTrade
 	q := bson.M{"$or": []bson.M{{"maker": a.Hex()}, {"taker": a.Hex()}}}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -306,7 +304,7 @@ func (dao *LendingTradeDao) UpdateTradeStatus(h common.Hash, status string) erro
 	query := bson.M{"hash": h.Hex()}
 	update := bson.M{"$set": bson.M{"status": status}}
 
-	err := db.Update(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -325,7 +323,7 @@ func (dao *LendingTradeDao) GetLendingTradeByTime(dateFrom, dateTo int64, pageOf
 	q["createdAt"] = dateFilter
 
 	trades := []*types.LendingTrade{}
-	_, err := db.GetEx(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, pageOffset, pageSize, &trades)
+	_, err := dao.db.GetEx(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, pageOffset, pageSize, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -361,7 +359,7 @@ func (dao *LendingTradeDao) GetLendingTradesUserHistory(a common.Address, lendin
 	}
 	var res types.LendingTradeRes
 	trades := []*types.LendingTrade{}
-	c, err := db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
+	c, err := dao.db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -396,7 +394,7 @@ func (dao *LendingTradeDao) GetLendingTrades(lendingtradeSpec *types.LendingTrad
 	}
 	var res types.LendingTradeRes
 	trades := []*types.LendingTrade{}
-	c, err := db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
+	c, err := dao.db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
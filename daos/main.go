@@ -1,8 +1,6 @@
 package daos
 
-import "github.com/globalsign/mgo"
-
-func existedIndex(indexName string, indexes []mgo.Index) bool {
+func existedIndex(indexName string, indexes []Index) bool {
 	for _, index := range indexes {
 		if index.Name == indexName {
 			return true
@@ -0,0 +1,1281 @@
+package daos
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryLogSize is the number of change events of a collection kept to resume the change streams
+const memoryLogSize = 1000
+
+// errAggregateNotSupported is returned by the aggregation pipelines of a MemoryStore
+var errAggregateNotSupported = errors.New("Aggregation pipelines are not supported by the memory store")
+
+// errStreamClosed is returned by a memory change stream once it is closed
+var errStreamClosed = errors.New("Change stream is closed")
+
+// MemoryStore keeps the collections in memory. The documents are stored the way they are
+// encoded to BSON, and the queries, sorts and updates support the operators used by the
+// DAOs, the unique and TTL indexes, and the change streams. The aggregation pipelines are
+// not supported, so the pair stats, the markets and the price board are not available
+type MemoryStore struct {
+	mu          sync.Mutex
+	collections map[string]*memoryCollection
+}
+
+type memoryCollection struct {
+	docs    []bson.M
+	indexes []Index
+	streams []*memoryStream
+	log     []*memoryEvent
+}
+
+// memoryEvent is a change event of a collection
+type memoryEvent struct {
+	id            string
+	time          time.Time
+	operationType string
+	doc           bson.M
+	full          bool
+}
+
+// NewMemoryStore returns a new instance of MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{collections: make(map[string]*memoryCollection)}
+}
+
+// collection returns a collection, created if it does not exist yet, without its expired
+// documents. It is called with the lock held
+func (s *MemoryStore) collection(dbName, name string) *memoryCollection {
+	c, ok := s.collections[dbName+"."+name]
+	if !ok {
+		c = &memoryCollection{}
+		s.collections[dbName+"."+name] = c
+	}
+
+	c.expire(time.Now())
+	return c
+}
+
+// EnsureIndex implements Store. Only the unique and the TTL indexes change the behavior of
+// the collection
+func (s *MemoryStore) EnsureIndex(dbName, collection string, index Index) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(dbName, collection)
+	for _, i := range c.indexes {
+		if reflect.DeepEqual(i.Key, index.Key) {
+			return nil
+		}
+	}
+
+	c.indexes = append(c.indexes, index)
+	return nil
+}
+
+// Indexes implements Store
+func (s *MemoryStore) Indexes(dbName, collection string) ([]Index, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Index{}, s.collection(dbName, collection).indexes...), nil
+}
+
+// Create implements Store
+func (s *MemoryStore) Create(dbName, collection string, data ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(dbName, collection)
+	for _, d := range data {
+		doc, err := toDocument(d)
+		if err != nil {
+			return err
+		}
+
+		err = c.insert(doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Watch implements Store. The stream gets the inserts, updates and replacements made after it
+// is opened, or after the event of the ResumeAfter option if it is in the last events of the
+// collection, and waits for them up to the MaxAwaitTime of the options
+func (s *MemoryStore) Watch(dbName, collection string, options WatchOptions) (interfaces.ChangeStream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(dbName, collection)
+	stream := &memoryStream{
+		notify:       make(chan struct{}, 1),
+		maxAwait:     options.MaxAwaitTime,
+		fullDocument: options.FullDocument,
+		ns:           bson.M{"db": dbName, "coll": collection},
+	}
+
+	if len(options.ResumeAfter) > 0 {
+		token := struct {
+			Data string `bson:"_data"`
+		}{}
+
+		err := bson.Unmarshal(options.ResumeAfter, &token)
+		if err != nil {
+			return nil, err
+		}
+
+		i := 0
+		for i < len(c.log) && c.log[i].id != token.Data {
+			i++
+		}
+
+		if i == len(c.log) {
+			return nil, ErrResumeTokenNotFound
+		}
+
+		for _, ev := range c.log[i+1:] {
+			stream.push(ev)
+		}
+	}
+
+	stream.close = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for i, st := range c.streams {
+			if st == stream {
+				c.streams = append(c.streams[:i], c.streams[i+1:]...)
+				break
+			}
+		}
+	}
+
+	c.streams = append(c.streams, stream)
+	return stream, nil
+}
+
+// Count implements Store
+func (s *MemoryStore) Count(dbName, collection string, query interface{}) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docs, err := s.collection(dbName, collection).find(query, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(docs), nil
+}
+
+// GetByID implements Store
+func (s *MemoryStore) GetByID(dbName, collection string, id primitive.ObjectID, response interface{}) error {
+	return s.GetOne(dbName, collection, bson.M{"_id": id}, response)
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(dbName, collection string, query interface{}, offset, limit int, response interface{}) error {
+	return s.GetAndSort(dbName, collection, query, nil, offset, limit, response)
+}
+
+// GetOne implements Store
+func (s *MemoryStore) GetOne(dbName, collection string, query interface{}, response interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docs, err := s.collection(dbName, collection).find(query, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(docs) == 0 {
+		return ErrNotFound
+	}
+
+	return fromDocument(docs[0], response)
+}
+
+// GetAndSort implements Store
+func (s *MemoryStore) GetAndSort(dbName, collection string, query interface{}, sort []string, offset, limit int, response interface{}) error {
+	_, err := s.GetEx(dbName, collection, query, sort, offset, limit, response)
+	return err
+}
+
+// GetEx implements Store
+func (s *MemoryStore) GetEx(dbName, collection string, query interface{}, sort []string, offset, limit int, response interface{}) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	docs, err := s.collection(dbName, collection).find(query, sort)
+	if err != nil {
+		return 0, err
+	}
+
+	count := len(docs)
+	docs = page(docs, offset, limit)
+
+	return count, fromDocuments(docs, response)
+}
+
+// Update implements Store
+func (s *MemoryStore) Update(dbName, collection string, query interface{}, update interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := s.collection(dbName, collection).update(query, update, false, false)
+	if err != nil {
+		return err
+	}
+
+	if info.Matched == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Upsert implements Store
+func (s *MemoryStore) Upsert(dbName, collection string, query interface{}, update interface{}) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := s.collection(dbName, collection).update(query, update, false, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.UpsertedID, nil
+}
+
+// BulkUpsert implements Store
+func (s *MemoryStore) BulkUpsert(dbName, collection string, pairs ...interface{}) error {
+	if len(pairs)%2 != 0 {
+		return errors.New("Bulk upsert requires a selector and an update for each document")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(dbName, collection)
+	for i := 0; i < len(pairs); i += 2 {
+		_, err := c.update(pairs[i], pairs[i+1], false, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateAll implements Store
+func (s *MemoryStore) UpdateAll(dbName, collection string, query interface{}, update interface{}) error {
+	_, err := s.ChangeAll(dbName, collection, query, update)
+	return err
+}
+
+// ChangeAll implements Store
+func (s *MemoryStore) ChangeAll(dbName, collection string, query interface{}, update interface{}) (*ChangeInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.collection(dbName, collection).update(query, update, true, false)
+}
+
+// FindAndModify implements Store
+func (s *MemoryStore) FindAndModify(dbName, collection string, query interface{}, change Change, response interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(dbName, collection)
+	docs, err := c.find(query, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(docs) == 0 && !change.Upsert {
+		return ErrNotFound
+	}
+
+	if change.Remove {
+		if len(docs) == 0 {
+			return ErrNotFound
+		}
+
+		c.remove(docs[:1])
+		return fromDocument(docs[0], response)
+	}
+
+	info, err := c.update(query, change.Update, false, change.Upsert)
+	if err != nil {
+		return err
+	}
+
+	if !change.ReturnNew {
+		if len(docs) == 0 {
+			return nil
+		}
+
+		return fromDocument(docs[0], response)
+	}
+
+	id := info.UpsertedID
+	if id == nil {
+		id = docs[0]["_id"]
+	}
+
+	for _, doc := range c.docs {
+		if compareValues(doc["_id"], id) == 0 {
+			return fromDocument(doc, response)
+		}
+	}
+
+	return ErrNotFound
+}
+
+// Aggregate implements Store. The aggregation pipelines are not supported
+func (s *MemoryStore) Aggregate(dbName, collection string, query []bson.M, response interface{}) error {
+	return errAggregateNotSupported
+}
+
+// RemoveItem implements Store
+func (s *MemoryStore) RemoveItem(dbName, collection string, query interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(dbName, collection)
+	docs, err := c.find(query, nil)
+	if err != nil {
+		return err
+	}
+
+	if len(docs) == 0 {
+		return ErrNotFound
+	}
+
+	c.remove(docs[:1])
+	return nil
+}
+
+// RemoveAll implements Store
+func (s *MemoryStore) RemoveAll(dbName, collection string, query interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collection(dbName, collection)
+	docs, err := c.find(query, nil)
+	if err != nil {
+		return err
+	}
+
+	c.remove(docs)
+	return nil
+}
+
+// DropCollection implements Store
+func (s *MemoryStore) DropCollection(dbName, collection string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.collections, dbName+"."+collection)
+	return nil
+}
+
+// find returns the documents matching a query, in the order of sort
+func (c *memoryCollection) find(query interface{}, sort []string) ([]bson.M, error) {
+	q, err := toDocument(query)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := []bson.M{}
+	for _, doc := range c.docs {
+		ok, err := matchDocument(doc, q)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			docs = append(docs, doc)
+		}
+	}
+
+	sortDocuments(docs, sort)
+	return docs, nil
+}
+
+// insert adds a document, with a new ID if it does not have one
+func (c *memoryCollection) insert(doc bson.M) error {
+	if _, ok := doc["_id"]; !ok {
+		doc["_id"] = primitive.NewObjectID()
+	}
+
+	err := c.checkUnique(doc, nil)
+	if err != nil {
+		return err
+	}
+
+	c.docs = append(c.docs, doc)
+	c.publish("insert", doc, true)
+	return nil
+}
+
+// update updates the first document matching a query, or all of them if multi is set. With
+// upsert, a document made of the equality conditions of the query and the update is inserted
+// if no document matches
+func (c *memoryCollection) update(query interface{}, update interface{}, multi, upsert bool) (*ChangeInfo, error) {
+	u, err := toDocument(update)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := c.find(query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ChangeInfo{}
+	if len(docs) == 0 {
+		if !upsert {
+			return info, nil
+		}
+
+		q, err := toDocument(query)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, err := applyUpdate(equalityFields(q), u, true)
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.insert(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		info.UpsertedID = doc["_id"]
+		return info, nil
+	}
+
+	if !multi {
+		docs = docs[:1]
+	}
+
+	for _, old := range docs {
+		doc, err := applyUpdate(old, u, false)
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.checkUnique(doc, old)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range c.docs {
+			if sameDocument(c.docs[i], old) {
+				c.docs[i] = doc
+				break
+			}
+		}
+
+		info.Matched++
+		info.Updated++
+
+		if hasOperators(u) {
+			c.publish("update", doc, false)
+		} else {
+			c.publish("replace", doc, true)
+		}
+	}
+
+	return info, nil
+}
+
+// remove removes documents of the collection
+func (c *memoryCollection) remove(docs []bson.M) {
+	kept := c.docs[:0]
+	for _, doc := range c.docs {
+		removed := false
+		for _, d := range docs {
+			if sameDocument(doc, d) {
+				removed = true
+				break
+			}
+		}
+
+		if !removed {
+			kept = append(kept, doc)
+		}
+	}
+
+	c.docs = kept
+}
+
+// checkUnique returns a duplicate key error if a document has the same ID or the same keys
+// of a unique index as another document than old
+func (c *memoryCollection) checkUnique(doc bson.M, old bson.M) error {
+	indexes := append([]Index{{Key: []string{"_id"}, Unique: true}}, c.indexes...)
+
+	for _, index := range indexes {
+		if !index.Unique {
+			continue
+		}
+
+		for _, other := range c.docs {
+			if old != nil && sameDocument(other, old) {
+				continue
+			}
+
+			if sameKeys(doc, other, index) {
+				return &DuplicateKeyError{Message: "E11000 duplicate key error"}
+			}
+		}
+	}
+
+	return nil
+}
+
+// expire removes the documents whose TTL index date is older than its ExpireAfter
+func (c *memoryCollection) expire(now time.Time) {
+	for _, index := range c.indexes {
+		if index.ExpireAfter <= 0 || len(index.Key) != 1 {
+			continue
+		}
+
+		expired := []bson.M{}
+		for _, doc := range c.docs {
+			v, _ := lookup(doc, strings.TrimLeft(index.Key[0], "+-"))
+			if t, ok := v.(primitive.DateTime); ok && t.Time().Add(index.ExpireAfter).Before(now) {
+				expired = append(expired, doc)
+			}
+		}
+
+		if len(expired) > 0 {
+			c.remove(expired)
+		}
+	}
+}
+
+// publish adds a change event to the change log and sends it to the change streams of the
+// collection
+func (c *memoryCollection) publish(operationType string, doc bson.M, full bool) {
+	ev := &memoryEvent{
+		id:            primitive.NewObjectID().Hex(),
+		time:          time.Now(),
+		operationType: operationType,
+		doc:           doc,
+		full:          full,
+	}
+
+	c.log = append(c.log, ev)
+	if len(c.log) > memoryLogSize {
+		c.log = c.log[len(c.log)-memoryLogSize:]
+	}
+
+	for _, stream := range c.streams {
+		stream.push(ev)
+	}
+}
+
+// memoryStream is a change stream of a MemoryStore collection
+type memoryStream struct {
+	mu           sync.Mutex
+	events       []*memoryEvent
+	notify       chan struct{}
+	closed       bool
+	err          error
+	maxAwait     time.Duration
+	fullDocument bool
+	ns           bson.M
+	token        string
+	close        func()
+}
+
+func (ms *memoryStream) push(ev *memoryEvent) {
+	ms.mu.Lock()
+	ms.events = append(ms.events, ev)
+	ms.mu.Unlock()
+
+	select {
+	case ms.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Next implements interfaces.ChangeStream. It returns false with no error if there is no
+// event before the max await time
+func (ms *memoryStream) Next(result interface{}) bool {
+	timeout := time.After(ms.maxAwait)
+
+	for {
+		ms.mu.Lock()
+		if ms.closed {
+			ms.mu.Unlock()
+			return false
+		}
+
+		if len(ms.events) > 0 {
+			ev := ms.events[0]
+			ms.events = ms.events[1:]
+			ms.token = ev.id
+			ms.mu.Unlock()
+
+			err := fromDocument(ms.document(ev), result)
+			if err != nil {
+				ms.mu.Lock()
+				ms.err = err
+				ms.mu.Unlock()
+				return false
+			}
+
+			return true
+		}
+		ms.mu.Unlock()
+
+		select {
+		case <-ms.notify:
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+// document returns the change event document of an event, as it is sent by MongoDB
+func (ms *memoryStream) document(ev *memoryEvent) bson.M {
+	doc := bson.M{
+		"_id":           bson.M{"_data": ev.id},
+		"clusterTime":   primitive.Timestamp{T: uint32(ev.time.Unix())},
+		"operationType": ev.operationType,
+		"ns":            ms.ns,
+		"documentKey":   bson.M{"_id": ev.doc["_id"]},
+	}
+
+	if ev.full || ms.fullDocument {
+		doc["fullDocument"] = ev.doc
+	}
+
+	return doc
+}
+
+// ResumeToken implements interfaces.ChangeStream
+func (ms *memoryStream) ResumeToken() []byte {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if ms.token == "" {
+		return nil
+	}
+
+	token, err := bson.Marshal(bson.M{"_data": ms.token})
+	if err != nil {
+		return nil
+	}
+
+	return token
+}
+
+// Err implements interfaces.ChangeStream
+func (ms *memoryStream) Err() error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return ms.err
+}
+
+// Close implements interfaces.ChangeStream
+func (ms *memoryStream) Close() error {
+	ms.mu.Lock()
+	if ms.closed {
+		ms.mu.Unlock()
+		return nil
+	}
+
+	ms.closed = true
+	ms.err = errStreamClosed
+	ms.mu.Unlock()
+
+	ms.close()
+	return nil
+}
+
+// toDocument encodes a value to BSON and decodes it back to a document, so that documents,
+// queries and updates hold the values the way MongoDB gets them
+func toDocument(v interface{}) (bson.M, error) {
+	if v == nil {
+		return bson.M{}, nil
+	}
+
+	data, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := bson.M{}
+	err = bson.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// fromDocument decodes a document into a response
+func fromDocument(doc bson.M, response interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	return bson.Unmarshal(data, response)
+}
+
+// fromDocuments decodes documents into a response which is a pointer to a slice
+func fromDocuments(docs []bson.M, response interface{}) error {
+	rv := reflect.ValueOf(response)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("Response must be a pointer to a slice")
+	}
+
+	slice := rv.Elem().Slice(0, 0)
+	for _, doc := range docs {
+		elem := reflect.New(slice.Type().Elem())
+
+		err := fromDocument(doc, elem.Interface())
+		if err != nil {
+			return err
+		}
+
+		slice = reflect.Append(slice, elem.Elem())
+	}
+
+	rv.Elem().Set(slice)
+	return nil
+}
+
+func page(docs []bson.M, offset, limit int) []bson.M {
+	if offset > len(docs) {
+		offset = len(docs)
+	}
+
+	docs = docs[offset:]
+	if limit > 0 && limit < len(docs) {
+		docs = docs[:limit]
+	}
+
+	return docs
+}
+
+// sortDocuments sorts documents by fields, which are prefixed with - for the descending order
+func sortDocuments(docs []bson.M, fields []string) {
+	if len(fields) == 0 {
+		return
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, f := range fields {
+			desc := strings.HasPrefix(f, "-")
+			f = strings.TrimLeft(f, "+-")
+
+			a, _ := lookup(docs[i], f)
+			b, _ := lookup(docs[j], f)
+
+			c := compareValues(a, b)
+			if c == 0 {
+				continue
+			}
+
+			return (c < 0) != desc
+		}
+
+		return false
+	})
+}
+
+// lookup returns the value of a field, which can be a dotted path in the embedded documents
+func lookup(doc bson.M, path string) (interface{}, bool) {
+	var v interface{} = doc
+
+	for _, part := range strings.Split(path, ".") {
+		switch value := v.(type) {
+		case bson.M:
+			var ok bool
+			v, ok = value[part]
+			if !ok {
+				return nil, false
+			}
+		case primitive.A:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(value) {
+				return nil, false
+			}
+			v = value[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// setPath sets a field, creating the embedded documents of a dotted path
+func setPath(doc bson.M, path string, v interface{}) {
+	parts := strings.Split(path, ".")
+
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := doc[part].(bson.M)
+		if !ok {
+			next = bson.M{}
+			doc[part] = next
+		}
+
+		doc = next
+	}
+
+	doc[parts[len(parts)-1]] = v
+}
+
+func unsetPath(doc bson.M, path string) {
+	parts := strings.Split(path, ".")
+
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := doc[part].(bson.M)
+		if !ok {
+			return
+		}
+
+		doc = next
+	}
+
+	delete(doc, parts[len(parts)-1])
+}
+
+// matchDocument checks that a document matches a query
+func matchDocument(doc bson.M, query bson.M) (bool, error) {
+	for k, cond := range query {
+		switch k {
+		case "$or", "$and", "$nor":
+			clauses, ok := cond.(primitive.A)
+			if !ok {
+				return false, errors.New(k + " requires an array")
+			}
+
+			matched := 0
+			for _, clause := range clauses {
+				q, ok := clause.(bson.M)
+				if !ok {
+					return false, errors.New(k + " requires an array of documents")
+				}
+
+				ok, err := matchDocument(doc, q)
+				if err != nil {
+					return false, err
+				}
+
+				if ok {
+					matched++
+				}
+			}
+
+			if (k == "$or" && matched == 0) || (k == "$and" && matched < len(clauses)) || (k == "$nor" && matched > 0) {
+				return false, nil
+			}
+		default:
+			v, exists := lookup(doc, k)
+
+			ok, err := matchField(v, exists, cond)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// matchField checks that the value of a field matches a condition, which is a value or a
+// document of operators
+func matchField(v interface{}, exists bool, cond interface{}) (bool, error) {
+	ops, ok := cond.(bson.M)
+	if !ok || !hasOperators(ops) {
+		return matchValue(v, cond), nil
+	}
+
+	for op, arg := range ops {
+		var ok bool
+
+		switch op {
+		case "$eq":
+			ok = matchValue(v, arg)
+		case "$ne":
+			ok = !matchValue(v, arg)
+		case "$in", "$nin":
+			values, isArray := arg.(primitive.A)
+			if !isArray {
+				return false, errors.New(op + " requires an array")
+			}
+
+			for _, a := range values {
+				if matchValue(v, a) {
+					ok = true
+					break
+				}
+			}
+
+			if op == "$nin" {
+				ok = !ok
+			}
+		case "$gt", "$gte", "$lt", "$lte":
+			if !exists || typeOrder(v) != typeOrder(arg) {
+				return false, nil
+			}
+
+			c := compareValues(v, arg)
+			ok = (op == "$gt" && c > 0) || (op == "$gte" && c >= 0) || (op == "$lt" && c < 0) || (op == "$lte" && c <= 0)
+		case "$exists":
+			ok = exists == truthy(arg)
+		case "$regex":
+			options, _ := ops["$options"].(string)
+			pattern, _ := arg.(string)
+			ok = matchValue(v, primitive.Regex{Pattern: pattern, Options: options})
+		case "$options":
+			ok = true
+		default:
+			return false, errors.New("Query operator " + op + " is not supported by the memory store")
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// matchValue checks that a value is equal to another or matches a regular expression. An
+// array matches if one of its elements does
+func matchValue(v interface{}, cond interface{}) bool {
+	if re, ok := cond.(primitive.Regex); ok {
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+
+		pattern := re.Pattern
+		if strings.Contains(re.Options, "i") {
+			pattern = "(?i)" + pattern
+		}
+
+		matched, err := regexp.MatchString(pattern, s)
+		return err == nil && matched
+	}
+
+	if values, ok := v.(primitive.A); ok {
+		if _, ok := cond.(primitive.A); !ok {
+			for _, value := range values {
+				if equalValues(value, cond) {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
+
+	return equalValues(v, cond)
+}
+
+func equalValues(a, b interface{}) bool {
+	if typeOrder(a) != typeOrder(b) {
+		return false
+	}
+
+	switch a.(type) {
+	case bson.M, primitive.A:
+		return reflect.DeepEqual(a, b)
+	}
+
+	return compareValues(a, b) == 0
+}
+
+// typeOrder returns the rank of the type of a value in the sort order of MongoDB
+func typeOrder(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 1
+	case int, int32, int64, float64:
+		return 2
+	case string:
+		return 3
+	case bson.M:
+		return 4
+	case primitive.A:
+		return 5
+	case primitive.Binary:
+		return 6
+	case primitive.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case primitive.DateTime:
+		return 9
+	default:
+		return 10
+	}
+}
+
+// compareValues returns -1, 0 or 1 whether a is before, equal to or after b in the sort
+// order of MongoDB
+func compareValues(a, b interface{}) int {
+	ta, tb := typeOrder(a), typeOrder(b)
+	if ta != tb {
+		return compareInts(ta, tb)
+	}
+
+	switch x := a.(type) {
+	case int, int32, int64, float64:
+		fa, fb := toFloat(x), toFloat(b)
+		if fa < fb {
+			return -1
+		}
+
+		if fa > fb {
+			return 1
+		}
+
+		return 0
+	case string:
+		return strings.Compare(x, b.(string))
+	case primitive.ObjectID:
+		y := b.(primitive.ObjectID)
+		return bytes.Compare(x[:], y[:])
+	case bool:
+		if x == b.(bool) {
+			return 0
+		}
+
+		if !x {
+			return -1
+		}
+
+		return 1
+	case primitive.DateTime:
+		return compareInts(int(x), int(b.(primitive.DateTime)))
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return 0
+	}
+
+	return -1
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	}
+
+	if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+
+	return 0
+}
+
+func toInt(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	}
+
+	return 0
+}
+
+func truthy(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case nil:
+		return false
+	}
+
+	return toFloat(v) != 0
+}
+
+// hasOperators checks that a document is made of operators, as an update or a condition
+func hasOperators(doc bson.M) bool {
+	if len(doc) == 0 {
+		return false
+	}
+
+	for k := range doc {
+		if !strings.HasPrefix(k, "$") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalityFields returns the fields of a query which are set to a value, which are the
+// fields of a document inserted by an upsert
+func equalityFields(query bson.M) bson.M {
+	doc := bson.M{}
+	for k, v := range query {
+		if strings.HasPrefix(k, "$") {
+			continue
+		}
+
+		if cond, ok := v.(bson.M); ok && hasOperators(cond) {
+			if eq, ok := cond["$eq"]; ok {
+				setPath(doc, k, eq)
+			}
+			continue
+		}
+
+		if _, ok := v.(primitive.Regex); ok {
+			continue
+		}
+
+		setPath(doc, k, v)
+	}
+
+	return doc
+}
+
+// applyUpdate returns a document updated with update operators or replaced by a document.
+// $setOnInsert is only applied when the document is inserted by an upsert
+func applyUpdate(old bson.M, update bson.M, insert bool) (bson.M, error) {
+	doc, err := toDocument(old)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasOperators(update) {
+		replaced, err := toDocument(update)
+		if err != nil {
+			return nil, err
+		}
+
+		if id, ok := doc["_id"]; ok {
+			replaced["_id"] = id
+		}
+
+		return replaced, nil
+	}
+
+	for op, arg := range update {
+		fields, ok := arg.(bson.M)
+		if !ok {
+			return nil, errors.New(op + " requires a document")
+		}
+
+		for path, v := range fields {
+			switch op {
+			case "$set":
+				setPath(doc, path, v)
+			case "$setOnInsert":
+				if insert {
+					setPath(doc, path, v)
+				}
+			case "$unset":
+				unsetPath(doc, path)
+			case "$inc":
+				current, _ := lookup(doc, path)
+				setPath(doc, path, addNumbers(current, v))
+			case "$addToSet", "$push":
+				current, _ := lookup(doc, path)
+				values, _ := current.(primitive.A)
+
+				added := primitive.A{v}
+				if each, ok := v.(bson.M); ok && each["$each"] != nil {
+					added, _ = each["$each"].(primitive.A)
+				}
+
+				for _, a := range added {
+					if op == "$addToSet" && matchValue(values, a) {
+						continue
+					}
+
+					values = append(values, a)
+				}
+
+				setPath(doc, path, values)
+			default:
+				return nil, errors.New("Update operator " + op + " is not supported by the memory store")
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// addNumbers adds two numbers, keeping an integer if both are integers
+func addNumbers(a, b interface{}) interface{} {
+	_, af := a.(float64)
+	_, bf := b.(float64)
+	if af || bf {
+		return toFloat(a) + toFloat(b)
+	}
+
+	return toInt(a) + toInt(b)
+}
+
+// sameDocument checks that two documents are the same stored document
+func sameDocument(a, b bson.M) bool {
+	return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+}
+
+// sameKeys checks that two documents have the same keys of an index. The sparse indexes
+// skip the documents which have none of the keys
+func sameKeys(a, b bson.M, index Index) bool {
+	present := false
+
+	for _, k := range index.Key {
+		k = strings.TrimLeft(k, "+-")
+
+		va, ok := lookup(a, k)
+		present = present || ok
+
+		vb, _ := lookup(b, k)
+		if !equalValues(va, vb) {
+			return false
+		}
+	}
+
+	return present || !index.Sparse
+}
@@ -0,0 +1,479 @@
+package daos
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMemoryStoreOrders(t *testing.T) {
+	dao := NewOrderDao(NewMemoryStore())
+
+	user := common.HexToAddress("0x1")
+	baseToken := common.HexToAddress("0x3")
+	quoteToken := common.HexToAddress("0x4")
+
+	o1 := &types.Order{
+		UserAddress:  user,
+		BaseToken:    baseToken,
+		QuoteToken:   quoteToken,
+		Amount:       big.NewInt(10),
+		FilledAmount: big.NewInt(0),
+		PricePoint:   big.NewInt(100),
+		Side:         types.BUY,
+		PairName:     "ZRX/WETH",
+		Nonce:        big.NewInt(1),
+		Hash:         common.HexToHash("0x5"),
+	}
+
+	o2 := *o1
+	o2.Hash = common.HexToHash("0x6")
+	o2.Nonce = big.NewInt(2)
+	o2.ClientOrderID = "bot-1"
+
+	err := dao.Create(o1)
+	assert.Nil(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+	err = dao.Create(&o2)
+	assert.Nil(t, err)
+
+	err = dao.UpdateOrderFilledAmount(o1.Hash, big.NewInt(4))
+	assert.Nil(t, err)
+
+	stored, err := dao.GetByHash(o1.Hash)
+	assert.Nil(t, err)
+	assert.Equal(t, types.OrderStatusPartialFilled, stored.Status)
+	assert.Equal(t, big.NewInt(4), stored.FilledAmount)
+
+	missing, err := dao.GetByHash(common.HexToHash("0x7"))
+	assert.Nil(t, err)
+	assert.Nil(t, missing)
+
+	res, err := dao.GetOrders(types.OrderSpec{UserAddress: user.Hex()}, []string{"-createdAt"}, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, res.Total)
+	assert.Equal(t, o2.Hash, res.Orders[0].Hash)
+	assert.Equal(t, o1.Hash, res.Orders[1].Hash)
+
+	res, err = dao.GetOrders(types.OrderSpec{UserAddress: user.Hex(), ClientOrderID: "bot-1"}, nil, 0, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, res.Total)
+	assert.Equal(t, o2.Hash, res.Orders[0].Hash)
+	assert.Equal(t, "bot-1", res.Orders[0].ClientOrderID)
+
+	p := &types.Pair{BaseTokenAddress: baseToken, QuoteTokenAddress: quoteToken}
+	bids, err := dao.GetSideOrderBook(p, types.BUY, -1)
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]string{{"pricepoint": "100", "amount": "16"}}, bids)
+}
+
+func TestMemoryStoreAccounts(t *testing.T) {
+	dao := NewAccountDao(NewMemoryStore())
+
+	owner := common.HexToAddress("0x1")
+	token := common.HexToAddress("0x2")
+
+	created, err := dao.FindOrCreate(owner)
+	assert.Nil(t, err)
+	assert.Equal(t, owner, created.Address)
+
+	found, err := dao.FindOrCreate(owner)
+	assert.Nil(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	err = dao.AddFavoriteToken(owner, token)
+	assert.Nil(t, err)
+
+	favorites, err := dao.GetFavoriteTokens(owner)
+	assert.Nil(t, err)
+	assert.Equal(t, map[common.Address]bool{token: true}, favorites)
+
+	err = dao.AddFavoriteToken(common.HexToAddress("0x3"), token)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestMemoryStoreUniqueAndTTLIndexes(t *testing.T) {
+	dao := NewRequestNonceDao(NewMemoryStore())
+	addr := common.HexToAddress("0x1")
+
+	ok, err := dao.Use(addr, "1", time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = dao.Use(addr, "1", time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	ok, err = dao.Use(common.HexToAddress("0x2"), "1", time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// the nonce is removed once it expires, like with the TTL index of MongoDB
+	ok, err = dao.Use(addr, "2", time.Now().Add(-time.Minute))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = dao.Use(addr, "2", time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestMemoryStoreWatch(t *testing.T) {
+	dao := NewOrderDao(NewMemoryStore())
+
+	ct, err := dao.Watch(nil)
+	assert.Nil(t, err)
+	defer ct.Close()
+
+	o := &types.Order{
+		Amount:       big.NewInt(10),
+		FilledAmount: big.NewInt(0),
+		PricePoint:   big.NewInt(100),
+		Nonce:        big.NewInt(1),
+		Hash:         common.HexToHash("0x5"),
+	}
+
+	err = dao.Create(o)
+	assert.Nil(t, err)
+
+	err = dao.UpdateOrderStatus(o.Hash, types.OrderStatusCancelled)
+	assert.Nil(t, err)
+
+	ev := types.OrderChangeEvent{}
+	assert.True(t, ct.Next(&ev))
+	assert.Equal(t, "insert", ev.OperationType)
+	assert.Equal(t, types.OrderStatusOpen, ev.FullDocument.Status)
+
+	ev = types.OrderChangeEvent{}
+	assert.True(t, ct.Next(&ev))
+	assert.Equal(t, "update", ev.OperationType)
+	assert.Equal(t, types.OrderStatusCancelled, ev.FullDocument.Status)
+
+	// there is no more event before the max await time
+	assert.False(t, ct.Next(&ev))
+	assert.Nil(t, ct.Err())
+}
+
+func TestMemoryStoreResumeWatch(t *testing.T) {
+	store := NewMemoryStore()
+	dao := NewOrderDao(store)
+
+	ct, err := dao.Watch(nil)
+	assert.Nil(t, err)
+
+	o := &types.Order{
+		Amount:       big.NewInt(10),
+		FilledAmount: big.NewInt(0),
+		PricePoint:   big.NewInt(100),
+		Nonce:        big.NewInt(1),
+		Hash:         common.HexToHash("0x5"),
+	}
+
+	err = dao.Create(o)
+	assert.Nil(t, err)
+
+	ev := types.OrderChangeEvent{}
+	assert.True(t, ct.Next(&ev))
+
+	token := ct.ResumeToken()
+	assert.NotNil(t, token)
+	ct.Close()
+
+	// the change made while the stream is closed is sent once it is resumed
+	err = dao.UpdateOrderStatus(o.Hash, types.OrderStatusCancelled)
+	assert.Nil(t, err)
+
+	ct, err = store.Watch(dao.dbName, dao.collectionName, WatchOptions{
+		FullDocument: true,
+		MaxAwaitTime: 10 * time.Millisecond,
+		ResumeAfter:  token,
+	})
+	assert.Nil(t, err)
+	defer ct.Close()
+
+	ev = types.OrderChangeEvent{}
+	assert.True(t, ct.Next(&ev))
+	assert.Equal(t, "update", ev.OperationType)
+	assert.Equal(t, types.OrderStatusCancelled, ev.FullDocument.Status)
+
+	_, err = store.Watch(dao.dbName, dao.collectionName, WatchOptions{ResumeAfter: []byte{5, 0, 0, 0, 0}})
+	assert.Equal(t, ErrResumeTokenNotFound, err)
+}
+
+func TestMemoryStoreLease(t *testing.T) {
+	dao := NewConfigDao(NewMemoryStore())
+
+	ok, err := dao.AcquireLease("leader", "a", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// the lease is held by a until it expires or a releases it
+	ok, err = dao.AcquireLease("leader", "b", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	ok, err = dao.AcquireLease("leader", "a", 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	time.Sleep(20 * time.Millisecond)
+
+	ok, err = dao.AcquireLease("leader", "b", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	err = dao.ReleaseLease("leader", "a")
+	assert.Nil(t, err)
+
+	ok, err = dao.AcquireLease("leader", "a", time.Minute)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	err = dao.ReleaseLease("leader", "b")
+	assert.Nil(t, err)
+
+	ok, err = dao.AcquireLease("leader", "a", time.Minute)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestMemoryStoreClientOrders(t *testing.T) {
+	dao := NewClientOrderDao(NewMemoryStore())
+
+	user := common.HexToAddress("0x1")
+	o := &types.Order{UserAddress: user, ClientOrderID: "bot-1", Hash: common.HexToHash("0xa")}
+
+	c := types.NewClientOrder(o)
+	existing, err := dao.Reserve(c)
+	assert.Nil(t, err)
+	assert.Nil(t, existing)
+
+	// the ID is held by the first order until it is released
+	other := types.NewClientOrder(&types.Order{UserAddress: user, ClientOrderID: "bot-1", Hash: common.HexToHash("0xb")})
+	existing, err = dao.Reserve(other)
+	assert.Nil(t, err)
+	assert.Equal(t, o.Hash, existing.OrderHash)
+	assert.Equal(t, types.ClientOrderStatusPending, existing.Status)
+
+	// another user can use the same ID
+	existing, err = dao.Reserve(types.NewClientOrder(&types.Order{UserAddress: common.HexToAddress("0x2"), ClientOrderID: "bot-1", Hash: common.HexToHash("0xb")}))
+	assert.Nil(t, err)
+	assert.Nil(t, existing)
+
+	err = dao.Accept(c)
+	assert.Nil(t, err)
+
+	existing, err = dao.GetByClientOrderID(user, "bot-1")
+	assert.Nil(t, err)
+	assert.Equal(t, types.ClientOrderStatusAccepted, existing.Status)
+
+	// an accepted client order is not released
+	err = dao.Release(c)
+	assert.Nil(t, err)
+
+	existing, err = dao.Reserve(other)
+	assert.Nil(t, err)
+	assert.Equal(t, o.Hash, existing.OrderHash)
+
+	// an expired client order frees its ID
+	expired := types.NewClientOrder(&types.Order{UserAddress: user, ClientOrderID: "bot-2", Hash: common.HexToHash("0xc")})
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	existing, err = dao.Reserve(expired)
+	assert.Nil(t, err)
+	assert.Nil(t, existing)
+
+	existing, err = dao.GetByClientOrderID(user, "bot-2")
+	assert.Nil(t, err)
+	assert.Nil(t, existing)
+
+	existing, err = dao.Reserve(types.NewClientOrder(&types.Order{UserAddress: user, ClientOrderID: "bot-2", Hash: common.HexToHash("0xd")}))
+	assert.Nil(t, err)
+	assert.Nil(t, existing)
+}
+
+func TestMemoryStoreWallets(t *testing.T) {
+	store := NewMemoryStore()
+	w := types.NewWallet()
+
+	err := NewWalletDao(store).Create(w)
+	assert.Equal(t, ErrNoWalletPassphrase, err)
+
+	dao := NewWalletDao(store, WalletDaoPassphraseOption("passphrase"))
+	err = dao.Create(w)
+	assert.Nil(t, err)
+
+	docs := []bson.M{}
+	err = store.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &docs)
+	assert.Nil(t, err)
+	assert.Len(t, docs, 1)
+	assert.NotContains(t, docs[0], "privateKey")
+	assert.NotEmpty(t, docs[0]["keystore"])
+
+	stored, err := dao.GetByAddress(w.Address)
+	assert.Nil(t, err)
+	assert.Equal(t, w.PrivateKey, stored.PrivateKey)
+
+	stored, err = NewWalletDao(store).GetByAddress(w.Address)
+	assert.Nil(t, err)
+	assert.Nil(t, stored.PrivateKey)
+
+	legacy := types.NewWallet()
+	err = store.Create(dao.dbName, dao.collectionName, bson.M{
+		"_id":        primitive.NewObjectID(),
+		"address":    legacy.Address.Hex(),
+		"privateKey": legacy.GetPrivateKey(),
+	})
+	assert.Nil(t, err)
+
+	n, err := dao.EncryptPlaintextKeys()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	count, err := store.Count(dao.dbName, dao.collectionName, bson.M{"privateKey": bson.M{"$exists": true}})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	stored, err = dao.GetByAddress(legacy.Address)
+	assert.Nil(t, err)
+	assert.Equal(t, legacy.PrivateKey, stored.PrivateKey)
+}
+
+func TestMemoryStoreAPIKeys(t *testing.T) {
+	store := NewMemoryStore()
+	user := common.HexToAddress("0x1")
+	secret, _ := types.NewAPIKeySecret()
+	k := &types.APIKey{Key: "key-1", Secret: secret, UserAddress: user}
+
+	err := NewAPIKeyDao(store).Create(k)
+	assert.Equal(t, ErrNoAPIKeyPassphrase, err)
+
+	dao := NewAPIKeyDao(store, APIKeyDaoPassphraseOption("passphrase"))
+	err = dao.Create(k)
+	assert.Nil(t, err)
+	assert.Equal(t, secret, k.Secret)
+
+	docs := []bson.M{}
+	err = store.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &docs)
+	assert.Nil(t, err)
+	assert.Nil(t, docs[0]["secret"])
+	assert.NotEmpty(t, docs[0]["keystore"])
+
+	stored, err := dao.GetByKey("key-1")
+	assert.Nil(t, err)
+	assert.Equal(t, secret, stored.Secret)
+
+	keys, err := dao.GetByUserAddress(user)
+	assert.Nil(t, err)
+	assert.Equal(t, "", keys[0].Secret)
+
+	legacy, _ := types.NewAPIKeySecret()
+	err = store.Create(dao.dbName, dao.collectionName, bson.M{
+		"_id":         primitive.NewObjectID(),
+		"key":         "key-2",
+		"secret":      legacy,
+		"userAddress": user.Hex(),
+	})
+	assert.Nil(t, err)
+
+	n, err := dao.EncryptPlaintextSecrets()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	count, err := store.Count(dao.dbName, dao.collectionName, bson.M{"secret": bson.M{"$exists": true}})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, count)
+
+	stored, err = dao.GetByKey("key-2")
+	assert.Nil(t, err)
+	assert.Equal(t, legacy, stored.Secret)
+}
+
+func TestMemoryStoreReplaceTicks(t *testing.T) {
+	dao := NewOHLCVDao(NewMemoryStore())
+
+	pair := types.PairID{
+		PairName:   "ZRX/WETH",
+		BaseToken:  common.HexToAddress("0x3"),
+		QuoteToken: common.HexToAddress("0x4"),
+	}
+
+	tick := func(ts int64, count int64) *types.Tick {
+		return &types.Tick{
+			Pair:      pair,
+			Duration:  1,
+			Unit:      "hour",
+			Timestamp: ts,
+			Open:      big.NewInt(1),
+			High:      big.NewInt(1),
+			Low:       big.NewInt(1),
+			Close:     big.NewInt(1),
+			Volume:    big.NewInt(1),
+			Count:     big.NewInt(count),
+		}
+	}
+
+	err := dao.UpsertTicks([]*types.Tick{tick(0, 1), tick(3600, 1), tick(7200, 1), tick(10800, 1)})
+	assert.Nil(t, err)
+
+	r := &types.TickRange{
+		Pair:     types.PairAddresses{Name: pair.PairName, BaseToken: pair.BaseToken, QuoteToken: pair.QuoteToken},
+		Duration: 1,
+		Unit:     "hour",
+		From:     3600,
+		To:       10800,
+	}
+
+	// the tick at 3600 has no trade anymore and the one at 7200 is rebuilt
+	err = dao.ReplaceTicks(r, []*types.Tick{tick(7200, 5)})
+	assert.Nil(t, err)
+
+	ticks, err := dao.GetTicksInRange(r)
+	assert.Nil(t, err)
+	assert.Len(t, ticks, 1)
+	assert.Equal(t, int64(7200), ticks[0].Timestamp)
+	assert.Equal(t, big.NewInt(5), ticks[0].Count)
+
+	// the ticks out of the range are kept
+	ticks, err = dao.GetTicks(0)
+	assert.Nil(t, err)
+	assert.Len(t, ticks, 3)
+}
+
+func TestMatchDocument(t *testing.T) {
+	doc := bson.M{
+		"status": "OPEN",
+		"amount": 10,
+		"tags":   primitive.A{"a", "b"},
+		"nested": bson.M{"side": "BUY"},
+	}
+
+	tests := []struct {
+		query bson.M
+		match bool
+	}{
+		{bson.M{"status": "OPEN"}, true},
+		{bson.M{"status": bson.M{"$in": primitive.A{"FILLED", "OPEN"}}}, true},
+		{bson.M{"status": bson.M{"$nin": primitive.A{"OPEN"}}}, false},
+		{bson.M{"amount": bson.M{"$gte": 10, "$lt": 11}}, true},
+		{bson.M{"amount": bson.M{"$gt": "1"}}, false},
+		{bson.M{"tags": "b"}, true},
+		{bson.M{"nested.side": "BUY"}, true},
+		{bson.M{"$or": primitive.A{bson.M{"status": "FILLED"}, bson.M{"amount": 10}}}, true},
+		{bson.M{"$nor": primitive.A{bson.M{"status": "OPEN"}}}, false},
+		{bson.M{"missing": bson.M{"$exists": false}}, true},
+		{bson.M{"status": primitive.Regex{Pattern: "^op", Options: "i"}}, true},
+	}
+
+	for _, test := range tests {
+		match, err := matchDocument(doc, test.query)
+		assert.Nil(t, err)
+		assert.Equal(t, test.match, match, "%v", test.query)
+	}
+}
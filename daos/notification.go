@@ -4,36 +4,37 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type NotificationDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
-func NewNotificationDao() *NotificationDao {
-	dao := &NotificationDao{}
+func NewNotificationDao(db Store) *NotificationDao {
+	dao := &NotificationDao{db: db}
 	dao.collectionName = "notifications"
 	dao.dbName = app.Config.DBName
 
-	i1 := mgo.Index{
+	i1 := Index{
 		Key: []string{"recipient"},
 	}
 
-	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i1)
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, i1)
 
-	i2 := mgo.Index{
+	i2 := Index{
 		Key:         []string{"createdAt"},
 		Background:  true,
 		ExpireAfter: time.Duration(30*24*60*60) * time.Second, // 30 days
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i2)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i2)
 
 	if err != nil {
 		logger.Warning("Index failed", err)
@@ -49,13 +50,13 @@ func (dao *NotificationDao) Create(notifications ...*types.Notification) ([]*typ
 	y := make([]interface{}, len(notifications))
 
 	for _, notification := range notifications {
-		notification.ID = bson.NewObjectId()
+		notification.ID = primitive.NewObjectID()
 		notification.CreatedAt = time.Now()
 		notification.UpdatedAt = time.Now()
 		y = append(y, notification)
 	}
 
-	err := db.Create(dao.dbName, dao.collectionName, y...)
+	err := dao.db.Create(dao.dbName, dao.collectionName, y...)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -68,7 +69,7 @@ func (dao *NotificationDao) Create(notifications ...*types.Notification) ([]*typ
 func (dao *NotificationDao) GetAll() ([]types.Notification, error) {
 	var response []types.Notification
 
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &response)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &response)
 
 	if err != nil {
 		logger.Error(err)
@@ -88,7 +89,7 @@ func (dao *NotificationDao) GetByUserAddress(addr common.Address, limit int, off
 	var res []*types.Notification
 	q := bson.M{"recipient": addr.Hex()}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, offset, limit, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, offset, limit, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -111,7 +112,7 @@ func (dao *NotificationDao) GetSortDecByUserAddress(addr common.Address, limit i
 	var res []*types.Notification
 	q := bson.M{"recipient": addr.Hex()}
 	sort := []string{"-createdAt"}
-	err := db.GetAndSort(dao.dbName, dao.collectionName, q, sort, offset, limit, &res)
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, sort, offset, limit, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -125,10 +126,10 @@ func (dao *NotificationDao) GetSortDecByUserAddress(addr common.Address, limit i
 }
 
 // GetByID function fetches details of a notification based on its mongo id
-func (dao *NotificationDao) GetByID(id bson.ObjectId) (*types.Notification, error) {
+func (dao *NotificationDao) GetByID(id primitive.ObjectID) (*types.Notification, error) {
 	var response *types.Notification
 
-	err := db.GetByID(dao.dbName, dao.collectionName, id, &response)
+	err := dao.db.GetByID(dao.dbName, dao.collectionName, id, &response)
 
 	if err != nil {
 		logger.Error(err)
@@ -138,18 +139,18 @@ func (dao *NotificationDao) GetByID(id bson.ObjectId) (*types.Notification, erro
 	return response, nil
 }
 
-func (dao *NotificationDao) FindAndModify(id bson.ObjectId, n *types.Notification) (*types.Notification, error) {
+func (dao *NotificationDao) FindAndModify(id primitive.ObjectID, n *types.Notification) (*types.Notification, error) {
 	n.UpdatedAt = time.Now()
 	query := bson.M{"_id": id}
 	updated := &types.Notification{}
-	change := mgo.Change{
+	change := Change{
 		Update:    types.NotificationBSONUpdate{Notification: n},
 		Upsert:    true,
 		Remove:    false,
 		ReturnNew: true,
 	}
 
-	err := db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
+	err := dao.db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
 
 	if err != nil {
 		logger.Error(err)
@@ -161,7 +162,7 @@ func (dao *NotificationDao) FindAndModify(id bson.ObjectId, n *types.Notificatio
 
 func (dao *NotificationDao) Update(n *types.Notification) error {
 	n.UpdatedAt = time.Now()
-	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": n.ID}, n)
+	err := dao.db.Update(dao.dbName, dao.collectionName, bson.M{"_id": n.ID}, n)
 
 	if err != nil {
 		logger.Error(err)
@@ -171,10 +172,10 @@ func (dao *NotificationDao) Update(n *types.Notification) error {
 	return nil
 }
 
-func (dao *NotificationDao) Upsert(id bson.ObjectId, n *types.Notification) error {
+func (dao *NotificationDao) Upsert(id primitive.ObjectID, n *types.Notification) error {
 	n.UpdatedAt = time.Now()
 
-	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"_id": id}, n)
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{"_id": id}, n)
 
 	if err != nil {
 		logger.Error(err)
@@ -185,12 +186,12 @@ func (dao *NotificationDao) Upsert(id bson.ObjectId, n *types.Notification) erro
 }
 
 func (dao *NotificationDao) Delete(notifications ...*types.Notification) error {
-	ids := make([]bson.ObjectId, 0)
+	ids := make([]primitive.ObjectID, 0)
 	for _, n := range notifications {
 		ids = append(ids, n.ID)
 	}
 
-	err := db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"_id": bson.M{"$in": ids}})
+	err := dao.db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"_id": bson.M{"$in": ids}})
 
 	if err != nil {
 		logger.Error(err)
@@ -200,8 +201,8 @@ func (dao *NotificationDao) Delete(notifications ...*types.Notification) error {
 	return nil
 }
 
-func (dao *NotificationDao) DeleteByIds(ids ...bson.ObjectId) error {
-	err := db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"_id": bson.M{"$in": ids}})
+func (dao *NotificationDao) DeleteByIds(ids ...primitive.ObjectID) error {
+	err := dao.db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"_id": bson.M{"$in": ids}})
 
 	if err != nil {
 		logger.Error(err)
@@ -215,7 +216,7 @@ func (dao *NotificationDao) DeleteByIds(ids ...bson.ObjectId) error {
 func (dao *NotificationDao) Aggregate(q []bson.M) ([]*types.Notification, error) {
 	var res []*types.Notification
 
-	err := db.Aggregate(dao.dbName, dao.collectionName, q, &res)
+	err := dao.db.Aggregate(dao.dbName, dao.collectionName, q, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -226,11 +227,11 @@ func (dao *NotificationDao) Aggregate(q []bson.M) ([]*types.Notification, error)
 
 // Drop drops all the order documents in the current database
 func (dao *NotificationDao) Drop() {
-	db.DropCollection(dao.dbName, dao.collectionName)
+	dao.db.DropCollection(dao.dbName, dao.collectionName)
 }
 
 // MarkStatus update UNREAD status to READ status
-func (dao *NotificationDao) MarkStatus(id bson.ObjectId, status string) error {
+func (dao *NotificationDao) MarkStatus(id primitive.ObjectID, status string) error {
 	query := bson.M{"_id": id}
 	updated := &types.Notification{}
 	changeData := bson.M{}
@@ -238,14 +239,14 @@ func (dao *NotificationDao) MarkStatus(id bson.ObjectId, status string) error {
 	changeData["updatedAt"] = time.Now()
 	changeDataSet := bson.M{"$set": changeData}
 
-	change := mgo.Change{
+	change := Change{
 		Update:    changeDataSet,
 		Upsert:    false,
 		Remove:    false,
 		ReturnNew: true,
 	}
 
-	err := db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
+	err := dao.db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -255,12 +256,12 @@ func (dao *NotificationDao) MarkStatus(id bson.ObjectId, status string) error {
 }
 
 // MarkRead update UNREAD status to READ status
-func (dao *NotificationDao) MarkRead(id bson.ObjectId) error {
+func (dao *NotificationDao) MarkRead(id primitive.ObjectID) error {
 	return dao.MarkStatus(id, types.StatusRead)
 }
 
 // MarkUnRead update READ status to UNREAD status
-func (dao *NotificationDao) MarkUnRead(id bson.ObjectId) error {
+func (dao *NotificationDao) MarkUnRead(id primitive.ObjectID) error {
 	return dao.MarkStatus(id, types.StatusUnread)
 }
 
@@ -271,7 +272,7 @@ func (dao *NotificationDao) MarkAllRead(addr common.Address) error {
 	changeData["status"] = types.StatusRead
 	changeData["updatedAt"] = time.Now()
 	changeDataSet := bson.M{"$set": changeData}
-	changeInfo, err := db.ChangeAll(dao.dbName, dao.collectionName, query, changeDataSet)
+	changeInfo, err := dao.db.ChangeAll(dao.dbName, dao.collectionName, query, changeDataSet)
 	if err != nil {
 		logger.Error(err)
 		return err
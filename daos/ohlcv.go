@@ -1,85 +1,242 @@
 package daos
 
 import (
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
+	"time"
+
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
-// OHLCVDao for ohlcv database struct
+// OHLCVDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
 type OHLCVDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
-// NewOHLCVDao for init ohlcv database struct
-func NewOHLCVDao() *OHLCVDao {
-	dbName := app.Config.DBName
-	collection := "ohlcv"
-	index := mgo.Index{
-		Key:    []string{"timestamp", "uint", "duration"},
+type OHLCVDaoOption = func(*OHLCVDao) error
+
+func OHLCVDaoDBOption(dbName string) func(dao *OHLCVDao) error {
+	return func(dao *OHLCVDao) error {
+		dao.dbName = dbName
+		return nil
+	}
+}
+
+// NewOHLCVDao returns a new instance of OHLCVDao
+func NewOHLCVDao(db Store, opts ...OHLCVDaoOption) *OHLCVDao {
+	dao := &OHLCVDao{db: db}
+	dao.collectionName = "ohlcv"
+	dao.dbName = app.Config.DBName
+
+	for _, op := range opts {
+		err := op(dao)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	err := dao.ensureIndexes()
+	if err != nil {
+		panic(err)
+	}
+
+	return dao
+}
+
+func (dao *OHLCVDao) ensureIndexes() error {
+	index := Index{
+		Key:    []string{"baseToken", "quoteToken", "duration", "unit", "timestamp"},
 		Unique: true,
 	}
-	db.Session.DB(dbName).C(collection).EnsureIndex(index)
-	return &OHLCVDao{collection, dbName}
+
+	i1 := Index{
+		Key: []string{"closeTime"},
+	}
+
+	i2 := Index{
+		Key: []string{"updatedAt"},
+	}
+
+	err := dao.db.EnsureIndex(dao.dbName, dao.collectionName, index)
+	if err != nil {
+		return err
+	}
+
+	err = dao.db.EnsureIndex(dao.dbName, dao.collectionName, i1)
+	if err != nil {
+		return err
+	}
+
+	return dao.db.EnsureIndex(dao.dbName, dao.collectionName, i2)
 }
 
-// Create function performs the DB insertion task for token collection
-func (dao *OHLCVDao) Create(tick *types.Tick) error {
-	err := db.Create(dao.dbName, dao.collectionName, tick)
+// UpsertTicks inserts the ticks, or replaces them if they are already stored
+func (dao *OHLCVDao) UpsertTicks(ticks []*types.Tick) error {
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	pairs := []interface{}{}
+	for _, t := range ticks {
+		query := bson.M{
+			"baseToken":  t.Pair.BaseToken.Hex(),
+			"quoteToken": t.Pair.QuoteToken.Hex(),
+			"duration":   t.Duration,
+			"unit":       t.Unit,
+			"timestamp":  t.Timestamp,
+		}
+
+		pairs = append(pairs, query, bson.M{"$set": types.NewTickRecord(t)})
+	}
+
+	err := dao.db.BulkUpsert(dao.dbName, dao.collectionName, pairs...)
 	if err != nil {
 		logger.Error(err)
 		return err
 	}
+
 	return nil
 }
 
-// GetAll function fetches all the tokens in the token collection of mongodb.
-func (dao *OHLCVDao) GetAll() ([]types.Tick, error) {
-	var response []types.Tick
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &response)
+// GetTicks returns the ticks whose timestamp is after from
+func (dao *OHLCVDao) GetTicks(from int64) ([]*types.Tick, error) {
+	var res []*types.TickRecord
+
+	q := bson.M{"timestamp": bson.M{"$gte": from}}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
 
-	return response, nil
+	ticks := []*types.Tick{}
+	for _, r := range res {
+		ticks = append(ticks, r.Tick())
+	}
+
+	return ticks, nil
 }
 
-// GetOhlcv get OHLCV data
-func (dao *OHLCVDao) GetOhlcv(pairs []types.PairAddresses, duration int64, unit string, start, end int64) ([]*types.Tick, error) {
-	res := make([]*types.Tick, 0)
-
-	match := bson.M{
-		"timestamp": bson.M{
-			"$gte": start,
-			"$lt":  end,
-		},
-	}
-
-	if len(pairs) >= 1 {
-		or := make([]bson.M, 0)
-
-		for _, pair := range pairs {
-			or = append(or, bson.M{
-				"$and": []bson.M{
-					{
-						"baseToken":  pair.BaseToken.Hex(),
-						"quoteToken": pair.QuoteToken.Hex(),
-					},
-				},
-			},
-			)
-		}
+// GetTicksUpdatedSince returns the ticks stored since a time
+func (dao *OHLCVDao) GetTicksUpdatedSince(since time.Time) ([]*types.Tick, error) {
+	var res []*types.TickRecord
+
+	q := bson.M{"updatedAt": bson.M{"$gte": since}}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ticks := []*types.Tick{}
+	for _, r := range res {
+		ticks = append(ticks, r.Tick())
+	}
+
+	return ticks, nil
+}
 
-		match["$or"] = or
+// GetLastTick returns the tick that includes the last trade, or nil if no tick is stored
+func (dao *OHLCVDao) GetLastTick() (*types.Tick, error) {
+	var res []*types.TickRecord
+
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, bson.M{}, []string{"-closeTime"}, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
 	}
-	sort := []string{"-timestamp"}
-	err := db.GetAndSort(dao.dbName, dao.collectionName, match, sort, 0, 0, &res)
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return res[0].Tick(), nil
+}
+
+// DeleteTicks removes the ticks of a duration and unit whose timestamp is before to
+func (dao *OHLCVDao) DeleteTicks(duration int64, unit string, to int64) error {
+	q := bson.M{
+		"duration":  duration,
+		"unit":      unit,
+		"timestamp": bson.M{"$lt": to},
+	}
+
+	err := dao.db.RemoveAll(dao.dbName, dao.collectionName, q)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetTicksInRange returns the stored ticks of a range
+func (dao *OHLCVDao) GetTicksInRange(r *types.TickRange) ([]*types.Tick, error) {
+	var res []*types.TickRecord
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, tickRangeQuery(r), 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
-	return res, nil
+
+	ticks := []*types.Tick{}
+	for _, r := range res {
+		ticks = append(ticks, r.Tick())
+	}
+
+	return ticks, nil
+}
+
+// ReplaceTicks replaces the stored ticks of a range with the ticks rebuilt for it. The rebuilt
+// ticks are upserted, then the stored ticks of the range that were not rebuilt are removed, so
+// that the ticks out of the range, which the servers may store meanwhile, are left untouched
+func (dao *OHLCVDao) ReplaceTicks(r *types.TickRange, ticks []*types.Tick) error {
+	err := dao.UpsertTicks(ticks)
+	if err != nil {
+		return err
+	}
+
+	timestamps := []int64{}
+	for _, t := range ticks {
+		timestamps = append(timestamps, t.Timestamp)
+	}
+
+	q := tickRangeQuery(r)
+	q["timestamp"] = bson.M{"$gte": r.From, "$lt": r.To, "$nin": timestamps}
+
+	err = dao.db.RemoveAll(dao.dbName, dao.collectionName, q)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func tickRangeQuery(r *types.TickRange) bson.M {
+	return bson.M{
+		"baseToken":  r.Pair.BaseToken.Hex(),
+		"quoteToken": r.Pair.QuoteToken.Hex(),
+		"duration":   r.Duration,
+		"unit":       r.Unit,
+		"timestamp":  bson.M{"$gte": r.From, "$lt": r.To},
+	}
+}
+
+// Drop drops all the ticks in the current database
+func (dao *OHLCVDao) Drop() error {
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
 }
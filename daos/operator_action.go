@@ -0,0 +1,119 @@
+package daos
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OperatorActionDao stores the audit log of the orders and order cancels signed with the
+// operator wallets
+type OperatorActionDao struct {
+	collectionName string
+	dbName         string
+	db             Store
+}
+
+// NewOperatorActionDao returns a new instance of OperatorActionDao
+func NewOperatorActionDao(db Store) *OperatorActionDao {
+	dao := &OperatorActionDao{db: db}
+	dao.collectionName = "operator_actions"
+	dao.dbName = app.Config.DBName
+
+	index := Index{
+		Key: []string{"operator", "createdAt"},
+	}
+
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
+	if err != nil {
+		panic(err)
+	}
+
+	i1 := Index{
+		Key: []string{"walletAddress", "createdAt"},
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i1)
+	if err != nil {
+		panic(err)
+	}
+
+	return dao
+}
+
+// Create stores an operator action
+func (dao *OperatorActionDao) Create(a *types.OperatorAction) error {
+	err := dao.db.Create(dao.dbName, dao.collectionName, a)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// SetError records the reason an operator action was rejected
+func (dao *OperatorActionDao) SetError(id primitive.ObjectID, msg string) error {
+	err := dao.db.Update(dao.dbName, dao.collectionName, bson.M{"_id": id}, bson.M{"$set": bson.M{"error": msg}})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetOperatorActions returns a page of the operator actions matching spec, most recent first
+func (dao *OperatorActionDao) GetOperatorActions(spec types.OperatorActionSpec, offset int, size int) (*types.OperatorActionRes, error) {
+	q := bson.M{}
+
+	if spec.Operator != "" {
+		q["operator"] = common.HexToAddress(spec.Operator).Hex()
+	}
+
+	if spec.WalletAddress != "" {
+		q["walletAddress"] = common.HexToAddress(spec.WalletAddress).Hex()
+	}
+
+	if spec.Action != "" {
+		q["action"] = strings.ToUpper(spec.Action)
+	}
+
+	if spec.DateFrom != 0 || spec.DateTo != 0 {
+		dateFilter := bson.M{}
+		if spec.DateFrom != 0 {
+			dateFilter["$gte"] = time.Unix(spec.DateFrom, 0)
+		}
+
+		if spec.DateTo != 0 {
+			dateFilter["$lt"] = time.Unix(spec.DateTo, 0)
+		}
+
+		q["createdAt"] = dateFilter
+	}
+
+	actions := []*types.OperatorAction{}
+	total, err := dao.db.GetEx(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, offset, size, &actions)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &types.OperatorActionRes{Total: total, Actions: actions}, nil
+}
+
+// Drop drops all the operator actions in the current database
+func (dao *OperatorActionDao) Drop() error {
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
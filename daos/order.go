@@ -1,7 +1,7 @@
 package daos
 
 import (
-	"fmt"
+	"context"
 	"math/big"
 	"sort"
 	"strconv"
@@ -10,13 +10,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/tomox"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/math"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // OrderDao contains:
@@ -25,6 +26,8 @@ import (
 type OrderDao struct {
 	collectionName string
 	dbName         string
+	db             Store
+	tomoxClient    *tomox.Client
 }
 
 type OrderDaoOption = func(*OrderDao) error
@@ -36,9 +39,18 @@ func OrderDaoDBOption(dbName string) func(dao *OrderDao) error {
 	}
 }
 
-// NewOrderDao returns a new instance of OrderDao
-func NewOrderDao(opts ...OrderDaoOption) *OrderDao {
-	dao := &OrderDao{}
+// OrderDaoTomoXOption sets the client of the TomoX nodes the orders are sent to
+func OrderDaoTomoXOption(client *tomox.Client) func(dao *OrderDao) error {
+	return func(dao *OrderDao) error {
+		dao.tomoxClient = client
+		return nil
+	}
+}
+
+// NewOrderDao returns a new instance of OrderDao. The orders are sent to the TomoX nodes of the
+// configuration unless a client is set by OrderDaoTomoXOption
+func NewOrderDao(db Store, opts ...OrderDaoOption) *OrderDao {
+	dao := &OrderDao{db: db}
 	dao.collectionName = "orders"
 	dao.dbName = app.Config.DBName
 
@@ -49,121 +61,133 @@ func NewOrderDao(opts ...OrderDaoOption) *OrderDao {
 		}
 	}
 
-	index := mgo.Index{
+	if dao.tomoxClient == nil {
+		dao.tomoxClient = tomox.NewClient(app.Config.NodeURLs(), app.Config.TomoXTimeout)
+	}
+
+	index := Index{
 		Key:    []string{"hash"},
 		Unique: true,
 	}
 
-	i1 := mgo.Index{
+	i1 := Index{
 		Key: []string{"userAddress"},
 	}
 
-	i2 := mgo.Index{
+	i2 := Index{
 		Key: []string{"status"},
 	}
 
-	i3 := mgo.Index{
+	i3 := Index{
 		Key: []string{"baseToken"},
 	}
 
-	i4 := mgo.Index{
+	i4 := Index{
 		Key: []string{"quoteToken"},
 	}
 
-	i5 := mgo.Index{
+	i5 := Index{
 		Key:       []string{"price"},
-		Collation: &mgo.Collation{NumericOrdering: true, Locale: "en"},
+		Collation: &Collation{NumericOrdering: true, Locale: "en"},
 	}
 
-	i6 := mgo.Index{
+	i6 := Index{
 		Key: []string{"baseToken", "quoteToken", "price"},
 	}
 
-	i7 := mgo.Index{
+	i7 := Index{
 		Key: []string{"side", "status"},
 	}
 
-	i8 := mgo.Index{
+	i8 := Index{
 		Key: []string{"baseToken", "quoteToken", "side", "status"},
 	}
 
-	i9 := mgo.Index{
+	i9 := Index{
 		Key: []string{"createdAt"},
 	}
-	indexes := []mgo.Index{}
-	indexes, err := db.Session.DB(dao.dbName).C(dao.collectionName).Indexes()
+
+	i10 := Index{
+		Key: []string{"userAddress", "clientOrderId"},
+	}
+	indexes := []Index{}
+	indexes, err := db.Indexes(dao.dbName, dao.collectionName)
 	if err == nil {
 		if !existedIndex("index_order_hash", indexes) {
-			err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(index)
+			err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
 			if err != nil {
 				panic(err)
 			}
 		}
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i1)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i1)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i2)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i2)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i3)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i3)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i4)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i4)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i5)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i5)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i6)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i6)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i7)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i7)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i8)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i8)
 	if err != nil {
 		panic(err)
 	}
 
-	err = db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(i9)
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i9)
 	if err != nil {
 		panic(err)
 	}
 
-	return dao
-}
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i10)
+	if err != nil {
+		panic(err)
+	}
 
-func (dao *OrderDao) GetCollection() *mgo.Collection {
-	return db.GetCollection(dao.dbName, dao.collectionName)
+	return dao
 }
 
-func (dao *OrderDao) Watch() (*mgo.ChangeStream, *mgo.Session, error) {
-	return db.Watch(dao.dbName, dao.collectionName, mgo.ChangeStreamOptions{
-		FullDocument:   mgo.UpdateLookup,
-		MaxAwaitTimeMS: 500,
-		BatchSize:      1000,
+// Watch opens a change stream on the orders, which starts after the event of resumeAfter if
+// it is not empty
+func (dao *OrderDao) Watch(resumeAfter []byte) (interfaces.ChangeStream, error) {
+	return dao.db.Watch(dao.dbName, dao.collectionName, WatchOptions{
+		FullDocument: true,
+		MaxAwaitTime: 500 * time.Millisecond,
+		BatchSize:    1000,
+		ResumeAfter:  resumeAfter,
 	})
 }
 
 // Create function performs the DB insertion task for Order collection
 func (dao *OrderDao) Create(o *types.Order) error {
-	o.ID = bson.NewObjectId()
+	o.ID = primitive.NewObjectID()
 	o.CreatedAt = time.Now()
 	o.UpdatedAt = time.Now()
 
@@ -171,7 +195,7 @@ func (dao *OrderDao) Create(o *types.Order) error {
 		o.Status = "OPEN"
 	}
 
-	err := db.Create(dao.dbName, dao.collectionName, o)
+	err := dao.db.Create(dao.dbName, dao.collectionName, o)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -181,7 +205,7 @@ func (dao *OrderDao) Create(o *types.Order) error {
 }
 
 func (dao *OrderDao) DeleteByHashes(hashes ...common.Hash) error {
-	err := db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
+	err := dao.db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -196,7 +220,7 @@ func (dao *OrderDao) Delete(orders ...*types.Order) error {
 		hashes = append(hashes, o.Hash)
 	}
 
-	err := db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
+	err := dao.db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -207,10 +231,10 @@ func (dao *OrderDao) Delete(orders ...*types.Order) error {
 
 // Update function performs the DB updations task for Order collection
 // corresponding to a particular order ID
-func (dao *OrderDao) Update(id bson.ObjectId, o *types.Order) error {
+func (dao *OrderDao) Update(id primitive.ObjectID, o *types.Order) error {
 	o.UpdatedAt = time.Now()
 
-	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": id}, o)
+	err := dao.db.Update(dao.dbName, dao.collectionName, bson.M{"_id": id}, o)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -219,10 +243,10 @@ func (dao *OrderDao) Update(id bson.ObjectId, o *types.Order) error {
 	return nil
 }
 
-func (dao *OrderDao) Upsert(id bson.ObjectId, o *types.Order) error {
+func (dao *OrderDao) Upsert(id primitive.ObjectID, o *types.Order) error {
 	o.UpdatedAt = time.Now()
 
-	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"_id": id}, o)
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{"_id": id}, o)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -232,7 +256,7 @@ func (dao *OrderDao) Upsert(id bson.ObjectId, o *types.Order) error {
 }
 
 func (dao *OrderDao) UpsertByHash(h common.Hash, o *types.Order) error {
-	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"hash": h.Hex()}, types.OrderBSONUpdate{o})
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{"hash": h.Hex()}, types.OrderBSONUpdate{Order: o})
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -244,7 +268,7 @@ func (dao *OrderDao) UpsertByHash(h common.Hash, o *types.Order) error {
 func (dao *OrderDao) UpdateAllByHash(h common.Hash, o *types.Order) error {
 	o.UpdatedAt = time.Now()
 
-	err := db.Update(dao.dbName, dao.collectionName, bson.M{"hash": h.Hex()}, o)
+	err := dao.db.Update(dao.dbName, dao.collectionName, bson.M{"hash": h.Hex()}, o)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -257,14 +281,14 @@ func (dao *OrderDao) FindAndModify(h common.Hash, o *types.Order) (*types.Order,
 	o.UpdatedAt = time.Now()
 	query := bson.M{"hash": h.Hex()}
 	updated := &types.Order{}
-	change := mgo.Change{
-		Update:    types.OrderBSONUpdate{o},
+	change := Change{
+		Update:    types.OrderBSONUpdate{Order: o},
 		Upsert:    true,
 		Remove:    false,
 		ReturnNew: true,
 	}
 
-	err := db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
+	err := dao.db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -285,7 +309,7 @@ func (dao *OrderDao) UpdateByHash(h common.Hash, o *types.Order) error {
 		"updatedAt":    o.UpdatedAt,
 	}}
 
-	err := db.Update(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -300,7 +324,7 @@ func (dao *OrderDao) UpdateOrderStatus(h common.Hash, status string) error {
 		"status": status,
 	}}
 
-	err := db.Update(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -323,14 +347,14 @@ func (dao *OrderDao) UpdateOrderStatusesByHashes(status string, hashes ...common
 		},
 	}
 
-	err := db.UpdateAll(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.UpdateAll(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return nil, nil
 	}
 
 	orders := []*types.Order{}
-	err = db.Get(dao.dbName, dao.collectionName, query, 0, 0, &orders)
+	err = dao.db.Get(dao.dbName, dao.collectionName, query, 0, 0, &orders)
 	if err != nil {
 		logger.Error(err)
 		return nil, nil
@@ -342,7 +366,7 @@ func (dao *OrderDao) UpdateOrderStatusesByHashes(status string, hashes ...common
 func (dao *OrderDao) UpdateOrderFilledAmount(hash common.Hash, value *big.Int) error {
 	q := bson.M{"hash": hash.Hex()}
 	res := []types.Order{}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -367,7 +391,7 @@ func (dao *OrderDao) UpdateOrderFilledAmount(hash common.Hash, value *big.Int) e
 		"filledAmount": filledAmount.String(),
 	}}
 
-	err = db.Update(dao.dbName, dao.collectionName, q, update)
+	err = dao.db.Update(dao.dbName, dao.collectionName, q, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -384,7 +408,7 @@ func (dao *OrderDao) UpdateOrderFilledAmounts(hashes []common.Hash, amount []*bi
 	}
 
 	query := bson.M{"hash": bson.M{"$in": hexes}}
-	err := db.Get(dao.dbName, dao.collectionName, query, 0, 0, &orders)
+	err := dao.db.Get(dao.dbName, dao.collectionName, query, 0, 0, &orders)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -410,7 +434,7 @@ func (dao *OrderDao) UpdateOrderFilledAmounts(hashes []common.Hash, amount []*bi
 			"status":       status,
 			"filledAmount": filledAmount.String(),
 		}}
-		change := mgo.Change{
+		change := Change{
 			Update:    update,
 			Upsert:    true,
 			Remove:    false,
@@ -418,7 +442,7 @@ func (dao *OrderDao) UpdateOrderFilledAmounts(hashes []common.Hash, amount []*bi
 		}
 
 		updated := &types.Order{}
-		err := db.FindAndModify(dao.dbName, dao.collectionName, query, change, updated)
+		err := dao.db.FindAndModify(dao.dbName, dao.collectionName, query, change, updated)
 		if err != nil {
 			logger.Error(err)
 			return nil, err
@@ -435,7 +459,7 @@ func (dao *OrderDao) UpdateOrderFilledAmounts(hashes []common.Hash, amount []*bi
 func (dao *OrderDao) GetOrderCountByUserAddress(addr common.Address) (int, error) {
 	q := bson.M{"userAddress": addr.Hex()}
 
-	total, err := db.Count(dao.dbName, dao.collectionName, q)
+	total, err := dao.db.Count(dao.dbName, dao.collectionName, q)
 
 	if err != nil {
 		logger.Error(err)
@@ -447,9 +471,9 @@ func (dao *OrderDao) GetOrderCountByUserAddress(addr common.Address) (int, error
 
 // GetByID function fetches a single document from order collection based on mongoDB ID.
 // Returns Order type struct
-func (dao *OrderDao) GetByID(id bson.ObjectId) (*types.Order, error) {
+func (dao *OrderDao) GetByID(id primitive.ObjectID) (*types.Order, error) {
 	var response *types.Order
-	err := db.GetByID(dao.dbName, dao.collectionName, id, &response)
+	err := dao.db.GetByID(dao.dbName, dao.collectionName, id, &response)
 	return response, err
 }
 
@@ -459,7 +483,7 @@ func (dao *OrderDao) GetByHash(hash common.Hash) (*types.Order, error) {
 	q := bson.M{"hash": hash.Hex()}
 	res := []types.Order{}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -482,7 +506,7 @@ func (dao *OrderDao) GetByHashes(hashes []common.Hash) ([]*types.Order, error) {
 	q := bson.M{"hash": bson.M{"$in": hexes}}
 	res := []*types.Order{}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -534,7 +558,7 @@ func (dao *OrderDao) GetByUserAddress(addr, bt, qt common.Address, from, to int6
 		}
 	}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, limit[0], &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, limit[0], &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -588,9 +612,12 @@ func (dao *OrderDao) GetOrders(orderSpec types.OrderSpec, sort []string, offset
 	if orderSpec.OrderHash != "" {
 		q["hash"] = orderSpec.OrderHash
 	}
+	if orderSpec.ClientOrderID != "" {
+		q["clientOrderId"] = orderSpec.ClientOrderID
+	}
 	var res types.OrderRes
 	orders := []*types.Order{}
-	c, err := db.GetEx(dao.dbName, dao.collectionName, q, sort, offset, size, &orders)
+	c, err := dao.db.GetEx(dao.dbName, dao.collectionName, q, sort, offset, size, &orders)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -614,7 +641,7 @@ func (dao *OrderDao) GetOpenOrdersByUserAddress(addr common.Address) ([]*types.O
 		"status":      bson.M{"$in": []string{types.OrderStatusOpen, types.OrderStatusPartialFilled}},
 	}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 
 	if err != nil {
 		logger.Error(err)
@@ -636,7 +663,7 @@ func (dao *OrderDao) GetOpenOrders() ([]*types.Order, error) {
 	q = bson.M{
 		"status": bson.M{"$in": []string{types.OrderStatusOpen, types.OrderStatusPartialFilled}},
 	}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -664,7 +691,7 @@ func (dao *OrderDao) GetCurrentByUserAddress(addr common.Address, limit ...int)
 		},
 	}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, limit[0], &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, limit[0], &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -732,7 +759,7 @@ func (dao *OrderDao) GetHistoryByUserAddress(addr, bt, qt common.Address, from,
 		}
 	}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, limit[0], &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, limit[0], &res)
 
 	if err != nil {
 		logger.Error(err)
@@ -767,7 +794,7 @@ func (dao *OrderDao) GetUserLockedBalance(account common.Address, token common.A
 		},
 	}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &orders)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &orders)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -799,13 +826,13 @@ func (dao *OrderDao) GetUserLockedBalance(account common.Address, token common.A
 
 func (dao *OrderDao) GetRawOrderBook(p *types.Pair) ([]*types.Order, error) {
 	var orders []*types.Order
-	c := dao.GetCollection()
 	// TODO: need to have limit
-	err := c.Find(bson.M{
+	q := bson.M{
 		"status":     bson.M{"$in": []string{types.OrderStatusOpen, types.OrderStatusPartialFilled}},
 		"baseToken":  p.BaseTokenAddress.Hex(),
 		"quoteToken": p.QuoteTokenAddress.Hex(),
-	}).Sort("-createdAt").All(&orders)
+	}
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, 0, 0, &orders)
 
 	sort.SliceStable(orders, func(i, j int) bool {
 		return orders[i].PricePoint.Cmp(orders[j].PricePoint) == 1
@@ -822,15 +849,15 @@ func (dao *OrderDao) GetSideOrderBook(p *types.Pair, side string, srt int, limit
 	}
 
 	var orders []types.Order
-	c := dao.GetCollection()
 
 	// TODO: need to have limit
-	err := c.Find(bson.M{
+	q := bson.M{
 		"status":     bson.M{"$in": []string{types.OrderStatusOpen, types.OrderStatusPartialFilled}},
 		"baseToken":  p.BaseTokenAddress.Hex(),
 		"quoteToken": p.QuoteTokenAddress.Hex(),
 		"side":       side,
-	}).Sort("-createdAt").All(&orders)
+	}
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, 0, 0, &orders)
 
 	pa := make(map[string]*big.Int)
 	for _, order := range orders {
@@ -875,62 +902,59 @@ func (dao *OrderDao) GetOrderBookInDb(p *types.Pair) ([]map[string]string, []map
 }
 
 func (dao *OrderDao) GetOrderBook(p *types.Pair) ([]map[string]string, []map[string]string, error) {
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-	defer rpcClient.Close()
+	ctx := context.Background()
 
-	var result interface{}
-
-	err = rpcClient.Call(&result, "tomox_getAsks", p.BaseTokenAddress.Hex(), p.QuoteTokenAddress.Hex())
 	asks := []map[string]string{}
-	if result != nil && err == nil {
-		for k, v := range result.(map[string]interface{}) {
-			s := map[string]string{
-				"pricepoint": k,
-				"amount":     fmt.Sprintf("%.0f", v.(float64)),
-			}
-			asks = append(asks, s)
-		}
-
-		sort.SliceStable(asks, func(i, j int) bool {
-			return math.ToBigInt(asks[i]["pricepoint"]).Cmp(math.ToBigInt(asks[j]["pricepoint"])) == -1
-		})
+	result, err := dao.tomoxClient.GetAsks(ctx, p.BaseTokenAddress, p.QuoteTokenAddress)
+	if err != nil {
+		logger.Error(err)
 	}
 
-	err = rpcClient.Call(&result, "tomox_getBids", p.BaseTokenAddress.Hex(), p.QuoteTokenAddress.Hex())
-	bids := []map[string]string{}
-	if result != nil && err == nil {
-		for k, v := range result.(map[string]interface{}) {
-			s := map[string]string{
-				"pricepoint": k,
-				"amount":     fmt.Sprintf("%.0f", v.(float64)),
-			}
-			bids = append(bids, s)
+	for k, v := range result {
+		s := map[string]string{
+			"pricepoint": k,
+			"amount":     v.String(),
 		}
-		sort.SliceStable(bids, func(i, j int) bool {
-			return math.ToBigInt(bids[i]["pricepoint"]).Cmp(math.ToBigInt(bids[j]["pricepoint"])) == 1
-		})
+		asks = append(asks, s)
 	}
 
+	sort.SliceStable(asks, func(i, j int) bool {
+		return math.ToBigInt(asks[i]["pricepoint"]).Cmp(math.ToBigInt(asks[j]["pricepoint"])) == -1
+	})
+
+	bids := []map[string]string{}
+	result, err = dao.tomoxClient.GetBids(ctx, p.BaseTokenAddress, p.QuoteTokenAddress)
 	if err != nil {
 		logger.Error(err)
-		return bids, asks, nil
 	}
 
+	for k, v := range result {
+		s := map[string]string{
+			"pricepoint": k,
+			"amount":     v.String(),
+		}
+		bids = append(bids, s)
+	}
+
+	sort.SliceStable(bids, func(i, j int) bool {
+		return math.ToBigInt(bids[i]["pricepoint"]).Cmp(math.ToBigInt(bids[j]["pricepoint"])) == 1
+	})
+
 	return bids, asks, nil
 }
 
 func (dao *OrderDao) GetOrderBookPricePoint(p *types.Pair, pp *big.Int, side string) (*big.Int, error) {
 	var orders []types.Order
-	c := dao.GetCollection()
 
 	//TODO: need to have limit
-	err := c.Find(bson.M{
+	q := bson.M{
 		"status":     bson.M{"$in": []string{types.OrderStatusOpen, types.OrderStatusPartialFilled}},
 		"baseToken":  p.BaseTokenAddress.Hex(),
 		"quoteToken": p.QuoteTokenAddress.Hex(),
 		"side":       side,
 		"price":      pp.String(),
-	}).Sort("-createdAt").All(&orders)
+	}
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, 0, 0, &orders)
 
 	amount := big.NewInt(0)
 
@@ -943,7 +967,7 @@ func (dao *OrderDao) GetOrderBookPricePoint(p *types.Pair, pp *big.Int, side str
 
 // Drop drops all the order documents in the current database
 func (dao *OrderDao) Drop() error {
-	err := db.DropCollection(dao.dbName, dao.collectionName)
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -956,7 +980,7 @@ func (dao *OrderDao) Drop() error {
 func (dao *OrderDao) Aggregate(q []bson.M) ([]*types.OrderData, error) {
 	logger.Info("Query aggregate", q)
 	orderData := []*types.OrderData{}
-	err := db.Aggregate(dao.dbName, dao.collectionName, q, &orderData)
+	err := dao.db.Aggregate(dao.dbName, dao.collectionName, q, &orderData)
 	if err != nil {
 		logger.Error(err)
 		return []*types.OrderData{}, err
@@ -965,35 +989,12 @@ func (dao *OrderDao) Aggregate(q []bson.M) ([]*types.OrderData, error) {
 	return orderData, nil
 }
 
-type OrderMsg struct {
-	AccountNonce    hexutil.Uint64 `json:"nonce"    gencodec:"required"`
-	Quantity        hexutil.Big    `json:"quantity,omitempty"`
-	Price           hexutil.Big    `json:"price,omitempty"`
-	ExchangeAddress common.Address `json:"exchangeAddress,omitempty"`
-	UserAddress     common.Address `json:"userAddress,omitempty"`
-	BaseToken       common.Address `json:"baseToken,omitempty"`
-	QuoteToken      common.Address `json:"quoteToken,omitempty"`
-	Status          string         `json:"status,omitempty"`
-	Side            string         `json:"side,omitempty"`
-	Type            string         `json:"type,omitempty"`
-	OrderID         hexutil.Uint64 `json:"orderid,omitempty"`
-	// Signature values
-	V hexutil.Big `json:"v" gencodec:"required"`
-	R hexutil.Big `json:"r" gencodec:"required"`
-	S hexutil.Big `json:"s" gencodec:"required"`
-
-	// This is only used when marshaling to JSON.
-	Hash common.Hash `json:"hash" rlp:"-"`
-}
-
 type OrderErrorMsg struct {
 	Message string `json:"message,omitempty"`
 }
 
 // AddNewOrder add order
 func (dao *OrderDao) AddNewOrder(o *types.Order, topic string) error {
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-	defer rpcClient.Close()
 	bigstr := o.Nonce.String()
 	n, err := strconv.ParseInt(bigstr, 10, 64)
 	if err != nil {
@@ -1003,7 +1004,7 @@ func (dao *OrderDao) AddNewOrder(o *types.Order, topic string) error {
 	R := o.Signature.R.Big()
 	S := o.Signature.S.Big()
 
-	msg := OrderMsg{
+	msg := &tomox.OrderMsg{
 		AccountNonce:    hexutil.Uint64(uint64(n)),
 		Quantity:        hexutil.Big(*o.Amount),
 		Price:           hexutil.Big(*o.PricePoint),
@@ -1019,9 +1020,8 @@ func (dao *OrderDao) AddNewOrder(o *types.Order, topic string) error {
 		R:               hexutil.Big(*R),
 		S:               hexutil.Big(*S),
 	}
-	var result interface{}
 	logger.Info("tomox_sendOrder", o.Status, o.Hash.Hex())
-	err = rpcClient.Call(&result, "tomox_sendOrder", msg)
+	err = dao.tomoxClient.SendOrder(context.Background(), msg)
 
 	if err != nil {
 		logger.Error(err)
@@ -1037,9 +1037,6 @@ func (dao *OrderDao) AddNewOrder(o *types.Order, topic string) error {
 
 // CancelOrder cancel order
 func (dao *OrderDao) CancelOrder(o *types.Order, topic string) error {
-
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-	defer rpcClient.Close()
 	bigstr := o.Nonce.String()
 	n, err := strconv.ParseInt(bigstr, 10, 64)
 	if err != nil {
@@ -1049,7 +1046,7 @@ func (dao *OrderDao) CancelOrder(o *types.Order, topic string) error {
 	R := o.Signature.R.Big()
 	S := o.Signature.S.Big()
 
-	msg := OrderMsg{
+	msg := &tomox.OrderMsg{
 		AccountNonce:    hexutil.Uint64(uint64(n)),
 		Status:          o.Status,
 		Hash:            o.Hash,
@@ -1062,9 +1059,8 @@ func (dao *OrderDao) CancelOrder(o *types.Order, topic string) error {
 		R:               hexutil.Big(*R),
 		S:               hexutil.Big(*S),
 	}
-	var result interface{}
 	logger.Info("tomox_sendOrder", o.Status, o.Hash.Hex(), o.OrderID, o.UserAddress.Hex(), n)
-	err = rpcClient.Call(&result, "tomox_sendOrder", msg)
+	err = dao.tomoxClient.SendOrder(context.Background(), msg)
 
 	if err != nil {
 		logger.Error(err)
@@ -1078,25 +1074,10 @@ func (dao *OrderDao) CancelOrder(o *types.Order, topic string) error {
 	return nil
 }
 
-// GetOrderNonce get nonce of order
+// GetOrderNonce get nonce of order, as the hex string returned by tomox_getOrderCount
 func (dao *OrderDao) GetOrderNonce(userAddress common.Address) (interface{}, error) {
-	rpcClient, err := rpc.DialHTTP(app.Config.Tomochain["http_url"])
-
-	defer rpcClient.Close()
-
-	if err != nil {
-		logger.Error(err)
-		return 0, err
-	}
-
 	var result interface{}
-	if err != nil {
-		logger.Error(err)
-		return 0, err
-	}
-
-	err = rpcClient.Call(&result, "tomox_getOrderCount", userAddress)
-
+	err := dao.tomoxClient.Call(context.Background(), &result, "tomox_getOrderCount", userAddress)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -0,0 +1,192 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrderExpiryDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type OrderExpiryDao struct {
+	collectionName string
+	dbName         string
+	db             Store
+}
+
+type OrderExpiryDaoOption = func(*OrderExpiryDao) error
+
+func OrderExpiryDaoDBOption(dbName string) func(dao *OrderExpiryDao) error {
+	return func(dao *OrderExpiryDao) error {
+		dao.dbName = dbName
+		return nil
+	}
+}
+
+// NewOrderExpiryDao returns a new instance of OrderExpiryDao
+func NewOrderExpiryDao(db Store, opts ...OrderExpiryDaoOption) *OrderExpiryDao {
+	dao := &OrderExpiryDao{db: db}
+	dao.collectionName = "order_expiries"
+	dao.dbName = app.Config.DBName
+
+	for _, op := range opts {
+		err := op(dao)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	index := Index{
+		Key:    []string{"orderHash"},
+		Unique: true,
+	}
+
+	i1 := Index{
+		Key: []string{"status", "expiresAt"},
+	}
+
+	i2 := Index{
+		Key: []string{"userAddress", "status"},
+	}
+
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i1)
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i2)
+	if err != nil {
+		panic(err)
+	}
+
+	return dao
+}
+
+// Create function performs the DB insertion task for OrderExpiry collection
+func (dao *OrderExpiryDao) Create(e *types.OrderExpiry) error {
+	e.ID = primitive.NewObjectID()
+	e.CreatedAt = time.Now()
+	e.UpdatedAt = time.Now()
+
+	if e.Status == "" {
+		e.Status = types.OrderExpiryStatusPending
+	}
+
+	err := dao.db.Create(dao.dbName, dao.collectionName, e)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByOrderHash returns the expiry of an order, or nil if the order has none
+func (dao *OrderExpiryDao) GetByOrderHash(h common.Hash) (*types.OrderExpiry, error) {
+	q := bson.M{"orderHash": h.Hex()}
+	res := []types.OrderExpiry{}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return &res[0], nil
+}
+
+// GetDueOrderExpiries returns the pending expiries that are due at time t, oldest first
+func (dao *OrderExpiryDao) GetDueOrderExpiries(t time.Time) ([]*types.OrderExpiry, error) {
+	var res []*types.OrderExpiry
+
+	q := bson.M{
+		"status":    types.OrderExpiryStatusPending,
+		"expiresAt": bson.M{"$lte": t},
+	}
+
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"expiresAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if res == nil {
+		return []*types.OrderExpiry{}, nil
+	}
+
+	return res, nil
+}
+
+// GetPendingOrderExpiriesByUserAddress returns the pending expiries of the orders of a user
+func (dao *OrderExpiryDao) GetPendingOrderExpiriesByUserAddress(addr common.Address) ([]*types.OrderExpiry, error) {
+	var res []*types.OrderExpiry
+
+	q := bson.M{
+		"userAddress": addr.Hex(),
+		"status":      types.OrderExpiryStatusPending,
+	}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if res == nil {
+		return []*types.OrderExpiry{}, nil
+	}
+
+	return res, nil
+}
+
+// UpdatePendingOrderExpiryStatus sets the status of an order expiry only if it is still PENDING.
+// It reports whether the expiry was updated, so that the cron and the engine responses
+// never cancel the same order twice.
+func (dao *OrderExpiryDao) UpdatePendingOrderExpiryStatus(h common.Hash, status string) (bool, error) {
+	query := bson.M{
+		"orderHash": h.Hex(),
+		"status":    types.OrderExpiryStatusPending,
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":    status,
+		"updatedAt": time.Now(),
+	}}
+
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, update)
+	if err == ErrNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Drop drops all the order expiry documents in the current database
+func (dao *OrderExpiryDao) Drop() error {
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
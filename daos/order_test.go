@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package daos
 
 import (
@@ -6,8 +9,6 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
@@ -27,7 +28,7 @@ func init() {
 
 func TestUpdateOrderByHash(t *testing.T) {
 	o := &types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0001"),
+		ID:              objectID("537f700b537461b70c5f0001"),
 		UserAddress:     common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
@@ -49,7 +50,7 @@ func TestUpdateOrderByHash(t *testing.T) {
 		UpdatedAt: time.Unix(1405544146, 0),
 	}
 
-	dao := NewOrderDao()
+	dao := NewOrderDao(db)
 
 	err := dao.Create(o)
 	if err != nil {
@@ -92,14 +93,14 @@ func TestUpdateOrderByHash(t *testing.T) {
 }
 
 func TestOrderUpdate(t *testing.T) {
-	dao := NewOrderDao()
+	dao := NewOrderDao(db)
 	err := dao.Drop()
 	if err != nil {
 		t.Errorf("Could not drop previous order state")
 	}
 
 	o := &types.Order{
-		ID:           bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:           objectID("537f700b537461b70c5f0000"),
 		UserAddress:  common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		BaseToken:    common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
 		QuoteToken:   common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
@@ -161,14 +162,14 @@ func TestOrderUpdate(t *testing.T) {
 }
 
 func TestOrderDao1(t *testing.T) {
-	dao := NewOrderDao()
+	dao := NewOrderDao(db)
 	err := dao.Drop()
 	if err != nil {
 		t.Errorf("Could not drop previous order state")
 	}
 
 	o := &types.Order{
-		ID:           bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:           objectID("537f700b537461b70c5f0000"),
 		UserAddress:  common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		BaseToken:    common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
 		QuoteToken:   common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
@@ -209,7 +210,7 @@ func TestOrderDao1(t *testing.T) {
 }
 
 func TestOrderDaoGetByHashes(t *testing.T) {
-	dao := NewOrderDao()
+	dao := NewOrderDao(db)
 	err := dao.Drop()
 	if err != nil {
 		t.Error("Could not drop previous order state")
@@ -234,7 +235,7 @@ func TestOrderDaoGetByHashes(t *testing.T) {
 }
 
 func TestUpdateOrderFilledAmount1(t *testing.T) {
-	dao := NewOrderDao()
+	dao := NewOrderDao(db)
 	err := dao.Drop()
 	if err != nil {
 		t.Error("Could not drop previous order collection")
@@ -247,7 +248,7 @@ func TestUpdateOrderFilledAmount1(t *testing.T) {
 	hash := common.HexToHash("0x5")
 
 	o1 := &types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0001"),
+		ID:              objectID("537f700b537461b70c5f0001"),
 		UserAddress:     user,
 		ExchangeAddress: exchange,
 		BaseToken:       baseToken,
@@ -281,7 +282,7 @@ func TestUpdateOrderFilledAmount1(t *testing.T) {
 }
 
 func TestUpdateOrderFilledAmount2(t *testing.T) {
-	dao := NewOrderDao()
+	dao := NewOrderDao(db)
 	err := dao.Drop()
 	if err != nil {
 		t.Error("Could not drop previous order collection")
@@ -294,7 +295,7 @@ func TestUpdateOrderFilledAmount2(t *testing.T) {
 	hash := common.HexToHash("0x5")
 
 	o1 := &types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0001"),
+		ID:              objectID("537f700b537461b70c5f0001"),
 		UserAddress:     user,
 		ExchangeAddress: exchange,
 		Amount:          units.Ethers(10),
@@ -328,7 +329,7 @@ func TestUpdateOrderFilledAmount2(t *testing.T) {
 }
 
 func TestUpdateOrderFilledAmount3(t *testing.T) {
-	dao := NewOrderDao()
+	dao := NewOrderDao(db)
 	err := dao.Drop()
 	if err != nil {
 		t.Error("Could not drop previous order collection")
@@ -341,7 +342,7 @@ func TestUpdateOrderFilledAmount3(t *testing.T) {
 	hash := common.HexToHash("0x5")
 
 	o1 := &types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0001"),
+		ID:              objectID("537f700b537461b70c5f0001"),
 		UserAddress:     user,
 		ExchangeAddress: exchange,
 		Amount:          units.Ethers(10),
@@ -375,7 +376,7 @@ func TestUpdateOrderFilledAmount3(t *testing.T) {
 }
 
 func TestUpdateOrderFilledAmounts(t *testing.T) {
-	dao := NewOrderDao()
+	dao := NewOrderDao(db)
 	err := dao.Drop()
 	if err != nil {
 		t.Error("Could not drop previous order collection")
@@ -389,7 +390,7 @@ func TestUpdateOrderFilledAmounts(t *testing.T) {
 	hash2 := common.HexToHash("0x6")
 
 	o1 := &types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0001"),
+		ID:              objectID("537f700b537461b70c5f0001"),
 		UserAddress:     user,
 		ExchangeAddress: exchange,
 		Amount:          units.Ethers(2),
@@ -404,7 +405,7 @@ func TestUpdateOrderFilledAmounts(t *testing.T) {
 	}
 
 	o2 := &types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0001"),
+		ID:              objectID("537f700b537461b70c5f0001"),
 		UserAddress:     user,
 		ExchangeAddress: exchange,
 		Amount:          units.Ethers(1),
@@ -439,7 +440,7 @@ func TestUpdateOrderFilledAmounts(t *testing.T) {
 }
 
 func TestOrderStatusesByHashes(t *testing.T) {
-	dao := NewOrderDao()
+	dao := NewOrderDao(db)
 	err := dao.Drop()
 	if err != nil {
 		t.Error("Could not drop previous order collection")
@@ -453,7 +454,7 @@ func TestOrderStatusesByHashes(t *testing.T) {
 	hash2 := common.HexToHash("0x6")
 
 	o1 := &types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0001"),
+		ID:              objectID("537f700b537461b70c5f0001"),
 		UserAddress:     user,
 		ExchangeAddress: exchange,
 		BaseToken:       baseToken,
@@ -468,7 +469,7 @@ func TestOrderStatusesByHashes(t *testing.T) {
 	}
 
 	o2 := &types.Order{
-		ID:           bson.ObjectIdHex("537f700b537461b70c5f0002"),
+		ID:           objectID("537f700b537461b70c5f0002"),
 		UserAddress:  user,
 		BaseToken:    baseToken,
 		QuoteToken:   quoteToken,
@@ -503,14 +504,14 @@ func TestOrderStatusesByHashes(t *testing.T) {
 
 func TestGetOrderBook(t *testing.T) {
 
-	session, err := mgo.Dial(app.Config.MongoURL)
+	d, err := Connect(app.Config.MongoURL)
 	if err != nil {
 		panic(err)
 	}
 
-	db = &Database{session}
-	pairDao := NewPairDao(PairDaoDBOption("tomodex"))
-	orderDao := NewOrderDao(OrderDaoDBOption("tomodex"))
+	db = d
+	pairDao := NewPairDao(db, PairDaoDBOption("tomodex"))
+	orderDao := NewOrderDao(db, OrderDaoDBOption("tomodex"))
 	pair, err := pairDao.GetByTokenSymbols("AE", "WETH")
 	if err != nil {
 		panic(err)
@@ -525,14 +526,14 @@ func TestGetOrderBook(t *testing.T) {
 }
 
 func TestGetExchangeRate(t *testing.T) {
-	session, err := mgo.Dial(app.Config.MongoURL)
+	d, err := Connect(app.Config.MongoURL)
 	if err != nil {
 		panic(err)
 	}
 
-	db = &Database{session}
-	pairDao := NewPairDao(PairDaoDBOption("tomodex"))
-	orderDao := NewOrderDao(OrderDaoDBOption("tomodex"))
+	db = d
+	pairDao := NewPairDao(db, PairDaoDBOption("tomodex"))
+	orderDao := NewOrderDao(db, OrderDaoDBOption("tomodex"))
 	// pair, err := pairDao.GetByTokenSymbols("AE", "WETH")
 	pair, err := pairDao.GetByName("AE/WETH")
 	if err != nil {
@@ -561,15 +562,15 @@ func TestGetExchangeRate(t *testing.T) {
 }
 
 func TestGetOrderBookPricePoint(t *testing.T) {
-	session, err := mgo.Dial(app.Config.MongoURL)
+	d, err := Connect(app.Config.MongoURL)
 	if err != nil {
 		panic(err)
 	}
 
-	db = &Database{session}
+	db = d
 
-	pairDao := NewPairDao(PairDaoDBOption("tomodex"))
-	orderDao := NewOrderDao(OrderDaoDBOption("tomodex"))
+	pairDao := NewPairDao(db, PairDaoDBOption("tomodex"))
+	orderDao := NewOrderDao(db, OrderDaoDBOption("tomodex"))
 	pair, err := pairDao.GetByTokenSymbols("AE", "WETH")
 	if err != nil {
 		panic(err)
@@ -584,15 +585,15 @@ func TestGetOrderBookPricePoint(t *testing.T) {
 }
 
 func TestGetRawOrderBook(t *testing.T) {
-	session, err := mgo.Dial(app.Config.MongoURL)
+	d, err := Connect(app.Config.MongoURL)
 	if err != nil {
 		panic(err)
 	}
 
-	db = &Database{session}
+	db = d
 
-	pairDao := NewPairDao(PairDaoDBOption("tomodex"))
-	orderDao := NewOrderDao(OrderDaoDBOption("tomodex"))
+	pairDao := NewPairDao(db, PairDaoDBOption("tomodex"))
+	orderDao := NewOrderDao(db, OrderDaoDBOption("tomodex"))
 	pair, err := pairDao.GetByTokenSymbols("AE", "WETH")
 	if err != nil {
 		panic(err)
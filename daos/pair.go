@@ -5,10 +5,10 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // PairDao contains:
@@ -17,6 +17,7 @@ import (
 type PairDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
 type PairDaoOption = func(*PairDao) error
@@ -29,8 +30,8 @@ func PairDaoDBOption(dbName string) func(dao *PairDao) error {
 }
 
 // NewPairDao returns a new instance of AddressDao
-func NewPairDao(options ...PairDaoOption) *PairDao {
-	dao := &PairDao{}
+func NewPairDao(db Store, options ...PairDaoOption) *PairDao {
+	dao := &PairDao{db: db}
 	dao.collectionName = "pairs"
 	dao.dbName = app.Config.DBName
 
@@ -41,12 +42,12 @@ func NewPairDao(options ...PairDaoOption) *PairDao {
 		}
 	}
 
-	index := mgo.Index{
+	index := Index{
 		Key:    []string{"baseTokenAddress", "quoteTokenAddress", "relayerAddress"},
 		Unique: true,
 	}
 
-	err := db.Session.DB(dao.dbName).C(dao.collectionName).EnsureIndex(index)
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
 	if err != nil {
 		panic(err)
 	}
@@ -56,11 +57,11 @@ func NewPairDao(options ...PairDaoOption) *PairDao {
 
 // Create function performs the DB insertion task for pair collection
 func (dao *PairDao) Create(pair *types.Pair) error {
-	pair.ID = bson.NewObjectId()
+	pair.ID = primitive.NewObjectID()
 	pair.CreatedAt = time.Now()
 	pair.UpdatedAt = time.Now()
 
-	err := db.Create(dao.dbName, dao.collectionName, pair)
+	err := dao.db.Create(dao.dbName, dao.collectionName, pair)
 	return err
 }
 
@@ -68,7 +69,7 @@ func (dao *PairDao) Create(pair *types.Pair) error {
 // for GetAll return continous memory
 func (dao *PairDao) GetAll() ([]types.Pair, error) {
 	var res []types.Pair
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +90,7 @@ func (dao *PairDao) GetAll() ([]types.Pair, error) {
 
 func (dao *PairDao) GetAllByCoinbase(addr common.Address) ([]types.Pair, error) {
 	var res []types.Pair
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{"relayerAddress": addr.Hex()}, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{"relayerAddress": addr.Hex()}, 0, 0, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -101,7 +102,7 @@ func (dao *PairDao) GetListedPairs() ([]types.Pair, error) {
 	var res []types.Pair
 
 	sort := []string{"-rank"}
-	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{"active": true, "listed": true}, sort, 0, 0, &res)
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, bson.M{"active": true, "listed": true}, sort, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -118,7 +119,7 @@ func (dao *PairDao) GetUnlistedPairs() ([]types.Pair, error) {
 	var res []types.Pair
 
 	sort := []string{"-rank"}
-	err := db.GetAndSort(dao.dbName, dao.collectionName, bson.M{"active": true, "listed": false}, sort, 0, 0, &res)
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, bson.M{"active": true, "listed": false}, sort, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -136,7 +137,7 @@ func (dao *PairDao) GetActivePairsByCoinbase(addr common.Address) ([]*types.Pair
 
 	q := bson.M{"active": true, "relayerAddress": addr.Hex()}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -164,7 +165,7 @@ func (dao *PairDao) GetActivePairs() ([]*types.Pair, error) {
 
 	q := bson.M{"active": true}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -188,9 +189,9 @@ func (dao *PairDao) GetActivePairs() ([]*types.Pair, error) {
 }
 
 // GetByID function fetches details of a pair using pair's mongo ID.
-func (dao *PairDao) GetByID(id bson.ObjectId) (*types.Pair, error) {
+func (dao *PairDao) GetByID(id primitive.ObjectID) (*types.Pair, error) {
 	var response *types.Pair
-	err := db.GetByID(dao.dbName, dao.collectionName, id, &response)
+	err := dao.db.GetByID(dao.dbName, dao.collectionName, id, &response)
 	return response, err
 }
 
@@ -202,12 +203,12 @@ func (dao *PairDao) GetByName(name string) (*types.Pair, error) {
 	return dao.GetByTokenSymbols(tokenSymbols[0], tokenSymbols[1])
 
 	// var res []*types.Pair
-	// q := bson.M{"name": bson.RegEx{
+	// q := bson.M{"name": primitive.Regex{
 	// 	Pattern: name,
 	// 	Options: "i",
 	// }}
 
-	// err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	// err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	// if err != nil {
 	// 	return nil, err
 	// }
@@ -227,7 +228,7 @@ func (dao *PairDao) GetByTokenSymbols(baseTokenSymbol, quoteTokenSymbol string)
 		"quoteTokenSymbol": quoteTokenSymbol,
 	}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -249,7 +250,7 @@ func (dao *PairDao) GetByTokenAddress(baseToken, quoteToken common.Address) (*ty
 		"quoteTokenAddress": quoteToken.Hex(),
 	}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -264,10 +265,10 @@ func (dao *PairDao) GetByTokenAddress(baseToken, quoteToken common.Address) (*ty
 // DeleteByToken delete token by contract address
 func (dao *PairDao) DeleteByToken(baseAddress common.Address, quoteAddress common.Address) error {
 	query := bson.M{"baseTokenAddress": baseAddress.Hex(), "quoteTokenAddress": quoteAddress.Hex()}
-	return db.RemoveItem(dao.dbName, dao.collectionName, query)
+	return dao.db.RemoveItem(dao.dbName, dao.collectionName, query)
 }
 
 func (dao *PairDao) DeleteByTokenAndCoinbase(baseAddress common.Address, quoteAddress common.Address, addr common.Address) error {
 	query := bson.M{"baseTokenAddress": baseAddress.Hex(), "quoteTokenAddress": quoteAddress.Hex(), "relayerAddress": addr.Hex()}
-	return db.RemoveItem(dao.dbName, dao.collectionName, query)
+	return dao.db.RemoveItem(dao.dbName, dao.collectionName, query)
 }
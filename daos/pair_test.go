@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package daos
 
 import (
@@ -6,24 +9,28 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/testutils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func init() {
 	temp, _ := ioutil.TempDir("", "test")
 	server.SetPath(temp)
 
-	session := server.Session()
-	db = &Database{session}
+	d, err := Connect(server.URI())
+	if err != nil {
+		panic(err)
+	}
+
+	db = d
 }
 
 func TestPairDao(t *testing.T) {
-	dao := NewPairDao()
+	dao := NewPairDao(db)
 
 	pair := &types.Pair{
-		ID:                bson.NewObjectId(),
+		ID:                primitive.NewObjectID(),
 		BaseTokenSymbol:   "REQ",
 		BaseTokenAddress:  common.HexToAddress("0xcf7389dc6c63637598402907d5431160ec8972a5"),
 		QuoteTokenSymbol:  "WETH",
@@ -4,39 +4,40 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type RelayerDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
-func NewRelayerDao() *RelayerDao {
+func NewRelayerDao(db Store) *RelayerDao {
 	dbName := app.Config.DBName
 	collection := "relayers"
-	index := mgo.Index{
+	index := Index{
 		Key:    []string{"address", "domain"},
 		Unique: true,
 	}
 
-	err := db.Session.DB(dbName).C(collection).EnsureIndex(index)
+	err := db.EnsureIndex(dbName, collection, index)
 	if err != nil {
 		panic(err)
 	}
 
-	return &RelayerDao{collection, dbName}
+	return &RelayerDao{collection, dbName, db}
 }
 
 func (dao *RelayerDao) Create(a *types.Relayer) error {
-	a.ID = bson.NewObjectId()
+	a.ID = primitive.NewObjectID()
 	a.CreatedAt = time.Now()
 	a.UpdatedAt = time.Now()
 
-	err := db.Create(dao.dbName, dao.collectionName, a)
+	err := dao.db.Create(dao.dbName, dao.collectionName, a)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -50,14 +51,14 @@ func (dao *RelayerDao) FindOrCreate(addr common.Address) (*types.Relayer, error)
 	query := bson.M{"address": addr.Hex()}
 	updated := &types.Relayer{}
 
-	change := mgo.Change{
-		Update:    types.RelayerBSONUpdate{Relayer: a},
+	change := Change{
+		Update:    &types.RelayerBSONUpdate{Relayer: a},
 		Upsert:    true,
 		Remove:    false,
 		ReturnNew: true,
 	}
 
-	err := db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
+	err := dao.db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -67,15 +68,15 @@ func (dao *RelayerDao) FindOrCreate(addr common.Address) (*types.Relayer, error)
 }
 
 func (dao *RelayerDao) GetAll() (res []types.Relayer, err error) {
-	err = db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
+	err = dao.db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
 	return
 }
 
-func (dao *RelayerDao) GetByID(id bson.ObjectId) (*types.Relayer, error) {
+func (dao *RelayerDao) GetByID(id primitive.ObjectID) (*types.Relayer, error) {
 	res := []types.Relayer{}
 	q := bson.M{"_id": id}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -87,7 +88,7 @@ func (dao *RelayerDao) GetByID(id bson.ObjectId) (*types.Relayer, error) {
 func (dao *RelayerDao) GetByAddress(owner common.Address) (*types.Relayer, error) {
 	res := []types.Relayer{}
 	q := bson.M{"address": owner.Hex()}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -103,7 +104,7 @@ func (dao *RelayerDao) GetByAddress(owner common.Address) (*types.Relayer, error
 func (dao *RelayerDao) GetByHost(host string) (*types.Relayer, error) {
 	res := []types.Relayer{}
 	q := bson.M{"domain": host}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -118,7 +119,7 @@ func (dao *RelayerDao) GetByHost(host string) (*types.Relayer, error) {
 
 func (dao *RelayerDao) DeleteByAddress(addr common.Address) error {
 	query := bson.M{"address": addr.Hex()}
-	return db.RemoveItem(dao.dbName, dao.collectionName, query)
+	return dao.db.RemoveItem(dao.dbName, dao.collectionName, query)
 }
 
 func (dao *RelayerDao) UpdateByAddress(addr common.Address, relayer *types.Relayer) error {
@@ -136,7 +137,7 @@ func (dao *RelayerDao) UpdateByAddress(addr common.Address, relayer *types.Relay
 			"lockTime":   relayer.LockTime,
 		},
 	}
-	err := db.Update(dao.dbName, dao.collectionName, q, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -155,7 +156,7 @@ func (dao *RelayerDao) UpdateNameByAddress(addr common.Address, name string, url
 		},
 	}
 
-	err := db.Update(dao.dbName, dao.collectionName, q, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -0,0 +1,79 @@
+package daos
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// RequestNonceDao stores the nonces of the signed REST requests, so that a request
+// can not be replayed while its timestamp is valid
+type RequestNonceDao struct {
+	collectionName string
+	dbName         string
+	db             Store
+}
+
+// NewRequestNonceDao returns a new instance of RequestNonceDao
+func NewRequestNonceDao(db Store) *RequestNonceDao {
+	dao := &RequestNonceDao{db: db}
+	dao.collectionName = "request_nonces"
+	dao.dbName = app.Config.DBName
+
+	index := Index{
+		Key:    []string{"address", "nonce"},
+		Unique: true,
+	}
+
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
+	if err != nil {
+		panic(err)
+	}
+
+	// the nonces are removed by mongoDB once they expire
+	i1 := Index{
+		Key:         []string{"expiresAt"},
+		ExpireAfter: time.Second,
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i1)
+	if err != nil {
+		panic(err)
+	}
+
+	return dao
+}
+
+// Use stores the nonce of an address until expiresAt. It returns false if the nonce
+// was already used by the address
+func (dao *RequestNonceDao) Use(addr common.Address, nonce string, expiresAt time.Time) (bool, error) {
+	err := dao.db.Create(dao.dbName, dao.collectionName, bson.M{
+		"address":   addr.Hex(),
+		"nonce":     nonce,
+		"expiresAt": expiresAt,
+	})
+
+	if IsDup(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Drop drops all the nonces in the current database
+func (dao *RequestNonceDao) Drop() error {
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
@@ -1,252 +1,477 @@
 package daos
 
 import (
-	"reflect"
+	"context"
+	"strings"
+	"time"
 
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// Database struct contains the pointer to mgo.session
-// It is a wrapper over mgo to help utilize mgo connection pool
+// defaultTimeout is the timeout of the queries when the config does not set one
+const defaultTimeout = 30 * time.Second
+
+// the error codes of MongoDB when a change stream can not be resumed after an event which is
+// not in the oplog anymore
+const (
+	changeStreamFatalError  = 280
+	changeStreamHistoryLost = 286
+)
+
+// Database struct contains the client of the official MongoDB driver, whose connection
+// pool is shared by all the queries
 type Database struct {
-	Session *mgo.Session
+	client  *mongo.Client
+	timeout time.Duration
 }
 
-// Global instance of Database struct for singleton use
-var db *Database
 var logger = utils.Logger
 
-// InitSession initializes a new session with mongodb
-func InitSession(session *mgo.Session) (*mgo.Session, error) {
-	if db == nil {
-		if session == nil {
-			db1, err := mgo.Dial(app.Config.MongoURL)
-			if err != nil {
-				logger.Error(err)
-				return nil, err
-			}
+// InitSession returns a Database connected to the MongoDB of the config. The DAOs are
+// created with the Database they use
+func InitSession() (*Database, error) {
+	return Connect(app.Config.MongoURL)
+}
 
-			session = db1
-		}
+// Connect returns a Database connected to the MongoDB of a URL. The mongodb:// scheme can be
+// left out of the URL
+func Connect(url string) (*Database, error) {
+	if !strings.HasPrefix(url, "mongodb://") && !strings.HasPrefix(url, "mongodb+srv://") {
+		url = "mongodb://" + url
+	}
+
+	opts := options.Client().ApplyURI(url)
+
+	timeout := app.Config.MongoTimeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-		db = &Database{session}
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
 	}
-	return db.Session, nil
+
+	err = client.Ping(ctx, nil)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &Database{client: client, timeout: timeout}, nil
 }
 
-func (d *Database) InitDatabase(session *mgo.Session) {
-	d.Session = session
+// Close closes the connections of the Database
+func (d *Database) Close() error {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	return d.client.Disconnect(ctx)
 }
 
-func (d *Database) GetCollection(dbName, collection string) *mgo.Collection {
-	s := d.Session
+// context returns the context of a query, which is cancelled after the timeout
+func (d *Database) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), d.timeout)
+}
+
+func (d *Database) collection(dbName, collection string) *mongo.Collection {
+	return d.client.Database(dbName).Collection(collection)
+}
+
+// EnsureIndex creates an index on a collection if it does not exist yet
+func (d *Database) EnsureIndex(dbName, collection string, index Index) error {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	opts := options.Index()
+	if index.Name != "" {
+		opts.SetName(index.Name)
+	}
+
+	if index.Unique {
+		opts.SetUnique(true)
+	}
+
+	if index.Sparse {
+		opts.SetSparse(true)
+	}
+
+	if index.Background {
+		opts.SetBackground(true)
+	}
+
+	if index.ExpireAfter > 0 {
+		opts.SetExpireAfterSeconds(int32(index.ExpireAfter / time.Second))
+	}
+
+	if index.Collation != nil {
+		opts.SetCollation(&options.Collation{
+			Locale:          index.Collation.Locale,
+			NumericOrdering: index.Collation.NumericOrdering,
+		})
+	}
+
+	_, err := d.collection(dbName, collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    sortDocument(index.Key),
+		Options: opts,
+	})
+
+	return err
+}
+
+// Indexes returns the names and the keys of the indexes of a collection
+func (d *Database) Indexes(dbName, collection string) ([]Index, error) {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	cursor, err := d.collection(dbName, collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := []struct {
+		Name   string `bson:"name"`
+		Key    bson.D `bson:"key"`
+		Unique bool   `bson:"unique"`
+		Sparse bool   `bson:"sparse"`
+	}{}
+
+	err = cursor.All(ctx, &specs)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := []Index{}
+	for _, spec := range specs {
+		index := Index{Name: spec.Name, Unique: spec.Unique, Sparse: spec.Sparse}
+		for _, e := range spec.Key {
+			if order, ok := e.Value.(int32); ok && order < 0 {
+				index.Key = append(index.Key, "-"+e.Key)
+			} else {
+				index.Key = append(index.Key, e.Key)
+			}
+		}
+
+		indexes = append(indexes, index)
+	}
 
-	return s.DB(dbName).C(collection)
+	return indexes, nil
 }
 
-// Create is a wrapper for mgo.Insert function.
-// It creates a copy of session initialized, sends query over this session
-// and returns the session to connection pool
+// Create inserts documents in a collection
 func (d *Database) Create(dbName, collection string, data ...interface{}) (err error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
+	if len(data) == 0 {
+		return nil
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
 
-	err = sc.DB(dbName).C(collection).Insert(data...)
-	return
+	_, err = d.collection(dbName, collection).InsertMany(ctx, data)
+	return storeError(err)
 }
 
-func (d *Database) Watch(dbName, collection string, options mgo.ChangeStreamOptions) (*mgo.ChangeStream, *mgo.Session, error) {
-	sc := d.Session.Copy()
+// Watch opens a change stream on a collection. The stream is resumed by the driver after the
+// network errors, and after the event of the ResumeAfter option when it is opened again
+func (d *Database) Watch(dbName, collection string, opts WatchOptions) (interfaces.ChangeStream, error) {
+	ctx, cancel := d.context()
+	defer cancel()
 
-	pipeline := []bson.M{}
+	csOpts := options.ChangeStream().SetMaxAwaitTime(opts.MaxAwaitTime)
+	if opts.BatchSize > 0 {
+		csOpts.SetBatchSize(opts.BatchSize)
+	}
 
-	ct, err := sc.DB(dbName).C(collection).Watch(pipeline, options)
+	if opts.FullDocument {
+		csOpts.SetFullDocument(options.UpdateLookup)
+	}
 
-	return ct, sc, err
+	if len(opts.ResumeAfter) > 0 {
+		csOpts.SetResumeAfter(bson.Raw(opts.ResumeAfter))
+	}
+
+	cs, err := d.collection(dbName, collection).Watch(ctx, mongo.Pipeline{}, csOpts)
+	if se, ok := err.(mongo.ServerError); ok && (se.HasErrorCode(changeStreamHistoryLost) || se.HasErrorCode(changeStreamFatalError)) {
+		return nil, ErrResumeTokenNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &changeStream{cs: cs, timeout: d.timeout}, nil
 }
 
-func (d *Database) Count(dbName, collection string, query interface{}) (int, error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
+// changeStream is a change stream of the driver, whose Next returns false with no error once
+// the max await time of the stream is over if there is no change
+type changeStream struct {
+	cs      *mongo.ChangeStream
+	timeout time.Duration
+	err     error
+}
+
+// Next implements interfaces.ChangeStream
+func (c *changeStream) Next(result interface{}) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
 
-	return sc.DB(dbName).C(collection).Find(query).Count()
+	if !c.cs.TryNext(ctx) {
+		return false
+	}
+
+	c.err = c.cs.Decode(result)
+	return c.err == nil
 }
 
-// GetByID is a wrapper for mgo.FindId function.
-// It creates a copy of session initialized, sends query over this session
-// and returns the session to connection pool
-func (d *Database) GetByID(dbName, collection string, id bson.ObjectId, response interface{}) (err error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
+// Err implements interfaces.ChangeStream
+func (c *changeStream) Err() error {
+	if c.err != nil {
+		return c.err
+	}
 
-	err = sc.DB(dbName).C(collection).FindId(id).One(response)
-	return
+	return c.cs.Err()
 }
 
-// Get is a wrapper for mgo.Find function.
-// It creates a copy of session initialized, sends query over this session
-// and returns the session to connection pool
-func (d *Database) Get(dbName, collection string, query interface{}, offset, limit int, response interface{}) (err error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
+// Close implements interfaces.ChangeStream
+func (c *changeStream) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
 
-	err = sc.DB(dbName).C(collection).Find(query).Skip(offset).Limit(limit).All(response)
-	return
+	return c.cs.Close(ctx)
 }
 
-// GetOne return one document
-func (d *Database) GetOne(dbName, collection string, query interface{}, response interface{}) (err error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
+// ResumeToken implements interfaces.ChangeStream
+func (c *changeStream) ResumeToken() []byte {
+	return c.cs.ResumeToken()
+}
+
+// Count returns the number of documents matching a query
+func (d *Database) Count(dbName, collection string, query interface{}) (int, error) {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	n, err := d.collection(dbName, collection).CountDocuments(ctx, filter(query))
+	return int(n), err
+}
 
-	err = sc.DB(dbName).C(collection).Find(query).One(response)
-	// logger.Debug(err, query)
-	return
+// GetByID returns the document of an ID
+func (d *Database) GetByID(dbName, collection string, id primitive.ObjectID, response interface{}) (err error) {
+	return d.GetOne(dbName, collection, bson.M{"_id": id}, response)
 }
 
-func (d *Database) Query(dbName, collection string, query interface{}, selector interface{}, offset, limit int, response interface{}) (err error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
+// Get returns the documents matching a query
+func (d *Database) Get(dbName, collection string, query interface{}, offset, limit int, response interface{}) (err error) {
+	return d.GetAndSort(dbName, collection, query, nil, offset, limit, response)
+}
+
+// GetOne return one document
+func (d *Database) GetOne(dbName, collection string, query interface{}, response interface{}) (err error) {
+	ctx, cancel := d.context()
+	defer cancel()
 
-	err = sc.DB(dbName).C(collection).Find(query).Skip(offset).Limit(limit).Select(selector).All(response)
-	return
+	err = d.collection(dbName, collection).FindOne(ctx, filter(query)).Decode(response)
+	return storeError(err)
 }
 
-// GetAndSort is a wrapper for mgo.Find function with SORT function in pipeline.
-// It creates a copy of session initialized, sends query over this session
-// and returns the session to connection pool
+// GetAndSort returns the documents matching a query in the order of sort, where the fields
+// prefixed with - are in descending order
 func (d *Database) GetAndSort(dbName, collection string, query interface{}, sort []string, offset, limit int, response interface{}) (err error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
+	ctx, cancel := d.context()
+	defer cancel()
 
-	err = sc.DB(dbName).C(collection).Find(query).Sort(sort...).Skip(offset).Limit(limit).All(response)
-	return
+	opts := options.Find().SetSkip(int64(offset)).SetLimit(int64(limit))
+	if len(sort) > 0 {
+		opts.SetSort(sortDocument(sort))
+	}
+
+	cursor, err := d.collection(dbName, collection).Find(ctx, filter(query), opts)
+	if err != nil {
+		return err
+	}
+
+	return cursor.All(ctx, response)
 }
 
-// GetEx extend get function
+// GetEx extend get function with the number of documents matching the query
 func (d *Database) GetEx(dbName, collection string, query interface{}, sort []string, offset, limit int, response interface{}) (count int, err error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
-	cursor := sc.DB(dbName).C(collection).Find(query).Sort(sort...)
-	c, _ := cursor.Count()
-	err = cursor.Skip(offset).Limit(limit).All(response)
-	return c, err
-}
-
-// GetSortOne is a wrapper for mgo.Find function with SORT function in pipeline.
-// It creates a copy of session initialized, sends query over this session
-// and returns the session to connection pool
-func (d *Database) GetSortOne(dbName, collection string, query interface{}, sort []string, response interface{}) (err error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
-	err = sc.DB(dbName).C(collection).Find(query).Sort(sort...).One(response)
-	return
-}
-
-// Update is a wrapper for mgo.Update function.
-// It creates a copy of session initialized, sends query over this session
-// and returns the session to connection pool
-func (d *Database) Update(dbName, collection string, query interface{}, update interface{}) error {
-	sc := d.Session.Copy()
-	defer sc.Close()
+	count, err = d.Count(dbName, collection, query)
+	if err != nil {
+		return 0, err
+	}
+
+	err = d.GetAndSort(dbName, collection, query, sort, offset, limit, response)
+	return count, err
+}
 
-	err := sc.DB(dbName).C(collection).Update(query, update)
+// Update updates the first document matching a query with update operators, or replaces it
+// with a document
+func (d *Database) Update(dbName, collection string, query interface{}, update interface{}) error {
+	res, err := d.updateOne(dbName, collection, query, update, false)
 	if err != nil {
 		logger.Error(err)
 		return err
 	}
 
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
 	return nil
 }
 
+// Upsert updates the first document matching a query, or inserts it if none does. It returns
+// the ID of the inserted document
 func (d *Database) Upsert(dbName, collection string, query interface{}, update interface{}) (interface{}, error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
-
-	changed, err := sc.DB(dbName).C(collection).Upsert(query, update)
+	res, err := d.updateOne(dbName, collection, query, update, true)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
-	return changed.UpsertedId, nil
+
+	return res.UpsertedID, nil
 }
 
-func (d *Database) UpdateAll(dbName, collection string, query interface{}, update interface{}) error {
-	sc := d.Session.Copy()
-	defer sc.Close()
+func (d *Database) updateOne(dbName, collection string, query interface{}, update interface{}, upsert bool) (*mongo.UpdateResult, error) {
+	ctx, cancel := d.context()
+	defer cancel()
 
-	_, err := sc.DB(dbName).C(collection).UpdateAll(query, update)
+	doc, ops, err := updateDocument(update)
 	if err != nil {
-		logger.Error(err)
-		return err
+		return nil, err
 	}
 
-	return nil
+	c := d.collection(dbName, collection)
+	if ops {
+		res, err := c.UpdateOne(ctx, filter(query), doc, options.Update().SetUpsert(upsert))
+		return res, storeError(err)
+	}
+
+	res, err := c.ReplaceOne(ctx, filter(query), doc, options.Replace().SetUpsert(upsert))
+	return res, storeError(err)
 }
 
-// ChangeAll update all document and return change information
-func (d *Database) ChangeAll(dbName, collection string, query interface{}, update interface{}) (*mgo.ChangeInfo, error) {
-	sc := d.Session.Copy()
-	defer sc.Close()
+// BulkUpsert upserts documents in one request. pairs holds the selector and the update of
+// each document, in that order
+func (d *Database) BulkUpsert(dbName, collection string, pairs ...interface{}) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := d.context()
+	defer cancel()
+
+	models := []mongo.WriteModel{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		doc, ops, err := updateDocument(pairs[i+1])
+		if err != nil {
+			return err
+		}
 
-	changeInfo, err := sc.DB(dbName).C(collection).UpdateAll(query, update)
+		if ops {
+			models = append(models, mongo.NewUpdateOneModel().SetFilter(filter(pairs[i])).SetUpdate(doc).SetUpsert(true))
+		} else {
+			models = append(models, mongo.NewReplaceOneModel().SetFilter(filter(pairs[i])).SetReplacement(doc).SetUpsert(true))
+		}
+	}
+
+	_, err := d.collection(dbName, collection).BulkWrite(ctx, models)
 	if err != nil {
 		logger.Error(err)
-		return nil, err
+		return storeError(err)
 	}
 
-	return changeInfo, nil
+	return nil
 }
 
-func (d *Database) FindAndModify(dbName, collection string, query interface{}, change mgo.Change, response interface{}) error {
-	sc := d.Session.Copy()
-	defer sc.Close()
+// UpdateAll updates all the documents matching a query with update operators
+func (d *Database) UpdateAll(dbName, collection string, query interface{}, update interface{}) error {
+	_, err := d.ChangeAll(dbName, collection, query, update)
+	return err
+}
 
-	_, err := sc.DB(dbName).C(collection).Find(query).Apply(change, response)
+// ChangeAll update all document and return change information
+func (d *Database) ChangeAll(dbName, collection string, query interface{}, update interface{}) (*ChangeInfo, error) {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	res, err := d.collection(dbName, collection).UpdateMany(ctx, filter(query), update)
 	if err != nil {
 		logger.Error(err)
-		return err
+		return nil, storeError(err)
 	}
 
-	return nil
+	return &ChangeInfo{Matched: int(res.MatchedCount), Updated: int(res.ModifiedCount)}, nil
 }
 
-// AggregateEx add collation
-func (d *Database) AggregateEx(dbName, collection string, query []bson.M, response interface{}) error {
-	sc := d.Session.Copy()
-	defer sc.Close()
+// FindAndModify updates, replaces or removes the first document matching a query, and decodes
+// the original or the updated document into response
+func (d *Database) FindAndModify(dbName, collection string, query interface{}, change Change, response interface{}) error {
+	ctx, cancel := d.context()
+	defer cancel()
+
+	c := d.collection(dbName, collection)
+
+	var res *mongo.SingleResult
+	if change.Remove {
+		res = c.FindOneAndDelete(ctx, filter(query))
+	} else {
+		doc, ops, err := updateDocument(change.Update)
+		if err != nil {
+			return err
+		}
 
-	result := reflect.ValueOf(response).Interface()
-	c := mgo.Collation{
-		NumericOrdering: true,
-		Locale:          "en_US",
+		returned := options.Before
+		if change.ReturnNew {
+			returned = options.After
+		}
+
+		if ops {
+			res = c.FindOneAndUpdate(ctx, filter(query), doc, options.FindOneAndUpdate().SetUpsert(change.Upsert).SetReturnDocument(returned))
+		} else {
+			res = c.FindOneAndReplace(ctx, filter(query), doc, options.FindOneAndReplace().SetUpsert(change.Upsert).SetReturnDocument(returned))
+		}
 	}
-	err := sc.DB(dbName).C(collection).Pipe(query).Collation(&c).All(result)
-	if err != nil {
+
+	err := res.Decode(response)
+	if err == mongo.ErrNoDocuments && change.Upsert && !change.ReturnNew {
+		// the document was inserted, and there is no original document
+		return nil
+	}
+
+	if err != nil && err != mongo.ErrNoDocuments {
 		logger.Error(err)
-		return err
 	}
 
-	return nil
+	return storeError(err)
 }
 
-// Aggregate is a wrapper for mgo.Pipe function.
-// It is used to make mongo aggregate pipeline queries
-// It creates a copy of session initialized, sends query over this session
-// and returns the session to connection pool
+// Aggregate runs an aggregation pipeline, comparing the strings which are numbers as numbers
 func (d *Database) Aggregate(dbName, collection string, query []bson.M, response interface{}) error {
-	return d.AggregateEx(dbName, collection, query, response)
-}
+	ctx, cancel := d.context()
+	defer cancel()
 
-// Remove removes one document matching a certain query
-func (d *Database) Remove(dbName, collection string, query []bson.M) error {
-	sc := d.Session.Copy()
-	defer sc.Close()
+	opts := options.Aggregate().SetCollation(&options.Collation{
+		NumericOrdering: true,
+		Locale:          "en_US",
+	})
 
-	err := sc.DB(dbName).C(collection).Remove(query)
+	cursor, err := d.collection(dbName, collection).Aggregate(ctx, query, opts)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	err = cursor.All(ctx, response)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -255,26 +480,30 @@ func (d *Database) Remove(dbName, collection string, query []bson.M) error {
 	return nil
 }
 
-// Remove removes one document matching a certain query
+// RemoveItem removes one document matching a certain query
 func (d *Database) RemoveItem(dbName, collection string, query interface{}) error {
-	sc := d.Session.Copy()
-	defer sc.Close()
+	ctx, cancel := d.context()
+	defer cancel()
 
-	err := sc.DB(dbName).C(collection).Remove(query)
+	res, err := d.collection(dbName, collection).DeleteOne(ctx, filter(query))
 	if err != nil {
 		logger.Error(err)
 		return err
 	}
 
+	if res.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
 	return nil
 }
 
 // RemoveAll removes all the documents from a collection matching a certain query
 func (d *Database) RemoveAll(dbName, collection string, query interface{}) error {
-	sc := d.Session.Copy()
-	defer sc.Close()
+	ctx, cancel := d.context()
+	defer cancel()
 
-	_, err := sc.DB(dbName).C(collection).RemoveAll(query)
+	_, err := d.collection(dbName, collection).DeleteMany(ctx, filter(query))
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -285,10 +514,10 @@ func (d *Database) RemoveAll(dbName, collection string, query interface{}) error
 
 // DropCollection drops all the documents in a collection
 func (d *Database) DropCollection(dbName, collection string) error {
-	sc := d.Session.Copy()
-	defer sc.Close()
+	ctx, cancel := d.context()
+	defer cancel()
 
-	err := sc.DB(dbName).C(collection).DropCollection()
+	err := d.collection(dbName, collection).Drop(ctx)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -296,3 +525,58 @@ func (d *Database) DropCollection(dbName, collection string) error {
 
 	return nil
 }
+
+// filter returns the filter of a query, which matches all the documents if the query is nil
+func filter(query interface{}) interface{} {
+	if query == nil {
+		return bson.M{}
+	}
+
+	return query
+}
+
+// sortDocument returns the sort document of fields, where the fields prefixed with - are in
+// descending order
+func sortDocument(fields []string) bson.D {
+	doc := bson.D{}
+	for _, f := range fields {
+		order := 1
+		if strings.HasPrefix(f, "-") {
+			order = -1
+		}
+
+		doc = append(doc, bson.E{Key: strings.TrimLeft(f, "+-"), Value: order})
+	}
+
+	return doc
+}
+
+// updateDocument encodes an update, and tells whether it is made of update operators or is a
+// replacement document
+func updateDocument(update interface{}) (bson.Raw, bool, error) {
+	doc, err := bson.Marshal(update)
+	if err != nil {
+		return nil, false, err
+	}
+
+	elems, err := bson.Raw(doc).Elements()
+	if err != nil {
+		return nil, false, err
+	}
+
+	ops := len(elems) > 0 && strings.HasPrefix(elems[0].Key(), "$")
+	return doc, ops, nil
+}
+
+// storeError returns the errors of the driver as the errors of Store
+func storeError(err error) error {
+	if err == mongo.ErrNoDocuments {
+		return ErrNotFound
+	}
+
+	if mongo.IsDuplicateKeyError(err) {
+		return &DuplicateKeyError{Message: err.Error()}
+	}
+
+	return err
+}
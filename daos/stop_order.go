@@ -0,0 +1,243 @@
+package daos
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StopOrderDao contains:
+// collectionName: MongoDB collection name
+// dbName: name of mongodb to interact with
+type StopOrderDao struct {
+	collectionName string
+	dbName         string
+	db             Store
+}
+
+type StopOrderDaoOption = func(*StopOrderDao) error
+
+func StopOrderDaoDBOption(dbName string) func(dao *StopOrderDao) error {
+	return func(dao *StopOrderDao) error {
+		dao.dbName = dbName
+		return nil
+	}
+}
+
+// NewStopOrderDao returns a new instance of StopOrderDao
+func NewStopOrderDao(db Store, opts ...StopOrderDaoOption) *StopOrderDao {
+	dao := &StopOrderDao{db: db}
+	dao.collectionName = "stop_orders"
+	dao.dbName = app.Config.DBName
+
+	for _, op := range opts {
+		err := op(dao)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	index := Index{
+		Key:    []string{"hash"},
+		Unique: true,
+	}
+
+	i1 := Index{
+		Key: []string{"userAddress"},
+	}
+
+	i2 := Index{
+		Key: []string{"baseToken", "quoteToken", "status", "direction", "stopPriceKey"},
+	}
+
+	err := db.EnsureIndex(dao.dbName, dao.collectionName, index)
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i1)
+	if err != nil {
+		panic(err)
+	}
+
+	err = db.EnsureIndex(dao.dbName, dao.collectionName, i2)
+	if err != nil {
+		panic(err)
+	}
+
+	return dao
+}
+
+// Create function performs the DB insertion task for StopOrder collection
+func (dao *StopOrderDao) Create(so *types.StopOrder) error {
+	so.ID = primitive.NewObjectID()
+	so.CreatedAt = time.Now()
+	so.UpdatedAt = time.Now()
+
+	if so.Status == "" {
+		so.Status = types.StopOrderStatusOpen
+	}
+
+	err := dao.db.Create(dao.dbName, dao.collectionName, so)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Update function performs the DB updations task for StopOrder collection
+// corresponding to a particular stop order ID
+func (dao *StopOrderDao) Update(id primitive.ObjectID, so *types.StopOrder) error {
+	so.UpdatedAt = time.Now()
+
+	err := dao.db.Update(dao.dbName, dao.collectionName, bson.M{"_id": id}, so)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateByHash updates fields that are considered updateable for a stop order.
+func (dao *StopOrderDao) UpdateByHash(h common.Hash, so *types.StopOrder) error {
+	so.UpdatedAt = time.Now()
+	query := bson.M{"hash": h.Hex()}
+	update := bson.M{"$set": bson.M{
+		"stopPrice":    so.StopPrice.String(),
+		"limitPrice":   so.LimitPrice.String(),
+		"amount":       so.Amount.String(),
+		"status":       so.Status,
+		"filledAmount": so.FilledAmount.String(),
+		"updatedAt":    so.UpdatedAt,
+	}}
+
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, update)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateOpenStopOrderStatus sets the status of a stop order only if it is still OPEN.
+// It reports whether the stop order was updated, which lets concurrent triggers and
+// cancellations race safely on the same document.
+func (dao *StopOrderDao) UpdateOpenStopOrderStatus(h common.Hash, status string) (bool, error) {
+	query := bson.M{
+		"hash":   h.Hex(),
+		"status": types.StopOrderStatusOpen,
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":    status,
+		"updatedAt": time.Now(),
+	}}
+
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, update)
+	if err == ErrNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetByHash function fetches a single document from stop order collection based on hash.
+// Returns StopOrder type struct
+func (dao *StopOrderDao) GetByHash(hash common.Hash) (*types.StopOrder, error) {
+	q := bson.M{"hash": hash.Hex()}
+	res := []types.StopOrder{}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, nil
+	}
+
+	return &res[0], nil
+}
+
+// GetOpenStopOrdersByUserAddress fetches the stop orders of a user that have not been triggered yet
+func (dao *StopOrderDao) GetOpenStopOrdersByUserAddress(addr common.Address) ([]*types.StopOrder, error) {
+	var res []*types.StopOrder
+
+	q := bson.M{
+		"userAddress": addr.Hex(),
+		"status":      types.StopOrderStatusOpen,
+	}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if res == nil {
+		return []*types.StopOrder{}, nil
+	}
+
+	return res, nil
+}
+
+// GetTriggeredStopOrders returns the open stop orders of a pair whose stop price has been
+// crossed by trades priced between lowPrice and highPrice: rising stop orders at or below
+// the highest price and falling stop orders at or above the lowest price
+func (dao *StopOrderDao) GetTriggeredStopOrders(baseToken, quoteToken common.Address, lowPrice, highPrice *big.Int) ([]*types.StopOrder, error) {
+	var res []*types.StopOrder
+
+	q := bson.M{
+		"baseToken":  baseToken.Hex(),
+		"quoteToken": quoteToken.Hex(),
+		"status":     types.StopOrderStatusOpen,
+		"$or": []bson.M{
+			{
+				"direction":    types.StopOrderDirectionUp,
+				"stopPriceKey": bson.M{"$lte": types.StopPriceKey(highPrice)},
+			},
+			{
+				"direction":    types.StopOrderDirectionDown,
+				"stopPriceKey": bson.M{"$gte": types.StopPriceKey(lowPrice)},
+			},
+		},
+	}
+
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, []string{"createdAt"}, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if res == nil {
+		return []*types.StopOrder{}, nil
+	}
+
+	return res, nil
+}
+
+// Drop drops all the stop order documents in the current database
+func (dao *StopOrderDao) Drop() error {
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package daos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrNotFound is returned by a Store when no document matches a query that needs one
+var ErrNotFound = errors.New("not found")
+
+// ErrResumeTokenNotFound is returned by Watch when the event of the ResumeAfter option is too
+// old to resume the change stream after it
+var ErrResumeTokenNotFound = errors.New("Resume token is not in the change log")
+
+// Store is the storage the DAOs read and write their collections with. Database stores the
+// collections in MongoDB, and MemoryStore keeps them in memory so that the services can be
+// tested and the SDK can be run without MongoDB
+type Store interface {
+	EnsureIndex(dbName, collection string, index Index) error
+	Indexes(dbName, collection string) ([]Index, error)
+	Create(dbName, collection string, data ...interface{}) error
+	Watch(dbName, collection string, options WatchOptions) (interfaces.ChangeStream, error)
+	Count(dbName, collection string, query interface{}) (int, error)
+	GetByID(dbName, collection string, id primitive.ObjectID, response interface{}) error
+	Get(dbName, collection string, query interface{}, offset, limit int, response interface{}) error
+	GetOne(dbName, collection string, query interface{}, response interface{}) error
+	GetAndSort(dbName, collection string, query interface{}, sort []string, offset, limit int, response interface{}) error
+	GetEx(dbName, collection string, query interface{}, sort []string, offset, limit int, response interface{}) (int, error)
+	Update(dbName, collection string, query interface{}, update interface{}) error
+	Upsert(dbName, collection string, query interface{}, update interface{}) (interface{}, error)
+	BulkUpsert(dbName, collection string, pairs ...interface{}) error
+	UpdateAll(dbName, collection string, query interface{}, update interface{}) error
+	ChangeAll(dbName, collection string, query interface{}, update interface{}) (*ChangeInfo, error)
+	FindAndModify(dbName, collection string, query interface{}, change Change, response interface{}) error
+	Aggregate(dbName, collection string, query []bson.M, response interface{}) error
+	RemoveItem(dbName, collection string, query interface{}) error
+	RemoveAll(dbName, collection string, query interface{}) error
+	DropCollection(dbName, collection string) error
+}
+
+// Index is an index of a collection. The keys prefixed with - are in descending order
+type Index struct {
+	Name        string
+	Key         []string
+	Unique      bool
+	Sparse      bool
+	Background  bool
+	ExpireAfter time.Duration
+	Collation   *Collation
+}
+
+// Collation are the rules to compare the strings of an index
+type Collation struct {
+	Locale          string
+	NumericOrdering bool
+}
+
+// Change is the update of a document by FindAndModify. The document is removed if Remove is
+// set, and the updated document is returned instead of the original one if ReturnNew is set
+type Change struct {
+	Update    interface{}
+	Upsert    bool
+	Remove    bool
+	ReturnNew bool
+}
+
+// ChangeInfo is the number of documents matched and updated by an update
+type ChangeInfo struct {
+	Matched    int
+	Updated    int
+	UpsertedID interface{}
+}
+
+// WatchOptions are the options of a change stream. The updates come with the full document if
+// FullDocument is set, and the stream starts after the event of ResumeAfter if it is not empty
+type WatchOptions struct {
+	FullDocument bool
+	MaxAwaitTime time.Duration
+	BatchSize    int32
+	ResumeAfter  []byte
+}
+
+// DuplicateKeyError is returned by a Store when a write breaks a unique index
+type DuplicateKeyError struct {
+	Message string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return e.Message
+}
+
+// IsDup checks that an error is a duplicate key error
+func IsDup(err error) bool {
+	_, ok := err.(*DuplicateKeyError)
+	return ok
+}
@@ -5,10 +5,10 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // TokenDao contains:
@@ -17,46 +17,47 @@ import (
 type TokenDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
 // NewTokenDao returns a new instance of TokenDao.
-func NewTokenDao() *TokenDao {
+func NewTokenDao(db Store) *TokenDao {
 	dbName := app.Config.DBName
 	collection := "tokens"
-	index := mgo.Index{
+	index := Index{
 		Key:    []string{"contractAddress", "relayerAddress"},
 		Unique: true,
 	}
 
-	db.Session.DB(dbName).C(collection).EnsureIndex(index)
+	db.EnsureIndex(dbName, collection, index)
 
-	return &TokenDao{collection, dbName}
+	return &TokenDao{collection, dbName, db}
 }
 
 // NewLendingTokenDao lending dao
-func NewLendingTokenDao() *TokenDao {
+func NewLendingTokenDao(db Store) *TokenDao {
 	dbName := app.Config.DBName
 	collection := "lending_tokens"
-	index := mgo.Index{
+	index := Index{
 		Key:    []string{"contractAddress", "relayerAddress"},
 		Unique: true,
 	}
 
-	db.Session.DB(dbName).C(collection).EnsureIndex(index)
+	db.EnsureIndex(dbName, collection, index)
 
-	return &TokenDao{collection, dbName}
+	return &TokenDao{collection, dbName, db}
 }
 
 // NewCollateralTokenDao lending dao
-func NewCollateralTokenDao() *TokenDao {
+func NewCollateralTokenDao(db Store) *TokenDao {
 	dbName := app.Config.DBName
 	collection := "collateral_tokens"
-	index := mgo.Index{
+	index := Index{
 		Key:    []string{"contractAddress", "relayerAddress"},
 		Unique: true,
 	}
-	db.Session.DB(dbName).C(collection).EnsureIndex(index)
-	return &TokenDao{collection, dbName}
+	db.EnsureIndex(dbName, collection, index)
+	return &TokenDao{collection, dbName, db}
 }
 
 // Create function performs the DB insertion task for token collection
@@ -66,11 +67,11 @@ func (dao *TokenDao) Create(token *types.Token) error {
 		return err
 	}
 
-	token.ID = bson.NewObjectId()
+	token.ID = primitive.NewObjectID()
 	token.CreatedAt = time.Now()
 	token.UpdatedAt = time.Now()
 
-	err := db.Create(dao.dbName, dao.collectionName, token)
+	err := dao.db.Create(dao.dbName, dao.collectionName, token)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -82,7 +83,7 @@ func (dao *TokenDao) Create(token *types.Token) error {
 // GetAll function fetches all the tokens in the token collection of mongodb.
 func (dao *TokenDao) GetAll() ([]types.Token, error) {
 	var res []types.Token
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -104,7 +105,7 @@ func (dao *TokenDao) GetAll() ([]types.Token, error) {
 
 func (dao *TokenDao) GetAllByCoinbase(addr common.Address) ([]types.Token, error) {
 	var response []types.Token
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{"relayerAddress": addr.Hex()}, 0, 0, &response)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{"relayerAddress": addr.Hex()}, 0, 0, &response)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -116,7 +117,7 @@ func (dao *TokenDao) GetAllByCoinbase(addr common.Address) ([]types.Token, error
 // GetQuote function fetches all the quote tokens in the token collection of mongodb.
 func (dao *TokenDao) GetQuoteTokens() ([]types.Token, error) {
 	var response []types.Token
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{"quote": true}, 0, 0, &response)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{"quote": true}, 0, 0, &response)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -128,7 +129,7 @@ func (dao *TokenDao) GetQuoteTokens() ([]types.Token, error) {
 // GetBase function fetches all the base tokens in the token collection of mongodb.
 func (dao *TokenDao) GetBaseTokens() ([]types.Token, error) {
 	var response []types.Token
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{"quote": false}, 0, 0, &response)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{"quote": false}, 0, 0, &response)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -138,9 +139,9 @@ func (dao *TokenDao) GetBaseTokens() ([]types.Token, error) {
 }
 
 // GetByID function fetches details of a token based on its mongo id
-func (dao *TokenDao) GetByID(id bson.ObjectId) (*types.Token, error) {
+func (dao *TokenDao) GetByID(id primitive.ObjectID) (*types.Token, error) {
 	var response *types.Token
-	err := db.GetByID(dao.dbName, dao.collectionName, id, &response)
+	err := dao.db.GetByID(dao.dbName, dao.collectionName, id, &response)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -154,7 +155,7 @@ func (dao *TokenDao) GetByAddress(addr common.Address) (*types.Token, error) {
 	q := bson.M{"contractAddress": addr.Hex()}
 	var resp []types.Token
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &resp)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &resp)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -171,7 +172,7 @@ func (dao *TokenDao) UpdateFiatPriceBySymbol(symbol string, price float64) error
 	q := bson.M{"symbol": symbol}
 	update := bson.M{"$set": bson.M{"usd": fmt.Sprintf("%f", price)}}
 
-	err := db.Update(dao.dbName, dao.collectionName, q, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -189,7 +190,7 @@ func (dao *TokenDao) UpdateByToken(addr common.Address, token *types.Token) erro
 			"takeFee": token.TakeFee.String(),
 		},
 	}
-	err := db.Update(dao.dbName, dao.collectionName, q, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -207,7 +208,7 @@ func (dao *TokenDao) UpdateByTokenAndCoinbase(addr common.Address, coinbase comm
 			"takeFee": token.TakeFee.String(),
 		},
 	}
-	err := db.Update(dao.dbName, dao.collectionName, q, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, q, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -218,7 +219,7 @@ func (dao *TokenDao) UpdateByTokenAndCoinbase(addr common.Address, coinbase comm
 
 // Drop drops all the order documents in the current database
 func (dao *TokenDao) Drop() error {
-	err := db.DropCollection(dao.dbName, dao.collectionName)
+	err := dao.db.DropCollection(dao.dbName, dao.collectionName)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -230,10 +231,10 @@ func (dao *TokenDao) Drop() error {
 // DeleteByToken delete token by contract address
 func (dao *TokenDao) DeleteByToken(contractAddress common.Address) error {
 	query := bson.M{"contractAddress": contractAddress.Hex()}
-	return db.RemoveItem(dao.dbName, dao.collectionName, query)
+	return dao.db.RemoveItem(dao.dbName, dao.collectionName, query)
 }
 
 func (dao *TokenDao) DeleteByTokenAndCoinbase(contractAddress common.Address, addr common.Address) error {
 	query := bson.M{"contractAddress": contractAddress.Hex(), "relayerAddress": addr.Hex()}
-	return db.RemoveItem(dao.dbName, dao.collectionName, query)
+	return dao.db.RemoveItem(dao.dbName, dao.collectionName, query)
 }
@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package daos
 
 import (
@@ -14,12 +17,16 @@ func init() {
 	temp, _ := ioutil.TempDir("", "test")
 	server.SetPath(temp)
 
-	session := server.Session()
-	db = &Database{Session: session}
+	d, err := Connect(server.URI())
+	if err != nil {
+		panic(err)
+	}
+
+	db = d
 }
 
 func TestTokenDao(t *testing.T) {
-	dao := NewTokenDao()
+	dao := NewTokenDao(db)
 	dao.Drop()
 
 	token := &types.Token{
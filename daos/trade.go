@@ -5,10 +5,11 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // TradeDao contains:
@@ -17,82 +18,82 @@ import (
 type TradeDao struct {
 	collectionName string
 	dbName         string
+	db             Store
 }
 
 // NewTradeDao returns a new instance of TradeDao.
-func NewTradeDao() *TradeDao {
+func NewTradeDao(db Store) *TradeDao {
 	dbName := app.Config.DBName
 	collection := "trades"
 
-	i1 := mgo.Index{
+	i1 := Index{
 		Key: []string{"baseToken"},
 	}
 
-	i2 := mgo.Index{
+	i2 := Index{
 		Key: []string{"quoteToken"},
 	}
 
-	i3 := mgo.Index{
+	i3 := Index{
 		Key: []string{"createdAt"},
 	}
 
-	i4 := mgo.Index{
+	i4 := Index{
 		Key:    []string{"hash"},
 		Unique: true,
 		Sparse: true,
 	}
 
-	i5 := mgo.Index{
+	i5 := Index{
 		Key:    []string{"makerOrderHash"},
 		Sparse: true,
 	}
 
-	i6 := mgo.Index{
+	i6 := Index{
 		Key:    []string{"takerOrderHash"},
 		Sparse: true,
 	}
 
-	i7 := mgo.Index{
+	i7 := Index{
 		Key: []string{"createdAt", "status", "baseToken", "quoteToken"},
 	}
 
-	i8 := mgo.Index{
+	i8 := Index{
 		Key:       []string{"pricepoint"},
-		Collation: &mgo.Collation{NumericOrdering: true, Locale: "en"},
+		Collation: &Collation{NumericOrdering: true, Locale: "en"},
 	}
-	i9 := mgo.Index{
+	i9 := Index{
 		Key:    []string{"createdAt"},
 		Sparse: true,
 	}
-	indexes := []mgo.Index{}
-	indexes, err := db.Session.DB(dbName).C(collection).Indexes()
+	indexes := []Index{}
+	indexes, err := db.Indexes(dbName, collection)
 	if err == nil {
 		if !existedIndex("index_trade_hash", indexes) {
-			db.Session.DB(dbName).C(collection).EnsureIndex(i4)
+			db.EnsureIndex(dbName, collection, i4)
 		}
 	}
 
-	db.Session.DB(dbName).C(collection).EnsureIndex(i1)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i2)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i3)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i5)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i6)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i7)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i8)
-	db.Session.DB(dbName).C(collection).EnsureIndex(i9)
+	db.EnsureIndex(dbName, collection, i1)
+	db.EnsureIndex(dbName, collection, i2)
+	db.EnsureIndex(dbName, collection, i3)
+	db.EnsureIndex(dbName, collection, i5)
+	db.EnsureIndex(dbName, collection, i6)
+	db.EnsureIndex(dbName, collection, i7)
+	db.EnsureIndex(dbName, collection, i8)
+	db.EnsureIndex(dbName, collection, i9)
 
-	return &TradeDao{collection, dbName}
+	return &TradeDao{collection, dbName, db}
 }
 
-func (dao *TradeDao) GetCollection() *mgo.Collection {
-	return db.GetCollection(dao.dbName, dao.collectionName)
-}
-
-func (dao *TradeDao) Watch() (*mgo.ChangeStream, *mgo.Session, error) {
-	return db.Watch(dao.dbName, dao.collectionName, mgo.ChangeStreamOptions{
-		FullDocument:   mgo.UpdateLookup,
-		MaxAwaitTimeMS: 500,
-		BatchSize:      1000,
+// Watch opens a change stream on the trades, which starts after the event of resumeAfter if
+// it is not empty
+func (dao *TradeDao) Watch(resumeAfter []byte) (interfaces.ChangeStream, error) {
+	return dao.db.Watch(dao.dbName, dao.collectionName, WatchOptions{
+		FullDocument: true,
+		MaxAwaitTime: 500 * time.Millisecond,
+		BatchSize:    1000,
+		ResumeAfter:  resumeAfter,
 	})
 }
 
@@ -100,16 +101,16 @@ func (dao *TradeDao) Watch() (*mgo.ChangeStream, *mgo.Session, error) {
 // It accepts 1 or more trades as input.
 // All the trades are inserted in one query itself.
 func (dao *TradeDao) Create(trades ...*types.Trade) error {
-	y := make([]interface{}, len(trades))
+	y := make([]interface{}, 0, len(trades))
 
 	for _, trade := range trades {
-		trade.ID = bson.NewObjectId()
+		trade.ID = primitive.NewObjectID()
 		trade.CreatedAt = time.Now()
 		trade.UpdatedAt = time.Now()
 		y = append(y, trade)
 	}
 
-	err := db.Create(dao.dbName, dao.collectionName, y...)
+	err := dao.db.Create(dao.dbName, dao.collectionName, y...)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -119,7 +120,7 @@ func (dao *TradeDao) Create(trades ...*types.Trade) error {
 }
 
 func (dao *TradeDao) DeleteByHashes(hashes ...common.Hash) error {
-	err := db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
+	err := dao.db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -134,7 +135,7 @@ func (dao *TradeDao) Delete(trades ...*types.Trade) error {
 		hashes = append(hashes, t.Hash)
 	}
 
-	err := db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
+	err := dao.db.RemoveAll(dao.dbName, dao.collectionName, bson.M{"hash": bson.M{"$in": hashes}})
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -145,7 +146,7 @@ func (dao *TradeDao) Delete(trades ...*types.Trade) error {
 
 func (dao *TradeDao) Update(trade *types.Trade) error {
 	trade.UpdatedAt = time.Now()
-	err := db.Update(dao.dbName, dao.collectionName, bson.M{"_id": trade.ID}, trade)
+	err := dao.db.Update(dao.dbName, dao.collectionName, bson.M{"_id": trade.ID}, trade)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -154,10 +155,10 @@ func (dao *TradeDao) Update(trade *types.Trade) error {
 	return nil
 }
 
-func (dao *TradeDao) Upsert(id bson.ObjectId, t *types.Trade) error {
+func (dao *TradeDao) Upsert(id primitive.ObjectID, t *types.Trade) error {
 	t.UpdatedAt = time.Now()
 
-	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"_id": id}, t)
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{"_id": id}, t)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -169,7 +170,7 @@ func (dao *TradeDao) Upsert(id bson.ObjectId, t *types.Trade) error {
 func (dao *TradeDao) UpsertByHash(h common.Hash, t *types.Trade) error {
 	t.UpdatedAt = time.Now()
 
-	_, err := db.Upsert(dao.dbName, dao.collectionName, bson.M{"hash": h}, t)
+	_, err := dao.db.Upsert(dao.dbName, dao.collectionName, bson.M{"hash": h}, t)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -182,14 +183,14 @@ func (dao *TradeDao) FindAndModify(h common.Hash, t *types.Trade) (*types.Trade,
 	t.UpdatedAt = time.Now()
 	query := bson.M{"hash": h.Hex()}
 	updated := &types.Trade{}
-	change := mgo.Change{
-		Update:    types.TradeBSONUpdate{t},
+	change := Change{
+		Update:    types.TradeBSONUpdate{Trade: t},
 		Upsert:    true,
 		Remove:    false,
 		ReturnNew: true,
 	}
 
-	err := db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
+	err := dao.db.FindAndModify(dao.dbName, dao.collectionName, query, change, &updated)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -212,7 +213,7 @@ func (dao *TradeDao) UpdateByHash(h common.Hash, t *types.Trade) error {
 		"updatedAt":      t.UpdatedAt,
 	}}
 
-	err := db.Update(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -224,7 +225,7 @@ func (dao *TradeDao) UpdateByHash(h common.Hash, t *types.Trade) error {
 // GetAll function fetches all the trades in mongodb
 func (dao *TradeDao) GetAll() ([]types.Trade, error) {
 	var response []types.Trade
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &response)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &response)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -237,7 +238,7 @@ func (dao *TradeDao) GetAll() ([]types.Trade, error) {
 func (dao *TradeDao) Aggregate(q []bson.M) ([]*types.Tick, error) {
 	var res []*types.Tick
 
-	err := db.Aggregate(dao.dbName, dao.collectionName, q, &res)
+	err := dao.db.Aggregate(dao.dbName, dao.collectionName, q, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -249,12 +250,12 @@ func (dao *TradeDao) Aggregate(q []bson.M) ([]*types.Tick, error) {
 // GetByPairName fetches all the trades corresponding to a particular pair name.
 func (dao *TradeDao) GetByPairName(name string) ([]*types.Trade, error) {
 	var res []*types.Trade
-	q := bson.M{"pairName": bson.RegEx{
+	q := bson.M{"pairName": primitive.Regex{
 		Pattern: name,
 		Options: "i",
 	}}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -268,7 +269,7 @@ func (dao *TradeDao) GetByHash(h common.Hash) (*types.Trade, error) {
 	q := bson.M{"hash": h.Hex()}
 
 	res := []*types.Trade{}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -282,7 +283,7 @@ func (dao *TradeDao) GetByMakerOrderHash(h common.Hash) ([]*types.Trade, error)
 	q := bson.M{"makerOrderHash": h.Hex()}
 
 	res := []*types.Trade{}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -295,7 +296,7 @@ func (dao *TradeDao) GetByTakerOrderHash(h common.Hash) ([]*types.Trade, error)
 	q := bson.M{"takerOrderHash": h.Hex()}
 
 	res := []*types.Trade{}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -313,7 +314,7 @@ func (dao *TradeDao) GetByOrderHashes(hashes []common.Hash) ([]*types.Trade, err
 	q := bson.M{"makerOrderHash": bson.M{"$in": hexes}}
 	res := []*types.Trade{}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -345,7 +346,7 @@ func (dao *TradeDao) GetSortedTrades(bt, qt common.Address, from, to int64, n in
 
 	sort := []string{"-createdAt"}
 
-	err := db.GetAndSort(dao.dbName, dao.collectionName, q, sort, 0, n, &res)
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, sort, 0, n, &res)
 
 	if err != nil {
 		logger.Error(err)
@@ -380,7 +381,7 @@ func (dao *TradeDao) GetTradesByPairAddress(bt, qt common.Address, n int) ([]*ty
 	var res []*types.Trade
 
 	q := bson.M{"baseToken": bt.Hex(), "quoteToken": qt.Hex()}
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, n, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, n, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -445,7 +446,7 @@ func (dao *TradeDao) GetSortedTradesByUserAddress(a, bt, qt common.Address, from
 
 	sort := []string{"-createdAt"}
 
-	err := db.GetAndSort(dao.dbName, dao.collectionName, q, sort, 0, limit[0], &res)
+	err := dao.db.GetAndSort(dao.dbName, dao.collectionName, q, sort, 0, limit[0], &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -459,7 +460,7 @@ func (dao *TradeDao) GetByUserAddress(a common.Address) ([]*types.Trade, error)
 	var res []*types.Trade
 	q := bson.M{"$or": []bson.M{{"maker": a.Hex()}, {"taker": a.Hex()}}}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -487,7 +488,7 @@ func (dao *TradeDao) UpdateTradeStatus(h common.Hash, status string) error {
 	query := bson.M{"hash": h.Hex()}
 	update := bson.M{"$set": bson.M{"status": status}}
 
-	err := db.Update(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.Update(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -510,14 +511,14 @@ func (dao *TradeDao) UpdateTradeStatuses(status string, hashes ...common.Hash) (
 		},
 	}
 
-	err := db.UpdateAll(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.UpdateAll(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return nil, nil
 	}
 
 	trades := []*types.Trade{}
-	err = db.Get(dao.dbName, dao.collectionName, query, 0, 0, &trades)
+	err = dao.db.Get(dao.dbName, dao.collectionName, query, 0, 0, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, nil
@@ -540,14 +541,14 @@ func (dao *TradeDao) UpdateTradeStatusesByOrderHashes(status string, hashes ...c
 		},
 	}
 
-	err := db.UpdateAll(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.UpdateAll(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return nil, nil
 	}
 
 	trades := []*types.Trade{}
-	err = db.Get(dao.dbName, dao.collectionName, query, 0, 0, &trades)
+	err = dao.db.Get(dao.dbName, dao.collectionName, query, 0, 0, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, nil
@@ -570,14 +571,14 @@ func (dao *TradeDao) UpdateTradeStatusesByHashes(status string, hashes ...common
 		},
 	}
 
-	err := db.UpdateAll(dao.dbName, dao.collectionName, query, update)
+	err := dao.db.UpdateAll(dao.dbName, dao.collectionName, query, update)
 	if err != nil {
 		logger.Error(err)
 		return nil, nil
 	}
 
 	trades := []*types.Trade{}
-	err = db.Get(dao.dbName, dao.collectionName, query, 0, 0, &trades)
+	err = dao.db.Get(dao.dbName, dao.collectionName, query, 0, 0, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, nil
@@ -588,7 +589,7 @@ func (dao *TradeDao) UpdateTradeStatusesByHashes(status string, hashes ...common
 
 // Drop drops all the order documents in the current database
 func (dao *TradeDao) Drop() {
-	db.DropCollection(dao.dbName, dao.collectionName)
+	dao.db.DropCollection(dao.dbName, dao.collectionName)
 }
 
 // GetTrades filter trade
@@ -614,7 +615,7 @@ func (dao *TradeDao) GetTrades(tradeSpec *types.TradeSpec, sortedBy []string, pa
 
 	var res types.TradeRes
 	trades := []*types.Trade{}
-	c, err := db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
+	c, err := dao.db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -634,7 +635,7 @@ func (dao *TradeDao) GetTradeByTime(dateFrom, dateTo int64, pageOffset int, page
 	q["createdAt"] = dateFilter
 
 	trades := []*types.Trade{}
-	_, err := db.GetEx(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, pageOffset, pageSize, &trades)
+	_, err := dao.db.GetEx(dao.dbName, dao.collectionName, q, []string{"-createdAt"}, pageOffset, pageSize, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -668,7 +669,7 @@ func (dao *TradeDao) GetTradesUserHistory(a common.Address, tradeSpec *types.Tra
 	}
 	var res types.TradeRes
 	trades := []*types.Trade{}
-	c, err := db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
+	c, err := dao.db.GetEx(dao.dbName, dao.collectionName, q, sortedBy, pageOffset, pageSize, &trades)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
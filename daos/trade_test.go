@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package daos
 
 import (
@@ -6,10 +9,10 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/testutils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func init() {
@@ -17,12 +20,16 @@ func init() {
 	temp, _ := ioutil.TempDir("", "test")
 	server.SetPath(temp)
 
-	session := server.Session()
-	db = &Database{Session: session}
+	d, err := Connect(server.URI())
+	if err != nil {
+		panic(err)
+	}
+
+	db = d
 }
 
 func TestTradeDao(t *testing.T) {
-	dao := NewTradeDao()
+	dao := NewTradeDao(db)
 	dao.Drop()
 
 	ZRXAddress := common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498")
@@ -31,7 +38,7 @@ func TestTradeDao(t *testing.T) {
 
 	trs := []*types.Trade{
 		{
-			ID:             bson.ObjectIdHex("537f700b537461b70c5f0001"),
+			ID:             objectID("537f700b537461b70c5f0001"),
 			Maker:          common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 			Taker:          common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 			BaseToken:      ZRXAddress,
@@ -44,7 +51,7 @@ func TestTradeDao(t *testing.T) {
 			Amount:         big.NewInt(100),
 		},
 		{
-			ID:             bson.ObjectIdHex("537f700b537461b70c5f0004"),
+			ID:             objectID("537f700b537461b70c5f0004"),
 			Maker:          common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 			Taker:          common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 			BaseToken:      ZRXAddress,
@@ -57,7 +64,7 @@ func TestTradeDao(t *testing.T) {
 			Amount:         big.NewInt(100),
 		},
 		{
-			ID:             bson.ObjectIdHex("537f700b537461b70c5f0007"),
+			ID:             objectID("537f700b537461b70c5f0007"),
 			Maker:          common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 			Taker:          common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 			BaseToken:      ZRXAddress,
@@ -110,11 +117,11 @@ func TestTradeDao(t *testing.T) {
 }
 
 func TestUpdateTrade(t *testing.T) {
-	dao := NewTradeDao()
+	dao := NewTradeDao(db)
 	dao.Drop()
 
 	tr := &types.Trade{
-		ID:             bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:             objectID("537f700b537461b70c5f0000"),
 		Maker:          common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		Taker:          common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:      common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
@@ -161,3 +168,8 @@ func TestUpdateTrade(t *testing.T) {
 
 	testutils.CompareTrade(t, queried, updated)
 }
+
+func objectID(hex string) primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(hex)
+	return id
+}
@@ -1,24 +1,65 @@
 package daos
 
 import (
+	"crypto/ecdsa"
+	"errors"
+	"sync"
+
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrNoWalletPassphrase is returned when a private key is stored without a wallet passphrase
+var ErrNoWalletPassphrase = errors.New("No passphrase to encrypt the wallet keys with")
+
 // TokenDao contains:
 // collectionName: MongoDB collection name
 // dbName: name of mongodb to interact with
+// passphrase: passphrase the private keys are encrypted with
 type WalletDao struct {
 	collectionName string
 	dbName         string
+	db             Store
+	passphrase     string
+
+	// keys caches the decrypted private keys, as each decryption takes a scrypt derivation
+	mu   sync.Mutex
+	keys map[common.Address]*ecdsa.PrivateKey
 }
 
-func NewWalletDao() *WalletDao {
-	return &WalletDao{"wallets", app.Config.DBName}
+type WalletDaoOption = func(*WalletDao) error
+
+// WalletDaoPassphraseOption sets the passphrase the private keys are encrypted with. The
+// wallets are read without their key, and can not be created with one, when it is not set
+func WalletDaoPassphraseOption(passphrase string) func(dao *WalletDao) error {
+	return func(dao *WalletDao) error {
+		dao.passphrase = passphrase
+		return nil
+	}
 }
 
+func NewWalletDao(db Store, opts ...WalletDaoOption) *WalletDao {
+	dao := &WalletDao{
+		collectionName: "wallets",
+		dbName:         app.Config.DBName,
+		db:             db,
+		keys:           make(map[common.Address]*ecdsa.PrivateKey),
+	}
+
+	for _, op := range opts {
+		err := op(dao)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return dao
+}
+
+// Create stores a wallet, with its private key encrypted with the passphrase of the DAO
 func (dao *WalletDao) Create(wallet *types.Wallet) error {
 	err := wallet.Validate()
 	if err != nil {
@@ -26,8 +67,21 @@ func (dao *WalletDao) Create(wallet *types.Wallet) error {
 		return err
 	}
 
-	wallet.ID = bson.NewObjectId()
-	err = db.Create(dao.dbName, dao.collectionName, wallet)
+	if wallet.PrivateKey != nil && wallet.Keystore == "" {
+		if dao.passphrase == "" {
+			logger.Error(ErrNoWalletPassphrase)
+			return ErrNoWalletPassphrase
+		}
+
+		err = wallet.Encrypt(dao.passphrase)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	wallet.ID = primitive.NewObjectID()
+	err = dao.db.Create(dao.dbName, dao.collectionName, wallet)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -39,25 +93,37 @@ func (dao *WalletDao) Create(wallet *types.Wallet) error {
 func (dao *WalletDao) GetAll() ([]types.Wallet, error) {
 	var response []types.Wallet
 
-	err := db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &response)
+	err := dao.db.Get(dao.dbName, dao.collectionName, bson.M{}, 0, 0, &response)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
 
+	for i := range response {
+		err = dao.decrypt(&response[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return response, nil
 }
 
 // GetByID function fetches details of a token based on its mongo id
-func (dao *WalletDao) GetByID(id bson.ObjectId) (*types.Wallet, error) {
+func (dao *WalletDao) GetByID(id primitive.ObjectID) (*types.Wallet, error) {
 	var response *types.Wallet
 
-	err := db.GetByID(dao.dbName, dao.collectionName, id, &response)
+	err := dao.db.GetByID(dao.dbName, dao.collectionName, id, &response)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
 
+	err = dao.decrypt(response)
+	if err != nil {
+		return nil, err
+	}
+
 	return response, nil
 }
 
@@ -66,7 +132,7 @@ func (dao *WalletDao) GetByAddress(a common.Address) (*types.Wallet, error) {
 	q := bson.M{"address": a.Hex()}
 	var resp []types.Wallet
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 1, &resp)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 1, &resp)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
@@ -77,6 +143,11 @@ func (dao *WalletDao) GetByAddress(a common.Address) (*types.Wallet, error) {
 		return nil, nil
 	}
 
+	err = dao.decrypt(&resp[0])
+	if err != nil {
+		return nil, err
+	}
+
 	return &resp[0], nil
 }
 
@@ -84,12 +155,17 @@ func (dao *WalletDao) GetDefaultAdminWallet() (*types.Wallet, error) {
 	q := bson.M{"admin": true}
 	var resp types.Wallet
 
-	err := db.GetOne(dao.dbName, dao.collectionName, q, &resp)
+	err := dao.db.GetOne(dao.dbName, dao.collectionName, q, &resp)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
 
+	err = dao.decrypt(&resp)
+	if err != nil {
+		return nil, err
+	}
+
 	return &resp, nil
 }
 
@@ -97,11 +173,86 @@ func (dao *WalletDao) GetOperatorWallets() ([]*types.Wallet, error) {
 	q := bson.M{"operator": true}
 	res := []*types.Wallet{}
 
-	err := db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
 	if err != nil || len(res) == 0 {
 		logger.Error(err)
 		return nil, err
 	}
 
+	for _, w := range res {
+		err = dao.decrypt(w)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return res, nil
 }
+
+// EncryptPlaintextKeys encrypts the private keys of the wallets stored in plaintext, and
+// returns the number of wallets encrypted
+func (dao *WalletDao) EncryptPlaintextKeys() (int, error) {
+	q := bson.M{"privateKey": bson.M{"$exists": true}}
+	res := []*types.Wallet{}
+
+	err := dao.db.Get(dao.dbName, dao.collectionName, q, 0, 0, &res)
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	if len(res) == 0 {
+		return 0, nil
+	}
+
+	if dao.passphrase == "" {
+		logger.Error(ErrNoWalletPassphrase)
+		return 0, ErrNoWalletPassphrase
+	}
+
+	for i, w := range res {
+		err = w.Encrypt(dao.passphrase)
+		if err != nil {
+			logger.Error(err)
+			return i, err
+		}
+
+		update := bson.M{
+			"$set":   bson.M{"keystore": w.Keystore},
+			"$unset": bson.M{"privateKey": ""},
+		}
+
+		err = dao.db.Update(dao.dbName, dao.collectionName, bson.M{"_id": w.ID}, update)
+		if err != nil {
+			logger.Error(err)
+			return i, err
+		}
+	}
+
+	return len(res), nil
+}
+
+// decrypt decrypts the private key of a wallet read from the store, unless the DAO has no
+// passphrase
+func (dao *WalletDao) decrypt(w *types.Wallet) error {
+	if w == nil || w.Keystore == "" || dao.passphrase == "" {
+		return nil
+	}
+
+	dao.mu.Lock()
+	defer dao.mu.Unlock()
+
+	if key, ok := dao.keys[w.Address]; ok {
+		w.PrivateKey = key
+		return nil
+	}
+
+	err := w.Decrypt(dao.passphrase)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	dao.keys[w.Address] = w.PrivateKey
+	return nil
+}
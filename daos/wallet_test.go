@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package daos
 
 import (
@@ -5,24 +8,28 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/globalsign/mgo/dbtest"
 	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils"
 )
 
-var server dbtest.DBServer
+var server testutils.DBServer
 
 func init() {
 	temp, _ := ioutil.TempDir("", "test")
 	server.SetPath(temp)
 
-	session := server.Session()
-	db = &Database{session}
+	d, err := Connect(server.URI())
+	if err != nil {
+		panic(err)
+	}
+
+	db = d
 }
 
 func TestWalletDao(t *testing.T) {
 	key := "7c78c6e2f65d0d84c44ac0f7b53d6e4dd7a82c35f51b251d387c2a69df712660"
 	w := types.NewWalletFromPrivateKey(key)
-	dao := NewWalletDao()
+	dao := NewWalletDao(db, WalletDaoPassphraseOption("passphrase"))
 
 	err := dao.Create(w)
 	if err != nil {
@@ -61,7 +68,7 @@ func TestDefaultAdminWallet(t *testing.T) {
 	key := "7c78c6e2f65d0d84c44ac0f7b53d6e4dd7a82c35f51b251d387c2a69df712660"
 	w := types.NewWalletFromPrivateKey(key)
 	w.Admin = true
-	dao := NewWalletDao()
+	dao := NewWalletDao(db, WalletDaoPassphraseOption("passphrase"))
 
 	err := dao.Create(w)
 	if err != nil {
@@ -10,7 +10,6 @@ import (
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/middlewares"
 	"github.com/tomochain/tomox-sdk/types"
-	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 )
 
@@ -21,9 +20,12 @@ type AccountEndpoint struct {
 func ServeAccountResource(
 	r *mux.Router,
 	accountService interfaces.AccountService,
+	verifier *middlewares.SignatureVerifier,
+	apiKeys *middlewares.APIKeyVerifier,
 ) {
 
 	e := &AccountEndpoint{AccountService: accountService}
+	read := alice.New(apiKeys.Authenticate(types.APIKeyScopeRead))
 
 	/*
 		r.Handle(
@@ -33,27 +35,27 @@ func ServeAccountResource(
 
 	r.Handle(
 		"/api/account/favorite/{address}",
-		alice.New(middlewares.VerifySignature).Then(http.HandlerFunc(e.handleGetFavoriteTokens)),
+		read.Append(verifier.VerifySignature).Then(http.HandlerFunc(e.handleGetFavoriteTokens)),
 	).Methods("GET")
 
 	/*
 		r.Handle(
 			"/api/account/favorite/add",
-			alice.New(middlewares.VerifySignature).Then(http.HandlerFunc(e.handleAddFavoriteToken)),
+			alice.New(verifier.VerifySignature).Then(http.HandlerFunc(e.handleAddFavoriteToken)),
 		).Methods("POST")
 
 			r.Handle(
 				"/api/account/favorite/remove",
-				alice.New(middlewares.VerifySignature).Then(http.HandlerFunc(e.handleRemoveFavoriteToken)),
+				alice.New(verifier.VerifySignature).Then(http.HandlerFunc(e.handleRemoveFavoriteToken)),
 			).Methods("POST")
 	*/
 
 	r.Handle(
-		"/api/account/{address}", http.HandlerFunc(e.handleGetAccount),
+		"/api/account/{address}", read.Then(http.HandlerFunc(e.handleGetAccount)),
 	).Methods("GET")
 
 	r.Handle(
-		"/api/account/{address}/{token}", http.HandlerFunc(e.handleGetAccountTokenBalance),
+		"/api/account/{address}/{token}", read.Then(http.HandlerFunc(e.handleGetAccountTokenBalance)),
 	).Methods("GET")
 }
 
@@ -100,6 +102,10 @@ func (e *AccountEndpoint) handleGetAccount(w http.ResponseWriter, r *http.Reques
 	}
 
 	address := common.HexToAddress(addr)
+	if !isAuthorized(w, r, address) {
+		return
+	}
+
 	a, err := e.AccountService.GetByAddress(address)
 	if err != nil {
 		logger.Error(err)
@@ -130,6 +136,10 @@ func (e *AccountEndpoint) handleGetAccountTokenBalance(w http.ResponseWriter, r
 	addr := common.HexToAddress(a)
 	tokenAddr := common.HexToAddress(t)
 
+	if !isAuthorized(w, r, addr) {
+		return
+	}
+
 	b, err := e.AccountService.GetTokenBalanceProvidor(addr, tokenAddr)
 	if err != nil {
 		logger.Error(err)
@@ -151,10 +161,8 @@ func (e *AccountEndpoint) handleGetFavoriteTokens(w http.ResponseWriter, r *http
 
 	address := common.HexToAddress(addr)
 
-	publicKeyBytes := common.Hex2Bytes(r.Header["Pubkey"][0])
-	publicAddress := utils.GetAddressFromPublicKey(publicKeyBytes)
-
-	if address != publicAddress {
+	signer, ok := middlewares.AuthenticatedAddress(r)
+	if !ok || address != signer {
 		httputils.WriteError(w, http.StatusUnauthorized, "Request is not sent from address's owner")
 		return
 	}
@@ -195,10 +203,8 @@ func (e *AccountEndpoint) handleAddFavoriteToken(w http.ResponseWriter, r *http.
 	address := common.HexToAddress(tr.Address)
 	tokenAddr := common.HexToAddress(tr.Token)
 
-	publicKeyBytes := common.Hex2Bytes(r.Header["Pubkey"][0])
-	publicAddress := utils.GetAddressFromPublicKey(publicKeyBytes)
-
-	if address != publicAddress {
+	signer, ok := middlewares.AuthenticatedAddress(r)
+	if !ok || address != signer {
 		httputils.WriteError(w, http.StatusUnauthorized, "Request is not sent from address's owner")
 		return
 	}
@@ -240,10 +246,8 @@ func (e *AccountEndpoint) handleRemoveFavoriteToken(w http.ResponseWriter, r *ht
 	address := common.HexToAddress(tr.Address)
 	tokenAddr := common.HexToAddress(tr.Token)
 
-	publicKeyBytes := common.Hex2Bytes(r.Header["Pubkey"][0])
-	publicAddress := utils.GetAddressFromPublicKey(publicKeyBytes)
-
-	if address != publicAddress {
+	signer, ok := middlewares.AuthenticatedAddress(r)
+	if !ok || address != signer {
 		httputils.WriteError(w, http.StatusUnauthorized, "Request is not sent from address's owner")
 		return
 	}
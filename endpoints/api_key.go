@@ -0,0 +1,97 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type apiKeyEndpoint struct {
+	apiKeyService interfaces.APIKeyService
+}
+
+// ServeAPIKeyResource sets up the routing of API key endpoints and the corresponding handlers.
+// The keys of an address are managed with requests signed by its wallet
+func ServeAPIKeyResource(
+	r *mux.Router,
+	apiKeyService interfaces.APIKeyService,
+	verifier *middlewares.SignatureVerifier,
+) {
+	e := &apiKeyEndpoint{apiKeyService}
+
+	signed := alice.New(verifier.VerifySignature)
+	r.Handle("/api/keys", signed.ThenFunc(e.handleGetAPIKeys)).Methods("GET")
+	r.Handle("/api/keys", signed.ThenFunc(e.handleCreateAPIKey)).Methods("POST")
+	r.Handle("/api/keys/{key}", signed.ThenFunc(e.handleRevokeAPIKey)).Methods("DELETE")
+}
+
+func (e *apiKeyEndpoint) handleGetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	addr, _ := middlewares.AuthenticatedAddress(r)
+
+	keys, err := e.apiKeyService.GetByUserAddress(addr)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, keys)
+}
+
+func (e *apiKeyEndpoint) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req *types.APIKeyRequest
+	decoder := json.NewDecoder(r.Body)
+
+	defer r.Body.Close()
+
+	err := decoder.Decode(&req)
+	if err != nil || req == nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	addr, _ := middlewares.AuthenticatedAddress(r)
+
+	k, err := e.apiKeyService.Create(addr, req)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusCreated, k)
+}
+
+func (e *apiKeyEndpoint) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	addr, _ := middlewares.AuthenticatedAddress(r)
+
+	err := e.apiKeyService.Revoke(addr, vars["key"])
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	httputils.WriteMessage(w, http.StatusOK, "API key revoked")
+}
+
+// isAuthorized checks that a request authenticated with an API key or a signature acts for
+// the address it is authenticated with, and writes the error response otherwise. Requests
+// that are not authenticated are not restricted
+func isAuthorized(w http.ResponseWriter, r *http.Request, addr common.Address) bool {
+	authenticated, ok := middlewares.AuthenticatedAddress(r)
+	if ok && authenticated != addr {
+		httputils.WriteError(w, http.StatusForbidden, "Request is authenticated for another address")
+		return false
+	}
+
+	return true
+}
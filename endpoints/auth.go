@@ -0,0 +1,64 @@
+package endpoints
+
+import (
+	"encoding/json"
+
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+// ServeAuthResource sets up the login of the websocket connections, which the private channels
+// (orders, lending_orders, notification and deposit) require
+func ServeAuthResource() {
+	ws.RegisterChannel(ws.AuthChannel, handleAuthWebSocket)
+}
+
+// handleAuthWebSocket handles the login of a connection. A CHALLENGE message is answered with a
+// nonce, and a LOGIN message carrying the signature of this nonce binds the connection to the
+// address that signed it
+func handleAuthWebSocket(input interface{}, c *ws.Client) {
+	b, _ := json.Marshal(input)
+	var ev *types.WebsocketEvent
+	errInvalidPayload := map[string]string{"Message": "Invalid payload"}
+
+	err := json.Unmarshal(b, &ev)
+	if err != nil {
+		logger.Error(err)
+	}
+
+	if ev == nil {
+		c.SendMessage(ws.AuthChannel, types.ERROR, errInvalidPayload)
+		return
+	}
+
+	switch ev.Type {
+	case types.CHALLENGE:
+		challenge, err := c.NewLoginChallenge()
+		if err != nil {
+			logger.Error(err)
+			c.SendMessage(ws.AuthChannel, types.ERROR, err.Error())
+			return
+		}
+
+		c.SendMessage(ws.AuthChannel, types.CHALLENGE, challenge)
+	case types.LOGIN:
+		l := &types.Login{}
+		b, _ = json.Marshal(ev.Payload)
+
+		err = json.Unmarshal(b, &l)
+		if err != nil || l == nil {
+			c.SendMessage(ws.AuthChannel, types.ERROR, errInvalidPayload)
+			return
+		}
+
+		err = c.Login(l)
+		if err != nil {
+			c.SendMessage(ws.AuthChannel, types.ERROR, err)
+			return
+		}
+
+		c.SendMessage(ws.AuthChannel, types.SUCCESS_EVENT, map[string]string{"address": l.Address.Hex()})
+	default:
+		c.SendMessage(ws.AuthChannel, types.ERROR, errInvalidPayload)
+	}
+}
@@ -0,0 +1,29 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type changeStreamEndpoint struct {
+	changeStreamService interfaces.ChangeStreamService
+}
+
+// ServeChangeStreamResource sets up the routing of the change stream endpoints
+func ServeChangeStreamResource(
+	r *mux.Router,
+	changeStreamService interfaces.ChangeStreamService,
+) {
+
+	e := &changeStreamEndpoint{changeStreamService}
+	r.HandleFunc("/api/streams", e.handleGetStreams).Methods("GET")
+}
+
+// handleGetStreams returns the metrics of the change streams, with the lag of their last handled
+// event
+func (e *changeStreamEndpoint) handleGetStreams(w http.ResponseWriter, r *http.Request) {
+	httputils.WriteJSON(w, http.StatusOK, e.changeStreamService.Stats())
+}
@@ -0,0 +1,59 @@
+package endpoints
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type deadLetterEndpoint struct {
+	deadLetterQueue interfaces.DeadLetterQueue
+}
+
+// ServeDeadLetterResource sets up the routing of the dead-letter queue endpoints
+func ServeDeadLetterResource(
+	r *mux.Router,
+	deadLetterQueue interfaces.DeadLetterQueue,
+) {
+
+	e := &deadLetterEndpoint{deadLetterQueue}
+	r.HandleFunc("/api/dead-letters", e.handleGetDeadLetters).Methods("GET")
+}
+
+// handleGetDeadLetters returns the first messages of the dead-letter queue, with the queue they
+// were rejected from. The messages are left in the queue
+func (e *deadLetterEndpoint) handleGetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	authKey := v.Get("authKey")
+	limit := v.Get("limit")
+
+	// the messages can hold the orders of the users, so they are not served without a key
+	if app.Config.ApiAuthKey == "" || app.Config.ApiAuthKey != authKey {
+		httputils.WriteError(w, http.StatusUnauthorized, "Invalid auth key")
+		return
+	}
+
+	size := types.DefaultLimit
+	if limit != "" {
+		t, err := strconv.Atoi(limit)
+		if err != nil || t < 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		size = t
+	}
+
+	res, err := e.deadLetterQueue.GetDeadLetters(size)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
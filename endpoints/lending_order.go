@@ -9,7 +9,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 	"github.com/tomochain/tomox-sdk/ws"
@@ -24,18 +26,22 @@ type lendingorderEndpoint struct {
 }
 
 // ServeLendingOrderResource sets up the routing of order endpoints and the corresponding handlers.
-func ServeLendingOrderResource(r *mux.Router, lendingorderService interfaces.LendingOrderService) {
+func ServeLendingOrderResource(r *mux.Router, lendingorderService interfaces.LendingOrderService, apiKeys *middlewares.APIKeyVerifier) {
 	e := &lendingorderEndpoint{lendingorderService}
-	r.HandleFunc("/api/lending/orders", e.handleGetLendingOrders).Methods("GET")
-	r.HandleFunc("/api/lending/repay", e.handleGetRepay).Methods("GET")
-	r.HandleFunc("/api/lending/topup", e.handleGetTopup).Methods("GET")
-	r.HandleFunc("/api/lending/recall", e.handleGetRecall).Methods("GET")
+
+	read := alice.New(apiKeys.Authenticate(types.APIKeyScopeRead))
+	lending := alice.New(apiKeys.Authenticate(types.APIKeyScopeLending))
+
+	r.Handle("/api/lending/orders", read.ThenFunc(e.handleGetLendingOrders)).Methods("GET")
+	r.Handle("/api/lending/repay", read.ThenFunc(e.handleGetRepay)).Methods("GET")
+	r.Handle("/api/lending/topup", read.ThenFunc(e.handleGetTopup)).Methods("GET")
+	r.Handle("/api/lending/recall", read.ThenFunc(e.handleGetRecall)).Methods("GET")
 	r.HandleFunc("/api/lending/estimate", e.handleGetEstimateCollateral).Methods("GET")
-	r.HandleFunc("/api/lending/nonce", e.handleGetLendingOrderNonce).Methods("GET")
-	r.HandleFunc("/api/lending", e.handleNewLendingOrder).Methods("POST")
-	r.HandleFunc("/api/lending/cancel", e.handleCancelLendingOrder).Methods("POST")
-	r.HandleFunc("/api/lending/repay", e.handleRepayLendingOrder).Methods("POST")
-	r.HandleFunc("/api/lending/topup", e.handleTopupLendingOrder).Methods("POST")
+	r.Handle("/api/lending/nonce", read.ThenFunc(e.handleGetLendingOrderNonce)).Methods("GET")
+	r.Handle("/api/lending", lending.ThenFunc(e.handleNewLendingOrder)).Methods("POST")
+	r.Handle("/api/lending/cancel", lending.ThenFunc(e.handleCancelLendingOrder)).Methods("POST")
+	r.Handle("/api/lending/repay", lending.ThenFunc(e.handleRepayLendingOrder)).Methods("POST")
+	r.Handle("/api/lending/topup", lending.ThenFunc(e.handleTopupLendingOrder)).Methods("POST")
 	r.HandleFunc("/api/lending/{hash}", e.handleLendingByHash).Methods("GET")
 
 	ws.RegisterChannel(ws.LendingOrderChannel, e.ws)
@@ -69,6 +75,11 @@ func (e *lendingorderEndpoint) handleGetLendingOrders(w http.ResponseWriter, r *
 			httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
 			return
 		}
+
+		if !isAuthorized(w, r, common.HexToAddress(addr)) {
+			return
+		}
+
 		lendingSpec.UserAddress = common.HexToAddress(addr).Hex()
 
 	}
@@ -211,6 +222,11 @@ func (e *lendingorderEndpoint) handleNewLendingOrder(w http.ResponseWriter, r *h
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
 		return
 	}
+
+	if !isAuthorized(w, r, o.UserAddress) {
+		return
+	}
+
 	o.Hash = o.ComputeHash()
 	err = e.lendingorderService.NewLendingOrder(o)
 	if err != nil {
@@ -231,6 +247,11 @@ func (e *lendingorderEndpoint) handleCancelLendingOrder(w http.ResponseWriter, r
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
 		return
 	}
+
+	if !isAuthorized(w, r, o.UserAddress) {
+		return
+	}
+
 	err = e.lendingorderService.CancelLendingOrder(o)
 	if err != nil {
 		logger.Error(err)
@@ -252,6 +273,11 @@ func (e *lendingorderEndpoint) handleRepayLendingOrder(w http.ResponseWriter, r
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
 		return
 	}
+
+	if !isAuthorized(w, r, o.UserAddress) {
+		return
+	}
+
 	err = e.lendingorderService.RepayLendingOrder(o)
 	if err != nil {
 		logger.Error(err)
@@ -272,6 +298,11 @@ func (e *lendingorderEndpoint) handleTopupLendingOrder(w http.ResponseWriter, r
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
 		return
 	}
+
+	if !isAuthorized(w, r, o.UserAddress) {
+		return
+	}
+
 	err = e.lendingorderService.TopupLendingOrder(o)
 	if err != nil {
 		logger.Error(err)
@@ -328,7 +359,12 @@ func (e *lendingorderEndpoint) handleWSSubLendingOrder(ev *types.WebsocketEvent,
 	}
 
 	a := common.HexToAddress(addr)
-	ws.RegisterLendingOrderConnection(a, c)
+	err = ws.RegisterLendingOrderConnection(a, c)
+	if err != nil {
+		c.SendMessage(ws.LendingOrderChannel, types.ERROR, err)
+		return
+	}
+
 	ws.SendLendingOrderMessage(types.INIT, a, nil)
 }
 
@@ -361,7 +397,11 @@ func (e *lendingorderEndpoint) handleWSNewLendingOrder(ev *types.WebsocketEvent,
 	}
 
 	o.Hash = o.ComputeHash()
-	ws.RegisterLendingOrderConnection(o.UserAddress, c)
+	err = ws.RegisterLendingOrderConnection(o.UserAddress, c)
+	if err != nil {
+		c.SendLendingOrderErrorMessage(err, o.Hash)
+		return
+	}
 
 	err = e.lendingorderService.NewLendingOrder(o)
 	if err != nil {
@@ -382,7 +422,11 @@ func (e *lendingorderEndpoint) handleWSCancelLendingOrder(ev *types.WebsocketEve
 		c.SendLendingOrderErrorMessage(err, o.Hash)
 	}
 
-	ws.RegisterLendingOrderConnection(o.UserAddress, c)
+	err = ws.RegisterLendingOrderConnection(o.UserAddress, c)
+	if err != nil {
+		c.SendLendingOrderErrorMessage(err, o.Hash)
+		return
+	}
 
 	orderErr := e.lendingorderService.CancelLendingOrder(o)
 	if orderErr != nil {
@@ -408,6 +452,10 @@ func (e *lendingorderEndpoint) handleGetLendingOrderNonce(w http.ResponseWriter,
 
 	a := common.HexToAddress(addr)
 
+	if !isAuthorized(w, r, a) {
+		return
+	}
+
 	total, err := e.lendingorderService.GetLendingNonceByUserAddress(a)
 	if err != nil {
 		logger.Error(err)
@@ -441,6 +489,11 @@ func (e *lendingorderEndpoint) handleGetTopup(w http.ResponseWriter, r *http.Req
 			httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
 			return
 		}
+
+		if !isAuthorized(w, r, common.HexToAddress(addr)) {
+			return
+		}
+
 		topupSpec.UserAddress = common.HexToAddress(addr).Hex()
 
 	}
@@ -575,6 +628,11 @@ func (e *lendingorderEndpoint) handleGetRepay(w http.ResponseWriter, r *http.Req
 			httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
 			return
 		}
+
+		if !isAuthorized(w, r, common.HexToAddress(addr)) {
+			return
+		}
+
 		repaySpec.UserAddress = common.HexToAddress(addr).Hex()
 
 	}
@@ -701,6 +759,11 @@ func (e *lendingorderEndpoint) handleGetRecall(w http.ResponseWriter, r *http.Re
 			httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
 			return
 		}
+
+		if !isAuthorized(w, r, common.HexToAddress(addr)) {
+			return
+		}
+
 		recallSpec.UserAddress = common.HexToAddress(addr).Hex()
 
 	}
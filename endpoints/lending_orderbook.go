@@ -105,7 +105,7 @@ func (e *LendingOrderBookEndpoint) lendingOrderBookWebSocket(input interface{},
 		socket.SendErrorMessage(c, errInvalidPayload)
 		return
 	}
-	if ev.Type != types.SUBSCRIBE && ev.Type != types.UNSUBSCRIBE {
+	if ev.Type != types.SUBSCRIBE && ev.Type != types.UNSUBSCRIBE && ev.Type != types.RESYNC {
 		logger.Info("Event Type", ev.Type)
 		socket.SendErrorMessage(c, errInvalidPayload)
 		return
@@ -137,6 +137,15 @@ func (e *LendingOrderBookEndpoint) lendingOrderBookWebSocket(input interface{},
 		e.lendingOrderBookService.SubscribeLendingOrderBook(c, p.Term, p.LendingToken)
 	}
 
+	if ev.Type == types.RESYNC {
+		if p == nil {
+			socket.SendErrorMessage(c, errInvalidPayload)
+			return
+		}
+
+		e.lendingOrderBookService.ResyncLendingOrderBook(c, p.Term, p.LendingToken)
+	}
+
 	if ev.Type == types.UNSUBSCRIBE {
 		if p == nil {
 			e.lendingOrderBookService.UnsubscribeLendingOrderBook(c)
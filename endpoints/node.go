@@ -0,0 +1,38 @@
+package endpoints
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type nodeEndpoint struct {
+	nodeMonitor interfaces.NodeMonitor
+}
+
+// ServeNodeResource sets up the routing of the TomoX node endpoints
+func ServeNodeResource(
+	r *mux.Router,
+	nodeMonitor interfaces.NodeMonitor,
+) {
+
+	e := &nodeEndpoint{nodeMonitor}
+	r.HandleFunc("/api/nodes", e.handleGetNodes).Methods("GET")
+}
+
+// handleGetNodes returns the health of the TomoX nodes the orders are sent to
+func (e *nodeEndpoint) handleGetNodes(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	authKey := v.Get("authKey")
+
+	// the URLs of the nodes can hold credentials, so they are not served without a key
+	if app.Config.ApiAuthKey == "" || app.Config.ApiAuthKey != authKey {
+		httputils.WriteError(w, http.StatusUnauthorized, "Invalid auth key")
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, e.nodeMonitor.Nodes())
+}
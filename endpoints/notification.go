@@ -8,11 +8,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type NotificationEndpoint struct {
@@ -23,14 +26,26 @@ type NotificationEndpoint struct {
 func ServeNotificationResource(
 	r *mux.Router,
 	notificationService interfaces.NotificationService,
+	verifier *middlewares.SignatureVerifier,
 ) {
 	e := &NotificationEndpoint{notificationService}
 
 	r.HandleFunc("/api/notifications", e.HandleGetNotifications).Methods("GET")
 
-	r.HandleFunc("/api/notification/mark/read", e.HandleMarkReadNotification).Methods("PUT")
-	r.HandleFunc("/api/notification/mark/unread", e.HandleMarkUnReadNotification).Methods("PUT")
-	r.HandleFunc("/api/notification/mark/readall", e.HandleMarkReadAllNotification).Methods("PUT")
+	r.Handle(
+		"/api/notification/mark/read",
+		alice.New(verifier.VerifySignature).Then(http.HandlerFunc(e.HandleMarkReadNotification)),
+	).Methods("PUT")
+
+	r.Handle(
+		"/api/notification/mark/unread",
+		alice.New(verifier.VerifySignature).Then(http.HandlerFunc(e.HandleMarkUnReadNotification)),
+	).Methods("PUT")
+
+	r.Handle(
+		"/api/notification/mark/readall",
+		alice.New(verifier.VerifySignature).Then(http.HandlerFunc(e.HandleMarkReadAllNotification)),
+	).Methods("PUT")
 
 	ws.RegisterChannel(ws.NotificationChannel, e.handleNotificationWebSocket)
 }
@@ -46,6 +61,13 @@ func (e *NotificationEndpoint) HandleMarkReadAllNotification(w http.ResponseWrit
 		return
 	}
 	defer r.Body.Close()
+
+	signer, ok := middlewares.AuthenticatedAddress(r)
+	if !ok || n.Recipient != signer {
+		httputils.WriteError(w, http.StatusUnauthorized, "Request is not sent from recipient")
+		return
+	}
+
 	err = e.NotificationService.MarkAllRead(n.Recipient)
 
 	if err != nil {
@@ -67,6 +89,11 @@ func (e *NotificationEndpoint) HandleMarkReadNotification(w http.ResponseWriter,
 		return
 	}
 	defer r.Body.Close()
+
+	if !e.isRecipient(w, r, n.ID) {
+		return
+	}
+
 	err = e.NotificationService.MarkRead(n.ID)
 
 	if err != nil {
@@ -88,6 +115,11 @@ func (e *NotificationEndpoint) HandleMarkUnReadNotification(w http.ResponseWrite
 		return
 	}
 	defer r.Body.Close()
+
+	if !e.isRecipient(w, r, n.ID) {
+		return
+	}
+
 	err = e.NotificationService.MarkUnRead(n.ID)
 
 	if err != nil {
@@ -98,6 +130,35 @@ func (e *NotificationEndpoint) HandleMarkUnReadNotification(w http.ResponseWrite
 	httputils.WriteMessage(w, http.StatusOK, "Mark unread status successfully")
 }
 
+// isRecipient checks that the notification was sent to the address that signed the request,
+// and writes the error response otherwise
+func (e *NotificationEndpoint) isRecipient(w http.ResponseWriter, r *http.Request, id primitive.ObjectID) bool {
+	if id.IsZero() {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid notification ID")
+		return false
+	}
+
+	n, err := e.NotificationService.GetByID(id)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+
+	if n == nil {
+		httputils.WriteError(w, http.StatusNotFound, "Notification not found")
+		return false
+	}
+
+	signer, ok := middlewares.AuthenticatedAddress(r)
+	if !ok || n.Recipient != signer {
+		httputils.WriteError(w, http.StatusUnauthorized, "Request is not sent from recipient")
+		return false
+	}
+
+	return true
+}
+
 // HandleGetNotifications get notifications user address
 func (e *NotificationEndpoint) HandleGetNotifications(w http.ResponseWriter, r *http.Request) {
 	v := r.URL.Query()
@@ -214,7 +275,12 @@ func (e *NotificationEndpoint) handleNotificationWebSocket(input interface{}, c
 
 		a := common.HexToAddress(addr)
 
-		ws.RegisterNotificationConnection(a, c)
+		err = ws.RegisterNotificationConnection(a, c)
+		if err != nil {
+			ws.SendNotificationErrorMessage(c, err)
+			return
+		}
+
 		notifications, err := e.NotificationService.GetByUserAddress(a, 0, 0)
 
 		if err != nil {
@@ -0,0 +1,171 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+type operatorEndpoint struct {
+	operatorService interfaces.OperatorService
+}
+
+// ServeOperatorResource sets up the routing of the operator endpoints, which place and cancel
+// the orders of the operator wallets for the trading operators. The requests are signed by the
+// operator, or sent with one of its API keys
+func ServeOperatorResource(
+	r *mux.Router,
+	operatorService interfaces.OperatorService,
+	verifier *middlewares.SignatureVerifier,
+	apiKeys *middlewares.APIKeyVerifier,
+) {
+	e := &operatorEndpoint{operatorService}
+
+	read := alice.New(apiKeys.Authenticate(types.APIKeyScopeRead), verifier.VerifySignature, e.requireOperator)
+	trade := alice.New(apiKeys.Authenticate(types.APIKeyScopeTrade), verifier.VerifySignature, e.requireOperator)
+
+	r.Handle("/api/operator/orders", trade.ThenFunc(e.handleNewOrder)).Methods("POST")
+	r.Handle("/api/operator/orders/cancel", trade.ThenFunc(e.handleCancelOrder)).Methods("POST")
+	r.Handle("/api/operator/actions", read.ThenFunc(e.handleGetActions)).Methods("GET")
+}
+
+// requireOperator rejects the requests which are not authenticated for a trading operator
+func (e *operatorEndpoint) requireOperator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, _ := middlewares.AuthenticatedAddress(r)
+		if !e.operatorService.IsOperator(addr) {
+			httputils.WriteError(w, http.StatusForbidden, "Address is not a trading operator")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleNewOrder signs and places an order of the operator wallet of its userAddress. The
+// nonce, hash and signature of the order are set by the server
+func (e *operatorEndpoint) handleNewOrder(w http.ResponseWriter, r *http.Request) {
+	var o *types.Order
+	decoder := json.NewDecoder(r.Body)
+
+	defer r.Body.Close()
+
+	err := decoder.Decode(&o)
+	if err != nil || o == nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	operator, _ := middlewares.AuthenticatedAddress(r)
+
+	o, err = e.operatorService.NewOrder(operator, o)
+	if err != nil {
+		logger.Error(err)
+		writeOrderError(w, err)
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusCreated, o)
+}
+
+// handleCancelOrder signs and sends the cancel of the order of {"orderHash": <hash>}
+func (e *operatorEndpoint) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
+	req := struct {
+		OrderHash common.Hash `json:"orderHash"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+
+	defer r.Body.Close()
+
+	err := decoder.Decode(&req)
+	if err != nil || (req.OrderHash == common.Hash{}) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	operator, _ := middlewares.AuthenticatedAddress(r)
+
+	oc, err := e.operatorService.CancelOrder(operator, req.OrderHash)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, oc)
+}
+
+// handleGetActions returns a page of the audit log of the operator actions, filtered by
+// operator, walletAddress, action, from and to
+func (e *operatorEndpoint) handleGetActions(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	spec := types.OperatorActionSpec{Action: v.Get("action")}
+
+	for key, value := range map[string]*string{"operator": &spec.Operator, "walletAddress": &spec.WalletAddress} {
+		addr := v.Get(key)
+		if addr == "" {
+			continue
+		}
+
+		if !common.IsHexAddress(addr) {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid "+key)
+			return
+		}
+
+		*value = addr
+	}
+
+	for key, value := range map[string]*int64{"from": &spec.DateFrom, "to": &spec.DateTo} {
+		param := v.Get(key)
+		if param == "" {
+			continue
+		}
+
+		t, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid time "+key+" value")
+			return
+		}
+
+		*value = t
+	}
+
+	offset := 0
+	size := types.DefaultLimit
+
+	if pageOffset := v.Get("pageOffset"); pageOffset != "" {
+		t, err := strconv.Atoi(pageOffset)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid page offset")
+			return
+		}
+		offset = t
+	}
+
+	if pageSize := v.Get("pageSize"); pageSize != "" {
+		t, err := strconv.Atoi(pageSize)
+		if err != nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid page size")
+			return
+		}
+		size = t
+	}
+
+	res, err := e.operatorService.GetOperatorActions(spec, offset, size)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
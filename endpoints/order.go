@@ -2,6 +2,7 @@ package endpoints
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -9,35 +10,52 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/justinas/alice"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 	"github.com/tomochain/tomox-sdk/ws"
 )
 
+// maxBatchSize is the maximum number of orders or cancels in a batch request
+const maxBatchSize = 100
+
 type orderEndpoint struct {
-	orderService   interfaces.OrderService
-	accountService interfaces.AccountService
+	orderService     interfaces.OrderService
+	stopOrderService interfaces.StopOrderService
+	accountService   interfaces.AccountService
 }
 
 // ServeOrderResource sets up the routing of order endpoints and the corresponding handlers.
 func ServeOrderResource(
 	r *mux.Router,
 	orderService interfaces.OrderService,
+	stopOrderService interfaces.StopOrderService,
 	accountService interfaces.AccountService,
+	apiKeys *middlewares.APIKeyVerifier,
 ) {
-	e := &orderEndpoint{orderService, accountService}
-
-	r.HandleFunc("/api/orders/count", e.handleGetCountOrder).Methods("GET")
-	r.HandleFunc("/api/orders/nonce", e.handleGetOrderNonce).Methods("GET")
-	r.HandleFunc("/api/orders/history", e.handleGetOrderHistory).Methods("GET")
-	r.HandleFunc("/api/orders/positions", e.handleGetPositions).Methods("GET")
-	r.HandleFunc("/api/orders", e.handleGetOrders).Methods("GET")
-	r.HandleFunc("/api/orders", e.handleNewOrder).Methods("POST")
-	r.HandleFunc("/api/orders/cancel", e.handleCancelOrder).Methods("POST")
-	r.HandleFunc("/api/orders/cancelAll", e.handleCancelAllOrders).Methods("POST")
-	r.HandleFunc("/api/orders/balance/lock", e.handleGetLockedBalanceInOrder).Methods("GET")
+	e := &orderEndpoint{orderService, stopOrderService, accountService}
+
+	read := alice.New(apiKeys.Authenticate(types.APIKeyScopeRead))
+	trade := alice.New(apiKeys.Authenticate(types.APIKeyScopeTrade))
+
+	r.Handle("/api/orders/count", read.ThenFunc(e.handleGetCountOrder)).Methods("GET")
+	r.Handle("/api/orders/nonce", read.ThenFunc(e.handleGetOrderNonce)).Methods("GET")
+	r.Handle("/api/orders/history", read.ThenFunc(e.handleGetOrderHistory)).Methods("GET")
+	r.Handle("/api/orders/positions", read.ThenFunc(e.handleGetPositions)).Methods("GET")
+	r.Handle("/api/orders", read.ThenFunc(e.handleGetOrders)).Methods("GET")
+	r.Handle("/api/orders", trade.ThenFunc(e.handleNewOrder)).Methods("POST")
+	r.Handle("/api/orders/batch", trade.ThenFunc(e.handleNewOrders)).Methods("POST")
+	r.Handle("/api/orders/cancel", trade.ThenFunc(e.handleCancelOrder)).Methods("POST")
+	r.Handle("/api/orders/cancel/batch", trade.ThenFunc(e.handleCancelOrders)).Methods("POST")
+	r.Handle("/api/orders/replace", trade.ThenFunc(e.handleReplaceOrder)).Methods("POST")
+	r.Handle("/api/orders/cancelAll", trade.ThenFunc(e.handleCancelAllOrders)).Methods("POST")
+	r.Handle("/api/orders/balance/lock", read.ThenFunc(e.handleGetLockedBalanceInOrder)).Methods("GET")
+	r.Handle("/api/orders/stop", read.ThenFunc(e.handleGetStopOrders)).Methods("GET")
+	r.Handle("/api/orders/stop", trade.ThenFunc(e.handleNewStopOrder)).Methods("POST")
+	r.Handle("/api/orders/stop/cancel", trade.ThenFunc(e.handleCancelStopOrder)).Methods("POST")
 	r.HandleFunc("/api/orders/{hash}", e.handleGetOrderByHash).Methods("GET")
 	ws.RegisterChannel(ws.OrderChannel, e.ws)
 }
@@ -71,6 +89,10 @@ func (e *orderEndpoint) handleGetLockedBalanceInOrder(w http.ResponseWriter, r *
 
 	a := common.HexToAddress(addr)
 
+	if !isAuthorized(w, r, a) {
+		return
+	}
+
 	total, err := e.orderService.GetOrdersLockedBalanceByUserAddress(a)
 
 	if err != nil {
@@ -98,6 +120,10 @@ func (e *orderEndpoint) handleGetCountOrder(w http.ResponseWriter, r *http.Reque
 
 	a := common.HexToAddress(addr)
 
+	if !isAuthorized(w, r, a) {
+		return
+	}
+
 	total, err := e.orderService.GetOrderCountByUserAddress(a)
 
 	if err != nil {
@@ -124,6 +150,7 @@ func (e *orderEndpoint) handleGetOrders(w http.ResponseWriter, r *http.Request)
 	status := v.Get("orderStatus")
 	orderType := v.Get("orderType")
 	orderhash := v.Get("hash")
+	clientOrderID := v.Get("clientOrderId")
 
 	sortedList := make(map[string]string)
 	sortedList["time"] = "createdAt"
@@ -137,6 +164,11 @@ func (e *orderEndpoint) handleGetOrders(w http.ResponseWriter, r *http.Request)
 			httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
 			return
 		}
+
+		if !isAuthorized(w, r, common.HexToAddress(addr)) {
+			return
+		}
+
 		orderSpec.UserAddress = common.HexToAddress(addr).Hex()
 
 	}
@@ -223,6 +255,14 @@ func (e *orderEndpoint) handleGetOrders(w http.ResponseWriter, r *http.Request)
 	if orderType != "" {
 		orderSpec.OrderType = orderType
 	}
+	if clientOrderID != "" {
+		// the client order IDs are only unique for each user
+		if orderSpec.UserAddress == "" {
+			httputils.WriteError(w, http.StatusBadRequest, "address Parameter missing")
+			return
+		}
+		orderSpec.ClientOrderID = clientOrderID
+	}
 	var err error
 	var orders *types.OrderRes
 
@@ -265,6 +305,10 @@ func (e *orderEndpoint) handleGetPositions(w http.ResponseWriter, r *http.Reques
 	var orders []*types.Order
 	address := common.HexToAddress(addr)
 
+	if !isAuthorized(w, r, address) {
+		return
+	}
+
 	if limit == "" {
 		orders, err = e.orderService.GetCurrentByUserAddress(address)
 	} else {
@@ -317,6 +361,10 @@ func (e *orderEndpoint) handleGetOrderHistory(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if !isAuthorized(w, r, common.HexToAddress(addr)) {
+		return
+	}
+
 	var orderSpec types.OrderSpec
 
 	orderSpec.UserAddress = common.HexToAddress(addr).Hex()
@@ -436,6 +484,10 @@ func (e *orderEndpoint) handleNewOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !isAuthorized(w, r, o.UserAddress) {
+		return
+	}
+
 	acc, err := e.accountService.GetByAddress(o.UserAddress)
 	if err != nil {
 		logger.Error(err)
@@ -451,13 +503,28 @@ func (e *orderEndpoint) handleNewOrder(w http.ResponseWriter, r *http.Request) {
 	err = e.orderService.NewOrder(o)
 	if err != nil {
 		logger.Error(err)
-		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		writeOrderError(w, err)
 		return
 	}
 
 	httputils.WriteJSON(w, http.StatusCreated, o)
 }
 
+// writeOrderError writes a rejected order error, along with its error code if it has one
+func writeOrderError(w http.ResponseWriter, err error) {
+	if apiErr, ok := err.(*errors.APIError); ok {
+		res := map[string]interface{}{"error": apiErr.Error(), "code": apiErr.ErrorCode}
+		if apiErr.Details != nil {
+			res["details"] = apiErr.Details
+		}
+
+		httputils.Write(w, apiErr.StatusCode(), res)
+		return
+	}
+
+	httputils.WriteError(w, http.StatusBadRequest, err.Error())
+}
+
 func (e *orderEndpoint) handleCancelOrder(w http.ResponseWriter, r *http.Request) {
 	oc := &types.OrderCancel{}
 
@@ -471,14 +538,305 @@ func (e *orderEndpoint) handleCancelOrder(w http.ResponseWriter, r *http.Request
 		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
 		return
 	}
+
+	if !isCancelAuthorized(w, r, oc) {
+		return
+	}
+
 	logger.Info("handle cancel order nonce", oc.Nonce)
 	err = e.orderService.CancelOrder(oc)
+	if err != nil {
+		logger.Error(err)
+		if _, ok := err.(*errors.APIError); ok {
+			writeOrderError(w, err)
+			return
+		}
+
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, oc.Hash)
+}
+
+// handleNewOrders places a batch of orders and returns a result for each of them
+func (e *orderEndpoint) handleNewOrders(w http.ResponseWriter, r *http.Request) {
+	var orders []*types.Order
+	decoder := json.NewDecoder(r.Body)
+
+	defer r.Body.Close()
+
+	err := decoder.Decode(&orders)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	if len(orders) == 0 || len(orders) > maxBatchSize {
+		httputils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Batch should contain between 1 and %d orders", maxBatchSize))
+		return
+	}
+
+	for _, o := range orders {
+		if o != nil && !isAuthorized(w, r, o.UserAddress) {
+			return
+		}
+	}
+
+	err = e.checkAccounts(orders)
+	if err != nil {
+		httputils.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	res, err := e.orderService.NewOrders(orders)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusCreated, res)
+}
+
+// handleCancelOrders cancels a batch of orders and returns a result for each of them
+func (e *orderEndpoint) handleCancelOrders(w http.ResponseWriter, r *http.Request) {
+	var ocs []*types.OrderCancel
+	decoder := json.NewDecoder(r.Body)
+
+	defer r.Body.Close()
+
+	err := decoder.Decode(&ocs)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	if len(ocs) == 0 || len(ocs) > maxBatchSize {
+		httputils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Batch should contain between 1 and %d cancels", maxBatchSize))
+		return
+	}
+
+	for _, oc := range ocs {
+		if oc == nil {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+			return
+		}
+
+		if !isCancelAuthorized(w, r, oc) {
+			return
+		}
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, e.orderService.CancelOrders(ocs))
+}
+
+// isCancelAuthorized checks that a cancel of a request authenticated with an API key or a
+// signature is signed by the address the request is authenticated with
+func isCancelAuthorized(w http.ResponseWriter, r *http.Request, oc *types.OrderCancel) bool {
+	if _, ok := middlewares.AuthenticatedAddress(r); !ok {
+		return true
+	}
+
+	if oc.Signature == nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return false
+	}
+
+	addr, err := oc.GetSenderAddress()
+	if err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return isAuthorized(w, r, addr)
+}
+
+// checkAccounts returns an error if the account of one of the orders is blocked
+func (e *orderEndpoint) checkAccounts(orders []*types.Order) error {
+	checked := make(map[common.Address]bool)
+
+	for _, o := range orders {
+		if o == nil {
+			return errors.New("Invalid payload")
+		}
+
+		if checked[o.UserAddress] {
+			continue
+		}
+
+		acc, err := e.accountService.GetByAddress(o.UserAddress)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		if acc.IsBlocked {
+			return errors.New("Account is blocked")
+		}
+
+		checked[o.UserAddress] = true
+	}
+
+	return nil
+}
+
+// handleReplaceOrder cancels an order and places a new one in its place. It responds once
+// the engine has processed both halves of the replacement, or the request is done
+func (e *orderEndpoint) handleReplaceOrder(w http.ResponseWriter, r *http.Request) {
+	var or *types.OrderReplace
+	decoder := json.NewDecoder(r.Body)
+
+	defer r.Body.Close()
+
+	err := decoder.Decode(&or)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	if or == nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	if err := or.Validate(); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !isAuthorized(w, r, or.Order.UserAddress) {
+		return
+	}
+
+	acc, err := e.accountService.GetByAddress(or.Order.UserAddress)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if acc.IsBlocked {
+		httputils.WriteError(w, http.StatusForbidden, "Account is blocked")
+		return
+	}
+
+	res, err := e.orderService.ReplaceOrder(r.Context(), or)
+	if err != nil {
+		logger.Error(err)
+		writeOrderError(w, err)
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusOK, res)
+}
+
+// handleGetStopOrders returns the stop orders of an user address that have not been triggered yet
+func (e *orderEndpoint) handleGetStopOrders(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	addr := v.Get("address")
+
+	if addr == "" {
+		httputils.WriteError(w, http.StatusBadRequest, "address Parameter Missing")
+		return
+	}
+
+	if !common.IsHexAddress(addr) {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid Address")
+		return
+	}
+
+	if !isAuthorized(w, r, common.HexToAddress(addr)) {
+		return
+	}
+
+	stopOrders, err := e.stopOrderService.GetOpenStopOrdersByUserAddress(common.HexToAddress(addr))
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	httputils.WriteJSON(w, http.StatusOK, stopOrders)
+}
+
+func (e *orderEndpoint) handleNewStopOrder(w http.ResponseWriter, r *http.Request) {
+	var so *types.StopOrder
+	decoder := json.NewDecoder(r.Body)
+
+	defer r.Body.Close()
+
+	err := decoder.Decode(&so)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	if so == nil {
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	if err := so.Validate(); err != nil {
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !isAuthorized(w, r, so.UserAddress) {
+		return
+	}
+
+	acc, err := e.accountService.GetByAddress(so.UserAddress)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if acc.IsBlocked {
+		httputils.WriteError(w, http.StatusForbidden, "Account is blocked")
+		return
+	}
+
+	err = e.stopOrderService.NewStopOrder(so)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	httputils.WriteJSON(w, http.StatusCreated, so)
+}
+
+func (e *orderEndpoint) handleCancelStopOrder(w http.ResponseWriter, r *http.Request) {
+	oc := &types.OrderCancel{}
+
+	decoder := json.NewDecoder(r.Body)
+
+	defer r.Body.Close()
+
+	err := decoder.Decode(&oc)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, "Invalid payload")
+		return
+	}
+
+	if !isCancelAuthorized(w, r, oc) {
+		return
+	}
+
+	err = e.stopOrderService.CancelStopOrder(oc)
+	if err != nil {
+		logger.Error(err)
+		httputils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	httputils.WriteJSON(w, http.StatusOK, oc.Hash)
 }
 
@@ -499,6 +857,10 @@ func (e *orderEndpoint) handleCancelAllOrders(w http.ResponseWriter, r *http.Req
 
 	a := common.HexToAddress(addr)
 
+	if !isAuthorized(w, r, a) {
+		return
+	}
+
 	err := e.orderService.CancelAllOrder(a)
 
 	if err != nil {
@@ -523,8 +885,18 @@ func (e *orderEndpoint) ws(input interface{}, c *ws.Client) {
 	switch msg.Type {
 	case "NEW_ORDER":
 		e.handleWSNewOrder(msg, c)
+	case "NEW_ORDERS":
+		e.handleWSNewOrders(msg, c)
 	case "CANCEL_ORDER":
 		e.handleWSCancelOrder(msg, c)
+	case "CANCEL_ORDERS":
+		e.handleWSCancelOrders(msg, c)
+	case "REPLACE_ORDER":
+		e.handleWSReplaceOrder(msg, c)
+	case "NEW_STOP_ORDER":
+		e.handleWSNewStopOrder(msg, c)
+	case "CANCEL_STOP_ORDER":
+		e.handleWSCancelStopOrder(msg, c)
 	case "SUBSCRIBE":
 		e.handleWSSubOrder(msg, c)
 	default:
@@ -557,7 +929,12 @@ func (e *orderEndpoint) handleWSSubOrder(ev *types.WebsocketEvent, c *ws.Client)
 	}
 
 	a := common.HexToAddress(addr)
-	ws.RegisterOrderConnection(a, c)
+	err = ws.RegisterOrderConnection(a, c)
+	if err != nil {
+		c.SendMessage(ws.OrderChannel, types.ERROR, err)
+		return
+	}
+
 	ws.SendOrderMessage(types.INIT, a, nil)
 }
 
@@ -589,7 +966,11 @@ func (e *orderEndpoint) handleWSNewOrder(ev *types.WebsocketEvent, c *ws.Client)
 		return
 	}
 
-	ws.RegisterOrderConnection(o.UserAddress, c)
+	err = ws.RegisterOrderConnection(o.UserAddress, c)
+	if err != nil {
+		c.SendOrderErrorMessage(err, o.Hash)
+		return
+	}
 
 	acc, err := e.accountService.GetByAddress(o.UserAddress)
 	if err != nil {
@@ -626,7 +1007,11 @@ func (e *orderEndpoint) handleWSCancelOrder(ev *types.WebsocketEvent, c *ws.Clie
 		c.SendOrderErrorMessage(err, oc.Hash)
 	}
 
-	ws.RegisterOrderConnection(addr, c)
+	err = ws.RegisterOrderConnection(addr, c)
+	if err != nil {
+		c.SendOrderErrorMessage(err, oc.Hash)
+		return
+	}
 
 	orderErr := e.orderService.CancelOrder(oc)
 	if orderErr != nil {
@@ -636,6 +1021,241 @@ func (e *orderEndpoint) handleWSCancelOrder(ev *types.WebsocketEvent, c *ws.Clie
 	}
 }
 
+// handleWSNewOrders handles NewOrders message. The result of each order is sent back in a BATCH_RESULT message
+func (e *orderEndpoint) handleWSNewOrders(ev *types.WebsocketEvent, c *ws.Client) {
+	var orders []*types.Order
+	bytes, err := json.Marshal(ev.Payload)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	err = json.Unmarshal(bytes, &orders)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	if len(orders) == 0 || len(orders) > maxBatchSize {
+		c.SendMessage(ws.OrderChannel, types.ERROR, fmt.Sprintf("Batch should contain between 1 and %d orders", maxBatchSize))
+		return
+	}
+
+	err = e.checkAccounts(orders)
+	if err != nil {
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	for _, o := range orders {
+		err = ws.RegisterOrderConnection(o.UserAddress, c)
+		if err != nil {
+			c.SendMessage(ws.OrderChannel, types.ERROR, err)
+			return
+		}
+	}
+
+	res, err := e.orderService.NewOrders(orders)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	c.SendMessage(ws.OrderChannel, types.BATCH_RESULT, res)
+}
+
+// handleWSCancelOrders handles CancelOrders message. The result of each cancel is sent back in a BATCH_RESULT message
+func (e *orderEndpoint) handleWSCancelOrders(ev *types.WebsocketEvent, c *ws.Client) {
+	var ocs []*types.OrderCancel
+	bytes, err := json.Marshal(ev.Payload)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	err = json.Unmarshal(bytes, &ocs)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	if len(ocs) == 0 || len(ocs) > maxBatchSize {
+		c.SendMessage(ws.OrderChannel, types.ERROR, fmt.Sprintf("Batch should contain between 1 and %d cancels", maxBatchSize))
+		return
+	}
+
+	for _, oc := range ocs {
+		if oc == nil || oc.Signature == nil {
+			c.SendMessage(ws.OrderChannel, types.ERROR, "Invalid payload")
+			return
+		}
+
+		addr, err := oc.GetSenderAddress()
+		if err == nil {
+			err = ws.RegisterOrderConnection(addr, c)
+		}
+
+		if err != nil {
+			c.SendMessage(ws.OrderChannel, types.ERROR, err)
+			return
+		}
+	}
+
+	c.SendMessage(ws.OrderChannel, types.BATCH_RESULT, e.orderService.CancelOrders(ocs))
+}
+
+// handleWSReplaceOrder handles ReplaceOrder message. The replacement waits for the engine, so it
+// runs in the background until the connection is closed, and its result is sent as an
+// ORDER_REPLACED or ERROR message
+func (e *orderEndpoint) handleWSReplaceOrder(ev *types.WebsocketEvent, c *ws.Client) {
+	or := &types.OrderReplace{}
+	errInvalidPayload := map[string]string{"Message": "Invalid payload"}
+	bytes, err := json.Marshal(ev.Payload)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	err = json.Unmarshal(bytes, &or)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	if or == nil {
+		c.SendMessage(ws.OrderChannel, types.ERROR, errInvalidPayload)
+		return
+	}
+
+	if err := or.Validate(); err != nil {
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	err = ws.RegisterOrderConnection(or.Order.UserAddress, c)
+	if err != nil {
+		c.SendOrderErrorMessage(err, or.Order.Hash)
+		return
+	}
+
+	acc, err := e.accountService.GetByAddress(or.Order.UserAddress)
+	if err != nil {
+		logger.Error(err)
+		c.SendOrderErrorMessage(err, or.Order.Hash)
+		return
+	}
+
+	if acc.IsBlocked {
+		c.SendOrderErrorMessage(errors.New("Account is blocked"), or.Order.Hash)
+		return
+	}
+
+	go func() {
+		_, err := e.orderService.ReplaceOrder(c.Context(), or)
+		if err != nil {
+			logger.Error(err)
+			c.SendOrderErrorMessage(err, or.Order.Hash)
+		}
+	}()
+}
+
+func (e *orderEndpoint) handleWSNewStopOrder(ev *types.WebsocketEvent, c *ws.Client) {
+	so := &types.StopOrder{}
+	errInvalidPayload := map[string]string{"Message": "Invalid payload"}
+	bytes, err := json.Marshal(ev.Payload)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	err = json.Unmarshal(bytes, &so)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	if so == nil {
+		c.SendMessage(ws.OrderChannel, types.ERROR, errInvalidPayload)
+		return
+	}
+
+	if err := so.Validate(); err != nil {
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	err = ws.RegisterOrderConnection(so.UserAddress, c)
+	if err != nil {
+		c.SendOrderErrorMessage(err, so.Hash)
+		return
+	}
+
+	acc, err := e.accountService.GetByAddress(so.UserAddress)
+	if err != nil {
+		logger.Error(err)
+		c.SendOrderErrorMessage(err, so.Hash)
+		return
+	}
+
+	if acc.IsBlocked {
+		c.SendOrderErrorMessage(errors.New("Account is blocked"), so.Hash)
+		return
+	}
+
+	err = e.stopOrderService.NewStopOrder(so)
+	if err != nil {
+		logger.Error(err)
+		c.SendOrderErrorMessage(err, so.Hash)
+		return
+	}
+}
+
+func (e *orderEndpoint) handleWSCancelStopOrder(ev *types.WebsocketEvent, c *ws.Client) {
+	oc := &types.OrderCancel{}
+	bytes, err := json.Marshal(ev.Payload)
+	if err != nil {
+		logger.Error(err)
+		c.SendMessage(ws.OrderChannel, types.ERROR, err.Error())
+		return
+	}
+
+	err = json.Unmarshal(bytes, &oc)
+	if err != nil {
+		logger.Error(err)
+		c.SendOrderErrorMessage(err, oc.Hash)
+		return
+	}
+
+	addr, err := oc.GetSenderAddress()
+	if err != nil {
+		logger.Error(err)
+		c.SendOrderErrorMessage(err, oc.Hash)
+		return
+	}
+
+	err = ws.RegisterOrderConnection(addr, c)
+	if err != nil {
+		c.SendOrderErrorMessage(err, oc.Hash)
+		return
+	}
+
+	err = e.stopOrderService.CancelStopOrder(oc)
+	if err != nil {
+		logger.Error(err)
+		c.SendOrderErrorMessage(err, oc.Hash)
+		return
+	}
+}
+
 func (e *orderEndpoint) handleGetOrderNonce(w http.ResponseWriter, r *http.Request) {
 	v := r.URL.Query()
 	addr := v.Get("address")
@@ -652,6 +1272,10 @@ func (e *orderEndpoint) handleGetOrderNonce(w http.ResponseWriter, r *http.Reque
 
 	a := common.HexToAddress(addr)
 
+	if !isAuthorized(w, r, a) {
+		return
+	}
+
 	total, err := e.orderService.GetOrderNonceByUserAddress(a)
 	if err != nil {
 		logger.Error(err)
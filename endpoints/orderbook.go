@@ -3,6 +3,7 @@ package endpoints
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
@@ -55,9 +56,38 @@ func (e *OrderBookEndpoint) handleGetOrderBook(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	opts := types.OrderBookOptions{}
+	if depth := v.Get("depth"); depth != "" {
+		d, err := strconv.Atoi(depth)
+		if err != nil || d < 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid depth")
+			return
+		}
+
+		opts.Depth = d
+	}
+
+	if grouping := v.Get("grouping"); grouping != "" {
+		g, err := strconv.Atoi(grouping)
+		if err != nil || g < 0 {
+			httputils.WriteError(w, http.StatusBadRequest, "Invalid grouping")
+			return
+		}
+
+		opts.Grouping = &g
+	}
+
 	baseTokenAddress := common.HexToAddress(bt)
 	quoteTokenAddress := common.HexToAddress(qt)
-	ob, err := e.orderBookService.GetOrderBook(baseTokenAddress, quoteTokenAddress)
+
+	var ob *types.OrderBook
+	var err error
+	if opts.IsDefault() {
+		ob, err = e.orderBookService.GetOrderBook(baseTokenAddress, quoteTokenAddress)
+	} else {
+		ob, err = e.orderBookService.GetAggregatedOrderBook(baseTokenAddress, quoteTokenAddress, opts)
+	}
+
 	if err != nil {
 		logger.Error(err)
 		httputils.WriteError(w, http.StatusInternalServerError, err.Error())
@@ -181,6 +211,10 @@ func (e *OrderBookEndpoint) rawOrderBookWebSocket(input interface{}, c *ws.Clien
 	if ev.Type == types.SUBSCRIBE {
 		e.orderBookService.SubscribeRawOrderBook(c, p.BaseToken, p.QuoteToken)
 	}
+
+	if ev.Type == types.RESYNC {
+		e.orderBookService.ResyncRawOrderBook(c, p.BaseToken, p.QuoteToken)
+	}
 }
 
 func (e *OrderBookEndpoint) orderBookWebSocket(input interface{}, c *ws.Client) {
@@ -197,7 +231,7 @@ func (e *OrderBookEndpoint) orderBookWebSocket(input interface{}, c *ws.Client)
 		socket.SendErrorMessage(c, errInvalidPayload)
 		return
 	}
-	if ev.Type != types.SUBSCRIBE && ev.Type != types.UNSUBSCRIBE {
+	if ev.Type != types.SUBSCRIBE && ev.Type != types.UNSUBSCRIBE && ev.Type != types.RESYNC {
 		logger.Info("Event Type", ev.Type)
 		socket.SendErrorMessage(c, errInvalidPayload)
 		return
@@ -230,7 +264,17 @@ func (e *OrderBookEndpoint) orderBookWebSocket(input interface{}, c *ws.Client)
 			return
 		}
 
-		e.orderBookService.SubscribeOrderBook(c, p.BaseToken, p.QuoteToken)
+		opts := types.OrderBookOptions{Depth: p.Depth, Grouping: p.Grouping}
+		e.orderBookService.SubscribeOrderBook(c, p.BaseToken, p.QuoteToken, opts)
+	}
+
+	if ev.Type == types.RESYNC {
+		if p == nil {
+			socket.SendErrorMessage(c, errInvalidPayload)
+			return
+		}
+
+		e.orderBookService.ResyncOrderBook(c, p.BaseToken, p.QuoteToken)
 	}
 
 	if ev.Type == types.UNSUBSCRIBE {
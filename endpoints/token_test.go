@@ -9,6 +9,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/mock"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/testutils"
 	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
@@ -17,13 +18,26 @@ import (
 func SetupTest() (*mux.Router, *mocks.TokenService) {
 	r := mux.NewRouter()
 	tokenService := new(mocks.TokenService)
+	relayerService := new(mocks.RelayerService)
+	relayerService.On("GetRelayerAddress", mock.Anything).Return(common.Address{})
 
-	ServeTokenResource(r, tokenService)
+	ServeTokenResource(r, tokenService, relayerService)
 
 	return r, tokenService
 }
 
+// decodeData decodes the data of a response into v
+func decodeData(rr *httptest.ResponseRecorder, v interface{}) error {
+	res := struct {
+		Data interface{} `json:"data"`
+	}{v}
+
+	return json.NewDecoder(rr.Body).Decode(&res)
+}
+
 func TestHandleCreateTokens(t *testing.T) {
+	t.Skip("POST /api/tokens is disabled in ServeTokenResource")
+
 	router, tokenService := SetupTest()
 
 	token := types.Token{
@@ -50,7 +64,7 @@ func TestHandleCreateTokens(t *testing.T) {
 	}
 
 	created := types.Token{}
-	json.NewDecoder(rr.Body).Decode(&created)
+	decodeData(rr, &created)
 
 	tokenService.AssertCalled(t, "Create", &token)
 	testutils.CompareToken(t, &token, &created)
@@ -62,7 +76,7 @@ func TestHandleGetTokens(t *testing.T) {
 	t1 := testutils.GetTestZRXToken()
 	t2 := testutils.GetTestWETHToken()
 
-	tokenService.On("GetAll").Return([]types.Token{t1, t2}, nil)
+	tokenService.On("GetAllByCoinbase", common.Address{}).Return([]types.Token{t1, t2}, nil)
 
 	req, err := http.NewRequest("GET", "/api/tokens", nil)
 	if err != nil {
@@ -77,9 +91,9 @@ func TestHandleGetTokens(t *testing.T) {
 	}
 
 	result := []types.Token{}
-	json.NewDecoder(rr.Body).Decode(&result)
+	decodeData(rr, &result)
 
-	tokenService.AssertCalled(t, "GetAll")
+	tokenService.AssertCalled(t, "GetAllByCoinbase", common.Address{})
 	testutils.CompareToken(t, &t1, &result[0])
 	testutils.CompareToken(t, &t2, &result[1])
 }
@@ -118,7 +132,7 @@ func TestHandleGetQuoteTokens(t *testing.T) {
 	}
 
 	result := []types.Token{}
-	json.NewDecoder(rr.Body).Decode(&result)
+	decodeData(rr, &result)
 
 	tokenService.AssertCalled(t, "GetQuoteTokens")
 	testutils.CompareToken(t, &t1, &result[0])
@@ -159,7 +173,7 @@ func TestHandleGetBaseTokens(t *testing.T) {
 	}
 
 	result := []types.Token{}
-	json.NewDecoder(rr.Body).Decode(&result)
+	decodeData(rr, &result)
 
 	tokenService.AssertCalled(t, "GetBaseTokens")
 	testutils.CompareToken(t, &t1, &result[0])
@@ -195,7 +209,7 @@ func TestHandleGetToken(t *testing.T) {
 	}
 
 	result := types.Token{}
-	json.NewDecoder(rr.Body).Decode(&result)
+	decodeData(rr, &result)
 
 	tokenService.AssertCalled(t, "GetByAddress", addr)
 	testutils.Compare(t, &t1, &result)
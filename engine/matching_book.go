@@ -0,0 +1,258 @@
+package engine
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// matchingBook is the in-memory orderbook of a pair used by the MatchingEngine.
+// Both sides are kept sorted by price then by arrival, so that the first order of
+// a side is always the next one to be matched (price-time priority)
+type matchingBook struct {
+	pair *types.Pair
+	// bids are sorted by decreasing price
+	bids []*types.Order
+	// asks are sorted by increasing price
+	asks []*types.Order
+}
+
+func newMatchingBook(p *types.Pair) *matchingBook {
+	return &matchingBook{
+		pair: p,
+		bids: []*types.Order{},
+		asks: []*types.Order{},
+	}
+}
+
+// match fills the taker order against the opposite side of the book. Trades are
+// priced at the maker price. The maker orders that are completely filled are
+// removed from the book, and the remaining amount of a limit order is added to it.
+// Market orders and IOC orders are never added to the book: their remaining amount
+// is cancelled. A FOK order is cancelled without any trade unless it can be filled completely,
+// and a post-only order that crosses the book is rejected
+func (b *matchingBook) match(taker *types.Order) ([]*types.Order, []*types.Trade) {
+	makers := []*types.Order{}
+	trades := []*types.Trade{}
+
+	if taker.FilledAmount == nil {
+		taker.FilledAmount = big.NewInt(0)
+	}
+
+	if maker := b.best(oppositeSide(taker.Side)); taker.PostOnly && maker != nil && crosses(taker, maker) {
+		taker.Status = types.OrderStatusRejected
+		return makers, trades
+	}
+
+	if taker.TimeInForce == types.TimeInForceFOK && math.IsStrictlySmallerThan(b.crossingAmount(taker), taker.RemainingAmount()) {
+		taker.Status = types.OrderStatusCancelled
+		return makers, trades
+	}
+
+	for math.IsStrictlyGreaterThan(taker.RemainingAmount(), big.NewInt(0)) {
+		maker := b.best(oppositeSide(taker.Side))
+		if maker == nil || !crosses(taker, maker) {
+			break
+		}
+
+		amount := taker.RemainingAmount()
+		if math.IsStrictlySmallerThan(maker.RemainingAmount(), amount) {
+			amount = maker.RemainingAmount()
+		}
+
+		maker.FilledAmount = math.Add(maker.FilledAmount, amount)
+		taker.FilledAmount = math.Add(taker.FilledAmount, amount)
+		maker.Status = filledStatus(maker)
+
+		if maker.Status == types.OrderStatusFilled {
+			b.remove(maker.Hash)
+		}
+
+		makers = append(makers, maker)
+		trades = append(trades, newTrade(b.pair, taker, maker, amount))
+	}
+
+	taker.Status = filledStatus(taker)
+	if taker.Status == types.OrderStatusFilled {
+		return makers, trades
+	}
+
+	if taker.Type == types.TypeMarketOrder || taker.TimeInForce == types.TimeInForceIOC || taker.TimeInForce == types.TimeInForceFOK {
+		taker.Status = types.OrderStatusCancelled
+		return makers, trades
+	}
+
+	b.add(taker)
+	return makers, trades
+}
+
+// bookState is the state of a book and of its orders saved before a match, so that the match
+// can be undone
+type bookState struct {
+	bids   []*types.Order
+	asks   []*types.Order
+	orders map[*types.Order]orderState
+}
+
+type orderState struct {
+	filledAmount *big.Int
+	status       string
+}
+
+// save returns the current state of the book and of its orders
+func (b *matchingBook) save() *bookState {
+	s := &bookState{
+		bids:   append([]*types.Order{}, b.bids...),
+		asks:   append([]*types.Order{}, b.asks...),
+		orders: make(map[*types.Order]orderState),
+	}
+
+	for _, o := range append(s.bids, s.asks...) {
+		s.orders[o] = orderState{o.FilledAmount, o.Status}
+	}
+
+	return s
+}
+
+// restore puts the book and its orders back in a saved state
+func (b *matchingBook) restore(s *bookState) {
+	b.bids = s.bids
+	b.asks = s.asks
+
+	for o, state := range s.orders {
+		o.FilledAmount = state.filledAmount
+		o.Status = state.status
+	}
+}
+
+// crossingAmount returns the amount available on the opposite side of the book at the
+// price of the taker order or better
+func (b *matchingBook) crossingAmount(taker *types.Order) *big.Int {
+	orders := b.asks
+	if taker.Side == types.SELL {
+		orders = b.bids
+	}
+
+	amount := big.NewInt(0)
+	for _, maker := range orders {
+		if !crosses(taker, maker) {
+			break
+		}
+
+		amount = math.Add(amount, maker.RemainingAmount())
+	}
+
+	return amount
+}
+
+// add inserts an order after all the orders of the same side with the same or a better price
+func (b *matchingBook) add(o *types.Order) {
+	if o.Side == types.BUY {
+		i := 0
+		for i < len(b.bids) && math.IsEqualOrGreaterThan(b.bids[i].PricePoint, o.PricePoint) {
+			i++
+		}
+
+		b.bids = append(b.bids[:i], append([]*types.Order{o}, b.bids[i:]...)...)
+		return
+	}
+
+	i := 0
+	for i < len(b.asks) && math.IsEqualOrSmallerThan(b.asks[i].PricePoint, o.PricePoint) {
+		i++
+	}
+
+	b.asks = append(b.asks[:i], append([]*types.Order{o}, b.asks[i:]...)...)
+}
+
+// remove deletes an order from the book and returns it, or nil if the book does not contain it
+func (b *matchingBook) remove(h common.Hash) *types.Order {
+	for i, o := range b.bids {
+		if o.Hash == h {
+			b.bids = append(b.bids[:i], b.bids[i+1:]...)
+			return o
+		}
+	}
+
+	for i, o := range b.asks {
+		if o.Hash == h {
+			b.asks = append(b.asks[:i], b.asks[i+1:]...)
+			return o
+		}
+	}
+
+	return nil
+}
+
+func (b *matchingBook) best(side string) *types.Order {
+	orders := b.asks
+	if side == types.BUY {
+		orders = b.bids
+	}
+
+	if len(orders) == 0 {
+		return nil
+	}
+
+	return orders[0]
+}
+
+// crosses returns true if the taker order accepts the price of the maker order
+func crosses(taker, maker *types.Order) bool {
+	if taker.Type == types.TypeMarketOrder {
+		return true
+	}
+
+	if taker.Side == types.BUY {
+		return math.IsEqualOrSmallerThan(maker.PricePoint, taker.PricePoint)
+	}
+
+	return math.IsEqualOrGreaterThan(maker.PricePoint, taker.PricePoint)
+}
+
+func oppositeSide(side string) string {
+	if side == types.BUY {
+		return types.SELL
+	}
+
+	return types.BUY
+}
+
+func filledStatus(o *types.Order) string {
+	if math.IsEqualOrGreaterThan(o.FilledAmount, o.Amount) {
+		return types.OrderStatusFilled
+	}
+
+	if math.IsStrictlyGreaterThan(o.FilledAmount, big.NewInt(0)) {
+		return types.OrderStatusPartialFilled
+	}
+
+	return types.OrderStatusOpen
+}
+
+// newTrade returns the trade of the given amount between a taker and a maker order.
+// The simulated engine does not charge any fee
+func newTrade(p *types.Pair, taker, maker *types.Order, amount *big.Int) *types.Trade {
+	t := &types.Trade{
+		Taker:          taker.UserAddress,
+		Maker:          maker.UserAddress,
+		BaseToken:      p.BaseTokenAddress,
+		QuoteToken:     p.QuoteTokenAddress,
+		MakerOrderHash: maker.Hash,
+		TakerOrderHash: taker.Hash,
+		PairName:       p.Name(),
+		PricePoint:     maker.PricePoint,
+		Amount:         amount,
+		MakeFee:        big.NewInt(0),
+		TakeFee:        big.NewInt(0),
+		Status:         types.TradeStatusSuccess,
+		TakerOrderSide: taker.Side,
+		TakerOrderType: taker.Type,
+		MakerOrderType: maker.Type,
+	}
+
+	t.Hash = t.ComputeHash()
+	return t
+}
@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func newTestMatchingBook() *matchingBook {
+	return newMatchingBook(&types.Pair{
+		BaseTokenSymbol:   "ZRX",
+		BaseTokenAddress:  common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteTokenSymbol:  "TOMO",
+		QuoteTokenAddress: common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+	})
+}
+
+func newTestBookOrder(hash string, side string, orderType string, price int64, amount int64) *types.Order {
+	return &types.Order{
+		UserAddress:  common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
+		Hash:         common.HexToHash(hash),
+		Side:         side,
+		Type:         orderType,
+		PricePoint:   big.NewInt(price),
+		Amount:       big.NewInt(amount),
+		FilledAmount: big.NewInt(0),
+	}
+}
+
+func TestMatchingBookAddsUnmatchedOrder(t *testing.T) {
+	b := newTestMatchingBook()
+	o := newTestBookOrder("0x1", types.BUY, types.TypeLimitOrder, 100, 10)
+
+	makers, trades := b.match(o)
+
+	assert.Empty(t, makers)
+	assert.Empty(t, trades)
+	assert.Equal(t, types.OrderStatusOpen, o.Status)
+	assert.Equal(t, o, b.best(types.BUY))
+}
+
+func TestMatchingBookPriceTimePriority(t *testing.T) {
+	b := newTestMatchingBook()
+	first := newTestBookOrder("0x1", types.SELL, types.TypeLimitOrder, 101, 5)
+	second := newTestBookOrder("0x2", types.SELL, types.TypeLimitOrder, 100, 5)
+	third := newTestBookOrder("0x3", types.SELL, types.TypeLimitOrder, 100, 5)
+	b.match(first)
+	b.match(second)
+	b.match(third)
+
+	taker := newTestBookOrder("0x4", types.BUY, types.TypeLimitOrder, 101, 12)
+	makers, trades := b.match(taker)
+
+	assert.Equal(t, []*types.Order{second, third, first}, makers)
+	assert.Equal(t, 3, len(trades))
+	assert.Equal(t, big.NewInt(100), trades[0].PricePoint)
+	assert.Equal(t, big.NewInt(101), trades[2].PricePoint)
+	assert.Equal(t, big.NewInt(2), trades[2].Amount)
+	assert.Equal(t, second.Hash, trades[0].MakerOrderHash)
+	assert.Equal(t, taker.Hash, trades[0].TakerOrderHash)
+
+	assert.Equal(t, types.OrderStatusFilled, taker.Status)
+	assert.Equal(t, types.OrderStatusFilled, second.Status)
+	assert.Equal(t, types.OrderStatusPartialFilled, first.Status)
+	assert.Equal(t, first, b.best(types.SELL))
+	assert.Nil(t, b.best(types.BUY))
+}
+
+func TestMatchingBookDoesNotCrossLimitPrice(t *testing.T) {
+	b := newTestMatchingBook()
+	ask := newTestBookOrder("0x1", types.SELL, types.TypeLimitOrder, 100, 5)
+	b.match(ask)
+
+	bid := newTestBookOrder("0x2", types.BUY, types.TypeLimitOrder, 99, 5)
+	makers, trades := b.match(bid)
+
+	assert.Empty(t, makers)
+	assert.Empty(t, trades)
+	assert.Equal(t, bid, b.best(types.BUY))
+	assert.Equal(t, ask, b.best(types.SELL))
+}
+
+func TestMatchingBookCancelsMarketOrderRemainder(t *testing.T) {
+	b := newTestMatchingBook()
+	b.match(newTestBookOrder("0x1", types.BUY, types.TypeLimitOrder, 100, 5))
+
+	o := newTestBookOrder("0x2", types.SELL, types.TypeMarketOrder, 0, 8)
+	_, trades := b.match(o)
+
+	assert.Equal(t, 1, len(trades))
+	assert.Equal(t, big.NewInt(5), o.FilledAmount)
+	assert.Equal(t, types.OrderStatusCancelled, o.Status)
+	assert.Nil(t, b.best(types.BUY))
+	assert.Nil(t, b.best(types.SELL))
+}
+
+func TestMatchingBookTimeInForce(t *testing.T) {
+	b := newTestMatchingBook()
+	b.match(newTestBookOrder("0x1", types.SELL, types.TypeLimitOrder, 100, 5))
+
+	fok := newTestBookOrder("0x2", types.BUY, types.TypeLimitOrder, 100, 8)
+	fok.TimeInForce = types.TimeInForceFOK
+	_, trades := b.match(fok)
+
+	assert.Empty(t, trades)
+	assert.Equal(t, types.OrderStatusCancelled, fok.Status)
+	assert.Equal(t, big.NewInt(5), b.best(types.SELL).RemainingAmount())
+
+	ioc := newTestBookOrder("0x3", types.BUY, types.TypeLimitOrder, 100, 8)
+	ioc.TimeInForce = types.TimeInForceIOC
+	_, trades = b.match(ioc)
+
+	assert.Equal(t, 1, len(trades))
+	assert.Equal(t, big.NewInt(5), ioc.FilledAmount)
+	assert.Equal(t, types.OrderStatusCancelled, ioc.Status)
+	assert.Nil(t, b.best(types.BUY))
+	assert.Nil(t, b.best(types.SELL))
+}
+
+func TestMatchingBookRejectsCrossingPostOnlyOrder(t *testing.T) {
+	b := newTestMatchingBook()
+	ask := newTestBookOrder("0x1", types.SELL, types.TypeLimitOrder, 100, 5)
+	b.match(ask)
+
+	o := newTestBookOrder("0x2", types.BUY, types.TypeLimitOrder, 100, 5)
+	o.PostOnly = true
+	_, trades := b.match(o)
+
+	assert.Empty(t, trades)
+	assert.Equal(t, types.OrderStatusRejected, o.Status)
+	assert.Equal(t, ask, b.best(types.SELL))
+	assert.Nil(t, b.best(types.BUY))
+
+	o = newTestBookOrder("0x3", types.BUY, types.TypeLimitOrder, 99, 5)
+	o.PostOnly = true
+	b.match(o)
+
+	assert.Equal(t, types.OrderStatusOpen, o.Status)
+	assert.Equal(t, o, b.best(types.BUY))
+}
+
+func TestMatchingBookRemove(t *testing.T) {
+	b := newTestMatchingBook()
+	o := newTestBookOrder("0x1", types.BUY, types.TypeLimitOrder, 100, 5)
+	b.match(o)
+
+	assert.Nil(t, b.remove(common.HexToHash("0x2")))
+	assert.Equal(t, o, b.remove(o.Hash))
+	assert.Nil(t, b.best(types.BUY))
+}
+
+func TestMatchingBookRestore(t *testing.T) {
+	b := newTestMatchingBook()
+	m1 := newTestBookOrder("0x1", types.SELL, types.TypeLimitOrder, 100, 5)
+	m2 := newTestBookOrder("0x2", types.SELL, types.TypeLimitOrder, 101, 5)
+	b.match(m1)
+	b.match(m2)
+
+	state := b.save()
+
+	taker := newTestBookOrder("0x3", types.BUY, types.TypeLimitOrder, 101, 7)
+	b.match(taker)
+
+	b.restore(state)
+
+	assert.Equal(t, []*types.Order{m1, m2}, b.asks)
+	assert.Empty(t, b.bids)
+	assert.Equal(t, big.NewInt(0), m1.FilledAmount)
+	assert.Equal(t, types.OrderStatusOpen, m1.Status)
+	assert.Equal(t, big.NewInt(0), m2.FilledAmount)
+	assert.Equal(t, types.OrderStatusOpen, m2.Status)
+}
@@ -0,0 +1,280 @@
+package engine
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/rabbitmq"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// responsePublisher publishes the engine responses consumed by the order and trade services
+type responsePublisher interface {
+	PublishOrderResponse(res *types.EngineResponse) error
+	PublishTradeResponse(res *types.EngineResponse) error
+}
+
+// MatchingEngine is an in-process price-time priority matching engine used in place of the
+// TomoX node to run the SDK offline. It stores the orders and trades in mongo the same way
+// the node does, and publishes the order and trade responses that the node changes would
+// otherwise produce through the mongo change streams
+type MatchingEngine struct {
+	books     map[string]*matchingBook
+	publisher responsePublisher
+	orderDao  interfaces.OrderDao
+	tradeDao  interfaces.TradeDao
+	pairDao   interfaces.PairDao
+	provider  interfaces.EthereumProvider
+	mutex     sync.Mutex
+}
+
+// NewMatchingEngine returns a matching engine with the open orders of all the pairs loaded in its books
+func NewMatchingEngine(
+	rabbitMQConn *rabbitmq.Connection,
+	orderDao interfaces.OrderDao,
+	tradeDao interfaces.TradeDao,
+	pairDao interfaces.PairDao,
+	provider interfaces.EthereumProvider,
+) *MatchingEngine {
+	e := &MatchingEngine{
+		books:     make(map[string]*matchingBook),
+		publisher: rabbitMQConn,
+		orderDao:  orderDao,
+		tradeDao:  tradeDao,
+		pairDao:   pairDao,
+		provider:  provider,
+	}
+
+	pairs, err := pairDao.GetAll()
+	if err != nil {
+		panic(err)
+	}
+
+	for i := range pairs {
+		e.books[pairs[i].Code()] = newMatchingBook(&pairs[i])
+	}
+
+	orders, err := orderDao.GetOpenOrders()
+	if err != nil {
+		panic(err)
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+
+	for _, o := range orders {
+		code, _ := o.PairCode()
+		if b, ok := e.books[code]; ok {
+			b.add(o)
+		}
+	}
+
+	return e
+}
+
+// Provider : implement engine interface
+func (e *MatchingEngine) Provider() interfaces.EthereumProvider {
+	return e.provider
+}
+
+// HandleOrders parses incoming rabbitmq order messages and matches them against the in-memory orderbooks
+func (e *MatchingEngine) HandleOrders(msg *rabbitmq.Message) error {
+	o := &types.Order{}
+	err := json.Unmarshal(msg.Data, o)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	switch msg.Type {
+	case "NEW_ORDER":
+		err = e.newOrder(o)
+	case "CANCEL_ORDER":
+		err = e.cancelOrder(o)
+	default:
+		logger.Error("Unknown message", msg)
+	}
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (e *MatchingEngine) getBook(o *types.Order) (*matchingBook, error) {
+	code, err := o.PairCode()
+	if err != nil {
+		return nil, err
+	}
+
+	if b, ok := e.books[code]; ok {
+		return b, nil
+	}
+
+	p, err := e.pairDao.GetByTokenAddress(o.BaseToken, o.QuoteToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, errors.New("Orderbook error")
+	}
+
+	b := newMatchingBook(p)
+	e.books[code] = b
+
+	return b, nil
+}
+
+// newOrder matches an order, stores the taker order, the maker orders and the trades,
+// then publishes the order responses followed by the trade responses. The match is undone if
+// the taker order can not be stored. If the maker orders or the trades can not be stored, the
+// taker order is rejected with an ERROR response instead
+func (e *MatchingEngine) newOrder(o *types.Order) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	b, err := e.getBook(o)
+	if err != nil {
+		return err
+	}
+
+	state := b.save()
+	makers, trades := b.match(o)
+
+	err = e.orderDao.Create(o)
+	if err != nil {
+		b.restore(state)
+		return err
+	}
+
+	err = e.saveMatches(trades)
+	if err != nil {
+		logger.Error(err)
+		b.restore(state)
+		e.rejectOrder(o)
+		return nil
+	}
+
+	e.publishOrderResponse(&types.EngineResponse{
+		Status:  orderResponseStatus(o),
+		Order:   o,
+		Matches: &types.Matches{MakerOrders: makers, TakerOrder: o, Trades: trades},
+	})
+
+	for _, m := range makers {
+		e.publishOrderResponse(&types.EngineResponse{Status: orderResponseStatus(m), Order: m})
+	}
+
+	for _, t := range trades {
+		err := e.publisher.PublishTradeResponse(&types.EngineResponse{Status: types.TradeAdded, Trade: t})
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// saveMatches stores the filled amounts of the maker orders and the trades of a match. The
+// filled amounts already stored are reverted if it fails
+func (e *MatchingEngine) saveMatches(trades []*types.Trade) error {
+	for i, t := range trades {
+		err := e.orderDao.UpdateOrderFilledAmount(t.MakerOrderHash, t.Amount)
+		if err != nil {
+			e.revertFilledAmounts(trades[:i])
+			return err
+		}
+	}
+
+	if len(trades) > 0 {
+		err := e.tradeDao.Create(trades...)
+		if err != nil {
+			e.revertFilledAmounts(trades)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *MatchingEngine) revertFilledAmounts(trades []*types.Trade) {
+	for _, t := range trades {
+		err := e.orderDao.UpdateOrderFilledAmount(t.MakerOrderHash, math.Neg(t.Amount))
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
+// rejectOrder stores a taker order whose match was undone as rejected, and publishes an
+// ERROR response for it
+func (e *MatchingEngine) rejectOrder(o *types.Order) {
+	o.FilledAmount = big.NewInt(0)
+	o.Status = types.OrderStatusRejected
+
+	err := e.orderDao.UpsertByHash(o.Hash, o)
+	if err != nil {
+		logger.Error(err)
+	}
+
+	e.publishOrderResponse(&types.EngineResponse{Status: types.ERROR_STATUS, Order: o})
+}
+
+// cancelOrder removes an order from its orderbook and marks it as cancelled
+func (e *MatchingEngine) cancelOrder(o *types.Order) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	b, err := e.getBook(o)
+	if err != nil {
+		return err
+	}
+
+	removed := b.remove(o.Hash)
+	if removed == nil {
+		return errors.New("No order with corresponding hash")
+	}
+
+	removed.Status = types.OrderStatusCancelled
+
+	err = e.orderDao.UpdateOrderStatus(removed.Hash, types.OrderStatusCancelled)
+	if err != nil {
+		b.add(removed)
+		return err
+	}
+
+	e.publishOrderResponse(&types.EngineResponse{Status: types.ORDER_CANCELLED, Order: removed})
+
+	return nil
+}
+
+func (e *MatchingEngine) publishOrderResponse(res *types.EngineResponse) {
+	err := e.publisher.PublishOrderResponse(res)
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+func orderResponseStatus(o *types.Order) string {
+	switch o.Status {
+	case types.OrderStatusFilled:
+		return types.ORDER_FILLED
+	case types.OrderStatusPartialFilled:
+		return types.ORDER_PARTIALLY_FILLED
+	case types.OrderStatusCancelled:
+		return types.ORDER_CANCELLED
+	case types.OrderStatusRejected:
+		return types.ORDER_REJECTED
+	default:
+		return types.ORDER_ADDED
+	}
+}
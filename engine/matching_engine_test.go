@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
+)
+
+type testPublisher struct {
+	orders []*types.EngineResponse
+	trades []*types.EngineResponse
+}
+
+func (p *testPublisher) PublishOrderResponse(res *types.EngineResponse) error {
+	p.orders = append(p.orders, res)
+	return nil
+}
+
+func (p *testPublisher) PublishTradeResponse(res *types.EngineResponse) error {
+	p.trades = append(p.trades, res)
+	return nil
+}
+
+func newTestMatchingEngine(b *matchingBook, orderDao *mocks.OrderDao, tradeDao *mocks.TradeDao, publisher *testPublisher) *MatchingEngine {
+	return &MatchingEngine{
+		books:     map[string]*matchingBook{b.pair.Code(): b},
+		publisher: publisher,
+		orderDao:  orderDao,
+		tradeDao:  tradeDao,
+	}
+}
+
+func TestMatchingEngineUndoesMatchWhenTakerIsNotStored(t *testing.T) {
+	orderDao := new(mocks.OrderDao)
+	tradeDao := new(mocks.TradeDao)
+	publisher := &testPublisher{}
+
+	b := newTestMatchingBook()
+	maker := newTestBookOrder("0x1", types.SELL, types.TypeLimitOrder, 100, 5)
+	b.match(maker)
+
+	e := newTestMatchingEngine(b, orderDao, tradeDao, publisher)
+
+	taker := newTestBookOrder("0x2", types.BUY, types.TypeLimitOrder, 100, 5)
+	taker.BaseToken = b.pair.BaseTokenAddress
+	taker.QuoteToken = b.pair.QuoteTokenAddress
+	taker.PairName = b.pair.Name()
+
+	orderDao.On("Create", taker).Return(errors.New("create failed"))
+
+	err := e.newOrder(taker)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, maker, b.best(types.SELL))
+	assert.Equal(t, types.OrderStatusOpen, maker.Status)
+	assert.Empty(t, publisher.orders)
+}
+
+func TestMatchingEngineRejectsTakerWhenTradesAreNotStored(t *testing.T) {
+	orderDao := new(mocks.OrderDao)
+	tradeDao := new(mocks.TradeDao)
+	publisher := &testPublisher{}
+
+	b := newTestMatchingBook()
+	maker := newTestBookOrder("0x1", types.SELL, types.TypeLimitOrder, 100, 5)
+	b.match(maker)
+
+	e := newTestMatchingEngine(b, orderDao, tradeDao, publisher)
+
+	taker := newTestBookOrder("0x2", types.BUY, types.TypeLimitOrder, 100, 3)
+	taker.BaseToken = b.pair.BaseTokenAddress
+	taker.QuoteToken = b.pair.QuoteTokenAddress
+	taker.PairName = b.pair.Name()
+
+	orderDao.On("Create", taker).Return(nil)
+	orderDao.On("UpdateOrderFilledAmount", maker.Hash, mock.Anything).Return(nil)
+	orderDao.On("UpsertByHash", taker.Hash, taker).Return(nil)
+	tradeDao.On("Create", mock.Anything).Return(errors.New("create failed"))
+
+	err := e.newOrder(taker)
+	assert.Nil(t, err)
+
+	// the filled amount of the maker is stored then reverted
+	orderDao.AssertNumberOfCalls(t, "UpdateOrderFilledAmount", 2)
+	assert.Equal(t, big.NewInt(-3), orderDao.Calls[2].Arguments.Get(1))
+
+	assert.Equal(t, maker, b.best(types.SELL))
+	assert.Equal(t, big.NewInt(0), maker.FilledAmount)
+	assert.Nil(t, b.best(types.BUY))
+
+	assert.Equal(t, types.OrderStatusRejected, taker.Status)
+	assert.Len(t, publisher.orders, 1)
+	assert.Equal(t, types.ERROR_STATUS, publisher.orders[0].Status)
+	assert.Empty(t, publisher.trades)
+}
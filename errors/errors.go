@@ -27,6 +27,42 @@ func Unauthorized(err string) *APIError {
 	return NewHTTPError(http.StatusUnauthorized, "UNAUTHORIZED", Params{"error": err})
 }
 
+// OrderWouldCross creates a new API error representing a post-only order that would take liquidity
+// from the orderbook (HTTP 400)
+func OrderWouldCross(side string, pricepoint, best string) *APIError {
+	return NewHTTPError(http.StatusBadRequest, "POST_ONLY_ORDER_WOULD_CROSS", Params{"side": side, "pricepoint": pricepoint, "best": best})
+}
+
+// ClientOrderIDInUse creates a new API error representing a client order ID that the user already
+// used for another order (HTTP 409)
+func ClientOrderIDInUse(clientOrderID string) *APIError {
+	return NewHTTPError(http.StatusConflict, "CLIENT_ORDER_ID_IN_USE", Params{"clientOrderId": clientOrderID})
+}
+
+// ClientOrderPending creates a new API error representing the retry of an order whose first
+// submission is still being processed (HTTP 409)
+func ClientOrderPending(clientOrderID string) *APIError {
+	return NewHTTPError(http.StatusConflict, "CLIENT_ORDER_PENDING", Params{"clientOrderId": clientOrderID})
+}
+
+// NonceInUse creates a new API error representing an order nonce held by another order of the
+// user which has not been processed by the node yet (HTTP 409)
+func NonceInUse(nonce string, next string) *APIError {
+	return NewHTTPError(http.StatusConflict, "NONCE_IN_USE", Params{"nonce": nonce, "next": next})
+}
+
+// NonceTooLow creates a new API error representing an order nonce already counted by the
+// node (HTTP 400)
+func NonceTooLow(nonce string, next string) *APIError {
+	return NewHTTPError(http.StatusBadRequest, "NONCE_TOO_LOW", Params{"nonce": nonce, "next": next})
+}
+
+// ReplaceOrderFailed creates a new API error representing an order replacement whose new
+// order failed after the replaced order was cancelled (HTTP 409)
+func ReplaceOrderFailed(cancelled string, order string, reason string) *APIError {
+	return NewHTTPError(http.StatusConflict, "REPLACE_ORDER_FAILED", Params{"cancelled": cancelled, "order": order, "error": reason})
+}
+
 // InvalidData converts a data validation error into an API error (HTTP 400)
 func InvalidData(errs validation.Errors) *APIError {
 	result := []validationError{}
@@ -28,3 +28,35 @@ func TestInvalidData(t *testing.T) {
 func TestNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, NotFound("abc").Status)
 }
+
+func TestOrderWouldCross(t *testing.T) {
+	err := OrderWouldCross("BUY", "101", "100")
+	assert.Equal(t, http.StatusBadRequest, err.Status)
+	assert.Equal(t, "POST_ONLY_ORDER_WOULD_CROSS", err.ErrorCode)
+}
+
+func TestClientOrderErrors(t *testing.T) {
+	err := ClientOrderIDInUse("abc")
+	assert.Equal(t, http.StatusConflict, err.Status)
+	assert.Equal(t, "CLIENT_ORDER_ID_IN_USE", err.ErrorCode)
+
+	err = ClientOrderPending("abc")
+	assert.Equal(t, http.StatusConflict, err.Status)
+	assert.Equal(t, "CLIENT_ORDER_PENDING", err.ErrorCode)
+}
+
+func TestNonceErrors(t *testing.T) {
+	err := NonceInUse("1", "2")
+	assert.Equal(t, http.StatusConflict, err.Status)
+	assert.Equal(t, "NONCE_IN_USE", err.ErrorCode)
+
+	err = NonceTooLow("1", "2")
+	assert.Equal(t, http.StatusBadRequest, err.Status)
+	assert.Equal(t, "NONCE_TOO_LOW", err.ErrorCode)
+}
+
+func TestReplaceOrderFailed(t *testing.T) {
+	err := ReplaceOrderFailed("0x1", "0x2", "rejected")
+	assert.Equal(t, http.StatusConflict, err.Status)
+	assert.Equal(t, "REPLACE_ORDER_FAILED", err.ErrorCode)
+}
@@ -10,27 +10,36 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	eth "github.com/ethereum/go-ethereum/core/types"
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/rabbitmq"
 	"github.com/tomochain/tomox-sdk/relayer"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ChangeStream is a stream of the changes of a collection. Next decodes the next change into
+// result, and returns false if there is none for now or if the stream failed, as told by Err.
+// ResumeToken is the token of the last change returned by Next, to resume a stream after it
+type ChangeStream interface {
+	Next(result interface{}) bool
+	Err() error
+	Close() error
+	ResumeToken() []byte
+}
+
 type OrderDao interface {
-	GetCollection() *mgo.Collection
 	Create(o *types.Order) error
-	Watch() (*mgo.ChangeStream, *mgo.Session, error)
-	Update(id bson.ObjectId, o *types.Order) error
-	Upsert(id bson.ObjectId, o *types.Order) error
+	Watch(resumeAfter []byte) (ChangeStream, error)
+	Update(id primitive.ObjectID, o *types.Order) error
+	Upsert(id primitive.ObjectID, o *types.Order) error
 	Delete(orders ...*types.Order) error
 	DeleteByHashes(hashes ...common.Hash) error
 	UpdateAllByHash(h common.Hash, o *types.Order) error
 	UpdateByHash(h common.Hash, o *types.Order) error
 	UpsertByHash(h common.Hash, o *types.Order) error
 	GetOrderCountByUserAddress(addr common.Address) (int, error)
-	GetByID(id bson.ObjectId) (*types.Order, error)
+	GetByID(id primitive.ObjectID) (*types.Order, error)
 	GetByHash(h common.Hash) (*types.Order, error)
 	GetByHashes(hashes []common.Hash) ([]*types.Order, error)
 	GetByUserAddress(addr, bt, qt common.Address, from, to int64, limit ...int) ([]*types.Order, error)
@@ -59,21 +68,45 @@ type OrderDao interface {
 
 type StopOrderDao interface {
 	Create(so *types.StopOrder) error
-	Update(id bson.ObjectId, so *types.StopOrder) error
+	Update(id primitive.ObjectID, so *types.StopOrder) error
 	UpdateByHash(h common.Hash, so *types.StopOrder) error
-	Upsert(id bson.ObjectId, so *types.StopOrder) error
-	UpsertByHash(h common.Hash, so *types.StopOrder) error
-	UpdateAllByHash(h common.Hash, so *types.StopOrder) error
 	GetByHash(h common.Hash) (*types.StopOrder, error)
-	FindAndModify(h common.Hash, so *types.StopOrder) (*types.StopOrder, error)
-	GetTriggeredStopOrders(baseToken, quoteToken common.Address, lastPrice *big.Int) ([]*types.StopOrder, error)
+	UpdateOpenStopOrderStatus(h common.Hash, status string) (bool, error)
+	GetOpenStopOrdersByUserAddress(addr common.Address) ([]*types.StopOrder, error)
+	GetTriggeredStopOrders(baseToken, quoteToken common.Address, lowPrice, highPrice *big.Int) ([]*types.StopOrder, error)
+	Drop() error
+}
+
+type OrderExpiryDao interface {
+	Create(e *types.OrderExpiry) error
+	GetByOrderHash(h common.Hash) (*types.OrderExpiry, error)
+	GetDueOrderExpiries(t time.Time) ([]*types.OrderExpiry, error)
+	GetPendingOrderExpiriesByUserAddress(addr common.Address) ([]*types.OrderExpiry, error)
+	UpdatePendingOrderExpiryStatus(h common.Hash, status string) (bool, error)
+	Drop() error
+}
+
+// ClientOrderDao stores the client order IDs of the users until they expire
+type ClientOrderDao interface {
+	Reserve(c *types.ClientOrder) (*types.ClientOrder, error)
+	GetByClientOrderID(addr common.Address, clientOrderID string) (*types.ClientOrder, error)
+	Accept(c *types.ClientOrder) error
+	Release(c *types.ClientOrder) error
+	Drop() error
+}
+
+// OperatorActionDao stores the audit log of the orders signed with the operator wallets
+type OperatorActionDao interface {
+	Create(a *types.OperatorAction) error
+	SetError(id primitive.ObjectID, msg string) error
+	GetOperatorActions(spec types.OperatorActionSpec, offset int, size int) (*types.OperatorActionRes, error)
 	Drop() error
 }
 
 type AccountDao interface {
 	Create(account *types.Account) (err error)
 	GetAll() (res []types.Account, err error)
-	GetByID(id bson.ObjectId) (*types.Account, error)
+	GetByID(id primitive.ObjectID) (*types.Account, error)
 	GetByAddress(owner common.Address) (response *types.Account, err error)
 	GetTokenBalances(owner common.Address) (map[common.Address]*types.TokenBalance, error)
 	GetTokenBalance(owner common.Address, token common.Address) (*types.TokenBalance, error)
@@ -104,6 +137,10 @@ type ConfigDao interface {
 	ResetBlockCounters() error
 	GetBlockToProcess(chain types.Chain) (uint64, error)
 	SaveLastProcessedBlock(chain types.Chain, block uint64) error
+	GetResumeToken(stream string) ([]byte, error)
+	SaveResumeToken(stream string, token []byte) error
+	AcquireLease(name string, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(name string, owner string) error
 	Drop()
 }
 
@@ -120,7 +157,7 @@ type AssociationDao interface {
 type WalletDao interface {
 	Create(wallet *types.Wallet) error
 	GetAll() ([]types.Wallet, error)
-	GetByID(id bson.ObjectId) (*types.Wallet, error)
+	GetByID(id primitive.ObjectID) (*types.Wallet, error)
 	GetByAddress(addr common.Address) (*types.Wallet, error)
 	GetDefaultAdminWallet() (*types.Wallet, error)
 	GetOperatorWallets() ([]*types.Wallet, error)
@@ -132,7 +169,7 @@ type PairDao interface {
 	GetAllByCoinbase(addr common.Address) ([]types.Pair, error)
 	GetActivePairs() ([]*types.Pair, error)
 	GetActivePairsByCoinbase(addr common.Address) ([]*types.Pair, error)
-	GetByID(id bson.ObjectId) (*types.Pair, error)
+	GetByID(id primitive.ObjectID) (*types.Pair, error)
 	GetByName(name string) (*types.Pair, error)
 	GetByTokenSymbols(baseTokenSymbol, quoteTokenSymbol string) (*types.Pair, error)
 	GetByTokenAddress(baseToken, quoteToken common.Address) (*types.Pair, error)
@@ -143,9 +180,8 @@ type PairDao interface {
 }
 
 type TradeDao interface {
-	GetCollection() *mgo.Collection
 	Create(o ...*types.Trade) error
-	Watch() (*mgo.ChangeStream, *mgo.Session, error)
+	Watch(resumeAfter []byte) (ChangeStream, error)
 	Update(t *types.Trade) error
 	UpdateByHash(h common.Hash, t *types.Trade) error
 	GetAll() ([]types.Trade, error)
@@ -172,13 +208,37 @@ type TradeDao interface {
 	GetTradeByTime(dateFrom, dateTo int64, pageOffset int, pageSize int) ([]*types.Trade, error)
 }
 
+type OHLCVDao interface {
+	UpsertTicks(ticks []*types.Tick) error
+	GetTicks(from int64) ([]*types.Tick, error)
+	GetTicksUpdatedSince(since time.Time) ([]*types.Tick, error)
+	GetLastTick() (*types.Tick, error)
+	DeleteTicks(duration int64, unit string, to int64) error
+	GetTicksInRange(r *types.TickRange) ([]*types.Tick, error)
+	ReplaceTicks(r *types.TickRange, ticks []*types.Tick) error
+	Drop() error
+}
+
+type APIKeyDao interface {
+	Create(k *types.APIKey) error
+	GetByKey(key string) (*types.APIKey, error)
+	GetByUserAddress(addr common.Address) ([]*types.APIKey, error)
+	Revoke(addr common.Address, key string) error
+	Drop() error
+}
+
+type RequestNonceDao interface {
+	Use(addr common.Address, nonce string, expiresAt time.Time) (bool, error)
+	Drop() error
+}
+
 type TokenDao interface {
 	Create(token *types.Token) error
 	UpdateByToken(contractAddress common.Address, token *types.Token) error
 	UpdateByTokenAndCoinbase(contractAddress common.Address, addr common.Address, token *types.Token) error
 	GetAll() ([]types.Token, error)
 	GetAllByCoinbase(addr common.Address) ([]types.Token, error)
-	GetByID(id bson.ObjectId) (*types.Token, error)
+	GetByID(id primitive.ObjectID) (*types.Token, error)
 	GetByAddress(addr common.Address) (*types.Token, error)
 	GetQuoteTokens() ([]types.Token, error)
 	GetBaseTokens() ([]types.Token, error)
@@ -193,16 +253,16 @@ type NotificationDao interface {
 	GetAll() ([]types.Notification, error)
 	GetByUserAddress(addr common.Address, limit int, offset int) ([]*types.Notification, error)
 	GetSortDecByUserAddress(addr common.Address, limit int, offset int) ([]*types.Notification, error)
-	GetByID(id bson.ObjectId) (*types.Notification, error)
-	FindAndModify(id bson.ObjectId, n *types.Notification) (*types.Notification, error)
+	GetByID(id primitive.ObjectID) (*types.Notification, error)
+	FindAndModify(id primitive.ObjectID, n *types.Notification) (*types.Notification, error)
 	Update(n *types.Notification) error
-	Upsert(id bson.ObjectId, n *types.Notification) error
+	Upsert(id primitive.ObjectID, n *types.Notification) error
 	Delete(notifications ...*types.Notification) error
-	DeleteByIds(ids ...bson.ObjectId) error
+	DeleteByIds(ids ...primitive.ObjectID) error
 	Aggregate(q []bson.M) ([]*types.Notification, error)
 	Drop()
-	MarkRead(id bson.ObjectId) error
-	MarkUnRead(id bson.ObjectId) error
+	MarkRead(id primitive.ObjectID) error
+	MarkUnRead(id primitive.ObjectID) error
 	MarkAllRead(addr common.Address) error
 }
 
@@ -223,6 +283,14 @@ type WalletService interface {
 	GetByAddress(addr common.Address) (*types.Wallet, error)
 }
 
+// OperatorService signs and places the orders of the operator wallets for the operators
+type OperatorService interface {
+	IsOperator(addr common.Address) bool
+	NewOrder(operator common.Address, o *types.Order) (*types.Order, error)
+	CancelOrder(operator common.Address, orderHash common.Hash) (*types.OrderCancel, error)
+	GetOperatorActions(spec types.OperatorActionSpec, offset int, size int) (*types.OperatorActionRes, error)
+}
+
 type OHLCVService interface {
 	Unsubscribe(c *ws.Client)
 	UnsubscribeChannel(c *ws.Client, p *types.SubscriptionPayload)
@@ -245,7 +313,7 @@ type EthereumService interface {
 type OrderService interface {
 	GetOrdersLockedBalanceByUserAddress(addr common.Address) (map[string]*big.Int, error)
 	GetOrderCountByUserAddress(addr common.Address) (int, error)
-	GetByID(id bson.ObjectId) (*types.Order, error)
+	GetByID(id primitive.ObjectID) (*types.Order, error)
 	GetByHash(h common.Hash) (*types.Order, error)
 	GetByHashes(hashes []common.Hash) ([]*types.Order, error)
 	// GetTokenByAddress(a common.Address) (*types.Token, error)
@@ -253,21 +321,37 @@ type OrderService interface {
 	GetCurrentByUserAddress(a common.Address, limit ...int) ([]*types.Order, error)
 	GetHistoryByUserAddress(a, bt, qt common.Address, from, to int64, limit ...int) ([]*types.Order, error)
 	NewOrder(o *types.Order) error
+	NewOrders(orders []*types.Order) ([]*types.OrderBatchResult, error)
 	CancelOrder(oc *types.OrderCancel) error
+	CancelOrders(ocs []*types.OrderCancel) []*types.OrderBatchResult
+	ReplaceOrder(ctx context.Context, r *types.OrderReplace) (*types.OrderReplaceResult, error)
 	CancelAllOrder(a common.Address) error
 	HandleEngineResponse(res *types.EngineResponse) error
 	GetOrders(orderSpec types.OrderSpec, sort []string, offset int, size int) (*types.OrderRes, error)
 	GetOrderNonceByUserAddress(addr common.Address) (interface{}, error)
+	HoldOrderNonce(addr common.Address, nonce *big.Int, h common.Hash) error
+	ReleaseOrderNonce(addr common.Address, nonce *big.Int, h common.Hash)
+}
+
+type StopOrderService interface {
+	GetByHash(h common.Hash) (*types.StopOrder, error)
+	GetOpenStopOrdersByUserAddress(addr common.Address) ([]*types.StopOrder, error)
+	NewStopOrder(so *types.StopOrder) error
+	CancelStopOrder(oc *types.OrderCancel) error
+	HandleTrade(t *types.Trade)
 }
 
 type OrderBookService interface {
 	GetOrderBook(bt, qt common.Address) (*types.OrderBook, error)
 	GetDbOrderBook(bt, qt common.Address) (*types.OrderBook, error)
 	GetRawOrderBook(bt, qt common.Address) (*types.RawOrderBook, error)
-	SubscribeOrderBook(c *ws.Client, bt, qt common.Address)
+	GetAggregatedOrderBook(bt, qt common.Address, opts types.OrderBookOptions) (*types.OrderBook, error)
+	SubscribeOrderBook(c *ws.Client, bt, qt common.Address, opts types.OrderBookOptions)
+	ResyncOrderBook(c *ws.Client, bt, qt common.Address)
 	UnsubscribeOrderBook(c *ws.Client)
 	UnsubscribeOrderBookChannel(c *ws.Client, bt, qt common.Address)
 	SubscribeRawOrderBook(c *ws.Client, bt, qt common.Address)
+	ResyncRawOrderBook(c *ws.Client, bt, qt common.Address)
 	UnsubscribeRawOrderBook(c *ws.Client)
 	UnsubscribeRawOrderBookChannel(c *ws.Client, bt, qt common.Address)
 }
@@ -275,7 +359,7 @@ type OrderBookService interface {
 type PairService interface {
 	Create(pair *types.Pair) error
 	CreatePairs(token common.Address) ([]*types.Pair, error)
-	GetByID(id bson.ObjectId) (*types.Pair, error)
+	GetByID(id primitive.ObjectID) (*types.Pair, error)
 	GetByTokenAddress(bt, qt common.Address) (*types.Pair, error)
 	GetTokenPairData(bt, qt common.Address) (*types.PairData, error)
 	GetAllTokenPairData() ([]*types.PairData, error)
@@ -289,7 +373,7 @@ type PairService interface {
 
 type TokenService interface {
 	Create(token *types.Token) error
-	GetByID(id bson.ObjectId) (*types.Token, error)
+	GetByID(id primitive.ObjectID) (*types.Token, error)
 	GetByAddress(a common.Address) (*types.Token, error)
 	GetAll() ([]types.Token, error)
 	GetAllByCoinbase(addr common.Address) ([]types.Token, error)
@@ -312,6 +396,7 @@ type TradeService interface {
 	Unsubscribe(c *ws.Client)
 	GetTrades(tradeSpec *types.TradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.TradeRes, error)
 	GetTradesUserHistory(a common.Address, tradeSpec *types.TradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.TradeRes, error)
+	RegisterNotify(fn func(*types.Trade))
 }
 
 type PriceBoardService interface {
@@ -331,10 +416,10 @@ type NotificationService interface {
 	GetAll() ([]types.Notification, error)
 	GetByUserAddress(a common.Address, limit int, offset int) ([]*types.Notification, error)
 	GetSortDecByUserAddress(addr common.Address, limit int, offset int) ([]*types.Notification, error)
-	GetByID(id bson.ObjectId) (*types.Notification, error)
+	GetByID(id primitive.ObjectID) (*types.Notification, error)
 	Update(n *types.Notification) (*types.Notification, error)
-	MarkRead(id bson.ObjectId) error
-	MarkUnRead(id bson.ObjectId) error
+	MarkRead(id primitive.ObjectID) error
+	MarkUnRead(id primitive.ObjectID) error
 	MarkAllRead(addr common.Address) error
 }
 
@@ -346,10 +431,16 @@ type TxService interface {
 	GetCustomTxSendOptions(w *types.Wallet) *bind.TransactOpts
 }
 
+type APIKeyService interface {
+	Create(addr common.Address, req *types.APIKeyRequest) (*types.APIKey, error)
+	GetByUserAddress(addr common.Address) ([]*types.APIKey, error)
+	Revoke(addr common.Address, key string) error
+}
+
 type AccountService interface {
 	GetAll() ([]types.Account, error)
 	Create(account *types.Account) error
-	GetByID(id bson.ObjectId) (*types.Account, error)
+	GetByID(id primitive.ObjectID) (*types.Account, error)
 	GetByAddress(a common.Address) (*types.Account, error)
 	FindOrCreate(a common.Address) (*types.Account, error)
 	GetTokenBalance(owner common.Address, token common.Address) (*types.TokenBalance, error)
@@ -364,6 +455,8 @@ type AccountService interface {
 type ValidatorService interface {
 	ValidateBalance(o *types.Order) error
 	ValidateAvailableBalance(o *types.Order) error
+	ValidateAvailableBalances(orders []*types.Order) (map[common.Hash]error, error)
+	ValidateReplacingBalance(o *types.Order, replaced *types.Order) error
 }
 
 type EthereumConfig interface {
@@ -431,7 +524,7 @@ type LendingOrderService interface {
 // LendingOrderDao dao
 type LendingOrderDao interface {
 	GetByHash(h common.Hash) (*types.LendingOrder, error)
-	Watch() (*mgo.ChangeStream, *mgo.Session, error)
+	Watch(resumeAfter []byte) (ChangeStream, error)
 	GetLendingNonce(addr common.Address) (uint64, error)
 	AddNewLendingOrder(o *types.LendingOrder) error
 	CancelLendingOrder(o *types.LendingOrder) error
@@ -449,6 +542,7 @@ type LendingOrderBookService interface {
 	GetLendingOrderBook(term uint64, lendingToken common.Address) (*types.LendingOrderBook, error)
 	GetLendingOrderBookInDb(term uint64, lendingToken common.Address) (*types.LendingOrderBook, error)
 	SubscribeLendingOrderBook(c *ws.Client, term uint64, lendingToken common.Address)
+	ResyncLendingOrderBook(c *ws.Client, term uint64, lendingToken common.Address)
 	UnsubscribeLendingOrderBook(c *ws.Client)
 	UnsubscribeLendingOrderBookChannel(c *ws.Client, term uint64, lendingToken common.Address)
 }
@@ -467,13 +561,23 @@ type LendingTradeService interface {
 // LendingTradeDao interface for lending dao
 type LendingTradeDao interface {
 	GetLendingTradeByOrderBook(tern uint64, lendingToken common.Address, from, to int64, n int) ([]*types.LendingTrade, error)
-	Watch() (*mgo.ChangeStream, *mgo.Session, error)
+	Watch(resumeAfter []byte) (ChangeStream, error)
 	GetLendingTradeByTime(dateFrom, dateTo int64, pageOffset int, pageSize int) ([]*types.LendingTrade, error)
 	GetLendingTradesUserHistory(a common.Address, lendingtradeSpec *types.LendingTradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.LendingTradeRes, error)
 	GetLendingTrades(lendingtradeSpec *types.LendingTradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.LendingTradeRes, error)
 	GetByHash(hash common.Hash) (*types.LendingTrade, error)
 }
 
+// LendingOhlcvDao interface for lending ohlcv dao
+type LendingOhlcvDao interface {
+	UpsertTicks(ticks []*types.LendingTick) error
+	GetTicks(from int64) ([]*types.LendingTick, error)
+	GetTicksUpdatedSince(since time.Time) ([]*types.LendingTick, error)
+	GetLastTick() (*types.LendingTick, error)
+	DeleteTicks(duration int64, unit string, to int64) error
+	Drop() error
+}
+
 // LendingOhlcvService interface for lending service
 type LendingOhlcvService interface {
 	GetOHLCV(term uint64, lendingToken common.Address, duration int64, unit string, timeInterval ...int64) ([]*types.LendingTick, error)
@@ -513,3 +617,30 @@ type LendingPriceBoardService interface {
 	UnsubscribeChannel(c *ws.Client, term uint64, lendingToken common.Address)
 	Unsubscribe(c *ws.Client)
 }
+
+// ChangeStreamService runs the change streams of the collections. Watch consumes a stream until
+// the service is stopped, resuming it after its last handled event when it is opened again
+type ChangeStreamService interface {
+	Watch(name string, watch func(resumeAfter []byte) (ChangeStream, error), handle func(ev bson.Raw))
+	Stats() []*types.ChangeStreamStats
+	Stop()
+}
+
+// LeaderService elects one of the replicas of the server as the leader. Campaign blocks until
+// the replica is elected, and lost is called if it can not keep the leadership
+type LeaderService interface {
+	Campaign(lost func()) bool
+	IsLeader() bool
+	Resign()
+}
+
+// DeadLetterQueue keeps the messages rejected by the consumers of the RabbitMQ queues after their
+// retries. GetDeadLetters returns the first ones and leaves them in the queue
+type DeadLetterQueue interface {
+	GetDeadLetters(limit int) (*types.DeadLetters, error)
+}
+
+// NodeMonitor returns the health of the TomoX nodes, as of their last call or health check
+type NodeMonitor interface {
+	Nodes() []*types.NodeStatus
+}
@@ -3,5 +3,5 @@ package main
 import "github.com/tomochain/tomox-sdk/server"
 
 func main() {
-	server.Start()
+	server.Execute()
 }
@@ -0,0 +1,108 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+)
+
+// APIKeyVerifier authenticates the REST requests sent with an API key
+type APIKeyVerifier struct {
+	apiKeyDao interfaces.APIKeyDao
+	nonceDao  interfaces.RequestNonceDao
+}
+
+// NewAPIKeyVerifier returns a new instance of APIKeyVerifier
+func NewAPIKeyVerifier(apiKeyDao interfaces.APIKeyDao, nonceDao interfaces.RequestNonceDao) *APIKeyVerifier {
+	return &APIKeyVerifier{apiKeyDao: apiKeyDao, nonceDao: nonceDao}
+}
+
+// Authenticate returns a middleware that authenticates the requests sent with an API-Key header
+// as the user address of the key, which is exposed on the request context as for a signed
+// request. The key should not be revoked, should have the scope and should allow the IP of the
+// request, and the request should be signed with the API-Signature, Timestamp and Nonce
+// headers (see types.APIKey.Sign). Requests without an API key are passed on as they are
+func (v *APIKeyVerifier) Authenticate(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("API-Key")
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			signature := r.Header.Get("API-Signature")
+			timestamp := r.Header.Get("Timestamp")
+			nonce := r.Header.Get("Nonce")
+
+			if signature == "" || timestamp == "" || nonce == "" {
+				httputils.WriteError(w, http.StatusUnauthorized, "API-Signature, Timestamp and Nonce headers are required")
+				return
+			}
+
+			k, err := v.apiKeyDao.GetByKey(key)
+			if err != nil {
+				httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			if k == nil || k.Revoked {
+				httputils.WriteError(w, http.StatusUnauthorized, "Invalid API key")
+				return
+			}
+
+			if !k.AllowsIP(remoteIP(r)) {
+				httputils.WriteError(w, http.StatusForbidden, "IP is not allowed for this API key")
+				return
+			}
+
+			if !k.HasScope(scope) {
+				httputils.WriteError(w, http.StatusForbidden, "API key does not have the "+scope+" scope")
+				return
+			}
+
+			req, err := signedRequest(w, r, timestamp, nonce)
+			if err == errBodyTooLarge {
+				httputils.WriteError(w, http.StatusRequestEntityTooLarge, err.Error())
+				return
+			}
+
+			if err != nil {
+				httputils.WriteError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+
+			if !k.VerifySignature(req, signature) {
+				httputils.WriteError(w, http.StatusUnauthorized, "Signature Invalid")
+				return
+			}
+
+			ok, err := v.nonceDao.Use(k.UserAddress, nonce, time.Unix(req.Timestamp, 0).Add(signatureWindow))
+			if err != nil {
+				httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			if !ok {
+				httputils.WriteError(w, http.StatusUnauthorized, "Nonce already used")
+				return
+			}
+
+			serveAuthenticated(w, r, next, k.UserAddress)
+		})
+	}
+}
+
+// remoteIP returns the IP the request is sent from. The API servers are expected to be
+// reached directly, forwarding headers are not trusted
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return net.ParseIP(host)
+}
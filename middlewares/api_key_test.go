@@ -0,0 +1,114 @@
+package middlewares
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
+)
+
+func TestAPIKeyAuthenticate(t *testing.T) {
+	apiKeyDao := new(mocks.APIKeyDao)
+	nonceDao := new(mocks.RequestNonceDao)
+	v := NewAPIKeyVerifier(apiKeyDao, nonceDao)
+
+	owner := common.HexToAddress("0x1")
+	key := &types.APIKey{
+		Key:         "key",
+		Secret:      "secret",
+		UserAddress: owner,
+		Scopes:      []string{types.APIKeyScopeRead, types.APIKeyScopeTrade},
+		IPs:         []string{"10.0.0.0/24"},
+	}
+
+	revoked := *key
+	revoked.Key = "revoked"
+	revoked.Revoked = true
+
+	apiKeyDao.On("GetByKey", "key").Return(key, nil)
+	apiKeyDao.On("GetByKey", "revoked").Return(&revoked, nil)
+	apiKeyDao.On("GetByKey", "unknown").Return(nil, nil)
+	nonceDao.On("Use", owner, "1", mock.Anything).Return(true, nil)
+	nonceDao.On("Use", owner, "2", mock.Anything).Return(false, nil)
+
+	var authenticated common.Address
+	var ok bool
+	handler := func(scope string) http.Handler {
+		return v.Authenticate(scope)(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			authenticated, ok = AuthenticatedAddress(r)
+		}))
+	}
+
+	now := time.Now().Unix()
+	newRequest := func(k *types.APIKey, body, ip, nonce string) *http.Request {
+		r := httptest.NewRequest("POST", "/api/orders?a=1", bytes.NewBufferString(body))
+		r.RemoteAddr = ip + ":4000"
+		req := &types.SignedRequest{
+			Method:    r.Method,
+			Path:      r.URL.EscapedPath(),
+			Query:     r.URL.Query(),
+			Body:      []byte(body),
+			Timestamp: now,
+			Nonce:     nonce,
+		}
+
+		r.Header.Set("API-Key", k.Key)
+		r.Header.Set("API-Signature", k.Sign(req))
+		r.Header.Set("Timestamp", fmt.Sprintf("%d", now))
+		r.Header.Set("Nonce", nonce)
+		return r
+	}
+
+	rr := httptest.NewRecorder()
+	handler(types.APIKeyScopeTrade).ServeHTTP(rr, newRequest(key, `{}`, "10.0.0.1", "1"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, ok)
+	assert.Equal(t, owner, authenticated)
+
+	// the key does not have the lending scope
+	rr = httptest.NewRecorder()
+	handler(types.APIKeyScopeLending).ServeHTTP(rr, newRequest(key, `{}`, "10.0.0.1", "1"))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// the IP is not in the allowlist
+	rr = httptest.NewRecorder()
+	handler(types.APIKeyScopeTrade).ServeHTTP(rr, newRequest(key, `{}`, "10.0.1.1", "1"))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// the key is revoked or unknown
+	rr = httptest.NewRecorder()
+	handler(types.APIKeyScopeTrade).ServeHTTP(rr, newRequest(&revoked, `{}`, "10.0.0.1", "1"))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler(types.APIKeyScopeTrade).ServeHTTP(rr, newRequest(&types.APIKey{Key: "unknown"}, `{}`, "10.0.0.1", "1"))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// the nonce was already used
+	rr = httptest.NewRecorder()
+	handler(types.APIKeyScopeTrade).ServeHTTP(rr, newRequest(key, `{}`, "10.0.0.1", "2"))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// the body is not the signed one
+	r := newRequest(key, `{}`, "10.0.0.1", "1")
+	r.Body = httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"amount":"1"}`)).Body
+
+	rr = httptest.NewRecorder()
+	handler(types.APIKeyScopeTrade).ServeHTTP(rr, r)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// requests without an API key are passed on
+	ok = false
+	rr = httptest.NewRecorder()
+	handler(types.APIKeyScopeTrade).ServeHTTP(rr, httptest.NewRequest("GET", "/api/orders", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.False(t, ok)
+}
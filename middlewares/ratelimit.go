@@ -0,0 +1,83 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/utils/httputils"
+	"github.com/tomochain/tomox-sdk/utils/ratelimit"
+)
+
+const rateLimitContextKey contextKey = "rateLimit"
+
+// RateLimiter limits the rate of the requests of each IP and each authenticated address in the
+// endpoint groups of ratelimit
+type RateLimiter struct {
+	limiter *ratelimit.Limiter
+}
+
+// groupLimit is the group of a request and the limiter of its authenticated address
+type groupLimit struct {
+	limiter *ratelimit.Limiter
+	group   string
+}
+
+// NewRateLimiter returns a new instance of RateLimiter
+func NewRateLimiter(limiter *ratelimit.Limiter) *RateLimiter {
+	return &RateLimiter{limiter: limiter}
+}
+
+// Limit limits the requests of each IP in the group of the request, and is meant to be used by
+// the router for all the routes. The requests authenticated with a signature or an API key are
+// also limited for their address once they are authenticated. The rejected requests get a 429
+// response with a Retry-After header
+func (l *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		group := requestGroup(r)
+
+		if ok, retry := l.limiter.AllowIP(group, remoteIP(r).String()); !ok {
+			writeTooManyRequests(w, retry)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), rateLimitContextKey, &groupLimit{l.limiter, group})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requestGroup returns the endpoint group of a request
+func requestGroup(r *http.Request) string {
+	if r.URL.Path == "/socket" {
+		return ratelimit.GroupWebsocket
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead &&
+		(strings.HasPrefix(r.URL.Path, "/api/orders") || strings.HasPrefix(r.URL.Path, "/api/lending")) {
+		return ratelimit.GroupOrder
+	}
+
+	return ratelimit.GroupQuery
+}
+
+// serveAuthenticated exposes the authenticated address of a request on its context and serves
+// it, unless the address is over the limit of the group of the request
+func serveAuthenticated(w http.ResponseWriter, r *http.Request, next http.Handler, addr common.Address) {
+	if l, ok := r.Context().Value(rateLimitContextKey).(*groupLimit); ok {
+		if ok, retry := l.limiter.AllowAddress(l.group, addr); !ok {
+			writeTooManyRequests(w, retry)
+			return
+		}
+	}
+
+	ctx := context.WithValue(r.Context(), addressContextKey, addr)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+func writeTooManyRequests(w http.ResponseWriter, retry time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(ratelimit.RetryAfter(retry)))
+	httputils.WriteError(w, http.StatusTooManyRequests, "Too many requests")
+}
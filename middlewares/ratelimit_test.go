@@ -0,0 +1,63 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/utils/ratelimit"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Limits{
+		ratelimit.GroupOrder: {
+			IP:      ratelimit.Limit{Rate: 0.1, Burst: 2},
+			Address: ratelimit.Limit{Rate: 0.1, Burst: 1},
+		},
+	})
+
+	addr := common.HexToAddress("0x1")
+	handler := NewRateLimiter(limiter).Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Signature") == "" {
+			return
+		}
+
+		serveAuthenticated(w, r, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), addr)
+	}))
+
+	newRequest := func(method, ip string, signed bool) *http.Request {
+		r := httptest.NewRequest(method, "/api/orders", nil)
+		r.RemoteAddr = ip + ":4000"
+		if signed {
+			r.Header.Set("Signature", "0x1")
+		}
+
+		return r
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest("POST", "10.0.0.1", true))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// the address is over its limit from another IP
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest("POST", "10.0.0.2", true))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "10", rr.Header().Get("Retry-After"))
+
+	// the IP is over its limit
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest("POST", "10.0.0.1", false))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest("POST", "10.0.0.1", false))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+	// the queries are in another group
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest("GET", "10.0.0.1", false))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -1,33 +1,135 @@
 package middlewares
 
 import (
+	"bytes"
+	"io/ioutil"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/httputils"
 )
 
-func VerifySignature(next http.Handler) http.Handler {
+// signatureWindow is how far the timestamp of a signed request can be from the time of the server
+const signatureWindow = 5 * time.Minute
+
+// maxBodySize is the size of the largest body read to check the signature of a request. A batch
+// of 100 orders takes about 100KB
+const maxBodySize = 1 << 20
+
+// errBodyTooLarge is returned when the body of a signed request is larger than maxBodySize
+var errBodyTooLarge = errors.New("Request body is too large")
+
+type contextKey string
+
+const addressContextKey contextKey = "address"
+
+// SignatureVerifier authenticates the REST requests signed by the owner of an address
+type SignatureVerifier struct {
+	nonceDao interfaces.RequestNonceDao
+}
+
+// NewSignatureVerifier returns a new instance of SignatureVerifier
+func NewSignatureVerifier(nonceDao interfaces.RequestNonceDao) *SignatureVerifier {
+	return &SignatureVerifier{nonceDao: nonceDao}
+}
+
+// VerifySignature recovers the address that signed a request from its Signature, Timestamp
+// and Nonce headers, and exposes it on the request context. The signature covers the method,
+// path, query, body, timestamp and nonce of the request (see types.SignedRequest). Requests
+// whose timestamp is more than 5 minutes away from the time of the server are rejected, and
+// a nonce can only be used once by an address while the timestamp is valid. A request already
+// authenticated with an API key is passed on as it is
+func (v *SignatureVerifier) VerifySignature(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := AuthenticatedAddress(r); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		signature := r.Header.Get("Signature")
+		timestamp := r.Header.Get("Timestamp")
+		nonce := r.Header.Get("Nonce")
 
-		if r.Header["Signature"] == nil || r.Header["Hash"] == nil || r.Header["Pubkey"] == nil {
-			httputils.WriteError(w, http.StatusUnauthorized, "There is not enough parameters in header")
+		if signature == "" || timestamp == "" || nonce == "" {
+			httputils.WriteError(w, http.StatusUnauthorized, "Signature, Timestamp and Nonce headers are required")
 			return
 		}
 
-		hash := common.Hex2Bytes(r.Header["Hash"][0])
-		signature := common.Hex2Bytes(r.Header["Signature"][0])
-		publicKeyBytes := common.Hex2Bytes(r.Header["Pubkey"][0])
+		req, err := signedRequest(w, r, timestamp, nonce)
+		if err == errBodyTooLarge {
+			httputils.WriteError(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
 
-		signatureNoRecoverID := signature[:len(signature)-1] // remove recovery id
-		verified := crypto.VerifySignature(publicKeyBytes, hash, signatureNoRecoverID)
+		if err != nil {
+			httputils.WriteError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
 
-		if !verified {
+		addr, err := req.RecoverAddress(signature)
+		if err != nil {
 			httputils.WriteError(w, http.StatusUnauthorized, "Signature Invalid")
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		ok, err := v.nonceDao.Use(addr, nonce, time.Unix(req.Timestamp, 0).Add(signatureWindow))
+		if err != nil {
+			httputils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if !ok {
+			httputils.WriteError(w, http.StatusUnauthorized, "Nonce already used")
+			return
+		}
+
+		serveAuthenticated(w, r, next, addr)
 	})
 }
+
+// signedRequest checks the timestamp of a request and returns the parts of the request covered
+// by its signature. The body is read up to maxBodySize and put back for the next handlers
+func signedRequest(w http.ResponseWriter, r *http.Request, timestamp, nonce string) (*types.SignedRequest, error) {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.New("Invalid Timestamp")
+	}
+
+	t := time.Unix(ts, 0)
+	if time.Since(t) > signatureWindow || time.Until(t) > signatureWindow {
+		return nil, errors.New("Timestamp is expired")
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil && len(body) == maxBodySize {
+		return nil, errBodyTooLarge
+	}
+
+	if err != nil {
+		return nil, errors.New("Invalid payload")
+	}
+
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return &types.SignedRequest{
+		Method:    r.Method,
+		Path:      r.URL.EscapedPath(),
+		Query:     r.URL.Query(),
+		Body:      body,
+		Timestamp: ts,
+		Nonce:     nonce,
+	}, nil
+}
+
+// AuthenticatedAddress returns the address that signed a request verified by VerifySignature,
+// or the address of the API key of a request verified by APIKeyVerifier
+func AuthenticatedAddress(r *http.Request) (common.Address, bool) {
+	addr, ok := r.Context().Value(addressContextKey).(common.Address)
+	return addr, ok
+}
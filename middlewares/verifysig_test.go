@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
+)
+
+func TestVerifySignature(t *testing.T) {
+	nonceDao := new(mocks.RequestNonceDao)
+	v := NewSignatureVerifier(nonceDao)
+	w := types.NewWallet()
+
+	var signer common.Address
+	handler := v.VerifySignature(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		signer, _ = AuthenticatedAddress(r)
+	}))
+
+	newRequest := func(body string, ts int64, nonce string) *http.Request {
+		r := httptest.NewRequest("PUT", "/api/notification/mark/read?b=2&a=1", bytes.NewBufferString(body))
+		req := &types.SignedRequest{
+			Method:    r.Method,
+			Path:      r.URL.EscapedPath(),
+			Query:     r.URL.Query(),
+			Body:      []byte(body),
+			Timestamp: ts,
+			Nonce:     nonce,
+		}
+
+		sig, _ := req.Sign(w)
+		r.Header.Set("Signature", sig)
+		r.Header.Set("Timestamp", fmt.Sprintf("%d", ts))
+		r.Header.Set("Nonce", nonce)
+		return r
+	}
+
+	now := time.Now().Unix()
+	nonceDao.On("Use", w.Address, "1", mock.Anything).Return(true, nil)
+	nonceDao.On("Use", w.Address, "2", mock.Anything).Return(false, nil)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest(`{"_id":"1"}`, now, "1"))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, w.Address, signer)
+
+	// the nonce was already used
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest(`{"_id":"1"}`, now, "2"))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// the timestamp is expired
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest(`{"_id":"1"}`, now-3600, "1"))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// the body is not the signed one, so another address is recovered
+	signer = common.Address{}
+	r := newRequest(`{"_id":"1"}`, now, "1")
+	r.Body = httptest.NewRequest("PUT", "/", bytes.NewBufferString(`{"_id":"2"}`)).Body
+	nonceDao.On("Use", mock.Anything, "1", mock.Anything).Return(true, nil)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, r)
+	assert.NotEqual(t, w.Address, signer)
+
+	// the body is not read past the size limit
+	body := `{"_id":"` + strings.Repeat("1", maxBodySize) + `"}`
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, newRequest(body, now, "1"))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+}
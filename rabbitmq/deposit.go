@@ -12,56 +12,40 @@ import (
 // by default the exchange type is fanout, which means it broadcast all the queues
 // topic is just a list of words, direct is not filterable
 
-// QueuePoolDepositTransactions : return a queue as a channel
+// QueuePoolDepositTransactions : return a queue as a channel. A transaction is acked once it is
+// read from the channel
 func (c *Connection) QueuePoolDepositTransactions() (<-chan *types.DepositTransaction, error) {
-	ch := c.GetChannel("depositSubscribe")
-	q := c.GetQueue(ch, "deposit")
-
-	// get channel
-	msgs, err := c.Consume(ch, q)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
 	out := make(chan *types.DepositTransaction)
 
-	go func() {
-		forever := make(chan bool)
+	err := c.Subscribe("deposit", func(bytes []byte) error {
+		msg := &types.DepositTransaction{}
+		err := json.Unmarshal(bytes, msg)
+		if err != nil {
+			logger.Error(err)
+			return &rejectError{err}
+		}
 
-		go func() {
-			for d := range msgs {
-				msg := &types.DepositTransaction{}
-				err := json.Unmarshal(d.Body, msg)
-				if err != nil {
-					logger.Error(err)
-					continue
-				}
-				// feed this queue forever
-				out <- msg
-			}
-		}()
+		// feed this queue forever
+		out <- msg
+		return nil
+	})
 
-		// wait for background feeding
-		<-forever
-		// if this program is terminate
-		close(out)
-	}()
+	// the consumer keeps being started until it succeeds, and feeds the channel after it
+	if err != nil {
+		logger.Error(err)
+	}
 
 	return out, nil
 }
 
 func (c *Connection) PublishDepositTransaction(transaction *types.DepositTransaction) error {
-	ch := c.GetChannel("depositPublish")
-	q := c.GetQueue(ch, "deposit")
-
 	bytes, err := json.Marshal(transaction)
 	if err != nil {
 		log.Fatal("Failed to marshal deposit: ", err)
 		return errors.New("Failed to marshal deposit: " + err.Error())
 	}
 
-	err = c.Publish(ch, q, bytes)
+	err = c.Publish("deposit", bytes)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -8,41 +8,16 @@ import (
 
 // SubscribeQueue subscribe queue
 func (c *Connection) SubscribeQueue(fn func(*types.EngineResponse) error, queue string) error {
-	ch := c.GetChannel("erSub")
-	q := c.GetQueue(ch, queue)
-
-	go func() {
-		msgs, err := ch.Consume(
-			q.Name, // queue
-			"",     // consumer
-			true,   // auto-ack
-			false,  // exclusive
-			false,  // no-local
-			false,  // no-wait
-			nil,    // args
-		)
-
+	return c.Subscribe(queue, func(bytes []byte) error {
+		var res *types.EngineResponse
+		err := json.Unmarshal(bytes, &res)
 		if err != nil {
-			logger.Fatal("Failed to register a consumer:", err)
+			logger.Error(err)
+			return &rejectError{err}
 		}
 
-		forever := make(chan bool)
-
-		go func() {
-			for d := range msgs {
-				var res *types.EngineResponse
-				err := json.Unmarshal(d.Body, &res)
-				if err != nil {
-					logger.Error(err)
-					continue
-				}
-				go fn(res)
-			}
-		}()
-
-		<-forever
-	}()
-	return nil
+		return fn(res)
+	})
 }
 
 // SubscribeOrderResponses subscribe order responses
@@ -67,55 +42,18 @@ func (c *Connection) SubscribeLendingOrderResponses(fn func(*types.EngineRespons
 
 // SubscribeEngineResponses subscribe engine responses
 func (c *Connection) SubscribeEngineResponses(fn func(*types.EngineResponse) error) error {
-	ch := c.GetChannel("erSub")
-	q := c.GetQueue(ch, "engineResponse")
-
-	go func() {
-		msgs, err := ch.Consume(
-			q.Name, // queue
-			"",     // consumer
-			true,   // auto-ack
-			false,  // exclusive
-			false,  // no-local
-			false,  // no-wait
-			nil,    // args
-		)
-
-		if err != nil {
-			logger.Fatal("Failed to register a consumer:", err)
-		}
-
-		forever := make(chan bool)
-
-		go func() {
-			for d := range msgs {
-				var res *types.EngineResponse
-				err := json.Unmarshal(d.Body, &res)
-				if err != nil {
-					logger.Error(err)
-					continue
-				}
-				go fn(res)
-			}
-		}()
-
-		<-forever
-	}()
-	return nil
+	return c.SubscribeQueue(fn, "engineResponse")
 }
 
 // PublishMessage publish message to rabbitmq
 func (c *Connection) PublishMessage(res *types.EngineResponse, queue string) error {
-	ch := c.GetChannel("erPub")
-	q := c.GetQueue(ch, queue)
-
 	bytes, err := json.Marshal(res)
 	if err != nil {
 		logger.Error("Failed to marshal engine response: ", err)
 		return err
 	}
 
-	err = c.Publish(ch, q, bytes)
+	err = c.Publish(queue, bytes)
 	if err != nil {
 		logger.Error("Failed to publish order: ", err)
 		return err
@@ -146,16 +84,13 @@ func (c *Connection) PublishLendingOrderResponse(res *types.EngineResponse) erro
 
 // PublishEngineResponse publish engine response to queue
 func (c *Connection) PublishEngineResponse(res *types.EngineResponse) error {
-	ch := c.GetChannel("erPub")
-	q := c.GetQueue(ch, "engineResponse")
-
 	bytes, err := json.Marshal(res)
 	if err != nil {
 		logger.Error("Failed to marshal engine response: ", err)
 		return err
 	}
 
-	err = c.Publish(ch, q, bytes)
+	err = c.Publish("engineResponse", bytes)
 	if err != nil {
 		logger.Error("Failed to publish order: ", err)
 		return err
@@ -163,3 +98,34 @@ func (c *Connection) PublishEngineResponse(res *types.EngineResponse) error {
 
 	return nil
 }
+
+// engineResponseExchange is the fanout exchange through which the replica that handles an
+// engine response shares it with all the replicas of the server, since the response queues
+// are consumed by one replica only
+const engineResponseExchange = "engineResponses"
+
+// ShareEngineResponse publishes an engine response to all the replicas of the server
+func (c *Connection) ShareEngineResponse(res *types.EngineResponse) error {
+	b, err := json.Marshal(res)
+	if err != nil {
+		logger.Error("Failed to marshal engine response: ", err)
+		return err
+	}
+
+	return c.publishFanout(engineResponseExchange, "engineResponsePublish", b)
+}
+
+// SubscribeSharedEngineResponses calls fn with the engine responses shared by all the
+// replicas of the server, one at a time and in the order they are received
+func (c *Connection) SubscribeSharedEngineResponses(fn func(*types.EngineResponse)) error {
+	return c.subscribeFanout("the shared engine responses", engineResponseExchange, func(b []byte) {
+		res := &types.EngineResponse{}
+		err := json.Unmarshal(b, res)
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+
+		fn(res)
+	})
+}
@@ -0,0 +1,65 @@
+package rabbitmq
+
+import (
+	"github.com/streadway/amqp"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// publishFanout publishes a message to all the queues bound to a fanout exchange, which is
+// declared on the first use. channel is the id of the channel used to publish
+func (c *Connection) publishFanout(exchange string, channel string, b []byte) error {
+	ch := c.GetChannel(channel)
+	if ch == nil {
+		return errors.New("Fail to open " + channel + " channel")
+	}
+
+	err := c.DeclareExchange(ch, exchange, "fanout")
+	if err != nil {
+		return err
+	}
+
+	err = ch.Publish(
+		exchange,
+		"",
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "text/json",
+			Body:        b,
+		},
+	)
+
+	if err != nil {
+		logger.Error(err)
+		c.CloseChannel(channel)
+		return err
+	}
+
+	return nil
+}
+
+// subscribeFanout binds an exclusive queue of the replica to a fanout exchange, and calls fn
+// with the messages of the queue, one at a time and in the order they are received. The queue
+// is removed when its connection is closed, and a new one is bound when it reconnects
+func (c *Connection) subscribeFanout(name string, exchange string, fn func(b []byte)) error {
+	return c.consume(name, func(ch *amqp.Channel) (<-chan amqp.Delivery, error) {
+		err := ch.ExchangeDeclare(exchange, "fanout", false, false, false, false, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		q, err := ch.QueueDeclare("", false, true, true, false, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ch.QueueBind(q.Name, "", exchange, false, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return ch.Consume(q.Name, "", true, true, false, false, nil)
+	}, func(d amqp.Delivery) {
+		fn(d.Body)
+	})
+}
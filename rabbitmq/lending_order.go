@@ -10,38 +10,16 @@ import (
 
 // SubscribeLendingOrders sub a order consumer
 func (c *Connection) SubscribeLendingOrders(fn func(*Message) error) error {
-	ch := c.GetChannel("lendingOrderSubscribe")
-	if ch == nil {
-		return errors.New("Fail to open lendingorderSubscribe chanel")
-	}
-	q := c.GetQueue(ch, "lending_order")
-	if q == nil {
-		return errors.New("Fail to open lending order queue")
-	}
-	go func() {
-		msgs, err := c.Consume(ch, q)
+	return c.Subscribe("lending_order", func(bytes []byte) error {
+		msg := &Message{}
+		err := json.Unmarshal(bytes, msg)
 		if err != nil {
 			logger.Error(err)
+			return &rejectError{err}
 		}
 
-		forever := make(chan bool)
-
-		go func() {
-			for d := range msgs {
-				msg := &Message{}
-				err := json.Unmarshal(d.Body, msg)
-				if err != nil {
-					logger.Error(err)
-					continue
-				}
-
-				go fn(msg)
-			}
-		}()
-
-		<-forever
-	}()
-	return nil
+		return fn(msg)
+	})
 }
 
 // PublishLendingOrderMessage publish message to queue
@@ -88,16 +66,13 @@ func (c *Connection) PublishCancelLendingOrderMessage(o *types.LendingOrder) err
 
 // PublishLendingOrder publish a lending order
 func (c *Connection) PublishLendingOrder(order *Message) error {
-	ch := c.GetChannel("lendingOrderPublish")
-	q := c.GetQueue(ch, "lending_order")
-
 	bytes, err := json.Marshal(order)
 	if err != nil {
 		log.Fatal("Failed to marshal order: ", err)
 		return errors.New("Failed to marshal order: " + err.Error())
 	}
 
-	err = c.Publish(ch, q, bytes)
+	err = c.Publish("lending_order", bytes)
 	if err != nil {
 		logger.Error(err)
 		return err
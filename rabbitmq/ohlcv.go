@@ -0,0 +1,36 @@
+package rabbitmq
+
+import (
+	"encoding/json"
+
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// ohlcvExchange is the fanout exchange through which the servers are told that ticks were
+// rebuilt in the OHLCV store, so that they load them instead of the ticks they hold
+const ohlcvExchange = "ohlcv"
+
+// PublishTicksRebuilt tells all the servers that the ticks of the ranges were rebuilt
+func (c *Connection) PublishTicksRebuilt(ranges []*types.TickRange) error {
+	b, err := json.Marshal(ranges)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return c.publishFanout(ohlcvExchange, "ohlcvPublish", b)
+}
+
+// SubscribeTicksRebuilt calls fn with the ranges of the ticks rebuilt in the OHLCV store
+func (c *Connection) SubscribeTicksRebuilt(fn func([]*types.TickRange)) error {
+	return c.subscribeFanout("the rebuilt ticks", ohlcvExchange, func(b []byte) {
+		ranges := []*types.TickRange{}
+		err := json.Unmarshal(b, &ranges)
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+
+		fn(ranges)
+	})
+}
@@ -8,39 +8,18 @@ import (
 	"github.com/tomochain/tomox-sdk/types"
 )
 
+// SubscribeOrders calls fn with the messages of the order queue
 func (c *Connection) SubscribeOrders(fn func(*Message) error) error {
-	ch := c.GetChannel("orderSubscribe")
-	if ch == nil {
-		return errors.New("Fail to open orderSubscribe chanel")
-	}
-	q := c.GetQueue(ch, "order")
-	if q == nil {
-		return errors.New("Fail to open order queue")
-	}
-	go func() {
-		msgs, err := c.Consume(ch, q)
+	return c.Subscribe("order", func(bytes []byte) error {
+		msg := &Message{}
+		err := json.Unmarshal(bytes, msg)
 		if err != nil {
 			logger.Error(err)
+			return &rejectError{err}
 		}
 
-		forever := make(chan bool)
-
-		go func() {
-			for d := range msgs {
-				msg := &Message{}
-				err := json.Unmarshal(d.Body, msg)
-				if err != nil {
-					logger.Error(err)
-					continue
-				}
-
-				go fn(msg)
-			}
-		}()
-
-		<-forever
-	}()
-	return nil
+		return fn(msg)
+	})
 }
 
 func (c *Connection) PublishNewOrderMessage(o *types.Order) error {
@@ -124,16 +103,13 @@ func (c *Connection) PublishCancelStopOrderMessage(so *types.StopOrder) error {
 }
 
 func (c *Connection) PublishOrder(order *Message) error {
-	ch := c.GetChannel("orderPublish")
-	q := c.GetQueue(ch, "order")
-
 	bytes, err := json.Marshal(order)
 	if err != nil {
 		log.Fatal("Failed to marshal order: ", err)
 		return errors.New("Failed to marshal order: " + err.Error())
 	}
 
-	err = c.Publish(ch, q, bytes)
+	err = c.Publish("order", bytes)
 	if err != nil {
 		logger.Error(err)
 		return err
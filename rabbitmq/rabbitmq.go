@@ -1,162 +1,542 @@
 package rabbitmq
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/streadway/amqp"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
 )
 
 // Conn is singleton rabbitmq connection
 var conn *Connection
-var channels = make(map[string]*amqp.Channel)
-var queues = make(map[string]*amqp.Queue)
 
 var logger = utils.Logger
 
+const (
+	// the delay before the connection is dialed again after a failure, doubled after each failure
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+
+	// deadLetterExchange routes the messages rejected by the consumers to deadLetterQueue, where
+	// they are kept until they are inspected and removed by hand
+	deadLetterExchange = "dead_letter"
+	deadLetterQueue    = "dead_letter"
+
+	// a message which fails is published again to its queue with retriesHeader incremented,
+	// after retryDelay doubled after each retry, and is dead-lettered after maxRetries retries
+	retriesHeader = "x-retries"
+	maxRetries    = 3
+	retryDelay    = time.Second
+
+	// consumerPrefetch is the number of messages a consumer handles at once
+	consumerPrefetch = 50
+
+	// confirmTimeout is the time RabbitMQ has to confirm a published message
+	confirmTimeout = 10 * time.Second
+)
+
+// Connection is a connection to RabbitMQ. The connection is dialed again when it is lost, and
+// the channels, queues and consumers are declared again on the new connection
 type Connection struct {
-	Conn *amqp.Connection
+	url       string
+	mu        sync.Mutex
+	amqpConn  *amqp.Connection
+	channels  map[string]*amqp.Channel
+	exchanges map[string]bool
+
+	// publisher is the channel in confirm mode the messages are published with, one at a time
+	publishMu sync.Mutex
+	publisher *publisher
+}
+
+type publisher struct {
+	ch       *amqp.Channel
+	confirms chan amqp.Confirmation
+	queues   map[string]bool
 }
+
 type Message struct {
 	Type string `json:"type"`
 	Data []byte `json:"data"`
 }
 
-// InitConnection Initializes single rabbitmq connection for whole system
+// rejectError is returned by the handler of a message which can not be handled, so that the
+// message is dead-lettered without being retried
+type rejectError struct {
+	err error
+}
+
+func (e *rejectError) Error() string {
+	return e.err.Error()
+}
+
+// InitConnection Initializes single rabbitmq connection for whole system. It blocks until
+// RabbitMQ can be reached
 func InitConnection(address string) *Connection {
 	if conn == nil {
-		newConn, err := amqp.Dial(address)
-		if err != nil {
-			panic(err)
-		}
-		conn = &Connection{newConn}
+		c := &Connection{url: address}
+		c.connect()
+		conn = c
 	}
 
 	return conn
 }
 
-func (c *Connection) NewConnection(address string) *amqp.Connection {
-	conn, err := amqp.Dial(address)
+// Dial connects to RabbitMQ once, for the commands which should fail rather than wait for it
+func Dial(address string) (*Connection, error) {
+	c := &Connection{url: address}
+	err := c.dial()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	return conn
+	return c, nil
 }
 
-func (c *Connection) GetQueue(ch *amqp.Channel, queue string) *amqp.Queue {
-	if queues[queue] == nil {
-		q, err := ch.QueueDeclare(queue, false, false, false, false, nil)
-		if err != nil {
-			logger.Error("Failed to declare a queue:", err)
-			return nil
+// Close closes the connection, which is not dialed again
+func (c *Connection) Close() error {
+	return c.connection().Close()
+}
+
+// connect dials RabbitMQ until it succeeds
+func (c *Connection) connect() {
+	delay := minReconnectDelay
+
+	for {
+		err := c.dial()
+		if err == nil {
+			return
 		}
 
-		queues[queue] = &q
-	}
+		logger.Errorf("Failed to connect to RabbitMQ, retrying in %s: %v", delay, err)
+		time.Sleep(delay)
 
-	return queues[queue]
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
 }
 
-func (c *Connection) DeclareQueue(ch *amqp.Channel, name string) error {
-	if queues[name] == nil {
-		q, err := ch.QueueDeclare(name, false, false, false, false, nil)
-		if err != nil {
-			logger.Error(err)
-			return err
-		}
+// dial opens a new connection, declares the dead-letter queue on it and watches it so that it
+// is dialed again when it is lost. The consumers move to the new connection on their own
+func (c *Connection) dial() error {
+	amqpConn, err := amqp.Dial(c.url)
+	if err != nil {
+		return err
+	}
 
-		queues[name] = &q
+	closed := amqpConn.NotifyClose(make(chan *amqp.Error, 1))
+
+	err = declareDeadLetterQueue(amqpConn)
+	if err != nil {
+		amqpConn.Close()
+		return err
 	}
 
+	c.mu.Lock()
+	c.amqpConn = amqpConn
+	c.channels = make(map[string]*amqp.Channel)
+	c.exchanges = make(map[string]bool)
+	c.mu.Unlock()
+
+	c.publishMu.Lock()
+	c.publisher = nil
+	c.publishMu.Unlock()
+
+	go func() {
+		// the channel is closed without an error when the connection is closed on purpose
+		err, ok := <-closed
+		if !ok {
+			return
+		}
+
+		logger.Errorf("Lost the connection to RabbitMQ, reconnecting: %v", err)
+		c.connect()
+		logger.Info("Reconnected to RabbitMQ")
+	}()
+
 	return nil
 }
 
-func (c *Connection) DeclareThrottledQueue(ch *amqp.Channel, name string) error {
-	ch.Qos(1, 0, true)
+func (c *Connection) connection() *amqp.Connection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.amqpConn
+}
+
+func declareDeadLetterQueue(amqpConn *amqp.Connection) error {
+	ch, err := amqpConn.Channel()
+	if err != nil {
+		return err
+	}
+
+	defer ch.Close()
+
+	err = ch.ExchangeDeclare(deadLetterExchange, "fanout", true, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = ch.QueueDeclare(deadLetterQueue, true, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	return ch.QueueBind(deadLetterQueue, "", deadLetterExchange, false, nil)
+}
+
+// declareQueue declares a durable queue, whose rejected messages go to the dead-letter queue
+func declareQueue(ch *amqp.Channel, name string) error {
+	_, err := ch.QueueDeclare(name, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange": deadLetterExchange,
+	})
+
+	return err
+}
+
+// DeclareExchange declares an exchange of a kind, if it was not declared yet on the connection
+func (c *Connection) DeclareExchange(ch *amqp.Channel, name string, kind string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	if queues[name] == nil {
-		q, err := ch.QueueDeclare(name, false, false, false, false, nil)
+	if !c.exchanges[name] {
+		err := ch.ExchangeDeclare(name, kind, false, false, false, false, nil)
 		if err != nil {
 			logger.Error(err)
 			return err
 		}
 
-		queues[name] = &q
+		c.exchanges[name] = true
 	}
 
 	return nil
 }
 
+// GetChannel returns the channel of an id, which is opened if it was not opened yet on the
+// connection or was closed with CloseChannel
 func (c *Connection) GetChannel(id string) *amqp.Channel {
-	if channels[id] == nil {
-		ch, err := c.Conn.Channel()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.channels[id] == nil {
+		ch, err := c.amqpConn.Channel()
 		if err != nil {
 			logger.Error("Failed to open a channel:", err)
 			return nil
 		}
 
-		channels[id] = ch
+		c.channels[id] = ch
 	}
 
-	return channels[id]
+	return c.channels[id]
+}
+
+// CloseChannel closes the channel of an id after an error, so that GetChannel opens a new one
+func (c *Connection) CloseChannel(id string) {
+	c.mu.Lock()
+	ch := c.channels[id]
+	delete(c.channels, id)
+	c.mu.Unlock()
+
+	if ch != nil {
+		ch.Close()
+	}
 }
 
-// Publish
-func (c *Connection) Publish(ch *amqp.Channel, q *amqp.Queue, bytes []byte) error {
-	err := ch.Publish(
+// Publish publishes a persistent message to a durable queue, and waits for RabbitMQ to confirm
+// that it got it
+func (c *Connection) Publish(queue string, bytes []byte) error {
+	err := c.publish(queue, bytes, 0)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (c *Connection) publish(queue string, bytes []byte, retries int) error {
+	c.publishMu.Lock()
+	defer c.publishMu.Unlock()
+
+	p, err := c.getPublisher()
+	if err != nil {
+		return err
+	}
+
+	if !p.queues[queue] {
+		err = declareQueue(p.ch, queue)
+		if err != nil {
+			c.closePublisher()
+			return err
+		}
+
+		p.queues[queue] = true
+	}
+
+	err = p.ch.Publish(
 		"",
-		q.Name,
+		queue,
 		false,
 		false,
 		amqp.Publishing{
-			ContentType: "text/json",
-			Body:        bytes,
+			ContentType:  "text/json",
+			DeliveryMode: amqp.Persistent,
+			Headers:      amqp.Table{retriesHeader: int32(retries)},
+			Body:         bytes,
 		},
 	)
 
 	if err != nil {
-		logger.Error(err)
+		c.closePublisher()
 		return err
 	}
 
+	select {
+	case confirm, ok := <-p.confirms:
+		if !ok {
+			c.closePublisher()
+			return errors.New("The channel was closed before the message was confirmed")
+		}
+
+		if !confirm.Ack {
+			return errors.New("The message was not confirmed by RabbitMQ")
+		}
+	case <-time.After(confirmTimeout):
+		// the next confirmation of the channel would be the one of this message
+		c.closePublisher()
+		return errors.New("Timed out waiting for RabbitMQ to confirm the message")
+	}
+
 	return nil
 }
 
-func (c *Connection) Consume(ch *amqp.Channel, q *amqp.Queue) (<-chan amqp.Delivery, error) {
-	msgs, err := ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		true,   // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
+func (c *Connection) getPublisher() (*publisher, error) {
+	if c.publisher != nil {
+		return c.publisher, nil
+	}
 
+	ch, err := c.connection().Channel()
 	if err != nil {
-		logger.Error(err)
 		return nil, err
 	}
 
-	return msgs, nil
+	err = ch.Confirm(false)
+	if err != nil {
+		ch.Close()
+		return nil, err
+	}
+
+	c.publisher = &publisher{
+		ch:       ch,
+		confirms: ch.NotifyPublish(make(chan amqp.Confirmation, 1)),
+		queues:   make(map[string]bool),
+	}
+
+	return c.publisher, nil
 }
 
-func (c *Connection) ConsumeAfterAck(ch *amqp.Channel, q *amqp.Queue) (<-chan amqp.Delivery, error) {
-	msgs, err := ch.Consume(
-		q.Name,
-		"",
-		false,
-		false,
-		false,
-		false,
-		nil,
-	)
+func (c *Connection) closePublisher() {
+	if c.publisher != nil {
+		c.publisher.ch.Close()
+		c.publisher = nil
+	}
+}
 
+// Subscribe consumes a durable queue and calls fn with each message. A message is acked once fn
+// returns nil, and is retried up to maxRetries times before it is dead-lettered otherwise
+func (c *Connection) Subscribe(queue string, fn func(bytes []byte) error) error {
+	return c.consume(queue, func(ch *amqp.Channel) (<-chan amqp.Delivery, error) {
+		err := ch.Qos(consumerPrefetch, 0, false)
+		if err != nil {
+			return nil, err
+		}
+
+		err = declareQueue(ch, queue)
+		if err != nil {
+			return nil, err
+		}
+
+		return ch.Consume(queue, "", false, false, false, false, nil)
+	}, func(d amqp.Delivery) {
+		go c.handle(queue, d, fn)
+	})
+}
+
+// consume opens a channel, starts a consumer on it with start, and calls handle with each
+// message until the channel is closed. The consumer is then started again on a new channel, so
+// that it survives the errors of its channel and the loss of the connection. It returns the
+// error of the first start, and the consumer keeps being started in the background after it
+func (c *Connection) consume(name string, start func(ch *amqp.Channel) (<-chan amqp.Delivery, error), handle func(d amqp.Delivery)) error {
+	started := make(chan error, 1)
+
+	go func() {
+		first := true
+
+		for {
+			msgs, ch, err := c.startConsumer(start)
+			if first {
+				started <- err
+				first = false
+			}
+
+			if err != nil {
+				logger.Errorf("Failed to start the consumer of %s: %v", name, err)
+			} else {
+				for d := range msgs {
+					handle(d)
+				}
+
+				ch.Close()
+				logger.Errorf("The consumer of %s stopped, starting it again", name)
+			}
+
+			time.Sleep(minReconnectDelay)
+		}
+	}()
+
+	return <-started
+}
+
+func (c *Connection) startConsumer(start func(ch *amqp.Channel) (<-chan amqp.Delivery, error)) (<-chan amqp.Delivery, *amqp.Channel, error) {
+	ch, err := c.connection().Channel()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msgs, err := start(ch)
+	if err != nil {
+		ch.Close()
+		return nil, nil, err
+	}
+
+	return msgs, ch, nil
+}
+
+// handle calls fn with a message of a queue and acks it if it succeeds. Otherwise the message
+// is published again to the queue after a delay, or dead-lettered after maxRetries retries
+func (c *Connection) handle(queue string, d amqp.Delivery, fn func(bytes []byte) error) {
+	err := call(fn, d.Body)
+	if err == nil {
+		d.Ack(false)
+		return
+	}
+
+	retries := getRetries(d.Headers)
+	if _, ok := err.(*rejectError); ok || retries >= maxRetries {
+		logger.Errorf("Dead-lettering a message of the %s queue after %d retries: %v", queue, retries, err)
+		d.Nack(false, false)
+		return
+	}
+
+	logger.Errorf("Failed to handle a message of the %s queue, retrying it: %v", queue, err)
+	time.Sleep(retryDelay << uint(retries))
+
+	err = c.publish(queue, d.Body, retries+1)
+	if err != nil {
+		// the message is delivered again as it is
+		logger.Error(err)
+		d.Nack(false, true)
+		return
+	}
+
+	d.Ack(false)
+}
+
+// call calls fn with a message, and turns a panic into an error
+func call(fn func(bytes []byte) error, bytes []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return fn(bytes)
+}
+
+// getRetries returns the number of retries of a message
+func getRetries(headers amqp.Table) int {
+	switch v := headers[retriesHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+
+	return 0
+}
+
+// GetDeadLetters returns the number of messages in the dead-letter queue and up to limit of
+// them, the oldest first. The messages are left in the queue
+func (c *Connection) GetDeadLetters(limit int) (*types.DeadLetters, error) {
+	ch, err := c.connection().Channel()
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// the messages are not acked, so they are put back in the queue when the channel is closed
+	defer ch.Close()
+
+	q, err := ch.QueueInspect(deadLetterQueue)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
 
-	return msgs, nil
+	res := &types.DeadLetters{
+		Count:    q.Messages,
+		Messages: []*types.DeadLetter{},
+	}
+
+	for len(res.Messages) < limit {
+		d, ok, err := ch.Get(deadLetterQueue, false)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		if !ok {
+			break
+		}
+
+		res.Messages = append(res.Messages, newDeadLetter(d))
+	}
+
+	return res, nil
+}
+
+// newDeadLetter reads the queue, reason and time of the rejection of a message from the
+// x-death header added by RabbitMQ, whose first entry is the last rejection
+func newDeadLetter(d amqp.Delivery) *types.DeadLetter {
+	dl := &types.DeadLetter{
+		Retries: getRetries(d.Headers),
+		Body:    string(d.Body),
+	}
+
+	deaths, _ := d.Headers["x-death"].([]interface{})
+	if len(deaths) == 0 {
+		return dl
+	}
+
+	death, ok := deaths[0].(amqp.Table)
+	if !ok {
+		return dl
+	}
+
+	dl.Queue, _ = death["queue"].(string)
+	dl.Reason, _ = death["reason"].(string)
+	dl.Time, _ = death["time"].(time.Time)
+
+	return dl
 }
 
 func (c *Connection) Purge(ch *amqp.Channel, name string) error {
@@ -0,0 +1,60 @@
+package rabbitmq
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRetries(t *testing.T) {
+	assert.Equal(t, 0, getRetries(nil))
+	assert.Equal(t, 0, getRetries(amqp.Table{}))
+	assert.Equal(t, 2, getRetries(amqp.Table{retriesHeader: int32(2)}))
+	assert.Equal(t, 3, getRetries(amqp.Table{retriesHeader: int64(3)}))
+}
+
+func TestCallRecoversPanics(t *testing.T) {
+	err := call(func(bytes []byte) error {
+		return errors.New("failed")
+	}, nil)
+	assert.EqualError(t, err, "failed")
+
+	err = call(func(bytes []byte) error {
+		panic("boom")
+	}, nil)
+	assert.EqualError(t, err, "panic: boom")
+
+	err = call(func(bytes []byte) error {
+		return nil
+	}, nil)
+	assert.NoError(t, err)
+}
+
+func TestNewDeadLetter(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+
+	dl := newDeadLetter(amqp.Delivery{
+		Headers: amqp.Table{
+			retriesHeader: int32(maxRetries),
+			"x-death": []interface{}{
+				amqp.Table{"queue": "order", "reason": "rejected", "time": now, "count": int64(1)},
+				amqp.Table{"queue": "lending_order", "reason": "expired"},
+			},
+		},
+		Body: []byte(`{"type":"NEW_ORDER"}`),
+	})
+
+	assert.Equal(t, "order", dl.Queue)
+	assert.Equal(t, "rejected", dl.Reason)
+	assert.Equal(t, maxRetries, dl.Retries)
+	assert.Equal(t, now, dl.Time)
+	assert.Equal(t, `{"type":"NEW_ORDER"}`, dl.Body)
+
+	dl = newDeadLetter(amqp.Delivery{Body: []byte("malformed")})
+	assert.Equal(t, "", dl.Queue)
+	assert.Equal(t, 0, dl.Retries)
+	assert.Equal(t, "malformed", dl.Body)
+}
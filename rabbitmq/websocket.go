@@ -0,0 +1,38 @@
+package rabbitmq
+
+import (
+	"encoding/json"
+
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// websocketExchange is the fanout exchange of the websocket events. Each replica of the server
+// binds its own exclusive queue to it, so that every replica gets all the events
+const websocketExchange = "websocket"
+
+// PublishWebsocketEvent publishes a websocket event to all the replicas of the server
+func (c *Connection) PublishWebsocketEvent(m *types.BusMessage) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return c.publishFanout(websocketExchange, "websocketPublish", b)
+}
+
+// SubscribeWebsocketEvents calls fn with the websocket events published by all the replicas of
+// the server, one at a time and in the order they are received. The queue of the replica is
+// removed when its connection is closed, and a new one is bound when it reconnects
+func (c *Connection) SubscribeWebsocketEvents(fn func(*types.BusMessage)) error {
+	return c.subscribeFanout("the websocket events", websocketExchange, func(b []byte) {
+		m := &types.BusMessage{}
+		err := json.Unmarshal(b, m)
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+
+		fn(m)
+	})
+}
@@ -1,79 +1,83 @@
 package relayer
 
 import (
-	"fmt"
-
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tomochain/tomox-sdk/tomox"
 )
 
 // Relayer get token
 type Relayer struct {
-	rpcURL                string
+	tomoxClient           *tomox.Client
 	coinBase              common.Address
 	relayerAddress        common.Address
 	lendingRelayerAddress common.Address
+	signer                *Signer
 }
 
-// NewRelayer init relayer
-func NewRelayer(rpcURL string,
+// NewRelayer init relayer. The relayer contracts are read through the connections of the
+// TomoX client, and the transactions are signed by signer
+func NewRelayer(tomoxClient *tomox.Client,
 	coinBase common.Address,
 	relayerAddress common.Address,
 	lendingRelayerAddress common.Address,
+	signer *Signer,
 ) *Relayer {
 
 	return &Relayer{
-		rpcURL:                rpcURL,
+		tomoxClient:           tomoxClient,
 		coinBase:              coinBase,
 		relayerAddress:        relayerAddress,
 		lendingRelayerAddress: lendingRelayerAddress,
+		signer:                signer,
+	}
+}
+
+// blockchain returns a Blockchain on the connection to the first healthy TomoX node
+func (r *Relayer) blockchain() (*Blockchain, error) {
+	client, err := r.tomoxClient.RPC()
+	if err != nil {
+		logger.Error(err)
+		return nil, err
 	}
+
+	return NewBlockchain(client, ethclient.NewClient(client), r.signer), nil
 }
 
 // GetRelayer get relayer information
 func (r *Relayer) GetRelayer(coinbase common.Address) (*RInfo, error) {
-	signer := NewSigner()
-	client, err := rpc.Dial(r.rpcURL)
+	bc, err := r.blockchain()
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
-	ethclient := ethclient.NewClient(client)
-	bc := NewBlockchain(client, ethclient, signer)
+
 	return bc.GetRelayer(coinbase, r.relayerAddress)
 }
 
 func (r *Relayer) GetRelayers() ([]*RInfo, error) {
-	signer := NewSigner()
-	client, err := rpc.Dial(r.rpcURL)
+	bc, err := r.blockchain()
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
-	ethclient := ethclient.NewClient(client)
-	bc := NewBlockchain(client, ethclient, signer)
+
 	return bc.GetRelayers(r.relayerAddress)
 }
 
 // GetLending get relayer information
 func (r *Relayer) GetLending() (*LendingRInfo, error) {
-	signer := NewSigner()
-	client, err := rpc.Dial(r.rpcURL)
+	bc, err := r.blockchain()
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
-	ethclient := ethclient.NewClient(client)
-	bc := NewBlockchain(client, ethclient, signer)
-	return bc.GetLendingRelayer(r.coinBase, r.lendingRelayerAddress)
 
+	return bc.GetLendingRelayer(r.coinBase, r.lendingRelayerAddress)
 }
 
 func (r *Relayer) GetLendings() ([]*LendingRInfo, error) {
-	signer := NewSigner()
-	client, err := rpc.Dial(r.rpcURL)
+	bc, err := r.blockchain()
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
-	ethclient := ethclient.NewClient(client)
-	bc := NewBlockchain(client, ethclient, signer)
+
 	return bc.GetLendingRelayers(r.relayerAddress, r.lendingRelayerAddress)
 }
@@ -1,72 +1,47 @@
 package relayer
 
 import (
-	"encoding/json"
-	"os"
-	"path/filepath"
-
-	"io/ioutil"
-
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	ethereum "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tomochain/tomox-sdk/signer"
 )
 
+// Signer signs the relayer transactions with a signer backend
 type Signer struct {
-	Keystore   string `json:"keystore_path"`
-	Passphrase string `json:"passphrase"`
-	opts       *bind.TransactOpts
+	backend signer.Signer
 }
 
 func (self *Signer) GetTransactOpts() *bind.TransactOpts {
-	return self.opts
+	return signer.TransactOpts(self.backend)
 }
 
 func (self *Signer) GetAddress() ethereum.Address {
-	return self.opts.From
+	return self.backend.Address()
 }
 
 func (self *Signer) Sign(tx *types.Transaction) (*types.Transaction, error) {
-	return self.opts.Signer(types.HomesteadSigner{}, self.GetAddress(), tx)
+	return signer.SignTx(self.backend, types.HomesteadSigner{}, tx)
 }
 
-func NewSignerFile(file string, fileLocation string) *Signer {
-	raw, err := ioutil.ReadFile(file)
+// NewSignerFile returns the signer of a keystore file, decrypted with the passphrase of the
+// passphraseEnv environment variable or of the passphraseFile file
+func NewSignerFile(keystore string, passphraseEnv string, passphraseFile string) (*Signer, error) {
+	passphrase, err := signer.ReadPassphrase(passphraseEnv, passphraseFile)
 	if err != nil {
-		panic(err)
-	}
-	signer := &Signer{}
-	err = json.Unmarshal(raw, signer)
-	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	keyio, err := os.Open(filepath.Join(fileLocation, signer.Keystore))
-	if err != nil {
-		panic(err)
-	}
-	logger.Debug("keyio: ", keyio)
-	auth, err := bind.NewTransactor(keyio, signer.Passphrase)
+	backend, err := signer.OpenKeystore(keystore, passphrase)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	logger.Debug("auth: ", auth.From.Hex())
-	signer.opts = auth
 
-	return signer
+	logger.Debug("auth: ", backend.Address().Hex())
+	return NewSigner(backend), nil
 }
 
-func NewSigner() *Signer {
-	signer := &Signer{}
-	passParser, keyio := GetKeyStore()
-	signer.Passphrase = passParser
-
-	auth, err := bind.NewTransactor(keyio, signer.Passphrase)
-	if err != nil {
-		panic(err)
-	}
-	logger.Debug("auth: ", auth.From.Hex())
-	signer.opts = auth
-
-	return signer
+// NewSigner returns the signer of a signer backend
+func NewSigner(backend signer.Signer) *Signer {
+	return &Signer{backend}
 }
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/daos"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/rabbitmq"
+	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+var tickDurationRegexp = regexp.MustCompile(`^(\d+)(sec|min|hour|day|week|month|year)$`)
+
+// RebuildOHLCV recomputes the OHLCV ticks of a set of pairs, durations and time range from
+// the trades, and replaces them in place in the OHLCV store while the servers run. The servers
+// are then told through RabbitMQ to reload the rebuilt ticks.
+//
+//	rebuild-ohlcv -pairs ZRX/TOMO,WETH/TOMO -units 1min,4hour -from 2020-01-01T00:00:00Z
+func RebuildOHLCV(args []string) error {
+	flags := flag.NewFlagSet("rebuild-ohlcv", flag.ExitOnError)
+	pairNames := flags.String("pairs", "", "comma separated pair names such as ZRX/TOMO, all the active pairs by default")
+	units := flags.String("units", "", "comma separated tick durations such as 1min,4hour,1day, all the durations by default")
+	from := flags.String("from", "", "start of the time range, as a RFC3339 date or a unix timestamp")
+	to := flags.String("to", "", "end of the time range, as a RFC3339 date or a unix timestamp, now by default")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	start, err := parseRebuildTime(*from)
+	if err != nil {
+		return err
+	}
+
+	end := time.Now().Unix()
+	if *to != "" {
+		end, err = parseRebuildTime(*to)
+		if err != nil {
+			return err
+		}
+	}
+
+	loadConfig()
+
+	// the ticks are computed with aggregation pipelines, which need MongoDB
+	db, err := daos.InitSession()
+	if err != nil {
+		return err
+	}
+
+	pairDao := daos.NewPairDao(db)
+	ohlcvService := services.NewOHLCVService(daos.NewTradeDao(db), pairDao, daos.NewTokenDao(db), daos.NewOHLCVDao(db))
+
+	pairs, err := getRebuildPairs(pairDao, *pairNames)
+	if err != nil {
+		return err
+	}
+
+	durations := ohlcvService.TickDurations()
+	if *units != "" {
+		durations, err = parseTickDurations(*units)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("Rebuilding OHLCV of %d pairs and %d durations from %v to %v", len(pairs), len(durations), time.Unix(start, 0), time.Unix(end, 0))
+
+	rebuilt, err := ohlcvService.RebuildTicks(pairs, durations, start, end, func(done, total int, r *types.TickRange, ticks int) {
+		logger.Infof("[%d/%d] %s %d%s: %d ticks", done, total, r.Pair.Name, r.Duration, r.Unit, ticks)
+	})
+
+	if len(rebuilt) > 0 {
+		notifyTicksRebuilt(rebuilt)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	logger.Info("OHLCV rebuild done")
+	return nil
+}
+
+// notifyTicksRebuilt tells the running servers to reload the rebuilt ticks. The servers which
+// do not run load them when they start
+func notifyTicksRebuilt(ranges []*types.TickRange) {
+	conn, err := rabbitmq.Dial(app.Config.RabbitMQURL)
+	if err != nil {
+		logger.Errorf("Could not tell the servers to reload the rebuilt ticks, restart them to load them: %v", err)
+		return
+	}
+
+	defer conn.Close()
+
+	err = conn.PublishTicksRebuilt(ranges)
+	if err != nil {
+		logger.Errorf("Could not tell the servers to reload the rebuilt ticks, restart them to load them: %v", err)
+	}
+}
+
+func getRebuildPairs(pairDao *daos.PairDao, names string) ([]*types.Pair, error) {
+	if names == "" {
+		return pairDao.GetActivePairs()
+	}
+
+	pairs := []*types.Pair{}
+	for _, name := range strings.Split(names, ",") {
+		p, err := pairDao.GetByName(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+
+		if p == nil {
+			return nil, fmt.Errorf("pair %s not found", name)
+		}
+
+		pairs = append(pairs, p)
+	}
+
+	return pairs, nil
+}
+
+func parseTickDurations(units string) ([]types.TickDuration, error) {
+	durations := []types.TickDuration{}
+	for _, u := range strings.Split(units, ",") {
+		m := tickDurationRegexp.FindStringSubmatch(strings.TrimSpace(u))
+		if m == nil {
+			return nil, fmt.Errorf("invalid tick duration %s", u)
+		}
+
+		d, _ := strconv.ParseInt(m[1], 10, 64)
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid tick duration %s", u)
+		}
+
+		durations = append(durations, types.TickDuration{Duration: d, Unit: m[2]})
+	}
+
+	return durations, nil
+}
+
+func parseRebuildTime(s string) (int64, error) {
+	if s == "" {
+		return 0, errors.New("the -from flag is required")
+	}
+
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ts, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s", s)
+	}
+
+	return t.Unix(), nil
+}
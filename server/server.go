@@ -5,6 +5,9 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/handlers"
@@ -16,10 +19,16 @@ import (
 	"github.com/tomochain/tomox-sdk/engine"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/ethereum"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/middlewares"
 	"github.com/tomochain/tomox-sdk/rabbitmq"
 	"github.com/tomochain/tomox-sdk/relayer"
 	"github.com/tomochain/tomox-sdk/services"
+	"github.com/tomochain/tomox-sdk/signer"
+	"github.com/tomochain/tomox-sdk/tomox"
+	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/utils/ratelimit"
 	"github.com/tomochain/tomox-sdk/ws"
 )
 
@@ -30,27 +39,25 @@ const (
 var logger = utils.Logger
 
 func Start() {
-	env := os.Getenv("GO_ENV")
-
-	if err := app.LoadConfig("./config", env); err != nil {
-		panic(err)
-	}
-
-	utils.InitLogger(app.Config.LogLevel)
-
-	if err := errors.LoadMessages(app.Config.ErrorFile); err != nil {
-		panic(err)
-	}
+	loadConfig()
 
 	logger.Infof("Server port: %v", app.Config.ServerPort)
 	logger.Infof("Tomochain node HTTP url: %v", app.Config.Tomochain["http_url"])
 	logger.Infof("Tomochain node WS url: %v", app.Config.Tomochain["ws_url"])
-	logger.Infof("MongoDB url: %v", app.Config.MongoURL)
+	logger.Infof("TomoX nodes: %v", app.Config.NodeURLs())
+	if app.Config.Datastore == app.DatastoreMemory {
+		logger.Infof("Datastore: %v", app.Config.Datastore)
+	} else {
+		logger.Infof("MongoDB url: %v", app.Config.MongoURL)
+	}
 	logger.Infof("RabbitMQ url: %v", app.Config.RabbitMQURL)
 	logger.Infof("Exchange contract address: %v", app.Config.Tomochain["exchange_address"])
 	logger.Infof("Env: %v", app.Config.Env)
+	if app.Config.Engine == app.EngineSimulated {
+		logger.Infof("Matching engine: %v", app.Config.Engine)
+	}
 
-	_, err := daos.InitSession(nil)
+	store, err := newStore()
 	if err != nil {
 		panic(err)
 	}
@@ -59,7 +66,7 @@ func Start() {
 
 	provider := ethereum.NewWebsocketProvider()
 
-	router := NewRouter(provider, rabbitConn)
+	router := NewRouter(store, provider, rabbitConn)
 	// http.Handle("/", router)
 	router.HandleFunc("/socket", ws.ConnectionEndpoint)
 
@@ -67,62 +74,159 @@ func Start() {
 	address := fmt.Sprintf(":%v", app.Config.ServerPort)
 	log.Printf("server %v is started at %v\n", app.Version, address)
 
-	allowedHeaders := handlers.AllowedHeaders([]string{"Content-Type", "Accept", "Authorization", "Access-Control-Allow-Origin"})
+	allowedHeaders := handlers.AllowedHeaders([]string{"Content-Type", "Accept", "Authorization", "Access-Control-Allow-Origin", "Signature", "Timestamp", "Nonce", "API-Key", "API-Signature"})
 	allowedOrigins := handlers.AllowedOrigins([]string{"*"})
 	allowedMethods := handlers.AllowedMethods([]string{"GET", "HEAD", "POST", "PUT", "DELETE", "OPTIONS"})
 
 	panic(http.ListenAndServe(address, handlers.CORS(allowedHeaders, allowedOrigins, allowedMethods)(router)))
 }
 
+// loadConfig loads the configuration of the environment set in GO_ENV and the error messages
+func loadConfig() {
+	env := os.Getenv("GO_ENV")
+
+	if err := app.LoadConfig("./config", env); err != nil {
+		panic(err)
+	}
+
+	utils.InitLogger(app.Config.LogLevel)
+
+	if err := errors.LoadMessages(app.Config.ErrorFile); err != nil {
+		panic(err)
+	}
+}
+
+// newStore returns the store of the DAOs selected by the Datastore configuration
+func newStore() (daos.Store, error) {
+	if app.Config.Datastore == app.DatastoreMemory {
+		return daos.NewMemoryStore(), nil
+	}
+
+	return daos.InitSession()
+}
+
+// newRelayerSigner returns the signer of the relayer transactions selected by the Signer
+// configuration
+func newRelayerSigner() (*relayer.Signer, error) {
+	s, err := signer.New(app.Config.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	if app.Config.Signer.Type == signer.TypeMemory || app.Config.Signer.Type == "" {
+		logger.Warningf("Relayer transactions are signed by the random key of %v, which is lost when the server stops", s.Address().Hex())
+	}
+
+	return relayer.NewSigner(s), nil
+}
+
+// walletPassphrase returns the passphrase the keys of the stored wallets are encrypted with,
+// which is empty when it is not configured
+func walletPassphrase() string {
+	passphrase, err := signer.ReadPassphrase(app.Config.WalletPassphraseEnv, app.Config.WalletPassphraseFile)
+	if err != nil {
+		logger.Warningf("No wallet passphrase, the stored wallets can not sign: %v", err)
+		return ""
+	}
+
+	return passphrase
+}
+
 func NewRouter(
+	store daos.Store,
 	provider *ethereum.EthereumProvider,
 	rabbitConn *rabbitmq.Connection,
 ) *mux.Router {
 
 	r := mux.NewRouter()
 
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), app.Config.RateLimit)
+	r.Use(middlewares.NewRateLimiter(limiter).Limit)
+	ws.SetRateLimiter(limiter)
+
+	// the connections to the TomoX nodes are shared by the DAOs and the relayer
+	tomoxClient := tomox.NewClient(app.Config.NodeURLs(), app.Config.TomoXTimeout)
+	tomoxClient.StartHealthChecks(tomox.HealthCheckInterval)
+
 	// get daos for dependency injection
-	orderDao := daos.NewOrderDao()
-	tokenDao := daos.NewTokenDao()
+	orderDao := daos.NewOrderDao(store, daos.OrderDaoTomoXOption(tomoxClient))
+	stopOrderDao := daos.NewStopOrderDao(store)
+	tokenDao := daos.NewTokenDao(store)
 
-	pairDao := daos.NewPairDao()
-	tradeDao := daos.NewTradeDao()
-	accountDao := daos.NewAccountDao()
-	walletDao := daos.NewWalletDao()
-	notificationDao := daos.NewNotificationDao()
+	pairDao := daos.NewPairDao(store)
+	tradeDao := daos.NewTradeDao(store)
+	accountDao := daos.NewAccountDao(store)
+	passphrase := walletPassphrase()
+	walletDao := daos.NewWalletDao(store, daos.WalletDaoPassphraseOption(passphrase))
+	notificationDao := daos.NewNotificationDao(store)
 
 	// Lending Dao
-	tokenLendingDao := daos.NewLendingTokenDao()
-	tokenCollateralDao := daos.NewCollateralTokenDao()
-	lendingOrderDao := daos.NewLendingOrderDao()
-	lendingTopupDao := daos.NewTopupDao()
-	lendingRepayDao := daos.NewRepayDao()
-	lendingRecallDao := daos.NewRecallDao()
-	lendingTradeDao := daos.NewLendingTradeDao()
-	lengdingPairDao := daos.NewLendingPairDao()
-	relayerDao := daos.NewRelayerDao()
+	tokenLendingDao := daos.NewLendingTokenDao(store)
+	tokenCollateralDao := daos.NewCollateralTokenDao(store)
+	lendingOrderDao := daos.NewLendingOrderDao(store, daos.LendingOrderDaoTomoXOption(tomoxClient))
+	lendingTopupDao := daos.NewTopupDao(store, daos.LendingOrderDaoTomoXOption(tomoxClient))
+	lendingRepayDao := daos.NewRepayDao(store, daos.LendingOrderDaoTomoXOption(tomoxClient))
+	lendingRecallDao := daos.NewRecallDao(store, daos.LendingOrderDaoTomoXOption(tomoxClient))
+	lendingTradeDao := daos.NewLendingTradeDao(store)
+	lengdingPairDao := daos.NewLendingPairDao(store)
+	relayerDao := daos.NewRelayerDao(store)
+	orderExpiryDao := daos.NewOrderExpiryDao(store)
+	clientOrderDao := daos.NewClientOrderDao(store)
+	ohlcvDao := daos.NewOHLCVDao(store)
+	lendingOhlcvDao := daos.NewLendingOhlcvDao(store)
+	requestNonceDao := daos.NewRequestNonceDao(store)
+	apiKeyDao := daos.NewAPIKeyDao(store, daos.APIKeyDaoPassphraseOption(passphrase))
+	configDao := daos.NewConfigDao(store)
+	operatorActionDao := daos.NewOperatorActionDao(store)
+
+	// the wallets stored by the previous versions hold plaintext private keys
+	encrypted, err := walletDao.EncryptPlaintextKeys()
+	if err != nil {
+		logger.Errorf("Could not encrypt the plaintext wallet keys: %v", err)
+	} else if encrypted > 0 {
+		logger.Infof("Encrypted the private keys of %d wallets", encrypted)
+	}
+
+	encrypted, err = apiKeyDao.EncryptPlaintextSecrets()
+	if err != nil {
+		logger.Errorf("Could not encrypt the plaintext API key secrets: %v", err)
+	} else if encrypted > 0 {
+		logger.Infof("Encrypted the secrets of %d API keys", encrypted)
+	}
+
 	// instantiate engine
-	eng := engine.NewEngine(rabbitConn, orderDao, tradeDao, pairDao, provider)
+	var eng interfaces.Engine
+	if app.Config.Engine == app.EngineSimulated {
+		eng = engine.NewMatchingEngine(rabbitConn, orderDao, tradeDao, pairDao, provider)
+	} else {
+		eng = engine.NewEngine(rabbitConn, orderDao, tradeDao, pairDao, provider)
+	}
 
 	// get services for injection
 	accountService := services.NewAccountService(accountDao, tokenDao, pairDao, orderDao, provider)
-	ohlcvService := services.NewOHLCVService(tradeDao, pairDao, tokenDao)
+	ohlcvService := services.NewOHLCVService(tradeDao, pairDao, tokenDao, ohlcvDao)
 	ohlcvService.Init()
 
 	tokenService := services.NewTokenService(tokenDao)
 	validatorService := services.NewValidatorService(provider, accountDao, orderDao, pairDao)
 	pairService := services.NewPairService(pairDao, tokenDao, tradeDao, orderDao, ohlcvService, eng, provider)
 
-	orderService := services.NewOrderService(orderDao, tokenDao, pairDao, accountDao, tradeDao, notificationDao, eng, validatorService, rabbitConn)
+	orderService := services.NewOrderService(orderDao, tokenDao, pairDao, accountDao, tradeDao, notificationDao, orderExpiryDao, clientOrderDao, eng, validatorService, rabbitConn)
 	orderService.LoadCache()
 	orderBookService := services.NewOrderBookService(pairDao, tokenDao, orderDao, eng)
 	tradeService := services.NewTradeService(orderDao, tradeDao, ohlcvService, notificationDao, rabbitConn)
+	stopOrderService := services.NewStopOrderService(stopOrderDao, pairDao, tradeDao, orderService, validatorService)
+	tradeService.RegisterNotify(stopOrderService.HandleTrade)
+	orderService.RegisterHeldNonces(stopOrderService.GetHeldNonces)
 
 	walletService := services.NewWalletService(walletDao)
+	operatorService := services.NewOperatorService(walletDao, orderService, operatorActionDao, app.Config.TradingOperatorAddresses())
 
 	priceBoardService := services.NewPriceBoardService(tokenDao, tradeDao, ohlcvService)
 	marketsService := services.NewMarketsService(pairDao, orderDao, tradeDao, ohlcvService, pairService)
 	notificationService := services.NewNotificationService(notificationDao)
+	apiKeyService := services.NewAPIKeyService(apiKeyDao)
+	changeStreamService := services.NewChangeStreamService(configDao)
 
 	// LEDNDING SERVICE
 	tokenLendingService := services.NewTokenService(tokenLendingDao)
@@ -130,7 +234,7 @@ func NewRouter(
 
 	lendingOrderService := services.NewLendingOrderService(lendingOrderDao, lendingTopupDao, lendingRepayDao, lendingRecallDao, tokenCollateralDao, tokenLendingDao, notificationDao, lendingTradeDao, eng, rabbitConn)
 	lendingTradeService := services.NewLendingTradeService(lendingOrderDao, lendingTradeDao, notificationDao, rabbitConn)
-	lendingOhlcvService := services.NewLendingOhlcvService(lendingTradeService, lengdingPairDao)
+	lendingOhlcvService := services.NewLendingOhlcvService(lendingTradeService, lengdingPairDao, lendingOhlcvDao)
 	lendingOhlcvService.Init()
 
 	lendingOrderbookService := services.NewLendingOrderBookService(lendingOrderDao)
@@ -141,23 +245,37 @@ func NewRouter(
 	exchangeAddress := common.HexToAddress(app.Config.Tomochain["exchange_address"])
 	contractAddress := common.HexToAddress(app.Config.Tomochain["exchange_contract_address"])
 	lendingContractAddress := common.HexToAddress(app.Config.Tomochain["lending_contract_address"])
-	relayerEngine := relayer.NewRelayer(app.Config.Tomochain["http_url"], exchangeAddress, contractAddress, lendingContractAddress)
+	relayerSigner, err := newRelayerSigner()
+	if err != nil {
+		panic(err)
+	}
+
+	relayerEngine := relayer.NewRelayer(tomoxClient, exchangeAddress, contractAddress, lendingContractAddress, relayerSigner)
 	relayerService := services.NewRelayerService(relayerEngine, tokenDao, tokenCollateralDao, tokenLendingDao, pairDao, lengdingPairDao, relayerDao)
 
+	verifier := middlewares.NewSignatureVerifier(requestNonceDao)
+	apiKeys := middlewares.NewAPIKeyVerifier(apiKeyDao, requestNonceDao)
+
 	// deploy http and ws endpoints
 	endpoints.ServeInfoResource(r, walletService, tokenService, relayerService)
-	endpoints.ServeAccountResource(r, accountService)
+	endpoints.ServeAccountResource(r, accountService, verifier, apiKeys)
 	endpoints.ServeTokenResource(r, tokenService, relayerService)
 	endpoints.ServePairResource(r, pairService, relayerService)
 	endpoints.ServeOrderBookResource(r, orderBookService)
 	endpoints.ServeOHLCVResource(r, ohlcvService)
 
 	endpoints.ServeTradeResource(r, tradeService)
-	endpoints.ServeOrderResource(r, orderService, accountService)
+	endpoints.ServeOrderResource(r, orderService, stopOrderService, accountService, apiKeys)
 
 	endpoints.ServePriceBoardResource(r, priceBoardService)
 	endpoints.ServeMarketsResource(r, marketsService, ohlcvService, relayerService)
-	endpoints.ServeNotificationResource(r, notificationService)
+	endpoints.ServeNotificationResource(r, notificationService, verifier)
+	endpoints.ServeAPIKeyResource(r, apiKeyService, verifier)
+	endpoints.ServeOperatorResource(r, operatorService, verifier, apiKeys)
+	endpoints.ServeAuthResource()
+	endpoints.ServeChangeStreamResource(r, changeStreamService)
+	endpoints.ServeDeadLetterResource(r, rabbitConn)
+	endpoints.ServeNodeResource(r, tomoxClient)
 
 	// Endpoint for lending
 
@@ -166,7 +284,7 @@ func NewRouter(
 	endpoints.ServeLendingPairResource(r, lendingPairService, relayerService)
 	endpoints.ServeLendingOrderBookResource(r, lendingOrderbookService)
 	endpoints.ServeLendingTradeResource(r, lendingTradeService)
-	endpoints.ServeLendingOrderResource(r, lendingOrderService)
+	endpoints.ServeLendingOrderResource(r, lendingOrderService, apiKeys)
 	endpoints.ServeLendingOhlcvResource(r, lendingOhlcvService)
 	endpoints.ServeLendingMarketsResource(r, lendingMarketService, lendingOhlcvService)
 	endpoints.ServeLendingPriceBoardResource(r, lendingPriceboardService)
@@ -177,6 +295,20 @@ func NewRouter(
 	sh := http.StripPrefix(swaggerUIDir, http.FileServer(http.Dir("."+swaggerUIDir)))
 	r.PathPrefix(swaggerUIDir).Handler(sh)
 
+	// in a cluster, the websocket events are streamed to the clients of all the replicas, and
+	// the engine responses reach the replica waiting for them
+	if app.Config.Cluster {
+		err := ws.SetBus(rabbitConn)
+		if err != nil {
+			panic(err)
+		}
+
+		err = orderService.ShareEngineResponses()
+		if err != nil {
+			panic(err)
+		}
+	}
+
 	//initialize rabbitmq subscriptions
 	rabbitConn.SubscribeOrders(eng.HandleOrders)
 	rabbitConn.SubscribeEngineResponses(orderService.HandleEngineResponse)
@@ -184,6 +316,14 @@ func NewRouter(
 	rabbitConn.SubscribeOrderResponses(orderService.HandleEngineResponse)
 	rabbitConn.SubscribeTradeResponses(tradeService.HandleTradeResponse)
 
+	// the ticks rebuilt by the rebuild-ohlcv command replace the ones held by the replica
+	rabbitConn.SubscribeTicksRebuilt(func(ranges []*types.TickRange) {
+		err := ohlcvService.ReloadTicks(ranges)
+		if err != nil {
+			logger.Errorf("Could not reload the rebuilt ticks: %v", err)
+		}
+	})
+
 	// Subscribe lending
 	// for create/cancel order
 	rabbitConn.SubscribeLendingOrders(lendingOrderService.HandleLendingOrdersCreateCancel)
@@ -191,14 +331,72 @@ func NewRouter(
 	rabbitConn.SubscribeLendingOrderResponses(lendingOrderService.HandleLendingOrderResponse)
 	rabbitConn.SubscribeLendingTradeResponses(lendingTradeService.HandleLendingTradeResponse)
 	// start cron service
-	cronService := crons.NewCronService(ohlcvService, priceBoardService, pairService, relayerService, eng, lendingPriceboardService, lendingPairService, lendingOhlcvService)
-	// initialize MongoDB Change Streams
-	go orderService.WatchChanges()
-	go tradeService.WatchChanges()
-
-	// lending mongo watch change
-	go lendingOrderService.WatchChanges()
-	go lendingTradeService.WatchChanges()
-	cronService.InitCrons()
+	cronService := crons.NewCronService(ohlcvService, priceBoardService, pairService, relayerService, orderService, eng, lendingPriceboardService, lendingPairService, lendingOhlcvService)
+	// lead starts the change streams and the crons, which only run on the leader of a cluster
+	lead := func() {
+		// initialize MongoDB Change Streams
+		if app.Config.Engine == app.EngineSimulated {
+			// the matching engine publishes the order and trade responses itself
+			go orderService.BroadcastBulkOrders()
+			go tradeService.BroadcastBulkTrades()
+		} else {
+			go orderService.WatchChanges(changeStreamService)
+			go tradeService.WatchChanges(changeStreamService)
+		}
+
+		// lending mongo watch change
+		go lendingOrderService.WatchChanges(changeStreamService)
+		go lendingTradeService.WatchChanges(changeStreamService)
+		cronService.InitCrons()
+	}
+
+	if app.Config.Cluster {
+		leaderService := services.NewLeaderService(configDao)
+		go campaign(leaderService, ohlcvService, lendingOhlcvService, lead)
+	} else {
+		lead()
+	}
+
 	return r
 }
+
+// campaign runs for the leadership of the cluster and calls lead once the replica is elected.
+// Until then, the replica loads the ticks stored by the leader. The process exits if it loses the
+// leadership, as the change streams and the crons can not be stopped, and resigns when it is
+// interrupted so that another replica is elected without waiting for the lease to expire
+func campaign(leaderService *services.LeaderService, ohlcvService *services.OHLCVService, lendingOhlcvService *services.LendingOhlcvService, lead func()) {
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+
+		leaderService.Resign()
+		os.Exit(0)
+	}()
+
+	stop := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	go func() {
+		ohlcvService.Follow(stop)
+		wg.Done()
+	}()
+
+	go func() {
+		lendingOhlcvService.Follow(stop)
+		wg.Done()
+	}()
+
+	elected := leaderService.Campaign(func() {
+		logger.Error("Exiting, as the change streams and the crons of the leader can not be stopped")
+		os.Exit(1)
+	})
+
+	close(stop)
+	wg.Wait()
+
+	if elected {
+		lead()
+	}
+}
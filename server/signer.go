@@ -0,0 +1,67 @@
+package server
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/signer"
+)
+
+// ServeSigner runs a signing process holding the keys of keystore files, which signs for the
+// servers configured with the external signer on the same socket. The keys stay out of the
+// servers, and the socket is only accessible by the user running the process.
+//
+//	serve-signer -socket /var/run/tomox/signer.ipc -keystore relayer.json -passphrase-env TOMOX_SIGNER_PASSPHRASE
+func ServeSigner(args []string) error {
+	flags := flag.NewFlagSet("serve-signer", flag.ExitOnError)
+	socket := flags.String("socket", "", "path of the unix socket to listen on")
+	keystores := flags.String("keystore", "", "comma separated paths of the keystore files")
+	passphraseEnv := flags.String("passphrase-env", "", "environment variable holding the passphrase of the keystore files")
+	passphraseFile := flags.String("passphrase-file", "", "file holding the passphrase of the keystore files")
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *socket == "" || *keystores == "" {
+		return errors.New("the -socket and -keystore flags are required")
+	}
+
+	passphrase, err := signer.ReadPassphrase(*passphraseEnv, *passphraseFile)
+	if err != nil {
+		return err
+	}
+
+	signers := []signer.Signer{}
+	for _, path := range strings.Split(*keystores, ",") {
+		s, err := signer.OpenKeystore(strings.TrimSpace(path), passphrase)
+		if err != nil {
+			return fmt.Errorf("could not open keystore %s: %v", path, err)
+		}
+
+		logger.Infof("Signing for %v", s.Address().Hex())
+		signers = append(signers, s)
+	}
+
+	// a socket left by a previous process would make the listen fail
+	os.Remove(*socket)
+	l, err := net.Listen("unix", *socket)
+	if err != nil {
+		return err
+	}
+
+	defer l.Close()
+
+	err = os.Chmod(*socket, 0600)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Signer listening on %v", *socket)
+	return signer.Serve(l, signers...)
+}
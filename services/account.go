@@ -4,10 +4,10 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type AccountService struct {
@@ -151,7 +151,7 @@ func (s *AccountService) FindOrCreate(addr common.Address) (*types.Account, erro
 }
 
 // GetByID get account by id [Deprecated]
-func (s *AccountService) GetByID(id bson.ObjectId) (*types.Account, error) {
+func (s *AccountService) GetByID(id primitive.ObjectID) (*types.Account, error) {
 	return s.AccountDao.GetByID(id)
 }
 
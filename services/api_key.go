@@ -0,0 +1,98 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/daos"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// APIKeyService manages the API keys of the user addresses
+type APIKeyService struct {
+	apiKeyDao interfaces.APIKeyDao
+}
+
+// NewAPIKeyService returns a new instance of APIKeyService
+func NewAPIKeyService(apiKeyDao interfaces.APIKeyDao) *APIKeyService {
+	return &APIKeyService{apiKeyDao}
+}
+
+// Create creates an API key for an user address. The secret is only returned here,
+// the keys returned later do not hold it
+func (s *APIKeyService) Create(addr common.Address, req *types.APIKeyRequest) (*types.APIKey, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := types.NewAPIKeySecret()
+	if err != nil {
+		return nil, err
+	}
+
+	k := &types.APIKey{
+		Key:         key,
+		Secret:      secret,
+		UserAddress: addr,
+		Label:       req.Label,
+		Scopes:      req.Scopes,
+		IPs:         req.IPs,
+	}
+
+	err = s.apiKeyDao.Create(k)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// GetByUserAddress returns the API keys of an user address without their secret
+func (s *APIKeyService) GetByUserAddress(addr common.Address) ([]*types.APIKey, error) {
+	keys, err := s.apiKeyDao.GetByUserAddress(addr)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	for _, k := range keys {
+		k.Secret = ""
+	}
+
+	return keys, nil
+}
+
+// Revoke revokes an API key of an user address
+func (s *APIKeyService) Revoke(addr common.Address, key string) error {
+	err := s.apiKeyDao.Revoke(addr, key)
+	if err == daos.ErrNotFound {
+		return errors.New("API key not found")
+	}
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/daos"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// the delay before a failed change stream is opened again, doubled after each failure
+	minChangeStreamBackoff = time.Second
+	maxChangeStreamBackoff = time.Minute
+
+	// resumeTokenSaveInterval is the minimum time between two saves of the resume token of a
+	// stream, so that a restart handles again the events of at most this interval
+	resumeTokenSaveInterval = time.Second
+)
+
+// ChangeStreamService runs the change streams of the collections. The resume token of the last
+// handled event of each stream is saved in the config collection, and the stream is resumed
+// after it when it is opened again after an error or a restart. The events are handled at least
+// once: the events of the last second before a restart can be handled again
+type ChangeStreamService struct {
+	configDao interfaces.ConfigDao
+	mu        sync.Mutex
+	stats     []*types.ChangeStreamStats
+	quit      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewChangeStreamService returns a new instance of ChangeStreamService
+func NewChangeStreamService(configDao interfaces.ConfigDao) *ChangeStreamService {
+	return &ChangeStreamService{
+		configDao: configDao,
+		quit:      make(chan struct{}),
+	}
+}
+
+// Watch consumes the change stream opened by watch until the service is stopped, and calls
+// handle with each event. The stream is opened again with an exponential backoff when it fails
+func (s *ChangeStreamService) Watch(name string, watch func(resumeAfter []byte) (interfaces.ChangeStream, error), handle func(ev bson.Raw)) {
+	stats := &types.ChangeStreamStats{Name: name}
+
+	s.mu.Lock()
+	s.stats = append(s.stats, stats)
+	s.mu.Unlock()
+
+	r := &changeStreamRunner{
+		service: s,
+		name:    name,
+		watch:   watch,
+		handle:  handle,
+		stats:   stats,
+	}
+
+	r.run()
+}
+
+// Stats returns the metrics of the streams
+func (s *ChangeStreamService) Stats() []*types.ChangeStreamStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := []*types.ChangeStreamStats{}
+	for _, stats := range s.stats {
+		copied := *stats
+		res = append(res, &copied)
+	}
+
+	return res
+}
+
+// Stop stops the streams, which save the resume token of their last handled event
+func (s *ChangeStreamService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.quit)
+	})
+}
+
+func (s *ChangeStreamService) stopped() bool {
+	select {
+	case <-s.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// changeStreamRunner consumes a change stream of a ChangeStreamService
+type changeStreamRunner struct {
+	service *ChangeStreamService
+	name    string
+	watch   func(resumeAfter []byte) (interfaces.ChangeStream, error)
+	handle  func(ev bson.Raw)
+	stats   *types.ChangeStreamStats
+
+	token   []byte
+	saved   bool
+	savedAt time.Time
+}
+
+func (r *changeStreamRunner) run() {
+	token, err := r.service.configDao.GetResumeToken(r.name)
+	if err != nil {
+		logger.Errorf("Failed to get the resume token of the %s change stream: %v", r.name, err)
+	}
+
+	r.token = token
+	r.saved = true
+	backoff := minChangeStreamBackoff
+
+	for !r.service.stopped() {
+		handled, err := r.consume()
+		if err == nil {
+			return
+		}
+
+		r.update(func(stats *types.ChangeStreamStats) {
+			stats.Errors++
+			stats.LastError = err.Error()
+		})
+
+		logger.Errorf("The %s change stream failed, opening it again in %s: %v", r.name, backoff, err)
+
+		if handled {
+			backoff = minChangeStreamBackoff
+		}
+
+		select {
+		case <-r.service.quit:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxChangeStreamBackoff {
+			backoff = maxChangeStreamBackoff
+		}
+
+		r.update(func(stats *types.ChangeStreamStats) {
+			stats.Restarts++
+		})
+	}
+}
+
+// consume opens the stream after the last handled event and handles its events until it fails
+// or the service is stopped. It tells whether an event was handled before the stream failed
+func (r *changeStreamRunner) consume() (bool, error) {
+	ct, err := r.watch(r.token)
+	if err == daos.ErrResumeTokenNotFound {
+		// the events since the last handled one are lost, the stream can only start from now
+		logger.Errorf("The %s change stream can not be resumed after its last handled event, starting it from now", r.name)
+		r.token = nil
+		r.saved = false
+		ct, err = r.watch(nil)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	defer ct.Close()
+	defer r.saveToken(true)
+
+	r.update(func(stats *types.ChangeStreamStats) {
+		stats.Running = true
+	})
+
+	defer r.update(func(stats *types.ChangeStreamStats) {
+		stats.Running = false
+	})
+
+	handled := false
+	for !r.service.stopped() {
+		var ev bson.Raw
+		if !ct.Next(&ev) {
+			err := ct.Err()
+			if err != nil {
+				return handled, err
+			}
+
+			// there is no event for now, the token can be saved if it is late
+			r.saveToken(false)
+			continue
+		}
+
+		r.handle(ev)
+		handled = true
+
+		r.token = ct.ResumeToken()
+		r.saved = false
+		r.saveToken(false)
+
+		now := time.Now()
+		r.update(func(stats *types.ChangeStreamStats) {
+			stats.Events++
+			stats.LastHandledAt = now
+
+			if t, _, ok := ev.Lookup("clusterTime").TimestampOK(); ok {
+				stats.LastEventTime = time.Unix(int64(t), 0)
+				stats.LagMs = now.Sub(stats.LastEventTime).Nanoseconds() / int64(time.Millisecond)
+			}
+		})
+	}
+
+	return handled, nil
+}
+
+// saveToken saves the resume token of the last handled event if it changed, and if the last
+// save is older than resumeTokenSaveInterval unless force is set
+func (r *changeStreamRunner) saveToken(force bool) {
+	if r.saved || (!force && time.Since(r.savedAt) < resumeTokenSaveInterval) {
+		return
+	}
+
+	err := r.service.configDao.SaveResumeToken(r.name, r.token)
+	if err != nil {
+		logger.Errorf("Failed to save the resume token of the %s change stream: %v", r.name, err)
+		return
+	}
+
+	r.saved = true
+	r.savedAt = time.Now()
+}
+
+func (r *changeStreamRunner) update(fn func(stats *types.ChangeStreamStats)) {
+	r.service.mu.Lock()
+	defer r.service.mu.Unlock()
+
+	fn(r.stats)
+}
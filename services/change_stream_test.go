@@ -0,0 +1,125 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/daos"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestChangeStreamServiceResumesAfterRestart(t *testing.T) {
+	store := daos.NewMemoryStore()
+	orderDao := daos.NewOrderDao(store)
+	configDao := daos.NewConfigDao(store)
+
+	newOrder := func(hash string) *types.Order {
+		return &types.Order{
+			Amount:       big.NewInt(10),
+			FilledAmount: big.NewInt(0),
+			PricePoint:   big.NewInt(100),
+			Nonce:        big.NewInt(1),
+			Hash:         common.HexToHash(hash),
+		}
+	}
+
+	watch := func(s *ChangeStreamService, events chan types.OrderChangeEvent) chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			s.Watch("orders", orderDao.Watch, func(raw bson.Raw) {
+				ev := types.OrderChangeEvent{}
+				err := bson.Unmarshal(raw, &ev)
+				assert.Nil(t, err)
+				events <- ev
+			})
+			close(done)
+		}()
+
+		return done
+	}
+
+	receive := func(events chan types.OrderChangeEvent) types.OrderChangeEvent {
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(5 * time.Second):
+			t.Fatal("no change event")
+			return types.OrderChangeEvent{}
+		}
+	}
+
+	s := NewChangeStreamService(configDao)
+	events := make(chan types.OrderChangeEvent, 10)
+	done := watch(s, events)
+
+	// wait for the stream to be opened
+	for len(s.Stats()) == 0 || !s.Stats()[0].Running {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err := orderDao.Create(newOrder("0x1"))
+	assert.Nil(t, err)
+
+	ev := receive(events)
+	assert.Equal(t, "insert", ev.OperationType)
+	assert.Equal(t, common.HexToHash("0x1"), ev.FullDocument.Hash)
+
+	stats := s.Stats()
+	assert.Equal(t, 1, len(stats))
+	assert.Equal(t, "orders", stats[0].Name)
+	assert.Equal(t, uint64(1), stats[0].Events)
+	assert.False(t, stats[0].LastEventTime.IsZero())
+
+	s.Stop()
+	<-done
+
+	token, err := configDao.GetResumeToken("orders")
+	assert.Nil(t, err)
+	assert.NotNil(t, token)
+
+	// the order created while the stream is stopped is handled once it is resumed, and the
+	// handled one is not handled again
+	err = orderDao.Create(newOrder("0x2"))
+	assert.Nil(t, err)
+
+	s = NewChangeStreamService(configDao)
+	done = watch(s, events)
+
+	ev = receive(events)
+	assert.Equal(t, "insert", ev.OperationType)
+	assert.Equal(t, common.HexToHash("0x2"), ev.FullDocument.Hash)
+
+	s.Stop()
+	<-done
+	assert.Equal(t, 0, len(events))
+}
+
+func TestChangeStreamServiceStartsFromNowWithLostToken(t *testing.T) {
+	store := daos.NewMemoryStore()
+	orderDao := daos.NewOrderDao(store)
+	configDao := daos.NewConfigDao(store)
+
+	err := configDao.SaveResumeToken("orders", []byte{5, 0, 0, 0, 0})
+	assert.Nil(t, err)
+
+	s := NewChangeStreamService(configDao)
+	done := make(chan struct{})
+	go func() {
+		s.Watch("orders", orderDao.Watch, func(raw bson.Raw) {})
+		close(done)
+	}()
+
+	for len(s.Stats()) == 0 || !s.Stats()[0].Running {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stats := s.Stats()
+	assert.Equal(t, uint64(0), stats[0].Errors)
+
+	s.Stop()
+	<-done
+}
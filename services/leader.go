@@ -0,0 +1,145 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/interfaces"
+)
+
+const (
+	// leaderLease is the name of the lease held by the leader in the config collection
+	leaderLease = "leader"
+
+	// the leader renews its lease every leaderRenewInterval for leaderLeaseTTL, and the other
+	// replicas try to take it at the same interval
+	leaderLeaseTTL      = 15 * time.Second
+	leaderRenewInterval = 5 * time.Second
+)
+
+// LeaderService elects the leader of the replicas of the server with a lease in the config
+// collection. The leader renews the lease until it resigns, and another replica takes it once it
+// expires. The leader gives up the leadership if it can not renew the lease for longer than
+// leaderLeaseTTL - leaderRenewInterval, before another replica can take it
+type LeaderService struct {
+	configDao interfaces.ConfigDao
+	id        string
+	mu        sync.Mutex
+	leader    bool
+	quit      chan struct{}
+	resign    sync.Once
+}
+
+// NewLeaderService returns a new instance of LeaderService, with an ID unique to the process
+func NewLeaderService(configDao interfaces.ConfigDao) *LeaderService {
+	host, _ := os.Hostname()
+
+	return &LeaderService{
+		configDao: configDao,
+		id:        fmt.Sprintf("%s/%d/%d", host, os.Getpid(), time.Now().UnixNano()),
+		quit:      make(chan struct{}),
+	}
+}
+
+// ID returns the ID of the replica in the election
+func (s *LeaderService) ID() string {
+	return s.id
+}
+
+// Campaign blocks until the replica is elected or resigns, and tells whether it was elected. The
+// lease is then renewed in the background, and lost is called if it can not be renewed, as the
+// work of the leader must stop before another replica is elected
+func (s *LeaderService) Campaign(lost func()) bool {
+	for {
+		ok, err := s.configDao.AcquireLease(leaderLease, s.id, leaderLeaseTTL)
+		if err != nil {
+			logger.Error(err)
+		}
+
+		if ok {
+			break
+		}
+
+		select {
+		case <-s.quit:
+			return false
+		case <-time.After(leaderRenewInterval):
+		}
+	}
+
+	s.mu.Lock()
+	s.leader = true
+	s.mu.Unlock()
+
+	logger.Infof("Elected as the leader: %s", s.id)
+	go s.renew(lost)
+
+	return true
+}
+
+func (s *LeaderService) renew(lost func()) {
+	renewedAt := time.Now()
+
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-time.After(leaderRenewInterval):
+		}
+
+		ok, err := s.configDao.AcquireLease(leaderLease, s.id, leaderLeaseTTL)
+		if ok {
+			renewedAt = time.Now()
+			continue
+		}
+
+		if err != nil {
+			logger.Error(err)
+		}
+
+		// the lease is kept while it can still be renewed before it expires
+		if err != nil && time.Since(renewedAt) < leaderLeaseTTL-leaderRenewInterval {
+			continue
+		}
+
+		logger.Errorf("Lost the leadership: %s", s.id)
+
+		s.mu.Lock()
+		s.leader = false
+		s.mu.Unlock()
+
+		lost()
+		return
+	}
+}
+
+// IsLeader tells whether the replica is the leader
+func (s *LeaderService) IsLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.leader
+}
+
+// Resign stops the campaign, and releases the lease if the replica is the leader so that
+// another replica is elected without waiting for it to expire
+func (s *LeaderService) Resign() {
+	s.resign.Do(func() {
+		close(s.quit)
+
+		if !s.IsLeader() {
+			return
+		}
+
+		s.mu.Lock()
+		s.leader = false
+		s.mu.Unlock()
+
+		err := s.configDao.ReleaseLease(leaderLease, s.id)
+		if err != nil {
+			logger.Error(err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/daos"
+)
+
+func TestLeaderServiceElection(t *testing.T) {
+	configDao := daos.NewConfigDao(daos.NewMemoryStore())
+
+	a := NewLeaderService(configDao)
+	b := NewLeaderService(configDao)
+	assert.NotEqual(t, a.ID(), b.ID())
+
+	lost := func() {
+		t.Error("the leadership is lost")
+	}
+
+	assert.True(t, a.Campaign(lost))
+	assert.True(t, a.IsLeader())
+
+	elected := make(chan bool)
+	go func() {
+		elected <- b.Campaign(lost)
+	}()
+
+	select {
+	case <-elected:
+		t.Fatal("two replicas are elected")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// b is elected once a resigns, without waiting for the lease to expire
+	a.Resign()
+	assert.False(t, a.IsLeader())
+
+	select {
+	case ok := <-elected:
+		assert.True(t, ok)
+		assert.True(t, b.IsLeader())
+	case <-time.After(leaderLeaseTTL):
+		t.Fatal("no replica is elected")
+	}
+
+	b.Resign()
+}
@@ -1,11 +1,8 @@
 package services
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
 	"math/big"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,39 +18,30 @@ import (
 	"github.com/tomochain/tomox-sdk/ws"
 )
 
-const (
-	// LendingCachePath OHL cache file name
-	LendingCachePath = "lending.cache"
-)
-
 // LendingOhlcvService ohlcv lending struct
 type LendingOhlcvService struct {
 	lendingTradeService interfaces.LendingTradeService
 	lendingTickCache    *lendingTickCache
 	lendingPairDao      interfaces.LendingPairDao
+	lendingOhlcvDao     interfaces.LendingOhlcvDao
 	bulkPairs           map[string]bool
 	mutex               sync.RWMutex
 	tokenCache          map[common.Address]int
 }
 
 type lendingTickCache struct {
-	tframes timeframes
-	ticks   map[string]map[int64]*types.LendingTick
-}
-
-type lendingtickfile struct {
-	Frame        timeframes         `json:"frame" bson:"frame"`
-	LendingTicks types.LendingTicks `json:"ticks" bson:"ticks"`
+	ticks map[string]map[int64]*types.LendingTick
 }
 
 // NewLendingOhlcvService init new ohlcv service
-func NewLendingOhlcvService(lendingTradeService interfaces.LendingTradeService, lendingPairDao interfaces.LendingPairDao) *LendingOhlcvService {
+func NewLendingOhlcvService(lendingTradeService interfaces.LendingTradeService, lendingPairDao interfaces.LendingPairDao, lendingOhlcvDao interfaces.LendingOhlcvDao) *LendingOhlcvService {
 	cache := &lendingTickCache{
 		ticks: make(map[string]map[int64]*types.LendingTick),
 	}
 	return &LendingOhlcvService{
 		lendingTradeService: lendingTradeService,
 		lendingPairDao:      lendingPairDao,
+		lendingOhlcvDao:     lendingOhlcvDao,
 		lendingTickCache:    cache,
 		tokenCache:          make(map[common.Address]int),
 		bulkPairs:           make(map[string]bool),
@@ -218,51 +206,39 @@ func (s *LendingOhlcvService) getConfig() []durationtick {
 	}
 }
 
-// Init init cache
-// ensure add current time frame before trade notify come
+// Init loads the ticks stored in the lending_ohlcv collection and builds the ticks of the
+// lending trades made since the last stored tick. Ticks are then upserted as trades come
 func (s *LendingOhlcvService) Init() {
 	logger.Info("Lending OHLCV init starting...")
 	now := time.Now().Unix()
-	datefrom := now - intervalMin
-	s.loadCache()
-	lastFrame := s.lastTimeFrame()
-	if lastFrame != nil {
-		logger.Info("last frame first time", time.Unix(lastFrame.FirstTime, 0))
-		if now-lastFrame.LastTime < intervalMin {
-			datefrom = lastFrame.LastTime
-		}
-	} else {
-		// add start frame to list
-		s.lendingTickCache.tframes = append(s.lendingTickCache.tframes, &timeframe{
-			FirstTime: now - intervalMax,
-			LastTime:  now - intervalMax,
-		})
-	}
-	// add current frame to list
-	s.lendingTickCache.tframes = append(s.lendingTickCache.tframes, &timeframe{
-		FirstTime: now,
-		LastTime:  now,
-	})
+	datefrom := now - intervalMax
+
+	ticks, err := s.lendingOhlcvDao.GetTicks(datefrom)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, t := range ticks {
+		s.addTick(t)
+	}
+
+	last, err := s.lendingOhlcvDao.GetLastTick()
+	if err != nil {
+		panic(err)
+	}
+
+	if last != nil {
+		logger.Info("last tick close time", last.CloseTime)
+		datefrom = last.CloseTime.Unix()
+	}
 
-	lastFrame = s.lastTimeFrame()
 	logger.Info("init fetch", time.Unix(datefrom, 0), time.Unix(now, 0))
-	s.fetch(datefrom, now, lastFrame)
-	s.commitCache()
-	go s.continueCache()
-	ticker := time.NewTicker(60 * time.Second)
-	quit := make(chan struct{})
+	s.fetch(datefrom, now)
+
+	ticker := time.NewTicker(time.Hour)
 	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				err := s.commitCache()
-				if err != nil {
-					logger.Error(err)
-				}
-			case <-quit:
-				ticker.Stop()
-				return
-			}
+		for range ticker.C {
+			s.removeExpiredTicks()
 		}
 	}()
 	s.lendingTradeService.RegisterNotify(s.NotifyTrade)
@@ -285,6 +261,22 @@ func (s *LendingOhlcvService) getIntervelByUint(d int64, unit string) (int64, er
 	return 0, errors.New("unit not found")
 }
 
+// removeExpiredTicks removes the ticks older than the interval of their duration
+func (s *LendingOhlcvService) removeExpiredTicks() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.truncate()
+
+	now := time.Now().Unix()
+	for _, d := range s.getConfig() {
+		err := s.lendingOhlcvDao.DeleteTicks(d.duration, d.unit, now-d.interval)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
 // cache need to be locked
 func (s *LendingOhlcvService) truncate() {
 	now := time.Now().Unix()
@@ -302,133 +294,88 @@ func (s *LendingOhlcvService) truncate() {
 		}
 	}
 }
-func (s *LendingOhlcvService) fetch(fromdate int64, todate int64, frame *timeframe) {
+
+// fetch builds the ticks of the lending trades made between fromdate and todate, one day at
+// a time and oldest first. A trade that a tick already includes is skipped
+func (s *LendingOhlcvService) fetch(fromdate int64, todate int64) {
 	durations := s.getConfig()
-	pageOffset := 0
-	size := 1000
 	now := time.Now().Unix()
-	for {
-		trades, err := s.lendingTradeService.GetLendingTradeByTime(fromdate, todate, pageOffset*size, size)
-		logger.Debug("FETCH DATA", pageOffset*size)
-		if err != nil || len(trades) == 0 {
-			break
+	for start := fromdate; start < todate; start = start + intervalMin {
+		end := start + intervalMin
+		if end > todate {
+			end = todate
+		}
+
+		trades := s.fetchTrades(start, end)
+		if len(trades) == 0 {
+			continue
 		}
-		sort.Slice(trades, func(i, j int) bool {
-			return trades[i].CreatedAt.Unix() < trades[j].CreatedAt.Unix()
-		})
+
 		s.mutex.Lock()
-		for i, trade := range trades {
+		updated := make(map[*types.LendingTick]bool)
+		for _, trade := range trades {
 			for _, d := range durations {
 				key := s.getTickKey(trade.Term, trade.LendingToken, d.duration, d.unit)
-				if trade.CreatedAt.Unix() > now-d.interval {
-					s.updateTick(key, trade)
+				if trade.CreatedAt.Unix() > now-d.interval && !s.hasTrade(key, trade) {
+					tick, err := s.updateTick(key, trade)
+					if err == nil && tick != nil {
+						updated[tick] = true
+					}
 				}
 			}
-			if i == 0 {
-				s.updatefisttimeframe(trade.CreatedAt.Unix(), frame)
-			}
+		}
 
+		ticks := []*types.LendingTick{}
+		for t := range updated {
+			ticks = append(ticks, t)
 		}
+
+		s.saveTicks(ticks)
 		s.mutex.Unlock()
-		pageOffset = pageOffset + 1
 	}
 }
 
-// ensure init cache finished before invoke
-func (s *LendingOhlcvService) continueCache() {
-	if len(s.lendingTickCache.tframes) > 1 {
-		for i := len(s.lendingTickCache.tframes) - 1; i > 0; i-- {
-			currentframe := s.lendingTickCache.tframes[i]
-			preframe := s.lendingTickCache.tframes[i-1]
-			if currentframe.FirstTime > preframe.LastTime {
-				logger.Debug("continue cache", time.Unix(preframe.LastTime, 0), time.Unix(currentframe.FirstTime, 0))
-				s.fetch(preframe.LastTime, currentframe.FirstTime, currentframe)
-			}
-
+// fetchTrades returns the lending trades made between fromdate and todate, oldest first
+func (s *LendingOhlcvService) fetchTrades(fromdate int64, todate int64) []*types.LendingTrade {
+	pageOffset := 0
+	size := 1000
+	res := []*types.LendingTrade{}
+	for {
+		trades, err := s.lendingTradeService.GetLendingTradeByTime(fromdate, todate, pageOffset*size, size)
+		logger.Debug("FETCH DATA", pageOffset*size)
+		if err != nil || len(trades) == 0 {
+			break
 		}
-	}
-	logger.Debug("continueCache finished")
-}
-func (s *LendingOhlcvService) lastTimeFrame() *timeframe {
-	if len(s.lendingTickCache.tframes) > 0 {
-		return s.lendingTickCache.tframes[len(s.lendingTickCache.tframes)-1]
-	}
-	return nil
-}
-func (s *LendingOhlcvService) updatefisttimeframe(firsttime int64, frame *timeframe) {
-	logger.Info("updatefisttimeframe", time.Unix(firsttime, 0))
-	if frame != nil {
-		frame.FirstTime = firsttime
-	}
-}
 
-func (s *LendingOhlcvService) updatelasttimeframe(lasttime int64, frame *timeframe) {
-	if frame != nil {
-		frame.LastTime = lasttime
+		res = append(res, trades...)
+		pageOffset = pageOffset + 1
 	}
 
-}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
 
-func (s *LendingOhlcvService) flatten() []*types.LendingTick {
-	var ticks []*types.LendingTick
-	for _, tickbytime := range s.lendingTickCache.ticks {
-		for _, tick := range tickbytime {
-			ticks = append(ticks, tick)
-		}
-	}
-	return ticks
+	return res
 }
 
-func (s *LendingOhlcvService) commitCache() error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-	logger.Info("commit ohlcv cache")
-	s.truncate()
-	ticks := s.flatten()
-	lendingtickfile := &lendingtickfile{
-		Frame:        s.lendingTickCache.tframes,
-		LendingTicks: ticks,
-	}
-	tickData, err := json.Marshal(lendingtickfile)
+// hasTrade returns true if the tick of a trade was closed after the trade, need to be lock
+func (s *LendingOhlcvService) hasTrade(key string, trade *types.LendingTrade) bool {
+	_, _, duration, unit, err := s.parseTickKey(key)
 	if err != nil {
-		return err
+		return false
 	}
-	file, err := os.Create(LendingCachePath)
-	defer file.Close()
-	if err == nil {
-		_, err = file.Write(tickData)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
 
-func (s *LendingOhlcvService) loadCache() error {
-	file, err := os.Open(LendingCachePath)
-	defer file.Close()
-	if err != nil {
-		return err
-	}
-	stats, statsErr := file.Stat()
-	if statsErr != nil {
-		return statsErr
-	}
+	modTime, _ := utils.GetModTime(trade.CreatedAt.Unix(), duration, unit)
+	tick, ok := s.lendingTickCache.ticks[key][modTime]
+	return ok && !trade.CreatedAt.After(tick.CloseTime)
+}
 
-	size := stats.Size()
-	bytes := make([]byte, size)
-	bufr := bufio.NewReader(file)
-	_, err = bufr.Read(bytes)
-	var tickf lendingtickfile
-	err = json.Unmarshal(bytes, &tickf)
+// saveTicks upserts the ticks in the lending_ohlcv collection
+func (s *LendingOhlcvService) saveTicks(ticks []*types.LendingTick) {
+	err := s.lendingOhlcvDao.UpsertTicks(ticks)
 	if err != nil {
-		return err
-	}
-	for _, t := range tickf.LendingTicks {
-		s.addTick(t)
+		logger.Error(err)
 	}
-	s.lendingTickCache.tframes = tickf.Frame
-	return nil
 }
 
 func (s *LendingOhlcvService) getTickKey(term uint64, lendingToken common.Address, duration int64, unit string) string {
@@ -453,12 +400,12 @@ func (s *LendingOhlcvService) parseTickKey(key string) (uint64, common.Address,
 	return term, lendingToken, duration, unit, nil
 }
 
-// updateTick update lastest tick, need to be lock
-func (s *LendingOhlcvService) updateTick(key string, trade *types.LendingTrade) error {
+// updateTick update lastest tick and returns it, need to be lock
+func (s *LendingOhlcvService) updateTick(key string, trade *types.LendingTrade) (*types.LendingTick, error) {
 	tradeTime := trade.CreatedAt.Unix()
 	term, lendingToken, duration, unit, err := s.parseTickKey(key)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if term == trade.Term && lendingToken.Hex() == trade.LendingToken.Hex() {
 		modTime, _ := utils.GetModTime(tradeTime, duration, unit)
@@ -477,6 +424,8 @@ func (s *LendingOhlcvService) updateTick(key string, trade *types.LendingTrade)
 				}
 				last.Volume = big.NewInt(0).Add(last.Volume, trade.Amount)
 				last.Count = last.Count.Add(last.Count, big.NewInt(1))
+				last.CloseTime = trade.CreatedAt
+				return last, nil
 			} else {
 				tick := &types.LendingTick{
 					LendingID: types.LendingID{
@@ -492,13 +441,15 @@ func (s *LendingOhlcvService) updateTick(key string, trade *types.LendingTrade)
 					Timestamp: modTime,
 					Duration:  duration,
 					Unit:      unit,
+					CloseTime: trade.CreatedAt,
 				}
 				tickByTime[modTime] = tick
+				return tick, nil
 			}
 		}
 	}
 
-	return nil
+	return nil, nil
 }
 
 func (s *LendingOhlcvService) addTick(tick *types.LendingTick) {
@@ -589,16 +540,56 @@ func (s *LendingOhlcvService) get24hTick(term uint64, lendingToken common.Addres
 	}
 }
 
+// Follow loads the ticks stored in the lending_ohlcv collection by the leader replica, which
+// handles the lending trades, every followInterval until stop is closed. The ticks stored
+// meanwhile are loaded before it returns
+func (s *LendingOhlcvService) Follow(stop <-chan struct{}) {
+	since := time.Now()
+
+	for {
+		select {
+		case <-stop:
+			s.follow(since)
+			return
+		case <-time.After(followInterval):
+			since = s.follow(since)
+		}
+	}
+}
+
+// follow loads the ticks stored since a time, and returns the time to load the next ones from
+func (s *LendingOhlcvService) follow(since time.Time) time.Time {
+	next := time.Now()
+
+	ticks, err := s.lendingOhlcvDao.GetTicksUpdatedSince(since.Add(-followInterval))
+	if err != nil {
+		logger.Error(err)
+		return since
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, t := range ticks {
+		s.addTick(t)
+	}
+
+	return next
+}
+
 // NotifyTrade trigger if trade comming
 func (s *LendingOhlcvService) NotifyTrade(trade *types.LendingTrade) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
+	ticks := []*types.LendingTick{}
 	for _, d := range s.getConfig() {
 		key := s.getTickKey(trade.Term, trade.LendingToken, d.duration, d.unit)
-		s.updateTick(key, trade)
+		tick, err := s.updateTick(key, trade)
+		if err == nil && tick != nil {
+			ticks = append(ticks, tick)
+		}
 	}
-	lastFrame := s.lastTimeFrame()
-	s.updatelasttimeframe(trade.CreatedAt.Unix(), lastFrame)
+	s.saveTicks(ticks)
 	id := utils.GetLendingChannelID(trade.Term, trade.LendingToken)
 	s.bulkPairs[id] = true
 }
@@ -1,7 +1,6 @@
 package services
 
 import (
-	"context"
 	"encoding/json"
 	"math"
 	"math/big"
@@ -10,12 +9,14 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/rabbitmq"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 const (
@@ -39,6 +40,7 @@ type LendingOrderService struct {
 	broker             *rabbitmq.Connection
 	mutext             sync.RWMutex
 	bulkLendingOrders  map[string]map[common.Hash]*types.LendingOrder
+	nonces             *NonceManager
 }
 
 // NewLendingOrderService returns a new instance of lending order service
@@ -55,7 +57,7 @@ func NewLendingOrderService(
 	broker *rabbitmq.Connection,
 ) *LendingOrderService {
 	bulkLendingOrders := make(map[string]map[common.Hash]*types.LendingOrder)
-	return &LendingOrderService{
+	s := &LendingOrderService{
 		lendingDao,
 		topupDao,
 		repayDao,
@@ -68,7 +70,14 @@ func NewLendingOrderService(
 		broker,
 		sync.RWMutex{},
 		bulkLendingOrders,
+		nil,
 	}
+
+	if app.Config.Engine != app.EngineSimulated {
+		s.nonces = NewNonceManager(lendingDao.GetLendingNonce)
+	}
+
+	return s
 }
 
 // GetByHash get lending by hash
@@ -94,9 +103,16 @@ func (s *LendingOrderService) NewLendingOrder(o *types.LendingOrder) error {
 	if !ok {
 		return errors.New("Invalid Signature")
 	}
+
+	err = s.reserveNonce(o)
+	if err != nil {
+		return err
+	}
+
 	err = s.broker.PublishLendingOrderMessage(o)
 	if err != nil {
 		logger.Error(err)
+		s.releaseNonce(o)
 		return err
 	}
 
@@ -107,7 +123,63 @@ func (s *LendingOrderService) NewLendingOrder(o *types.LendingOrder) error {
 // Only Orders which are OPEN or NEW i.e. Not yet filled/partially filled
 // can be cancelled
 func (s *LendingOrderService) CancelLendingOrder(o *types.LendingOrder) error {
-	return s.lendingDao.CancelLendingOrder(o)
+	err := s.reserveNonce(o)
+	if err != nil {
+		return err
+	}
+
+	err = s.lendingDao.CancelLendingOrder(o)
+	if err != nil {
+		s.rejectNonce(o)
+		return err
+	}
+
+	return nil
+}
+
+// reserveNonce reserves the nonce of a lending order or cancel until the node counts it
+func (s *LendingOrderService) reserveNonce(o *types.LendingOrder) error {
+	if s.nonces == nil {
+		return nil
+	}
+
+	return s.nonces.Reserve(o.UserAddress, o.Nonce, o.Hash)
+}
+
+// releaseNonce frees the nonce of a lending order which was not sent
+func (s *LendingOrderService) releaseNonce(o *types.LendingOrder) {
+	if s.nonces == nil {
+		return
+	}
+
+	s.nonces.Release(o.UserAddress, o.Nonce, o.Hash)
+}
+
+// rejectNonce frees the nonce of a lending order rejected by the node, which may have rejected
+// it because its nonce did not match, so the nonces of the user are synced from the node again
+func (s *LendingOrderService) rejectNonce(o *types.LendingOrder) {
+	if s.nonces == nil {
+		return
+	}
+
+	s.nonces.Release(o.UserAddress, o.Nonce, o.Hash)
+	s.nonces.Resync(o.UserAddress)
+}
+
+// handleLendingNonce records that the node counted the nonce of an added lending order, and
+// frees the nonce of a rejected one
+func (s *LendingOrderService) handleLendingNonce(res *types.EngineResponse) {
+	o := res.LendingOrder
+	if s.nonces == nil || o == nil || o.Nonce == nil {
+		return
+	}
+
+	switch res.Status {
+	case types.LENDING_ORDER_ADDED:
+		s.nonces.Confirm(o.UserAddress, o.Nonce)
+	case types.LENDING_ORDER_REJECTED, types.ERROR_STATUS:
+		s.rejectNonce(o)
+	}
 }
 
 // RepayLendingOrder repay
@@ -124,6 +196,8 @@ func (s *LendingOrderService) TopupLendingOrder(o *types.LendingOrder) error {
 // HandleLendingOrderResponse listens to messages incoming from the engine and handles websocket
 // responses and database updates accordingly
 func (s *LendingOrderService) HandleLendingOrderResponse(res *types.EngineResponse) error {
+	s.handleLendingNonce(res)
+
 	switch res.Status {
 	case types.LENDING_ORDER_ADDED:
 		s.handleLendingOrderAdded(res)
@@ -347,69 +421,33 @@ func (s *LendingOrderService) handleEngineError(res *types.EngineResponse) {
 func (s *LendingOrderService) handleEngineUnknownMessage(res *types.EngineResponse) {
 }
 
-// WatchChanges watch database
-func (s *LendingOrderService) watchChanges(dao interfaces.LendingOrderDao, docType string) {
-	ct, sc, err := dao.Watch()
-
-	if err != nil {
-		logger.Error("Failed to open change stream")
-		return //exiting func
-	}
-
-	defer ct.Close()
-	defer sc.Close()
-
-	// Watch the event again in case there is error and function returned
-	defer s.watchChanges(dao, docType)
-
-	ctx := context.Background()
-	//Handling change stream in a cycle
-	for {
-		select {
-		case <-ctx.Done(): // if parent context was cancelled
-			err := ct.Close() // close the stream
-			if err != nil {
-				logger.Error("Change stream closed")
-			}
-			return //exiting from the func
-		default:
-			ev := types.LendingOrderChangeEvent{}
-
-			//getting next item from the steam
-			ok := ct.Next(&ev)
-
-			//if data from the stream wasn't un-marshaled, we get ok == false as a result
-			//so we need to call Err() method to get info why
-			//it'll be nil if we just have no data
-			if !ok {
-				err := ct.Err()
-				if err != nil {
-					logger.Error(err)
-					return
-				}
-			}
-
-			//if item from the stream un-marshaled successfully, do something with it
-			if ok {
-				logger.Debugf("Lending Operation Type: %s", ev.OperationType)
-				s.HandleDocumentType(ev, docType)
-			}
+// watchChanges handles the changes of the stream of a lending order collection
+func (s *LendingOrderService) watchChanges(streams interfaces.ChangeStreamService, name string, dao interfaces.LendingOrderDao, docType string) {
+	streams.Watch(name, dao.Watch, func(raw bson.Raw) {
+		ev := types.LendingOrderChangeEvent{}
+		err := bson.Unmarshal(raw, &ev)
+		if err != nil {
+			logger.Error(err)
+			return
 		}
-	}
+
+		logger.Debugf("Lending Operation Type: %s", ev.OperationType)
+		s.HandleDocumentType(ev, docType)
+	})
 }
 
-// WatchChanges watch database
-func (s *LendingOrderService) WatchChanges() {
+// WatchChanges handles the changes of the lending orders, topups, repays and recalls streams
+func (s *LendingOrderService) WatchChanges(streams interfaces.ChangeStreamService) {
 	go func() {
 		for {
 			<-time.After(500 * time.Millisecond)
 			s.processBulkLendingOrders()
 		}
 	}()
-	go s.watchChanges(s.lendingDao, LENDING_EVENT)
-	go s.watchChanges(s.topupDao, TOPUP_EVENT)
-	go s.watchChanges(s.repayDao, REPAY_EVENT)
-	s.watchChanges(s.recallDao, RECALL_EVENT)
+	go s.watchChanges(streams, "lending_orders", s.lendingDao, LENDING_EVENT)
+	go s.watchChanges(streams, "lending_topups", s.topupDao, TOPUP_EVENT)
+	go s.watchChanges(streams, "lending_repays", s.repayDao, REPAY_EVENT)
+	s.watchChanges(streams, "lending_recalls", s.recallDao, RECALL_EVENT)
 }
 
 // HandleDocumentType handle order frome changing db
@@ -499,7 +537,14 @@ func (s *LendingOrderService) handleNewLendingOrder(bytes []byte) error {
 		logger.Error(err)
 		return err
 	}
-	return s.lendingDao.AddNewLendingOrder(o)
+
+	err = s.lendingDao.AddNewLendingOrder(o)
+	if err != nil {
+		s.rejectNonce(o)
+		return err
+	}
+
+	return nil
 }
 
 func (s *LendingOrderService) handleCancelLendingOrder(bytes []byte) error {
@@ -512,9 +557,14 @@ func (s *LendingOrderService) handleCancelLendingOrder(bytes []byte) error {
 	return s.lendingDao.CancelLendingOrder(o)
 }
 
-// GetLendingNonceByUserAddress return nonce of user order
+// GetLendingNonceByUserAddress returns the next nonce of the lending orders of an address,
+// which is neither counted by the node nor held by a pending lending order
 func (s *LendingOrderService) GetLendingNonceByUserAddress(addr common.Address) (uint64, error) {
-	return s.lendingDao.GetLendingNonce(addr)
+	if s.nonces == nil {
+		return s.lendingDao.GetLendingNonce(addr)
+	}
+
+	return s.nonces.Next(addr)
 }
 
 func (s *LendingOrderService) saveBulkLendingOrders(res *types.EngineResponse) error {
@@ -565,7 +615,7 @@ func (s *LendingOrderService) processBulkLendingOrders() {
 				lend = append(lend, update)
 			}
 		}
-		ws.GetLendingOrderBookSocket().BroadcastMessage(p, &types.LendingOrderBook{
+		ws.GetLendingOrderBookSocket().BroadcastUpdate(p, &types.LendingOrderBook{
 			Name:   p,
 			Borrow: borrow,
 			Lend:   lend,
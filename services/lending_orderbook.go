@@ -24,6 +24,9 @@ func NewLendingOrderBookService(
 
 // GetLendingOrderBook fetches orderbook from engine and returns it as an map[string]interface
 func (s *LendingOrderBookService) GetLendingOrderBook(term uint64, lendingToken common.Address) (*types.LendingOrderBook, error) {
+	id := utils.GetLendingOrderBookChannelID(term, lendingToken)
+	sequence := ws.GetLendingOrderBookSocket().Sequence(id)
+
 	borrow, lend, err := s.lendingOrderDao.GetLendingOrderBook(term, lendingToken)
 	if err != nil {
 		logger.Error(err)
@@ -31,15 +34,19 @@ func (s *LendingOrderBookService) GetLendingOrderBook(term uint64, lendingToken
 	}
 
 	ob := &types.LendingOrderBook{
-		Name:   utils.GetLendingOrderBookChannelID(term, lendingToken),
-		Lend:   lend,
-		Borrow: borrow,
+		Name:     id,
+		Lend:     lend,
+		Borrow:   borrow,
+		Sequence: sequence,
 	}
 
 	return ob, nil
 }
 
 func (s *LendingOrderBookService) GetLendingOrderBookInDb(term uint64, lendingToken common.Address) (*types.LendingOrderBook, error) {
+	id := utils.GetLendingOrderBookChannelID(term, lendingToken)
+	sequence := ws.GetLendingOrderBookSocket().Sequence(id)
+
 	borrow, lend, err := s.lendingOrderDao.GetLendingOrderBookInDb(term, lendingToken)
 	if err != nil {
 		logger.Error(err)
@@ -47,9 +54,10 @@ func (s *LendingOrderBookService) GetLendingOrderBookInDb(term uint64, lendingTo
 	}
 
 	ob := &types.LendingOrderBook{
-		Name:   utils.GetLendingOrderBookChannelID(term, lendingToken),
-		Lend:   lend,
-		Borrow: borrow,
+		Name:     id,
+		Lend:     lend,
+		Borrow:   borrow,
+		Sequence: sequence,
 	}
 
 	return ob, nil
@@ -60,21 +68,35 @@ func (s *LendingOrderBookService) GetLendingOrderBookInDb(term uint64, lendingTo
 func (s *LendingOrderBookService) SubscribeLendingOrderBook(c *ws.Client, term uint64, lendingToken common.Address) {
 	socket := ws.GetLendingOrderBookSocket()
 
+	id := utils.GetLendingOrderBookChannelID(term, lendingToken)
+	err := socket.Subscribe(id, c)
+	if err != nil {
+		msg := map[string]string{"Message": err.Error()}
+		socket.SendErrorMessage(c, msg)
+		return
+	}
+
 	ob, err := s.GetLendingOrderBook(term, lendingToken)
 	if err != nil {
+		socket.UnsubscribeChannel(id, c)
 		socket.SendErrorMessage(c, err.Error())
 		return
 	}
 
-	id := utils.GetLendingOrderBookChannelID(term, lendingToken)
-	err = socket.Subscribe(id, c)
+	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(id))
+	socket.SendInitMessage(c, ob)
+}
+
+// ResyncLendingOrderBook sends a new lending orderbook snapshot to a client that missed an update
+func (s *LendingOrderBookService) ResyncLendingOrderBook(c *ws.Client, term uint64, lendingToken common.Address) {
+	socket := ws.GetLendingOrderBookSocket()
+
+	ob, err := s.GetLendingOrderBook(term, lendingToken)
 	if err != nil {
-		msg := map[string]string{"Message": err.Error()}
-		socket.SendErrorMessage(c, msg)
+		socket.SendErrorMessage(c, err.Error())
 		return
 	}
 
-	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(id))
 	socket.SendInitMessage(c, ob)
 }
 
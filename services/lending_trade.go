@@ -1,7 +1,6 @@
 package services
 
 import (
-	"context"
 	"sync"
 	"time"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // LendingTradeService struct with daos required, responsible for communicating with daos.
@@ -92,69 +92,29 @@ func (s *LendingTradeService) GetLendingTradeByOrderBook(tern uint64, lendingTok
 	return s.lendingTradeDao.GetLendingTradeByOrderBook(tern, lendingToken, from, to, n)
 }
 
-// WatchChanges watch changing trade database
-func (s *LendingTradeService) WatchChanges() {
+// WatchChanges handles the changes of the lending trades stream
+func (s *LendingTradeService) WatchChanges(streams interfaces.ChangeStreamService) {
 	go func() {
 		for {
 			<-time.After(500 * time.Millisecond)
 			s.processBulkLendingTrades()
 		}
 	}()
-	s.watchChanges()
-}
-
-func (s *LendingTradeService) watchChanges() {
-
-	ct, sc, err := s.lendingTradeDao.Watch()
-
-	if err != nil {
-		logger.Error("Failed to open change stream")
-		return //exiting func
-	}
 
-	defer ct.Close()
-	defer sc.Close()
-
-	// Watch the event again in case there is error and function returned
-	defer s.WatchChanges()
-
-	ctx := context.Background()
-
-	//Handling change stream in a cycle
-	for {
-		select {
-		case <-ctx.Done(): // if parent context was cancelled
-			err := ct.Close() // close the stream
-			if err != nil {
-				logger.Error("Change stream closed")
-			}
-			return //exiting from the func
-		default:
-			ev := types.LendingTradeChangeEvent{}
-
-			//getting next item from the steam
-			ok := ct.Next(&ev)
-
-			//if data from the stream wasn't un-marshaled, we get ok == false as a result
-			//so we need to call Err() method to get info why
-			//it'll be nil if we just have no data
-			if !ok {
-				err := ct.Err()
-				if err != nil {
-					logger.Error(err)
-					return
-				}
-			}
-
-			//if item from the stream un-marshaled successfully, do something with it
-			if ok {
-				logger.Debugf("Operation Type: %s", ev.OperationType)
-				s.HandleDocumentType(ev)
-			}
+	streams.Watch("lending_trades", s.lendingTradeDao.Watch, func(raw bson.Raw) {
+		ev := types.LendingTradeChangeEvent{}
+		err := bson.Unmarshal(raw, &ev)
+		if err != nil {
+			logger.Error(err)
+			return
 		}
-	}
+
+		logger.Debugf("Operation Type: %s", ev.OperationType)
+		s.HandleDocumentType(ev)
+	})
 }
 
+
 func (s *LendingTradeService) processBulkLendingTrades() {
 	s.mutext.Lock()
 	defer s.mutext.Unlock()
@@ -4,12 +4,13 @@ import (
 	"math/big"
 	"time"
 
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/utils/math"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MarketsService struct with daos required, responsible for communicating with daos.
@@ -88,7 +89,7 @@ func (s *MarketsService) GetPairData() ([]*types.PairData, error) {
 	now := time.Now()
 	end := time.Unix(now.Unix(), 0)
 	start := time.Unix(now.AddDate(0, 0, -1).Unix(), 0)
-	one, _ := bson.ParseDecimal128("1")
+	one, _ := primitive.ParseDecimal128("1")
 
 	pairs, err := s.PairDao.GetActivePairs()
 	if err != nil {
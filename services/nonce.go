@@ -0,0 +1,302 @@
+package services
+
+import (
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// pendingNonceTimeout is the time a reserved nonce is held while the node has not counted it.
+// The nonces of the address are then synced from the node again, which frees the nonce if the
+// order holding it never reached the node
+var pendingNonceTimeout = time.Minute
+
+// NonceManager tracks the nonces reserved by the orders of each address which the node has not
+// processed yet. The node only counts the nonce of an order once it has processed it, so the
+// orders sent meanwhile would get the same nonce from the node. The manager hands out the next
+// nonce no pending order holds, rejects the nonces held by other pending orders, and frees the
+// nonces of the rejected orders so that they can be used again. A nonce can also be held for a
+// message signed in advance, like the cancel enforcing the time in force of an order or the
+// order of a stop order, until the message is sent or dropped
+type NonceManager struct {
+	fetch func(addr common.Address) (uint64, error)
+	held  func(addr common.Address) (map[uint64]common.Hash, error)
+
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonces
+}
+
+// accountNonces are the nonces of an address. count is the number of orders counted by the
+// node, the lowest nonce that can be used, and pending the nonces reserved above it
+type accountNonces struct {
+	mu      sync.Mutex
+	synced  bool
+	count   uint64
+	pending map[uint64]*pendingNonce
+}
+
+// pendingNonce is a reserved nonce. A held nonce is kept until it is released or counted by
+// the node, however long it takes
+type pendingNonce struct {
+	hash       common.Hash
+	reservedAt time.Time
+	held       bool
+}
+
+// NewNonceManager returns a nonce manager fetching the number of orders counted by the node
+// with fetch
+func NewNonceManager(fetch func(addr common.Address) (uint64, error)) *NonceManager {
+	return &NonceManager{
+		fetch:    fetch,
+		accounts: make(map[common.Address]*accountNonces),
+	}
+}
+
+// LoadHeldNonces makes the manager load the nonces of an address held for the messages stored
+// in the database with held each time it syncs the address, in place of the ones held since
+// the last sync. The servers of a cluster and a restarted server then hold the same nonces,
+// and a nonce released by another server is freed on the next sync
+func (m *NonceManager) LoadHeldNonces(held func(addr common.Address) (map[uint64]common.Hash, error)) {
+	m.held = held
+}
+
+// account returns the locked nonces of addr, synced from the node if they are not yet or a
+// nonce has been pending for too long
+func (m *NonceManager) account(addr common.Address) (*accountNonces, error) {
+	m.mu.Lock()
+	a, ok := m.accounts[addr]
+	if !ok {
+		a = &accountNonces{pending: make(map[uint64]*pendingNonce)}
+		m.accounts[addr] = a
+	}
+	m.mu.Unlock()
+
+	a.mu.Lock()
+	if !a.synced || a.expired(time.Now()) {
+		err := m.sync(addr, a)
+		if err != nil {
+			a.mu.Unlock()
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// sync fetches the count of addr from the node, and frees the nonces it counted and the ones
+// pending for too long. The held nonces are loaded again if they are stored
+func (m *NonceManager) sync(addr common.Address, a *accountNonces) error {
+	count, err := m.fetch(addr)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	var held map[uint64]common.Hash
+	if m.held != nil {
+		held, err = m.held(addr)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	now := time.Now()
+	for n, p := range a.pending {
+		if n < count || p.expired(now) || (m.held != nil && p.held) {
+			delete(a.pending, n)
+		}
+	}
+
+	for n, h := range held {
+		if _, ok := a.pending[n]; !ok && n >= count {
+			a.pending[n] = &pendingNonce{h, now, true}
+		}
+	}
+
+	a.count = count
+	a.synced = true
+	return nil
+}
+
+func (a *accountNonces) expired(now time.Time) bool {
+	for _, p := range a.pending {
+		if p.expired(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *pendingNonce) expired(now time.Time) bool {
+	return !p.held && now.Sub(p.reservedAt) > pendingNonceTimeout
+}
+
+// next returns the lowest nonce which is neither counted by the node nor pending
+func (a *accountNonces) next() uint64 {
+	n := a.count
+	for a.pending[n] != nil {
+		n++
+	}
+
+	return n
+}
+
+// Next returns the next nonce of addr that no pending order holds
+func (m *NonceManager) Next(addr common.Address) (uint64, error) {
+	a, err := m.account(addr)
+	if err != nil {
+		return 0, err
+	}
+
+	defer a.mu.Unlock()
+	return a.next(), nil
+}
+
+// Reserve reserves a nonce of addr for the order or the cancel with hash h, until the node
+// counts it or it is released. A nonce which is higher than the next one is checked against
+// the node first, as it may have counted orders sent by other servers. It fails with
+// NONCE_TOO_LOW when the node has already counted the nonce, and with NONCE_IN_USE when another
+// pending order holds it. A nonce held for the message with hash h is reserved for it as it is
+// sent
+func (m *NonceManager) Reserve(addr common.Address, nonce *big.Int, h common.Hash) error {
+	return m.reserve(addr, nonce, h, false)
+}
+
+// Hold reserves a nonce of addr for the message with hash h which is signed in advance, until
+// it is released or the node counts it. It fails like Reserve
+func (m *NonceManager) Hold(addr common.Address, nonce *big.Int, h common.Hash) error {
+	return m.reserve(addr, nonce, h, true)
+}
+
+func (m *NonceManager) reserve(addr common.Address, nonce *big.Int, h common.Hash, held bool) error {
+	if nonce == nil {
+		return errors.New("Nonce is missing")
+	}
+
+	a, err := m.account(addr)
+	if err != nil {
+		return err
+	}
+
+	defer a.mu.Unlock()
+
+	if !nonce.IsUint64() {
+		return errors.NonceTooLow(nonce.String(), strconv.FormatUint(a.next(), 10))
+	}
+
+	n := nonce.Uint64()
+	if n > a.next() {
+		err = m.sync(addr, a)
+		if err != nil {
+			return err
+		}
+	}
+
+	if n < a.count {
+		return errors.NonceTooLow(nonce.String(), strconv.FormatUint(a.next(), 10))
+	}
+
+	if p, ok := a.pending[n]; ok && p.hash != h {
+		return errors.NonceInUse(nonce.String(), strconv.FormatUint(a.next(), 10))
+	}
+
+	a.pending[n] = &pendingNonce{h, time.Now(), held}
+	return nil
+}
+
+// Release frees a nonce of addr reserved or held for the order or the cancel with hash h, which
+// was not sent, was rejected or is not needed anymore
+func (m *NonceManager) Release(addr common.Address, nonce *big.Int, h common.Hash) {
+	if nonce == nil || !nonce.IsUint64() {
+		return
+	}
+
+	m.mu.Lock()
+	a, ok := m.accounts[addr]
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if p, ok := a.pending[nonce.Uint64()]; ok && p.hash == h {
+		delete(a.pending, nonce.Uint64())
+	}
+}
+
+// Unhold frees a nonce of addr held for the message with hash h, which will not be sent. The
+// nonce is kept if the message has been sent meanwhile
+func (m *NonceManager) Unhold(addr common.Address, nonce *big.Int, h common.Hash) {
+	if nonce == nil || !nonce.IsUint64() {
+		return
+	}
+
+	m.mu.Lock()
+	a, ok := m.accounts[addr]
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if p, ok := a.pending[nonce.Uint64()]; ok && p.hash == h && p.held {
+		delete(a.pending, nonce.Uint64())
+	}
+}
+
+// Confirm records that the node counted a nonce of addr
+func (m *NonceManager) Confirm(addr common.Address, nonce *big.Int) {
+	if nonce == nil || !nonce.IsUint64() {
+		return
+	}
+
+	m.mu.Lock()
+	a, ok := m.accounts[addr]
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if nonce.Uint64() < a.count {
+		return
+	}
+
+	a.count = nonce.Uint64() + 1
+	for n := range a.pending {
+		if n < a.count {
+			delete(a.pending, n)
+		}
+	}
+}
+
+// Resync syncs the nonces of addr from the node on their next use, after the node rejected an
+// order whose nonce did not match its count
+func (m *NonceManager) Resync(addr common.Address) {
+	m.mu.Lock()
+	a, ok := m.accounts[addr]
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	a.mu.Lock()
+	a.synced = false
+	a.mu.Unlock()
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+type nodeNonces struct {
+	count   uint64
+	fetches int
+}
+
+func (n *nodeNonces) fetch(addr common.Address) (uint64, error) {
+	n.fetches++
+	return n.count, nil
+}
+
+func assertErrorCode(t *testing.T, code string, err error) {
+	apiErr, ok := err.(*errors.APIError)
+	if assert.True(t, ok, "expected an API error, got %v", err) {
+		assert.Equal(t, code, apiErr.ErrorCode)
+	}
+}
+
+func TestNonceManagerReserve(t *testing.T) {
+	node := &nodeNonces{count: 5}
+	m := NewNonceManager(node.fetch)
+	addr := common.HexToAddress("0x1")
+
+	next, err := m.Next(addr)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), next)
+
+	err = m.Reserve(addr, big.NewInt(5), common.HexToHash("0xa"))
+	assert.Nil(t, err)
+
+	// a concurrent order gets the following nonce without asking the node
+	next, _ = m.Next(addr)
+	assert.Equal(t, uint64(6), next)
+	assert.Equal(t, 1, node.fetches)
+
+	err = m.Reserve(addr, big.NewInt(5), common.HexToHash("0xb"))
+	assertErrorCode(t, "NONCE_IN_USE", err)
+
+	// a retry of the same order keeps its nonce
+	err = m.Reserve(addr, big.NewInt(5), common.HexToHash("0xa"))
+	assert.Nil(t, err)
+
+	err = m.Reserve(addr, big.NewInt(4), common.HexToHash("0xc"))
+	assertErrorCode(t, "NONCE_TOO_LOW", err)
+}
+
+func TestNonceManagerRelease(t *testing.T) {
+	node := &nodeNonces{count: 0}
+	m := NewNonceManager(node.fetch)
+	addr := common.HexToAddress("0x1")
+
+	m.Reserve(addr, big.NewInt(0), common.HexToHash("0xa"))
+	m.Reserve(addr, big.NewInt(1), common.HexToHash("0xb"))
+
+	// only the order holding the nonce frees it
+	m.Release(addr, big.NewInt(0), common.HexToHash("0xb"))
+	next, _ := m.Next(addr)
+	assert.Equal(t, uint64(2), next)
+
+	m.Release(addr, big.NewInt(0), common.HexToHash("0xa"))
+	next, _ = m.Next(addr)
+	assert.Equal(t, uint64(0), next)
+
+	err := m.Reserve(addr, big.NewInt(0), common.HexToHash("0xc"))
+	assert.Nil(t, err)
+}
+
+func TestNonceManagerConfirm(t *testing.T) {
+	node := &nodeNonces{count: 0}
+	m := NewNonceManager(node.fetch)
+	addr := common.HexToAddress("0x1")
+
+	m.Reserve(addr, big.NewInt(0), common.HexToHash("0xa"))
+	m.Reserve(addr, big.NewInt(1), common.HexToHash("0xb"))
+	m.Confirm(addr, big.NewInt(0))
+
+	next, _ := m.Next(addr)
+	assert.Equal(t, uint64(2), next)
+
+	err := m.Reserve(addr, big.NewInt(0), common.HexToHash("0xa"))
+	assertErrorCode(t, "NONCE_TOO_LOW", err)
+}
+
+func TestNonceManagerResync(t *testing.T) {
+	node := &nodeNonces{count: 0}
+	m := NewNonceManager(node.fetch)
+	addr := common.HexToAddress("0x1")
+
+	m.Reserve(addr, big.NewInt(0), common.HexToHash("0xa"))
+
+	// the node counted orders sent by another server
+	node.count = 3
+	m.Resync(addr)
+
+	next, _ := m.Next(addr)
+	assert.Equal(t, uint64(3), next)
+	assert.Equal(t, 2, node.fetches)
+
+	// a nonce ahead of the manager is checked against the node
+	node.count = 5
+	err := m.Reserve(addr, big.NewInt(5), common.HexToHash("0xb"))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, node.fetches)
+
+	next, _ = m.Next(addr)
+	assert.Equal(t, uint64(6), next)
+}
+
+func TestNonceManagerPendingTimeout(t *testing.T) {
+	timeout := pendingNonceTimeout
+	pendingNonceTimeout = 10 * time.Millisecond
+	defer func() { pendingNonceTimeout = timeout }()
+
+	node := &nodeNonces{count: 0}
+	m := NewNonceManager(node.fetch)
+	addr := common.HexToAddress("0x1")
+
+	m.Reserve(addr, big.NewInt(0), common.HexToHash("0xa"))
+	time.Sleep(20 * time.Millisecond)
+
+	// the order never reached the node, so its nonce is used again
+	next, _ := m.Next(addr)
+	assert.Equal(t, uint64(0), next)
+	assert.Equal(t, 2, node.fetches)
+}
+
+func TestNonceManagerHold(t *testing.T) {
+	timeout := pendingNonceTimeout
+	pendingNonceTimeout = 10 * time.Millisecond
+	defer func() { pendingNonceTimeout = timeout }()
+
+	node := &nodeNonces{count: 0}
+	m := NewNonceManager(node.fetch)
+	addr := common.HexToAddress("0x1")
+
+	m.Reserve(addr, big.NewInt(0), common.HexToHash("0xa"))
+	err := m.Hold(addr, big.NewInt(1), common.HexToHash("0xb"))
+	assert.Nil(t, err)
+
+	// the held nonce outlives the confirmation of the order before it and the timeout
+	m.Confirm(addr, big.NewInt(0))
+	time.Sleep(20 * time.Millisecond)
+	node.count = 1
+	m.Resync(addr)
+
+	next, _ := m.Next(addr)
+	assert.Equal(t, uint64(2), next)
+
+	err = m.Reserve(addr, big.NewInt(1), common.HexToHash("0xc"))
+	assertErrorCode(t, "NONCE_IN_USE", err)
+
+	// the message it is held for reserves it as it is sent, so it is not unheld anymore
+	err = m.Reserve(addr, big.NewInt(1), common.HexToHash("0xb"))
+	assert.Nil(t, err)
+	m.Unhold(addr, big.NewInt(1), common.HexToHash("0xb"))
+	next, _ = m.Next(addr)
+	assert.Equal(t, uint64(2), next)
+
+	m.Hold(addr, big.NewInt(2), common.HexToHash("0xd"))
+	m.Unhold(addr, big.NewInt(2), common.HexToHash("0xd"))
+	err = m.Reserve(addr, big.NewInt(2), common.HexToHash("0xe"))
+	assert.Nil(t, err)
+}
+
+func TestNonceManagerLoadHeldNonces(t *testing.T) {
+	node := &nodeNonces{count: 0}
+	m := NewNonceManager(node.fetch)
+	addr := common.HexToAddress("0x1")
+
+	stored := map[uint64]common.Hash{1: common.HexToHash("0xa")}
+	m.LoadHeldNonces(func(addr common.Address) (map[uint64]common.Hash, error) {
+		return stored, nil
+	})
+
+	next, _ := m.Next(addr)
+	assert.Equal(t, uint64(0), next)
+
+	err := m.Reserve(addr, big.NewInt(1), common.HexToHash("0xb"))
+	assertErrorCode(t, "NONCE_IN_USE", err)
+
+	// a nonce held by this server and released by another one is freed on the next sync
+	m.Hold(addr, big.NewInt(0), common.HexToHash("0xc"))
+	stored = map[uint64]common.Hash{}
+	m.Resync(addr)
+
+	next, _ = m.Next(addr)
+	assert.Equal(t, uint64(0), next)
+
+	err = m.Reserve(addr, big.NewInt(1), common.HexToHash("0xb"))
+	assert.Nil(t, err)
+}
@@ -2,9 +2,9 @@ package services
 
 import (
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // NotificationService struct with daos required, responsible for communicating with dao
@@ -51,7 +51,7 @@ func (s *NotificationService) GetSortDecByUserAddress(addr common.Address, limit
 }
 
 // GetByID fetches the detailed document of a notification using its mongo ID
-func (s *NotificationService) GetByID(id bson.ObjectId) (*types.Notification, error) {
+func (s *NotificationService) GetByID(id primitive.ObjectID) (*types.Notification, error) {
 	return s.NotificationDao.GetByID(id)
 }
 
@@ -68,12 +68,12 @@ func (s *NotificationService) Update(n *types.Notification) (*types.Notification
 }
 
 // MarkRead update UNREAD status to READ status
-func (s *NotificationService) MarkRead(id bson.ObjectId) error {
+func (s *NotificationService) MarkRead(id primitive.ObjectID) error {
 	return s.NotificationDao.MarkRead(id)
 }
 
 // MarkUnRead update READ status to UNREAD status
-func (s *NotificationService) MarkUnRead(id bson.ObjectId) error {
+func (s *NotificationService) MarkUnRead(id primitive.ObjectID) error {
 	return s.NotificationDao.MarkUnRead(id)
 }
 
@@ -1,12 +1,9 @@
 package services
 
 import (
-	"bufio"
-	"encoding/json"
 	"fmt"
 	"math"
 	"math/big"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,12 +11,14 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -31,6 +30,11 @@ const (
 	baseFiat         = "USDT"
 	tomo             = "TOMO"
 	cacheTimeLifeMax = 15 * 50
+
+	// followInterval is the interval at which the replicas that do not handle the trades load
+	// the ticks stored by the leader. The ticks are loaded with an overlap of one interval, so
+	// that the ticks are not missed because of the clock skew between the replicas
+	followInterval = 5 * time.Second
 )
 
 type PairCache struct {
@@ -45,6 +49,7 @@ type OHLCVService struct {
 	tradeDao           interfaces.TradeDao
 	pairDao            interfaces.PairDao
 	tokenDao           interfaces.TokenDao
+	ohlcvDao           interfaces.OHLCVDao
 	tickCache          *tickCache
 	mutex              sync.RWMutex
 	tokenCache         map[common.Address]*TokenCache
@@ -53,21 +58,10 @@ type OHLCVService struct {
 	priceCacheByUsdt   map[string]*big.Int
 }
 
-type timeframe struct {
-	FirstTime int64 `json:"firstTime" bson:"firstTime"`
-	LastTime  int64 `json:"lastTime" bson:"lastTime"`
-}
-type timeframes []*timeframe
-
 type tickCache struct {
-	tframes timeframes
-	ticks   map[string]map[int64]*types.Tick
+	ticks map[string]map[int64]*types.Tick
 }
 
-type tickfile struct {
-	Frame timeframes  `json:"frame" bson:"frame"`
-	Ticks types.Ticks `json:"ticks" bson:"ticks"`
-}
 type durationtick struct {
 	duration int64
 	unit     string
@@ -75,7 +69,7 @@ type durationtick struct {
 }
 
 // NewOHLCVService init new ohlcv service
-func NewOHLCVService(TradeDao interfaces.TradeDao, pairDao interfaces.PairDao, tokenDao interfaces.TokenDao) *OHLCVService {
+func NewOHLCVService(TradeDao interfaces.TradeDao, pairDao interfaces.PairDao, tokenDao interfaces.TokenDao, ohlcvDao interfaces.OHLCVDao) *OHLCVService {
 	cache := &tickCache{
 		ticks: make(map[string]map[int64]*types.Tick),
 	}
@@ -83,6 +77,7 @@ func NewOHLCVService(TradeDao interfaces.TradeDao, pairDao interfaces.PairDao, t
 		tradeDao:           TradeDao,
 		pairDao:            pairDao,
 		tokenDao:           tokenDao,
+		ohlcvDao:           ohlcvDao,
 		tickCache:          cache,
 		tokenCache:         make(map[common.Address]*TokenCache),
 		pairCacheByAddress: make(map[string]*PairCache),
@@ -132,8 +127,10 @@ func (s *OHLCVService) Subscribe(conn *ws.Client, p *types.SubscriptionPayload)
 	socket.SendInitMessage(conn, ohlcv)
 }
 
+// getConfig returns the durations of the ticks, including the durations of the tick_duration
+// config that are not in the default list
 func (s *OHLCVService) getConfig() []durationtick {
-	return []durationtick{
+	durations := []durationtick{
 		{
 			duration: 1,
 			unit:     "min",
@@ -210,53 +207,70 @@ func (s *OHLCVService) getConfig() []durationtick {
 			interval: intervalMax,
 		},
 	}
+
+	for unit, ds := range app.Config.TickDuration {
+		for _, d := range ds {
+			found := false
+			for _, dt := range durations {
+				if dt.duration == d && dt.unit == unit {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				durations = append(durations, durationtick{duration: d, unit: unit, interval: intervalMax})
+			}
+		}
+	}
+
+	return durations
 }
 
-// Init init cache
-// ensure add current time frame before trade notify come
+// TickDurations returns the durations of the ticks built from the trades
+func (s *OHLCVService) TickDurations() []types.TickDuration {
+	res := []types.TickDuration{}
+	for _, d := range s.getConfig() {
+		res = append(res, types.TickDuration{Duration: d.duration, Unit: d.unit})
+	}
+
+	return res
+}
+
+// Init loads the ticks stored in the ohlcv collection and builds the ticks of the trades
+// made since the last stored tick, so that only the missing range is fetched from the trades.
+// Ticks are then upserted as trades come
 func (s *OHLCVService) Init() {
 	logger.Info("OHLCV init starting...")
 	now := time.Now().Unix()
-	datefrom := now - intervalMin
-	s.loadCache()
-	lastFrame := s.lastTimeFrame()
-	if lastFrame != nil {
-		logger.Info("last frame first time", time.Unix(lastFrame.FirstTime, 0))
-		if now-lastFrame.LastTime < intervalMin {
-			datefrom = lastFrame.LastTime
-		}
-	} else {
-		// add start frame to list
-		s.tickCache.tframes = append(s.tickCache.tframes, &timeframe{
-			FirstTime: now - intervalMax,
-			LastTime:  now - intervalMax,
-		})
+	datefrom := now - intervalMax
+
+	ticks, err := s.ohlcvDao.GetTicks(datefrom)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, t := range ticks {
+		s.addTick(t)
+	}
+
+	last, err := s.ohlcvDao.GetLastTick()
+	if err != nil {
+		panic(err)
+	}
+
+	if last != nil {
+		logger.Info("last tick close time", last.CloseTime)
+		datefrom = last.CloseTime.Unix()
 	}
-	// add current frame to list
-	s.tickCache.tframes = append(s.tickCache.tframes, &timeframe{
-		FirstTime: now,
-		LastTime:  now,
-	})
 
-	lastFrame = s.lastTimeFrame()
 	logger.Info("init fetch", time.Unix(datefrom, 0), time.Unix(now, 0))
-	s.fetch(datefrom, now, lastFrame)
-	s.commitCache()
-	go s.continueCache()
-	ticker := time.NewTicker(60 * time.Second)
-	quit := make(chan struct{})
+	s.fetch(datefrom, now)
+
+	ticker := time.NewTicker(time.Hour)
 	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				err := s.commitCache()
-				if err != nil {
-					logger.Error(err)
-				}
-			case <-quit:
-				ticker.Stop()
-				return
-			}
+		for range ticker.C {
+			s.removeExpiredTicks()
 		}
 	}()
 
@@ -273,6 +287,22 @@ func (s *OHLCVService) getIntervelByUint(d int64, unit string) (int64, error) {
 	return 0, errors.New("unit not found")
 }
 
+// removeExpiredTicks removes the ticks older than the interval of their duration
+func (s *OHLCVService) removeExpiredTicks() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.truncate()
+
+	now := time.Now().Unix()
+	for _, d := range s.getConfig() {
+		err := s.ohlcvDao.DeleteTicks(d.duration, d.unit, now-d.interval)
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+}
+
 // cache need to be locked
 func (s *OHLCVService) truncate() {
 	now := time.Now().Unix()
@@ -290,133 +320,89 @@ func (s *OHLCVService) truncate() {
 		}
 	}
 }
-func (s *OHLCVService) fetch(fromdate int64, todate int64, frame *timeframe) {
+
+// fetch builds the ticks of the trades made between fromdate and todate, one day at a time
+// and oldest first. A trade that a tick already includes is skipped, so that the trades of
+// the last stored tick are not counted twice
+func (s *OHLCVService) fetch(fromdate int64, todate int64) {
 	durations := s.getConfig()
-	pageOffset := 0
-	size := 1000
 	now := time.Now().Unix()
-	for {
-		trades, err := s.tradeDao.GetTradeByTime(fromdate, todate, pageOffset*size, size)
-		logger.Debug("FETCH DATA", pageOffset*size)
-		if err != nil || len(trades) == 0 {
-			break
+	for start := fromdate; start < todate; start = start + intervalMin {
+		end := start + intervalMin
+		if end > todate {
+			end = todate
 		}
-		sort.Slice(trades, func(i, j int) bool {
-			return trades[i].CreatedAt.Unix() < trades[j].CreatedAt.Unix()
-		})
+
+		trades := s.fetchTrades(start, end)
+		if len(trades) == 0 {
+			continue
+		}
+
 		s.mutex.Lock()
-		for i, trade := range trades {
+		updated := make(map[*types.Tick]bool)
+		for _, trade := range trades {
 			for _, d := range durations {
 				key := s.getTickKey(trade.BaseToken, trade.QuoteToken, d.duration, d.unit)
-				if trade.CreatedAt.Unix() > now-d.interval {
-					s.updateTick(key, trade)
+				if trade.CreatedAt.Unix() > now-d.interval && !s.hasTrade(key, trade) {
+					tick, err := s.updateTick(key, trade)
+					if err == nil && tick != nil {
+						updated[tick] = true
+					}
 				}
 			}
-			if i == 0 {
-				s.updatefisttimeframe(trade.CreatedAt.Unix(), frame)
-			}
+		}
 
+		ticks := []*types.Tick{}
+		for t := range updated {
+			ticks = append(ticks, t)
 		}
+
+		s.saveTicks(ticks)
 		s.mutex.Unlock()
-		pageOffset = pageOffset + 1
 	}
 }
 
-// ensure init cache finished before invoke
-func (s *OHLCVService) continueCache() {
-	if len(s.tickCache.tframes) > 1 {
-		for i := len(s.tickCache.tframes) - 1; i > 0; i-- {
-			currentframe := s.tickCache.tframes[i]
-			preframe := s.tickCache.tframes[i-1]
-			if currentframe.FirstTime > preframe.LastTime {
-				logger.Debug("continue cache", time.Unix(preframe.LastTime, 0), time.Unix(currentframe.FirstTime, 0))
-				s.fetch(preframe.LastTime, currentframe.FirstTime, currentframe)
-			}
-
+// fetchTrades returns the trades made between fromdate and todate, oldest first
+func (s *OHLCVService) fetchTrades(fromdate int64, todate int64) []*types.Trade {
+	pageOffset := 0
+	size := 1000
+	res := []*types.Trade{}
+	for {
+		trades, err := s.tradeDao.GetTradeByTime(fromdate, todate, pageOffset*size, size)
+		logger.Debug("FETCH DATA", pageOffset*size)
+		if err != nil || len(trades) == 0 {
+			break
 		}
-	}
-	logger.Debug("continueCache finished")
-}
-func (s *OHLCVService) lastTimeFrame() *timeframe {
-	if len(s.tickCache.tframes) > 0 {
-		return s.tickCache.tframes[len(s.tickCache.tframes)-1]
-	}
-	return nil
-}
-func (s *OHLCVService) updatefisttimeframe(firsttime int64, frame *timeframe) {
-	logger.Info("updatefisttimeframe", time.Unix(firsttime, 0))
-	if frame != nil {
-		frame.FirstTime = firsttime
-	}
-}
 
-func (s *OHLCVService) updatelasttimeframe(lasttime int64, frame *timeframe) {
-	if frame != nil {
-		frame.LastTime = lasttime
+		res = append(res, trades...)
+		pageOffset = pageOffset + 1
 	}
 
-}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
 
-func (s *OHLCVService) flatten() []*types.Tick {
-	var ticks []*types.Tick
-	for _, tickbytime := range s.tickCache.ticks {
-		for _, tick := range tickbytime {
-			ticks = append(ticks, tick)
-		}
-	}
-	return ticks
+	return res
 }
 
-func (s *OHLCVService) commitCache() error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-	logger.Info("commit ohlcv cache")
-	s.truncate()
-	ticks := s.flatten()
-	tickfile := &tickfile{
-		Frame: s.tickCache.tframes,
-		Ticks: ticks,
-	}
-	tickData, err := json.Marshal(tickfile)
+// hasTrade returns true if the tick of a trade was closed after the trade, need to be lock
+func (s *OHLCVService) hasTrade(key string, trade *types.Trade) bool {
+	_, _, duration, unit, err := s.parseTickKey(key)
 	if err != nil {
-		return err
-	}
-	file, err := os.Create("ohlcv.cache")
-	defer file.Close()
-	if err == nil {
-		_, err = file.Write(tickData)
-		if err != nil {
-			return err
-		}
+		return false
 	}
-	return nil
-}
 
-func (s *OHLCVService) loadCache() error {
-	file, err := os.Open("ohlcv.cache")
-	defer file.Close()
-	if err != nil {
-		return err
-	}
-	stats, statsErr := file.Stat()
-	if statsErr != nil {
-		return statsErr
-	}
+	modTime, _ := utils.GetModTime(trade.CreatedAt.Unix(), duration, unit)
+	tick, ok := s.tickCache.ticks[key][modTime]
+	return ok && !trade.CreatedAt.After(tick.CloseTime)
+}
 
-	size := stats.Size()
-	bytes := make([]byte, size)
-	bufr := bufio.NewReader(file)
-	_, err = bufr.Read(bytes)
-	var tickf tickfile
-	err = json.Unmarshal(bytes, &tickf)
+// saveTicks upserts the ticks in the ohlcv collection
+func (s *OHLCVService) saveTicks(ticks []*types.Tick) {
+	err := s.ohlcvDao.UpsertTicks(ticks)
 	if err != nil {
-		return err
-	}
-	for _, t := range tickf.Ticks {
-		s.addTick(t)
+		logger.Error(err)
 	}
-	s.tickCache.tframes = tickf.Frame
-	return nil
 }
 
 func (s *OHLCVService) getTickKey(baseToken, quoteToken common.Address, duration int64, unit string) string {
@@ -448,12 +434,12 @@ func (s *OHLCVService) getVolumeByQuote(baseToken, quoteToken common.Address, am
 	return big.NewInt(0)
 }
 
-// updateTick update lastest tick, need to be lock
-func (s *OHLCVService) updateTick(key string, trade *types.Trade) error {
+// updateTick update lastest tick and returns it, need to be lock
+func (s *OHLCVService) updateTick(key string, trade *types.Trade) (*types.Tick, error) {
 	tradeTime := trade.CreatedAt.Unix()
 	baseToken, quoteToken, duration, unit, err := s.parseTickKey(key)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if baseToken.Hex() == trade.BaseToken.Hex() && quoteToken.Hex() == trade.QuoteToken.Hex() {
 		modTime, _ := utils.GetModTime(tradeTime, duration, unit)
@@ -474,6 +460,7 @@ func (s *OHLCVService) updateTick(key string, trade *types.Trade) error {
 				last.VolumeByQuote = big.NewInt(0).Add(last.VolumeByQuote, s.getVolumeByQuote(trade.BaseToken, trade.QuoteToken, trade.Amount, trade.PricePoint))
 				last.Count = last.Count.Add(last.Count, big.NewInt(1))
 				last.CloseTime = trade.CreatedAt
+				return last, nil
 			} else {
 				tick := &types.Tick{
 					Pair: types.PairID{
@@ -482,6 +469,7 @@ func (s *OHLCVService) updateTick(key string, trade *types.Trade) error {
 						QuoteToken: trade.QuoteToken,
 					},
 					OpenTime:      trade.CreatedAt,
+					CloseTime:     trade.CreatedAt,
 					Open:          trade.PricePoint,
 					Close:         trade.PricePoint,
 					High:          trade.PricePoint,
@@ -494,15 +482,15 @@ func (s *OHLCVService) updateTick(key string, trade *types.Trade) error {
 					Unit:          unit,
 				}
 				tickByTime[modTime] = tick
+				return tick, nil
 			}
 		}
 	}
 
-	return nil
+	return nil, nil
 }
 
 func (s *OHLCVService) addTick(tick *types.Tick) {
-	tick.VolumeByQuote = big.NewInt(0)
 	key := s.getTickKey(tick.Pair.BaseToken, tick.Pair.QuoteToken, tick.Duration, tick.Unit)
 	if _, ok := s.tickCache.ticks[key]; ok {
 
@@ -580,16 +568,56 @@ func (s *OHLCVService) get24hTick(baseToken, quoteToken common.Address) *types.T
 	return nil
 }
 
+// Follow loads the ticks stored in the ohlcv collection by the leader replica, which handles the
+// trades, every followInterval until stop is closed. The ticks stored meanwhile are loaded before
+// it returns, so that the replica can then handle the trades if it is elected
+func (s *OHLCVService) Follow(stop <-chan struct{}) {
+	since := time.Now()
+
+	for {
+		select {
+		case <-stop:
+			s.follow(since)
+			return
+		case <-time.After(followInterval):
+			since = s.follow(since)
+		}
+	}
+}
+
+// follow loads the ticks stored since a time, and returns the time to load the next ones from
+func (s *OHLCVService) follow(since time.Time) time.Time {
+	next := time.Now()
+
+	ticks, err := s.ohlcvDao.GetTicksUpdatedSince(since.Add(-followInterval))
+	if err != nil {
+		logger.Error(err)
+		return since
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, t := range ticks {
+		s.addTick(t)
+	}
+
+	return next
+}
+
 // NotifyTrade trigger if trade comming
 func (s *OHLCVService) NotifyTrade(trade *types.Trade) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
+	ticks := []*types.Tick{}
 	for _, d := range s.getConfig() {
 		key := s.getTickKey(trade.BaseToken, trade.QuoteToken, d.duration, d.unit)
-		s.updateTick(key, trade)
+		tick, err := s.updateTick(key, trade)
+		if err == nil && tick != nil {
+			ticks = append(ticks, tick)
+		}
 	}
-	lastFrame := s.lastTimeFrame()
-	s.updatelasttimeframe(trade.CreatedAt.Unix(), lastFrame)
+	s.saveTicks(ticks)
 }
 func (s *OHLCVService) getOHLCV(pairs []types.PairAddresses, duration int64, unit string, start, end time.Time) ([]*types.Tick, error) {
 	res := make([]*types.Tick, 0)
@@ -684,7 +712,7 @@ func getGroupAddFieldBson(key, units string, duration int64) (bson.M, bson.M) {
 		date = key
 	}
 
-	one, _ := bson.ParseDecimal128("1")
+	one, _ := primitive.ParseDecimal128("1")
 	group = bson.M{
 		"count":     bson.M{"$sum": one},
 		"high":      bson.M{"$max": "$pricepoint"},
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// RebuildTicks recomputes from the trades the ticks of the pairs and durations between from
+// and to, and replaces the stored ticks with them. The range of each duration is rounded down
+// to whole ticks, so that no tick is rebuilt from a part of its trades. The ticks of each pair
+// and duration are replaced in place once they are rebuilt, so that the ticks the servers store
+// meanwhile out of the ranges are kept. It returns the ranges replaced, which the servers should
+// reload with ReloadTicks, even if a later range fails. progress is called after each pair and
+// duration with the number of ticks
+func (s *OHLCVService) RebuildTicks(pairs []*types.Pair, durations []types.TickDuration, from, to int64, progress func(done, total int, r *types.TickRange, ticks int)) ([]*types.TickRange, error) {
+	ranges := []*types.TickRange{}
+	decimals := []int{}
+	for _, p := range pairs {
+		for _, d := range durations {
+			start, _ := utils.GetModTime(from, d.Duration, d.Unit)
+			end, _ := utils.GetModTime(to, d.Duration, d.Unit)
+			if start >= end {
+				continue
+			}
+
+			ranges = append(ranges, &types.TickRange{
+				Pair: types.PairAddresses{
+					Name:       p.Name(),
+					BaseToken:  p.BaseTokenAddress,
+					QuoteToken: p.QuoteTokenAddress,
+				},
+				Duration: d.Duration,
+				Unit:     d.Unit,
+				From:     start,
+				To:       end,
+			})
+			decimals = append(decimals, p.BaseTokenDecimals)
+		}
+	}
+
+	if len(ranges) == 0 {
+		return nil, errors.New("The time range does not cover a whole tick")
+	}
+
+	for i, r := range ranges {
+		ticks, err := s.aggregateTicks(r, decimals[i])
+		if err == nil {
+			err = s.ohlcvDao.ReplaceTicks(r, ticks)
+		}
+
+		if err != nil {
+			return ranges[:i], err
+		}
+
+		if progress != nil {
+			progress(i+1, len(ranges), r, len(ticks))
+		}
+	}
+
+	return ranges, nil
+}
+
+// ReloadTicks replaces the ticks of the ranges held by the service with the stored ones, after
+// they were rebuilt
+func (s *OHLCVService) ReloadTicks(ranges []*types.TickRange) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, r := range ranges {
+		ticks, err := s.ohlcvDao.GetTicksInRange(r)
+		if err != nil {
+			return err
+		}
+
+		key := s.getTickKey(r.Pair.BaseToken, r.Pair.QuoteToken, r.Duration, r.Unit)
+		for ts := range s.tickCache.ticks[key] {
+			if ts >= r.From && ts < r.To {
+				delete(s.tickCache.ticks[key], ts)
+			}
+		}
+
+		for _, t := range ticks {
+			s.addTick(t)
+		}
+	}
+
+	return nil
+}
+
+// aggregateTicks computes the ticks of a range from the trades. Trades are grouped by the
+// timestamp of their tick, computed the same way as for the ticks built as trades come
+func (s *OHLCVService) aggregateTicks(r *types.TickRange, baseTokenDecimals int) ([]*types.Tick, error) {
+	_, interval := utils.GetModTime(r.From, r.Duration, r.Unit)
+
+	seconds := bson.M{"$toLong": bson.M{"$floor": bson.M{"$divide": []interface{}{
+		bson.M{"$subtract": []interface{}{"$createdAt", time.Unix(0, 0)}},
+		1000,
+	}}}}
+
+	group, _ := getGroupAddFieldBson("$createdAt", r.Unit, r.Duration)
+	group["_id"] = bson.M{
+		"pairName":   "$pairName",
+		"baseToken":  "$baseToken",
+		"quoteToken": "$quoteToken",
+		"timestamp": bson.M{"$subtract": []interface{}{
+			"$seconds",
+			bson.M{"$mod": []interface{}{"$seconds", interval}},
+		}},
+	}
+	group["volumeByQuote"] = bson.M{"$sum": bson.M{"$multiply": []interface{}{
+		bson.M{"$toDecimal": "$amount"},
+		bson.M{"$toDecimal": "$pricepoint"},
+	}}}
+
+	query := []bson.M{
+		{"$match": getMatchQuery(time.Unix(r.From, 0), time.Unix(r.To, 0), r.Pair)},
+		{"$sort": bson.M{"createdAt": 1}},
+		{"$addFields": bson.M{"seconds": seconds}},
+		{"$group": group},
+		{"$addFields": bson.M{
+			"timestamp":     "$_id.timestamp",
+			"volumeByQuote": bson.M{"$toString": "$volumeByQuote"},
+		}},
+		{"$sort": bson.M{"timestamp": 1}},
+	}
+
+	ticks, err := s.tradeDao.Aggregate(query)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := math.Exp(big.NewInt(10), big.NewInt(int64(baseTokenDecimals)))
+	for _, t := range ticks {
+		t.Duration = r.Duration
+		t.Unit = r.Unit
+
+		if t.VolumeByQuote == nil {
+			t.VolumeByQuote = big.NewInt(0)
+		}
+
+		t.VolumeByQuote = math.Div(t.VolumeByQuote, decimals)
+	}
+
+	return ticks, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
+)
+
+func TestRebuildTicks(t *testing.T) {
+	tradeDao := new(mocks.TradeDao)
+	ohlcvDao := new(mocks.OHLCVDao)
+	s := NewOHLCVService(tradeDao, nil, nil, ohlcvDao)
+
+	pair := &types.Pair{
+		BaseTokenSymbol:   "ZRX",
+		BaseTokenAddress:  common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		BaseTokenDecimals: 18,
+		QuoteTokenSymbol:  "TOMO",
+		QuoteTokenAddress: common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+	}
+
+	durations := []types.TickDuration{{Duration: 1, Unit: "hour"}, {Duration: 1, Unit: "day"}}
+
+	ohlcvDao.On("ReplaceTicks", mock.Anything, mock.Anything).Return(nil)
+	tradeDao.On("Aggregate", mock.Anything).Return([]*types.Tick{{VolumeByQuote: big.NewInt(3000000000000000000)}}, nil)
+
+	// 1 hour and 30 minutes after midnight to 3 hours and 30 minutes after midnight
+	ranges, err := s.RebuildTicks([]*types.Pair{pair}, durations, 5400, 12600, nil)
+	assert.Nil(t, err)
+
+	// the range of the hours is rounded down to whole ticks and the range of the days is empty
+	assert.Equal(t, 1, len(ranges))
+	assert.Equal(t, int64(3600), ranges[0].From)
+	assert.Equal(t, int64(10800), ranges[0].To)
+	assert.Equal(t, "hour", ranges[0].Unit)
+
+	ohlcvDao.AssertNumberOfCalls(t, "ReplaceTicks", 1)
+	assert.Equal(t, ranges[0], ohlcvDao.Calls[0].Arguments.Get(0))
+
+	ticks := ohlcvDao.Calls[0].Arguments.Get(1).([]*types.Tick)
+	assert.Equal(t, big.NewInt(3), ticks[0].VolumeByQuote)
+	assert.Equal(t, "hour", ticks[0].Unit)
+}
+
+func TestRebuildTicksStopsOnError(t *testing.T) {
+	tradeDao := new(mocks.TradeDao)
+	ohlcvDao := new(mocks.OHLCVDao)
+	s := NewOHLCVService(tradeDao, nil, nil, ohlcvDao)
+
+	pair := &types.Pair{
+		BaseTokenSymbol:   "ZRX",
+		BaseTokenAddress:  common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		BaseTokenDecimals: 18,
+		QuoteTokenSymbol:  "TOMO",
+		QuoteTokenAddress: common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+	}
+
+	tradeDao.On("Aggregate", mock.Anything).Return(nil, errors.New("aggregate failed"))
+
+	ranges, err := s.RebuildTicks([]*types.Pair{pair}, []types.TickDuration{{Duration: 1, Unit: "hour"}}, 0, 7200, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(ranges))
+
+	ohlcvDao.AssertNotCalled(t, "ReplaceTicks", mock.Anything, mock.Anything)
+}
+
+func TestReloadTicks(t *testing.T) {
+	ohlcvDao := new(mocks.OHLCVDao)
+	s := NewOHLCVService(nil, nil, nil, ohlcvDao)
+
+	pair := types.PairID{
+		PairName:   "ZRX/TOMO",
+		BaseToken:  common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteToken: common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+	}
+
+	tick := func(ts int64, count int64) *types.Tick {
+		return &types.Tick{Pair: pair, Duration: 1, Unit: "hour", Timestamp: ts, Count: big.NewInt(count)}
+	}
+
+	// the tick at 3600 is removed by the rebuild, the one at 7200 is rebuilt and the one at
+	// 10800 is out of the range
+	s.addTick(tick(3600, 1))
+	s.addTick(tick(7200, 1))
+	s.addTick(tick(10800, 1))
+
+	r := &types.TickRange{
+		Pair:     types.PairAddresses{Name: pair.PairName, BaseToken: pair.BaseToken, QuoteToken: pair.QuoteToken},
+		Duration: 1,
+		Unit:     "hour",
+		From:     3600,
+		To:       10800,
+	}
+
+	ohlcvDao.On("GetTicksInRange", r).Return([]*types.Tick{tick(7200, 5)}, nil)
+
+	err := s.ReloadTicks([]*types.TickRange{r})
+	assert.Nil(t, err)
+
+	ticks := s.tickCache.ticks[s.getTickKey(pair.BaseToken, pair.QuoteToken, 1, "hour")]
+	assert.Equal(t, 2, len(ticks))
+	assert.Equal(t, big.NewInt(5), ticks[7200].Count)
+	assert.Equal(t, big.NewInt(1), ticks[10800].Count)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
+)
+
+func TestOHLCVInitBackfillsMissingTrades(t *testing.T) {
+	tradeDao := new(mocks.TradeDao)
+	tokenDao := new(mocks.TokenDao)
+	ohlcvDao := new(mocks.OHLCVDao)
+	s := NewOHLCVService(tradeDao, nil, tokenDao, ohlcvDao)
+
+	bt := common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498")
+	qt := common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093")
+	start := time.Unix((time.Now().Unix()/60-5)*60, 0)
+
+	newTrade := func(createdAt time.Time) *types.Trade {
+		return &types.Trade{
+			BaseToken:  bt,
+			QuoteToken: qt,
+			PairName:   "ZRX/TOMO",
+			PricePoint: big.NewInt(100),
+			Amount:     big.NewInt(10),
+			CreatedAt:  createdAt,
+		}
+	}
+
+	stored := &types.Tick{
+		Pair:          types.PairID{PairName: "ZRX/TOMO", BaseToken: bt, QuoteToken: qt},
+		Open:          big.NewInt(100),
+		High:          big.NewInt(100),
+		Low:           big.NewInt(100),
+		Close:         big.NewInt(100),
+		Volume:        big.NewInt(10),
+		VolumeByQuote: big.NewInt(0),
+		Count:         big.NewInt(1),
+		Timestamp:     start.Unix(),
+		OpenTime:      start,
+		CloseTime:     start,
+		Duration:      1,
+		Unit:          "min",
+	}
+
+	var saved []*types.Tick
+	ohlcvDao.On("GetTicks", mock.Anything).Return([]*types.Tick{stored}, nil)
+	ohlcvDao.On("GetLastTick").Return(stored, nil)
+	ohlcvDao.On("UpsertTicks", mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(0).([]*types.Tick)
+	}).Return(nil)
+	tokenDao.On("GetByAddress", bt).Return(&types.Token{Decimals: 18}, nil)
+	tradeDao.On("GetTradeByTime", start.Unix(), mock.Anything, 0, 1000).Return([]*types.Trade{
+		newTrade(start.Add(10 * time.Second)),
+		newTrade(start),
+	}, nil)
+	tradeDao.On("GetTradeByTime", start.Unix(), mock.Anything, 1000, 1000).Return([]*types.Trade{}, nil)
+
+	s.Init()
+
+	minuteKey := s.getTickKey(bt, qt, 1, "min")
+	assert.Equal(t, big.NewInt(2), s.tickCache.ticks[minuteKey][start.Unix()].Count)
+	assert.Equal(t, start.Add(10*time.Second), s.tickCache.ticks[minuteKey][start.Unix()].CloseTime)
+
+	hourKey := s.getTickKey(bt, qt, 1, "hour")
+	for _, tick := range s.tickCache.ticks[hourKey] {
+		assert.Equal(t, big.NewInt(2), tick.Count)
+	}
+
+	assert.Equal(t, len(s.getConfig()), len(saved))
+	tradeDao.AssertNumberOfCalls(t, "GetTradeByTime", 2)
+}
@@ -1,3 +1,6 @@
+//go:build integration
+// +build integration
+
 package services
 
 import (
@@ -11,11 +14,11 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/daos"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const timeLayoutString = "Jan 2 2006 15:04:05"
@@ -98,10 +101,10 @@ func TestOHLCV(t *testing.T) {
 		Amount:         big.NewInt(125772),
 	}
 	app.Config.DBName = "tomodex"
-	tradeDao := daos.NewTradeDao()
-	pairDao := daos.NewPairDao()
-	fiatPriceDao := daos.NewFiatPriceDao()
-	ohlcvService := NewOHLCVService(tradeDao, pairDao, fiatPriceDao)
+	tradeDao := daos.NewTradeDao(database)
+	pairDao := daos.NewPairDao(database)
+	tokenDao := daos.NewTokenDao(database)
+	ohlcvService := NewOHLCVService(tradeDao, pairDao, tokenDao, daos.NewOHLCVDao(database))
 
 	for _, t := range testTimes {
 		tTime, err := time.Parse(timeLayoutString, t)
@@ -113,10 +116,10 @@ func TestOHLCV(t *testing.T) {
 		sampleTrade.CreatedAt = tTime
 		sampleTrade.Amount = amt.Add(sampleTrade.Amount, big.NewInt(10))
 		sampleTrade.PricePoint = prc.Add(sampleTrade.PricePoint, big.NewInt(5))
-		sampleTrade.ID = bson.NewObjectId()
+		sampleTrade.ID = primitive.NewObjectID()
 		sampleTrade.Hash = sampleTrade.ComputeHash()
 
-		if err := db.DB(app.Config.DBName).C("trades").Insert(&sampleTrade); err != nil {
+		if err := database.Create(app.Config.DBName, "trades", &sampleTrade); err != nil {
 			panic(err)
 		}
 
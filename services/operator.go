@@ -0,0 +1,218 @@
+package services
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// ErrNotOperator is returned when an address which is not a trading operator asks for an
+	// order to be signed
+	ErrNotOperator = errors.New("Address is not a trading operator")
+	// ErrNoOperatorWallet is returned when the user of an order is not an operator wallet
+	ErrNoOperatorWallet = errors.New("No operator wallet with this address")
+)
+
+// OperatorService places and cancels the orders of the operator wallets stored by the server
+// for the trading operators, so that they can trade without holding the keys. The orders are
+// signed with the nonce of the wallet on TomoX, and each signed order or cancel is recorded in
+// the audit log with the operator who requested it before it is sent
+type OperatorService struct {
+	walletDao         interfaces.WalletDao
+	orderService      interfaces.OrderService
+	operatorActionDao interfaces.OperatorActionDao
+	operators         map[common.Address]bool
+}
+
+// NewOperatorService returns a new instance of OperatorService, for the given trading operators
+func NewOperatorService(
+	walletDao interfaces.WalletDao,
+	orderService interfaces.OrderService,
+	operatorActionDao interfaces.OperatorActionDao,
+	operators []common.Address,
+) *OperatorService {
+	allowed := make(map[common.Address]bool)
+	for _, addr := range operators {
+		allowed[addr] = true
+	}
+
+	return &OperatorService{walletDao, orderService, operatorActionDao, allowed}
+}
+
+// IsOperator tells whether an address is a trading operator
+func (s *OperatorService) IsOperator(addr common.Address) bool {
+	return s.operators[addr]
+}
+
+// NewOrder signs an order with the operator wallet of its user address and places it. The
+// status, type and exchange address of the order default to NEW, LO and the exchange address
+// of the config
+func (s *OperatorService) NewOrder(operator common.Address, o *types.Order) (*types.Order, error) {
+	w, err := s.getOperatorWallet(operator, o.UserAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Status == "" {
+		o.Status = types.OrderStatusNew
+	}
+
+	if o.Type == "" {
+		o.Type = types.TypeLimitOrder
+	}
+
+	if (o.ExchangeAddress == common.Address{}) {
+		o.ExchangeAddress = common.HexToAddress(app.Config.Tomochain["exchange_address"])
+	}
+
+	o.Nonce, err = s.getOrderNonce(w.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	err = o.Sign(w)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	a := &types.OperatorAction{
+		Operator:      operator,
+		WalletAddress: w.Address,
+		Action:        types.OperatorActionNewOrder,
+		OrderHash:     o.Hash,
+		Hash:          o.Hash,
+		Nonce:         o.Nonce,
+	}
+
+	err = s.audit(a, func() error { return s.orderService.NewOrder(o) })
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// CancelOrder signs the cancel of an order of an operator wallet and sends it
+func (s *OperatorService) CancelOrder(operator common.Address, orderHash common.Hash) (*types.OrderCancel, error) {
+	o, err := s.orderService.GetByHash(orderHash)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if o == nil {
+		return nil, errors.New("No order with corresponding hash")
+	}
+
+	w, err := s.getOperatorWallet(operator, o.UserAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := s.getOrderNonce(w.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	oc := &types.OrderCancel{
+		OrderHash:       o.Hash,
+		Nonce:           nonce,
+		OrderID:         o.OrderID,
+		Status:          types.OrderStatusCancelled,
+		UserAddress:     o.UserAddress,
+		ExchangeAddress: o.ExchangeAddress,
+	}
+
+	err = oc.Sign(w)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	a := &types.OperatorAction{
+		Operator:      operator,
+		WalletAddress: w.Address,
+		Action:        types.OperatorActionCancelOrder,
+		OrderHash:     o.Hash,
+		Hash:          oc.Hash,
+		Nonce:         oc.Nonce,
+	}
+
+	err = s.audit(a, func() error { return s.orderService.CancelOrder(oc) })
+	if err != nil {
+		return nil, err
+	}
+
+	return oc, nil
+}
+
+// GetOperatorActions returns a page of the audit log, most recent first
+func (s *OperatorService) GetOperatorActions(spec types.OperatorActionSpec, offset int, size int) (*types.OperatorActionRes, error) {
+	return s.operatorActionDao.GetOperatorActions(spec, offset, size)
+}
+
+// getOperatorWallet returns the operator wallet of addr if operator is a trading operator. The
+// wallet signs with its private key or its signer
+func (s *OperatorService) getOperatorWallet(operator, addr common.Address) (*types.Wallet, error) {
+	if !s.IsOperator(operator) {
+		return nil, ErrNotOperator
+	}
+
+	w, err := s.walletDao.GetByAddress(addr)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if w == nil || !w.Operator || w.GetSigner() == nil {
+		return nil, ErrNoOperatorWallet
+	}
+
+	return w, nil
+}
+
+// getOrderNonce returns the next order nonce of an account on TomoX
+func (s *OperatorService) getOrderNonce(addr common.Address) (*big.Int, error) {
+	res, err := s.orderService.GetOrderNonceByUserAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseOrderNonce(res)
+}
+
+// audit records an action before sending it with send, so that no signed order or cancel is
+// sent without a record of who requested it. The reason send failed is added to the record
+func (s *OperatorService) audit(a *types.OperatorAction, send func() error) error {
+	a.ID = primitive.NewObjectID()
+	a.CreatedAt = time.Now()
+
+	err := s.operatorActionDao.Create(a)
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Operator %s: %s %s with wallet %s", a.Operator.Hex(), a.Action, a.OrderHash.Hex(), a.WalletAddress.Hex())
+
+	err = send()
+	if err != nil {
+		logger.Error(err)
+		a.Error = err.Error()
+
+		if err := s.operatorActionDao.SetError(a.ID, a.Error); err != nil {
+			logger.Error(err)
+		}
+
+		return err
+	}
+
+	return nil
+}
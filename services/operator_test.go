@@ -0,0 +1,206 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/signer"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
+)
+
+type operatorServiceMocks struct {
+	walletDao         *mocks.WalletDao
+	orderService      *mocks.OrderService
+	operatorActionDao *mocks.OperatorActionDao
+}
+
+func newTestOperatorService(operators ...common.Address) (*OperatorService, *operatorServiceMocks) {
+	m := &operatorServiceMocks{
+		walletDao:         new(mocks.WalletDao),
+		orderService:      new(mocks.OrderService),
+		operatorActionDao: new(mocks.OperatorActionDao),
+	}
+
+	s := NewOperatorService(m.walletDao, m.orderService, m.operatorActionDao, operators)
+	return s, m
+}
+
+func newTestOperatorWallet() *types.Wallet {
+	w := types.NewWallet()
+	w.Operator = true
+	return w
+}
+
+func newTestOperatorOrder(w *types.Wallet) *types.Order {
+	return &types.Order{
+		UserAddress:     w.Address,
+		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
+		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteToken:      common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+		Amount:          big.NewInt(1000),
+		PricePoint:      big.NewInt(100),
+		Side:            types.BUY,
+	}
+}
+
+func TestOperatorNewOrder(t *testing.T) {
+	operator := common.HexToAddress("0x1")
+	s, m := newTestOperatorService(operator)
+	w := newTestOperatorWallet()
+	o := newTestOperatorOrder(w)
+
+	var action *types.OperatorAction
+	m.walletDao.On("GetByAddress", w.Address).Return(w, nil)
+	m.orderService.On("GetOrderNonceByUserAddress", w.Address).Return("0x7", nil)
+	m.operatorActionDao.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		action = args.Get(0).(*types.OperatorAction)
+	}).Return(nil)
+	m.orderService.On("NewOrder", o).Return(nil)
+
+	res, err := s.NewOrder(operator, o)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), res.Nonce.Int64())
+	assert.Equal(t, types.OrderStatusNew, res.Status)
+	assert.Equal(t, types.TypeLimitOrder, res.Type)
+
+	ok, err := res.VerifySignature()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, operator, action.Operator)
+	assert.Equal(t, w.Address, action.WalletAddress)
+	assert.Equal(t, types.OperatorActionNewOrder, action.Action)
+	assert.Equal(t, res.Hash, action.OrderHash)
+	assert.Equal(t, int64(7), action.Nonce.Int64())
+
+	m.orderService.AssertExpectations(t)
+	m.operatorActionDao.AssertNotCalled(t, "SetError", mock.Anything, mock.Anything)
+}
+
+func TestOperatorNewOrderRejected(t *testing.T) {
+	operator := common.HexToAddress("0x1")
+	s, m := newTestOperatorService(operator)
+	w := newTestOperatorWallet()
+	o := newTestOperatorOrder(w)
+
+	var action *types.OperatorAction
+	m.walletDao.On("GetByAddress", w.Address).Return(w, nil)
+	m.orderService.On("GetOrderNonceByUserAddress", w.Address).Return("0x7", nil)
+	m.operatorActionDao.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		action = args.Get(0).(*types.OperatorAction)
+	}).Return(nil)
+	m.orderService.On("NewOrder", o).Return(errors.New("Insufficient balance"))
+	m.operatorActionDao.On("SetError", mock.Anything, "Insufficient balance").Return(nil)
+
+	_, err := s.NewOrder(operator, o)
+	assert.EqualError(t, err, "Insufficient balance")
+
+	// the rejected order is recorded with the reason it was rejected
+	m.operatorActionDao.AssertCalled(t, "SetError", action.ID, "Insufficient balance")
+}
+
+func TestOperatorNewOrderNotAudited(t *testing.T) {
+	operator := common.HexToAddress("0x1")
+	s, m := newTestOperatorService(operator)
+	w := newTestOperatorWallet()
+	o := newTestOperatorOrder(w)
+
+	m.walletDao.On("GetByAddress", w.Address).Return(w, nil)
+	m.orderService.On("GetOrderNonceByUserAddress", w.Address).Return("0x7", nil)
+	m.operatorActionDao.On("Create", mock.Anything).Return(errors.New("Database unavailable"))
+
+	// an order that could not be recorded is not sent
+	_, err := s.NewOrder(operator, o)
+	assert.Error(t, err)
+	m.orderService.AssertNotCalled(t, "NewOrder", mock.Anything)
+}
+
+func TestOperatorNewOrderUnauthorized(t *testing.T) {
+	operator := common.HexToAddress("0x1")
+	s, m := newTestOperatorService(operator)
+	w := newTestOperatorWallet()
+
+	_, err := s.NewOrder(common.HexToAddress("0x2"), newTestOperatorOrder(w))
+	assert.Equal(t, ErrNotOperator, err)
+
+	// a stored wallet which is not an operator wallet can not be traded with
+	user := types.NewWallet()
+	m.walletDao.On("GetByAddress", user.Address).Return(user, nil)
+
+	_, err = s.NewOrder(operator, newTestOperatorOrder(user))
+	assert.Equal(t, ErrNoOperatorWallet, err)
+
+	m.operatorActionDao.AssertNotCalled(t, "Create", mock.Anything)
+	m.orderService.AssertNotCalled(t, "NewOrder", mock.Anything)
+}
+
+func TestOperatorCancelOrder(t *testing.T) {
+	operator := common.HexToAddress("0x1")
+	s, m := newTestOperatorService(operator)
+	w := newTestOperatorWallet()
+
+	o := newTestOperatorOrder(w)
+	o.Status = types.OrderStatusNew
+	o.Type = types.TypeLimitOrder
+	o.Nonce = big.NewInt(7)
+	o.OrderID = 12
+	assert.NoError(t, o.Sign(w))
+
+	var action *types.OperatorAction
+	var cancel *types.OrderCancel
+	m.orderService.On("GetByHash", o.Hash).Return(o, nil)
+	m.walletDao.On("GetByAddress", w.Address).Return(w, nil)
+	m.orderService.On("GetOrderNonceByUserAddress", w.Address).Return("0x8", nil)
+	m.operatorActionDao.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		action = args.Get(0).(*types.OperatorAction)
+	}).Return(nil)
+	m.orderService.On("CancelOrder", mock.Anything).Run(func(args mock.Arguments) {
+		cancel = args.Get(0).(*types.OrderCancel)
+	}).Return(nil)
+
+	oc, err := s.CancelOrder(operator, o.Hash)
+	assert.NoError(t, err)
+	assert.Equal(t, oc, cancel)
+	assert.Equal(t, o.Hash, oc.OrderHash)
+	assert.Equal(t, uint64(12), oc.OrderID)
+	assert.Equal(t, int64(8), oc.Nonce.Int64())
+	assert.Equal(t, oc.ComputeHash(), oc.Hash)
+
+	assert.Equal(t, types.OperatorActionCancelOrder, action.Action)
+	assert.Equal(t, o.Hash, action.OrderHash)
+	assert.Equal(t, oc.Hash, action.Hash)
+}
+
+func TestOperatorNewOrderWithSigner(t *testing.T) {
+	operator := common.HexToAddress("0x1")
+	s, m := newTestOperatorService(operator)
+
+	// the key of the wallet is held by a signer rather than the server
+	key, _ := signer.NewRandomMemorySigner()
+	w := &types.Wallet{Address: key.Address(), Signer: key, Operator: true}
+	o := newTestOperatorOrder(w)
+
+	m.walletDao.On("GetByAddress", w.Address).Return(w, nil)
+	m.orderService.On("GetOrderNonceByUserAddress", w.Address).Return("0x7", nil)
+	m.operatorActionDao.On("Create", mock.Anything).Return(nil)
+	m.orderService.On("NewOrder", o).Return(nil)
+
+	res, err := s.NewOrder(operator, o)
+	assert.NoError(t, err)
+
+	ok, err := res.VerifySignature()
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// a wallet that can not sign is not an operator wallet
+	unsigned := &types.Wallet{Address: common.HexToAddress("0x3"), Operator: true}
+	m.walletDao.On("GetByAddress", unsigned.Address).Return(unsigned, nil)
+
+	_, err = s.NewOrder(operator, newTestOperatorOrder(unsigned))
+	assert.Equal(t, ErrNoOperatorWallet, err)
+}
@@ -5,17 +5,22 @@ import (
 	"fmt"
 	"log"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/rabbitmq"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
+	"github.com/tomochain/tomox-sdk/utils/math"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // OrderService
@@ -26,6 +31,8 @@ type OrderService struct {
 	accountDao        interfaces.AccountDao
 	tradeDao          interfaces.TradeDao
 	notificationDao   interfaces.NotificationDao
+	orderExpiryDao    interfaces.OrderExpiryDao
+	clientOrderDao    interfaces.ClientOrderDao
 	engine            interfaces.Engine
 	validator         interfaces.ValidatorService
 	broker            *rabbitmq.Connection
@@ -34,8 +41,16 @@ type OrderService struct {
 	orderPending      []*types.Order
 	isFinishCache     bool
 	bulkOrders        map[*types.PairAddresses]map[common.Hash]*types.Order
+	orderWatchers     map[common.Hash]chan *types.EngineResponse
+	watchersMutex     sync.Mutex
+	nonces            *NonceManager
+	shareResponses    bool
+	heldNonces        []func(addr common.Address) (map[uint64]common.Hash, error)
 }
 
+// replaceOrderTimeout is the time allowed to the engine to confirm both halves of an order replacement
+var replaceOrderTimeout = 30 * time.Second
+
 type amountByTime struct {
 	filledAmount *big.Int
 	amount       *big.Int
@@ -49,19 +64,23 @@ func NewOrderService(
 	accountDao interfaces.AccountDao,
 	tradeDao interfaces.TradeDao,
 	notificationDao interfaces.NotificationDao,
+	orderExpiryDao interfaces.OrderExpiryDao,
+	clientOrderDao interfaces.ClientOrderDao,
 	engine interfaces.Engine,
 	validator interfaces.ValidatorService,
 	broker *rabbitmq.Connection,
 ) *OrderService {
 	bulkOrders := make(map[*types.PairAddresses]map[common.Hash]*types.Order)
 	orderByPricepoint := make(map[string]map[common.Hash]*amountByTime)
-	return &OrderService{
+	s := &OrderService{
 		orderDao,
 		tokenDao,
 		pairDao,
 		accountDao,
 		tradeDao,
 		notificationDao,
+		orderExpiryDao,
+		clientOrderDao,
 		engine,
 		validator,
 		broker,
@@ -70,7 +89,20 @@ func NewOrderService(
 		[]*types.Order{},
 		false,
 		bulkOrders,
+		make(map[common.Hash]chan *types.EngineResponse),
+		sync.Mutex{},
+		nil,
+		false,
+		nil,
+	}
+
+	// the simulated engine does not check the nonces
+	if app.Config.Engine != app.EngineSimulated {
+		s.nonces = NewNonceManager(s.getNodeOrderNonce)
+		s.nonces.LoadHeldNonces(s.getHeldNonces)
 	}
+
+	return s
 }
 
 func (s *OrderService) getOrderPricepointKey(baseToken, quoteToken common.Address, pricepoint *big.Int, side string) string {
@@ -168,7 +200,7 @@ func (s *OrderService) GetOrderCountByUserAddress(addr common.Address) (int, err
 }
 
 // GetByID fetches the details of an order using order's mongo ID
-func (s *OrderService) GetByID(id bson.ObjectId) (*types.Order, error) {
+func (s *OrderService) GetByID(id primitive.ObjectID) (*types.Order, error) {
 	return s.orderDao.GetByID(id)
 }
 
@@ -214,120 +246,777 @@ func (s *OrderService) GetHistoryByUserAddress(addr, bt, qt common.Address, from
 // If valid: Order is inserted in DB with order status as new and order is publiched
 // on rabbitmq queue for matching engine to process the order
 func (s *OrderService) NewOrder(o *types.Order) error {
+	err := s.verifyNewOrder(o)
+	if err != nil {
+		return err
+	}
+
+	// a retried submission of a published order is accepted as the first one was, whatever
+	// the state of the orderbook is now
+	c, published, err := s.reserveClientOrderID(o)
+	if err != nil {
+		return err
+	}
+
+	if published {
+		return nil
+	}
+
+	err = s.processNewOrder(o, make(map[string]*types.Pair))
+	if err != nil {
+		s.releaseClientOrderID(c)
+		return err
+	}
+
+	err = s.reserveOrderNonces(o)
+	if err != nil {
+		s.releaseClientOrderID(c)
+		return err
+	}
+
+	if o.Type == types.TypeLimitOrder {
+		err = s.validator.ValidateAvailableBalance(o)
+		if err != nil {
+			logger.Error(err)
+			s.releaseOrderNonces(o)
+			s.releaseClientOrderID(c)
+			return err
+		}
+	}
+
+	err = s.publishNewOrder(o)
+	if err != nil {
+		logger.Error(err)
+		s.releaseOrderNonces(o)
+		s.releaseClientOrderID(c)
+		return err
+	}
+
+	s.acceptClientOrderID(c)
+	return nil
+}
+
+// reserveNonce reserves the nonce of an order or a cancel until the node counts it
+func (s *OrderService) reserveNonce(addr common.Address, nonce *big.Int, h common.Hash) error {
+	if s.nonces == nil {
+		return nil
+	}
+
+	return s.nonces.Reserve(addr, nonce, h)
+}
+
+// releaseNonce frees the nonce of an order or a cancel which was not sent or was rejected
+func (s *OrderService) releaseNonce(addr common.Address, nonce *big.Int, h common.Hash) {
+	if s.nonces == nil {
+		return
+	}
+
+	s.nonces.Release(addr, nonce, h)
+}
+
+// reserveOrderNonces reserves the nonce of an order, and holds the following nonce for the
+// cancel enforcing its time in force, so that no other order takes it before the cancel is sent
+func (s *OrderService) reserveOrderNonces(o *types.Order) error {
+	err := s.reserveNonce(o.UserAddress, o.Nonce, o.Hash)
+	if err != nil {
+		return err
+	}
+
+	if s.nonces == nil || !o.HasTimeInForceCancel() {
+		return nil
+	}
+
+	oc := o.TimeInForceCancel()
+	err = s.nonces.Hold(o.UserAddress, oc.Nonce, oc.Hash)
+	if err != nil {
+		s.releaseNonce(o.UserAddress, o.Nonce, o.Hash)
+		return err
+	}
+
+	return nil
+}
+
+// releaseOrderNonces frees the nonces reserved by reserveOrderNonces for an order which was
+// not sent
+func (s *OrderService) releaseOrderNonces(o *types.Order) {
+	s.releaseNonce(o.UserAddress, o.Nonce, o.Hash)
+
+	if o.HasTimeInForceCancel() {
+		oc := o.TimeInForceCancel()
+		s.releaseNonce(o.UserAddress, oc.Nonce, oc.Hash)
+	}
+}
+
+// HoldOrderNonce holds a nonce of an address for the order with hash h, which is signed in
+// advance and sent later, like the order of a stop order
+func (s *OrderService) HoldOrderNonce(addr common.Address, nonce *big.Int, h common.Hash) error {
+	if s.nonces == nil {
+		return nil
+	}
+
+	return s.nonces.Hold(addr, nonce, h)
+}
+
+// ReleaseOrderNonce frees a nonce held by HoldOrderNonce for an order which will not be sent
+func (s *OrderService) ReleaseOrderNonce(addr common.Address, nonce *big.Int, h common.Hash) {
+	if s.nonces == nil {
+		return
+	}
+
+	s.nonces.Unhold(addr, nonce, h)
+}
+
+// reserveClientOrderID reserves the client order ID of an order for the order, if it has one.
+// It tells whether the order was already published by a submission with the same ID, and
+// fails if the ID is used by another order or the other submission is not done yet
+func (s *OrderService) reserveClientOrderID(o *types.Order) (*types.ClientOrder, bool, error) {
+	if o.ClientOrderID == "" {
+		return nil, false, nil
+	}
+
+	c := types.NewClientOrder(o)
+	existing, err := s.clientOrderDao.Reserve(c)
+	if err != nil {
+		logger.Error(err)
+		return nil, false, err
+	}
+
+	if existing == nil {
+		return c, false, nil
+	}
+
+	if existing.OrderHash != o.Hash {
+		return nil, false, errors.ClientOrderIDInUse(o.ClientOrderID)
+	}
+
+	if existing.Status != types.ClientOrderStatusAccepted {
+		return nil, false, errors.ClientOrderPending(o.ClientOrderID)
+	}
+
+	return nil, true, nil
+}
+
+// releaseClientOrderID frees the client order ID of an order which was not published, so
+// that its submission can be retried
+func (s *OrderService) releaseClientOrderID(c *types.ClientOrder) {
+	if c == nil {
+		return
+	}
+
+	err := s.clientOrderDao.Release(c)
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+func (s *OrderService) acceptClientOrderID(c *types.ClientOrder) {
+	if c == nil {
+		return
+	}
+
+	err := s.clientOrderDao.Accept(c)
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+// publishNewOrder sends an order to the engine. The expiry of an order with a time in force
+// is stored first, so that it is known by any replica when the engine responses arrive
+func (s *OrderService) publishNewOrder(o *types.Order) error {
+	if o.HasTimeInForceCancel() {
+		err := s.orderExpiryDao.Create(types.NewOrderExpiry(o))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := s.broker.PublishNewOrderMessage(o)
+	if err != nil {
+		if o.HasTimeInForceCancel() {
+			s.closeOrderExpiry(o.Hash, types.OrderExpiryStatusFailed)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// NewOrders validates and publishes a batch of orders and returns a result for each of them.
+// Pairs are fetched once per pair, and the balances of the limit orders are validated once per
+// user and sell token for the whole batch
+func (s *OrderService) NewOrders(orders []*types.Order) ([]*types.OrderBatchResult, error) {
+	results := make([]*types.OrderBatchResult, len(orders))
+	clientOrders := make([]*types.ClientOrder, len(orders))
+	pairs := make(map[string]*types.Pair)
+	seen := make(map[common.Hash]bool)
+	limitOrders := []*types.Order{}
+
+	for i, o := range orders {
+		if o == nil {
+			results[i] = &types.OrderBatchResult{Error: "Invalid payload"}
+			continue
+		}
+
+		results[i] = &types.OrderBatchResult{Hash: o.Hash}
+
+		err := s.verifyNewOrder(o)
+		if err != nil {
+			results[i].Error = err.Error()
+			if apiErr, ok := err.(*errors.APIError); ok {
+				results[i].Code = apiErr.ErrorCode
+			}
+			continue
+		}
+
+		results[i].Hash = o.Hash
+		if seen[o.Hash] {
+			results[i].Error = "Duplicate order"
+			continue
+		}
+
+		seen[o.Hash] = true
+
+		c, published, err := s.reserveClientOrderID(o)
+		if err != nil {
+			results[i].Error = err.Error()
+			if apiErr, ok := err.(*errors.APIError); ok {
+				results[i].Code = apiErr.ErrorCode
+			}
+			continue
+		}
+
+		if published {
+			results[i].Accepted = true
+			continue
+		}
+
+		err = s.processNewOrder(o, pairs)
+		if err != nil {
+			results[i].Error = err.Error()
+			if apiErr, ok := err.(*errors.APIError); ok {
+				results[i].Code = apiErr.ErrorCode
+			}
+			s.releaseClientOrderID(c)
+			continue
+		}
+
+		err = s.reserveOrderNonces(o)
+		if err != nil {
+			results[i].Error = err.Error()
+			if apiErr, ok := err.(*errors.APIError); ok {
+				results[i].Code = apiErr.ErrorCode
+			}
+			s.releaseClientOrderID(c)
+			continue
+		}
+
+		clientOrders[i] = c
+		if o.Type == types.TypeLimitOrder {
+			limitOrders = append(limitOrders, o)
+		}
+	}
+
+	errs, err := s.validator.ValidateAvailableBalances(limitOrders)
+	if err != nil {
+		logger.Error(err)
+		for i, c := range clientOrders {
+			if results[i].Error == "" && !results[i].Accepted {
+				s.releaseOrderNonces(orders[i])
+			}
+			s.releaseClientOrderID(c)
+		}
+
+		return nil, err
+	}
+
+	for i, o := range orders {
+		if results[i].Error != "" || results[i].Accepted {
+			continue
+		}
+
+		if err, ok := errs[o.Hash]; ok {
+			results[i].Error = err.Error()
+			s.releaseOrderNonces(o)
+			s.releaseClientOrderID(clientOrders[i])
+			continue
+		}
+
+		err := s.publishNewOrder(o)
+		if err != nil {
+			logger.Error(err)
+			results[i].Error = err.Error()
+			s.releaseOrderNonces(o)
+			s.releaseClientOrderID(clientOrders[i])
+			continue
+		}
+
+		s.acceptClientOrderID(clientOrders[i])
+		results[i].Accepted = true
+	}
+
+	return results, nil
+}
+
+// verifyNewOrder checks an order and its signature
+func (s *OrderService) verifyNewOrder(o *types.Order) error {
 	if err := o.Validate(); err != nil {
 		logger.Error(err)
 		return err
 	}
 
-	ok, err := o.VerifySignature()
+	ok, err := o.VerifySignature()
+	if err != nil {
+		logger.Error(err)
+	}
+
+	if !ok {
+		return errors.New("Invalid Signature")
+	}
+
+	return nil
+}
+
+// processNewOrder fills the token and pair data of a verified order, and checks it against
+// the orderbook. Pairs are looked up in the given map first, and added to it once fetched
+func (s *OrderService) processNewOrder(o *types.Order, pairs map[string]*types.Pair) error {
+	var err error
+
+	code, _ := o.PairCode()
+	p, cached := pairs[code]
+	if !cached {
+		p, err = s.pairDao.GetByTokenAddress(o.BaseToken, o.QuoteToken)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+
+		pairs[code] = p
+	}
+
+	if p == nil {
+		return errors.New("Pair not found")
+	}
+
+	/*
+		if math.IsStrictlySmallerThan(o.QuoteAmount(p), p.MinQuoteAmount()) {
+			return errors.New("Order amount too low")
+		}
+	*/
+
+	// Fill token and pair data
+	err = o.Process(p)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if o.TimeInForce == types.TimeInForceFOK {
+		err = s.validateFillOrKill(o, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.PostOnly {
+		err = s.validatePostOnly(o, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validatePostOnly rejects a post-only order whose price crosses the best price of the
+// opposite side of the orderbook, since it would be matched as a taker
+func (s *OrderService) validatePostOnly(o *types.Order, p *types.Pair) error {
+	side, srt := types.SELL, 1
+	if o.Side == types.SELL {
+		side, srt = types.BUY, -1
+	}
+
+	levels, err := s.orderDao.GetSideOrderBook(p, side, srt)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if len(levels) == 0 {
+		return nil
+	}
+
+	best := math.ToBigInt(levels[0]["pricepoint"])
+	if (o.Side == types.BUY && best.Cmp(o.PricePoint) <= 0) || (o.Side == types.SELL && best.Cmp(o.PricePoint) >= 0) {
+		return errors.OrderWouldCross(o.Side, o.PricePoint.String(), best.String())
+	}
+
+	return nil
+}
+
+// validateFillOrKill checks that the orderbook holds enough liquidity at the order price or
+// better to fill a FOK order completely. Orders that would rest in the orderbook even for the
+// short time it takes to cancel them are rejected before reaching the engine
+func (s *OrderService) validateFillOrKill(o *types.Order, p *types.Pair) error {
+	side, srt := types.SELL, 1
+	if o.Side == types.SELL {
+		side, srt = types.BUY, -1
+	}
+
+	levels, err := s.orderDao.GetSideOrderBook(p, side, srt)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	available := big.NewInt(0)
+	for _, level := range levels {
+		pricepoint := math.ToBigInt(level["pricepoint"])
+		if (o.Side == types.BUY && pricepoint.Cmp(o.PricePoint) > 0) || (o.Side == types.SELL && pricepoint.Cmp(o.PricePoint) < 0) {
+			continue
+		}
+
+		available = math.Add(available, math.ToBigInt(level["amount"]))
+	}
+
+	if available.Cmp(o.Amount) < 0 {
+		return errors.New("FOK order cannot be filled completely")
+	}
+
+	return nil
+}
+
+// CancelOrder handles the cancellation order requests.
+// Only Orders which are OPEN or NEW i.e. Not yet filled/partially filled
+// can be cancelled
+func (s *OrderService) CancelOrder(oc *types.OrderCancel) error {
+	var err error
+	var o *types.Order
+
+	o, err = s.orderDao.GetByHash(oc.OrderHash)
+	if err != nil || o == nil {
+		return errors.New("No order with corresponding hash")
+	}
+	if o.Status == types.ORDER_FILLED || o.Status == types.ERROR_STATUS || o.Status == types.ORDER_CANCELLED {
+		return fmt.Errorf("Cannot cancel order. Status is %v", o.Status)
+	}
+
+	o.Nonce = oc.Nonce
+	o.Signature = oc.Signature
+	o.OrderID = oc.OrderID
+	o.Status = oc.Status
+	o.UserAddress = oc.UserAddress
+	o.ExchangeAddress = oc.ExchangeAddress
+
+	err = s.reserveNonce(oc.UserAddress, oc.Nonce, oc.Hash)
+	if err != nil {
+		return err
+	}
+
+	err = s.broker.PublishCancelOrderMessage(o)
+	if err != nil {
+		logger.Error(err)
+		s.releaseNonce(oc.UserAddress, oc.Nonce, oc.Hash)
+		return err
+	}
+
+	return nil
+}
+
+// CancelOrders publishes a batch of order cancels and returns a result for each of them
+func (s *OrderService) CancelOrders(ocs []*types.OrderCancel) []*types.OrderBatchResult {
+	results := make([]*types.OrderBatchResult, len(ocs))
+
+	for i, oc := range ocs {
+		if oc == nil {
+			results[i] = &types.OrderBatchResult{Error: "Invalid payload"}
+			continue
+		}
+
+		results[i] = &types.OrderBatchResult{Hash: oc.OrderHash}
+
+		err := s.CancelOrder(oc)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		results[i].Accepted = true
+	}
+
+	return results
+}
+
+// CancelOrder handles the cancellation order requests.
+// Only Orders which are OPEN or NEW i.e. Not yet filled/partially filled
+// can be cancelled
+func (s *OrderService) CancelAllOrder(a common.Address) error {
+	orders, err := s.orderDao.GetOpenOrdersByUserAddress(a)
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if len(orders) == 0 {
+		return nil
+	}
+
+	for _, o := range orders {
+		err = s.broker.PublishCancelOrderMessage(o)
+
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+	}
+
+	return nil
+}
+
+// ReplaceOrder cancels an order and places a new order in its place as a single operation.
+// The new order is checked and its nonce and client order ID are reserved before the cancel is
+// sent, so that a replacement that cannot succeed leaves the orderbook unchanged. The new order
+// is only sent once the engine has confirmed the cancellation. The engine can still reject it,
+// and the original order is not restored then: the replacement fails with REPLACE_ORDER_FAILED,
+// returned with the result of the cancellation. The engine responses are awaited until ctx is
+// done, for replaceOrderTimeout at most
+func (s *OrderService) ReplaceOrder(ctx context.Context, r *types.OrderReplace) (*types.OrderReplaceResult, error) {
+	if err := r.Validate(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	o, err := s.orderDao.GetByHash(r.Cancel.OrderHash)
+	if err != nil || o == nil {
+		return nil, errors.New("No order with corresponding hash")
+	}
+
+	if o.Status != types.OrderStatusOpen && o.Status != types.OrderStatusPartialFilled {
+		return nil, fmt.Errorf("Cannot replace order. Status is %v", o.Status)
+	}
+
+	ok, err := r.Cancel.VerifySignature(o)
+	if err != nil || !ok {
+		return nil, errors.New("Invalid cancel signature")
+	}
+
+	if r.Order.UserAddress != o.UserAddress {
+		return nil, errors.New("Replacing order should belong to the owner of the replaced order")
+	}
+
+	if r.Order.BaseToken != o.BaseToken || r.Order.QuoteToken != o.QuoteToken {
+		return nil, errors.New("Replacing order should be on the pair of the replaced order")
+	}
+
+	err = s.verifyNewOrder(r.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.processNewOrder(r.Order, make(map[string]*types.Pair))
+	if err != nil {
+		return nil, err
+	}
+
+	c, published, err := s.reserveClientOrderID(r.Order)
 	if err != nil {
-		logger.Error(err)
+		return nil, err
 	}
 
-	if !ok {
-		return errors.New("Invalid Signature")
+	if published {
+		return nil, errors.ClientOrderIDInUse(r.Order.ClientOrderID)
 	}
 
-	p, err := s.pairDao.GetByTokenAddress(o.BaseToken, o.QuoteToken)
+	err = s.reserveNonce(o.UserAddress, r.Cancel.Nonce, r.Cancel.Hash)
 	if err != nil {
-		logger.Error(err)
-		return err
+		s.releaseClientOrderID(c)
+		return nil, err
 	}
 
-	if p == nil {
-		return errors.New("Pair not found")
+	err = s.reserveOrderNonces(r.Order)
+	if err != nil {
+		s.releaseNonce(o.UserAddress, r.Cancel.Nonce, r.Cancel.Hash)
+		s.releaseClientOrderID(c)
+		return nil, err
 	}
 
-	/*
-		if math.IsStrictlySmallerThan(o.QuoteAmount(p), p.MinQuoteAmount()) {
-			return errors.New("Order amount too low")
+	if r.Order.Type == types.TypeLimitOrder {
+		err = s.validator.ValidateReplacingBalance(r.Order, o)
+		if err != nil {
+			logger.Error(err)
+			s.releaseOrderNonces(r.Order)
+			s.releaseNonce(o.UserAddress, r.Cancel.Nonce, r.Cancel.Hash)
+			s.releaseClientOrderID(c)
+			return nil, err
 		}
-	*/
+	}
+
+	ctx, stop := context.WithTimeout(ctx, replaceOrderTimeout)
+	defer stop()
+
+	cancel := *o
+	cancel.Nonce = r.Cancel.Nonce
+	cancel.Signature = r.Cancel.Signature
+	cancel.OrderID = r.Cancel.OrderID
+	cancel.Status = r.Cancel.Status
+	cancel.ExchangeAddress = r.Cancel.ExchangeAddress
+
+	res, err := s.submitAndWait(ctx, o.Hash, func() error {
+		return s.broker.PublishCancelOrderMessage(&cancel)
+	}, func(res *types.EngineResponse) bool {
+		return res.Status != types.ORDER_ADDED && res.Status != types.ORDER_PARTIALLY_FILLED
+	})
+
+	if err == nil && res.Status != types.ORDER_CANCELLED {
+		err = fmt.Errorf("Cannot replace order. Order was not cancelled: %v", res.Status)
+	}
 
-	// Fill token and pair data
-	err = o.Process(p)
 	if err != nil {
 		logger.Error(err)
-		return err
+		s.releaseOrderNonces(r.Order)
+		s.releaseNonce(o.UserAddress, r.Cancel.Nonce, r.Cancel.Hash)
+		s.releaseClientOrderID(c)
+		return nil, err
 	}
-	if o.Type == types.TypeLimitOrder {
-		err = s.validator.ValidateAvailableBalance(o)
+
+	cancelled := res.Order
+
+	res, err = s.submitAndWait(ctx, r.Order.Hash, func() error {
+		err := s.publishNewOrder(r.Order)
 		if err != nil {
-			logger.Error(err)
+			s.releaseOrderNonces(r.Order)
+			s.releaseClientOrderID(c)
 			return err
 		}
+
+		s.acceptClientOrderID(c)
+		return nil
+	}, func(res *types.EngineResponse) bool {
+		return true
+	})
+
+	if err == nil && (res.Status == types.ORDER_REJECTED || res.Status == types.ERROR_STATUS) {
+		err = fmt.Errorf("Order was rejected: %v", res.Status)
+	}
+
+	result := &types.OrderReplaceResult{CancelledOrder: cancelled}
+	if res != nil {
+		result.Order = res.Order
 	}
 
-	err = s.broker.PublishNewOrderMessage(o)
 	if err != nil {
 		logger.Error(err)
-		return err
+		apiErr := errors.ReplaceOrderFailed(o.Hash.Hex(), r.Order.Hash.Hex(), err.Error())
+		apiErr.Details = result
+		return result, apiErr
 	}
 
-	return nil
+	ws.SendOrderMessage(types.ORDER_REPLACED, o.UserAddress, result)
+
+	return result, nil
 }
 
-// CancelOrder handles the cancellation order requests.
-// Only Orders which are OPEN or NEW i.e. Not yet filled/partially filled
-// can be cancelled
-func (s *OrderService) CancelOrder(oc *types.OrderCancel) error {
-	var err error
-	var o *types.Order
+// submitAndWait publishes a message to the engine and waits for the first engine response
+// about the order with the given hash for which done returns true, until ctx is done
+func (s *OrderService) submitAndWait(ctx context.Context, h common.Hash, publish func() error, done func(*types.EngineResponse) bool) (*types.EngineResponse, error) {
+	ch := make(chan *types.EngineResponse, 8)
 
-	o, err = s.orderDao.GetByHash(oc.OrderHash)
-	if err != nil || o == nil {
-		return errors.New("No order with corresponding hash")
-	}
-	if o.Status == types.ORDER_FILLED || o.Status == types.ERROR_STATUS || o.Status == types.ORDER_CANCELLED {
-		return fmt.Errorf("Cannot cancel order. Status is %v", o.Status)
+	s.watchersMutex.Lock()
+	s.orderWatchers[h] = ch
+	s.watchersMutex.Unlock()
+
+	defer func() {
+		s.watchersMutex.Lock()
+		delete(s.orderWatchers, h)
+		s.watchersMutex.Unlock()
+	}()
+
+	err := publish()
+	if err != nil {
+		return nil, err
 	}
 
-	o.Nonce = oc.Nonce
-	o.Signature = oc.Signature
-	o.OrderID = oc.OrderID
-	o.Status = oc.Status
-	o.UserAddress = oc.UserAddress
-	o.ExchangeAddress = oc.ExchangeAddress
+	for {
+		select {
+		case res := <-ch:
+			if done(res) {
+				return res, nil
+			}
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, fmt.Errorf("Timed out waiting for order %v", h.Hex())
+			}
 
-	err = s.broker.PublishCancelOrderMessage(o)
+			return nil, fmt.Errorf("Stopped waiting for order %v: %v", h.Hex(), ctx.Err())
+		}
+	}
+}
+
+// ShareEngineResponses makes the replica share the engine responses it handles with all the
+// replicas of the cluster, and get the ones handled by the others. An order replacement waits
+// for the responses about its orders on the replica it was requested from, and the nonces are
+// tracked by the replica the order was sent from, while the response queues are consumed by
+// any replica
+func (s *OrderService) ShareEngineResponses() error {
+	err := s.broker.SubscribeSharedEngineResponses(s.handleSharedEngineResponse)
 	if err != nil {
 		logger.Error(err)
 		return err
 	}
 
+	s.shareResponses = true
 	return nil
 }
 
-// CancelOrder handles the cancellation order requests.
-// Only Orders which are OPEN or NEW i.e. Not yet filled/partially filled
-// can be cancelled
-func (s *OrderService) CancelAllOrder(a common.Address) error {
-	orders, err := s.orderDao.GetOpenOrdersByUserAddress(a)
+// watchEngineResponse handles the part of an engine response that concerns every replica,
+// through all the replicas when the responses are shared
+func (s *OrderService) watchEngineResponse(res *types.EngineResponse) {
+	if !s.shareResponses || res.Order == nil {
+		s.handleSharedEngineResponse(res)
+		return
+	}
 
+	err := s.broker.ShareEngineResponse(res)
 	if err != nil {
 		logger.Error(err)
-		return err
+		s.handleSharedEngineResponse(res)
 	}
+}
 
-	if len(orders) == 0 {
-		return nil
+// handleSharedEngineResponse records the nonces of the order of an engine response and
+// forwards it to the operation waiting for it, if any
+func (s *OrderService) handleSharedEngineResponse(res *types.EngineResponse) {
+	s.handleOrderNonce(res)
+	s.notifyOrderWatcher(res)
+}
+
+// notifyOrderWatcher forwards an engine response to the operation waiting for it, if any
+func (s *OrderService) notifyOrderWatcher(res *types.EngineResponse) {
+	if res.Order == nil {
+		return
 	}
 
-	for _, o := range orders {
-		err = s.broker.PublishCancelOrderMessage(o)
+	s.watchersMutex.Lock()
+	defer s.watchersMutex.Unlock()
 
-		if err != nil {
-			logger.Error(err)
-			continue
-		}
+	ch, ok := s.orderWatchers[res.Order.Hash]
+	if !ok {
+		return
 	}
 
-	return nil
+	select {
+	case ch <- res:
+	default:
+	}
 }
 
 // HandleEngineResponse listens to messages incoming from the engine and handles websocket
 // responses and database updates accordingly
 func (s *OrderService) HandleEngineResponse(res *types.EngineResponse) error {
+	s.watchEngineResponse(res)
+	s.handleOrderExpiry(res)
+
 	switch res.Status {
 	case types.ORDER_ADDED:
 		s.handleEngineOrderAdded(res)
@@ -512,7 +1201,7 @@ func (s *OrderService) broadcastOrderBookUpdate(orders []*types.Order) {
 	}
 
 	id := utils.GetOrderBookChannelID(p.BaseTokenAddress, p.QuoteTokenAddress)
-	ws.GetOrderBookSocket().BroadcastMessage(id, &types.OrderBook{
+	ws.GetOrderBookSocket().BroadcastUpdate(id, &types.OrderBook{
 		PairName: orders[0].PairName,
 		Bids:     bids,
 		Asks:     asks,
@@ -527,63 +1216,35 @@ func (s *OrderService) broadcastRawOrderBookUpdate(orders []*types.Order) {
 	}
 
 	id := utils.GetOrderBookChannelID(p.BaseTokenAddress, p.QuoteTokenAddress)
-	ws.GetRawOrderBookSocket().BroadcastMessage(id, orders)
+	ws.GetRawOrderBookSocket().BroadcastUpdate(id, &types.RawOrderBook{
+		PairName: orders[0].PairName,
+		Orders:   orders,
+	})
 }
 
-func (s *OrderService) WatchChanges() {
-	ct, sc, err := s.orderDao.Watch()
-
-	if err != nil {
-		logger.Error("Failed to open change stream")
-		return //exiting func
-	}
-
-	defer ct.Close()
-	defer sc.Close()
-
-	// Watch the event again in case there is error and function returned
-	defer s.WatchChanges()
+// WatchChanges handles the changes of the orders stream, and broadcasts the orderbook updates
+func (s *OrderService) WatchChanges(streams interfaces.ChangeStreamService) {
+	go s.BroadcastBulkOrders()
 
-	ctx := context.Background()
-	go func() {
-		for {
-			<-time.After(500 * time.Millisecond)
-			s.processBulkOrders()
+	streams.Watch("orders", s.orderDao.Watch, func(raw bson.Raw) {
+		ev := types.OrderChangeEvent{}
+		err := bson.Unmarshal(raw, &ev)
+		if err != nil {
+			logger.Error(err)
+			return
 		}
-	}()
 
-	//Handling change stream in a cycle
-	for {
-		select {
-		case <-ctx.Done(): // if parent context was cancelled
-			err := ct.Close() // close the stream
-			if err != nil {
-				logger.Error("Change stream closed")
-			}
-			return //exiting from the func
-		default:
-			ev := types.OrderChangeEvent{}
-
-			//getting next item from the steam
-			ok := ct.Next(&ev)
-
-			//if data from the stream wasn't un-marshaled, we get ok == false as a result
-			//so we need to call Err() method to get info why
-			//it'll be nil if we just have no data
-			if !ok {
-				err := ct.Err()
-				if err != nil {
-					logger.Error(err)
-					return
-				}
-			}
+		logger.Debugf("Operation Type: %s", ev.OperationType)
+		s.HandleDocumentType(ev)
+	})
+}
 
-			//if item from the stream un-marshaled successfully, do something with it
-			if ok {
-				logger.Debugf("Operation Type: %s", ev.OperationType)
-				s.HandleDocumentType(ev)
-			}
-		}
+// BroadcastBulkOrders broadcasts every 500ms the orderbook updates of the orders received since
+// the last broadcast
+func (s *OrderService) BroadcastBulkOrders() {
+	for {
+		<-time.After(500 * time.Millisecond)
+		s.processBulkOrders()
 	}
 }
 
@@ -599,7 +1260,9 @@ func (s *OrderService) processBulkOrders() {
 		}
 
 		var pairName string
+		rawOrders := []*types.Order{}
 		for _, o := range orders {
+			rawOrders = append(rawOrders, o)
 			pp := o.PricePoint
 			side := o.Side
 			pairName = o.PairName
@@ -629,11 +1292,12 @@ func (s *OrderService) processBulkOrders() {
 		}
 
 		id := utils.GetOrderBookChannelID(p.BaseToken, p.QuoteToken)
-		ws.GetOrderBookSocket().BroadcastMessage(id, &types.OrderBook{
+		ws.GetOrderBookSocket().BroadcastUpdate(id, &types.OrderBook{
 			PairName: pairName,
 			Bids:     bids,
 			Asks:     asks,
 		})
+		s.broadcastRawOrderBookUpdate(rawOrders)
 	}
 	s.bulkOrders = make(map[*types.PairAddresses]map[common.Hash]*types.Order)
 }
@@ -670,7 +1334,258 @@ func (s *OrderService) HandleDocumentType(ev types.OrderChangeEvent) error {
 	return nil
 }
 
-// GetOrderNonceByUserAddress return nonce of user order
+// GetOrderNonceByUserAddress returns the next nonce of the orders of an address, which is
+// neither counted by the node nor held by a pending order, as a hex string like the nonce
+// returned by the node
 func (s *OrderService) GetOrderNonceByUserAddress(addr common.Address) (interface{}, error) {
-	return s.orderDao.GetOrderNonce(addr)
+	if s.nonces == nil {
+		return s.orderDao.GetOrderNonce(addr)
+	}
+
+	n, err := s.nonces.Next(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return hexutil.EncodeUint64(n), nil
+}
+
+// getNodeOrderNonce returns the number of orders of an address counted by the node
+func (s *OrderService) getNodeOrderNonce(addr common.Address) (uint64, error) {
+	res, err := s.orderDao.GetOrderNonce(addr)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := parseOrderNonce(res)
+	if err != nil {
+		return 0, err
+	}
+
+	return n.Uint64(), nil
+}
+
+// RegisterHeldNonces adds a function returning the nonces of an address held for the orders
+// stored by another service, which are loaded with the nonces held for the time in force
+// cancels whenever the nonces of the address are synced
+func (s *OrderService) RegisterHeldNonces(fn func(addr common.Address) (map[uint64]common.Hash, error)) {
+	s.heldNonces = append(s.heldNonces, fn)
+}
+
+// getHeldNonces returns the nonces of an address held for the cancels enforcing the time in
+// force of its orders which are not closed, and for the orders of the registered functions
+func (s *OrderService) getHeldNonces(addr common.Address) (map[uint64]common.Hash, error) {
+	expiries, err := s.orderExpiryDao.GetPendingOrderExpiriesByUserAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	held := make(map[uint64]common.Hash)
+	if len(expiries) > 0 {
+		hashes := []common.Hash{}
+		for _, e := range expiries {
+			hashes = append(hashes, e.OrderHash)
+		}
+
+		orders, err := s.orderDao.GetByHashes(hashes)
+		if err != nil {
+			return nil, err
+		}
+
+		// the orders the node has not stored yet are not closed either
+		closed := make(map[common.Hash]bool)
+		for _, o := range orders {
+			if o.Status != types.OrderStatusOpen && o.Status != types.OrderStatusPartialFilled {
+				closed[o.Hash] = true
+			}
+		}
+
+		for _, e := range expiries {
+			oc := e.Cancel()
+			if !closed[e.OrderHash] && oc.Nonce.IsUint64() {
+				held[oc.Nonce.Uint64()] = oc.Hash
+			}
+		}
+	}
+
+	for _, fn := range s.heldNonces {
+		nonces, err := fn(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		for n, h := range nonces {
+			held[n] = h
+		}
+	}
+
+	return held, nil
+}
+
+// handleOrderNonce records that the node counted the nonce of an added order, and frees the
+// nonce of a rejected order. The node may have rejected it because its nonce did not match,
+// so the nonces of the user are synced from the node again. The nonce held for the cancel
+// enforcing the time in force of the order is freed once the order is closed without it
+func (s *OrderService) handleOrderNonce(res *types.EngineResponse) {
+	if s.nonces == nil || res.Order == nil || res.Order.Nonce == nil {
+		return
+	}
+
+	o := res.Order
+	switch res.Status {
+	case types.ORDER_ADDED:
+		s.nonces.Confirm(o.UserAddress, o.Nonce)
+	case types.ORDER_REJECTED, types.ERROR_STATUS:
+		s.nonces.Release(o.UserAddress, o.Nonce, o.Hash)
+		s.nonces.Resync(o.UserAddress)
+	}
+
+	switch res.Status {
+	case types.ORDER_FILLED, types.ORDER_CANCELLED, types.ORDER_REJECTED, types.ERROR_STATUS:
+		oc := o.TimeInForceCancel()
+		s.nonces.Unhold(o.UserAddress, oc.Nonce, oc.Hash)
+	}
+}
+
+// handleOrderExpiry enforces the time in force of an order from its engine responses. The
+// replica handling them has usually not received the order, so the time in force is read from
+// the stored expiry of the order. What remains of an IOC or FOK order is cancelled once the
+// engine has added it to the orderbook, and the expiry is closed with the final status of the
+// order. The ORDER_FILLED or ORDER_CANCELLED response is the final status of an IOC or FOK order
+func (s *OrderService) handleOrderExpiry(res *types.EngineResponse) {
+	if res.Order == nil || res.Order.TimeInForce == types.TimeInForceGTC {
+		return
+	}
+
+	h := res.Order.Hash
+
+	switch res.Status {
+	case types.ORDER_ADDED, types.ORDER_PARTIALLY_FILLED, types.ORDER_FILLED, types.ORDER_CANCELLED,
+		types.ORDER_REJECTED, types.ERROR_STATUS:
+	default:
+		return
+	}
+
+	e, err := s.orderExpiryDao.GetByOrderHash(h)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	if e == nil {
+		return
+	}
+
+	immediate := e.TimeInForce != types.TimeInForceGTD
+
+	switch res.Status {
+	case types.ORDER_ADDED, types.ORDER_PARTIALLY_FILLED:
+		if immediate && e.Status == types.OrderExpiryStatusPending {
+			go s.expireOrder(e)
+		}
+	case types.ORDER_FILLED:
+		s.closeOrderExpiry(h, types.OrderExpiryStatusDone)
+		if immediate {
+			ws.SendOrderMessage(types.ORDER_FILLED, res.Order.UserAddress, res.Order)
+		}
+	default:
+		s.closeOrderExpiry(h, types.OrderExpiryStatusDone)
+	}
+}
+
+// CancelExpiredOrders cancels the orders whose time in force has expired: GTD orders past
+// their expiry date, and IOC or FOK orders that are still open
+func (s *OrderService) CancelExpiredOrders() {
+	expiries, err := s.orderExpiryDao.GetDueOrderExpiries(time.Now())
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, e := range expiries {
+		s.expireOrder(e)
+	}
+}
+
+// expireOrder sends the cancel pre-signed by the user for an expired order. Orders that are
+// not known yet are retried on the next run. The cancel can only be sent while its nonce is
+// the next order nonce of the user, so an expiry whose nonce has been used by another order
+// is reported as failed
+func (s *OrderService) expireOrder(e *types.OrderExpiry) {
+	o, err := s.orderDao.GetByHash(e.OrderHash)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	if o == nil {
+		return
+	}
+
+	if o.Status != types.OrderStatusOpen && o.Status != types.OrderStatusPartialFilled {
+		s.closeOrderExpiry(e.OrderHash, types.OrderExpiryStatusDone)
+		return
+	}
+
+	oc := e.Cancel()
+
+	if app.Config.Engine != app.EngineSimulated {
+		count, err := s.getNodeOrderNonce(e.UserAddress)
+		if err != nil {
+			logger.Error(err)
+			return
+		}
+
+		nonce := new(big.Int).SetUint64(count)
+
+		// the nonce of the order has not been consumed yet
+		if nonce.Cmp(oc.Nonce) < 0 {
+			return
+		}
+
+		if nonce.Cmp(oc.Nonce) > 0 {
+			s.closeOrderExpiry(e.OrderHash, types.OrderExpiryStatusFailed)
+			ws.SendOrderMessage(types.ORDER_EXPIRY_FAILED, e.UserAddress, map[string]interface{}{
+				"hash":  e.OrderHash,
+				"error": fmt.Sprintf("The cancel nonce %v has already been used", oc.Nonce),
+			})
+			return
+		}
+	}
+
+	claimed, err := s.orderExpiryDao.UpdatePendingOrderExpiryStatus(e.OrderHash, types.OrderExpiryStatusDone)
+	if err != nil || !claimed {
+		return
+	}
+
+	err = s.CancelOrder(oc)
+	if err != nil {
+		logger.Error(err)
+		ws.SendOrderMessage(types.ORDER_EXPIRY_FAILED, e.UserAddress, map[string]interface{}{
+			"hash":  e.OrderHash,
+			"error": err.Error(),
+		})
+	}
+}
+
+func (s *OrderService) closeOrderExpiry(h common.Hash, status string) {
+	_, err := s.orderExpiryDao.UpdatePendingOrderExpiryStatus(h, status)
+	if err != nil {
+		logger.Error(err)
+	}
+}
+
+// parseOrderNonce reads the hex encoded order nonce returned by TomoX
+func parseOrderNonce(res interface{}) (*big.Int, error) {
+	str, ok := res.(string)
+	if !ok {
+		return nil, errors.New("Invalid order nonce")
+	}
+
+	nonce, ok := new(big.Int).SetString(strings.TrimPrefix(str, "0x"), 16)
+	if !ok {
+		return nil, errors.New("Invalid order nonce")
+	}
+
+	return nonce, nil
 }
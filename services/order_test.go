@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
+)
+
+func TestNewOrderAcceptsPublishedDuplicate(t *testing.T) {
+	orderDao := new(mocks.OrderDao)
+	pairDao := new(mocks.PairDao)
+	clientOrderDao := new(mocks.ClientOrderDao)
+	validator := new(mocks.ValidatorService)
+	s := NewOrderService(orderDao, nil, pairDao, nil, nil, nil, nil, clientOrderDao, nil, validator, nil)
+
+	w := types.NewWallet()
+	o := &types.Order{
+		UserAddress:     w.Address,
+		ExchangeAddress: common.HexToAddress("0x1"),
+		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteToken:      common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+		Amount:          big.NewInt(10),
+		PricePoint:      big.NewInt(100),
+		Side:            types.BUY,
+		Type:            types.TypeLimitOrder,
+		Nonce:           big.NewInt(1),
+		PostOnly:        true,
+		ClientOrderID:   "retried-order",
+	}
+
+	err := w.SignOrder(o)
+	assert.Nil(t, err)
+
+	accepted := types.NewClientOrder(o)
+	accepted.Status = types.ClientOrderStatusAccepted
+	clientOrderDao.On("Reserve", mock.Anything).Return(accepted, nil)
+
+	// the order now crosses the book, but the retry is accepted as the first submission was
+	err = s.NewOrder(o)
+	assert.Nil(t, err)
+
+	pairDao.AssertNotCalled(t, "GetByTokenAddress", mock.Anything, mock.Anything)
+	orderDao.AssertNotCalled(t, "GetSideOrderBook", mock.Anything, mock.Anything, mock.Anything)
+
+	validator.On("ValidateAvailableBalances", mock.Anything).Return(map[common.Hash]error{}, nil)
+
+	results, err := s.NewOrders([]*types.Order{o})
+	assert.Nil(t, err)
+	assert.True(t, results[0].Accepted)
+	validator.AssertNotCalled(t, "ValidateAvailableBalance", mock.Anything)
+}
+
+func TestCancelOrdersInvalidPayload(t *testing.T) {
+	s := NewOrderService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	results := s.CancelOrders([]*types.OrderCancel{nil})
+	assert.Equal(t, "Invalid payload", results[0].Error)
+	assert.False(t, results[0].Accepted)
+}
+
+func TestTimeInForceCancelNonceIsHeld(t *testing.T) {
+	orderDao := new(mocks.OrderDao)
+	orderExpiryDao := new(mocks.OrderExpiryDao)
+	s := NewOrderService(orderDao, nil, nil, nil, nil, nil, orderExpiryDao, nil, nil, nil, nil)
+
+	w := types.NewWallet()
+	orderDao.On("GetOrderNonce", w.Address).Return("0x5", nil)
+	orderExpiryDao.On("GetPendingOrderExpiriesByUserAddress", w.Address).Return([]*types.OrderExpiry{}, nil)
+
+	o := &types.Order{
+		Hash:        common.HexToHash("0x1"),
+		UserAddress: w.Address,
+		Nonce:       big.NewInt(5),
+		TimeInForce: types.TimeInForceGTD,
+		Expires:     time.Now().Add(time.Hour).Unix(),
+	}
+
+	err := s.reserveOrderNonces(o)
+	assert.Nil(t, err)
+
+	// the next order skips the nonce of the cancel enforcing the expiry
+	next, err := s.GetOrderNonceByUserAddress(w.Address)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x7", next)
+
+	err = s.reserveNonce(w.Address, big.NewInt(6), common.HexToHash("0xa"))
+	assertErrorCode(t, "NONCE_IN_USE", err)
+
+	err = s.reserveNonce(w.Address, big.NewInt(7), common.HexToHash("0xb"))
+	assert.Nil(t, err)
+
+	s.handleSharedEngineResponse(&types.EngineResponse{Status: types.ORDER_ADDED, Order: o})
+
+	// the expiry still sends its cancel with the held nonce
+	oc := types.NewOrderExpiry(o).Cancel()
+	assert.Equal(t, int64(6), oc.Nonce.Int64())
+
+	err = s.reserveNonce(w.Address, oc.Nonce, oc.Hash)
+	assert.Nil(t, err)
+}
+
+func TestTimeInForceCancelNonceIsFreedWithTheOrder(t *testing.T) {
+	orderDao := new(mocks.OrderDao)
+	orderExpiryDao := new(mocks.OrderExpiryDao)
+	s := NewOrderService(orderDao, nil, nil, nil, nil, nil, orderExpiryDao, nil, nil, nil, nil)
+
+	w := types.NewWallet()
+	orderDao.On("GetOrderNonce", w.Address).Return("0x5", nil)
+	orderExpiryDao.On("GetPendingOrderExpiriesByUserAddress", w.Address).Return([]*types.OrderExpiry{}, nil)
+
+	o := &types.Order{
+		Hash:        common.HexToHash("0x1"),
+		UserAddress: w.Address,
+		Nonce:       big.NewInt(5),
+		TimeInForce: types.TimeInForceIOC,
+	}
+
+	s.reserveOrderNonces(o)
+	s.handleSharedEngineResponse(&types.EngineResponse{Status: types.ORDER_ADDED, Order: o})
+	s.handleSharedEngineResponse(&types.EngineResponse{Status: types.ORDER_FILLED, Order: o})
+
+	next, _ := s.GetOrderNonceByUserAddress(w.Address)
+	assert.Equal(t, "0x6", next)
+}
+
+func TestHeldNoncesAreLoadedOnSync(t *testing.T) {
+	orderDao := new(mocks.OrderDao)
+	orderExpiryDao := new(mocks.OrderExpiryDao)
+	s := NewOrderService(orderDao, nil, nil, nil, nil, nil, orderExpiryDao, nil, nil, nil, nil)
+
+	w := types.NewWallet()
+	open := &types.OrderExpiry{OrderHash: common.HexToHash("0x1"), UserAddress: w.Address, Nonce: big.NewInt(5)}
+	filled := &types.OrderExpiry{OrderHash: common.HexToHash("0x2"), UserAddress: w.Address, Nonce: big.NewInt(7)}
+	unknown := &types.OrderExpiry{OrderHash: common.HexToHash("0x3"), UserAddress: w.Address, Nonce: big.NewInt(9)}
+
+	orderDao.On("GetOrderNonce", w.Address).Return("0x5", nil)
+	orderExpiryDao.On("GetPendingOrderExpiriesByUserAddress", w.Address).Return([]*types.OrderExpiry{open, filled, unknown}, nil)
+	orderDao.On("GetByHashes", mock.Anything).Return([]*types.Order{
+		{Hash: open.OrderHash, Status: types.OrderStatusOpen},
+		{Hash: filled.OrderHash, Status: types.OrderStatusFilled},
+	}, nil)
+
+	// a stop order stored by another server holds the nonce 5
+	s.RegisterHeldNonces(func(addr common.Address) (map[uint64]common.Hash, error) {
+		return map[uint64]common.Hash{5: common.HexToHash("0x4")}, nil
+	})
+
+	next, err := s.GetOrderNonceByUserAddress(w.Address)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x7", next)
+
+	err = s.reserveNonce(w.Address, big.NewInt(8), common.HexToHash("0x5"))
+	assert.Nil(t, err)
+
+	err = s.reserveNonce(w.Address, big.NewInt(10), common.HexToHash("0x6"))
+	assertErrorCode(t, "NONCE_IN_USE", err)
+}
+
+func TestHandleOrderExpiryReadsTheStoredTimeInForce(t *testing.T) {
+	orderDao := new(mocks.OrderDao)
+	orderExpiryDao := new(mocks.OrderExpiryDao)
+	s := NewOrderService(orderDao, nil, nil, nil, nil, nil, orderExpiryDao, nil, nil, nil, nil)
+
+	// the order was received by another replica, and is already filled when the expiry runs
+	o := &types.Order{Hash: common.HexToHash("0x1"), Status: types.OrderStatusOpen}
+	e := &types.OrderExpiry{OrderHash: o.Hash, TimeInForce: types.TimeInForceIOC, Status: types.OrderExpiryStatusPending}
+
+	closed := make(chan bool, 1)
+	orderExpiryDao.On("GetByOrderHash", o.Hash).Return(e, nil)
+	orderDao.On("GetByHash", o.Hash).Return(&types.Order{Hash: o.Hash, Status: types.OrderStatusFilled}, nil)
+	orderExpiryDao.On("UpdatePendingOrderExpiryStatus", o.Hash, types.OrderExpiryStatusDone).Return(true, nil).Run(func(args mock.Arguments) {
+		closed <- true
+	})
+
+	s.handleOrderExpiry(&types.EngineResponse{Status: types.ORDER_ADDED, Order: o})
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("the expiry of the IOC order was not handled")
+	}
+
+	s.handleOrderExpiry(&types.EngineResponse{Status: types.ORDER_CANCELLED, Order: o})
+	orderExpiryDao.AssertNumberOfCalls(t, "UpdatePendingOrderExpiryStatus", 2)
+
+	// the expiry of a GTC order is not looked up
+	gtc := &types.Order{Hash: common.HexToHash("0x2"), TimeInForce: types.TimeInForceGTC}
+	s.handleOrderExpiry(&types.EngineResponse{Status: types.ORDER_ADDED, Order: gtc})
+	orderExpiryDao.AssertNotCalled(t, "GetByOrderHash", gtc.Hash)
+}
+
+func TestHandleOrderExpiryKeepsGoodTillDateOrders(t *testing.T) {
+	orderExpiryDao := new(mocks.OrderExpiryDao)
+	s := NewOrderService(nil, nil, nil, nil, nil, nil, orderExpiryDao, nil, nil, nil, nil)
+
+	o := &types.Order{Hash: common.HexToHash("0x1"), Status: types.OrderStatusOpen}
+	e := &types.OrderExpiry{OrderHash: o.Hash, TimeInForce: types.TimeInForceGTD, Status: types.OrderExpiryStatusPending}
+	orderExpiryDao.On("GetByOrderHash", o.Hash).Return(e, nil)
+	orderExpiryDao.On("UpdatePendingOrderExpiryStatus", o.Hash, types.OrderExpiryStatusDone).Return(true, nil)
+
+	s.handleOrderExpiry(&types.EngineResponse{Status: types.ORDER_ADDED, Order: o})
+	orderExpiryDao.AssertNotCalled(t, "UpdatePendingOrderExpiryStatus", mock.Anything, mock.Anything)
+
+	// the expiry is closed with the order, so that its cancel nonce is not held anymore
+	s.handleOrderExpiry(&types.EngineResponse{Status: types.ORDER_FILLED, Order: o})
+	orderExpiryDao.AssertCalled(t, "UpdatePendingOrderExpiryStatus", o.Hash, types.OrderExpiryStatusDone)
+}
+
+func TestSubmitAndWaitStopsWithTheContext(t *testing.T) {
+	s := NewOrderService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	h := common.HexToHash("0x1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res, err := s.submitAndWait(ctx, h, func() error { return nil }, func(*types.EngineResponse) bool { return true })
+	assert.Nil(t, res)
+	assert.Error(t, err)
+
+	// the watcher of the order is removed once the wait stops
+	s.watchersMutex.Lock()
+	defer s.watchersMutex.Unlock()
+	assert.Empty(t, s.orderWatchers)
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"math/big"
+
 	"github.com/tomochain/tomox-sdk/errors"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -42,6 +44,9 @@ func (s *OrderBookService) GetOrderBook(bt, qt common.Address) (*types.OrderBook
 		return nil, errors.New("Pair not found")
 	}
 
+	// the sequence is read first, so that the orderbook includes at least the updates up to it
+	sequence := ws.GetOrderBookSocket().Sequence(utils.GetOrderBookChannelID(bt, qt))
+
 	bids, asks, err := s.orderDao.GetOrderBook(pair)
 	if err != nil {
 		logger.Error(err)
@@ -52,6 +57,7 @@ func (s *OrderBookService) GetOrderBook(bt, qt common.Address) (*types.OrderBook
 		PairName: pair.Name(),
 		Asks:     asks,
 		Bids:     bids,
+		Sequence: sequence,
 	}
 
 	return ob, nil
@@ -68,6 +74,8 @@ func (s *OrderBookService) GetDbOrderBook(bt, qt common.Address) (*types.OrderBo
 		return nil, errors.New("Pair not found")
 	}
 
+	sequence := ws.GetOrderBookSocket().Sequence(utils.GetOrderBookChannelID(bt, qt))
+
 	bids, asks, err := s.orderDao.GetOrderBookInDb(pair)
 	if err != nil {
 		logger.Error(err)
@@ -78,31 +86,141 @@ func (s *OrderBookService) GetDbOrderBook(bt, qt common.Address) (*types.OrderBo
 		PairName: pair.Name(),
 		Asks:     asks,
 		Bids:     bids,
+		Sequence: sequence,
 	}
 
 	return ob, nil
 }
 
+// GetAggregatedOrderBook returns the orderbook grouped by price and cut to the depth of the options
+func (s *OrderBookService) GetAggregatedOrderBook(bt, qt common.Address, opts types.OrderBookOptions) (*types.OrderBook, error) {
+	step, err := s.getOrderBookStep(bt, qt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ob, err := s.GetOrderBook(bt, qt)
+	if err != nil {
+		return nil, err
+	}
+
+	return ob.Aggregate(step, opts.Depth), nil
+}
+
+// getOrderBookStep validates the orderbook options against the pair and returns the price step
+func (s *OrderBookService) getOrderBookStep(bt, qt common.Address, opts types.OrderBookOptions) (*big.Int, error) {
+	pair, err := s.pairDao.GetByTokenAddress(bt, qt)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if pair == nil {
+		return nil, errors.New("Pair not found")
+	}
+
+	return opts.Validate(pair)
+}
+
+// fetchOrderBook returns a function fetching the orderbook without reading its sequence,
+// for the orderbook socket to call while it streams no update
+func (s *OrderBookService) fetchOrderBook(bt, qt common.Address) func() (*types.OrderBook, error) {
+	return func() (*types.OrderBook, error) {
+		pair, err := s.pairDao.GetByTokenAddress(bt, qt)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		if pair == nil {
+			return nil, errors.New("Pair not found")
+		}
+
+		bids, asks, err := s.orderDao.GetOrderBook(pair)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		return &types.OrderBook{PairName: pair.Name(), Asks: asks, Bids: bids}, nil
+	}
+}
+
 // SubscribeOrderBook is responsible for handling incoming orderbook subscription messages
 // It makes an entry of connection in pairSocket corresponding to pair,unit and duration
-func (s *OrderBookService) SubscribeOrderBook(c *ws.Client, bt, qt common.Address) {
+// The connection is subscribed before the orderbook snapshot is fetched, so that no update
+// is missed between the snapshot and the first update
+func (s *OrderBookService) SubscribeOrderBook(c *ws.Client, bt, qt common.Address, opts types.OrderBookOptions) {
 	socket := ws.GetOrderBookSocket()
 
+	if !opts.IsDefault() {
+		s.subscribeAggregatedOrderBook(c, bt, qt, opts)
+		return
+	}
+
+	id := utils.GetOrderBookChannelID(bt, qt)
+	err := socket.Subscribe(id, c)
+	if err != nil {
+		msg := map[string]string{"Message": err.Error()}
+		socket.SendErrorMessage(c, msg)
+		return
+	}
+
 	ob, err := s.GetOrderBook(bt, qt)
+	if err != nil {
+		socket.UnsubscribeChannel(id, c)
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
+	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(id))
+	socket.SendInitMessage(c, ob)
+}
+
+// subscribeAggregatedOrderBook subscribes a connection to the orderbook grouped by price and
+// cut to the depth of the options. The connection then receives the changes of its own
+// grouped orderbook
+func (s *OrderBookService) subscribeAggregatedOrderBook(c *ws.Client, bt, qt common.Address, opts types.OrderBookOptions) {
+	socket := ws.GetOrderBookSocket()
+
+	step, err := s.getOrderBookStep(bt, qt, opts)
 	if err != nil {
 		socket.SendErrorMessage(c, err.Error())
 		return
 	}
 
 	id := utils.GetOrderBookChannelID(bt, qt)
-	err = socket.Subscribe(id, c)
+	err = socket.SubscribeAggregated(id, c, step, opts.Depth, s.fetchOrderBook(bt, qt))
 	if err != nil {
-		msg := map[string]string{"Message": err.Error()}
-		socket.SendErrorMessage(c, msg)
+		socket.SendErrorMessage(c, err.Error())
 		return
 	}
 
 	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(id))
+}
+
+// ResyncOrderBook sends a new orderbook snapshot to a client that missed an orderbook update.
+// A client subscribed with a depth or a grouping receives its grouped orderbook
+func (s *OrderBookService) ResyncOrderBook(c *ws.Client, bt, qt common.Address) {
+	socket := ws.GetOrderBookSocket()
+
+	id := utils.GetOrderBookChannelID(bt, qt)
+	aggregated, err := socket.ResyncAggregated(id, c, s.fetchOrderBook(bt, qt))
+	if err != nil {
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
+	if aggregated {
+		return
+	}
+
+	ob, err := s.GetOrderBook(bt, qt)
+	if err != nil {
+		socket.SendErrorMessage(c, err.Error())
+		return
+	}
+
 	socket.SendInitMessage(c, ob)
 }
 
@@ -130,6 +248,8 @@ func (s *OrderBookService) GetRawOrderBook(bt, qt common.Address) (*types.RawOrd
 		return nil, errors.New("Pair does not exist")
 	}
 
+	sequence := ws.GetRawOrderBookSocket().Sequence(utils.GetOrderBookChannelID(bt, qt))
+
 	orders, err := s.orderDao.GetRawOrderBook(pair)
 	if err != nil {
 		logger.Error(err)
@@ -139,6 +259,7 @@ func (s *OrderBookService) GetRawOrderBook(bt, qt common.Address) (*types.RawOrd
 	return &types.RawOrderBook{
 		PairName: pair.Name(),
 		Orders:   orders,
+		Sequence: sequence,
 	}, nil
 }
 
@@ -147,21 +268,35 @@ func (s *OrderBookService) GetRawOrderBook(bt, qt common.Address) (*types.RawOrd
 func (s *OrderBookService) SubscribeRawOrderBook(c *ws.Client, bt, qt common.Address) {
 	socket := ws.GetRawOrderBookSocket()
 
+	id := utils.GetOrderBookChannelID(bt, qt)
+	err := socket.Subscribe(id, c)
+	if err != nil {
+		msg := map[string]string{"Message": err.Error()}
+		socket.SendErrorMessage(c, msg)
+		return
+	}
+
 	ob, err := s.GetRawOrderBook(bt, qt)
 	if err != nil {
+		socket.UnsubscribeChannel(id, c)
 		socket.SendErrorMessage(c, err.Error())
 		return
 	}
 
-	id := utils.GetOrderBookChannelID(bt, qt)
-	err = socket.Subscribe(id, c)
+	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(id))
+	socket.SendInitMessage(c, ob)
+}
+
+// ResyncRawOrderBook sends a new raw orderbook snapshot to a client that missed an update
+func (s *OrderBookService) ResyncRawOrderBook(c *ws.Client, bt, qt common.Address) {
+	socket := ws.GetRawOrderBookSocket()
+
+	ob, err := s.GetRawOrderBook(bt, qt)
 	if err != nil {
-		msg := map[string]string{"Message": err.Error()}
-		socket.SendErrorMessage(c, msg)
+		socket.SendErrorMessage(c, err.Error())
 		return
 	}
 
-	ws.RegisterConnectionUnsubscribeHandler(c, socket.UnsubscribeChannelHandler(id))
 	socket.SendInitMessage(c, ob)
 }
 
@@ -6,10 +6,11 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // PairService struct with daos required, responsible for communicating with daos.
@@ -165,7 +166,7 @@ func (s *PairService) Create(pair *types.Pair) error {
 }
 
 // GetByID fetches details of a pair using its mongo ID
-func (s *PairService) GetByID(id bson.ObjectId) (*types.Pair, error) {
+func (s *PairService) GetByID(id primitive.ObjectID) (*types.Pair, error) {
 	return s.pairDao.GetByID(id)
 }
 
@@ -197,7 +198,7 @@ func (s *PairService) GetMarketStats(bt, qt common.Address) (*types.PairData, er
 	now := time.Now()
 	end := time.Unix(now.Unix(), 0)
 	start := time.Unix(now.AddDate(0, 0, -1).Unix(), 0)
-	one, _ := bson.ParseDecimal128("1")
+	one, _ := primitive.ParseDecimal128("1")
 
 	tradeDataQuery := []bson.M{
 		{
@@ -362,7 +363,7 @@ func (s *PairService) GetTokenPairData(bt, qt common.Address) (*types.PairData,
 	now := time.Now()
 	end := time.Unix(now.Unix(), 0)
 	start := time.Unix(now.AddDate(0, 0, -1).Unix(), 0)
-	one, _ := bson.ParseDecimal128("1")
+	one, _ := primitive.ParseDecimal128("1")
 
 	tradeDataQuery := []bson.M{
 		{
@@ -443,7 +444,7 @@ func (s *PairService) GetAllTokenPairData() ([]*types.PairData, error) {
 	now := time.Now()
 	end := time.Unix(now.Unix(), 0)
 	start := time.Unix(now.AddDate(0, 0, -1).Unix(), 0)
-	one, _ := bson.ParseDecimal128("1")
+	one, _ := primitive.ParseDecimal128("1")
 
 	pairs, err := s.pairDao.GetActivePairs()
 	if err != nil {
@@ -527,7 +528,7 @@ func (s *PairService) GetAllMarketStats() ([]*types.PairData, error) {
 	now := time.Now()
 	end := time.Unix(now.Unix(), 0)
 	start := time.Unix(now.AddDate(0, 0, -1).Unix(), 0)
-	one, _ := bson.ParseDecimal128("1")
+	one, _ := primitive.ParseDecimal128("1")
 
 	pairs, err := s.pairDao.GetActivePairs()
 	if err != nil {
@@ -4,11 +4,12 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // TradeService struct with daos required, responsible for communicating with daos.
@@ -163,7 +164,7 @@ func (s *PriceBoardService) getPriceBoardData(pairs []types.PairAddresses, durat
 func getGroupBson() bson.M {
 	var group bson.M
 
-	one, _ := bson.ParseDecimal128("1")
+	one, _ := primitive.ParseDecimal128("1")
 	group = bson.M{
 		"count":  bson.M{"$sum": one},
 		"high":   bson.M{"$max": "$pricepoint"},
@@ -1,22 +1,26 @@
+//go:build integration
+// +build integration
+
 package services
 
 import (
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/dbtest"
-	"github.com/tomochain/tomox-sdk/daos"
 	"io/ioutil"
+
+	"github.com/tomochain/tomox-sdk/daos"
+	"github.com/tomochain/tomox-sdk/utils/testutils"
 )
 
-var server dbtest.DBServer
-var db *mgo.Session
+var server testutils.DBServer
+var database *daos.Database
 
 func init() {
 	temp, _ := ioutil.TempDir("", "test")
 	server.SetPath(temp)
 
-	session := server.Session()
-	if _, err := daos.InitSession(session); err != nil {
+	d, err := daos.Connect(server.URI())
+	if err != nil {
 		panic(err)
 	}
-	db = session
+
+	database = d
 }
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/ws"
+)
+
+// StopOrderService keeps the stop orders of users until the market reaches their
+// stop price, then sends the corresponding order through the OrderService.
+//
+// A stop order carries the order signature of the order it turns into, signed with
+// the nonce of the stop order. The nonce is consumed only when the stop order fires, so
+// it is held for the stop order until then, and the stop order is rejected if the nonce
+// has been used meanwhile
+type StopOrderService struct {
+	stopOrderDao interfaces.StopOrderDao
+	pairDao      interfaces.PairDao
+	tradeDao     interfaces.TradeDao
+	orderService interfaces.OrderService
+	validator    interfaces.ValidatorService
+	triggers     map[types.PairAddresses]*stopOrderTrigger
+	mutex        sync.Mutex
+}
+
+// stopOrderTrigger holds the price range traded on a pair since its stop orders were last checked
+type stopOrderTrigger struct {
+	lowPrice  *big.Int
+	highPrice *big.Int
+	wake      chan struct{}
+}
+
+// NewStopOrderService returns a new instance of StopOrderService
+func NewStopOrderService(
+	stopOrderDao interfaces.StopOrderDao,
+	pairDao interfaces.PairDao,
+	tradeDao interfaces.TradeDao,
+	orderService interfaces.OrderService,
+	validator interfaces.ValidatorService,
+) *StopOrderService {
+	return &StopOrderService{
+		stopOrderDao: stopOrderDao,
+		pairDao:      pairDao,
+		tradeDao:     tradeDao,
+		orderService: orderService,
+		validator:    validator,
+		triggers:     make(map[types.PairAddresses]*stopOrderTrigger),
+	}
+}
+
+// GetByHash fetches a stop order by its hash
+func (s *StopOrderService) GetByHash(h common.Hash) (*types.StopOrder, error) {
+	return s.stopOrderDao.GetByHash(h)
+}
+
+// GetOpenStopOrdersByUserAddress fetches the stop orders of an address that have not been triggered yet
+func (s *StopOrderService) GetOpenStopOrdersByUserAddress(addr common.Address) ([]*types.StopOrder, error) {
+	return s.stopOrderDao.GetOpenStopOrdersByUserAddress(addr)
+}
+
+// GetHeldNonces returns the nonces of an address held for the orders of its open stop orders,
+// with the hashes of these orders
+func (s *StopOrderService) GetHeldNonces(addr common.Address) (map[uint64]common.Hash, error) {
+	stopOrders, err := s.stopOrderDao.GetOpenStopOrdersByUserAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	held := make(map[uint64]common.Hash)
+	for _, so := range stopOrders {
+		o, err := so.ToOrder()
+		if err != nil || !so.Nonce.IsUint64() {
+			continue
+		}
+
+		held[so.Nonce.Uint64()] = o.Hash
+	}
+
+	return held, nil
+}
+
+// NewStopOrder validates and stores a stop order.
+// The stop order is rejected if its nonce has already been used, if the user cannot fund
+// the order it turns into, or if the last traded price has already reached the stop price
+func (s *StopOrderService) NewStopOrder(so *types.StopOrder) error {
+	if err := so.Validate(); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	p, err := s.pairDao.GetByTokenAddress(so.BaseToken, so.QuoteToken)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if p == nil {
+		return errors.New("Pair not found")
+	}
+
+	err = so.Process(p)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	nonce, err := s.getOrderNonce(so.UserAddress)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if so.Nonce.Cmp(nonce) < 0 {
+		return fmt.Errorf("Order 'nonce' %v has already been used, the next order nonce is %v", so.Nonce, nonce)
+	}
+
+	o, err := so.ToOrder()
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	err = o.Process(p)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	err = s.validator.ValidateAvailableBalance(o)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	trade, err := s.tradeDao.GetLatestTrade(so.BaseToken, so.QuoteToken)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if trade != nil && so.IsTriggered(trade.PricePoint) {
+		return fmt.Errorf("Stop price has already been reached, the last price is %v", trade.PricePoint)
+	}
+
+	existing, err := s.stopOrderDao.GetByHash(so.Hash)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if existing != nil {
+		return errors.New("Stop order already exists")
+	}
+
+	err = s.orderService.HoldOrderNonce(so.UserAddress, so.Nonce, o.Hash)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	so.Status = types.StopOrderStatusOpen
+	err = s.stopOrderDao.Create(so)
+	if err != nil {
+		logger.Error(err)
+		s.orderService.ReleaseOrderNonce(so.UserAddress, so.Nonce, o.Hash)
+		return err
+	}
+
+	ws.SendOrderMessage(types.STOP_ORDER_ADDED, so.UserAddress, so)
+
+	return nil
+}
+
+// CancelStopOrder cancels a stop order that has not been triggered yet.
+// The cancel message must be signed by the owner of the stop order
+func (s *StopOrderService) CancelStopOrder(oc *types.OrderCancel) error {
+	so, err := s.stopOrderDao.GetByHash(oc.OrderHash)
+	if err != nil || so == nil {
+		return errors.New("No stop order with corresponding hash")
+	}
+
+	if so.Status != types.StopOrderStatusOpen {
+		return fmt.Errorf("Cannot cancel stop order. Status is %v", so.Status)
+	}
+
+	if oc.Hash != oc.ComputeHash() {
+		return errors.New("Invalid cancel hash")
+	}
+
+	addr, err := oc.GetSenderAddress()
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if addr != so.UserAddress {
+		return errors.New("Invalid Signature")
+	}
+
+	updated, err := s.stopOrderDao.UpdateOpenStopOrderStatus(so.Hash, types.StopOrderStatusCancelled)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if !updated {
+		return errors.New("Cannot cancel stop order. It has already been triggered or cancelled")
+	}
+
+	so.Status = types.StopOrderStatusCancelled
+	s.releaseNonce(so)
+	ws.SendOrderMessage(types.STOP_ORDER_CANCELLED, so.UserAddress, so)
+
+	return nil
+}
+
+// HandleTrade records the trade price for its pair and wakes up the worker of the pair.
+// Each pair has a single worker, which checks the stop orders against the lowest and
+// highest prices traded since its last run, so a burst of trades costs a single query
+func (s *StopOrderService) HandleTrade(t *types.Trade) {
+	if t == nil || t.PricePoint == nil {
+		return
+	}
+
+	pair := types.PairAddresses{BaseToken: t.BaseToken, QuoteToken: t.QuoteToken}
+
+	s.mutex.Lock()
+	trigger, ok := s.triggers[pair]
+	if !ok {
+		trigger = &stopOrderTrigger{wake: make(chan struct{}, 1)}
+		s.triggers[pair] = trigger
+		go s.runTrigger(pair, trigger)
+	}
+
+	if trigger.lowPrice == nil || t.PricePoint.Cmp(trigger.lowPrice) < 0 {
+		trigger.lowPrice = t.PricePoint
+	}
+
+	if trigger.highPrice == nil || t.PricePoint.Cmp(trigger.highPrice) > 0 {
+		trigger.highPrice = t.PricePoint
+	}
+	s.mutex.Unlock()
+
+	select {
+	case trigger.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *StopOrderService) runTrigger(pair types.PairAddresses, trigger *stopOrderTrigger) {
+	for range trigger.wake {
+		s.mutex.Lock()
+		lowPrice, highPrice := trigger.lowPrice, trigger.highPrice
+		trigger.lowPrice, trigger.highPrice = nil, nil
+		s.mutex.Unlock()
+
+		if lowPrice == nil || highPrice == nil {
+			continue
+		}
+
+		s.triggerStopOrders(pair, lowPrice, highPrice)
+	}
+}
+
+// triggerStopOrders fires the stop orders of a pair crossed by the given price range.
+// Each stop order is marked as DONE before its order is sent so that it is never
+// submitted twice, even if it is cancelled at the same time
+func (s *StopOrderService) triggerStopOrders(pair types.PairAddresses, lowPrice, highPrice *big.Int) {
+	stopOrders, err := s.stopOrderDao.GetTriggeredStopOrders(pair.BaseToken, pair.QuoteToken, lowPrice, highPrice)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	for _, so := range stopOrders {
+		claimed, err := s.stopOrderDao.UpdateOpenStopOrderStatus(so.Hash, types.StopOrderStatusDone)
+		if err != nil {
+			logger.Error(err)
+			continue
+		}
+
+		if !claimed {
+			continue
+		}
+
+		so.Status = types.StopOrderStatusDone
+
+		o, err := s.submitStopOrder(so)
+		if err != nil {
+			logger.Error(err)
+			s.rejectStopOrder(so, err)
+			continue
+		}
+
+		ws.SendOrderMessage(types.STOP_ORDER_TRIGGERED, so.UserAddress, map[string]interface{}{
+			"stopOrder": so,
+			"order":     o,
+		})
+	}
+}
+
+// submitStopOrder sends the order of a stop order. Its nonce is taken over from the stop
+// order, and the order fails if the nonce has been used meanwhile
+func (s *StopOrderService) submitStopOrder(so *types.StopOrder) (*types.Order, error) {
+	o, err := so.ToOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.orderService.NewOrder(o)
+	if err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func (s *StopOrderService) rejectStopOrder(so *types.StopOrder, reason error) {
+	so.Status = types.StopOrderStatusRejected
+	s.releaseNonce(so)
+
+	err := s.stopOrderDao.UpdateByHash(so.Hash, so)
+	if err != nil {
+		logger.Error(err)
+	}
+
+	ws.SendOrderMessage(types.STOP_ORDER_REJECTED, so.UserAddress, map[string]interface{}{
+		"stopOrder": so,
+		"message":   reason.Error(),
+	})
+}
+
+// releaseNonce frees the nonce held for a stop order which will not fire
+func (s *StopOrderService) releaseNonce(so *types.StopOrder) {
+	o, err := so.ToOrder()
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
+	s.orderService.ReleaseOrderNonce(so.UserAddress, so.Nonce, o.Hash)
+}
+
+// getOrderNonce returns the next order nonce of an account on TomoX
+func (s *StopOrderService) getOrderNonce(addr common.Address) (*big.Int, error) {
+	res, err := s.orderService.GetOrderNonceByUserAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseOrderNonce(res)
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tomochain/tomox-sdk/app"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
+)
+
+type stopOrderServiceMocks struct {
+	stopOrderDao *mocks.StopOrderDao
+	pairDao      *mocks.PairDao
+	tradeDao     *mocks.TradeDao
+	orderService *mocks.OrderService
+	validator    *mocks.ValidatorService
+}
+
+func newTestStopOrderService() (*StopOrderService, *stopOrderServiceMocks) {
+	m := &stopOrderServiceMocks{
+		stopOrderDao: new(mocks.StopOrderDao),
+		pairDao:      new(mocks.PairDao),
+		tradeDao:     new(mocks.TradeDao),
+		orderService: new(mocks.OrderService),
+		validator:    new(mocks.ValidatorService),
+	}
+
+	s := NewStopOrderService(m.stopOrderDao, m.pairDao, m.tradeDao, m.orderService, m.validator)
+	return s, m
+}
+
+func newTestStopOrder(t *testing.T, w *types.Wallet, nonce int64) *types.StopOrder {
+	exchangeAddress := "0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"
+	app.Config.Tomochain = map[string]string{"exchange_address": exchangeAddress}
+
+	so := &types.StopOrder{
+		UserAddress:     w.Address,
+		ExchangeAddress: common.HexToAddress(exchangeAddress),
+		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteToken:      common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+		StopPrice:       big.NewInt(1000),
+		LimitPrice:      big.NewInt(1100),
+		Direction:       types.StopOrderDirectionUp,
+		Amount:          big.NewInt(1000),
+		Side:            types.BUY,
+		Type:            types.TypeStopLimitOrder,
+		Nonce:           big.NewInt(nonce),
+	}
+
+	if err := so.Sign(w); err != nil {
+		t.Fatal(err)
+	}
+
+	return so
+}
+
+func newTestStopOrderPair(so *types.StopOrder) *types.Pair {
+	return &types.Pair{
+		BaseTokenSymbol:    "ZRX",
+		BaseTokenAddress:   so.BaseToken,
+		BaseTokenDecimals:  18,
+		QuoteTokenSymbol:   "TOMO",
+		QuoteTokenAddress:  so.QuoteToken,
+		QuoteTokenDecimals: 18,
+	}
+}
+
+func TestNewStopOrder(t *testing.T) {
+	s, m := newTestStopOrderService()
+	so := newTestStopOrder(t, types.NewWallet(), 1)
+
+	m.pairDao.On("GetByTokenAddress", so.BaseToken, so.QuoteToken).Return(newTestStopOrderPair(so), nil)
+	m.orderService.On("GetOrderNonceByUserAddress", so.UserAddress).Return("0x1", nil)
+	m.validator.On("ValidateAvailableBalance", mock.Anything).Return(nil)
+	m.tradeDao.On("GetLatestTrade", so.BaseToken, so.QuoteToken).Return(&types.Trade{PricePoint: big.NewInt(900)}, nil)
+	m.stopOrderDao.On("GetByHash", so.Hash).Return(nil, nil)
+	m.orderService.On("HoldOrderNonce", so.UserAddress, so.Nonce, mock.Anything).Return(nil)
+	m.stopOrderDao.On("Create", so).Return(nil)
+
+	err := s.NewStopOrder(so)
+	assert.Nil(t, err)
+	assert.Equal(t, types.StopOrderStatusOpen, so.Status)
+	m.stopOrderDao.AssertCalled(t, "Create", so)
+
+	// the nonce is held for the order the stop order turns into
+	o, _ := so.ToOrder()
+	m.orderService.AssertCalled(t, "HoldOrderNonce", so.UserAddress, so.Nonce, o.Hash)
+}
+
+func TestNewStopOrderRejectsHeldNonce(t *testing.T) {
+	s, m := newTestStopOrderService()
+	so := newTestStopOrder(t, types.NewWallet(), 1)
+
+	m.pairDao.On("GetByTokenAddress", so.BaseToken, so.QuoteToken).Return(newTestStopOrderPair(so), nil)
+	m.orderService.On("GetOrderNonceByUserAddress", so.UserAddress).Return("0x1", nil)
+	m.validator.On("ValidateAvailableBalance", mock.Anything).Return(nil)
+	m.tradeDao.On("GetLatestTrade", so.BaseToken, so.QuoteToken).Return(&types.Trade{PricePoint: big.NewInt(900)}, nil)
+	m.stopOrderDao.On("GetByHash", so.Hash).Return(nil, nil)
+	m.orderService.On("HoldOrderNonce", so.UserAddress, so.Nonce, mock.Anything).Return(errors.NonceInUse("1", "2"))
+
+	err := s.NewStopOrder(so)
+	assertErrorCode(t, "NONCE_IN_USE", err)
+	m.stopOrderDao.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestNewStopOrderRejectsReachedStopPrice(t *testing.T) {
+	s, m := newTestStopOrderService()
+	so := newTestStopOrder(t, types.NewWallet(), 1)
+
+	m.pairDao.On("GetByTokenAddress", so.BaseToken, so.QuoteToken).Return(newTestStopOrderPair(so), nil)
+	m.orderService.On("GetOrderNonceByUserAddress", so.UserAddress).Return("0x1", nil)
+	m.validator.On("ValidateAvailableBalance", mock.Anything).Return(nil)
+	m.tradeDao.On("GetLatestTrade", so.BaseToken, so.QuoteToken).Return(&types.Trade{PricePoint: big.NewInt(1000)}, nil)
+
+	err := s.NewStopOrder(so)
+	assert.NotNil(t, err)
+	m.stopOrderDao.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestNewStopOrderRejectsUsedNonce(t *testing.T) {
+	s, m := newTestStopOrderService()
+	so := newTestStopOrder(t, types.NewWallet(), 1)
+
+	m.pairDao.On("GetByTokenAddress", so.BaseToken, so.QuoteToken).Return(newTestStopOrderPair(so), nil)
+	m.orderService.On("GetOrderNonceByUserAddress", so.UserAddress).Return("0x2", nil)
+
+	err := s.NewStopOrder(so)
+	assert.NotNil(t, err)
+	m.stopOrderDao.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestStopOrderHeldNonces(t *testing.T) {
+	s, m := newTestStopOrderService()
+	so := newTestStopOrder(t, types.NewWallet(), 3)
+
+	m.stopOrderDao.On("GetOpenStopOrdersByUserAddress", so.UserAddress).Return([]*types.StopOrder{so}, nil)
+
+	held, err := s.GetHeldNonces(so.UserAddress)
+	assert.Nil(t, err)
+
+	o, _ := so.ToOrder()
+	assert.Equal(t, map[uint64]common.Hash{3: o.Hash}, held)
+}
+
+func TestCancelStopOrder(t *testing.T) {
+	s, m := newTestStopOrderService()
+	owner := types.NewWallet()
+	so := newTestStopOrder(t, owner, 1)
+	so.Status = types.StopOrderStatusOpen
+
+	m.stopOrderDao.On("GetByHash", so.Hash).Return(so, nil)
+	m.stopOrderDao.On("UpdateOpenStopOrderStatus", so.Hash, types.StopOrderStatusCancelled).Return(true, nil)
+	m.orderService.On("ReleaseOrderNonce", so.UserAddress, so.Nonce, mock.Anything).Return()
+
+	oc := &types.OrderCancel{OrderHash: so.Hash, Nonce: big.NewInt(2)}
+	if err := oc.Sign(types.NewWallet()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.CancelStopOrder(oc)
+	assert.EqualError(t, err, "Invalid Signature")
+	m.stopOrderDao.AssertNotCalled(t, "UpdateOpenStopOrderStatus", mock.Anything, mock.Anything)
+
+	if err := oc.Sign(owner); err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.CancelStopOrder(oc)
+	assert.Nil(t, err)
+	assert.Equal(t, types.StopOrderStatusCancelled, so.Status)
+	m.orderService.AssertCalled(t, "ReleaseOrderNonce", so.UserAddress, so.Nonce, mock.Anything)
+}
+
+func TestTriggerStopOrdersSubmitsClaimedStopOrders(t *testing.T) {
+	s, m := newTestStopOrderService()
+	so := newTestStopOrder(t, types.NewWallet(), 1)
+	pair := types.PairAddresses{BaseToken: so.BaseToken, QuoteToken: so.QuoteToken}
+
+	m.stopOrderDao.On("GetTriggeredStopOrders", so.BaseToken, so.QuoteToken, big.NewInt(1000), big.NewInt(1000)).Return([]*types.StopOrder{so}, nil)
+	m.stopOrderDao.On("UpdateOpenStopOrderStatus", so.Hash, types.StopOrderStatusDone).Return(true, nil)
+	m.orderService.On("NewOrder", mock.Anything).Return(nil)
+
+	s.triggerStopOrders(pair, big.NewInt(1000), big.NewInt(1000))
+
+	m.orderService.AssertNumberOfCalls(t, "NewOrder", 1)
+	o := m.orderService.Calls[len(m.orderService.Calls)-1].Arguments.Get(0).(*types.Order)
+	assert.Equal(t, types.TypeLimitOrder, o.Type)
+	assert.Equal(t, so.LimitPrice, o.PricePoint)
+	assert.Equal(t, so.OrderSignature, o.Signature)
+	assert.Equal(t, types.StopOrderStatusDone, so.Status)
+}
+
+func TestTriggerStopOrdersSkipsStopOrdersClaimedElsewhere(t *testing.T) {
+	s, m := newTestStopOrderService()
+	so := newTestStopOrder(t, types.NewWallet(), 1)
+	pair := types.PairAddresses{BaseToken: so.BaseToken, QuoteToken: so.QuoteToken}
+
+	m.stopOrderDao.On("GetTriggeredStopOrders", so.BaseToken, so.QuoteToken, big.NewInt(1000), big.NewInt(1000)).Return([]*types.StopOrder{so}, nil)
+	m.stopOrderDao.On("UpdateOpenStopOrderStatus", so.Hash, types.StopOrderStatusDone).Return(false, nil)
+
+	s.triggerStopOrders(pair, big.NewInt(1000), big.NewInt(1000))
+
+	m.orderService.AssertNotCalled(t, "NewOrder", mock.Anything)
+}
+
+func TestTriggerStopOrdersRejectsStaleNonce(t *testing.T) {
+	s, m := newTestStopOrderService()
+	so := newTestStopOrder(t, types.NewWallet(), 1)
+	pair := types.PairAddresses{BaseToken: so.BaseToken, QuoteToken: so.QuoteToken}
+
+	m.stopOrderDao.On("GetTriggeredStopOrders", so.BaseToken, so.QuoteToken, big.NewInt(1000), big.NewInt(1000)).Return([]*types.StopOrder{so}, nil)
+	m.stopOrderDao.On("UpdateOpenStopOrderStatus", so.Hash, types.StopOrderStatusDone).Return(true, nil)
+	m.stopOrderDao.On("UpdateByHash", so.Hash, so).Return(nil)
+	m.orderService.On("NewOrder", mock.Anything).Return(errors.NonceTooLow("1", "2"))
+	m.orderService.On("ReleaseOrderNonce", so.UserAddress, so.Nonce, mock.Anything).Return()
+
+	s.triggerStopOrders(pair, big.NewInt(1000), big.NewInt(1000))
+
+	m.stopOrderDao.AssertCalled(t, "UpdateByHash", so.Hash, so)
+	assert.Equal(t, types.StopOrderStatusRejected, so.Status)
+}
@@ -2,10 +2,10 @@ package services
 
 import (
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/interfaces"
 
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // TokenService struct with daos required, responsible for communicating with daos.
@@ -41,7 +41,7 @@ func (s *TokenService) Create(token *types.Token) error {
 }
 
 // GetByID fetches the detailed document of a token using its mongo ID
-func (s *TokenService) GetByID(id bson.ObjectId) (*types.Token, error) {
+func (s *TokenService) GetByID(id primitive.ObjectID) (*types.Token, error) {
 	return s.tokenDao.GetByID(id)
 }
 
@@ -1,7 +1,6 @@
 package services
 
 import (
-	"context"
 	"sync"
 	"time"
 
@@ -13,6 +12,7 @@ import (
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
 	"github.com/tomochain/tomox-sdk/ws"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // TradeService struct with daos required, responsible for communicating with daos.
@@ -25,6 +25,8 @@ type TradeService struct {
 	ohlcvService    *OHLCVService
 	bulkTrades      map[types.PairAddresses][]*types.Trade
 	mutext          sync.RWMutex
+
+	tradeNotifyCallbacks []func(*types.Trade)
 }
 
 // NewTradeService returns a new instance of TradeService
@@ -47,6 +49,12 @@ func NewTradeService(
 	}
 }
 
+// RegisterNotify adds a function that is called with every new trade.
+// Functions are called in the order of registration and should not block
+func (s *TradeService) RegisterNotify(fn func(*types.Trade)) {
+	s.tradeNotifyCallbacks = append(s.tradeNotifyCallbacks, fn)
+}
+
 // Subscribe
 func (s *TradeService) Subscribe(c *ws.Client, bt, qt common.Address) {
 	socket := ws.GetTradeSocket()
@@ -125,62 +133,30 @@ func (s *TradeService) GetByOrderHashes(hashes []common.Hash) ([]*types.Trade, e
 	return s.tradeDao.GetByOrderHashes(hashes)
 }
 
-func (s *TradeService) WatchChanges() {
+// WatchChanges handles the changes of the trades stream, and broadcasts the trades and the
+// tick updates
+func (s *TradeService) WatchChanges(streams interfaces.ChangeStreamService) {
+	go s.BroadcastBulkTrades()
 
-	ct, sc, err := s.tradeDao.Watch()
-
-	if err != nil {
-		logger.Error("Failed to open change stream")
-		return //exiting func
-	}
-
-	defer ct.Close()
-	defer sc.Close()
-
-	// Watch the event again in case there is error and function returned
-	defer s.WatchChanges()
-
-	ctx := context.Background()
-
-	go func() {
-		for {
-			<-time.After(500 * time.Millisecond)
-			s.processBulkTrades()
+	streams.Watch("trades", s.tradeDao.Watch, func(raw bson.Raw) {
+		ev := types.TradeChangeEvent{}
+		err := bson.Unmarshal(raw, &ev)
+		if err != nil {
+			logger.Error(err)
+			return
 		}
-	}()
 
-	//Handling change stream in a cycle
-	for {
-		select {
-		case <-ctx.Done(): // if parent context was cancelled
-			err := ct.Close() // close the stream
-			if err != nil {
-				logger.Error("Change stream closed")
-			}
-			return //exiting from the func
-		default:
-			ev := types.TradeChangeEvent{}
-
-			//getting next item from the steam
-			ok := ct.Next(&ev)
-
-			//if data from the stream wasn't un-marshaled, we get ok == false as a result
-			//so we need to call Err() method to get info why
-			//it'll be nil if we just have no data
-			if !ok {
-				err := ct.Err()
-				if err != nil {
-					logger.Error(err)
-					return
-				}
-			}
+		logger.Debugf("Operation Type: %s", ev.OperationType)
+		s.HandleDocumentType(ev)
+	})
+}
 
-			//if item from the stream un-marshaled successfully, do something with it
-			if ok {
-				logger.Debugf("Operation Type: %s", ev.OperationType)
-				s.HandleDocumentType(ev)
-			}
-		}
+// BroadcastBulkTrades broadcasts every 500ms the trades and tick updates received since the
+// last broadcast
+func (s *TradeService) BroadcastBulkTrades() {
+	for {
+		<-time.After(500 * time.Millisecond)
+		s.processBulkTrades()
 	}
 }
 
@@ -272,6 +248,10 @@ func (s *TradeService) HandleOperationInsert(trade *types.Trade) error {
 
 	s.HandleTradeSuccess(m)
 
+	for _, fn := range s.tradeNotifyCallbacks {
+		fn(trade)
+	}
+
 	return nil
 }
 
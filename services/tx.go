@@ -2,7 +2,10 @@ package services
 
 import (
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	eth "github.com/ethereum/go-ethereum/core/types"
 	"github.com/tomochain/tomox-sdk/interfaces"
+	"github.com/tomochain/tomox-sdk/signer"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -26,17 +29,36 @@ func (s *TxService) GetTxDefaultSendOptions() (*bind.TransactOpts, error) {
 		return nil, err
 	}
 
-	return bind.NewKeyedTransactor(wallet.PrivateKey), nil
+	if wallet.GetSigner() == nil {
+		return nil, types.ErrNoWalletSigner
+	}
+
+	return signer.TransactOpts(wallet.GetSigner()), nil
 }
 
 func (s *TxService) GetTxSendOptions() (*bind.TransactOpts, error) {
-	return bind.NewKeyedTransactor(s.Wallet.PrivateKey), nil
+	if s.Wallet.GetSigner() == nil {
+		return nil, types.ErrNoWalletSigner
+	}
+
+	return signer.TransactOpts(s.Wallet.GetSigner()), nil
 }
 
 func (s *TxService) SetTxSender(w *types.Wallet) {
 	s.Wallet = w
 }
 
+// GetCustomTxSendOptions returns the options of the transactions signed by the signer of a
+// wallet. The transactions of a wallet that can not sign fail with ErrNoWalletSigner
 func (s *TxService) GetCustomTxSendOptions(w *types.Wallet) *bind.TransactOpts {
-	return bind.NewKeyedTransactor(w.PrivateKey)
+	if w.GetSigner() == nil {
+		return &bind.TransactOpts{
+			From: w.Address,
+			Signer: func(eth.Signer, common.Address, *eth.Transaction) (*eth.Transaction, error) {
+				return nil, types.ErrNoWalletSigner
+			},
+		}
+	}
+
+	return signer.TransactOpts(w.GetSigner())
 }
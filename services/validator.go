@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/interfaces"
 	"github.com/tomochain/tomox-sdk/types"
 	"github.com/tomochain/tomox-sdk/utils"
@@ -41,9 +43,34 @@ func (s *ValidatorService) ValidateAvailableBalance(o *types.Order) error {
 		return err
 	}
 
+	return s.validateAvailableBalance(o, pair, big.NewInt(0))
+}
+
+// ValidateReplacingBalance checks the balance of an order placed in place of an open order of
+// the same pair, before the open order is cancelled. What remains of the replaced order is
+// still locked, and is counted as available when both orders sell the same token
+func (s *ValidatorService) ValidateReplacingBalance(o *types.Order, replaced *types.Order) error {
+	pair, err := s.pairDao.GetByTokenAddress(o.BaseToken, o.QuoteToken)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	released := big.NewInt(0)
+	if replaced.SellToken() == o.SellToken() {
+		released = replaced.RemainingSellAmount(pair)
+	}
+
+	return s.validateAvailableBalance(o, pair, released)
+}
+
+// validateAvailableBalance checks that the balance of the user minus the amount locked by
+// the open orders, plus the released amount, covers the order
+func (s *ValidatorService) validateAvailableBalance(o *types.Order, pair *types.Pair, released *big.Int) error {
 	totalRequiredAmount := o.TotalRequiredSellAmount(pair)
 
 	var sellTokenBalance *big.Int
+	var err error
 
 	// we implement retries in the case the provider connection fell asleep
 	err = utils.Retry(3, func() error {
@@ -62,7 +89,7 @@ func (s *ValidatorService) ValidateAvailableBalance(o *types.Order) error {
 		return err
 	}
 
-	availableSellTokenBalance := math.Sub(sellTokenBalance, sellTokenLockedBalance)
+	availableSellTokenBalance := math.Add(math.Sub(sellTokenBalance, sellTokenLockedBalance), released)
 
 	//Sell Token Balance
 	if sellTokenBalance.Cmp(totalRequiredAmount) == -1 {
@@ -76,6 +103,68 @@ func (s *ValidatorService) ValidateAvailableBalance(o *types.Order) error {
 	return nil
 }
 
+// ValidateAvailableBalances checks that a batch of orders can be funded as a whole. The orders
+// are grouped by user and sell token, and the balance and locked amount of each group are fetched
+// once. Orders are funded in the order of the batch, and the returned map holds the error of
+// each order that cannot be funded
+func (s *ValidatorService) ValidateAvailableBalances(orders []*types.Order) (map[common.Hash]error, error) {
+	errs := make(map[common.Hash]error)
+
+	pairs, err := s.pairDao.GetActivePairs()
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	pairsByCode := make(map[string]*types.Pair)
+	for i := range pairs {
+		pairsByCode[pairs[i].Code()] = pairs[i]
+	}
+
+	type balanceKey struct {
+		user  common.Address
+		token common.Address
+	}
+
+	available := make(map[balanceKey]*big.Int)
+
+	for _, o := range orders {
+		code, _ := o.PairCode()
+		pair, ok := pairsByCode[code]
+		if !ok {
+			errs[o.Hash] = errors.New("Pair not found")
+			continue
+		}
+
+		key := balanceKey{o.UserAddress, o.SellToken()}
+		if _, ok := available[key]; !ok {
+			balance, err := s.ethereumProvider.Balance(o.UserAddress, o.SellToken())
+			if err != nil {
+				logger.Error(err)
+				return nil, err
+			}
+
+			locked, err := s.orderDao.GetUserLockedBalance(o.UserAddress, o.SellToken(), pairs)
+			if err != nil {
+				logger.Error(err)
+				return nil, err
+			}
+
+			available[key] = math.Sub(balance, locked)
+		}
+
+		required := o.TotalRequiredSellAmount(pair)
+		if available[key].Cmp(required) == -1 {
+			errs[o.Hash] = fmt.Errorf("insufficient %v available", o.SellTokenSymbol())
+			continue
+		}
+
+		available[key] = math.Sub(available[key], required)
+	}
+
+	return errs, nil
+}
+
 func (s *ValidatorService) ValidateBalance(o *types.Order) error {
 	//exchangeAddress := common.HexToAddress(app.Config.Tomochain["exchange_address"])
 
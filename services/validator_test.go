@@ -0,0 +1,91 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/testutils/mocks"
+)
+
+func TestValidateAvailableBalances(t *testing.T) {
+	provider := new(mocks.EthereumProvider)
+	orderDao := new(mocks.OrderDao)
+	pairDao := new(mocks.PairDao)
+	s := NewValidatorService(provider, nil, orderDao, pairDao)
+
+	user := common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa")
+	pair := &types.Pair{
+		BaseTokenSymbol:   "ZRX",
+		BaseTokenAddress:  common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteTokenSymbol:  "TOMO",
+		QuoteTokenAddress: common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+	}
+
+	newOrder := func(hash string, amount int64) *types.Order {
+		return &types.Order{
+			UserAddress: user,
+			BaseToken:   pair.BaseTokenAddress,
+			QuoteToken:  pair.QuoteTokenAddress,
+			PairName:    "ZRX/TOMO",
+			Hash:        common.HexToHash(hash),
+			Side:        types.SELL,
+			Amount:      big.NewInt(amount),
+		}
+	}
+
+	orders := []*types.Order{newOrder("0x1", 50), newOrder("0x2", 40), newOrder("0x3", 30)}
+
+	pairDao.On("GetActivePairs").Return([]*types.Pair{pair}, nil)
+	provider.On("Balance", user, pair.BaseTokenAddress).Return(big.NewInt(100), nil)
+	orderDao.On("GetUserLockedBalance", user, pair.BaseTokenAddress, mock.Anything).Return(big.NewInt(20), nil)
+
+	errs, err := s.ValidateAvailableBalances(orders)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(errs))
+	assert.EqualError(t, errs[orders[1].Hash], "insufficient ZRX available")
+
+	provider.AssertNumberOfCalls(t, "Balance", 1)
+	orderDao.AssertNumberOfCalls(t, "GetUserLockedBalance", 1)
+}
+
+func TestValidateReplacingBalance(t *testing.T) {
+	provider := new(mocks.EthereumProvider)
+	orderDao := new(mocks.OrderDao)
+	pairDao := new(mocks.PairDao)
+	s := NewValidatorService(provider, nil, orderDao, pairDao)
+
+	user := common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa")
+	pair := &types.Pair{
+		BaseTokenSymbol:   "ZRX",
+		BaseTokenAddress:  common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteTokenSymbol:  "TOMO",
+		QuoteTokenAddress: common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+	}
+
+	newOrder := func(amount int64, filled int64) *types.Order {
+		return &types.Order{
+			UserAddress:  user,
+			BaseToken:    pair.BaseTokenAddress,
+			QuoteToken:   pair.QuoteTokenAddress,
+			PairName:     "ZRX/TOMO",
+			Side:         types.SELL,
+			Amount:       big.NewInt(amount),
+			FilledAmount: big.NewInt(filled),
+		}
+	}
+
+	pairDao.On("GetByTokenAddress", pair.BaseTokenAddress, pair.QuoteTokenAddress).Return(pair, nil)
+	pairDao.On("GetActivePairs").Return([]*types.Pair{pair}, nil)
+	provider.On("Balance", user, pair.BaseTokenAddress).Return(big.NewInt(100), nil)
+	orderDao.On("GetUserLockedBalance", user, pair.BaseTokenAddress, mock.Anything).Return(big.NewInt(80), nil)
+
+	// the 60 remaining of the replaced order are available to the replacing order
+	replaced := newOrder(70, 10)
+	assert.Nil(t, s.ValidateReplacingBalance(newOrder(80, 0), replaced))
+	assert.EqualError(t, s.ValidateReplacingBalance(newOrder(90, 0), replaced), "insufficient ZRX available")
+	assert.EqualError(t, s.ValidateAvailableBalance(newOrder(80, 0)), "insufficient ZRX available")
+}
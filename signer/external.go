@@ -0,0 +1,125 @@
+package signer
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// ExternalTimeout is the time an external signer waits for each signature
+const ExternalTimeout = 10 * time.Second
+
+// ExternalSigner signs with the key of an address held by a signing process, which serves the
+// signer_signDigest method over JSON-RPC on a unix socket (see Serve)
+type ExternalSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// NewExternalSigner connects to the signing process listening on socket, and returns the
+// signer of one of its addresses
+func NewExternalSigner(socket string, address common.Address) (*ExternalSigner, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ExternalTimeout)
+	defer cancel()
+
+	client, err := rpc.DialIPC(ctx, socket)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := []common.Address{}
+	err = client.CallContext(ctx, &addresses, "signer_accounts")
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	for _, addr := range addresses {
+		if addr == address {
+			return &ExternalSigner{client, address}, nil
+		}
+	}
+
+	client.Close()
+	return nil, errors.New("The external signer has no key for " + address.Hex())
+}
+
+// Address returns the address the signing process signs for
+func (s *ExternalSigner) Address() common.Address {
+	return s.address
+}
+
+// SignDigest asks the signing process to sign a digest
+func (s *ExternalSigner) SignDigest(digest []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ExternalTimeout)
+	defer cancel()
+
+	var sig hexutil.Bytes
+	err := s.client.CallContext(ctx, &sig, "signer_signDigest", s.address, hexutil.Bytes(digest))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sig) != 65 {
+		return nil, errors.New("The external signer returned an invalid signature")
+	}
+
+	return sig, nil
+}
+
+// Close closes the connection to the signing process
+func (s *ExternalSigner) Close() {
+	s.client.Close()
+}
+
+// SignerAPI is the JSON-RPC API of a signing process. It is exported only because the rpc
+// package registers exported types
+type SignerAPI struct {
+	signers map[common.Address]Signer
+}
+
+// Accounts returns the addresses of the signing process
+func (api *SignerAPI) Accounts() []common.Address {
+	addresses := []common.Address{}
+	for addr := range api.signers {
+		addresses = append(addresses, addr)
+	}
+
+	return addresses
+}
+
+// SignDigest signs a 32 bytes digest with the key of an address
+func (api *SignerAPI) SignDigest(address common.Address, digest hexutil.Bytes) (hexutil.Bytes, error) {
+	s, ok := api.signers[address]
+	if !ok {
+		return nil, errors.New("No key for " + address.Hex())
+	}
+
+	if len(digest) != 32 {
+		return nil, errors.New("The digest should have 32 bytes")
+	}
+
+	return s.SignDigest(digest)
+}
+
+// Serve serves the signer_accounts and signer_signDigest methods of a signing process on l,
+// which should be a unix socket only the server can reach, until l is closed
+func Serve(l net.Listener, signers ...Signer) error {
+	api := &SignerAPI{make(map[common.Address]Signer)}
+	for _, s := range signers {
+		api.signers[s.Address()] = s
+	}
+
+	server := rpc.NewServer()
+	err := server.RegisterName("signer", api)
+	if err != nil {
+		return err
+	}
+
+	return server.ServeListener(l)
+}
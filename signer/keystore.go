@@ -0,0 +1,70 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// OpenKeystore decrypts the key of a keystore file with a passphrase, and returns its signer
+func OpenKeystore(path string, passphrase string) (*MemorySigner, error) {
+	keyJSON, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMemorySigner(key), nil
+}
+
+// EncryptKey returns the keystore JSON of a private key encrypted with a passphrase. The key
+// is derived with the light scrypt parameters, so that the keys can be decrypted by a server
+// without taking 256MB of memory each
+func EncryptKey(key *ecdsa.PrivateKey, passphrase string) ([]byte, error) {
+	k := &keystore.Key{
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}
+
+	return keystore.EncryptKey(k, passphrase, keystore.LightScryptN, keystore.LightScryptP)
+}
+
+// DecryptKey returns the private key of a keystore JSON encrypted with a passphrase
+func DecryptKey(keyJSON []byte, passphrase string) (*ecdsa.PrivateKey, error) {
+	k, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.PrivateKey, nil
+}
+
+// ReadPassphrase returns the passphrase held by the environment variable env, or else the
+// content of file without its trailing newline
+func ReadPassphrase(env string, file string) (string, error) {
+	if env != "" {
+		if passphrase, ok := os.LookupEnv(env); ok {
+			return passphrase, nil
+		}
+	}
+
+	if file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+
+	return "", errors.New("No passphrase in the environment or in a file")
+}
@@ -0,0 +1,40 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MemorySigner signs with a private key held in memory
+type MemorySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewMemorySigner returns a signer of a private key
+func NewMemorySigner(key *ecdsa.PrivateKey) *MemorySigner {
+	return &MemorySigner{key, crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// NewRandomMemorySigner returns a signer of a new random private key, which is lost when the
+// process exits. It is meant for development
+func NewRandomMemorySigner() (*MemorySigner, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMemorySigner(key), nil
+}
+
+// Address returns the address of the private key
+func (s *MemorySigner) Address() common.Address {
+	return s.address
+}
+
+// SignDigest signs a digest with the private key
+func (s *MemorySigner) SignDigest(digest []byte) ([]byte, error) {
+	return crypto.Sign(digest, s.key)
+}
@@ -0,0 +1,90 @@
+// Package signer signs digests and transactions for an address without exposing its private
+// key to the callers. The keys are kept in memory, decrypted from a keystore file, or held by
+// an external signing process reached over a local socket
+package signer
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+const (
+	TypeKeystore = "keystore"
+	TypeExternal = "external"
+	TypeMemory   = "memory"
+)
+
+// Signer signs for an address
+type Signer interface {
+	// Address returns the address the signer signs for
+	Address() common.Address
+	// SignDigest returns the 65 bytes [R || S || V] signature of a 32 bytes digest, where V
+	// is 0 or 1
+	SignDigest(digest []byte) ([]byte, error)
+}
+
+// Config selects the signer of the server. Type is "keystore" for a keystore file whose
+// passphrase is read from the PassphraseEnv environment variable or the PassphraseFile file,
+// "external" for the key of Address held by the signing process listening on Socket, or
+// "memory" for a random key generated at startup, for development
+type Config struct {
+	Type           string `mapstructure:"type"`
+	Keystore       string `mapstructure:"keystore"`
+	PassphraseEnv  string `mapstructure:"passphrase_env"`
+	PassphraseFile string `mapstructure:"passphrase_file"`
+	Socket         string `mapstructure:"socket"`
+	Address        string `mapstructure:"address"`
+}
+
+// New returns the signer of a config
+func New(config Config) (Signer, error) {
+	switch config.Type {
+	case TypeKeystore:
+		passphrase, err := ReadPassphrase(config.PassphraseEnv, config.PassphraseFile)
+		if err != nil {
+			return nil, err
+		}
+
+		return OpenKeystore(config.Keystore, passphrase)
+
+	case TypeExternal:
+		if !common.IsHexAddress(config.Address) {
+			return nil, errors.New("The address of the external signer is invalid")
+		}
+
+		return NewExternalSigner(config.Socket, common.HexToAddress(config.Address))
+
+	case TypeMemory, "":
+		return NewRandomMemorySigner()
+
+	default:
+		return nil, errors.New("Unknown signer type " + config.Type)
+	}
+}
+
+// SignTx signs a transaction with s
+func SignTx(s Signer, txSigner types.Signer, tx *types.Transaction) (*types.Transaction, error) {
+	sig, err := s.SignDigest(txSigner.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.WithSignature(txSigner, sig)
+}
+
+// TransactOpts returns the options of the contract bindings for the transactions sent and
+// signed by s
+func TransactOpts(s Signer) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: s.Address(),
+		Signer: func(txSigner types.Signer, addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != s.Address() {
+				return nil, errors.New("Not authorized to sign this account")
+			}
+
+			return SignTx(s, txSigner, tx)
+		},
+	}
+}
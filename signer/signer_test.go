@@ -0,0 +1,113 @@
+package signer
+
+import (
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recoverAddress(t *testing.T, digest []byte, sig []byte) common.Address {
+	pub, err := crypto.SigToPub(digest, sig)
+	require.NoError(t, err)
+
+	return crypto.PubkeyToAddress(*pub)
+}
+
+func TestMemorySigner(t *testing.T) {
+	s, err := NewRandomMemorySigner()
+	require.NoError(t, err)
+
+	digest := crypto.Keccak256([]byte("digest"))
+	sig, err := s.SignDigest(digest)
+	require.NoError(t, err)
+
+	assert.Len(t, sig, 65)
+	assert.Equal(t, s.Address(), recoverAddress(t, digest, sig))
+}
+
+func TestKeystore(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	keyJSON, err := EncryptKey(key, "passphrase")
+	require.NoError(t, err)
+
+	_, err = DecryptKey(keyJSON, "wrong")
+	assert.Error(t, err)
+
+	dir, _ := ioutil.TempDir("", "signer")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "key.json")
+	ioutil.WriteFile(path, keyJSON, 0600)
+
+	s, err := OpenKeystore(path, "passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), s.Address())
+}
+
+func TestReadPassphrase(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "signer")
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "passphrase")
+	ioutil.WriteFile(file, []byte("from file\n"), 0600)
+
+	os.Setenv("TOMOX_TEST_PASSPHRASE", "from env")
+	defer os.Unsetenv("TOMOX_TEST_PASSPHRASE")
+
+	passphrase, err := ReadPassphrase("TOMOX_TEST_PASSPHRASE", file)
+	require.NoError(t, err)
+	assert.Equal(t, "from env", passphrase)
+
+	passphrase, err = ReadPassphrase("TOMOX_TEST_MISSING", file)
+	require.NoError(t, err)
+	assert.Equal(t, "from file", passphrase)
+
+	_, err = ReadPassphrase("TOMOX_TEST_MISSING", "")
+	assert.Error(t, err)
+}
+
+func TestExternalSigner(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "signer")
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "signer.ipc")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer l.Close()
+
+	key, _ := NewRandomMemorySigner()
+	go Serve(l, key)
+
+	_, err = NewExternalSigner(socket, common.HexToAddress("0x1"))
+	assert.Error(t, err)
+
+	s, err := NewExternalSigner(socket, key.Address())
+	require.NoError(t, err)
+	defer s.Close()
+
+	digest := crypto.Keccak256([]byte("digest"))
+	sig, err := s.SignDigest(digest)
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), recoverAddress(t, digest, sig))
+
+	tx := types.NewTransaction(0, common.HexToAddress("0x2"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	opts := TransactOpts(s)
+	signed, err := opts.Signer(types.HomesteadSigner{}, s.Address(), tx)
+	require.NoError(t, err)
+
+	from, err := types.Sender(types.HomesteadSigner{}, signed)
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), from)
+
+	_, err = opts.Signer(types.HomesteadSigner{}, common.HexToAddress("0x2"), tx)
+	assert.Error(t, err)
+}
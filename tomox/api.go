@@ -0,0 +1,134 @@
+package tomox
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// OrderMsg is an order sent to TomoX by tomox_sendOrder, to add or cancel it
+type OrderMsg struct {
+	AccountNonce    hexutil.Uint64 `json:"nonce"    gencodec:"required"`
+	Quantity        hexutil.Big    `json:"quantity,omitempty"`
+	Price           hexutil.Big    `json:"price,omitempty"`
+	ExchangeAddress common.Address `json:"exchangeAddress,omitempty"`
+	UserAddress     common.Address `json:"userAddress,omitempty"`
+	BaseToken       common.Address `json:"baseToken,omitempty"`
+	QuoteToken      common.Address `json:"quoteToken,omitempty"`
+	Status          string         `json:"status,omitempty"`
+	Side            string         `json:"side,omitempty"`
+	Type            string         `json:"type,omitempty"`
+	OrderID         hexutil.Uint64 `json:"orderid,omitempty"`
+	// Signature values
+	V hexutil.Big `json:"v" gencodec:"required"`
+	R hexutil.Big `json:"r" gencodec:"required"`
+	S hexutil.Big `json:"s" gencodec:"required"`
+
+	// This is only used when marshaling to JSON.
+	Hash common.Hash `json:"hash" rlp:"-"`
+}
+
+// LendingOrderMsg is a lending order sent to TomoX by tomox_sendLending, to add, cancel, repay
+// or top up it
+type LendingOrderMsg struct {
+	AccountNonce    hexutil.Uint64 `json:"nonce"    gencodec:"required"`
+	Quantity        hexutil.Big    `json:"quantity,omitempty"`
+	RelayerAddress  common.Address `json:"relayerAddress,omitempty"`
+	UserAddress     common.Address `json:"userAddress,omitempty"`
+	CollateralToken common.Address `json:"collateralToken,omitempty"`
+	LendingToken    common.Address `json:"lendingToken,omitempty"`
+	Interest        hexutil.Uint64 `json:"interest,omitempty"`
+	Term            hexutil.Uint64 `json:"term,omitempty"`
+	Status          string         `json:"status,omitempty"`
+	Side            string         `json:"side,omitempty"`
+	Type            string         `json:"type,omitempty"`
+	LendingID       hexutil.Uint64 `json:"lendingID,omitempty"`
+	LendingTradeID  hexutil.Uint64 `json:"tradeId,omitempty"`
+	AutoTopUp       bool           `json:"autoTopUp,omitempty"`
+	// Signature values
+	V hexutil.Big `json:"v" gencodec:"required"`
+	R hexutil.Big `json:"r" gencodec:"required"`
+	S hexutil.Big `json:"s" gencodec:"required"`
+
+	// This is only used when marshaling to JSON.
+	Hash common.Hash `json:"hash" rlp:"-"`
+}
+
+// SendOrder adds or cancels an order
+func (c *Client) SendOrder(ctx context.Context, msg *OrderMsg) error {
+	var result interface{}
+	return c.Call(ctx, &result, "tomox_sendOrder", msg)
+}
+
+// SendLending adds, cancels, repays or tops up a lending order
+func (c *Client) SendLending(ctx context.Context, msg *LendingOrderMsg) error {
+	var result interface{}
+	return c.Call(ctx, &result, "tomox_sendLending", msg)
+}
+
+// GetOrderCount returns the number of orders sent by an address, which is the nonce of its
+// next order
+func (c *Client) GetOrderCount(ctx context.Context, addr common.Address) (uint64, error) {
+	var result hexutil.Uint64
+	err := c.Call(ctx, &result, "tomox_getOrderCount", addr)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(result), nil
+}
+
+// GetLendingOrderCount returns the number of lending orders sent by an address, which is the
+// nonce of its next lending order
+func (c *Client) GetLendingOrderCount(ctx context.Context, addr common.Address) (uint64, error) {
+	var result hexutil.Uint64
+	err := c.Call(ctx, &result, "tomox_getLendingOrderCount", addr)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(result), nil
+}
+
+// GetAsks returns the amount of the asks of a pair at each price point
+func (c *Client) GetAsks(ctx context.Context, baseToken, quoteToken common.Address) (map[string]*big.Int, error) {
+	return c.getAmounts(ctx, "tomox_getAsks", baseToken.Hex(), quoteToken.Hex())
+}
+
+// GetBids returns the amount of the bids of a pair at each price point
+func (c *Client) GetBids(ctx context.Context, baseToken, quoteToken common.Address) (map[string]*big.Int, error) {
+	return c.getAmounts(ctx, "tomox_getBids", baseToken.Hex(), quoteToken.Hex())
+}
+
+// GetInvests returns the amount of the invests of a lending token and term at each interest
+func (c *Client) GetInvests(ctx context.Context, lendingToken common.Address, term uint64) (map[string]*big.Int, error) {
+	return c.getAmounts(ctx, "tomox_getInvests", lendingToken.Hex(), term)
+}
+
+// GetBorrows returns the amount of the borrows of a lending token and term at each interest
+func (c *Client) GetBorrows(ctx context.Context, lendingToken common.Address, term uint64) (map[string]*big.Int, error) {
+	return c.getAmounts(ctx, "tomox_getBorrows", lendingToken.Hex(), term)
+}
+
+func (c *Client) getAmounts(ctx context.Context, method string, args ...interface{}) (map[string]*big.Int, error) {
+	result := map[string]*big.Int{}
+	err := c.Call(ctx, &result, method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetLastEpochPrice returns the price of a pair at the end of the last epoch
+func (c *Client) GetLastEpochPrice(ctx context.Context, baseToken, quoteToken common.Address) (*big.Int, error) {
+	var result hexutil.Big
+	err := c.Call(ctx, &result, "tomox_getLastEpochPrice", baseToken, quoteToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.ToInt(), nil
+}
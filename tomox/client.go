@@ -0,0 +1,277 @@
+package tomox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils"
+)
+
+const (
+	// DefaultTimeout is the timeout of each call when the client is created without one
+	DefaultTimeout = 10 * time.Second
+
+	// HealthCheckInterval is the interval between two health checks of the nodes
+	HealthCheckInterval = 10 * time.Second
+)
+
+var logger = utils.Logger
+
+// ErrNoNode is returned by the calls of a client created without any node URL
+var ErrNoNode = errors.New("No TomoX node is configured")
+
+// node is a TomoX node, with the connection to it which is dialed on its first call and then
+// reused by all the calls
+type node struct {
+	url string
+
+	mu        sync.Mutex
+	client    *rpc.Client
+	healthy   bool
+	err       error
+	checkedAt time.Time
+}
+
+// conn returns the connection to the node, dialing it on the first call. The HTTP connections
+// are kept alive between the calls
+func (n *node) conn() (*rpc.Client, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.client == nil {
+		client, err := rpc.DialHTTP(n.url)
+		if err != nil {
+			return nil, err
+		}
+
+		n.client = client
+	}
+
+	return n.client, nil
+}
+
+// setHealth records the result of the last call or health check of the node. A node that
+// returned a JSON-RPC error could be reached, so it is healthy
+func (n *node) setHealth(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := err.(rpc.Error); ok {
+		err = nil
+	}
+
+	n.healthy = err == nil
+	n.err = err
+	n.checkedAt = time.Now()
+}
+
+func (n *node) isHealthy() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.healthy
+}
+
+func (n *node) status() *types.NodeStatus {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	s := &types.NodeStatus{
+		URL:       n.url,
+		Healthy:   n.healthy,
+		CheckedAt: n.checkedAt,
+	}
+
+	if n.err != nil {
+		s.Error = n.err.Error()
+	}
+
+	return s
+}
+
+func (n *node) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.client != nil {
+		n.client.Close()
+		n.client = nil
+	}
+}
+
+// Client calls the tomox_* and tomox_lending* RPC methods of a list of TomoX nodes. It keeps one
+// connection to each node, and sends each call to the first healthy node in the order of the
+// list. A call fails over to the next node when a node can not be reached or does not answer
+// in time, but not when the node returns an error. The nodes that failed are checked in the
+// background, and get the calls again once they answer
+type Client struct {
+	nodes   []*node
+	timeout time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// NewClient returns a client of the TomoX nodes of urls, whose calls are cancelled after
+// timeout, or DefaultTimeout if it is not positive. The nodes are not dialed until they are
+// called
+func NewClient(urls []string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	c := &Client{
+		timeout: timeout,
+		stop:    make(chan struct{}),
+	}
+
+	for _, url := range urls {
+		c.nodes = append(c.nodes, &node{url: url, healthy: true})
+	}
+
+	return c
+}
+
+// Call calls method with args and decodes the result into result. The call is cancelled after
+// the timeout of the client, or sooner if ctx is done
+func (c *Client) Call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if len(c.nodes) == 0 {
+		return ErrNoNode
+	}
+
+	var err error
+	for _, n := range c.ordered() {
+		err = c.call(ctx, n, result, method, args...)
+		if err == nil {
+			return nil
+		}
+
+		if _, ok := err.(rpc.Error); ok || ctx.Err() != nil {
+			return err
+		}
+
+		logger.Warningf("TomoX node %s failed to answer %s: %v", n.url, method, err)
+	}
+
+	return err
+}
+
+func (c *Client) call(ctx context.Context, n *node, result interface{}, method string, args ...interface{}) error {
+	conn, err := n.conn()
+	if err != nil {
+		n.setHealth(err)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err = conn.CallContext(ctx, result, method, args...)
+	n.setHealth(err)
+
+	return err
+}
+
+// ordered returns the healthy nodes followed by the others, so that a call is still tried on
+// all the nodes when none is healthy
+func (c *Client) ordered() []*node {
+	healthy := []*node{}
+	unhealthy := []*node{}
+
+	for _, n := range c.nodes {
+		if n.isHealthy() {
+			healthy = append(healthy, n)
+		} else {
+			unhealthy = append(unhealthy, n)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// RPC returns the connection to the first healthy node, for the callers which need a
+// *rpc.Client, such as an ethclient.Client
+func (c *Client) RPC() (*rpc.Client, error) {
+	if len(c.nodes) == 0 {
+		return nil, ErrNoNode
+	}
+
+	var err error
+	for _, n := range c.ordered() {
+		var conn *rpc.Client
+		conn, err = n.conn()
+		if err == nil {
+			return conn, nil
+		}
+
+		n.setHealth(err)
+	}
+
+	return nil, err
+}
+
+// CheckHealth asks the block number to each node and records whether it answered
+func (c *Client) CheckHealth(ctx context.Context) {
+	wg := sync.WaitGroup{}
+
+	for _, n := range c.nodes {
+		wg.Add(1)
+
+		go func(n *node) {
+			defer wg.Done()
+
+			var result interface{}
+			err := c.call(ctx, n, &result, "eth_blockNumber")
+			if err != nil {
+				logger.Warningf("TomoX node %s is unhealthy: %v", n.url, err)
+			}
+		}(n)
+	}
+
+	wg.Wait()
+}
+
+// StartHealthChecks checks the health of the nodes every interval until the client is closed
+func (c *Client) StartHealthChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		c.CheckHealth(context.Background())
+
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				c.CheckHealth(context.Background())
+			}
+		}
+	}()
+}
+
+// Nodes returns the health of the nodes
+func (c *Client) Nodes() []*types.NodeStatus {
+	res := []*types.NodeStatus{}
+	for _, n := range c.nodes {
+		res = append(res, n.status())
+	}
+
+	return res
+}
+
+// Close stops the health checks and closes the connections to the nodes
+func (c *Client) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+
+	for _, n := range c.nodes {
+		n.close()
+	}
+}
@@ -0,0 +1,111 @@
+package tomox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestNode returns a JSON-RPC server answering every call with result, or with a JSON-RPC
+// error when result is nil, after delay
+func newTestNode(t *testing.T, result interface{}, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := map[string]interface{}{}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			t.Error(err)
+		}
+
+		time.Sleep(delay)
+
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req["id"]}
+		if result != nil {
+			res["result"] = result
+		} else {
+			res["error"] = map[string]interface{}{"code": -32000, "message": "invalid order"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+}
+
+func TestClientFailover(t *testing.T) {
+	down := newTestNode(t, "0x1", 0)
+	down.Close()
+
+	up := newTestNode(t, "0x5", 0)
+	defer up.Close()
+
+	c := NewClient([]string{down.URL, up.URL}, time.Second)
+	defer c.Close()
+
+	n, err := c.GetOrderCount(context.Background(), common.HexToAddress("0x1"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), n)
+
+	nodes := c.Nodes()
+	assert.False(t, nodes[0].Healthy)
+	assert.NotEmpty(t, nodes[0].Error)
+	assert.True(t, nodes[1].Healthy)
+
+	// the unhealthy node is called last
+	assert.Equal(t, up.URL, c.ordered()[0].url)
+}
+
+func TestClientTimeout(t *testing.T) {
+	slow := newTestNode(t, "0x1", 200*time.Millisecond)
+	defer slow.Close()
+
+	up := newTestNode(t, "0x2", 0)
+	defer up.Close()
+
+	c := NewClient([]string{slow.URL, up.URL}, 50*time.Millisecond)
+	defer c.Close()
+
+	n, err := c.GetOrderCount(context.Background(), common.HexToAddress("0x1"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), n)
+	assert.False(t, c.Nodes()[0].Healthy)
+}
+
+func TestClientNodeError(t *testing.T) {
+	failing := newTestNode(t, nil, 0)
+	defer failing.Close()
+
+	up := newTestNode(t, map[string]interface{}{}, 0)
+	defer up.Close()
+
+	c := NewClient([]string{failing.URL, up.URL}, time.Second)
+	defer c.Close()
+
+	// the error of the node is returned, and the order is not sent to another node
+	err := c.SendOrder(context.Background(), &OrderMsg{})
+	assert.EqualError(t, err, "invalid order")
+	assert.True(t, c.Nodes()[0].Healthy)
+}
+
+func TestClientNoNode(t *testing.T) {
+	c := NewClient(nil, 0)
+
+	_, err := c.GetOrderCount(context.Background(), common.HexToAddress("0x1"))
+	assert.Equal(t, ErrNoNode, err)
+}
+
+func TestGetAsks(t *testing.T) {
+	up := newTestNode(t, map[string]interface{}{"1000": 25}, 0)
+	defer up.Close()
+
+	c := NewClient([]string{up.URL}, time.Second)
+	defer c.Close()
+
+	asks, err := c.GetAsks(context.Background(), common.HexToAddress("0x1"), common.HexToAddress("0x2"))
+	assert.Nil(t, err)
+	assert.Equal(t, "25", asks["1000"].String())
+}
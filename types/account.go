@@ -6,13 +6,14 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/go-ozzo/ozzo-validation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Account corresponds to a single Ethereum address. It contains a list of token balances for that address
 type Account struct {
-	ID             bson.ObjectId                    `json:"-" bson:"_id"`
+	ID             primitive.ObjectID               `json:"-" bson:"_id"`
 	Address        common.Address                   `json:"address" bson:"address"`
 	TokenBalances  map[common.Address]*TokenBalance `json:"tokenBalances" bson:"tokenBalances"`
 	FavoriteTokens map[common.Address]bool          `json:"favoriteTokens" bson:"favoriteTokens"`
@@ -21,8 +22,8 @@ type Account struct {
 	UpdatedAt      time.Time                        `json:"updatedAt" bson:"updatedAt"`
 }
 
-// GetBSON implements bson.Getter
-func (a *Account) GetBSON() (interface{}, error) {
+// MarshalBSON implements bson.Marshaler
+func (a *Account) MarshalBSON() ([]byte, error) {
 	ar := AccountRecord{
 		IsBlocked: a.IsBlocked,
 		Address:   a.Address.Hex(),
@@ -53,20 +54,20 @@ func (a *Account) GetBSON() (interface{}, error) {
 
 	ar.FavoriteTokens = favoriteTokens
 
-	if a.ID.Hex() == "" {
-		ar.ID = bson.NewObjectId()
+	if a.ID.IsZero() {
+		ar.ID = primitive.NewObjectID()
 	} else {
 		ar.ID = a.ID
 	}
 
-	return ar, nil
+	return bson.Marshal(ar)
 }
 
-// SetBSON implemenets bson.Setter
-func (a *Account) SetBSON(raw bson.Raw) error {
+// UnmarshalBSON implemenets bson.Unmarshaler
+func (a *Account) UnmarshalBSON(data []byte) error {
 	decoded := &AccountRecord{}
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		return err
 	}
@@ -150,8 +151,8 @@ func (a *Account) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	if account["id"] != nil && bson.IsObjectIdHex(account["id"].(string)) {
-		a.ID = bson.ObjectIdHex(account["id"].(string))
+	if account["id"] != nil && primitive.IsValidObjectID(account["id"].(string)) {
+		a.ID, _ = primitive.ObjectIDFromHex(account["id"].(string))
 	}
 
 	if account["address"] != nil {
@@ -224,7 +225,7 @@ func (a Account) Validate() error {
 
 // AccountRecord corresponds to what is stored in the DB. big.Ints are encoded as strings
 type AccountRecord struct {
-	ID             bson.ObjectId                 `json:"id" bson:"_id"`
+	ID             primitive.ObjectID            `json:"id" bson:"_id"`
 	Address        string                        `json:"address" bson:"address"`
 	TokenBalances  map[string]TokenBalanceRecord `json:"tokenBalances" bson:"tokenBalances"`
 	FavoriteTokens map[string]bool               `json:"favoriteTokens" bson:"favoriteTokens"`
@@ -237,7 +238,7 @@ type AccountBSONUpdate struct {
 	*Account
 }
 
-func (a *AccountBSONUpdate) GetBSON() (interface{}, error) {
+func (a *AccountBSONUpdate) MarshalBSON() ([]byte, error) {
 	now := time.Now()
 	tokenBalances := make(map[string]TokenBalanceRecord)
 
@@ -255,11 +256,11 @@ func (a *AccountBSONUpdate) GetBSON() (interface{}, error) {
 
 	set := bson.M{
 		"updatedAt": now,
-		"address":   a.Address,
+		"address":   a.Address.Hex(),
 	}
 
 	setOnInsert := bson.M{
-		"_id":       bson.NewObjectId(),
+		"_id":       primitive.NewObjectID(),
 		"createdAt": now,
 	}
 
@@ -268,7 +269,7 @@ func (a *AccountBSONUpdate) GetBSON() (interface{}, error) {
 		"$setOnInsert": setOnInsert,
 	}
 
-	return update, nil
+	return bson.Marshal(update)
 }
 
 // TokenBalance holds the Balance and the Locked balance values for a single Ethereum token
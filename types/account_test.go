@@ -5,8 +5,9 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestAccountBSON(t *testing.T) {
@@ -33,13 +34,14 @@ func TestAccountBSON(t *testing.T) {
 	}
 
 	account := &Account{
-		ID:      bson.NewObjectId(),
+		ID:      primitive.NewObjectID(),
 		Address: address,
 		TokenBalances: map[common.Address]*TokenBalance{
 			tokenAddress1: tokenBalance1,
 			tokenAddress2: tokenBalance2,
 		},
-		IsBlocked: false,
+		FavoriteTokens: map[common.Address]bool{},
+		IsBlocked:      false,
 	}
 
 	data, err := bson.Marshal(account)
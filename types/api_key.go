@@ -0,0 +1,248 @@
+package types
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/go-ozzo/ozzo-validation"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/signer"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Scopes of the API keys
+const (
+	APIKeyScopeRead    = "read"
+	APIKeyScopeTrade   = "trade"
+	APIKeyScopeLending = "lending"
+)
+
+// ErrAPIKeyNotEncrypted is returned when an API key is stored with a secret which is not
+// encrypted into its keystore
+var ErrAPIKeyNotEncrypted = errors.New("The secret of the API key is not encrypted")
+
+// APIKey lets a bot act for an user address without a wallet signature for each request.
+// The requests are signed with the HMAC-SHA256 of the secret (see APIKey.Sign), and are
+// only accepted for the scopes of the key and, if it has an allowlist, from its IPs.
+// The secret is the hex encoded private key of a secp256k1 key, so that it is stored
+// encrypted in the Keystore in the same format as the wallet keys
+type APIKey struct {
+	ID          primitive.ObjectID `json:"-" bson:"_id"`
+	Key         string             `json:"key" bson:"key"`
+	Secret      string             `json:"secret,omitempty" bson:"secret"`
+	Keystore    string             `json:"-" bson:"keystore"`
+	UserAddress common.Address     `json:"userAddress" bson:"userAddress"`
+	Label       string             `json:"label" bson:"label"`
+	Scopes      []string           `json:"scopes" bson:"scopes"`
+	IPs         []string           `json:"ips" bson:"ips"`
+	Revoked     bool               `json:"revoked" bson:"revoked"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// APIKeyRecord is the struct which is stored in db. Secret is only read, from the keys stored
+// in plaintext before the secrets were encrypted, so that they can be migrated
+type APIKeyRecord struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id"`
+	Key         string             `json:"key" bson:"key"`
+	Secret      string             `json:"-" bson:"secret,omitempty"`
+	Keystore    string             `json:"-" bson:"keystore,omitempty"`
+	UserAddress string             `json:"userAddress" bson:"userAddress"`
+	Label       string             `json:"label" bson:"label"`
+	Scopes      []string           `json:"scopes" bson:"scopes"`
+	IPs         []string           `json:"ips" bson:"ips"`
+	Revoked     bool               `json:"revoked" bson:"revoked"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// APIKeyRequest is the payload of the creation of an API key
+type APIKeyRequest struct {
+	Label  string   `json:"label"`
+	Scopes []string `json:"scopes"`
+	IPs    []string `json:"ips"`
+}
+
+// Validate checks the scopes and the IPs of the request. An IP can also be a CIDR range
+func (r APIKeyRequest) Validate() error {
+	err := validation.ValidateStruct(&r,
+		validation.Field(&r.Scopes, validation.Required),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	for _, s := range r.Scopes {
+		err := validation.Validate(s, validation.In(APIKeyScopeRead, APIKeyScopeTrade, APIKeyScopeLending))
+		if err != nil {
+			return errors.New("Invalid scope " + s)
+		}
+	}
+
+	for _, ip := range r.IPs {
+		if parseIPRange(ip) == nil {
+			return errors.New("Invalid IP " + ip)
+		}
+	}
+
+	return nil
+}
+
+// HasScope returns true if the key has the scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsIP returns true if the key has no allowlist or if the IP is in its allowlist
+func (k *APIKey) AllowsIP(ip net.IP) bool {
+	if len(k.IPs) == 0 {
+		return true
+	}
+
+	if ip == nil {
+		return false
+	}
+
+	for _, s := range k.IPs {
+		if n := parseIPRange(s); n != nil && n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the canonical string of a request with the secret
+func (k *APIKey) Sign(r *SignedRequest) string {
+	mac := hmac.New(sha256.New, []byte(k.Secret))
+	mac.Write([]byte(r.Canonical()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the HMAC signature of a request
+func (k *APIKey) VerifySignature(r *SignedRequest, signature string) bool {
+	return hmac.Equal([]byte(k.Sign(r)), []byte(signature))
+}
+
+// NewAPIKeySecret returns a random secret for an API key
+func NewAPIKeySecret() (string, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(crypto.FromECDSA(key)), nil
+}
+
+// Encrypt encrypts the secret of the key with a passphrase into its keystore
+func (k *APIKey) Encrypt(passphrase string) error {
+	if k.Secret == "" {
+		return nil
+	}
+
+	key, err := crypto.HexToECDSA(k.Secret)
+	if err != nil {
+		return err
+	}
+
+	keyJSON, err := signer.EncryptKey(key, passphrase)
+	if err != nil {
+		return err
+	}
+
+	k.Keystore = string(keyJSON)
+	return nil
+}
+
+// Decrypt decrypts the keystore of the key with a passphrase into its secret
+func (k *APIKey) Decrypt(passphrase string) error {
+	if k.Keystore == "" {
+		return nil
+	}
+
+	key, err := signer.DecryptKey([]byte(k.Keystore), passphrase)
+	if err != nil {
+		return err
+	}
+
+	k.Secret = hex.EncodeToString(crypto.FromECDSA(key))
+	return nil
+}
+
+// parseIPRange parses an IP or a CIDR range into a range, and returns nil if it is invalid
+func parseIPRange(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err == nil {
+		return n
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil
+	}
+
+	bits := 8 * net.IPv6len
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 8 * net.IPv4len
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+}
+
+// MarshalBSON implements bson.Marshaler
+func (k *APIKey) MarshalBSON() ([]byte, error) {
+	if k.Secret != "" && k.Keystore == "" {
+		return nil, ErrAPIKeyNotEncrypted
+	}
+
+	return bson.Marshal(APIKeyRecord{
+		ID:          k.ID,
+		Key:         k.Key,
+		Keystore:    k.Keystore,
+		UserAddress: k.UserAddress.Hex(),
+		Label:       k.Label,
+		Scopes:      k.Scopes,
+		IPs:         k.IPs,
+		Revoked:     k.Revoked,
+		CreatedAt:   k.CreatedAt,
+		UpdatedAt:   k.UpdatedAt,
+	})
+}
+
+// UnmarshalBSON implements bson.Unmarshaler
+func (k *APIKey) UnmarshalBSON(data []byte) error {
+	decoded := &APIKeyRecord{}
+
+	err := bson.Unmarshal(data, decoded)
+	if err != nil {
+		return err
+	}
+
+	k.ID = decoded.ID
+	k.Key = decoded.Key
+	k.Secret = decoded.Secret
+	k.Keystore = decoded.Keystore
+	k.UserAddress = common.HexToAddress(decoded.UserAddress)
+	k.Label = decoded.Label
+	k.Scopes = decoded.Scopes
+	k.IPs = decoded.IPs
+	k.Revoked = decoded.Revoked
+	k.CreatedAt = decoded.CreatedAt
+	k.UpdatedAt = decoded.UpdatedAt
+
+	return nil
+}
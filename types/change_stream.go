@@ -0,0 +1,18 @@
+package types
+
+import "time"
+
+// ChangeStreamStats are the metrics of a change stream consumer. Lag is the time between the
+// change of the last handled event in MongoDB and the end of its handling, and Restarts is the
+// number of times the stream was opened again after an error
+type ChangeStreamStats struct {
+	Name          string    `json:"name"`
+	Running       bool      `json:"running"`
+	Events        uint64    `json:"events"`
+	Errors        uint64    `json:"errors"`
+	Restarts      uint64    `json:"restarts"`
+	LastEventTime time.Time `json:"lastEventTime"`
+	LastHandledAt time.Time `json:"lastHandledAt"`
+	LagMs         int64     `json:"lagMs"`
+	LastError     string    `json:"lastError,omitempty"`
+}
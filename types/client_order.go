@@ -0,0 +1,109 @@
+package types
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ClientOrderStatusPending  = "PENDING"
+	ClientOrderStatusAccepted = "ACCEPTED"
+
+	// ClientOrderTTL is the time a client order ID is kept for, during which it can not be used
+	// for another order of the same user
+	ClientOrderTTL = 24 * time.Hour
+)
+
+var clientOrderIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_\-]{1,64}$`)
+
+// ClientOrder records the order that a client order ID of an user was used for, so that an
+// order is published once when its submission is retried. A PENDING client order is being
+// published, and an ACCEPTED one was published
+type ClientOrder struct {
+	ID            primitive.ObjectID
+	UserAddress   common.Address
+	ClientOrderID string
+	OrderHash     common.Hash
+	Status        string
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+}
+
+// ClientOrderRecord is the BSON representation of a ClientOrder
+type ClientOrderRecord struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	UserAddress   string             `bson:"userAddress"`
+	ClientOrderID string             `bson:"clientOrderId"`
+	OrderHash     string             `bson:"orderHash"`
+	Status        string             `bson:"status"`
+	CreatedAt     time.Time          `bson:"createdAt"`
+	ExpiresAt     time.Time          `bson:"expiresAt"`
+}
+
+// ValidateClientOrderID checks that a client order ID has 1 to 64 letters, digits, - or _
+func ValidateClientOrderID(id string) error {
+	if !clientOrderIDPattern.MatchString(id) {
+		return errors.New("Order 'clientOrderId' parameter should have 1 to 64 letters, digits, '-' or '_'")
+	}
+
+	return nil
+}
+
+// NewClientOrder returns the PENDING client order of an order
+func NewClientOrder(o *Order) *ClientOrder {
+	now := time.Now()
+
+	return &ClientOrder{
+		UserAddress:   o.UserAddress,
+		ClientOrderID: o.ClientOrderID,
+		OrderHash:     o.Hash,
+		Status:        ClientOrderStatusPending,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(ClientOrderTTL),
+	}
+}
+
+// MarshalBSON implements the bson.Marshaler interface
+func (c *ClientOrder) MarshalBSON() ([]byte, error) {
+	r := ClientOrderRecord{
+		ID:            c.ID,
+		UserAddress:   c.UserAddress.Hex(),
+		ClientOrderID: c.ClientOrderID,
+		OrderHash:     c.OrderHash.Hex(),
+		Status:        c.Status,
+		CreatedAt:     c.CreatedAt,
+		ExpiresAt:     c.ExpiresAt,
+	}
+
+	if c.ID.IsZero() {
+		r.ID = primitive.NewObjectID()
+	}
+
+	return bson.Marshal(r)
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface
+func (c *ClientOrder) UnmarshalBSON(data []byte) error {
+	decoded := new(ClientOrderRecord)
+
+	err := bson.Unmarshal(data, decoded)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	c.ID = decoded.ID
+	c.UserAddress = common.HexToAddress(decoded.UserAddress)
+	c.ClientOrderID = decoded.ClientOrderID
+	c.OrderHash = common.HexToHash(decoded.OrderHash)
+	c.Status = decoded.Status
+	c.CreatedAt = decoded.CreatedAt
+	c.ExpiresAt = decoded.ExpiresAt
+
+	return nil
+}
@@ -1,6 +1,15 @@
 package types
 
+import "time"
+
 type KeyValue struct {
 	Key   string      `json:"key" bson"key"`
 	Value interface{} `json:"value" bson "value"`
 }
+
+// Lease is the lease of a name in the config collection. The owner holds it until ExpiresAt
+type Lease struct {
+	Key       string    `json:"key" bson:"key"`
+	Owner     string    `json:"owner" bson:"owner"`
+	ExpiresAt time.Time `json:"expiresAt" bson:"expiresAt"`
+}
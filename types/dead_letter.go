@@ -0,0 +1,19 @@
+package types
+
+import "time"
+
+// DeadLetter is a message rejected by the consumers of a RabbitMQ queue after its retries. Queue
+// is the queue it was rejected from, and Reason is why RabbitMQ dead-lettered it
+type DeadLetter struct {
+	Queue   string    `json:"queue"`
+	Reason  string    `json:"reason"`
+	Retries int       `json:"retries"`
+	Time    time.Time `json:"time"`
+	Body    string    `json:"body"`
+}
+
+// DeadLetters are the first messages of the dead-letter queue, out of Count messages
+type DeadLetters struct {
+	Count    int           `json:"count"`
+	Messages []*DeadLetter `json:"messages"`
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto/sha3"
-	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type AssetCode string
@@ -74,12 +74,12 @@ type AddressAssociationWebsocketPayload struct {
 
 // AddressAssociationRecord is the object that will be saved in the database
 type AddressAssociationRecord struct {
-	ID                bson.ObjectId `json:"id" bson:"_id"`
-	AddressIndex      uint64        `json:"addressIndex" bson:"addressIndex"`
-	Chain             Chain         `json:"chain" bson:"chain"`
-	Address           string        `json:"address" bson:"address"`
-	Status            string        `json:"status" bson:"status"`
-	AssociatedAddress string        `json:"associatedAddress" bson:"associatedAddress"`
+	ID                primitive.ObjectID `json:"id" bson:"_id"`
+	AddressIndex      uint64             `json:"addressIndex" bson:"addressIndex"`
+	Chain             Chain              `json:"chain" bson:"chain"`
+	Address           string             `json:"address" bson:"address"`
+	Status            string             `json:"status" bson:"status"`
+	AssociatedAddress string             `json:"associatedAddress" bson:"associatedAddress"`
 	// this is the last transaction envelopes, should move to seperated collection
 	// We also have it from blockchain transactions
 	TxEnvelopes       []string  `json:"txEnvelopes" bson:"txEnvelopes"`
@@ -10,9 +10,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/sha3"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -33,29 +34,29 @@ const (
 
 // LendingOrder contains the data related to an lending sent by the user
 type LendingOrder struct {
-	ID              bson.ObjectId  `json:"id" bson:"_id"`
-	Quantity        *big.Int       `bson:"quantity" json:"quantity"`
-	Interest        uint64         `bson:"interest" json:"interest"`
-	Term            uint64         `bson:"term" json:"term"`
-	Side            string         `bson:"side" json:"side"`
-	Type            string         `bson:"type" json:"type"`
-	LendingToken    common.Address `bson:"lendingToken" json:"lendingToken"`
-	CollateralToken common.Address `bson:"collateralToken" json:"collateralToken"`
-	FilledAmount    *big.Int       `bson:"filledAmount" json:"filledAmount"`
-	Status          string         `bson:"status" json:"status"`
-	UserAddress     common.Address `bson:"userAddress" json:"userAddress"`
-	RelayerAddress  common.Address `bson:"relayer" json:"relayerAddress"`
-	Signature       *Signature     `bson:"signature" json:"signature"`
-	Hash            common.Hash    `bson:"hash" json:"hash"`
-	TxHash          common.Hash    `bson:"txHash" json:"txHash"`
-	Nonce           *big.Int       `bson:"nonce" json:"nonce"`
-	CreatedAt       time.Time      `bson:"createdAt" json:"createdAt"`
-	UpdatedAt       time.Time      `bson:"updatedAt" json:"updatedAt"`
-	LendingID       uint64         `bson:"lendingId" json:"lendingId"`
-	ExtraData       string         `bson:"extraData" json:"extraData"`
-	LendingTradeID  uint64         `bson:"tradeId" json:"tradeId"`
-	AutoTopUp       uint64         `json:"autoTopUp" json:"autoTopUp"`
-	Key             string         `json:"key" bson:"key"`
+	ID              primitive.ObjectID `json:"id" bson:"_id"`
+	Quantity        *big.Int           `bson:"quantity" json:"quantity"`
+	Interest        uint64             `bson:"interest" json:"interest"`
+	Term            uint64             `bson:"term" json:"term"`
+	Side            string             `bson:"side" json:"side"`
+	Type            string             `bson:"type" json:"type"`
+	LendingToken    common.Address     `bson:"lendingToken" json:"lendingToken"`
+	CollateralToken common.Address     `bson:"collateralToken" json:"collateralToken"`
+	FilledAmount    *big.Int           `bson:"filledAmount" json:"filledAmount"`
+	Status          string             `bson:"status" json:"status"`
+	UserAddress     common.Address     `bson:"userAddress" json:"userAddress"`
+	RelayerAddress  common.Address     `bson:"relayer" json:"relayerAddress"`
+	Signature       *Signature         `bson:"signature" json:"signature"`
+	Hash            common.Hash        `bson:"hash" json:"hash"`
+	TxHash          common.Hash        `bson:"txHash" json:"txHash"`
+	Nonce           *big.Int           `bson:"nonce" json:"nonce"`
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
+	LendingID       uint64             `bson:"lendingId" json:"lendingId"`
+	ExtraData       string             `bson:"extraData" json:"extraData"`
+	LendingTradeID  uint64             `bson:"tradeId" json:"tradeId"`
+	AutoTopUp       uint64             `json:"autoTopUp" json:"autoTopUp"`
+	Key             string             `json:"key" bson:"key"`
 }
 
 // LendingRes use for api
@@ -217,6 +218,20 @@ func (o *LendingOrder) VerifySignature() (bool, error) {
 	return true, nil
 }
 
+// Sign first calculates the lending order hash, then computes a signature of this hash
+// with the given wallet
+func (o *LendingOrder) Sign(w *Wallet) error {
+	hash := o.ComputeHash()
+	sig, err := w.SignHash(hash)
+	if err != nil {
+		return err
+	}
+
+	o.Hash = hash
+	o.Signature = sig
+	return nil
+}
+
 // Process pre-process data
 func (o *LendingOrder) Process() error {
 	if o.FilledAmount == nil {
@@ -289,8 +304,8 @@ func (o *LendingOrder) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		return err
 	}
-	if lending["id"] != nil && bson.IsObjectIdHex(lending["id"].(string)) {
-		o.ID = bson.ObjectIdHex(lending["id"].(string))
+	if lending["id"] != nil && primitive.IsValidObjectID(lending["id"].(string)) {
+		o.ID, _ = primitive.ObjectIDFromHex(lending["id"].(string))
 	}
 
 	if lending["relayerAddress"] != nil {
@@ -397,8 +412,8 @@ func (o *LendingOrder) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// GetBSON return bson
-func (o *LendingOrder) GetBSON() (interface{}, error) {
+// MarshalBSON return bson
+func (o *LendingOrder) MarshalBSON() ([]byte, error) {
 	or := LendingRecord{
 		RelayerAddress:  o.RelayerAddress.Hex(),
 		UserAddress:     o.UserAddress.Hex(),
@@ -418,8 +433,8 @@ func (o *LendingOrder) GetBSON() (interface{}, error) {
 		Key:             o.Key,
 	}
 
-	if o.ID.Hex() == "" {
-		or.ID = bson.NewObjectId()
+	if o.ID.IsZero() {
+		or.ID = primitive.NewObjectID()
 	} else {
 		or.ID = o.ID
 	}
@@ -436,35 +451,35 @@ func (o *LendingOrder) GetBSON() (interface{}, error) {
 		}
 	}
 
-	return or, nil
+	return bson.Marshal(or)
 }
 
-// SetBSON for database
-func (o *LendingOrder) SetBSON(raw bson.Raw) error {
+// UnmarshalBSON for database
+func (o *LendingOrder) UnmarshalBSON(data []byte) error {
 	decoded := new(struct {
-		ID              bson.ObjectId    `json:"id,omitempty" bson:"_id"`
-		RelayerAddress  string           `json:"relayerAddress" bson:"relayer"`
-		UserAddress     string           `json:"userAddress" bson:"userAddress"`
-		CollateralToken string           `json:"collateralToken" bson:"collateralToken"`
-		LendingToken    string           `json:"lendingToken" bson:"lendingToken"`
-		Term            string           `json:"term" bson:"term"`
-		Interest        string           `json:"interest" bson:"interest"`
-		Status          string           `json:"status" bson:"status"`
-		Side            string           `json:"side" bson:"side"`
-		Type            string           `json:"type" bson:"type"`
-		Hash            string           `json:"hash" bson:"hash"`
-		Quantity        string           `json:"quantity" bson:"quantity"`
-		FilledAmount    string           `json:"filledAmount" bson:"filledAmount"`
-		Nonce           string           `json:"nonce" bson:"nonce"`
-		Signature       *SignatureRecord `json:"signature" bson:"signature"`
-		CreatedAt       time.Time        `json:"createdAt" bson:"createdAt"`
-		UpdatedAt       time.Time        `json:"updatedAt" bson:"updatedAt"`
-		LendingID       string           `json:"lendingId" bson:"lendingId"`
-		TradeID         string           `json:"tradeId" bson:"tradeId"`
-		Key             string           `json:"key" bson:"key"`
+		ID              primitive.ObjectID `json:"id,omitempty" bson:"_id"`
+		RelayerAddress  string             `json:"relayerAddress" bson:"relayer"`
+		UserAddress     string             `json:"userAddress" bson:"userAddress"`
+		CollateralToken string             `json:"collateralToken" bson:"collateralToken"`
+		LendingToken    string             `json:"lendingToken" bson:"lendingToken"`
+		Term            string             `json:"term" bson:"term"`
+		Interest        string             `json:"interest" bson:"interest"`
+		Status          string             `json:"status" bson:"status"`
+		Side            string             `json:"side" bson:"side"`
+		Type            string             `json:"type" bson:"type"`
+		Hash            string             `json:"hash" bson:"hash"`
+		Quantity        string             `json:"quantity" bson:"quantity"`
+		FilledAmount    string             `json:"filledAmount" bson:"filledAmount"`
+		Nonce           string             `json:"nonce" bson:"nonce"`
+		Signature       *SignatureRecord   `json:"signature" bson:"signature"`
+		CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+		UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+		LendingID       string             `json:"lendingId" bson:"lendingId"`
+		TradeID         string             `json:"tradeId" bson:"tradeId"`
+		Key             string             `json:"key" bson:"key"`
 	})
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -529,28 +544,28 @@ func (o *LendingOrder) SetBSON(raw bson.Raw) error {
 
 // LendingRecord is the object that will be saved in the database
 type LendingRecord struct {
-	ID              bson.ObjectId    `json:"id" bson:"_id"`
-	UserAddress     string           `json:"userAddress" bson:"userAddress"`
-	RelayerAddress  string           `json:"relayerAddress" bson:"relayer"`
-	CollateralToken string           `json:"collateralToken" bson:"collateralToken"`
-	LendingToken    string           `json:"lendingToken" bson:"lendingToken"`
-	Term            string           `json:"term" bson:"term"`
-	Interest        string           `json:"interest" bson:"interest"`
-	Status          string           `json:"status" bson:"status"`
-	Side            string           `json:"side" bson:"side"`
-	Type            string           `json:"type" bson:"type"`
-	Hash            string           `json:"hash" bson:"hash"`
-	Quantity        string           `json:"quantity" bson:"quantity"`
-	FilledAmount    string           `json:"filledAmount" bson:"filledAmount"`
-	Nonce           string           `json:"nonce" bson:"nonce"`
-	Signature       *SignatureRecord `json:"signature,omitempty" bson:"signature"`
-	CreatedAt       time.Time        `json:"createdAt" bson:"createdAt"`
-	UpdatedAt       time.Time        `json:"updatedAt" bson:"updatedAt"`
-	LendingID       string           `json:"lendingId,omitempty" bson:"lendingId"`
-	NextOrder       string           `json:"nextOrder,omitempty" bson:"nextOrder"`
-	PrevOrder       string           `json:"prevOrder,omitempty" bson:"prevOrder"`
-	OrderList       string           `json:"orderList,omitempty" bson:"orderList"`
-	Key             string           `json:"key" bson:"key"`
+	ID              primitive.ObjectID `json:"id" bson:"_id"`
+	UserAddress     string             `json:"userAddress" bson:"userAddress"`
+	RelayerAddress  string             `json:"relayerAddress" bson:"relayer"`
+	CollateralToken string             `json:"collateralToken" bson:"collateralToken"`
+	LendingToken    string             `json:"lendingToken" bson:"lendingToken"`
+	Term            string             `json:"term" bson:"term"`
+	Interest        string             `json:"interest" bson:"interest"`
+	Status          string             `json:"status" bson:"status"`
+	Side            string             `json:"side" bson:"side"`
+	Type            string             `json:"type" bson:"type"`
+	Hash            string             `json:"hash" bson:"hash"`
+	Quantity        string             `json:"quantity" bson:"quantity"`
+	FilledAmount    string             `json:"filledAmount" bson:"filledAmount"`
+	Nonce           string             `json:"nonce" bson:"nonce"`
+	Signature       *SignatureRecord   `json:"signature,omitempty" bson:"signature"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+	LendingID       string             `json:"lendingId,omitempty" bson:"lendingId"`
+	NextOrder       string             `json:"nextOrder,omitempty" bson:"nextOrder"`
+	PrevOrder       string             `json:"prevOrder,omitempty" bson:"prevOrder"`
+	OrderList       string             `json:"orderList,omitempty" bson:"orderList"`
+	Key             string             `json:"key" bson:"key"`
 }
 
 // LendingOrderChangeEvent data format for changing data records
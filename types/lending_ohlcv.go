@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"math/big"
 	"strconv"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/tomochain/tomox-sdk/utils/math"
@@ -21,6 +22,8 @@ type LendingTick struct {
 	Timestamp int64     `json:"timestamp,omitempty" bson:"timestamp"`
 	Duration  int64     `json:"duration" bson:"duration"`
 	Unit      string    `json:"unit" bson:"unit"`
+	// CloseTime is the time of the last trade included in the tick
+	CloseTime time.Time `json:"-" bson:"closeTime"`
 }
 
 // LendingID is the subdocument for aggregate grouping for OHLCV data
@@ -109,3 +112,64 @@ func (t *LendingTick) AddressCode() string {
 	code := strconv.FormatUint(t.LendingID.Term, 10) + "::" + t.LendingID.LendingToken.Hex()
 	return code
 }
+
+// LendingTickRecord is the BSON representation of a LendingTick stored in the lending_ohlcv
+// collection. A tick is identified by its term, lending token, duration, unit and timestamp.
+// UpdatedAt is the time the tick was last stored
+type LendingTickRecord struct {
+	Name         string    `bson:"name"`
+	Term         uint64    `bson:"term"`
+	LendingToken string    `bson:"lendingToken"`
+	Duration     int64     `bson:"duration"`
+	Unit         string    `bson:"unit"`
+	Timestamp    int64     `bson:"timestamp"`
+	Open         uint64    `bson:"open"`
+	High         uint64    `bson:"high"`
+	Low          uint64    `bson:"low"`
+	Close        uint64    `bson:"close"`
+	Volume       string    `bson:"volume"`
+	Count        string    `bson:"count"`
+	CloseTime    time.Time `bson:"closeTime"`
+	UpdatedAt    time.Time `bson:"updatedAt"`
+}
+
+// NewLendingTickRecord returns the record of a lending tick
+func NewLendingTickRecord(t *LendingTick) *LendingTickRecord {
+	return &LendingTickRecord{
+		Name:         t.LendingID.Name,
+		Term:         t.LendingID.Term,
+		LendingToken: t.LendingID.LendingToken.Hex(),
+		Duration:     t.Duration,
+		Unit:         t.Unit,
+		Timestamp:    t.Timestamp,
+		Open:         t.Open,
+		High:         t.High,
+		Low:          t.Low,
+		Close:        t.Close,
+		Volume:       t.Volume.String(),
+		Count:        t.Count.String(),
+		CloseTime:    t.CloseTime,
+		UpdatedAt:    time.Now(),
+	}
+}
+
+// LendingTick returns the lending tick of a record
+func (r *LendingTickRecord) LendingTick() *LendingTick {
+	return &LendingTick{
+		LendingID: LendingID{
+			Name:         r.Name,
+			Term:         r.Term,
+			LendingToken: common.HexToAddress(r.LendingToken),
+		},
+		Open:      r.Open,
+		High:      r.High,
+		Low:       r.Low,
+		Close:     r.Close,
+		Volume:    math.ToBigInt(r.Volume),
+		Count:     math.ToBigInt(r.Count),
+		Timestamp: r.Timestamp,
+		Duration:  r.Duration,
+		Unit:      r.Unit,
+		CloseTime: r.CloseTime,
+	}
+}
@@ -5,6 +5,8 @@ type LendingOrderBook struct {
 	Name   string              `json:"name"`
 	Borrow []map[string]string `json:"borrow"`
 	Lend   []map[string]string `json:"lend"`
+	// Sequence is the sequence of the last orderbook update included in the orderbook
+	Sequence uint64 `json:"sequence"`
 }
 
 // RawLendingOrderBook for lending orderbook
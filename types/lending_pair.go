@@ -6,32 +6,32 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-
-	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // LendingPair struct is used to model the lendingPair data in the system and DB
 type LendingPair struct {
-	ID                   bson.ObjectId  `json:"-" bson:"_id"`
-	Term                 uint64         `json:"term,omitempty" bson:"term"`
-	LendingTokenSymbol   string         `json:"lendingTokenSymbol,omitempty" bson:"lendingTokenSymbol"`
-	LendingTokenAddress  common.Address `json:"lendingTokenAddress,omitempty" bson:"lendingTokenAddress"`
-	LendingTokenDecimals int            `json:"lendingTokenDecimals,omitempty" bson:"lendingTokenDecimals"`
-	RelayerAddress       common.Address `json:"relayerAddress,omitempty" bson:"relayerAddress"`
-	CreatedAt            time.Time      `json:"-" bson:"createdAt"`
-	UpdatedAt            time.Time      `json:"-" bson:"updatedAt"`
+	ID                   primitive.ObjectID `json:"-" bson:"_id"`
+	Term                 uint64             `json:"term,omitempty" bson:"term"`
+	LendingTokenSymbol   string             `json:"lendingTokenSymbol,omitempty" bson:"lendingTokenSymbol"`
+	LendingTokenAddress  common.Address     `json:"lendingTokenAddress,omitempty" bson:"lendingTokenAddress"`
+	LendingTokenDecimals int                `json:"lendingTokenDecimals,omitempty" bson:"lendingTokenDecimals"`
+	RelayerAddress       common.Address     `json:"relayerAddress,omitempty" bson:"relayerAddress"`
+	CreatedAt            time.Time          `json:"-" bson:"createdAt"`
+	UpdatedAt            time.Time          `json:"-" bson:"updatedAt"`
 }
 
 // LendingPairRecord struct for database
 type LendingPairRecord struct {
-	ID                   bson.ObjectId `json:"id" bson:"_id"`
-	Term                 string        `json:"term" bson:"term"`
-	LendingTokenSymbol   string        `json:"lendingTokenSymbol" bson:"lendingTokenSymbol"`
-	LendingTokenAddress  string        `json:"lendingTokenAddress" bson:"lendingTokenAddress"`
-	LendingTokenDecimals int           `json:"lendingTokenDecimals" bson:"lendingTokenDecimals"`
-	RelayerAddress       string        `json:"relayerAddress" bson:"relayerAddress"`
-	CreatedAt            time.Time     `json:"createdAt" bson:"createdAt"`
-	UpdatedAt            time.Time     `json:"updatedAt" bson:"updatedAt"`
+	ID                   primitive.ObjectID `json:"id" bson:"_id"`
+	Term                 string             `json:"term" bson:"term"`
+	LendingTokenSymbol   string             `json:"lendingTokenSymbol" bson:"lendingTokenSymbol"`
+	LendingTokenAddress  string             `json:"lendingTokenAddress" bson:"lendingTokenAddress"`
+	LendingTokenDecimals int                `json:"lendingTokenDecimals" bson:"lendingTokenDecimals"`
+	RelayerAddress       string             `json:"relayerAddress" bson:"relayerAddress"`
+	CreatedAt            time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt            time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
 
 // UnmarshalJSON umarshal JSON
@@ -78,11 +78,11 @@ func (p *LendingPair) MarshalJSON() ([]byte, error) {
 	return json.Marshal(lendingPair)
 }
 
-// SetBSON get lending pair object from database
-func (p *LendingPair) SetBSON(raw bson.Raw) error {
+// UnmarshalBSON get lending pair object from database
+func (p *LendingPair) UnmarshalBSON(data []byte) error {
 	decoded := &LendingPairRecord{}
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		return err
 	}
@@ -99,9 +99,9 @@ func (p *LendingPair) SetBSON(raw bson.Raw) error {
 	return nil
 }
 
-// GetBSON insert record to database
-func (p *LendingPair) GetBSON() (interface{}, error) {
-	return &LendingPairRecord{
+// MarshalBSON insert record to database
+func (p *LendingPair) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(&LendingPairRecord{
 		ID:                   p.ID,
 		Term:                 strconv.FormatUint(p.Term, 10),
 		LendingTokenAddress:  p.LendingTokenAddress.Hex(),
@@ -110,7 +110,7 @@ func (p *LendingPair) GetBSON() (interface{}, error) {
 		RelayerAddress:       p.RelayerAddress.Hex(),
 		CreatedAt:            p.CreatedAt,
 		UpdatedAt:            p.UpdatedAt,
-	}, nil
+	})
 }
 
 // Name name of lending pair
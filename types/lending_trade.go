@@ -11,8 +11,8 @@ import (
 	"github.com/ethereum/go-ethereum/crypto/sha3"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/utils"
-
-	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -23,36 +23,36 @@ const (
 
 // LendingTrade lending trade struct
 type LendingTrade struct {
-	ID                     bson.ObjectId  `json:"id,omitempty" bson:"_id"`
-	Borrower               common.Address `bson:"borrower" json:"borrower"`
-	Investor               common.Address `bson:"investor" json:"investor"`
-	LendingToken           common.Address `bson:"lendingToken" json:"lendingToken"`
-	CollateralToken        common.Address `bson:"collateralToken" json:"collateralToken"`
-	BorrowingOrderHash     common.Hash    `bson:"borrowingOrderHash" json:"borrowingOrderHash"`
-	InvestingOrderHash     common.Hash    `bson:"investingOrderHash" json:"investingOrderHash"`
-	BorrowingRelayer       common.Address `bson:"borrowingRelayer" json:"borrowingRelayer"`
-	InvestingRelayer       common.Address `bson:"investingRelayer" json:"investingRelayer"`
-	Term                   uint64         `bson:"term" json:"term"`
-	Interest               uint64         `bson:"interest" json:"interest"`
-	CollateralPrice        *big.Int       `bson:"collateralPrice" json:"collateralPrice"`
-	LiquidationPrice       *big.Int       `bson:"liquidationPrice" json:"liquidationPrice"`
-	CollateralLockedAmount *big.Int       `bson:"collateralLockedAmount" json:"collateralLockedAmount"`
-	LiquidationTime        uint64         `bson:"liquidationTime" json:"liquidationTime"`
-	DepositRate            *big.Int       `bson:"depositRate" json:"depositRate"`
-	Amount                 *big.Int       `bson:"amount" json:"amount"`
-	BorrowingFee           *big.Int       `bson:"borrowingFee" json:"borrowingFee"`
-	InvestingFee           *big.Int       `bson:"investingFee" json:"investingFee"`
-	Status                 string         `bson:"status" json:"status"`
-	TakerOrderSide         string         `bson:"takerOrderSide" json:"takerOrderSide"`
-	TakerOrderType         string         `bson:"takerOrderType" json:"takerOrderType"`
-	MakerOrderType         string         `bson:"makerOrderType" json:"makerOrderType"`
-	TradeID                string         `bson:"tradeId" json:"tradeID"`
-	Hash                   common.Hash    `bson:"hash" json:"hash"`
-	TxHash                 common.Hash    `bson:"txHash" json:"txHash"`
-	AutoTopUp              uint64         `json:"autoTopUp" json:"autoTopUp"`
-	ExtraData              string         `bson:"extraData" json:"extraData"`
-	CreatedAt              time.Time      `bson:"createdAt" json:"createdAt"`
-	UpdatedAt              time.Time      `bson:"updatedAt" json:"updatedAt"`
+	ID                     primitive.ObjectID `json:"id,omitempty" bson:"_id"`
+	Borrower               common.Address     `bson:"borrower" json:"borrower"`
+	Investor               common.Address     `bson:"investor" json:"investor"`
+	LendingToken           common.Address     `bson:"lendingToken" json:"lendingToken"`
+	CollateralToken        common.Address     `bson:"collateralToken" json:"collateralToken"`
+	BorrowingOrderHash     common.Hash        `bson:"borrowingOrderHash" json:"borrowingOrderHash"`
+	InvestingOrderHash     common.Hash        `bson:"investingOrderHash" json:"investingOrderHash"`
+	BorrowingRelayer       common.Address     `bson:"borrowingRelayer" json:"borrowingRelayer"`
+	InvestingRelayer       common.Address     `bson:"investingRelayer" json:"investingRelayer"`
+	Term                   uint64             `bson:"term" json:"term"`
+	Interest               uint64             `bson:"interest" json:"interest"`
+	CollateralPrice        *big.Int           `bson:"collateralPrice" json:"collateralPrice"`
+	LiquidationPrice       *big.Int           `bson:"liquidationPrice" json:"liquidationPrice"`
+	CollateralLockedAmount *big.Int           `bson:"collateralLockedAmount" json:"collateralLockedAmount"`
+	LiquidationTime        uint64             `bson:"liquidationTime" json:"liquidationTime"`
+	DepositRate            *big.Int           `bson:"depositRate" json:"depositRate"`
+	Amount                 *big.Int           `bson:"amount" json:"amount"`
+	BorrowingFee           *big.Int           `bson:"borrowingFee" json:"borrowingFee"`
+	InvestingFee           *big.Int           `bson:"investingFee" json:"investingFee"`
+	Status                 string             `bson:"status" json:"status"`
+	TakerOrderSide         string             `bson:"takerOrderSide" json:"takerOrderSide"`
+	TakerOrderType         string             `bson:"takerOrderType" json:"takerOrderType"`
+	MakerOrderType         string             `bson:"makerOrderType" json:"makerOrderType"`
+	TradeID                string             `bson:"tradeId" json:"tradeID"`
+	Hash                   common.Hash        `bson:"hash" json:"hash"`
+	TxHash                 common.Hash        `bson:"txHash" json:"txHash"`
+	AutoTopUp              uint64             `json:"autoTopUp" json:"autoTopUp"`
+	ExtraData              string             `bson:"extraData" json:"extraData"`
+	CreatedAt              time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt              time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
 // MarshalJSON returns the json encoded byte array representing the trade struct
@@ -220,41 +220,41 @@ func (t *LendingTrade) UnmarshalJSON(b []byte) error {
 
 // LendingTradeBSON lending trade mongo
 type LendingTradeBSON struct {
-	ID                     bson.ObjectId `json:"id,omitempty" bson:"_id"`
-	Borrower               string        `bson:"borrower" json:"borrower"`
-	Investor               string        `bson:"investor" json:"investor"`
-	LendingToken           string        `bson:"lendingToken" json:"lendingToken"`
-	CollateralToken        string        `bson:"collateralToken" json:"collateralToken"`
-	BorrowingOrderHash     string        `bson:"borrowingOrderHash" json:"borrowingOrderHash"`
-	InvestingOrderHash     string        `bson:"investingOrderHash" json:"investingOrderHash"`
-	BorrowingRelayer       string        `bson:"borrowingRelayer" json:"borrowingRelayer"`
-	InvestingRelayer       string        `bson:"investingRelayer" json:"investingRelayer"`
-	Term                   string        `bson:"term" json:"term"`
-	Interest               string        `bson:"interest" json:"interest"`
-	CollateralPrice        string        `bson:"collateralPrice" json:"collateralPrice"`
-	LiquidationPrice       string        `bson:"liquidationPrice" json:"liquidationPrice"`
-	LiquidationTime        string        `bson:"liquidationTime" json:"liquidationTime"`
-	CollateralLockedAmount string        `bson:"collateralLockedAmount" json:"collateralLockedAmount"`
-	DepositRate            string        `bson:"depositRate" json:"depositRate"`
-	Amount                 string        `bson:"amount" json:"amount"`
-	BorrowingFee           string        `bson:"borrowingFee" json:"borrowingFee"`
-	InvestingFee           string        `bson:"investingFee" json:"investingFee"`
-	Status                 string        `bson:"status" json:"status"`
-	TakerOrderSide         string        `bson:"takerOrderSide" json:"takerOrderSide"`
-	TakerOrderType         string        `bson:"takerOrderType" json:"takerOrderType"`
-	MakerOrderType         string        `bson:"makerOrderType" json:"makerOrderType"`
-	TradeID                string        `bson:"tradeId" json:"tradeID"`
-	Hash                   string        `bson:"hash" json:"hash"`
-	TxHash                 string        `bson:"txHash" json:"txHash"`
-	AutoTopUp              uint64        `bson:"autoTopUp" json:"autoTopUp"`
-	ExtraData              string        `bson:"extraData" json:"extraData"`
-	CreatedAt              time.Time     `bson:"createdAt" json:"createdAt"`
-	UpdatedAt              time.Time     `bson:"updatedAt" json:"updatedAt"`
+	ID                     primitive.ObjectID `json:"id,omitempty" bson:"_id"`
+	Borrower               string             `bson:"borrower" json:"borrower"`
+	Investor               string             `bson:"investor" json:"investor"`
+	LendingToken           string             `bson:"lendingToken" json:"lendingToken"`
+	CollateralToken        string             `bson:"collateralToken" json:"collateralToken"`
+	BorrowingOrderHash     string             `bson:"borrowingOrderHash" json:"borrowingOrderHash"`
+	InvestingOrderHash     string             `bson:"investingOrderHash" json:"investingOrderHash"`
+	BorrowingRelayer       string             `bson:"borrowingRelayer" json:"borrowingRelayer"`
+	InvestingRelayer       string             `bson:"investingRelayer" json:"investingRelayer"`
+	Term                   string             `bson:"term" json:"term"`
+	Interest               string             `bson:"interest" json:"interest"`
+	CollateralPrice        string             `bson:"collateralPrice" json:"collateralPrice"`
+	LiquidationPrice       string             `bson:"liquidationPrice" json:"liquidationPrice"`
+	LiquidationTime        string             `bson:"liquidationTime" json:"liquidationTime"`
+	CollateralLockedAmount string             `bson:"collateralLockedAmount" json:"collateralLockedAmount"`
+	DepositRate            string             `bson:"depositRate" json:"depositRate"`
+	Amount                 string             `bson:"amount" json:"amount"`
+	BorrowingFee           string             `bson:"borrowingFee" json:"borrowingFee"`
+	InvestingFee           string             `bson:"investingFee" json:"investingFee"`
+	Status                 string             `bson:"status" json:"status"`
+	TakerOrderSide         string             `bson:"takerOrderSide" json:"takerOrderSide"`
+	TakerOrderType         string             `bson:"takerOrderType" json:"takerOrderType"`
+	MakerOrderType         string             `bson:"makerOrderType" json:"makerOrderType"`
+	TradeID                string             `bson:"tradeId" json:"tradeID"`
+	Hash                   string             `bson:"hash" json:"hash"`
+	TxHash                 string             `bson:"txHash" json:"txHash"`
+	AutoTopUp              uint64             `bson:"autoTopUp" json:"autoTopUp"`
+	ExtraData              string             `bson:"extraData" json:"extraData"`
+	CreatedAt              time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt              time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
-// GetBSON for monggo insert
-func (t *LendingTrade) GetBSON() (interface{}, error) {
-	return bson.M{
+// MarshalBSON for monggo insert
+func (t *LendingTrade) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(bson.M{
 		"$setOnInsert": bson.M{
 			"createdAt": t.CreatedAt,
 		},
@@ -289,14 +289,14 @@ func (t *LendingTrade) GetBSON() (interface{}, error) {
 			ExtraData:              t.ExtraData,
 			UpdatedAt:              t.UpdatedAt,
 		},
-	}, nil
+	})
 }
 
-// SetBSON get monggo record
-func (t *LendingTrade) SetBSON(raw bson.Raw) error {
+// UnmarshalBSON get monggo record
+func (t *LendingTrade) UnmarshalBSON(data []byte) error {
 	decoded := new(LendingTradeBSON)
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// LoginChallenge is the nonce that a websocket connection has to sign to log in
+type LoginChallenge struct {
+	Nonce string `json:"nonce"`
+}
+
+// Login is the signature of a login challenge by the address that logs in
+type Login struct {
+	Address   common.Address `json:"address"`
+	Nonce     string         `json:"nonce"`
+	Signature *Signature     `json:"signature"`
+}
+
+// ComputeHash returns the hash signed to log in, which is the keccak256 hash of the nonce
+func (l *Login) ComputeHash() common.Hash {
+	return crypto.Keccak256Hash([]byte(l.Nonce))
+}
+
+// Sign signs the hash of the nonce with the wallet, and sets the address of the login to the
+// address of the wallet
+func (l *Login) Sign(w *Wallet) error {
+	sig, err := w.SignHash(l.ComputeHash())
+	if err != nil {
+		return err
+	}
+
+	l.Address = w.Address
+	l.Signature = sig
+	return nil
+}
+
+// VerifySignature checks that the login has been signed by its address. The hash is signed
+// with the "Ethereum Signed Message" prefix, as the orders are
+func (l *Login) VerifySignature() error {
+	if l.Signature == nil {
+		return errors.New("Signature is missing")
+	}
+
+	message := crypto.Keccak256(
+		[]byte("\x19Ethereum Signed Message:\n32"),
+		l.ComputeHash().Bytes(),
+	)
+
+	address, err := l.Signature.Verify(common.BytesToHash(message))
+	if err != nil {
+		return err
+	}
+
+	if address != l.Address {
+		return errors.New("Recovered address is incorrect")
+	}
+
+	return nil
+}
@@ -1,7 +1,7 @@
 package types
 
 import (
-	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type MarketData struct {
@@ -29,7 +29,7 @@ type FiatPriceItemBSONUpdate struct {
 	*FiatPriceItem
 }
 
-func (i *FiatPriceItem) GetBSON() (interface{}, error) {
+func (i *FiatPriceItem) MarshalBSON() ([]byte, error) {
 	nr := FiatPriceItem{
 		Symbol:       i.Symbol,
 		Price:        i.Price,
@@ -38,10 +38,10 @@ func (i *FiatPriceItem) GetBSON() (interface{}, error) {
 		TotalVolume:  i.TotalVolume,
 	}
 
-	return nr, nil
+	return bson.Marshal(nr)
 }
 
-func (i *FiatPriceItem) SetBSON(raw bson.Raw) error {
+func (i *FiatPriceItem) UnmarshalBSON(data []byte) error {
 	decoded := new(struct {
 		Symbol       string `json:"symbol" bson:"symbol"`
 		Price        string `json:"price" bson:"price"`
@@ -50,7 +50,7 @@ func (i *FiatPriceItem) SetBSON(raw bson.Raw) error {
 		TotalVolume  string `json:"totalVolume" bson:"totalVolume"`
 	})
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 
 	if err != nil {
 		return err
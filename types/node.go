@@ -0,0 +1,12 @@
+package types
+
+import "time"
+
+// NodeStatus is the health of a TomoX node, as of its last call or health check. Error is the
+// error of that call when the node could not be reached
+type NodeStatus struct {
+	URL       string    `json:"url"`
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
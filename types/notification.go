@@ -5,7 +5,8 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -25,24 +26,24 @@ type Message struct {
 
 // Notification struct
 type Notification struct {
-	ID        bson.ObjectId  `json:"_id" bson:"_id"`
-	Recipient common.Address `json:"recipient" bson:"recipient"`
-	Message   Message        `json:"message" bson:"message"`
-	Type      string         `json:"type" bson:"type"`
-	Status    string         `json:"status" bson:"status"`
-	CreatedAt time.Time      `json:"createdAt" bson:"createdAt"`
-	UpdatedAt time.Time      `json:"updatedAt" bson:"updatedAt"`
+	ID        primitive.ObjectID `json:"_id" bson:"_id"`
+	Recipient common.Address     `json:"recipient" bson:"recipient"`
+	Message   Message            `json:"message" bson:"message"`
+	Type      string             `json:"type" bson:"type"`
+	Status    string             `json:"status" bson:"status"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
 
 // NotificationRecord struct
 type NotificationRecord struct {
-	ID        bson.ObjectId `json:"_id" bson:"_id"`
-	Recipient string        `json:"recipient" bson:"recipient"`
-	Message   Message       `json:"message" bson:"message"`
-	Type      string        `json:"type" bson:"type"`
-	Status    string        `json:"status" bson:"status"`
-	CreatedAt time.Time     `json:"createdAt" bson:"createdAt"`
-	UpdatedAt time.Time     `json:"updatedAt" bson:"updatedAt"`
+	ID        primitive.ObjectID `json:"_id" bson:"_id"`
+	Recipient string             `json:"recipient" bson:"recipient"`
+	Message   Message            `json:"message" bson:"message"`
+	Type      string             `json:"type" bson:"type"`
+	Status    string             `json:"status" bson:"status"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
 
 // NotificationBSONUpdate return BSON structure for NotificationSpec structure
@@ -75,11 +76,11 @@ func (n *Notification) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	if notification["_id"] != nil && bson.IsObjectIdHex(notification["_id"].(string)) {
-		n.ID = bson.ObjectIdHex(notification["_id"].(string))
+	if notification["_id"] != nil && primitive.IsValidObjectID(notification["_id"].(string)) {
+		n.ID, _ = primitive.ObjectIDFromHex(notification["_id"].(string))
 	}
-	if notification["id"] != nil && bson.IsObjectIdHex(notification["id"].(string)) {
-		n.ID = bson.ObjectIdHex(notification["id"].(string))
+	if notification["id"] != nil && primitive.IsValidObjectID(notification["id"].(string)) {
+		n.ID, _ = primitive.ObjectIDFromHex(notification["id"].(string))
 	}
 	if notification["recipient"] == nil {
 		// return errors.New("Order Hash is not set")
@@ -112,8 +113,8 @@ func (n *Notification) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// GetBSON get Notification struct
-func (n *Notification) GetBSON() (interface{}, error) {
+// MarshalBSON get Notification struct
+func (n *Notification) MarshalBSON() ([]byte, error) {
 	nr := NotificationRecord{
 		ID:        n.ID,
 		Recipient: n.Recipient.Hex(),
@@ -123,22 +124,22 @@ func (n *Notification) GetBSON() (interface{}, error) {
 		CreatedAt: n.CreatedAt,
 		UpdatedAt: n.UpdatedAt,
 	}
-	return nr, nil
+	return bson.Marshal(nr)
 }
 
-// SetBSON json to Notification
-func (n *Notification) SetBSON(raw bson.Raw) error {
+// UnmarshalBSON json to Notification
+func (n *Notification) UnmarshalBSON(data []byte) error {
 	decoded := new(struct {
-		ID        bson.ObjectId `json:"_id" bson:"_id"`
-		Recipient string        `json:"recipient" bson:"recipient"`
-		Message   Message       `json:"message" bson:"message"`
-		Type      string        `json:"type" bson:"type"`
-		Status    string        `json:"status" bson:"status"`
-		CreatedAt time.Time     `json:"createdAt" bson:"createdAt"`
-		UpdatedAt time.Time     `json:"updatedAt" bson:"updatedAt"`
+		ID        primitive.ObjectID `json:"_id" bson:"_id"`
+		Recipient string             `json:"recipient" bson:"recipient"`
+		Message   Message            `json:"message" bson:"message"`
+		Type      string             `json:"type" bson:"type"`
+		Status    string             `json:"status" bson:"status"`
+		CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+		UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
 	})
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 
 	if err != nil {
 		return err
@@ -6,8 +6,9 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Tick is the format in which mongo aggregate pipeline returns data when queried for OHLCV data
@@ -163,15 +164,15 @@ func (t *Tick) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// GetBSON return Tick structure
-func (t *Tick) GetBSON() (interface{}, error) {
+// MarshalBSON return Tick structure
+func (t *Tick) MarshalBSON() ([]byte, error) {
 	type PairID struct {
 		PairName   string `json:"pairName" bson:"pairName"`
 		BaseToken  string `json:"baseToken" bson:"baseToken"`
 		QuoteToken string `json:"quoteToken" bson:"quoteToken"`
 	}
 
-	count, err := bson.ParseDecimal128(t.Count.String())
+	count, err := primitive.ParseDecimal128(t.Count.String())
 	if err != nil {
 		return nil, err
 	}
@@ -181,24 +182,24 @@ func (t *Tick) GetBSON() (interface{}, error) {
 	l := t.Low.String()
 	c := t.Close.String()
 
-	v, err := bson.ParseDecimal128(t.Volume.String())
+	v, err := primitive.ParseDecimal128(t.Volume.String())
 	if err != nil {
 		return nil, err
 	}
 
-	return struct {
-		ID        PairID          `json:"id,omitempty" bson:"_id"`
-		Count     bson.Decimal128 `json:"count" bson:"count"`
-		Open      string          `json:"open" bson:"open"`
-		High      string          `json:"high" bson:"high"`
-		Low       string          `json:"low" bson:"low"`
-		Close     string          `json:"close" bson:"close"`
-		Volume    bson.Decimal128 `json:"volume" bson:"volume"`
-		Timestamp int64           `json:"timestamp" bson:"timestamp"`
-		OpenTime  time.Time       `json:"openTime" bson:"openTime"`
-		CloseTime time.Time       `json:"closeTime" bson:"closeTime"`
-		Duration  int64           `json:"duration" bson:"duration"`
-		Unit      string          `json:"unit" bson:"unit"`
+	return bson.Marshal(struct {
+		ID        PairID               `json:"id,omitempty" bson:"_id"`
+		Count     primitive.Decimal128 `json:"count" bson:"count"`
+		Open      string               `json:"open" bson:"open"`
+		High      string               `json:"high" bson:"high"`
+		Low       string               `json:"low" bson:"low"`
+		Close     string               `json:"close" bson:"close"`
+		Volume    primitive.Decimal128 `json:"volume" bson:"volume"`
+		Timestamp int64                `json:"timestamp" bson:"timestamp"`
+		OpenTime  time.Time            `json:"openTime" bson:"openTime"`
+		CloseTime time.Time            `json:"closeTime" bson:"closeTime"`
+		Duration  int64                `json:"duration" bson:"duration"`
+		Unit      string               `json:"unit" bson:"unit"`
 	}{
 		ID: PairID{
 			t.Pair.PairName,
@@ -217,34 +218,36 @@ func (t *Tick) GetBSON() (interface{}, error) {
 		CloseTime: t.CloseTime,
 		Duration:  t.Duration,
 		Unit:      t.Unit,
-	}, nil
+	})
 }
 
-// SetBSON decode json
-func (t *Tick) SetBSON(raw bson.Raw) error {
+// UnmarshalBSON decode json
+func (t *Tick) UnmarshalBSON(data []byte) error {
 	type PairIDRecord struct {
 		PairName   string `json:"pairName" bson:"pairName"`
 		BaseToken  string `json:"baseToken" bson:"baseToken"`
 		QuoteToken string `json:"quoteToken" bson:"quoteToken"`
 	}
 	m := map[string]interface{}{}
-	raw.Unmarshal(&m)
+	bson.Unmarshal(data, &m)
 	decoded := new(struct {
-		Pair      PairIDRecord    `json:"pair,omitempty" bson:"_id"`
-		Count     bson.Decimal128 `json:"count" bson:"count"`
-		Open      string          `json:"open" bson:"open"`
-		High      string          `json:"high" bson:"high"`
-		Low       string          `json:"low" bson:"low"`
-		Close     string          `json:"close" bson:"close"`
-		Volume    bson.Decimal128 `json:"volume" bson:"volume"`
-		Timestamp int64           `json:"timestamp" bson:"timestamp"`
-		OpenTime  time.Time       `json:"openTime" bson:"openTime"`
-		CloseTime time.Time       `json:"closeTime" bson:"closeTime"`
-		Duration  int64           `json:"duration" bson:"duration"`
-		Unit      string          `json:"unit" bson:"unit"`
+		Pair      PairIDRecord         `json:"pair,omitempty" bson:"_id"`
+		Count     primitive.Decimal128 `json:"count" bson:"count"`
+		Open      string               `json:"open" bson:"open"`
+		High      string               `json:"high" bson:"high"`
+		Low       string               `json:"low" bson:"low"`
+		Close     string               `json:"close" bson:"close"`
+		Volume    primitive.Decimal128 `json:"volume" bson:"volume"`
+		Timestamp int64                `json:"timestamp" bson:"timestamp"`
+		OpenTime  time.Time            `json:"openTime" bson:"openTime"`
+		CloseTime time.Time            `json:"closeTime" bson:"closeTime"`
+		Duration  int64                `json:"duration" bson:"duration"`
+		Unit      string               `json:"unit" bson:"unit"`
+		// VolumeByQuote is only returned by the OHLCV rebuild pipeline
+		VolumeByQuote string `json:"volumeByQuote" bson:"volumeByQuote"`
 	})
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		return err
 	}
@@ -269,6 +272,10 @@ func (t *Tick) SetBSON(raw bson.Raw) error {
 	t.Open = math.ToBigInt(o)
 	t.Volume = math.ToBigInt(v)
 
+	if decoded.VolumeByQuote != "" {
+		t.VolumeByQuote = math.DecimalToBigInt(decoded.VolumeByQuote)
+	}
+
 	t.Timestamp = decoded.Timestamp
 	t.OpenTime = decoded.OpenTime
 	t.CloseTime = decoded.CloseTime
@@ -283,3 +290,91 @@ func (t *Tick) AddressCode() string {
 	code := t.Pair.BaseToken.Hex() + "::" + t.Pair.QuoteToken.Hex()
 	return code
 }
+
+// TickDuration is the duration of the ticks of a chart, such as 5 min
+type TickDuration struct {
+	Duration int64
+	Unit     string
+}
+
+// TickRange is a range of ticks of a pair and duration. From is the timestamp of the first
+// tick and To the timestamp following the last one
+type TickRange struct {
+	Pair     PairAddresses `json:"pair"`
+	Duration int64         `json:"duration"`
+	Unit     string        `json:"unit"`
+	From     int64         `json:"from"`
+	To       int64         `json:"to"`
+}
+
+// TickRecord is the BSON representation of a Tick stored in the ohlcv collection.
+// A tick is identified by its pair, duration, unit and timestamp. UpdatedAt is the time the
+// tick was last stored, so that the replicas which do not handle the trades can load it
+type TickRecord struct {
+	PairName      string    `bson:"pairName"`
+	BaseToken     string    `bson:"baseToken"`
+	QuoteToken    string    `bson:"quoteToken"`
+	Duration      int64     `bson:"duration"`
+	Unit          string    `bson:"unit"`
+	Timestamp     int64     `bson:"timestamp"`
+	Open          string    `bson:"open"`
+	High          string    `bson:"high"`
+	Low           string    `bson:"low"`
+	Close         string    `bson:"close"`
+	Volume        string    `bson:"volume"`
+	VolumeByQuote string    `bson:"volumeByQuote"`
+	Count         string    `bson:"count"`
+	OpenTime      time.Time `bson:"openTime"`
+	CloseTime     time.Time `bson:"closeTime"`
+	UpdatedAt     time.Time `bson:"updatedAt"`
+}
+
+// NewTickRecord returns the record of a tick
+func NewTickRecord(t *Tick) *TickRecord {
+	r := &TickRecord{
+		PairName:   t.Pair.PairName,
+		BaseToken:  t.Pair.BaseToken.Hex(),
+		QuoteToken: t.Pair.QuoteToken.Hex(),
+		Duration:   t.Duration,
+		Unit:       t.Unit,
+		Timestamp:  t.Timestamp,
+		Open:       t.Open.String(),
+		High:       t.High.String(),
+		Low:        t.Low.String(),
+		Close:      t.Close.String(),
+		Volume:     t.Volume.String(),
+		Count:      t.Count.String(),
+		OpenTime:   t.OpenTime,
+		CloseTime:  t.CloseTime,
+		UpdatedAt:  time.Now(),
+	}
+
+	if t.VolumeByQuote != nil {
+		r.VolumeByQuote = t.VolumeByQuote.String()
+	}
+
+	return r
+}
+
+// Tick returns the tick of a record
+func (r *TickRecord) Tick() *Tick {
+	return &Tick{
+		Pair: PairID{
+			PairName:   r.PairName,
+			BaseToken:  common.HexToAddress(r.BaseToken),
+			QuoteToken: common.HexToAddress(r.QuoteToken),
+		},
+		Open:          math.ToBigInt(r.Open),
+		High:          math.ToBigInt(r.High),
+		Low:           math.ToBigInt(r.Low),
+		Close:         math.ToBigInt(r.Close),
+		Volume:        math.ToBigInt(r.Volume),
+		VolumeByQuote: math.ToBigInt(r.VolumeByQuote),
+		Count:         math.ToBigInt(r.Count),
+		Timestamp:     r.Timestamp,
+		OpenTime:      r.OpenTime,
+		CloseTime:     r.CloseTime,
+		Duration:      r.Duration,
+		Unit:          r.Unit,
+	}
+}
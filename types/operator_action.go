@@ -0,0 +1,109 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	OperatorActionNewOrder    = "NEW_ORDER"
+	OperatorActionCancelOrder = "CANCEL_ORDER"
+)
+
+// OperatorAction is the audit record of an order or order cancel signed by the server with
+// an operator wallet. Operator is the authenticated address that requested it, and Error is
+// the reason it was rejected, if it was
+type OperatorAction struct {
+	ID            primitive.ObjectID `json:"id"`
+	Operator      common.Address     `json:"operator"`
+	WalletAddress common.Address     `json:"walletAddress"`
+	Action        string             `json:"action"`
+	OrderHash     common.Hash        `json:"orderHash"`
+	Hash          common.Hash        `json:"hash"`
+	Nonce         *big.Int           `json:"nonce"`
+	Error         string             `json:"error,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt"`
+}
+
+// OperatorActionRecord is the BSON representation of an OperatorAction
+type OperatorActionRecord struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	Operator      string             `bson:"operator"`
+	WalletAddress string             `bson:"walletAddress"`
+	Action        string             `bson:"action"`
+	OrderHash     string             `bson:"orderHash"`
+	Hash          string             `bson:"hash"`
+	Nonce         string             `bson:"nonce"`
+	Error         string             `bson:"error,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt"`
+}
+
+// OperatorActionSpec filters the operator actions. The empty fields are not filtered on
+type OperatorActionSpec struct {
+	Operator      string
+	WalletAddress string
+	Action        string
+	DateFrom      int64
+	DateTo        int64
+}
+
+// OperatorActionRes is a page of operator actions and the total number of matching actions
+type OperatorActionRes struct {
+	Total   int               `json:"total"`
+	Actions []*OperatorAction `json:"actions"`
+}
+
+// MarshalBSON implements the bson.Marshaler interface
+func (a *OperatorAction) MarshalBSON() ([]byte, error) {
+	r := OperatorActionRecord{
+		ID:            a.ID,
+		Operator:      a.Operator.Hex(),
+		WalletAddress: a.WalletAddress.Hex(),
+		Action:        a.Action,
+		OrderHash:     a.OrderHash.Hex(),
+		Hash:          a.Hash.Hex(),
+		Error:         a.Error,
+		CreatedAt:     a.CreatedAt,
+	}
+
+	if a.ID.IsZero() {
+		r.ID = primitive.NewObjectID()
+	}
+
+	if a.Nonce != nil {
+		r.Nonce = a.Nonce.String()
+	}
+
+	return bson.Marshal(r)
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface
+func (a *OperatorAction) UnmarshalBSON(data []byte) error {
+	decoded := new(OperatorActionRecord)
+
+	err := bson.Unmarshal(data, decoded)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	a.ID = decoded.ID
+	a.Operator = common.HexToAddress(decoded.Operator)
+	a.WalletAddress = common.HexToAddress(decoded.WalletAddress)
+	a.Action = decoded.Action
+	a.OrderHash = common.HexToHash(decoded.OrderHash)
+	a.Hash = common.HexToHash(decoded.Hash)
+	a.Error = decoded.Error
+	a.CreatedAt = decoded.CreatedAt
+
+	if decoded.Nonce != "" {
+		a.Nonce = math.ToBigInt(decoded.Nonce)
+	}
+
+	return nil
+}
@@ -11,9 +11,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/sha3"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -22,37 +23,60 @@ const (
 	TypeMarketOrder = "MO"
 	TypeLimitOrder  = "LO"
 
+	// OrderStatusNew is the status the new orders are signed with
+	OrderStatusNew           = "NEW"
 	OrderStatusOpen          = "OPEN"
 	OrderStatusPartialFilled = "PARTIAL_FILLED"
 	OrderStatusFilled        = "FILLED"
 	OrderStatusRejected      = "REJECTED"
 	OrderStatusCancelled     = "CANCELLED"
+
+	// Good till cancelled: the order stays in the orderbook until it is filled or cancelled
+	TimeInForceGTC = "GTC"
+	// Immediate or cancel: the part of the order that is not matched immediately is cancelled
+	TimeInForceIOC = "IOC"
+	// Fill or kill: the order is cancelled unless it can be matched completely and immediately
+	TimeInForceFOK = "FOK"
+	// Good till date: the order is cancelled at its expiry date
+	TimeInForceGTD = "GTD"
 )
 
 // Order contains the data related to an order sent by the user
 type Order struct {
-	ID              bson.ObjectId  `json:"id" bson:"_id"`
-	UserAddress     common.Address `json:"userAddress" bson:"userAddress"`
-	ExchangeAddress common.Address `json:"exchangeAddress" bson:"exchangeAddress"`
-	BaseToken       common.Address `json:"baseToken" bson:"baseToken"`
-	QuoteToken      common.Address `json:"quoteToken" bson:"quoteToken"`
-	Status          string         `json:"status" bson:"status"`
-	Side            string         `json:"side" bson:"side"`
-	Type            string         `json:"type" bson:"type"`
-	Hash            common.Hash    `json:"hash" bson:"hash"`
-	Signature       *Signature     `json:"signature,omitempty" bson:"signature"`
-	PricePoint      *big.Int       `json:"pricepoint" bson:"price"`
-	Amount          *big.Int       `json:"amount" bson:"quantity"`
-	FilledAmount    *big.Int       `json:"filledAmount" bson:"filledAmount"`
-	Nonce           *big.Int       `json:"nonce" bson:"nonce"`
-	PairName        string         `json:"pairName" bson:"pairName"`
-	CreatedAt       time.Time      `json:"createdAt" bson:"createdAt"`
-	UpdatedAt       time.Time      `json:"updatedAt" bson:"updatedAt"`
-	OrderID         uint64         `json:"orderID,omitempty" bson:"orderID"`
-	NextOrder       []byte         `json:"-"`
-	PrevOrder       []byte         `json:"-"`
-	OrderList       []byte         `json:"-"`
-	Key             string         `json:"key" bson:"key"`
+	ID              primitive.ObjectID `json:"id" bson:"_id"`
+	UserAddress     common.Address     `json:"userAddress" bson:"userAddress"`
+	ExchangeAddress common.Address     `json:"exchangeAddress" bson:"exchangeAddress"`
+	BaseToken       common.Address     `json:"baseToken" bson:"baseToken"`
+	QuoteToken      common.Address     `json:"quoteToken" bson:"quoteToken"`
+	Status          string             `json:"status" bson:"status"`
+	Side            string             `json:"side" bson:"side"`
+	Type            string             `json:"type" bson:"type"`
+	Hash            common.Hash        `json:"hash" bson:"hash"`
+	Signature       *Signature         `json:"signature,omitempty" bson:"signature"`
+	PricePoint      *big.Int           `json:"pricepoint" bson:"price"`
+	Amount          *big.Int           `json:"amount" bson:"quantity"`
+	FilledAmount    *big.Int           `json:"filledAmount" bson:"filledAmount"`
+	Nonce           *big.Int           `json:"nonce" bson:"nonce"`
+	PairName        string             `json:"pairName" bson:"pairName"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+	OrderID         uint64             `json:"orderID,omitempty" bson:"orderID"`
+	NextOrder       []byte             `json:"-"`
+	PrevOrder       []byte             `json:"-"`
+	OrderList       []byte             `json:"-"`
+	Key             string             `json:"key" bson:"key"`
+	TimeInForce     string             `json:"timeInForce,omitempty" bson:"timeInForce"`
+	// Expires is the unix timestamp at which a GTD order is cancelled
+	Expires int64 `json:"expires,omitempty" bson:"expires"`
+	// CancelSignature signs the cancel that the relayer sends on behalf of the user to enforce
+	// the time in force of IOC, FOK and GTD orders. The cancel uses the nonce following the
+	// nonce of the order
+	CancelSignature *Signature `json:"cancelSignature,omitempty" bson:"-"`
+	// PostOnly orders are rejected instead of taking liquidity from the orderbook
+	PostOnly bool `json:"postOnly,omitempty" bson:"postOnly"`
+	// ClientOrderID is an optional ID chosen by the user, unique for the user for
+	// ClientOrderTTL, with which a retried submission is not published again
+	ClientOrderID string `json:"clientOrderId,omitempty" bson:"clientOrderId"`
 }
 
 // OrderRes use for api
@@ -61,6 +85,14 @@ type OrderRes struct {
 	Orders []*Order `json:"orders" bson:"orders"`
 }
 
+// OrderBatchResult is the result of one item of a batch of new orders or order cancels
+type OrderBatchResult struct {
+	Hash     common.Hash `json:"hash"`
+	Accepted bool        `json:"accepted"`
+	Error    string      `json:"error,omitempty"`
+	Code     string      `json:"code,omitempty"`
+}
+
 // OrderSpec contains field for filter
 type OrderSpec struct {
 	UserAddress string
@@ -72,6 +104,8 @@ type OrderSpec struct {
 	DateFrom    int64
 	DateTo      int64
 	OrderHash   string
+	// ClientOrderID is only unique for a user, so UserAddress is then required
+	ClientOrderID string
 }
 
 func (o *Order) String() string {
@@ -123,6 +157,26 @@ func (o *Order) Validate() error {
 		return errors.New("Order 'amount' parameter should be strictly positive")
 	}
 
+	if err := o.validateTimeInForce(); err != nil {
+		return err
+	}
+
+	if o.ClientOrderID != "" {
+		if err := ValidateClientOrderID(o.ClientOrderID); err != nil {
+			return err
+		}
+	}
+
+	if o.PostOnly {
+		if o.Type != TypeLimitOrder {
+			return errors.New("Order 'postOnly' parameter is only supported by limit orders")
+		}
+
+		if o.TimeInForce == TimeInForceIOC || o.TimeInForce == TimeInForceFOK {
+			return errors.New("Order 'postOnly' parameter cannot be used with 'IOC' or 'FOK' orders")
+		}
+	}
+
 	valid, err := o.VerifySignature()
 	if err != nil {
 		return err
@@ -132,9 +186,62 @@ func (o *Order) Validate() error {
 		return errors.New("Order 'signature' parameter is invalid")
 	}
 
+	if o.HasTimeInForceCancel() {
+		valid, err = o.TimeInForceCancel().VerifySignature(o)
+		if err != nil || !valid {
+			return errors.New("Order 'cancelSignature' parameter is invalid")
+		}
+	}
+
 	return nil
 }
 
+func (o *Order) validateTimeInForce() error {
+	switch o.TimeInForce {
+	case "", TimeInForceGTC, TimeInForceIOC, TimeInForceFOK:
+	case TimeInForceGTD:
+		if o.Expires <= time.Now().Unix() {
+			return errors.New("Order 'expires' parameter should be in the future")
+		}
+	default:
+		return errors.New("Order 'timeInForce' should be 'GTC', 'IOC', 'FOK' or 'GTD', but got: '" + o.TimeInForce + "'")
+	}
+
+	if !o.HasTimeInForceCancel() {
+		return nil
+	}
+
+	if o.Type != TypeLimitOrder {
+		return errors.New("Order 'timeInForce' parameter is only supported by limit orders")
+	}
+
+	if o.CancelSignature == nil {
+		return errors.New("Order 'cancelSignature' parameter is required")
+	}
+
+	return nil
+}
+
+// HasTimeInForceCancel returns true if the time in force of the order is enforced by cancelling it
+func (o *Order) HasTimeInForceCancel() bool {
+	return o.TimeInForce == TimeInForceIOC || o.TimeInForce == TimeInForceFOK || o.TimeInForce == TimeInForceGTD
+}
+
+// TimeInForceCancel returns the cancel signed by the user to enforce the time in force of the order
+func (o *Order) TimeInForceCancel() *OrderCancel {
+	oc := &OrderCancel{
+		OrderHash:       o.Hash,
+		Nonce:           math.Add(o.Nonce, big.NewInt(1)),
+		Status:          OrderStatusCancelled,
+		UserAddress:     o.UserAddress,
+		ExchangeAddress: o.ExchangeAddress,
+		Signature:       o.CancelSignature,
+	}
+
+	oc.Hash = oc.ComputeHash()
+	return oc
+}
+
 // ComputeHash calculates the orderRequest hash
 func (o *Order) ComputeHash() common.Hash {
 	sha := sha3.NewKeccak256()
@@ -235,7 +342,7 @@ func (o *Order) SellTokenSymbol() string {
 	return ""
 }
 
-//TODO handle error case
+// TODO handle error case
 func (o *Order) SellToken() common.Address {
 	if o.Side == BUY {
 		return o.QuoteToken
@@ -318,7 +425,7 @@ func (o *Order) BuyAmount(pairMultiplier *big.Int) *big.Int {
 	}
 }
 
-//TODO handle error case ?
+// TODO handle error case ?
 func (o *Order) EncodedSide() *big.Int {
 	if o.Side == BUY {
 		return big.NewInt(0)
@@ -398,6 +505,23 @@ func (o *Order) MarshalJSON() ([]byte, error) {
 			"S": o.Signature.S,
 		}
 	}
+
+	if o.TimeInForce != "" {
+		order["timeInForce"] = o.TimeInForce
+	}
+
+	if o.PostOnly {
+		order["postOnly"] = true
+	}
+
+	if o.ClientOrderID != "" {
+		order["clientOrderId"] = o.ClientOrderID
+	}
+
+	if o.Expires != 0 {
+		order["expires"] = strconv.FormatInt(o.Expires, 10)
+	}
+
 	return json.Marshal(order)
 }
 
@@ -410,8 +534,8 @@ func (o *Order) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	if order["id"] != nil && bson.IsObjectIdHex(order["id"].(string)) {
-		o.ID = bson.ObjectIdHex(order["id"].(string))
+	if order["id"] != nil && primitive.IsValidObjectID(order["id"].(string)) {
+		o.ID, _ = primitive.ObjectIDFromHex(order["id"].(string))
 	}
 
 	if order["pairName"] != nil {
@@ -475,6 +599,34 @@ func (o *Order) UnmarshalJSON(b []byte) error {
 		}
 	}
 
+	if order["timeInForce"] != nil {
+		o.TimeInForce = order["timeInForce"].(string)
+	}
+
+	if postOnly, ok := order["postOnly"].(bool); ok {
+		o.PostOnly = postOnly
+	}
+
+	if clientOrderID, ok := order["clientOrderId"].(string); ok {
+		o.ClientOrderID = clientOrderID
+	}
+
+	switch expires := order["expires"].(type) {
+	case float64:
+		o.Expires = int64(expires)
+	case string:
+		o.Expires, _ = strconv.ParseInt(expires, 10, 64)
+	}
+
+	if order["cancelSignature"] != nil {
+		signature := order["cancelSignature"].(map[string]interface{})
+		o.CancelSignature = &Signature{
+			V: byte(signature["V"].(float64)),
+			R: common.HexToHash(signature["R"].(string)),
+			S: common.HexToHash(signature["S"].(string)),
+		}
+	}
+
 	if order["createdAt"] != nil {
 		t, _ := time.Parse(time.RFC3339Nano, order["createdAt"].(string))
 		o.CreatedAt = t
@@ -501,8 +653,8 @@ func (o *Order) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-// GetBSON return bson
-func (o *Order) GetBSON() (interface{}, error) {
+// MarshalBSON return bson
+func (o *Order) MarshalBSON() ([]byte, error) {
 	or := OrderRecord{
 		PairName:        o.PairName,
 		ExchangeAddress: o.ExchangeAddress.Hex(),
@@ -523,10 +675,14 @@ func (o *Order) GetBSON() (interface{}, error) {
 		PrevOrder:       common.Bytes2Hex(o.PrevOrder),
 		OrderList:       common.Bytes2Hex(o.OrderList),
 		Key:             o.Key,
+		TimeInForce:     o.TimeInForce,
+		Expires:         o.Expires,
+		PostOnly:        o.PostOnly,
+		ClientOrderID:   o.ClientOrderID,
 	}
 
-	if o.ID.Hex() == "" {
-		or.ID = bson.NewObjectId()
+	if o.ID.IsZero() {
+		or.ID = primitive.NewObjectID()
 	} else {
 		or.ID = o.ID
 	}
@@ -543,36 +699,40 @@ func (o *Order) GetBSON() (interface{}, error) {
 		}
 	}
 
-	return or, nil
+	return bson.Marshal(or)
 }
 
-func (o *Order) SetBSON(raw bson.Raw) error {
+func (o *Order) UnmarshalBSON(data []byte) error {
 	decoded := new(struct {
-		ID              bson.ObjectId    `json:"id,omitempty" bson:"_id"`
-		PairName        string           `json:"pairName" bson:"pairName"`
-		ExchangeAddress string           `json:"exchangeAddress" bson:"exchangeAddress"`
-		UserAddress     string           `json:"userAddress" bson:"userAddress"`
-		BaseToken       string           `json:"baseToken" bson:"baseToken"`
-		QuoteToken      string           `json:"quoteToken" bson:"quoteToken"`
-		Status          string           `json:"status" bson:"status"`
-		Side            string           `json:"side" bson:"side"`
-		Type            string           `json:"type" bson:"type"`
-		Hash            string           `json:"hash" bson:"hash"`
-		Price           string           `json:"price" bson:"price"`
-		Quantity        string           `json:"quantity" bson:"quantity"`
-		FilledAmount    string           `json:"filledAmount" bson:"filledAmount"`
-		Nonce           string           `json:"nonce" bson:"nonce"`
-		Signature       *SignatureRecord `json:"signature" bson:"signature"`
-		CreatedAt       time.Time        `json:"createdAt" bson:"createdAt"`
-		UpdatedAt       time.Time        `json:"updatedAt" bson:"updatedAt"`
-		OrderID         string           `json:"orderID" bson:"orderID"`
-		NextOrder       string           `json:"-"`
-		PrevOrder       string           `json:"-"`
-		OrderList       string           `json:"-"`
-		Key             string           `json:"key" bson:"key"`
+		ID              primitive.ObjectID `json:"id,omitempty" bson:"_id"`
+		PairName        string             `json:"pairName" bson:"pairName"`
+		ExchangeAddress string             `json:"exchangeAddress" bson:"exchangeAddress"`
+		UserAddress     string             `json:"userAddress" bson:"userAddress"`
+		BaseToken       string             `json:"baseToken" bson:"baseToken"`
+		QuoteToken      string             `json:"quoteToken" bson:"quoteToken"`
+		Status          string             `json:"status" bson:"status"`
+		Side            string             `json:"side" bson:"side"`
+		Type            string             `json:"type" bson:"type"`
+		Hash            string             `json:"hash" bson:"hash"`
+		Price           string             `json:"price" bson:"price"`
+		Quantity        string             `json:"quantity" bson:"quantity"`
+		FilledAmount    string             `json:"filledAmount" bson:"filledAmount"`
+		Nonce           string             `json:"nonce" bson:"nonce"`
+		Signature       *SignatureRecord   `json:"signature" bson:"signature"`
+		CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+		UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+		OrderID         string             `json:"orderID" bson:"orderID"`
+		NextOrder       string             `json:"-"`
+		PrevOrder       string             `json:"-"`
+		OrderList       string             `json:"-"`
+		Key             string             `json:"key" bson:"key"`
+		TimeInForce     string             `json:"timeInForce" bson:"timeInForce"`
+		Expires         int64              `json:"expires" bson:"expires"`
+		PostOnly        bool               `json:"postOnly" bson:"postOnly"`
+		ClientOrderID   string             `json:"clientOrderId" bson:"clientOrderId"`
 	})
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -622,41 +782,49 @@ func (o *Order) SetBSON(raw bson.Raw) error {
 	o.PrevOrder = common.Hex2Bytes(decoded.PrevOrder)
 	o.OrderList = common.Hex2Bytes(decoded.OrderList)
 	o.Key = decoded.Key
+	o.TimeInForce = decoded.TimeInForce
+	o.Expires = decoded.Expires
+	o.PostOnly = decoded.PostOnly
+	o.ClientOrderID = decoded.ClientOrderID
 
 	return nil
 }
 
 // OrderRecord is the object that will be saved in the database
 type OrderRecord struct {
-	ID              bson.ObjectId    `json:"id" bson:"_id"`
-	UserAddress     string           `json:"userAddress" bson:"userAddress"`
-	ExchangeAddress string           `json:"exchangeAddress" bson:"exchangeAddress"`
-	BaseToken       string           `json:"baseToken" bson:"baseToken"`
-	QuoteToken      string           `json:"quoteToken" bson:"quoteToken"`
-	Status          string           `json:"status" bson:"status"`
-	Side            string           `json:"side" bson:"side"`
-	Type            string           `json:"type" bson:"type"`
-	Hash            string           `json:"hash" bson:"hash"`
-	Price           string           `json:"price" bson:"price"`
-	Quantity        string           `json:"quantity" bson:"quantity"`
-	FilledAmount    string           `json:"filledAmount" bson:"filledAmount"`
-	Nonce           string           `json:"nonce" bson:"nonce"`
-	Signature       *SignatureRecord `json:"signature,omitempty" bson:"signature"`
-	PairName        string           `json:"pairName" bson:"pairName"`
-	CreatedAt       time.Time        `json:"createdAt" bson:"createdAt"`
-	UpdatedAt       time.Time        `json:"updatedAt" bson:"updatedAt"`
-	OrderID         string           `json:"orderID,omitempty" bson:"orderID"`
-	NextOrder       string           `json:"nextOrder,omitempty" bson:"nextOrder"`
-	PrevOrder       string           `json:"prevOrder,omitempty" bson:"prevOrder"`
-	OrderList       string           `json:"orderList,omitempty" bson:"orderList"`
-	Key             string           `json:"key" bson:"key"`
+	ID              primitive.ObjectID `json:"id" bson:"_id"`
+	UserAddress     string             `json:"userAddress" bson:"userAddress"`
+	ExchangeAddress string             `json:"exchangeAddress" bson:"exchangeAddress"`
+	BaseToken       string             `json:"baseToken" bson:"baseToken"`
+	QuoteToken      string             `json:"quoteToken" bson:"quoteToken"`
+	Status          string             `json:"status" bson:"status"`
+	Side            string             `json:"side" bson:"side"`
+	Type            string             `json:"type" bson:"type"`
+	Hash            string             `json:"hash" bson:"hash"`
+	Price           string             `json:"price" bson:"price"`
+	Quantity        string             `json:"quantity" bson:"quantity"`
+	FilledAmount    string             `json:"filledAmount" bson:"filledAmount"`
+	Nonce           string             `json:"nonce" bson:"nonce"`
+	Signature       *SignatureRecord   `json:"signature,omitempty" bson:"signature"`
+	PairName        string             `json:"pairName" bson:"pairName"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+	OrderID         string             `json:"orderID,omitempty" bson:"orderID"`
+	NextOrder       string             `json:"nextOrder,omitempty" bson:"nextOrder"`
+	PrevOrder       string             `json:"prevOrder,omitempty" bson:"prevOrder"`
+	OrderList       string             `json:"orderList,omitempty" bson:"orderList"`
+	Key             string             `json:"key" bson:"key"`
+	TimeInForce     string             `json:"timeInForce,omitempty" bson:"timeInForce,omitempty"`
+	Expires         int64              `json:"expires,omitempty" bson:"expires,omitempty"`
+	PostOnly        bool               `json:"postOnly,omitempty" bson:"postOnly,omitempty"`
+	ClientOrderID   string             `json:"clientOrderId,omitempty" bson:"clientOrderId,omitempty"`
 }
 
 type OrderBSONUpdate struct {
 	*Order
 }
 
-func (o OrderBSONUpdate) GetBSON() (interface{}, error) {
+func (o OrderBSONUpdate) MarshalBSON() ([]byte, error) {
 	now := time.Now()
 
 	set := bson.M{
@@ -687,7 +855,7 @@ func (o OrderBSONUpdate) GetBSON() (interface{}, error) {
 	}
 
 	setOnInsert := bson.M{
-		"_id":       bson.NewObjectId(),
+		"_id":       primitive.NewObjectID(),
 		"hash":      o.Hash.Hex(),
 		"createdAt": now,
 	}
@@ -697,7 +865,7 @@ func (o OrderBSONUpdate) GetBSON() (interface{}, error) {
 		"$setOnInsert": setOnInsert,
 	}
 
-	return update, nil
+	return bson.Marshal(update)
 }
 
 type OrderData struct {
@@ -777,19 +945,19 @@ func (o *OrderData) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (o *OrderData) GetBSON() (interface{}, error) {
+func (o *OrderData) MarshalBSON() ([]byte, error) {
 	type PairID struct {
 		PairName   string `json:"pairName" bson:"pairName"`
 		BaseToken  string `json:"baseToken" bson:"baseToken"`
 		QuoteToken string `json:"quoteToken" bson:"quoteToken"`
 	}
 
-	count, err := bson.ParseDecimal128(o.OrderCount.String())
+	count, err := primitive.ParseDecimal128(o.OrderCount.String())
 	if err != nil {
 		return nil, err
 	}
 
-	volume, err := bson.ParseDecimal128(o.OrderVolume.String())
+	volume, err := primitive.ParseDecimal128(o.OrderVolume.String())
 	if err != nil {
 		return nil, err
 	}
@@ -799,11 +967,11 @@ func (o *OrderData) GetBSON() (interface{}, error) {
 		return nil, err
 	}
 
-	return struct {
-		ID          PairID          `json:"id,omitempty" bson:"_id"`
-		OrderVolume bson.Decimal128 `json:"orderCount" bson:"orderCount"`
-		OrderCount  bson.Decimal128 `json:"orderVolume" bson:"orderVolume"`
-		BestPrice   string          `json:"bestPrice" bson:"bestPrice"`
+	return bson.Marshal(struct {
+		ID          PairID               `json:"id,omitempty" bson:"_id"`
+		OrderVolume primitive.Decimal128 `json:"orderCount" bson:"orderCount"`
+		OrderCount  primitive.Decimal128 `json:"orderVolume" bson:"orderVolume"`
+		BestPrice   string               `json:"bestPrice" bson:"bestPrice"`
 	}{
 		ID: PairID{
 			o.Pair.PairName,
@@ -813,10 +981,10 @@ func (o *OrderData) GetBSON() (interface{}, error) {
 		OrderVolume: volume,
 		OrderCount:  count,
 		BestPrice:   bestPrice,
-	}, nil
+	})
 }
 
-func (o *OrderData) SetBSON(raw bson.Raw) error {
+func (o *OrderData) UnmarshalBSON(data []byte) error {
 	type PairIDRecord struct {
 		PairName   string `json:"pairName" bson:"pairName"`
 		BaseToken  string `json:"baseToken" bson:"baseToken"`
@@ -824,13 +992,13 @@ func (o *OrderData) SetBSON(raw bson.Raw) error {
 	}
 
 	decoded := new(struct {
-		Pair        PairIDRecord    `json:"pair,omitempty" bson:"_id"`
-		OrderCount  bson.Decimal128 `json:"orderCount" bson:"orderCount"`
-		OrderVolume bson.Decimal128 `json:"orderVolume" bson:"orderVolume"`
-		BestPrice   string          `json:"bestPrice" bson:"bestPrice"`
+		Pair        PairIDRecord         `json:"pair,omitempty" bson:"_id"`
+		OrderCount  primitive.Decimal128 `json:"orderCount" bson:"orderCount"`
+		OrderVolume primitive.Decimal128 `json:"orderVolume" bson:"orderVolume"`
+		BestPrice   string               `json:"bestPrice" bson:"bestPrice"`
 	})
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,146 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	OrderExpiryStatusPending = "PENDING"
+	OrderExpiryStatusDone    = "DONE"
+	OrderExpiryStatusFailed  = "FAILED"
+)
+
+// OrderExpiry records when an IOC, FOK or GTD order has to be cancelled, together with
+// the cancel signature the user provided when submitting the order
+type OrderExpiry struct {
+	ID              primitive.ObjectID
+	OrderHash       common.Hash
+	UserAddress     common.Address
+	ExchangeAddress common.Address
+	Nonce           *big.Int
+	TimeInForce     string
+	ExpiresAt       time.Time
+	CancelSignature *Signature
+	Status          string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// OrderExpiryRecord is the BSON representation of an OrderExpiry
+type OrderExpiryRecord struct {
+	ID              primitive.ObjectID `bson:"_id"`
+	OrderHash       string             `bson:"orderHash"`
+	UserAddress     string             `bson:"userAddress"`
+	ExchangeAddress string             `bson:"exchangeAddress"`
+	Nonce           string             `bson:"nonce"`
+	TimeInForce     string             `bson:"timeInForce"`
+	ExpiresAt       time.Time          `bson:"expiresAt"`
+	CancelSignature *SignatureRecord   `bson:"cancelSignature"`
+	Status          string             `bson:"status"`
+	CreatedAt       time.Time          `bson:"createdAt"`
+	UpdatedAt       time.Time          `bson:"updatedAt"`
+}
+
+// NewOrderExpiry returns the expiry of an order with a time in force. IOC and FOK orders
+// expire as soon as they have been processed by the engine
+func NewOrderExpiry(o *Order) *OrderExpiry {
+	e := &OrderExpiry{
+		OrderHash:       o.Hash,
+		UserAddress:     o.UserAddress,
+		ExchangeAddress: o.ExchangeAddress,
+		Nonce:           o.Nonce,
+		TimeInForce:     o.TimeInForce,
+		ExpiresAt:       time.Now(),
+		CancelSignature: o.CancelSignature,
+		Status:          OrderExpiryStatusPending,
+	}
+
+	if o.TimeInForce == TimeInForceGTD {
+		e.ExpiresAt = time.Unix(o.Expires, 0)
+	}
+
+	return e
+}
+
+// Cancel returns the cancel pre-signed by the user for the expired order
+func (e *OrderExpiry) Cancel() *OrderCancel {
+	oc := &OrderCancel{
+		OrderHash:       e.OrderHash,
+		Nonce:           math.Add(e.Nonce, big.NewInt(1)),
+		Status:          OrderStatusCancelled,
+		UserAddress:     e.UserAddress,
+		ExchangeAddress: e.ExchangeAddress,
+		Signature:       e.CancelSignature,
+	}
+
+	oc.Hash = oc.ComputeHash()
+	return oc
+}
+
+// MarshalBSON implements the bson.Marshaler interface
+func (e *OrderExpiry) MarshalBSON() ([]byte, error) {
+	r := OrderExpiryRecord{
+		ID:              e.ID,
+		OrderHash:       e.OrderHash.Hex(),
+		UserAddress:     e.UserAddress.Hex(),
+		ExchangeAddress: e.ExchangeAddress.Hex(),
+		Nonce:           e.Nonce.String(),
+		TimeInForce:     e.TimeInForce,
+		ExpiresAt:       e.ExpiresAt,
+		Status:          e.Status,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}
+
+	if e.ID.IsZero() {
+		r.ID = primitive.NewObjectID()
+	}
+
+	if e.CancelSignature != nil {
+		r.CancelSignature = &SignatureRecord{
+			V: e.CancelSignature.V,
+			R: e.CancelSignature.R.Hex(),
+			S: e.CancelSignature.S.Hex(),
+		}
+	}
+
+	return bson.Marshal(r)
+}
+
+// UnmarshalBSON implements the bson.Unmarshaler interface
+func (e *OrderExpiry) UnmarshalBSON(data []byte) error {
+	decoded := new(OrderExpiryRecord)
+
+	err := bson.Unmarshal(data, decoded)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	e.ID = decoded.ID
+	e.OrderHash = common.HexToHash(decoded.OrderHash)
+	e.UserAddress = common.HexToAddress(decoded.UserAddress)
+	e.ExchangeAddress = common.HexToAddress(decoded.ExchangeAddress)
+	e.Nonce = math.ToBigInt(decoded.Nonce)
+	e.TimeInForce = decoded.TimeInForce
+	e.ExpiresAt = decoded.ExpiresAt
+	e.Status = decoded.Status
+	e.CreatedAt = decoded.CreatedAt
+	e.UpdatedAt = decoded.UpdatedAt
+
+	if decoded.CancelSignature != nil {
+		e.CancelSignature = &Signature{
+			V: decoded.CancelSignature.V,
+			R: common.HexToHash(decoded.CancelSignature.R),
+			S: common.HexToHash(decoded.CancelSignature.S),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// OrderReplace is a request to cancel an order and to place a new order in its place.
+// Both the cancel and the new order are signed by the owner of the cancelled order,
+// and the new order uses the nonce that follows the nonce of the cancel
+type OrderReplace struct {
+	Cancel *OrderCancel `json:"cancel"`
+	Order  *Order       `json:"order"`
+}
+
+// OrderReplaceResult is the combined result of an order replacement
+type OrderReplaceResult struct {
+	CancelledOrder *Order `json:"cancelledOrder"`
+	Order          *Order `json:"order"`
+}
+
+// Validate checks that both halves of the replacement are present and well formed
+func (r *OrderReplace) Validate() error {
+	if r.Cancel == nil {
+		return errors.New("Replace 'cancel' parameter is required")
+	}
+
+	if r.Order == nil {
+		return errors.New("Replace 'order' parameter is required")
+	}
+
+	if r.Cancel.Nonce == nil || r.Order.Nonce == nil {
+		return errors.New("Replace 'nonce' parameters are required")
+	}
+
+	if r.Cancel.Hash != r.Cancel.ComputeHash() {
+		return errors.New("Invalid cancel hash")
+	}
+
+	if math.IsNotEqual(r.Order.Nonce, math.Add(r.Cancel.Nonce, big.NewInt(1))) {
+		return errors.New("Replace order 'nonce' should follow the cancel 'nonce'")
+	}
+
+	return r.Order.Validate()
+}
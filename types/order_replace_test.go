@@ -0,0 +1,56 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSignedOrderReplace(t *testing.T, cancelNonce, orderNonce int64) *OrderReplace {
+	w := NewWallet()
+
+	o := &Order{
+		UserAddress:     w.Address,
+		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
+		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteToken:      common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+		PricePoint:      big.NewInt(1000),
+		Amount:          big.NewInt(1000),
+		Side:            BUY,
+		Type:            TypeLimitOrder,
+		Nonce:           big.NewInt(orderNonce),
+	}
+
+	if err := o.Sign(w); err != nil {
+		t.Fatal(err)
+	}
+
+	oc := &OrderCancel{
+		OrderHash: common.HexToHash("0xb9070a2d333403c255ce71ddf6e795053599b2e885321de40353832b96d8880a"),
+		Nonce:     big.NewInt(cancelNonce),
+	}
+
+	if err := oc.Sign(w); err != nil {
+		t.Fatal(err)
+	}
+
+	return &OrderReplace{Cancel: oc, Order: o}
+}
+
+func TestOrderReplaceValidate(t *testing.T) {
+	r := newSignedOrderReplace(t, 1, 2)
+	assert.Nil(t, r.Validate())
+
+	r = newSignedOrderReplace(t, 1, 3)
+	assert.EqualError(t, r.Validate(), "Replace order 'nonce' should follow the cancel 'nonce'")
+
+	r = newSignedOrderReplace(t, 1, 2)
+	r.Cancel.Nonce = big.NewInt(0)
+	assert.EqualError(t, r.Validate(), "Invalid cancel hash")
+
+	r = newSignedOrderReplace(t, 1, 2)
+	r.Order = nil
+	assert.EqualError(t, r.Validate(), "Replace 'order' parameter is required")
+}
@@ -4,18 +4,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestBaseTokenSymbol(t *testing.T) {
 	o := &Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:              objectID("537f700b537461b70c5f0000"),
 		UserAddress:     common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
@@ -42,7 +44,7 @@ func TestBaseTokenSymbol(t *testing.T) {
 
 func TestQuoteTokenSymbol(t *testing.T) {
 	o := &Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:              objectID("537f700b537461b70c5f0000"),
 		UserAddress:     common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
@@ -70,7 +72,7 @@ func TestQuoteTokenSymbol(t *testing.T) {
 func TestOrderMarshal(t *testing.T) {
 
 	o := &Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:              objectID("537f700b537461b70c5f0000"),
 		UserAddress:     common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
@@ -135,7 +137,7 @@ func TestOrderMarshal(t *testing.T) {
 
 func TestOrderUnmarshal(t *testing.T) {
 	expected := Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:              objectID("537f700b537461b70c5f0000"),
 		UserAddress:     common.HexToAddress("0x14d281013d8ee8ccfa0eca87524e5b3cfa6152ba"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		QuoteToken:      common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
@@ -171,9 +173,9 @@ func TestOrderUnmarshal(t *testing.T) {
             "makeFee": "50",
             "takeFee": "50",
             "signature": {
-                "v": 28,
-                "r": "0x10b30eb0072a4f0a38b6fca0b731cba15eb2e1702845d97c1230b53a839bcb85",
-                "s": "0x6d9ad89548c9e3ce4c97825d027291477f2c44a8caef792095f2cabc978493ff"
+                "V": 28,
+                "R": "0x10b30eb0072a4f0a38b6fca0b731cba15eb2e1702845d97c1230b53a839bcb85",
+                "S": "0x6d9ad89548c9e3ce4c97825d027291477f2c44a8caef792095f2cabc978493ff"
             },
             "pairName": "ZRX/WETH",
             "hash":"0xb9070a2d333403c255ce71ddf6e795053599b2e885321de40353832b96d8880a"
@@ -192,7 +194,7 @@ func TestOrderUnmarshal(t *testing.T) {
 
 func TestOrderBSON(t *testing.T) {
 	order := &Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:              objectID("537f700b537461b70c5f0000"),
 		UserAddress:     common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
@@ -209,9 +211,10 @@ func TestOrderBSON(t *testing.T) {
 			R: common.HexToHash("0x10b30eb0072a4f0a38b6fca0b731cba15eb2e1702845d97c1230b53a839bcb85"),
 			S: common.HexToHash("0x6d9ad89548c9e3ce4c97825d027291477f2c44a8caef792095f2cabc978493ff"),
 		},
-		Hash:      common.HexToHash("0xb9070a2d333403c255ce71ddf6e795053599b2e885321de40353832b96d8880a"),
-		CreatedAt: time.Unix(1405544146, 0),
-		UpdatedAt: time.Unix(1405544146, 0),
+		Hash:          common.HexToHash("0xb9070a2d333403c255ce71ddf6e795053599b2e885321de40353832b96d8880a"),
+		CreatedAt:     time.Unix(1405544146, 0),
+		UpdatedAt:     time.Unix(1405544146, 0),
+		ClientOrderID: "bot-1",
 	}
 
 	data, err := bson.Marshal(order)
@@ -235,7 +238,7 @@ func TestOrderBSON(t *testing.T) {
 // 	tokenAddress2 := NewAddressFromString("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa")
 
 // 	tokenBalance1 := &TokenBalance{
-// 		ID:       bson.NewObjectId(),
+// 		ID:       primitive.NewObjectID(),
 // 		Address:  tokenAddress1,
 // 		Symbol:   "EOS",
 // 		Balance:       NewBigInt("10000"),
@@ -244,7 +247,7 @@ func TestOrderBSON(t *testing.T) {
 // 	}
 
 // 	tokenBalance2 := &TokenBalance{
-// 		ID:       bson.NewObjectId(),
+// 		ID:       primitive.NewObjectID(),
 // 		Address:  tokenAddress2,
 // 		Symbol:   "ZRX",
 // 		Balance:       NewBigInt("10000"),
@@ -253,7 +256,7 @@ func TestOrderBSON(t *testing.T) {
 // 	}
 
 // 	account := &Account{
-// 		ID:      bson.NewObjectId(),
+// 		ID:      primitive.NewObjectID(),
 // 		Address: address,
 // 		TokenBalances: map[common.Address]*TokenBalance{
 // 			tokenAddress1: tokenBalance1,
@@ -274,3 +277,92 @@ func TestOrderBSON(t *testing.T) {
 
 // 	assert.Equal(decoded, account)
 // }
+
+func TestOrderValidateTimeInForce(t *testing.T) {
+	w := NewWallet()
+
+	newOrder := func(tif string, expires int64) *Order {
+		o := &Order{
+			UserAddress:     w.Address,
+			ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
+			BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+			QuoteToken:      common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+			PricePoint:      big.NewInt(1000),
+			Amount:          big.NewInt(1000),
+			Side:            BUY,
+			Type:            TypeLimitOrder,
+			Nonce:           big.NewInt(1),
+			TimeInForce:     tif,
+			Expires:         expires,
+		}
+
+		if err := o.Sign(w); err != nil {
+			t.Fatal(err)
+		}
+
+		oc := o.TimeInForceCancel()
+		if err := oc.Sign(w); err != nil {
+			t.Fatal(err)
+		}
+
+		o.CancelSignature = oc.Signature
+		return o
+	}
+
+	assert.Nil(t, newOrder(TimeInForceGTC, 0).Validate())
+	assert.Nil(t, newOrder(TimeInForceIOC, 0).Validate())
+	assert.Nil(t, newOrder(TimeInForceGTD, time.Now().Add(time.Hour).Unix()).Validate())
+
+	assert.EqualError(t, newOrder("DAY", 0).Validate(), "Order 'timeInForce' should be 'GTC', 'IOC', 'FOK' or 'GTD', but got: 'DAY'")
+	assert.EqualError(t, newOrder(TimeInForceGTD, time.Now().Add(-time.Hour).Unix()).Validate(), "Order 'expires' parameter should be in the future")
+
+	o := newOrder(TimeInForceFOK, 0)
+	o.CancelSignature = nil
+	assert.EqualError(t, o.Validate(), "Order 'cancelSignature' parameter is required")
+
+	o = newOrder(TimeInForceFOK, 0)
+	o.CancelSignature = newOrder(TimeInForceFOK, 0).Signature
+	assert.EqualError(t, o.Validate(), "Order 'cancelSignature' parameter is invalid")
+}
+
+func TestOrderValidateClientOrderID(t *testing.T) {
+	w := NewWallet()
+
+	newOrder := func(clientOrderID string) *Order {
+		o := &Order{
+			UserAddress:     w.Address,
+			ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
+			BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+			QuoteToken:      common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+			PricePoint:      big.NewInt(1000),
+			Amount:          big.NewInt(1000),
+			Side:            BUY,
+			Type:            TypeLimitOrder,
+			Nonce:           big.NewInt(1),
+			ClientOrderID:   clientOrderID,
+		}
+
+		if err := o.Sign(w); err != nil {
+			t.Fatal(err)
+		}
+
+		return o
+	}
+
+	assert.Nil(t, newOrder("").Validate())
+	assert.Nil(t, newOrder("bot_1-A").Validate())
+
+	msg := "Order 'clientOrderId' parameter should have 1 to 64 letters, digits, '-' or '_'"
+	assert.EqualError(t, newOrder("bot 1").Validate(), msg)
+	assert.EqualError(t, newOrder(strings.Repeat("a", 65)).Validate(), msg)
+
+	o := &Order{}
+	err := json.Unmarshal([]byte(`{"clientOrderId": "bot-1"}`), o)
+	assert.Nil(t, err)
+	assert.Equal(t, "bot-1", o.ClientOrderID)
+}
+
+func objectID(hex string) primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(hex)
+	return id
+}
@@ -1,12 +1,136 @@
 package types
 
+import (
+	"math/big"
+	"sort"
+
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
 type OrderBook struct {
 	PairName string              `json:"pairName"`
 	Asks     []map[string]string `json:"asks"`
 	Bids     []map[string]string `json:"bids"`
+	// Sequence is the sequence of the last orderbook update included in the orderbook
+	Sequence uint64 `json:"sequence"`
 }
 
 type RawOrderBook struct {
 	PairName string   `json:"pairName"`
 	Orders   []*Order `json:"orders"`
+	// Sequence is the sequence of the last orderbook update included in the orderbook
+	Sequence uint64 `json:"sequence"`
+}
+
+// OrderBookOptions are the depth and the price grouping requested for an orderbook
+type OrderBookOptions struct {
+	// Depth is the maximum number of price levels on each side, 0 keeps all of them
+	Depth int `json:"depth,omitempty"`
+	// Grouping is the number of decimals of the grouped prices, nil keeps the pricepoints
+	Grouping *int `json:"grouping,omitempty"`
+}
+
+// IsDefault returns true if the options keep the orderbook as it is
+func (opts OrderBookOptions) IsDefault() bool {
+	return opts.Depth == 0 && opts.Grouping == nil
+}
+
+// Validate checks the options against the decimals of the pair, and returns the price step
+// of the grouping in pricepoint units. A pricepoint is a price multiplied by 10 to the power
+// of the quote token decimals, so grouping prices to n decimals groups the pricepoints by
+// 10 to the power of (quote token decimals - n). The step is nil without grouping
+func (opts OrderBookOptions) Validate(p *Pair) (*big.Int, error) {
+	if opts.Depth < 0 {
+		return nil, errors.New("Orderbook 'depth' parameter should be positive")
+	}
+
+	if opts.Grouping == nil {
+		return nil, nil
+	}
+
+	if *opts.Grouping < 0 {
+		return nil, errors.New("Orderbook 'grouping' parameter should be positive")
+	}
+
+	if *opts.Grouping > p.QuoteTokenDecimals {
+		return nil, errors.New("Orderbook 'grouping' parameter should not exceed the quote token decimals")
+	}
+
+	exp := big.NewInt(int64(p.QuoteTokenDecimals - *opts.Grouping))
+	return math.Exp(big.NewInt(10), exp), nil
+}
+
+// Aggregate returns the orderbook grouped by price step and cut to depth
+func (ob *OrderBook) Aggregate(step *big.Int, depth int) *OrderBook {
+	return &OrderBook{
+		PairName: ob.PairName,
+		Bids:     AggregateOrderBookLevels(ob.Bids, BUY, step, depth),
+		Asks:     AggregateOrderBookLevels(ob.Asks, SELL, step, depth),
+		Sequence: ob.Sequence,
+	}
+}
+
+// AggregateOrderBookLevels sums the amounts of the levels of one side of an orderbook into
+// buckets of step pricepoints, and returns the first depth buckets, best price first.
+// Bids are rounded down and asks are rounded up, so that a bucket never shows a better price
+// than the orders it holds. A nil step keeps the pricepoints and a depth of 0 keeps all the buckets
+func AggregateOrderBookLevels(levels []map[string]string, side string, step *big.Int, depth int) []map[string]string {
+	amounts := make(map[string]*big.Int)
+	for _, l := range levels {
+		pricepoint := math.ToBigInt(l["pricepoint"])
+		amount := math.ToBigInt(l["amount"])
+		if amount.Sign() <= 0 {
+			continue
+		}
+
+		key := groupPricepoint(pricepoint, side, step).String()
+		if a, ok := amounts[key]; ok {
+			amounts[key] = math.Add(a, amount)
+		} else {
+			amounts[key] = amount
+		}
+	}
+
+	return SortOrderBookLevels(amounts, side, depth)
+}
+
+// SortOrderBookLevels returns the amounts of one side of an orderbook as levels, best price
+// first, cut to depth if depth is not 0
+func SortOrderBookLevels(amounts map[string]*big.Int, side string, depth int) []map[string]string {
+	levels := []map[string]string{}
+	for pricepoint, amount := range amounts {
+		levels = append(levels, map[string]string{
+			"pricepoint": pricepoint,
+			"amount":     amount.String(),
+		})
+	}
+
+	sort.Slice(levels, func(i, j int) bool {
+		cmp := math.ToBigInt(levels[i]["pricepoint"]).Cmp(math.ToBigInt(levels[j]["pricepoint"]))
+		if side == BUY {
+			return cmp > 0
+		}
+
+		return cmp < 0
+	})
+
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+
+	return levels
+}
+
+func groupPricepoint(pricepoint *big.Int, side string, step *big.Int) *big.Int {
+	if step == nil || step.Cmp(big.NewInt(1)) <= 0 {
+		return pricepoint
+	}
+
+	bucket := math.Mul(math.Div(pricepoint, step), step)
+	if side == SELL && bucket.Cmp(pricepoint) < 0 {
+		bucket = math.Add(bucket, step)
+	}
+
+	return bucket
 }
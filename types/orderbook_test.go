@@ -0,0 +1,58 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateOrderBookLevels(t *testing.T) {
+	levels := []map[string]string{
+		{"pricepoint": "1012", "amount": "1"},
+		{"pricepoint": "1019", "amount": "2"},
+		{"pricepoint": "1020", "amount": "3"},
+		{"pricepoint": "1035", "amount": "4"},
+		{"pricepoint": "1040", "amount": "0"},
+	}
+
+	bids := AggregateOrderBookLevels(levels, BUY, big.NewInt(10), 0)
+	assert.Equal(t, []map[string]string{
+		{"pricepoint": "1030", "amount": "4"},
+		{"pricepoint": "1020", "amount": "3"},
+		{"pricepoint": "1010", "amount": "3"},
+	}, bids)
+
+	asks := AggregateOrderBookLevels(levels, SELL, big.NewInt(10), 2)
+	assert.Equal(t, []map[string]string{
+		{"pricepoint": "1020", "amount": "6"},
+		{"pricepoint": "1040", "amount": "4"},
+	}, asks)
+
+	raw := AggregateOrderBookLevels(levels, SELL, nil, 1)
+	assert.Equal(t, []map[string]string{{"pricepoint": "1012", "amount": "1"}}, raw)
+}
+
+func TestOrderBookOptionsValidate(t *testing.T) {
+	p := &Pair{QuoteTokenDecimals: 18}
+
+	step, err := OrderBookOptions{Depth: 20}.Validate(p)
+	assert.Nil(t, err)
+	assert.Nil(t, step)
+
+	grouping := 16
+	step, err = OrderBookOptions{Grouping: &grouping}.Validate(p)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(100), step)
+
+	grouping = 19
+	_, err = OrderBookOptions{Grouping: &grouping}.Validate(p)
+	assert.EqualError(t, err, "Orderbook 'grouping' parameter should not exceed the quote token decimals")
+
+	grouping = -1
+	_, err = OrderBookOptions{Grouping: &grouping}.Validate(p)
+	assert.EqualError(t, err, "Orderbook 'grouping' parameter should be positive")
+
+	_, err = OrderBookOptions{Depth: -1}.Validate(p)
+	assert.EqualError(t, err, "Orderbook 'depth' parameter should be positive")
+}
@@ -10,27 +10,28 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/tomochain/tomox-sdk/utils/math"
 
-	"github.com/globalsign/mgo/bson"
 	validation "github.com/go-ozzo/ozzo-validation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Pair struct is used to model the pair data in the system and DB
 type Pair struct {
-	ID                 bson.ObjectId  `json:"-" bson:"_id"`
-	BaseTokenSymbol    string         `json:"baseTokenSymbol,omitempty" bson:"baseTokenSymbol"`
-	BaseTokenAddress   common.Address `json:"baseTokenAddress,omitempty" bson:"baseTokenAddress"`
-	BaseTokenDecimals  int            `json:"baseTokenDecimals,omitempty" bson:"baseTokenDecimals"`
-	QuoteTokenSymbol   string         `json:"quoteTokenSymbol,omitempty" bson:"quoteTokenSymbol"`
-	QuoteTokenAddress  common.Address `json:"quoteTokenAddress,omitempty" bson:"quoteTokenAddress"`
-	QuoteTokenDecimals int            `json:"quoteTokenDecimals,omitempty" bson:"quoteTokenDecimals"`
-	Listed             bool           `json:"listed,omitempty" bson:"listed"`
-	Active             bool           `json:"active,omitempty" bson:"active"`
-	Rank               int            `json:"rank,omitempty" bson:"rank"`
-	MakeFee            *big.Int       `json:"makeFee,omitempty" bson:"makeFee"`
-	TakeFee            *big.Int       `json:"takeFee,omitempty" bson:"takeFee"`
-	RelayerAddress     common.Address `json:"relayerAddress,omitempty" bson:"relayerAddress"`
-	CreatedAt          time.Time      `json:"-" bson:"createdAt"`
-	UpdatedAt          time.Time      `json:"-" bson:"updatedAt"`
+	ID                 primitive.ObjectID `json:"-" bson:"_id"`
+	BaseTokenSymbol    string             `json:"baseTokenSymbol,omitempty" bson:"baseTokenSymbol"`
+	BaseTokenAddress   common.Address     `json:"baseTokenAddress,omitempty" bson:"baseTokenAddress"`
+	BaseTokenDecimals  int                `json:"baseTokenDecimals,omitempty" bson:"baseTokenDecimals"`
+	QuoteTokenSymbol   string             `json:"quoteTokenSymbol,omitempty" bson:"quoteTokenSymbol"`
+	QuoteTokenAddress  common.Address     `json:"quoteTokenAddress,omitempty" bson:"quoteTokenAddress"`
+	QuoteTokenDecimals int                `json:"quoteTokenDecimals,omitempty" bson:"quoteTokenDecimals"`
+	Listed             bool               `json:"listed,omitempty" bson:"listed"`
+	Active             bool               `json:"active,omitempty" bson:"active"`
+	Rank               int                `json:"rank,omitempty" bson:"rank"`
+	MakeFee            *big.Int           `json:"makeFee,omitempty" bson:"makeFee"`
+	TakeFee            *big.Int           `json:"takeFee,omitempty" bson:"takeFee"`
+	RelayerAddress     common.Address     `json:"relayerAddress,omitempty" bson:"relayerAddress"`
+	CreatedAt          time.Time          `json:"-" bson:"createdAt"`
+	UpdatedAt          time.Time          `json:"-" bson:"updatedAt"`
 }
 
 func (p *Pair) UnmarshalJSON(b []byte) error {
@@ -102,10 +103,10 @@ func (p *Pair) MarshalJSON() ([]byte, error) {
 	return json.Marshal(pair)
 }
 
-func (p *Pair) SetBSON(raw bson.Raw) error {
+func (p *Pair) UnmarshalBSON(data []byte) error {
 	decoded := &PairRecord{}
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		return err
 	}
@@ -135,8 +136,8 @@ func (p *Pair) SetBSON(raw bson.Raw) error {
 	return nil
 }
 
-func (p *Pair) GetBSON() (interface{}, error) {
-	return &PairRecord{
+func (p *Pair) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(&PairRecord{
 		ID:                 p.ID,
 		BaseTokenSymbol:    p.BaseTokenSymbol,
 		BaseTokenAddress:   p.BaseTokenAddress.Hex(),
@@ -152,7 +153,7 @@ func (p *Pair) GetBSON() (interface{}, error) {
 		TakeFee:            p.TakeFee.String(),
 		CreatedAt:          p.CreatedAt,
 		UpdatedAt:          p.UpdatedAt,
-	}, nil
+	})
 }
 
 func (p *Pair) BaseTokenMultiplier() *big.Int {
@@ -266,7 +267,7 @@ type PairAddressesRecord struct {
 }
 
 type PairRecord struct {
-	ID bson.ObjectId `json:"id" bson:"_id"`
+	ID primitive.ObjectID `json:"id" bson:"_id"`
 
 	BaseTokenSymbol    string    `json:"baseTokenSymbol" bson:"baseTokenSymbol"`
 	BaseTokenAddress   string    `json:"baseTokenAddress" bson:"baseTokenAddress"`
@@ -5,8 +5,9 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func ComparePair(t *testing.T, a, b *Pair) {
@@ -23,7 +24,7 @@ func ComparePair(t *testing.T, a, b *Pair) {
 
 func TestPairBSON(t *testing.T) {
 	pair := &Pair{
-		ID:                bson.NewObjectId(),
+		ID:                primitive.NewObjectID(),
 		BaseTokenSymbol:   "REQ",
 		BaseTokenAddress:  common.HexToAddress("0xcf7389dc6c63637598402907d5431160ec8972a5"),
 		QuoteTokenSymbol:  "WETH",
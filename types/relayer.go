@@ -6,31 +6,32 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/go-ozzo/ozzo-validation"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Relayer corresponds to a single Ethereum address. It contains a list of token balances for that address
 type Relayer struct {
-	ID         bson.ObjectId  `json:"-" bson:"_id"`
-	RID        int            `json:"rid" bson:"rid"`
-	Owner      common.Address `json:"owner" bson:"owner"`
-	Deposit    *big.Int       `json:"deposit" bson:"deposit"`
-	Address    common.Address `json:"address" bson:"address"`
-	Domain     string         `json:"domain" bson:"domain"`
-	Name       string         `json:"name" bson:"name"`
-	Resign     bool           `json:"resign" bson:"resign"`
-	LockTime   int            `json:"lockTime" bson:"lockTime"`
-	MakeFee    *big.Int       `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
-	TakeFee    *big.Int       `json:"takeFee,omitempty" bson:"makeFee,omitempty"`
-	LendingFee *big.Int       `json:"lendingFee,omitempty" bson:"lendingFee,omitempty"`
-	CreatedAt  time.Time      `json:"createdAt" bson:"createdAt"`
-	UpdatedAt  time.Time      `json:"updatedAt" bson:"updatedAt"`
+	ID         primitive.ObjectID `json:"-" bson:"_id"`
+	RID        int                `json:"rid" bson:"rid"`
+	Owner      common.Address     `json:"owner" bson:"owner"`
+	Deposit    *big.Int           `json:"deposit" bson:"deposit"`
+	Address    common.Address     `json:"address" bson:"address"`
+	Domain     string             `json:"domain" bson:"domain"`
+	Name       string             `json:"name" bson:"name"`
+	Resign     bool               `json:"resign" bson:"resign"`
+	LockTime   int                `json:"lockTime" bson:"lockTime"`
+	MakeFee    *big.Int           `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
+	TakeFee    *big.Int           `json:"takeFee,omitempty" bson:"makeFee,omitempty"`
+	LendingFee *big.Int           `json:"lendingFee,omitempty" bson:"lendingFee,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
 
-// GetBSON implements bson.Getter
-func (a *Relayer) GetBSON() (interface{}, error) {
+// MarshalBSON implements bson.Marshaler
+func (a *Relayer) MarshalBSON() ([]byte, error) {
 	ar := RelayerRecord{
 		RID:       a.RID,
 		Owner:     a.Owner.Hex(),
@@ -44,8 +45,8 @@ func (a *Relayer) GetBSON() (interface{}, error) {
 		UpdatedAt: a.UpdatedAt,
 	}
 
-	if a.ID.Hex() == "" {
-		ar.ID = bson.NewObjectId()
+	if a.ID.IsZero() {
+		ar.ID = primitive.NewObjectID()
 	} else {
 		ar.ID = a.ID
 	}
@@ -62,14 +63,14 @@ func (a *Relayer) GetBSON() (interface{}, error) {
 		ar.LendingFee = a.LendingFee.String()
 	}
 
-	return ar, nil
+	return bson.Marshal(ar)
 }
 
-// SetBSON implemenets bson.Setter
-func (a *Relayer) SetBSON(raw bson.Raw) error {
+// UnmarshalBSON implemenets bson.Unmarshaler
+func (a *Relayer) UnmarshalBSON(data []byte) error {
 	decoded := &RelayerRecord{}
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		return err
 	}
@@ -138,8 +139,8 @@ func (a *Relayer) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	if relayer["id"] != nil && bson.IsObjectIdHex(relayer["id"].(string)) {
-		a.ID = bson.ObjectIdHex(relayer["id"].(string))
+	if relayer["id"] != nil && primitive.IsValidObjectID(relayer["id"].(string)) {
+		a.ID, _ = primitive.ObjectIDFromHex(relayer["id"].(string))
 	}
 
 	if relayer["address"] != nil {
@@ -198,27 +199,27 @@ func (a Relayer) Validate() error {
 
 // RelayerRecord corresponds to what is stored in the DB. big.Ints are encoded as strings
 type RelayerRecord struct {
-	ID         bson.ObjectId `json:"id" bson:"_id"`
-	RID        int           `json:"rid" bson:"rid"`
-	Owner      string        `json:"owner" bson:"owner"`
-	Deposit    string        `json:"deposit" bson:"deposit"`
-	Address    string        `json:"address" bson:"address"`
-	Domain     string        `json:"domain" bson:"domain"`
-	Name       string        `json:"name" bson:"name"`
-	Resign     bool          `json:"resign" bson:"resign"`
-	LockTime   int           `json:"lockTime" bson:"lockTime"`
-	MakeFee    string        `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
-	TakeFee    string        `json:"takeFee,omitempty" bson:"takeFee,omitempty"`
-	LendingFee string        `json:"lendingFee,omitempty" bson:"lendingFee,omitempty"`
-	CreatedAt  time.Time     `json:"createdAt" bson:"createdAt"`
-	UpdatedAt  time.Time     `json:"updatedAt" bson:"updatedAt"`
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	RID        int                `json:"rid" bson:"rid"`
+	Owner      string             `json:"owner" bson:"owner"`
+	Deposit    string             `json:"deposit" bson:"deposit"`
+	Address    string             `json:"address" bson:"address"`
+	Domain     string             `json:"domain" bson:"domain"`
+	Name       string             `json:"name" bson:"name"`
+	Resign     bool               `json:"resign" bson:"resign"`
+	LockTime   int                `json:"lockTime" bson:"lockTime"`
+	MakeFee    string             `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
+	TakeFee    string             `json:"takeFee,omitempty" bson:"takeFee,omitempty"`
+	LendingFee string             `json:"lendingFee,omitempty" bson:"lendingFee,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
 
 type RelayerBSONUpdate struct {
 	*Relayer
 }
 
-func (a *RelayerBSONUpdate) GetBSON() (interface{}, error) {
+func (a *RelayerBSONUpdate) MarshalBSON() ([]byte, error) {
 	now := time.Now()
 
 	set := bson.M{
@@ -227,7 +228,7 @@ func (a *RelayerBSONUpdate) GetBSON() (interface{}, error) {
 	}
 
 	setOnInsert := bson.M{
-		"_id":       bson.NewObjectId(),
+		"_id":       primitive.NewObjectID(),
 		"createdAt": now,
 	}
 
@@ -236,5 +237,5 @@ func (a *RelayerBSONUpdate) GetBSON() (interface{}, error) {
 		"$setOnInsert": setOnInsert,
 	}
 
-	return update, nil
+	return bson.Marshal(update)
 }
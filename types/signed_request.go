@@ -0,0 +1,85 @@
+package types
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tomochain/tomox-sdk/errors"
+)
+
+// SignedRequest holds the parts of a REST request covered by its signature
+type SignedRequest struct {
+	Method    string
+	Path      string
+	Query     url.Values
+	Body      []byte
+	Timestamp int64
+	Nonce     string
+}
+
+// Canonical returns the string signed for the request. It holds the method, the escaped
+// path, the query sorted by key, the keccak256 hash of the body, the timestamp and the nonce,
+// one per line
+func (r *SignedRequest) Canonical() string {
+	return strings.Join([]string{
+		strings.ToUpper(r.Method),
+		r.Path,
+		r.Query.Encode(),
+		crypto.Keccak256Hash(r.Body).Hex(),
+		fmt.Sprintf("%d", r.Timestamp),
+		r.Nonce,
+	}, "\n")
+}
+
+// ComputeHash returns the keccak256 hash of the canonical string of the request
+func (r *SignedRequest) ComputeHash() common.Hash {
+	return crypto.Keccak256Hash([]byte(r.Canonical()))
+}
+
+// Sign signs the hash of the request with the wallet, with the "Ethereum Signed Message"
+// prefix, and returns the signature encoded as in the Signature header
+func (r *SignedRequest) Sign(w *Wallet) (string, error) {
+	sig, err := w.SignHash(r.ComputeHash())
+	if err != nil {
+		return "", err
+	}
+
+	b, err := sig.MarshalSignature()
+	if err != nil {
+		return "", err
+	}
+
+	b[64] += 27
+	return hexutil.Encode(b), nil
+}
+
+// RecoverAddress returns the address that signed the request. The signature is the hex
+// encoding of the 65 bytes r, s and v, with v either 0/1 or 27/28
+func (r *SignedRequest) RecoverAddress(signature string) (common.Address, error) {
+	b, err := hexutil.Decode(signature)
+	if err != nil || len(b) != 65 {
+		return common.Address{}, errors.New("Signature should be 65 hex encoded bytes")
+	}
+
+	v := b[64]
+	if v < 27 {
+		v += 27
+	}
+
+	sig := &Signature{
+		R: common.BytesToHash(b[0:32]),
+		S: common.BytesToHash(b[32:64]),
+		V: v,
+	}
+
+	message := crypto.Keccak256(
+		[]byte("\x19Ethereum Signed Message:\n32"),
+		r.ComputeHash().Bytes(),
+	)
+
+	return sig.Verify(common.BytesToHash(message))
+}
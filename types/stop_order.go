@@ -4,15 +4,17 @@ import (
 	"encoding/json"
 	"math/big"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/sha3"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -22,28 +24,38 @@ const (
 	StopOrderStatusOpen      = "OPEN"
 	StopOrderStatusDone      = "DONE"
 	StopOrderStatusCancelled = "CANCELLED"
+	StopOrderStatusRejected  = "REJECTED"
+
+	// StopOrderDirectionUp fires the stop order when the last price rises to the stop price
+	StopOrderDirectionUp = 1
+	// StopOrderDirectionDown fires the stop order when the last price falls to the stop price
+	StopOrderDirectionDown = -1
+
+	// stopPriceKeyLength is the number of digits of the largest uint256
+	stopPriceKeyLength = 78
 )
 
 type StopOrder struct {
-	ID              bson.ObjectId  `json:"id" bson:"_id"`
-	UserAddress     common.Address `json:"userAddress" bson:"userAddress"`
-	ExchangeAddress common.Address `json:"exchangeAddress" bson:"exchangeAddress"`
-	BaseToken       common.Address `json:"baseToken" bson:"baseToken"`
-	QuoteToken      common.Address `json:"quoteToken" bson:"quoteToken"`
-	Status          string         `json:"status" bson:"status"`
-	Side            string         `json:"side" bson:"side"`
-	Type            string         `json:"type" bson:"type"`
-	Hash            common.Hash    `json:"hash" bson:"hash"`
-	Signature       *Signature     `json:"signature,omitempty" bson:"signature"`
-	StopPrice       *big.Int       `json:"stopPrice" bson:"stopPrice"`
-	LimitPrice      *big.Int       `json:"limitPrice" bson:"limitPrice"`
-	Direction       int            `json:"direction" bson:"direction"`
-	Amount          *big.Int       `json:"amount" bson:"amount"`
-	FilledAmount    *big.Int       `json:"filledAmount" bson:"filledAmount"`
-	Nonce           *big.Int       `json:"nonce" bson:"nonce"`
-	PairName        string         `json:"pairName" bson:"pairName"`
-	CreatedAt       time.Time      `json:"createdAt" bson:"createdAt"`
-	UpdatedAt       time.Time      `json:"updatedAt" bson:"updatedAt"`
+	ID              primitive.ObjectID `json:"id" bson:"_id"`
+	UserAddress     common.Address     `json:"userAddress" bson:"userAddress"`
+	ExchangeAddress common.Address     `json:"exchangeAddress" bson:"exchangeAddress"`
+	BaseToken       common.Address     `json:"baseToken" bson:"baseToken"`
+	QuoteToken      common.Address     `json:"quoteToken" bson:"quoteToken"`
+	Status          string             `json:"status" bson:"status"`
+	Side            string             `json:"side" bson:"side"`
+	Type            string             `json:"type" bson:"type"`
+	Hash            common.Hash        `json:"hash" bson:"hash"`
+	Signature       *Signature         `json:"signature,omitempty" bson:"signature"`
+	OrderSignature  *Signature         `json:"orderSignature,omitempty" bson:"orderSignature"`
+	StopPrice       *big.Int           `json:"stopPrice" bson:"stopPrice"`
+	LimitPrice      *big.Int           `json:"limitPrice" bson:"limitPrice"`
+	Direction       int                `json:"direction" bson:"direction"`
+	Amount          *big.Int           `json:"amount" bson:"amount"`
+	FilledAmount    *big.Int           `json:"filledAmount" bson:"filledAmount"`
+	Nonce           *big.Int           `json:"nonce" bson:"nonce"`
+	PairName        string             `json:"pairName" bson:"pairName"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
 
 // MarshalJSON implements the json.Marshal interface
@@ -97,8 +109,8 @@ func (so *StopOrder) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	if order["id"] != nil && bson.IsObjectIdHex(order["id"].(string)) {
-		so.ID = bson.ObjectIdHex(order["id"].(string))
+	if order["id"] != nil && primitive.IsValidObjectID(order["id"].(string)) {
+		so.ID, _ = primitive.ObjectIDFromHex(order["id"].(string))
 	}
 
 	if order["pairName"] != nil {
@@ -176,6 +188,15 @@ func (so *StopOrder) UnmarshalJSON(b []byte) error {
 		}
 	}
 
+	if order["orderSignature"] != nil {
+		signature := order["orderSignature"].(map[string]interface{})
+		so.OrderSignature = &Signature{
+			V: byte(signature["V"].(float64)),
+			R: common.HexToHash(signature["R"].(string)),
+			S: common.HexToHash(signature["S"].(string)),
+		}
+	}
+
 	if order["createdAt"] != nil {
 		t, _ := time.Parse(time.RFC3339Nano, order["createdAt"].(string))
 		so.CreatedAt = t
@@ -189,7 +210,7 @@ func (so *StopOrder) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
-func (so *StopOrder) GetBSON() (interface{}, error) {
+func (so *StopOrder) MarshalBSON() ([]byte, error) {
 	or := StopOrderRecord{
 		PairName:        so.PairName,
 		ExchangeAddress: so.ExchangeAddress.Hex(),
@@ -202,6 +223,7 @@ func (so *StopOrder) GetBSON() (interface{}, error) {
 		Hash:            so.Hash.Hex(),
 		Amount:          so.Amount.String(),
 		StopPrice:       so.StopPrice.String(),
+		StopPriceKey:    StopPriceKey(so.StopPrice),
 		LimitPrice:      so.LimitPrice.String(),
 		Direction:       so.Direction,
 		Nonce:           so.Nonce.String(),
@@ -209,8 +231,8 @@ func (so *StopOrder) GetBSON() (interface{}, error) {
 		UpdatedAt:       so.UpdatedAt,
 	}
 
-	if so.ID.Hex() == "" {
-		or.ID = bson.NewObjectId()
+	if so.ID.IsZero() {
+		or.ID = primitive.NewObjectID()
 	} else {
 		or.ID = so.ID
 	}
@@ -227,33 +249,42 @@ func (so *StopOrder) GetBSON() (interface{}, error) {
 		}
 	}
 
-	return or, nil
+	if so.OrderSignature != nil {
+		or.OrderSignature = &SignatureRecord{
+			V: so.OrderSignature.V,
+			R: so.OrderSignature.R.Hex(),
+			S: so.OrderSignature.S.Hex(),
+		}
+	}
+
+	return bson.Marshal(or)
 }
 
-func (so *StopOrder) SetBSON(raw bson.Raw) error {
+func (so *StopOrder) UnmarshalBSON(data []byte) error {
 	decoded := new(struct {
-		ID              bson.ObjectId    `json:"id,omitempty" bson:"_id"`
-		PairName        string           `json:"pairName" bson:"pairName"`
-		ExchangeAddress string           `json:"exchangeAddress" bson:"exchangeAddress"`
-		UserAddress     string           `json:"userAddress" bson:"userAddress"`
-		BaseToken       string           `json:"baseToken" bson:"baseToken"`
-		QuoteToken      string           `json:"quoteToken" bson:"quoteToken"`
-		Status          string           `json:"status" bson:"status"`
-		Side            string           `json:"side" bson:"side"`
-		Type            string           `json:"type" bson:"type"`
-		Hash            string           `json:"hash" bson:"hash"`
-		StopPrice       string           `json:"stopPrice" bson:"stopPrice"`
-		LimitPrice      string           `json:"limitPrice" bson:"limitPrice"`
-		Direction       int              `json:"direction" bson:"direction"`
-		Amount          string           `json:"amount" bson:"amount"`
-		FilledAmount    string           `json:"filledAmount" bson:"filledAmount"`
-		Nonce           string           `json:"nonce" bson:"nonce"`
-		Signature       *SignatureRecord `json:"signature" bson:"signature"`
-		CreatedAt       time.Time        `json:"createdAt" bson:"createdAt"`
-		UpdatedAt       time.Time        `json:"updatedAt" bson:"updatedAt"`
+		ID              primitive.ObjectID `json:"id,omitempty" bson:"_id"`
+		PairName        string             `json:"pairName" bson:"pairName"`
+		ExchangeAddress string             `json:"exchangeAddress" bson:"exchangeAddress"`
+		UserAddress     string             `json:"userAddress" bson:"userAddress"`
+		BaseToken       string             `json:"baseToken" bson:"baseToken"`
+		QuoteToken      string             `json:"quoteToken" bson:"quoteToken"`
+		Status          string             `json:"status" bson:"status"`
+		Side            string             `json:"side" bson:"side"`
+		Type            string             `json:"type" bson:"type"`
+		Hash            string             `json:"hash" bson:"hash"`
+		StopPrice       string             `json:"stopPrice" bson:"stopPrice"`
+		LimitPrice      string             `json:"limitPrice" bson:"limitPrice"`
+		Direction       int                `json:"direction" bson:"direction"`
+		Amount          string             `json:"amount" bson:"amount"`
+		FilledAmount    string             `json:"filledAmount" bson:"filledAmount"`
+		Nonce           string             `json:"nonce" bson:"nonce"`
+		Signature       *SignatureRecord   `json:"signature" bson:"signature"`
+		OrderSignature  *SignatureRecord   `json:"orderSignature" bson:"orderSignature"`
+		CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+		UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
 	})
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -298,13 +329,23 @@ func (so *StopOrder) SetBSON(raw bson.Raw) error {
 		}
 	}
 
+	if decoded.OrderSignature != nil {
+		so.OrderSignature = &Signature{
+			V: byte(decoded.OrderSignature.V),
+			R: common.HexToHash(decoded.OrderSignature.R),
+			S: common.HexToHash(decoded.OrderSignature.S),
+		}
+	}
+
 	so.CreatedAt = decoded.CreatedAt
 	so.UpdatedAt = decoded.UpdatedAt
 
 	return nil
 }
 
-// ToOrder converts a stop order to a real order that will be pushed to TomoX
+// ToOrder converts a stop order to a real order that will be pushed to TomoX.
+// The order is signed with the order signature of the stop order, which is never
+// sent back to clients, so the order cannot be submitted before the stop is reached
 func (so *StopOrder) ToOrder() (*Order, error) {
 	var o *Order
 
@@ -318,8 +359,7 @@ func (so *StopOrder) ToOrder() (*Order, error) {
 			Status:          OrderStatusOpen,
 			Side:            so.Side,
 			Type:            TypeMarketOrder,
-			Hash:            so.Hash,
-			Signature:       so.Signature,
+			Signature:       so.OrderSignature,
 			PricePoint:      so.StopPrice,
 			Amount:          so.Amount,
 			FilledAmount:    big.NewInt(0),
@@ -337,8 +377,7 @@ func (so *StopOrder) ToOrder() (*Order, error) {
 			Status:          OrderStatusOpen,
 			Side:            so.Side,
 			Type:            TypeLimitOrder,
-			Hash:            so.Hash,
-			Signature:       so.Signature,
+			Signature:       so.OrderSignature,
 			PricePoint:      so.LimitPrice,
 			Amount:          so.Amount,
 			FilledAmount:    big.NewInt(0),
@@ -351,6 +390,8 @@ func (so *StopOrder) ToOrder() (*Order, error) {
 		return nil, errors.New("Unknown stop order type")
 	}
 
+	o.Hash = o.ComputeHash()
+
 	return o, nil
 }
 
@@ -392,6 +433,10 @@ func (so *StopOrder) Validate() error {
 		return errors.New("Order 'signature' parameter is required")
 	}
 
+	if so.OrderSignature == nil {
+		return errors.New("Order 'orderSignature' parameter is required")
+	}
+
 	if math.IsSmallerThan(so.Nonce, big.NewInt(0)) {
 		return errors.New("Order 'nonce' parameter should be positive")
 	}
@@ -404,6 +449,24 @@ func (so *StopOrder) Validate() error {
 		return errors.New("Order 'stopPrice' parameter should be strictly positive")
 	}
 
+	if so.Type != TypeStopMarketOrder && so.Type != TypeStopLimitOrder {
+		return errors.New("Order 'type' should be 'SMO' or 'SLO', but got: '" + so.Type + "'")
+	}
+
+	if so.Type == TypeStopLimitOrder {
+		if so.LimitPrice == nil {
+			return errors.New("Order 'limitPrice' parameter is required")
+		}
+
+		if math.IsEqualOrSmallerThan(so.LimitPrice, big.NewInt(0)) {
+			return errors.New("Order 'limitPrice' parameter should be strictly positive")
+		}
+	}
+
+	if so.Direction != StopOrderDirectionUp && so.Direction != StopOrderDirectionDown {
+		return errors.New("Order 'direction' should be '1' or '-1', but got: '" + strconv.Itoa(so.Direction) + "'")
+	}
+
 	valid, err := so.VerifySignature()
 	if err != nil {
 		return err
@@ -413,11 +476,27 @@ func (so *StopOrder) Validate() error {
 		return errors.New("Order 'signature' parameter is invalid")
 	}
 
+	valid, err = so.VerifyOrderSignature()
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return errors.New("Order 'orderSignature' parameter is invalid")
+	}
+
 	return nil
 }
 
-// ComputeHash calculates the orderRequest hash
+// ComputeHash calculates the stop order hash.
+// Unlike the order hash, it covers the stop price, the limit price, the direction
+// and the type, so none of the trigger conditions can be changed once signed
 func (so *StopOrder) ComputeHash() common.Hash {
+	limitPrice := so.LimitPrice
+	if limitPrice == nil {
+		limitPrice = big.NewInt(0)
+	}
+
 	sha := sha3.NewKeccak256()
 	sha.Write(so.ExchangeAddress.Bytes())
 	sha.Write(so.UserAddress.Bytes())
@@ -425,7 +504,10 @@ func (so *StopOrder) ComputeHash() common.Hash {
 	sha.Write(so.QuoteToken.Bytes())
 	sha.Write(common.BigToHash(so.Amount).Bytes())
 	sha.Write(common.BigToHash(so.StopPrice).Bytes())
+	sha.Write(common.BigToHash(limitPrice).Bytes())
+	sha.Write([]byte(strconv.Itoa(so.Direction)))
 	sha.Write(common.BigToHash(so.EncodedSide()).Bytes())
+	sha.Write([]byte(so.Type))
 	sha.Write(common.BigToHash(so.Nonce).Bytes())
 	return common.BytesToHash(sha.Sum(nil))
 }
@@ -451,11 +533,50 @@ func (so *StopOrder) VerifySignature() (bool, error) {
 	return true, nil
 }
 
+// VerifyOrderSignature checks that the order signature corresponds to the address in the userAddress field
+// for the order that is sent when the stop order is triggered
+func (so *StopOrder) VerifyOrderSignature() (bool, error) {
+	o, err := so.ToOrder()
+	if err != nil {
+		return false, err
+	}
+
+	return o.VerifySignature()
+}
+
+// Sign signs the stop order and the order it turns into with the wallet private key
+func (so *StopOrder) Sign(w *Wallet) error {
+	o, err := so.ToOrder()
+	if err != nil {
+		return err
+	}
+
+	orderSignature, err := w.SignHash(o.Hash)
+	if err != nil {
+		return err
+	}
+
+	hash := so.ComputeHash()
+	signature, err := w.SignHash(hash)
+	if err != nil {
+		return err
+	}
+
+	so.Hash = hash
+	so.Signature = signature
+	so.OrderSignature = orderSignature
+	return nil
+}
+
 func (so *StopOrder) Process(p *Pair) error {
 	if so.FilledAmount == nil {
 		so.FilledAmount = big.NewInt(0)
 	}
 
+	if so.LimitPrice == nil {
+		so.LimitPrice = big.NewInt(0)
+	}
+
 	// TODO: Handle this in Validate function
 	if so.Type != TypeStopMarketOrder && so.Type != TypeStopLimitOrder {
 		so.Type = TypeStopLimitOrder
@@ -467,6 +588,23 @@ func (so *StopOrder) Process(p *Pair) error {
 	return nil
 }
 
+// IsTriggered returns true if the last traded price has crossed the stop price
+// in the direction of the stop order
+func (so *StopOrder) IsTriggered(lastPrice *big.Int) bool {
+	if lastPrice == nil || so.StopPrice == nil {
+		return false
+	}
+
+	switch so.Direction {
+	case StopOrderDirectionUp:
+		return lastPrice.Cmp(so.StopPrice) >= 0
+	case StopOrderDirectionDown:
+		return lastPrice.Cmp(so.StopPrice) <= 0
+	}
+
+	return false
+}
+
 func (so *StopOrder) QuoteAmount(p *Pair) *big.Int {
 	pairMultiplier := p.PairMultiplier()
 	return math.Div(math.Mul(so.Amount, so.StopPrice), pairMultiplier)
@@ -491,22 +629,24 @@ func (so *StopOrder) PairCode() (string, error) {
 
 // StopOrderRecord is the object that will be saved in the database
 type StopOrderRecord struct {
-	ID              bson.ObjectId    `json:"id" bson:"_id"`
-	UserAddress     string           `json:"userAddress" bson:"userAddress"`
-	ExchangeAddress string           `json:"exchangeAddress" bson:"exchangeAddress"`
-	BaseToken       string           `json:"baseToken" bson:"baseToken"`
-	QuoteToken      string           `json:"quoteToken" bson:"quoteToken"`
-	Status          string           `json:"status" bson:"status"`
-	Side            string           `json:"side" bson:"side"`
-	Type            string           `json:"type" bson:"type"`
-	Hash            string           `json:"hash" bson:"hash"`
-	StopPrice       string           `json:"stopPrice" bson:"stopPrice"`
-	LimitPrice      string           `json:"limitPrice" bson:"limitPrice"`
-	Direction       int              `json:"direction" bson:"direction"`
-	Amount          string           `json:"amount" bson:"amount"`
-	FilledAmount    string           `json:"filledAmount" bson:"filledAmount"`
-	Nonce           string           `json:"nonce" bson:"nonce"`
-	Signature       *SignatureRecord `json:"signature,omitempty" bson:"signature"`
+	ID              primitive.ObjectID `json:"id" bson:"_id"`
+	UserAddress     string             `json:"userAddress" bson:"userAddress"`
+	ExchangeAddress string             `json:"exchangeAddress" bson:"exchangeAddress"`
+	BaseToken       string             `json:"baseToken" bson:"baseToken"`
+	QuoteToken      string             `json:"quoteToken" bson:"quoteToken"`
+	Status          string             `json:"status" bson:"status"`
+	Side            string             `json:"side" bson:"side"`
+	Type            string             `json:"type" bson:"type"`
+	Hash            string             `json:"hash" bson:"hash"`
+	StopPrice       string             `json:"stopPrice" bson:"stopPrice"`
+	LimitPrice      string             `json:"limitPrice" bson:"limitPrice"`
+	Direction       int                `json:"direction" bson:"direction"`
+	Amount          string             `json:"amount" bson:"amount"`
+	FilledAmount    string             `json:"filledAmount" bson:"filledAmount"`
+	Nonce           string             `json:"nonce" bson:"nonce"`
+	Signature       *SignatureRecord   `json:"signature,omitempty" bson:"signature"`
+	OrderSignature  *SignatureRecord   `json:"orderSignature,omitempty" bson:"orderSignature"`
+	StopPriceKey    string             `json:"-" bson:"stopPriceKey"`
 
 	PairName  string    `json:"pairName" bson:"pairName"`
 	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
@@ -517,7 +657,7 @@ type StopOrderBSONUpdate struct {
 	*StopOrder
 }
 
-func (o StopOrderBSONUpdate) GetBSON() (interface{}, error) {
+func (o StopOrderBSONUpdate) MarshalBSON() ([]byte, error) {
 	now := time.Now()
 
 	set := bson.M{
@@ -530,6 +670,7 @@ func (o StopOrderBSONUpdate) GetBSON() (interface{}, error) {
 		"side":            o.Side,
 		"type":            o.Type,
 		"stopPrice":       o.StopPrice.String(),
+		"stopPriceKey":    StopPriceKey(o.StopPrice),
 		"limitPrice":      o.LimitPrice.String(),
 		"direction":       o.Direction,
 		"amount":          o.Amount.String(),
@@ -549,8 +690,16 @@ func (o StopOrderBSONUpdate) GetBSON() (interface{}, error) {
 		}
 	}
 
+	if o.OrderSignature != nil {
+		set["orderSignature"] = bson.M{
+			"V": o.OrderSignature.V,
+			"R": o.OrderSignature.R.Hex(),
+			"S": o.OrderSignature.S.Hex(),
+		}
+	}
+
 	setOnInsert := bson.M{
-		"_id":       bson.NewObjectId(),
+		"_id":       primitive.NewObjectID(),
 		"hash":      o.Hash.Hex(),
 		"createdAt": now,
 	}
@@ -560,5 +709,20 @@ func (o StopOrderBSONUpdate) GetBSON() (interface{}, error) {
 		"$setOnInsert": setOnInsert,
 	}
 
-	return update, nil
+	return bson.Marshal(update)
+}
+
+// StopPriceKey encodes a price as a fixed width decimal string, so that prices can be
+// compared by MongoDB as strings in the same order as the numbers they represent
+func StopPriceKey(price *big.Int) string {
+	if price == nil {
+		return ""
+	}
+
+	key := price.String()
+	if len(key) >= stopPriceKeyLength {
+		return key
+	}
+
+	return strings.Repeat("0", stopPriceKeyLength-len(key)) + key
 }
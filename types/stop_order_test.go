@@ -1,18 +1,44 @@
 package types
 
 import (
+	"encoding/json"
 	"math/big"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/app"
 )
 
+func newSignedStopOrder(t *testing.T, w *Wallet) *StopOrder {
+	exchangeAddress := "0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"
+	app.Config.Tomochain = map[string]string{"exchange_address": exchangeAddress}
+
+	so := &StopOrder{
+		UserAddress:     w.Address,
+		ExchangeAddress: common.HexToAddress(exchangeAddress),
+		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
+		QuoteToken:      common.HexToAddress("0x12459c951127e0c374ff9105dda097662a027093"),
+		StopPrice:       big.NewInt(1000),
+		LimitPrice:      big.NewInt(1100),
+		Direction:       StopOrderDirectionUp,
+		Amount:          big.NewInt(1000),
+		Side:            BUY,
+		Type:            TypeStopLimitOrder,
+		Nonce:           big.NewInt(1),
+	}
+
+	if err := so.Sign(w); err != nil {
+		t.Fatal(err)
+	}
+
+	return so
+}
+
 func TestToOrder(t *testing.T) {
 	so := &StopOrder{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:              objectID("537f700b537461b70c5f0000"),
 		UserAddress:     common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
@@ -23,6 +49,7 @@ func TestToOrder(t *testing.T) {
 		FilledAmount:    big.NewInt(100),
 		Status:          "OPEN",
 		Side:            "BUY",
+		Type:            TypeStopLimitOrder,
 		PairName:        "ETH/TOMO",
 		Nonce:           big.NewInt(1000),
 		Signature: &Signature{
@@ -39,3 +66,82 @@ func TestToOrder(t *testing.T) {
 
 	assert.Equal(t, o.PricePoint, so.LimitPrice)
 }
+
+func TestStopOrderIsTriggered(t *testing.T) {
+	so := &StopOrder{
+		StopPrice: big.NewInt(1000),
+		Direction: StopOrderDirectionUp,
+	}
+
+	assert.False(t, so.IsTriggered(big.NewInt(999)))
+	assert.True(t, so.IsTriggered(big.NewInt(1000)))
+	assert.True(t, so.IsTriggered(big.NewInt(1001)))
+
+	so.Direction = StopOrderDirectionDown
+
+	assert.True(t, so.IsTriggered(big.NewInt(999)))
+	assert.True(t, so.IsTriggered(big.NewInt(1000)))
+	assert.False(t, so.IsTriggered(big.NewInt(1001)))
+
+	so.Direction = 0
+
+	assert.False(t, so.IsTriggered(big.NewInt(1000)))
+	assert.False(t, so.IsTriggered(nil))
+}
+
+func TestStopOrderValidate(t *testing.T) {
+	w := NewWallet()
+
+	so := newSignedStopOrder(t, w)
+	assert.Nil(t, so.Validate())
+
+	so = newSignedStopOrder(t, w)
+	so.LimitPrice = nil
+	assert.EqualError(t, so.Validate(), "Order 'limitPrice' parameter is required")
+
+	so = newSignedStopOrder(t, w)
+	so.LimitPrice = big.NewInt(0)
+	assert.EqualError(t, so.Validate(), "Order 'limitPrice' parameter should be strictly positive")
+
+	so = newSignedStopOrder(t, w)
+	so.Direction = 0
+	assert.EqualError(t, so.Validate(), "Order 'direction' should be '1' or '-1', but got: '0'")
+
+	so = newSignedStopOrder(t, w)
+	so.OrderSignature = nil
+	assert.EqualError(t, so.Validate(), "Order 'orderSignature' parameter is required")
+}
+
+func TestStopOrderSignatureCoversTrigger(t *testing.T) {
+	w := NewWallet()
+
+	so := newSignedStopOrder(t, w)
+	so.StopPrice = big.NewInt(500)
+	assert.NotNil(t, so.Validate())
+
+	so = newSignedStopOrder(t, w)
+	so.Direction = StopOrderDirectionDown
+	assert.NotNil(t, so.Validate())
+
+	so = newSignedStopOrder(t, w)
+	so.Type = TypeStopMarketOrder
+	assert.NotNil(t, so.Validate())
+
+	so = newSignedStopOrder(t, w)
+	o, err := so.ToOrder()
+	assert.Nil(t, err)
+	assert.NotEqual(t, so.Hash, o.Hash)
+	assert.Nil(t, o.Validate())
+}
+
+func TestStopOrderMarshalHidesOrderSignature(t *testing.T) {
+	so := newSignedStopOrder(t, NewWallet())
+
+	b, err := json.Marshal(so)
+	assert.Nil(t, err)
+
+	decoded := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Nil(t, decoded["orderSignature"])
+	assert.NotNil(t, decoded["signature"])
+}
@@ -6,27 +6,28 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/go-ozzo/ozzo-validation"
 	"github.com/tomochain/tomox-sdk/utils/math"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Token struct is used to model the token data in the system and DB
 type Token struct {
-	ID              bson.ObjectId  `json:"-" bson:"_id"`
-	Name            string         `json:"name" bson:"name"`
-	Symbol          string         `json:"symbol" bson:"symbol"`
-	Address         common.Address `json:"address" bson:"address"`
-	Image           Image          `json:"image" bson:"image"`
-	ContractAddress common.Address `json:"contractAddress" bson:"contractAddress"`
-	RelayerAddress  common.Address `json:"relayerAddress" bson:"relayerAddress"`
-	Decimals        int            `json:"decimals" bson:"decimals"`
-	Active          bool           `json:"active" bson:"active"`
-	Listed          bool           `json:"listed" bson:"listed"`
-	Quote           bool           `json:"quote" bson:"quote"`
-	MakeFee         *big.Int       `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
-	TakeFee         *big.Int       `json:"takeFee,omitempty" bson:"makeFee,omitempty"`
-	USD             string         `json:"usd,omitempty" bson:"usd,omitempty"`
+	ID              primitive.ObjectID `json:"-" bson:"_id"`
+	Name            string             `json:"name" bson:"name"`
+	Symbol          string             `json:"symbol" bson:"symbol"`
+	Address         common.Address     `json:"address" bson:"address"`
+	Image           Image              `json:"image" bson:"image"`
+	ContractAddress common.Address     `json:"contractAddress" bson:"contractAddress"`
+	RelayerAddress  common.Address     `json:"relayerAddress" bson:"relayerAddress"`
+	Decimals        int                `json:"decimals" bson:"decimals"`
+	Active          bool               `json:"active" bson:"active"`
+	Listed          bool               `json:"listed" bson:"listed"`
+	Quote           bool               `json:"quote" bson:"quote"`
+	MakeFee         *big.Int           `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
+	TakeFee         *big.Int           `json:"takeFee,omitempty" bson:"makeFee,omitempty"`
+	USD             string             `json:"usd,omitempty" bson:"usd,omitempty"`
 
 	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
@@ -34,18 +35,18 @@ type Token struct {
 
 // TokenRecord is the struct which is stored in db
 type TokenRecord struct {
-	ID              bson.ObjectId `json:"-" bson:"_id"`
-	Name            string        `json:"name" bson:"name"`
-	Symbol          string        `json:"symbol" bson:"symbol"`
-	Image           Image         `json:"image" bson:"image"`
-	ContractAddress string        `json:"contractAddress" bson:"contractAddress"`
-	RelayerAddress  string        `json:"relayerAddress" bson:"relayerAddress"`
-	Decimals        int           `json:"decimals" bson:"decimals"`
-	Active          bool          `json:"active" bson:"active"`
-	Quote           bool          `json:"quote" bson:"quote"`
-	MakeFee         string        `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
-	TakeFee         string        `json:"takeFee,omitempty" bson:"takeFee,omitempty"`
-	USD             string        `json:"usd,omitempty" bson:"usd,omitempty"`
+	ID              primitive.ObjectID `json:"-" bson:"_id"`
+	Name            string             `json:"name" bson:"name"`
+	Symbol          string             `json:"symbol" bson:"symbol"`
+	Image           Image              `json:"image" bson:"image"`
+	ContractAddress string             `json:"contractAddress" bson:"contractAddress"`
+	RelayerAddress  string             `json:"relayerAddress" bson:"relayerAddress"`
+	Decimals        int                `json:"decimals" bson:"decimals"`
+	Active          bool               `json:"active" bson:"active"`
+	Quote           bool               `json:"quote" bson:"quote"`
+	MakeFee         string             `json:"makeFee,omitempty" bson:"makeFee,omitempty"`
+	TakeFee         string             `json:"takeFee,omitempty" bson:"takeFee,omitempty"`
+	USD             string             `json:"usd,omitempty" bson:"usd,omitempty"`
 
 	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
@@ -101,6 +102,7 @@ func (t Token) Validate() error {
 func (t *Token) MarshalJSON() ([]byte, error) {
 	token := map[string]interface{}{
 		"id":              t.ID,
+		"name":            t.Name,
 		"symbol":          t.Symbol,
 		"contractAddress": t.ContractAddress.Hex(),
 		"relayerAddress":  t.RelayerAddress.Hex(),
@@ -132,12 +134,27 @@ func (t *Token) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	t.ID = bson.ObjectIdHex(token["id"].(string))
-	t.Symbol = token["symbol"].(string)
-	t.Decimals = token["decimals"].(int)
-	t.Active = token["active"].(bool)
-	t.Quote = token["quote"].(bool)
-	t.USD = token["usd"].(string)
+	if id, ok := token["id"].(string); ok {
+		t.ID, _ = primitive.ObjectIDFromHex(id)
+	}
+
+	if contractAddress, ok := token["contractAddress"].(string); ok {
+		t.ContractAddress = common.HexToAddress(contractAddress)
+	}
+
+	if relayerAddress, ok := token["relayerAddress"].(string); ok {
+		t.RelayerAddress = common.HexToAddress(relayerAddress)
+	}
+
+	if decimals, ok := token["decimals"].(float64); ok {
+		t.Decimals = int(decimals)
+	}
+
+	t.Name, _ = token["name"].(string)
+	t.Symbol, _ = token["symbol"].(string)
+	t.Active, _ = token["active"].(bool)
+	t.Quote, _ = token["quote"].(bool)
+	t.USD, _ = token["usd"].(string)
 
 	if token["createdAt"] != nil {
 		tm, _ := time.Parse(time.RFC3339Nano, token["createdAt"].(string))
@@ -159,15 +176,15 @@ func (t *Token) UnmarshalJSON(b []byte) error {
 
 	image, ok := token["image"].(map[string]interface{})
 	if ok {
-		t.Image.URL = image["url"].(string)
-		t.Image.Meta = image["meta"].(map[string]interface{})
+		t.Image.URL, _ = image["url"].(string)
+		t.Image.Meta, _ = image["meta"].(map[string]interface{})
 	}
 
 	return nil
 }
 
-// GetBSON implements bson.Getter
-func (t *Token) GetBSON() (interface{}, error) {
+// MarshalBSON implements bson.Marshaler
+func (t *Token) MarshalBSON() ([]byte, error) {
 
 	tr := TokenRecord{
 		ID:              t.ID,
@@ -192,14 +209,14 @@ func (t *Token) GetBSON() (interface{}, error) {
 		tr.TakeFee = t.TakeFee.String()
 	}
 
-	return tr, nil
+	return bson.Marshal(tr)
 }
 
-// SetBSON implemenets bson.Setter
-func (t *Token) SetBSON(raw bson.Raw) error {
+// UnmarshalBSON implemenets bson.Unmarshaler
+func (t *Token) UnmarshalBSON(data []byte) error {
 	decoded := &TokenRecord{}
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		return err
 	}
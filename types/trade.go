@@ -11,8 +11,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto/sha3"
 	"github.com/tomochain/tomox-sdk/utils/math"
-
-	"github.com/globalsign/mgo/bson"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -25,26 +25,26 @@ const (
 // To be valid an accept by the matching engine (and ultimately the exchange smart-contract),
 // the trade signature must be made from the trader Maker account
 type Trade struct {
-	ID             bson.ObjectId  `json:"id,omitempty" bson:"_id"`
-	Taker          common.Address `json:"taker" bson:"taker"`
-	Maker          common.Address `json:"maker" bson:"maker"`
-	BaseToken      common.Address `json:"baseToken" bson:"baseToken"`
-	QuoteToken     common.Address `json:"quoteToken" bson:"quoteToken"`
-	MakerOrderHash common.Hash    `json:"makerOrderHash" bson:"makerOrderHash"`
-	TakerOrderHash common.Hash    `json:"takerOrderHash" bson:"takerOrderHash"`
-	Hash           common.Hash    `json:"hash" bson:"hash"`
-	TxHash         common.Hash    `json:"txHash" bson:"txHash"`
-	PairName       string         `json:"pairName" bson:"pairName"`
-	PricePoint     *big.Int       `json:"pricepoint" bson:"pricepoint"`
-	Amount         *big.Int       `json:"amount" bson:"amount"`
-	MakeFee        *big.Int       `json:"makeFee" bson:"makeFee"`
-	TakeFee        *big.Int       `json:"takeFee" bson:"takeFee"`
-	Status         string         `json:"status" bson:"status"`
-	CreatedAt      time.Time      `json:"createdAt" bson:"createdAt"`
-	UpdatedAt      time.Time      `json:"updatedAt" bson:"updatedAt"`
-	TakerOrderSide string         `json:"takerOrderSide" bson:"takerOrderSide"`
-	TakerOrderType string         `json:"takerOrderType" bson:"takerOrderType"`
-	MakerOrderType string         `json:"makerOrderType" bson:"makerOrderType"`
+	ID             primitive.ObjectID `json:"id,omitempty" bson:"_id"`
+	Taker          common.Address     `json:"taker" bson:"taker"`
+	Maker          common.Address     `json:"maker" bson:"maker"`
+	BaseToken      common.Address     `json:"baseToken" bson:"baseToken"`
+	QuoteToken     common.Address     `json:"quoteToken" bson:"quoteToken"`
+	MakerOrderHash common.Hash        `json:"makerOrderHash" bson:"makerOrderHash"`
+	TakerOrderHash common.Hash        `json:"takerOrderHash" bson:"takerOrderHash"`
+	Hash           common.Hash        `json:"hash" bson:"hash"`
+	TxHash         common.Hash        `json:"txHash" bson:"txHash"`
+	PairName       string             `json:"pairName" bson:"pairName"`
+	PricePoint     *big.Int           `json:"pricepoint" bson:"pricepoint"`
+	Amount         *big.Int           `json:"amount" bson:"amount"`
+	MakeFee        *big.Int           `json:"makeFee" bson:"makeFee"`
+	TakeFee        *big.Int           `json:"takeFee" bson:"takeFee"`
+	Status         string             `json:"status" bson:"status"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`
+	TakerOrderSide string             `json:"takerOrderSide" bson:"takerOrderSide"`
+	TakerOrderType string             `json:"takerOrderType" bson:"takerOrderType"`
+	MakerOrderType string             `json:"makerOrderType" bson:"makerOrderType"`
 }
 
 // TradeSpec for query
@@ -61,26 +61,26 @@ type TradeRes struct {
 	Trades []*Trade `json:"trades" bson:"orders"`
 }
 type TradeRecord struct {
-	ID             bson.ObjectId `json:"id" bson:"_id"`
-	Taker          string        `json:"taker" bson:"taker"`
-	Maker          string        `json:"maker" bson:"maker"`
-	BaseToken      string        `json:"baseToken" bson:"baseToken"`
-	QuoteToken     string        `json:"quoteToken" bson:"quoteToken"`
-	MakerOrderHash string        `json:"makerOrderHash" bson:"makerOrderHash"`
-	TakerOrderHash string        `json:"takerOrderHash" bson:"takerOrderHash"`
-	Hash           string        `json:"hash" bson:"hash"`
-	TxHash         string        `json:"txHash" bson:"txHash"`
-	PairName       string        `json:"pairName" bson:"pairName"`
-	Amount         string        `json:"amount" bson:"amount"`
-	MakeFee        string        `json:"makeFee" bson:"makeFee"`
-	TakeFee        string        `json:"takeFee" bson:"takeFee"`
-	PricePoint     string        `json:"pricepoint" bson:"pricepoint"`
-	Status         string        `json:"status" bson:"status"`
-	CreatedAt      time.Time     `json:"createdAt" bson:"createdAt"`
-	UpdatedAt      time.Time     `json:"updatedAt" bson:"updatedAt"`
-	TakerOrderSide string        `json:"takerOrderSide" bson:"takerOrderSide"`
-	TakerOrderType string        `json:"takerOrderType" bson:"takerOrderType"`
-	MakerOrderType string        `json:"makerOrderType" bson:"makerOrderType"`
+	ID             primitive.ObjectID `json:"id" bson:"_id"`
+	Taker          string             `json:"taker" bson:"taker"`
+	Maker          string             `json:"maker" bson:"maker"`
+	BaseToken      string             `json:"baseToken" bson:"baseToken"`
+	QuoteToken     string             `json:"quoteToken" bson:"quoteToken"`
+	MakerOrderHash string             `json:"makerOrderHash" bson:"makerOrderHash"`
+	TakerOrderHash string             `json:"takerOrderHash" bson:"takerOrderHash"`
+	Hash           string             `json:"hash" bson:"hash"`
+	TxHash         string             `json:"txHash" bson:"txHash"`
+	PairName       string             `json:"pairName" bson:"pairName"`
+	Amount         string             `json:"amount" bson:"amount"`
+	MakeFee        string             `json:"makeFee" bson:"makeFee"`
+	TakeFee        string             `json:"takeFee" bson:"takeFee"`
+	PricePoint     string             `json:"pricepoint" bson:"pricepoint"`
+	Status         string             `json:"status" bson:"status"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`
+	TakerOrderSide string             `json:"takerOrderSide" bson:"takerOrderSide"`
+	TakerOrderType string             `json:"takerOrderType" bson:"takerOrderType"`
+	MakerOrderType string             `json:"makerOrderType" bson:"makerOrderType"`
 }
 
 // NewTrade returns a new unsigned trade corresponding to an Order, amount and taker address
@@ -241,8 +241,8 @@ func (t *Trade) UnmarshalJSON(b []byte) error {
 		t.Maker = common.HexToAddress(trade["maker"].(string))
 	}
 
-	if trade["id"] != nil && bson.IsObjectIdHex(trade["id"].(string)) {
-		t.ID = bson.ObjectIdHex(trade["id"].(string))
+	if trade["id"] != nil && primitive.IsValidObjectID(trade["id"].(string)) {
+		t.ID, _ = primitive.ObjectIDFromHex(trade["id"].(string))
 	}
 
 	if trade["txHash"] != nil {
@@ -296,7 +296,7 @@ func (t *Trade) QuoteAmount(p *Pair) *big.Int {
 	return math.Div(math.Mul(t.Amount, t.PricePoint), pairMultiplier)
 }
 
-func (t *Trade) GetBSON() (interface{}, error) {
+func (t *Trade) MarshalBSON() ([]byte, error) {
 	tr := TradeRecord{
 		ID:             t.ID,
 		PairName:       t.PairName,
@@ -320,34 +320,34 @@ func (t *Trade) GetBSON() (interface{}, error) {
 		MakerOrderType: t.MakerOrderType,
 	}
 
-	return tr, nil
+	return bson.Marshal(tr)
 }
 
-func (t *Trade) SetBSON(raw bson.Raw) error {
+func (t *Trade) UnmarshalBSON(data []byte) error {
 	decoded := new(struct {
-		ID             bson.ObjectId `json:"id,omitempty" bson:"_id"`
-		PairName       string        `json:"pairName" bson:"pairName"`
-		Taker          string        `json:"taker" bson:"taker"`
-		Maker          string        `json:"maker" bson:"maker"`
-		BaseToken      string        `json:"baseToken" bson:"baseToken"`
-		QuoteToken     string        `json:"quoteToken" bson:"quoteToken"`
-		MakerOrderHash string        `json:"makerOrderHash" bson:"makerOrderHash"`
-		TakerOrderHash string        `json:"takerOrderHash" bson:"takerOrderHash"`
-		Hash           string        `json:"hash" bson:"hash"`
-		TxHash         string        `json:"txHash" bson:"txHash"`
-		CreatedAt      time.Time     `json:"createdAt" bson:"createdAt"`
-		UpdatedAt      time.Time     `json:"updatedAt" bson:"updatedAt"`
-		PricePoint     string        `json:"pricepoint" bson:"pricepoint"`
-		Status         string        `json:"status" bson:"status"`
-		Amount         string        `json:"amount" bson:"amount"`
-		MakeFee        string        `json:"makeFee" bson:"makeFee"`
-		TakeFee        string        `json:"takeFee" bson:"takeFee"`
-		TakerOrderSide string        `json:"takerOrderSide" bson:"takerOrderSide"`
-		TakerOrderType string        `json:"takerOrderType" bson:"takerOrderType"`
-		MakerOrderType string        `json:"makerOrderType" bson:"makerOrderType"`
+		ID             primitive.ObjectID `json:"id,omitempty" bson:"_id"`
+		PairName       string             `json:"pairName" bson:"pairName"`
+		Taker          string             `json:"taker" bson:"taker"`
+		Maker          string             `json:"maker" bson:"maker"`
+		BaseToken      string             `json:"baseToken" bson:"baseToken"`
+		QuoteToken     string             `json:"quoteToken" bson:"quoteToken"`
+		MakerOrderHash string             `json:"makerOrderHash" bson:"makerOrderHash"`
+		TakerOrderHash string             `json:"takerOrderHash" bson:"takerOrderHash"`
+		Hash           string             `json:"hash" bson:"hash"`
+		TxHash         string             `json:"txHash" bson:"txHash"`
+		CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+		UpdatedAt      time.Time          `json:"updatedAt" bson:"updatedAt"`
+		PricePoint     string             `json:"pricepoint" bson:"pricepoint"`
+		Status         string             `json:"status" bson:"status"`
+		Amount         string             `json:"amount" bson:"amount"`
+		MakeFee        string             `json:"makeFee" bson:"makeFee"`
+		TakeFee        string             `json:"takeFee" bson:"takeFee"`
+		TakerOrderSide string             `json:"takerOrderSide" bson:"takerOrderSide"`
+		TakerOrderType string             `json:"takerOrderType" bson:"takerOrderType"`
+		MakerOrderType string             `json:"makerOrderType" bson:"makerOrderType"`
 	})
 
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		return err
 	}
@@ -406,7 +406,7 @@ type TradeBSONUpdate struct {
 	*Trade
 }
 
-func (t TradeBSONUpdate) GetBSON() (interface{}, error) {
+func (t TradeBSONUpdate) MarshalBSON() ([]byte, error) {
 	now := time.Now()
 
 	set := bson.M{
@@ -430,7 +430,7 @@ func (t TradeBSONUpdate) GetBSON() (interface{}, error) {
 	}
 
 	setOnInsert := bson.M{
-		"_id":       bson.NewObjectId(),
+		"_id":       primitive.NewObjectID(),
 		"hash":      t.Hash.Hex(),
 		"createdAt": now,
 	}
@@ -440,7 +440,7 @@ func (t TradeBSONUpdate) GetBSON() (interface{}, error) {
 		"$setOnInsert": setOnInsert,
 	}
 
-	return update, nil
+	return bson.Marshal(update)
 }
 
 type TradeChangeEvent struct {
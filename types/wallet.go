@@ -6,14 +6,28 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/globalsign/mgo/bson"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/signer"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Wallet holds both the address and the private key of an ethereum account
+// ErrWalletNotEncrypted is returned when a wallet holding a private key is stored before its
+// key is encrypted
+var ErrWalletNotEncrypted = errors.New("The private key of the wallet should be encrypted before it is stored")
+
+// ErrNoWalletSigner is returned when a wallet holds neither a private key nor a signer
+var ErrNoWalletSigner = errors.New("The wallet can not sign")
+
+// Wallet holds both the address and the private key of an ethereum account. Only the
+// Keystore, the private key encrypted with the wallet passphrase, is stored in the database.
+// Signer signs for the wallets whose key is not held by the server
 type Wallet struct {
-	ID         bson.ObjectId
+	ID         primitive.ObjectID
 	Address    common.Address
 	PrivateKey *ecdsa.PrivateKey
+	Keystore   string
+	Signer     signer.Signer
 	Admin      bool
 	Operator   bool
 }
@@ -53,30 +67,86 @@ func (w *Wallet) GetPrivateKey() string {
 	return hex.EncodeToString(w.PrivateKey.D.Bytes())
 }
 
+// GetSigner returns the signer of the wallet, which signs with its private key unless the
+// wallet has a Signer. It returns nil when the wallet can not sign
+func (w *Wallet) GetSigner() signer.Signer {
+	if w.Signer != nil {
+		return w.Signer
+	}
+
+	if w.PrivateKey != nil {
+		return signer.NewMemorySigner(w.PrivateKey)
+	}
+
+	return nil
+}
+
+// Encrypt encrypts the private key of the wallet with a passphrase into its keystore
+func (w *Wallet) Encrypt(passphrase string) error {
+	if w.PrivateKey == nil {
+		return nil
+	}
+
+	keyJSON, err := signer.EncryptKey(w.PrivateKey, passphrase)
+	if err != nil {
+		return err
+	}
+
+	w.Keystore = string(keyJSON)
+	return nil
+}
+
+// Decrypt decrypts the keystore of the wallet with a passphrase into its private key
+func (w *Wallet) Decrypt(passphrase string) error {
+	if w.Keystore == "" {
+		return nil
+	}
+
+	key, err := signer.DecryptKey([]byte(w.Keystore), passphrase)
+	if err != nil {
+		return err
+	}
+
+	if crypto.PubkeyToAddress(key.PublicKey) != w.Address {
+		return errors.New("The keystore of the wallet holds the key of another address")
+	}
+
+	w.PrivateKey = key
+	return nil
+}
+
 func (w *Wallet) Validate() error {
 	return nil
 }
 
+// WalletRecord is the stored wallet. PrivateKey is only read, from the wallets stored in
+// plaintext before the keys were encrypted, so that they can be migrated
 type WalletRecord struct {
-	ID         bson.ObjectId `json:"id,omitempty" bson:"_id"`
-	Address    string        `json:"address" bson:"address"`
-	PrivateKey string        `json:"privateKey" bson:"privateKey"`
-	Admin      bool          `json:"admin" bson:"admin"`
-	Operator   bool          `json:"operator" bson:"operator"`
+	ID         primitive.ObjectID `json:"id,omitempty" bson:"_id"`
+	Address    string             `json:"address" bson:"address"`
+	PrivateKey string             `json:"-" bson:"privateKey,omitempty"`
+	Keystore   string             `json:"keystore,omitempty" bson:"keystore,omitempty"`
+	Admin      bool               `json:"admin" bson:"admin"`
+	Operator   bool               `json:"operator" bson:"operator"`
 }
 
-func (w *Wallet) GetBSON() (interface{}, error) {
-	return WalletRecord{
-		ID:         w.ID,
-		Address:    w.Address.Hex(),
-		PrivateKey: hex.EncodeToString(w.PrivateKey.D.Bytes()),
-		Admin:      w.Admin,
-	}, nil
+func (w *Wallet) MarshalBSON() ([]byte, error) {
+	if w.PrivateKey != nil && w.Keystore == "" {
+		return nil, ErrWalletNotEncrypted
+	}
+
+	return bson.Marshal(WalletRecord{
+		ID:       w.ID,
+		Address:  w.Address.Hex(),
+		Keystore: w.Keystore,
+		Admin:    w.Admin,
+		Operator: w.Operator,
+	})
 }
 
-func (w *Wallet) SetBSON(raw bson.Raw) error {
+func (w *Wallet) UnmarshalBSON(data []byte) error {
 	decoded := &WalletRecord{}
-	err := raw.Unmarshal(decoded)
+	err := bson.Unmarshal(data, decoded)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -84,10 +154,13 @@ func (w *Wallet) SetBSON(raw bson.Raw) error {
 
 	w.ID = decoded.ID
 	w.Address = common.HexToAddress(decoded.Address)
-	w.PrivateKey, err = crypto.HexToECDSA(decoded.PrivateKey)
-	if err != nil {
-		logger.Error(err)
-		return err
+	w.Keystore = decoded.Keystore
+	if decoded.PrivateKey != "" {
+		w.PrivateKey, err = crypto.HexToECDSA(decoded.PrivateKey)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
 	}
 
 	w.Admin = decoded.Admin
@@ -95,15 +168,20 @@ func (w *Wallet) SetBSON(raw bson.Raw) error {
 	return nil
 }
 
-// SignHash signs a hashed message with a wallet private key
+// SignHash signs a hashed message with the wallet signer
 // and returns it as a Signature object
 func (w *Wallet) SignHash(h common.Hash) (*Signature, error) {
+	s := w.GetSigner()
+	if s == nil {
+		return &Signature{}, ErrNoWalletSigner
+	}
+
 	message := crypto.Keccak256(
 		[]byte("\x19Ethereum Signed Message:\n32"),
 		h.Bytes(),
 	)
 
-	sigBytes, err := crypto.Sign(message, w.PrivateKey)
+	sigBytes, err := s.SignDigest(message)
 	if err != nil {
 		return &Signature{}, err
 	}
@@ -5,8 +5,9 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/globalsign/mgo/bson"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestNewWallet(t *testing.T) {
@@ -39,15 +40,36 @@ func TestNewWalletFromPrivateKey(t *testing.T) {
 func TestBSON(t *testing.T) {
 	key := "7c78c6e2f65d0d84c44ac0f7b53d6e4dd7a82c35f51b251d387c2a69df712660"
 	w := NewWalletFromPrivateKey(key)
-	w.ID = bson.NewObjectId()
+	w.ID = primitive.NewObjectID()
+
+	_, err := bson.Marshal(w)
+	assert.Equal(t, ErrWalletNotEncrypted, err, "Private key should not be encoded in plaintext")
+
+	err = w.Encrypt("passphrase")
+	if err != nil {
+		t.Error("some error:", err)
+	}
 
 	data, err := bson.Marshal(w)
 	if err != nil {
 		t.Error("some error:", err)
 	}
 
+	record := bson.M{}
+	bson.Unmarshal(data, &record)
+	assert.NotContains(t, record, "privateKey")
+
 	decoded := &Wallet{}
 	bson.Unmarshal(data, decoded)
+	assert.Nil(t, decoded.PrivateKey)
+
+	err = decoded.Decrypt("wrong")
+	assert.NotNil(t, err)
+
+	err = decoded.Decrypt("passphrase")
+	if err != nil {
+		t.Error("some error:", err)
+	}
 
 	assert.Equal(
 		t,
@@ -1,7 +1,7 @@
 package types
 
 import (
-	//"encoding/json"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -24,6 +24,10 @@ const (
 	SUCCESS_EVENT SubscriptionEvent = "SUCCESS"
 	INIT          SubscriptionEvent = "INIT"
 	CANCEL        SubscriptionEvent = "CANCEL"
+	BATCH_RESULT  SubscriptionEvent = "BATCH_RESULT"
+	RESYNC        SubscriptionEvent = "RESYNC"
+	CHALLENGE     SubscriptionEvent = "CHALLENGE"
+	LOGIN         SubscriptionEvent = "LOGIN"
 
 	// status
 
@@ -32,8 +36,15 @@ const (
 	ORDER_PARTIALLY_FILLED = "ORDER_PARTIALLY_FILLED"
 	ORDER_CANCELLED        = "ORDER_CANCELLED"
 	ORDER_REJECTED         = "ORDER_REJECTED"
+	ORDER_REPLACED         = "ORDER_REPLACED"
+	ORDER_EXPIRY_FAILED    = "ORDER_EXPIRY_FAILED"
 	ERROR_STATUS           = "ERROR"
 
+	STOP_ORDER_ADDED     = "STOP_ORDER_ADDED"
+	STOP_ORDER_CANCELLED = "STOP_ORDER_CANCELLED"
+	STOP_ORDER_TRIGGERED = "STOP_ORDER_TRIGGERED"
+	STOP_ORDER_REJECTED  = "STOP_ORDER_REJECTED"
+
 	TradeAdded   = "TRADE_ADDED"
 	TradeUpdated = "TRADE_UPDATED"
 	// channel
@@ -76,6 +87,16 @@ func (ev *WebsocketEvent) String() string {
 	return fmt.Sprintf("%v", ev.Type)
 }
 
+// BusMessage is a websocket event sent to the replicas of the server through the websocket bus.
+// ID is the channel ID of the subscriptions to the event, or the address of the connections for
+// the order, lending order and notification channels
+type BusMessage struct {
+	Channel string            `json:"channel"`
+	ID      string            `json:"id"`
+	Type    SubscriptionEvent `json:"type"`
+	Payload json.RawMessage   `json:"payload"`
+}
+
 // Params is a sub document used to pass parameters in Subscription messages
 type Params struct {
 	From     int64  `json:"from"`
@@ -119,6 +140,8 @@ type SubscriptionPayload struct {
 	Units        string         `json:"units"`
 	Term         uint64         `json:"term"`
 	LendingToken common.Address `json:"lendingToken,omitempty"`
+	Depth        int            `json:"depth,omitempty"`
+	Grouping     *int           `json:"grouping,omitempty"`
 }
 
 /*
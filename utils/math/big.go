@@ -34,6 +34,18 @@ func ToBigInt(s string) *big.Int {
 	return res
 }
 
+// DecimalToBigInt parses a decimal number, which can have a fraction or an exponent such as
+// the string of a Decimal128, and returns its integer part
+func DecimalToBigInt(s string) *big.Int {
+	f, _, err := big.ParseFloat(s, 10, 256, big.ToZero)
+	if err != nil {
+		return big.NewInt(0)
+	}
+
+	res, _ := f.Int(nil)
+	return res
+}
+
 func Exp(x, y *big.Int) *big.Int {
 	return big.NewInt(0).Exp(x, y, nil)
 }
@@ -0,0 +1,149 @@
+// Package ratelimit limits the rate of the requests of each IP and each address with token buckets
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Endpoint groups which have their own limits
+const (
+	// GroupOrder is the placement, cancellation and update of orders and lending orders
+	GroupOrder = "order"
+	// GroupQuery is the other REST requests
+	GroupQuery = "query"
+	// GroupWebsocket is the websocket connections and messages
+	GroupWebsocket = "websocket"
+)
+
+// sweepInterval is how often the buckets which are full again are removed from a MemoryStore
+const sweepInterval = time.Minute
+
+// Limit is a token bucket which is refilled with Rate tokens per second up to Burst tokens.
+// A zero Rate does not limit the requests
+type Limit struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// Limits are the limits of an endpoint group for each IP and for each authenticated address
+type Limits struct {
+	IP      Limit `mapstructure:"ip"`
+	Address Limit `mapstructure:"address"`
+}
+
+// Store keeps the token buckets by key. Take takes a token from the bucket of a key, and
+// returns false and how long to wait for the next token if it is empty
+type Store interface {
+	Take(key string, l Limit) (bool, time.Duration)
+}
+
+type bucket struct {
+	limit     Limit
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryStore keeps the token buckets in memory, so the limits are applied to each server
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	sweptAt time.Time
+	now     func() time.Time
+}
+
+// NewMemoryStore returns a new instance of MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		sweptAt: time.Now(),
+		now:     time.Now,
+	}
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(key string, l Limit) (bool, time.Duration) {
+	if l.Rate <= 0 {
+		return true, 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.sweptAt) > sweepInterval {
+		s.sweep(now)
+	}
+
+	b := s.buckets[key]
+	if b == nil {
+		b = &bucket{tokens: float64(burst(l)), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	b.limit = l
+	b.tokens = math.Min(float64(burst(l)), b.tokens+now.Sub(b.updatedAt).Seconds()*l.Rate)
+	b.updatedAt = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.Rate * float64(time.Second))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// sweep removes the buckets which are full again, as they are the same as new buckets
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.updatedAt).Seconds()*b.limit.Rate >= float64(burst(b.limit)) {
+			delete(s.buckets, key)
+		}
+	}
+
+	s.sweptAt = now
+}
+
+// burst returns the size of the bucket of a limit, which holds at least one token
+func burst(l Limit) int {
+	if l.Burst < 1 {
+		return 1
+	}
+
+	return l.Burst
+}
+
+// Limiter applies the limits of the endpoint groups to the IPs and the authenticated addresses.
+// The buckets are keyed by group, kind and IP or address in the store
+type Limiter struct {
+	store  Store
+	groups map[string]Limits
+}
+
+// NewLimiter returns a new instance of Limiter. The groups without limits are not limited
+func NewLimiter(store Store, groups map[string]Limits) *Limiter {
+	return &Limiter{store: store, groups: groups}
+}
+
+// AllowIP takes a token for a request of the IP in a group
+func (l *Limiter) AllowIP(group string, ip string) (bool, time.Duration) {
+	return l.store.Take(group+":ip:"+ip, l.groups[group].IP)
+}
+
+// AllowAddress takes a token for a request of the authenticated address in a group
+func (l *Limiter) AllowAddress(group string, addr common.Address) (bool, time.Duration) {
+	return l.store.Take(group+":address:"+addr.Hex(), l.groups[group].Address)
+}
+
+// RetryAfter returns the whole number of seconds to wait, as sent in a Retry-After header
+func RetryAfter(d time.Duration) int {
+	s := int(math.Ceil(d.Seconds()))
+	if s < 1 {
+		return 1
+	}
+
+	return s
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Unix(1500000000, 0)
+	s.now = func() time.Time { return now }
+
+	l := Limit{Rate: 2, Burst: 3}
+
+	// the burst is available at once
+	for i := 0; i < 3; i++ {
+		ok, _ := s.Take("a", l)
+		assert.True(t, ok)
+	}
+
+	ok, retry := s.Take("a", l)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, retry)
+
+	// the other keys have their own bucket
+	ok, _ = s.Take("b", l)
+	assert.True(t, ok)
+
+	// a token is added every 500ms
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = s.Take("a", l)
+	assert.True(t, ok)
+
+	ok, _ = s.Take("a", l)
+	assert.False(t, ok)
+
+	// a zero rate is not limited
+	for i := 0; i < 10; i++ {
+		ok, _ := s.Take("c", Limit{})
+		assert.True(t, ok)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s := NewMemoryStore()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	l := Limit{Rate: 1, Burst: 100}
+	s.Take("a", l)
+	s.Take("b", Limit{Rate: 0.001, Burst: 100})
+
+	now = now.Add(2 * sweepInterval)
+	s.Take("c", l)
+
+	assert.NotContains(t, s.buckets, "a")
+	assert.Contains(t, s.buckets, "b")
+	assert.Contains(t, s.buckets, "c")
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(NewMemoryStore(), map[string]Limits{
+		GroupOrder: {
+			IP:      Limit{Rate: 1, Burst: 1},
+			Address: Limit{Rate: 1, Burst: 2},
+		},
+	})
+
+	ok, _ := l.AllowIP(GroupOrder, "10.0.0.1")
+	assert.True(t, ok)
+
+	ok, retry := l.AllowIP(GroupOrder, "10.0.0.1")
+	assert.False(t, ok)
+	assert.Equal(t, 1, RetryAfter(retry))
+
+	addr := common.HexToAddress("0x1")
+	for i := 0; i < 2; i++ {
+		ok, _ = l.AllowAddress(GroupOrder, addr)
+		assert.True(t, ok)
+	}
+
+	ok, _ = l.AllowAddress(GroupOrder, addr)
+	assert.False(t, ok)
+
+	// the groups without limits are not limited
+	for i := 0; i < 10; i++ {
+		ok, _ = l.AllowIP(GroupQuery, "10.0.0.1")
+		assert.True(t, ok)
+	}
+}
@@ -21,7 +21,7 @@ import (
 
 var wg = &sync.WaitGroup{}
 var addr = flag.String("addr", "localhost:8080", "http service address")
-var logger = utils.TerminalLogger
+var logger = utils.Logger
 
 // Client simulates the client websocket handler that will be used to perform trading.
 // requests and responses are respectively the outbound and incoming messages.
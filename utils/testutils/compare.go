@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"testing"
 
-	"github.com/globalsign/mgo/dbtest"
 	"github.com/go-test/deep"
 	"github.com/stretchr/testify/assert"
 	"github.com/tomochain/tomox-sdk/types"
@@ -149,6 +148,6 @@ func CompareStructs(t *testing.T, expected interface{}, order interface{}) {
 	}
 }
 
-func NewDBTestServer() *dbtest.DBServer {
-	return &dbtest.DBServer{}
+func NewDBTestServer() *DBServer {
+	return &DBServer{}
 }
@@ -0,0 +1,102 @@
+package testutils
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// DBServer runs a mongod process for the tests. The server is started on a free port the
+// first time URI is called, and stores its files in the directory set with SetPath
+type DBServer struct {
+	output bytes.Buffer
+	server *exec.Cmd
+	dbpath string
+	host   string
+}
+
+// SetPath sets the directory of the database files, which must exist
+func (dbs *DBServer) SetPath(dbpath string) {
+	dbs.dbpath = dbpath
+}
+
+// URI returns the URI of the server, which is started if it is not running yet
+func (dbs *DBServer) URI() string {
+	if dbs.server == nil {
+		dbs.start()
+	}
+
+	return "mongodb://" + dbs.host
+}
+
+// Stop stops the server if it is running
+func (dbs *DBServer) Stop() {
+	if dbs.server == nil {
+		return
+	}
+
+	dbs.server.Process.Signal(os.Interrupt)
+
+	done := make(chan struct{})
+	go func() {
+		dbs.server.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		dbs.server.Process.Kill()
+	}
+
+	dbs.server = nil
+}
+
+func (dbs *DBServer) start() {
+	if dbs.dbpath == "" {
+		panic("DBServer.SetPath must be called before using the server")
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("unable to listen on a local address: " + err.Error())
+	}
+
+	addr := l.Addr().(*net.TCPAddr)
+	l.Close()
+	dbs.host = addr.String()
+
+	args := []string{
+		"--dbpath", dbs.dbpath,
+		"--bind_ip", "127.0.0.1",
+		"--port", strconv.Itoa(addr.Port),
+	}
+
+	dbs.server = exec.Command("mongod", args...)
+	dbs.server.Stdout = &dbs.output
+	dbs.server.Stderr = &dbs.output
+
+	err = dbs.server.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mongod failed to start: %v\n", err)
+		panic(err)
+	}
+
+	// wait for the server to accept the connections
+	for i := 0; i < 100; i++ {
+		conn, err := net.Dial("tcp", dbs.host)
+		if err == nil {
+			conn.Close()
+			return
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s", dbs.output.Bytes())
+	panic("mongod did not start")
+}
@@ -160,7 +160,7 @@ func TestNewWebSocketMessage(t *testing.T) {
 		t.Errorf("Error creating order factory client: %v", err)
 	}
 
-	msg, order, err := f.NewOrderMessage(ZRX, 1, WETH, 1)
+	msg, order, err := f.NewOrderMessage(ZRX, WETH, 1, 1)
 	if err != nil {
 		t.Errorf("Error creating order message: %v", err)
 	}
@@ -2,18 +2,32 @@
 
 package mocks
 
-import big "math/big"
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
 
 import mock "github.com/stretchr/testify/mock"
 import types "github.com/tomochain/tomox-sdk/types"
+import primitive "go.mongodb.org/mongo-driver/bson/primitive"
+import big "math/big"
 
 // AccountDao is an autogenerated mock type for the AccountDao type
 type AccountDao struct {
 	mock.Mock
 }
 
+// AddFavoriteToken provides a mock function with given fields: owner, token
+func (_m *AccountDao) AddFavoriteToken(owner common.Address, token common.Address) error {
+	ret := _m.Called(owner, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) error); ok {
+		r0 = rf(owner, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: account
 func (_m *AccountDao) Create(account *types.Account) error {
 	ret := _m.Called(account)
@@ -28,11 +42,48 @@ func (_m *AccountDao) Create(account *types.Account) error {
 	return r0
 }
 
+// DeleteFavoriteToken provides a mock function with given fields: owner, token
+func (_m *AccountDao) DeleteFavoriteToken(owner common.Address, token common.Address) error {
+	ret := _m.Called(owner, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) error); ok {
+		r0 = rf(owner, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Drop provides a mock function with given fields:
 func (_m *AccountDao) Drop() {
 	_m.Called()
 }
 
+// FindOrCreate provides a mock function with given fields: addr
+func (_m *AccountDao) FindOrCreate(addr common.Address) (*types.Account, error) {
+	ret := _m.Called(addr)
+
+	var r0 *types.Account
+	if rf, ok := ret.Get(0).(func(common.Address) *types.Account); ok {
+		r0 = rf(addr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(addr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields:
 func (_m *AccountDao) GetAll() ([]types.Account, error) {
 	ret := _m.Called()
@@ -80,11 +131,11 @@ func (_m *AccountDao) GetByAddress(owner common.Address) (*types.Account, error)
 }
 
 // GetByID provides a mock function with given fields: id
-func (_m *AccountDao) GetByID(id bson.ObjectId) (*types.Account, error) {
+func (_m *AccountDao) GetByID(id primitive.ObjectID) (*types.Account, error) {
 	ret := _m.Called(id)
 
 	var r0 *types.Account
-	if rf, ok := ret.Get(0).(func(bson.ObjectId) *types.Account); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID) *types.Account); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
@@ -93,7 +144,7 @@ func (_m *AccountDao) GetByID(id bson.ObjectId) (*types.Account, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bson.ObjectId) error); ok {
+	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
 		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
@@ -102,6 +153,29 @@ func (_m *AccountDao) GetByID(id bson.ObjectId) (*types.Account, error) {
 	return r0, r1
 }
 
+// GetFavoriteTokens provides a mock function with given fields: owner
+func (_m *AccountDao) GetFavoriteTokens(owner common.Address) (map[common.Address]bool, error) {
+	ret := _m.Called(owner)
+
+	var r0 map[common.Address]bool
+	if rf, ok := ret.Get(0).(func(common.Address) map[common.Address]bool); ok {
+		r0 = rf(owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[common.Address]bool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTokenBalance provides a mock function with given fields: owner, token
 func (_m *AccountDao) GetTokenBalance(owner common.Address, token common.Address) (*types.TokenBalance, error) {
 	ret := _m.Called(owner, token)
@@ -148,6 +222,20 @@ func (_m *AccountDao) GetTokenBalances(owner common.Address) (map[common.Address
 	return r0, r1
 }
 
+// Transfer provides a mock function with given fields: token, fromAddress, toAddress, amount
+func (_m *AccountDao) Transfer(token common.Address, fromAddress common.Address, toAddress common.Address, amount *big.Int) error {
+	ret := _m.Called(token, fromAddress, toAddress, amount)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address, *big.Int) error); ok {
+		r0 = rf(token, fromAddress, toAddress, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateBalance provides a mock function with given fields: owner, token, balance
 func (_m *AccountDao) UpdateBalance(owner common.Address, token common.Address, balance *big.Int) error {
 	ret := _m.Called(owner, token, balance)
@@ -2,17 +2,32 @@
 
 package mocks
 
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
-import mock "github.com/stretchr/testify/mock"
 
+import mock "github.com/stretchr/testify/mock"
 import types "github.com/tomochain/tomox-sdk/types"
+import primitive "go.mongodb.org/mongo-driver/bson/primitive"
+import big "math/big"
 
 // AccountService is an autogenerated mock type for the AccountService type
 type AccountService struct {
 	mock.Mock
 }
 
+// AddFavoriteToken provides a mock function with given fields: account, token
+func (_m *AccountService) AddFavoriteToken(account common.Address, token common.Address) error {
+	ret := _m.Called(account, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) error); ok {
+		r0 = rf(account, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: account
 func (_m *AccountService) Create(account *types.Account) error {
 	ret := _m.Called(account)
@@ -27,6 +42,43 @@ func (_m *AccountService) Create(account *types.Account) error {
 	return r0
 }
 
+// DeleteFavoriteToken provides a mock function with given fields: account, token
+func (_m *AccountService) DeleteFavoriteToken(account common.Address, token common.Address) error {
+	ret := _m.Called(account, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) error); ok {
+		r0 = rf(account, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindOrCreate provides a mock function with given fields: a
+func (_m *AccountService) FindOrCreate(a common.Address) (*types.Account, error) {
+	ret := _m.Called(a)
+
+	var r0 *types.Account
+	if rf, ok := ret.Get(0).(func(common.Address) *types.Account); ok {
+		r0 = rf(a)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(a)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields:
 func (_m *AccountService) GetAll() ([]types.Account, error) {
 	ret := _m.Called()
@@ -74,11 +126,11 @@ func (_m *AccountService) GetByAddress(a common.Address) (*types.Account, error)
 }
 
 // GetByID provides a mock function with given fields: id
-func (_m *AccountService) GetByID(id bson.ObjectId) (*types.Account, error) {
+func (_m *AccountService) GetByID(id primitive.ObjectID) (*types.Account, error) {
 	ret := _m.Called(id)
 
 	var r0 *types.Account
-	if rf, ok := ret.Get(0).(func(bson.ObjectId) *types.Account); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID) *types.Account); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
@@ -87,7 +139,7 @@ func (_m *AccountService) GetByID(id bson.ObjectId) (*types.Account, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bson.ObjectId) error); ok {
+	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
 		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
@@ -96,6 +148,29 @@ func (_m *AccountService) GetByID(id bson.ObjectId) (*types.Account, error) {
 	return r0, r1
 }
 
+// GetFavoriteTokens provides a mock function with given fields: account
+func (_m *AccountService) GetFavoriteTokens(account common.Address) (map[common.Address]bool, error) {
+	ret := _m.Called(account)
+
+	var r0 map[common.Address]bool
+	if rf, ok := ret.Get(0).(func(common.Address) map[common.Address]bool); ok {
+		r0 = rf(account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[common.Address]bool)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTokenBalance provides a mock function with given fields: owner, token
 func (_m *AccountService) GetTokenBalance(owner common.Address, token common.Address) (*types.TokenBalance, error) {
 	ret := _m.Called(owner, token)
@@ -119,6 +194,29 @@ func (_m *AccountService) GetTokenBalance(owner common.Address, token common.Add
 	return r0, r1
 }
 
+// GetTokenBalanceProvidor provides a mock function with given fields: owner, token
+func (_m *AccountService) GetTokenBalanceProvidor(owner common.Address, token common.Address) (*types.TokenBalance, error) {
+	ret := _m.Called(owner, token)
+
+	var r0 *types.TokenBalance
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *types.TokenBalance); ok {
+		r0 = rf(owner, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.TokenBalance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
+		r1 = rf(owner, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTokenBalances provides a mock function with given fields: owner
 func (_m *AccountService) GetTokenBalances(owner common.Address) (map[common.Address]*types.TokenBalance, error) {
 	ret := _m.Called(owner)
@@ -141,3 +239,17 @@ func (_m *AccountService) GetTokenBalances(owner common.Address) (map[common.Add
 
 	return r0, r1
 }
+
+// Transfer provides a mock function with given fields: token, fromAddress, toAddress, amount
+func (_m *AccountService) Transfer(token common.Address, fromAddress common.Address, toAddress common.Address, amount *big.Int) error {
+	ret := _m.Called(token, fromAddress, toAddress, amount)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address, *big.Int) error); ok {
+		r0 = rf(token, fromAddress, toAddress, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -2,18 +2,71 @@
 
 package mocks
 
-import big "math/big"
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
 
 import mock "github.com/stretchr/testify/mock"
+import interfaces "github.com/tomochain/tomox-sdk/interfaces"
 import types "github.com/tomochain/tomox-sdk/types"
+import bson "go.mongodb.org/mongo-driver/bson"
+import primitive "go.mongodb.org/mongo-driver/bson/primitive"
+import big "math/big"
 
 // OrderDao is an autogenerated mock type for the OrderDao type
 type OrderDao struct {
 	mock.Mock
 }
 
+// AddNewOrder provides a mock function with given fields: o, topic
+func (_m *OrderDao) AddNewOrder(o *types.Order, topic string) error {
+	ret := _m.Called(o, topic)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Order, string) error); ok {
+		r0 = rf(o, topic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Aggregate provides a mock function with given fields: q
+func (_m *OrderDao) Aggregate(q []bson.M) ([]*types.OrderData, error) {
+	ret := _m.Called(q)
+
+	var r0 []*types.OrderData
+	if rf, ok := ret.Get(0).(func([]bson.M) []*types.OrderData); ok {
+		r0 = rf(q)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.OrderData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]bson.M) error); ok {
+		r1 = rf(q)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CancelOrder provides a mock function with given fields: o, topic
+func (_m *OrderDao) CancelOrder(o *types.Order, topic string) error {
+	ret := _m.Called(o, topic)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Order, string) error); ok {
+		r0 = rf(o, topic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: o
 func (_m *OrderDao) Create(o *types.Order) error {
 	ret := _m.Called(o)
@@ -28,6 +81,46 @@ func (_m *OrderDao) Create(o *types.Order) error {
 	return r0
 }
 
+// Delete provides a mock function with given fields: orders
+func (_m *OrderDao) Delete(orders ...*types.Order) error {
+	_va := make([]interface{}, len(orders))
+	for _i := range orders {
+		_va[_i] = orders[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(...*types.Order) error); ok {
+		r0 = rf(orders...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByHashes provides a mock function with given fields: hashes
+func (_m *OrderDao) DeleteByHashes(hashes ...common.Hash) error {
+	_va := make([]interface{}, len(hashes))
+	for _i := range hashes {
+		_va[_i] = hashes[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(...common.Hash) error); ok {
+		r0 = rf(hashes...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Drop provides a mock function with given fields:
 func (_m *OrderDao) Drop() error {
 	ret := _m.Called()
@@ -42,13 +135,36 @@ func (_m *OrderDao) Drop() error {
 	return r0
 }
 
-// GetByHash provides a mock function with given fields: hash
-func (_m *OrderDao) GetByHash(hash common.Hash) (*types.Order, error) {
-	ret := _m.Called(hash)
+// FindAndModify provides a mock function with given fields: h, o
+func (_m *OrderDao) FindAndModify(h common.Hash, o *types.Order) (*types.Order, error) {
+	ret := _m.Called(h, o)
+
+	var r0 *types.Order
+	if rf, ok := ret.Get(0).(func(common.Hash, *types.Order) *types.Order); ok {
+		r0 = rf(h, o)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Hash, *types.Order) error); ok {
+		r1 = rf(h, o)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByHash provides a mock function with given fields: h
+func (_m *OrderDao) GetByHash(h common.Hash) (*types.Order, error) {
+	ret := _m.Called(h)
 
 	var r0 *types.Order
 	if rf, ok := ret.Get(0).(func(common.Hash) *types.Order); ok {
-		r0 = rf(hash)
+		r0 = rf(h)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*types.Order)
@@ -57,7 +173,7 @@ func (_m *OrderDao) GetByHash(hash common.Hash) (*types.Order, error) {
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
-		r1 = rf(hash)
+		r1 = rf(h)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,11 +205,11 @@ func (_m *OrderDao) GetByHashes(hashes []common.Hash) ([]*types.Order, error) {
 }
 
 // GetByID provides a mock function with given fields: id
-func (_m *OrderDao) GetByID(id bson.ObjectId) (*types.Order, error) {
+func (_m *OrderDao) GetByID(id primitive.ObjectID) (*types.Order, error) {
 	ret := _m.Called(id)
 
 	var r0 *types.Order
-	if rf, ok := ret.Get(0).(func(bson.ObjectId) *types.Order); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID) *types.Order); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
@@ -102,7 +218,7 @@ func (_m *OrderDao) GetByID(id bson.ObjectId) (*types.Order, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bson.ObjectId) error); ok {
+	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
 		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
@@ -111,13 +227,20 @@ func (_m *OrderDao) GetByID(id bson.ObjectId) (*types.Order, error) {
 	return r0, r1
 }
 
-// GetByUserAddress provides a mock function with given fields: addr
-func (_m *OrderDao) GetByUserAddress(addr common.Address) ([]*types.Order, error) {
-	ret := _m.Called(addr)
+// GetByUserAddress provides a mock function with given fields: addr, bt, qt, from, to, limit
+func (_m *OrderDao) GetByUserAddress(addr common.Address, bt common.Address, qt common.Address, from int64, to int64, limit ...int) ([]*types.Order, error) {
+	_va := make([]interface{}, len(limit))
+	for _i := range limit {
+		_va[_i] = limit[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, addr, bt, qt, from, to)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []*types.Order
-	if rf, ok := ret.Get(0).(func(common.Address) []*types.Order); ok {
-		r0 = rf(addr)
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address, int64, int64, ...int) []*types.Order); ok {
+		r0 = rf(addr, bt, qt, from, to, limit...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*types.Order)
@@ -125,8 +248,8 @@ func (_m *OrderDao) GetByUserAddress(addr common.Address) ([]*types.Order, error
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
-		r1 = rf(addr)
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, common.Address, int64, int64, ...int) error); ok {
+		r1 = rf(addr, bt, qt, from, to, limit...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -134,8 +257,91 @@ func (_m *OrderDao) GetByUserAddress(addr common.Address) ([]*types.Order, error
 	return r0, r1
 }
 
-// GetCurrentByUserAddress provides a mock function with given fields: addr
-func (_m *OrderDao) GetCurrentByUserAddress(addr common.Address) ([]*types.Order, error) {
+// GetCurrentByUserAddress provides a mock function with given fields: a, limit
+func (_m *OrderDao) GetCurrentByUserAddress(a common.Address, limit ...int) ([]*types.Order, error) {
+	_va := make([]interface{}, len(limit))
+	for _i := range limit {
+		_va[_i] = limit[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, a)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*types.Order
+	if rf, ok := ret.Get(0).(func(common.Address, ...int) []*types.Order); ok {
+		r0 = rf(a, limit...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, ...int) error); ok {
+		r1 = rf(a, limit...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHistoryByUserAddress provides a mock function with given fields: a, bt, qt, from, to, limit
+func (_m *OrderDao) GetHistoryByUserAddress(a common.Address, bt common.Address, qt common.Address, from int64, to int64, limit ...int) ([]*types.Order, error) {
+	_va := make([]interface{}, len(limit))
+	for _i := range limit {
+		_va[_i] = limit[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, a, bt, qt, from, to)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*types.Order
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address, int64, int64, ...int) []*types.Order); ok {
+		r0 = rf(a, bt, qt, from, to, limit...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, common.Address, int64, int64, ...int) error); ok {
+		r1 = rf(a, bt, qt, from, to, limit...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOpenOrders provides a mock function with given fields:
+func (_m *OrderDao) GetOpenOrders() ([]*types.Order, error) {
+	ret := _m.Called()
+
+	var r0 []*types.Order
+	if rf, ok := ret.Get(0).(func() []*types.Order); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOpenOrdersByUserAddress provides a mock function with given fields: addr
+func (_m *OrderDao) GetOpenOrdersByUserAddress(addr common.Address) ([]*types.Order, error) {
 	ret := _m.Called(addr)
 
 	var r0 []*types.Order
@@ -157,16 +363,124 @@ func (_m *OrderDao) GetCurrentByUserAddress(addr common.Address) ([]*types.Order
 	return r0, r1
 }
 
-// GetHistoryByUserAddress provides a mock function with given fields: addr
-func (_m *OrderDao) GetHistoryByUserAddress(addr common.Address) ([]*types.Order, error) {
+// GetOrderBook provides a mock function with given fields: _a0
+func (_m *OrderDao) GetOrderBook(_a0 *types.Pair) ([]map[string]string, []map[string]string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []map[string]string
+	if rf, ok := ret.Get(0).(func(*types.Pair) []map[string]string); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]map[string]string)
+		}
+	}
+
+	var r1 []map[string]string
+	if rf, ok := ret.Get(1).(func(*types.Pair) []map[string]string); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]map[string]string)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*types.Pair) error); ok {
+		r2 = rf(_a0)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetOrderBookInDb provides a mock function with given fields: _a0
+func (_m *OrderDao) GetOrderBookInDb(_a0 *types.Pair) ([]map[string]string, []map[string]string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []map[string]string
+	if rf, ok := ret.Get(0).(func(*types.Pair) []map[string]string); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]map[string]string)
+		}
+	}
+
+	var r1 []map[string]string
+	if rf, ok := ret.Get(1).(func(*types.Pair) []map[string]string); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]map[string]string)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*types.Pair) error); ok {
+		r2 = rf(_a0)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetOrderBookPricePoint provides a mock function with given fields: p, pp, side
+func (_m *OrderDao) GetOrderBookPricePoint(p *types.Pair, pp *big.Int, side string) (*big.Int, error) {
+	ret := _m.Called(p, pp, side)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(*types.Pair, *big.Int, string) *big.Int); ok {
+		r0 = rf(p, pp, side)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*types.Pair, *big.Int, string) error); ok {
+		r1 = rf(p, pp, side)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderCountByUserAddress provides a mock function with given fields: addr
+func (_m *OrderDao) GetOrderCountByUserAddress(addr common.Address) (int, error) {
 	ret := _m.Called(addr)
 
-	var r0 []*types.Order
-	if rf, ok := ret.Get(0).(func(common.Address) []*types.Order); ok {
+	var r0 int
+	if rf, ok := ret.Get(0).(func(common.Address) int); ok {
+		r0 = rf(addr)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(addr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderNonce provides a mock function with given fields: addr
+func (_m *OrderDao) GetOrderNonce(addr common.Address) (interface{}, error) {
+	ret := _m.Called(addr)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(common.Address) interface{}); ok {
 		r0 = rf(addr)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Order)
+			r0 = ret.Get(0).(interface{})
 		}
 	}
 
@@ -180,13 +494,89 @@ func (_m *OrderDao) GetHistoryByUserAddress(addr common.Address) ([]*types.Order
 	return r0, r1
 }
 
-// GetUserLockedBalance provides a mock function with given fields: account, token
-func (_m *OrderDao) GetUserLockedBalance(account common.Address, token common.Address) (*big.Int, error) {
-	ret := _m.Called(account, token)
+// GetOrders provides a mock function with given fields: orderSpec, sort, offset, size
+func (_m *OrderDao) GetOrders(orderSpec types.OrderSpec, sort []string, offset int, size int) (*types.OrderRes, error) {
+	ret := _m.Called(orderSpec, sort, offset, size)
+
+	var r0 *types.OrderRes
+	if rf, ok := ret.Get(0).(func(types.OrderSpec, []string, int, int) *types.OrderRes); ok {
+		r0 = rf(orderSpec, sort, offset, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.OrderRes)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(types.OrderSpec, []string, int, int) error); ok {
+		r1 = rf(orderSpec, sort, offset, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRawOrderBook provides a mock function with given fields: _a0
+func (_m *OrderDao) GetRawOrderBook(_a0 *types.Pair) ([]*types.Order, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*types.Order
+	if rf, ok := ret.Get(0).(func(*types.Pair) []*types.Order); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*types.Pair) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSideOrderBook provides a mock function with given fields: p, side, sort, limit
+func (_m *OrderDao) GetSideOrderBook(p *types.Pair, side string, sort int, limit ...int) ([]map[string]string, error) {
+	_va := make([]interface{}, len(limit))
+	for _i := range limit {
+		_va[_i] = limit[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, p, side, sort)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []map[string]string
+	if rf, ok := ret.Get(0).(func(*types.Pair, string, int, ...int) []map[string]string); ok {
+		r0 = rf(p, side, sort, limit...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]map[string]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*types.Pair, string, int, ...int) error); ok {
+		r1 = rf(p, side, sort, limit...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserLockedBalance provides a mock function with given fields: account, token, p
+func (_m *OrderDao) GetUserLockedBalance(account common.Address, token common.Address, p []*types.Pair) (*big.Int, error) {
+	ret := _m.Called(account, token, p)
 
 	var r0 *big.Int
-	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *big.Int); ok {
-		r0 = rf(account, token)
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, []*types.Pair) *big.Int); ok {
+		r0 = rf(account, token, p)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*big.Int)
@@ -194,8 +584,8 @@ func (_m *OrderDao) GetUserLockedBalance(account common.Address, token common.Ad
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
-		r1 = rf(account, token)
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, []*types.Pair) error); ok {
+		r1 = rf(account, token, p)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -204,11 +594,11 @@ func (_m *OrderDao) GetUserLockedBalance(account common.Address, token common.Ad
 }
 
 // Update provides a mock function with given fields: id, o
-func (_m *OrderDao) Update(id bson.ObjectId, o *types.Order) error {
+func (_m *OrderDao) Update(id primitive.ObjectID, o *types.Order) error {
 	ret := _m.Called(id, o)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(bson.ObjectId, *types.Order) error); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID, *types.Order) error); ok {
 		r0 = rf(id, o)
 	} else {
 		r0 = ret.Error(0)
@@ -217,13 +607,13 @@ func (_m *OrderDao) Update(id bson.ObjectId, o *types.Order) error {
 	return r0
 }
 
-// UpdateAllByHash provides a mock function with given fields: hash, o
-func (_m *OrderDao) UpdateAllByHash(hash common.Hash, o *types.Order) error {
-	ret := _m.Called(hash, o)
+// UpdateAllByHash provides a mock function with given fields: h, o
+func (_m *OrderDao) UpdateAllByHash(h common.Hash, o *types.Order) error {
+	ret := _m.Called(h, o)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(common.Hash, *types.Order) error); ok {
-		r0 = rf(hash, o)
+		r0 = rf(h, o)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -231,13 +621,13 @@ func (_m *OrderDao) UpdateAllByHash(hash common.Hash, o *types.Order) error {
 	return r0
 }
 
-// UpdateByHash provides a mock function with given fields: hash, o
-func (_m *OrderDao) UpdateByHash(hash common.Hash, o *types.Order) error {
-	ret := _m.Called(hash, o)
+// UpdateByHash provides a mock function with given fields: h, o
+func (_m *OrderDao) UpdateByHash(h common.Hash, o *types.Order) error {
+	ret := _m.Called(h, o)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(common.Hash, *types.Order) error); ok {
-		r0 = rf(hash, o)
+		r0 = rf(h, o)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -245,13 +635,13 @@ func (_m *OrderDao) UpdateByHash(hash common.Hash, o *types.Order) error {
 	return r0
 }
 
-// UpdateOrderFilledAmount provides a mock function with given fields: hash, value
-func (_m *OrderDao) UpdateOrderFilledAmount(hash common.Hash, value *big.Int) error {
-	ret := _m.Called(hash, value)
+// UpdateOrderFilledAmount provides a mock function with given fields: h, value
+func (_m *OrderDao) UpdateOrderFilledAmount(h common.Hash, value *big.Int) error {
+	ret := _m.Called(h, value)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(common.Hash, *big.Int) error); ok {
-		r0 = rf(hash, value)
+		r0 = rf(h, value)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -259,16 +649,120 @@ func (_m *OrderDao) UpdateOrderFilledAmount(hash common.Hash, value *big.Int) er
 	return r0
 }
 
-// UpdateOrderStatus provides a mock function with given fields: hash, status
-func (_m *OrderDao) UpdateOrderStatus(hash common.Hash, status string) error {
-	ret := _m.Called(hash, status)
+// UpdateOrderFilledAmounts provides a mock function with given fields: h, values
+func (_m *OrderDao) UpdateOrderFilledAmounts(h []common.Hash, values []*big.Int) ([]*types.Order, error) {
+	ret := _m.Called(h, values)
+
+	var r0 []*types.Order
+	if rf, ok := ret.Get(0).(func([]common.Hash, []*big.Int) []*types.Order); ok {
+		r0 = rf(h, values)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]common.Hash, []*big.Int) error); ok {
+		r1 = rf(h, values)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateOrderStatus provides a mock function with given fields: h, status
+func (_m *OrderDao) UpdateOrderStatus(h common.Hash, status string) error {
+	ret := _m.Called(h, status)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(common.Hash, string) error); ok {
-		r0 = rf(hash, status)
+		r0 = rf(h, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateOrderStatusesByHashes provides a mock function with given fields: status, hashes
+func (_m *OrderDao) UpdateOrderStatusesByHashes(status string, hashes ...common.Hash) ([]*types.Order, error) {
+	_va := make([]interface{}, len(hashes))
+	for _i := range hashes {
+		_va[_i] = hashes[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, status)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*types.Order
+	if rf, ok := ret.Get(0).(func(string, ...common.Hash) []*types.Order); ok {
+		r0 = rf(status, hashes...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, ...common.Hash) error); ok {
+		r1 = rf(status, hashes...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: id, o
+func (_m *OrderDao) Upsert(id primitive.ObjectID, o *types.Order) error {
+	ret := _m.Called(id, o)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID, *types.Order) error); ok {
+		r0 = rf(id, o)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertByHash provides a mock function with given fields: h, o
+func (_m *OrderDao) UpsertByHash(h common.Hash, o *types.Order) error {
+	ret := _m.Called(h, o)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Hash, *types.Order) error); ok {
+		r0 = rf(h, o)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
+
+// Watch provides a mock function with given fields: resumeAfter
+func (_m *OrderDao) Watch(resumeAfter []byte) (interfaces.ChangeStream, error) {
+	ret := _m.Called(resumeAfter)
+
+	var r0 interfaces.ChangeStream
+	if rf, ok := ret.Get(0).(func([]byte) interfaces.ChangeStream); ok {
+		r0 = rf(resumeAfter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interfaces.ChangeStream)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]byte) error); ok {
+		r1 = rf(resumeAfter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
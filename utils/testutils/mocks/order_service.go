@@ -2,17 +2,33 @@
 
 package mocks
 
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
+import context "context"
 
 import mock "github.com/stretchr/testify/mock"
 import types "github.com/tomochain/tomox-sdk/types"
+import primitive "go.mongodb.org/mongo-driver/bson/primitive"
+import big "math/big"
 
 // OrderService is an autogenerated mock type for the OrderService type
 type OrderService struct {
 	mock.Mock
 }
 
+// CancelAllOrder provides a mock function with given fields: a
+func (_m *OrderService) CancelAllOrder(a common.Address) error {
+	ret := _m.Called(a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address) error); ok {
+		r0 = rf(a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CancelOrder provides a mock function with given fields: oc
 func (_m *OrderService) CancelOrder(oc *types.OrderCancel) error {
 	ret := _m.Called(oc)
@@ -27,27 +43,29 @@ func (_m *OrderService) CancelOrder(oc *types.OrderCancel) error {
 	return r0
 }
 
-// CancelTrades provides a mock function with given fields: trades
-func (_m *OrderService) CancelTrades(trades []*types.Trade) error {
-	ret := _m.Called(trades)
+// CancelOrders provides a mock function with given fields: ocs
+func (_m *OrderService) CancelOrders(ocs []*types.OrderCancel) []*types.OrderBatchResult {
+	ret := _m.Called(ocs)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func([]*types.Trade) error); ok {
-		r0 = rf(trades)
+	var r0 []*types.OrderBatchResult
+	if rf, ok := ret.Get(0).(func([]*types.OrderCancel) []*types.OrderBatchResult); ok {
+		r0 = rf(ocs)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.OrderBatchResult)
+		}
 	}
 
 	return r0
 }
 
-// GetByHash provides a mock function with given fields: hash
-func (_m *OrderService) GetByHash(hash common.Hash) (*types.Order, error) {
-	ret := _m.Called(hash)
+// GetByHash provides a mock function with given fields: h
+func (_m *OrderService) GetByHash(h common.Hash) (*types.Order, error) {
+	ret := _m.Called(h)
 
 	var r0 *types.Order
 	if rf, ok := ret.Get(0).(func(common.Hash) *types.Order); ok {
-		r0 = rf(hash)
+		r0 = rf(h)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*types.Order)
@@ -56,7 +74,30 @@ func (_m *OrderService) GetByHash(hash common.Hash) (*types.Order, error) {
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
-		r1 = rf(hash)
+		r1 = rf(h)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByHashes provides a mock function with given fields: hashes
+func (_m *OrderService) GetByHashes(hashes []common.Hash) ([]*types.Order, error) {
+	ret := _m.Called(hashes)
+
+	var r0 []*types.Order
+	if rf, ok := ret.Get(0).(func([]common.Hash) []*types.Order); ok {
+		r0 = rf(hashes)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]common.Hash) error); ok {
+		r1 = rf(hashes)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -65,11 +106,11 @@ func (_m *OrderService) GetByHash(hash common.Hash) (*types.Order, error) {
 }
 
 // GetByID provides a mock function with given fields: id
-func (_m *OrderService) GetByID(id bson.ObjectId) (*types.Order, error) {
+func (_m *OrderService) GetByID(id primitive.ObjectID) (*types.Order, error) {
 	ret := _m.Called(id)
 
 	var r0 *types.Order
-	if rf, ok := ret.Get(0).(func(bson.ObjectId) *types.Order); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID) *types.Order); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
@@ -78,7 +119,7 @@ func (_m *OrderService) GetByID(id bson.ObjectId) (*types.Order, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bson.ObjectId) error); ok {
+	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
 		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
@@ -87,19 +128,107 @@ func (_m *OrderService) GetByID(id bson.ObjectId) (*types.Order, error) {
 	return r0, r1
 }
 
-// GetByUserAddress provides a mock function with given fields: addr
-func (_m *OrderService) GetByUserAddress(addr common.Address) ([]*types.Order, error) {
-	ret := _m.Called(addr)
+// GetByUserAddress provides a mock function with given fields: a, bt, qt, from, to, limit
+func (_m *OrderService) GetByUserAddress(a common.Address, bt common.Address, qt common.Address, from int64, to int64, limit ...int) ([]*types.Order, error) {
+	_va := make([]interface{}, len(limit))
+	for _i := range limit {
+		_va[_i] = limit[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, a, bt, qt, from, to)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []*types.Order
-	if rf, ok := ret.Get(0).(func(common.Address) []*types.Order); ok {
-		r0 = rf(addr)
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address, int64, int64, ...int) []*types.Order); ok {
+		r0 = rf(a, bt, qt, from, to, limit...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*types.Order)
 		}
 	}
 
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, common.Address, int64, int64, ...int) error); ok {
+		r1 = rf(a, bt, qt, from, to, limit...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCurrentByUserAddress provides a mock function with given fields: a, limit
+func (_m *OrderService) GetCurrentByUserAddress(a common.Address, limit ...int) ([]*types.Order, error) {
+	_va := make([]interface{}, len(limit))
+	for _i := range limit {
+		_va[_i] = limit[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, a)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*types.Order
+	if rf, ok := ret.Get(0).(func(common.Address, ...int) []*types.Order); ok {
+		r0 = rf(a, limit...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, ...int) error); ok {
+		r1 = rf(a, limit...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHistoryByUserAddress provides a mock function with given fields: a, bt, qt, from, to, limit
+func (_m *OrderService) GetHistoryByUserAddress(a common.Address, bt common.Address, qt common.Address, from int64, to int64, limit ...int) ([]*types.Order, error) {
+	_va := make([]interface{}, len(limit))
+	for _i := range limit {
+		_va[_i] = limit[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, a, bt, qt, from, to)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*types.Order
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address, int64, int64, ...int) []*types.Order); ok {
+		r0 = rf(a, bt, qt, from, to, limit...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Order)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, common.Address, int64, int64, ...int) error); ok {
+		r1 = rf(a, bt, qt, from, to, limit...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrderCountByUserAddress provides a mock function with given fields: addr
+func (_m *OrderService) GetOrderCountByUserAddress(addr common.Address) (int, error) {
+	ret := _m.Called(addr)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(common.Address) int); ok {
+		r0 = rf(addr)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
 	var r1 error
 	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
 		r1 = rf(addr)
@@ -110,16 +239,16 @@ func (_m *OrderService) GetByUserAddress(addr common.Address) ([]*types.Order, e
 	return r0, r1
 }
 
-// GetCurrentByUserAddress provides a mock function with given fields: addr
-func (_m *OrderService) GetCurrentByUserAddress(addr common.Address) ([]*types.Order, error) {
+// GetOrderNonceByUserAddress provides a mock function with given fields: addr
+func (_m *OrderService) GetOrderNonceByUserAddress(addr common.Address) (interface{}, error) {
 	ret := _m.Called(addr)
 
-	var r0 []*types.Order
-	if rf, ok := ret.Get(0).(func(common.Address) []*types.Order); ok {
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(common.Address) interface{}); ok {
 		r0 = rf(addr)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Order)
+			r0 = ret.Get(0).(interface{})
 		}
 	}
 
@@ -133,16 +262,39 @@ func (_m *OrderService) GetCurrentByUserAddress(addr common.Address) ([]*types.O
 	return r0, r1
 }
 
-// GetHistoryByUserAddress provides a mock function with given fields: addr
-func (_m *OrderService) GetHistoryByUserAddress(addr common.Address) ([]*types.Order, error) {
+// GetOrders provides a mock function with given fields: orderSpec, sort, offset, size
+func (_m *OrderService) GetOrders(orderSpec types.OrderSpec, sort []string, offset int, size int) (*types.OrderRes, error) {
+	ret := _m.Called(orderSpec, sort, offset, size)
+
+	var r0 *types.OrderRes
+	if rf, ok := ret.Get(0).(func(types.OrderSpec, []string, int, int) *types.OrderRes); ok {
+		r0 = rf(orderSpec, sort, offset, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.OrderRes)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(types.OrderSpec, []string, int, int) error); ok {
+		r1 = rf(orderSpec, sort, offset, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrdersLockedBalanceByUserAddress provides a mock function with given fields: addr
+func (_m *OrderService) GetOrdersLockedBalanceByUserAddress(addr common.Address) (map[string]*big.Int, error) {
 	ret := _m.Called(addr)
 
-	var r0 []*types.Order
-	if rf, ok := ret.Get(0).(func(common.Address) []*types.Order); ok {
+	var r0 map[string]*big.Int
+	if rf, ok := ret.Get(0).(func(common.Address) map[string]*big.Int); ok {
 		r0 = rf(addr)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Order)
+			r0 = ret.Get(0).(map[string]*big.Int)
 		}
 	}
 
@@ -170,6 +322,20 @@ func (_m *OrderService) HandleEngineResponse(res *types.EngineResponse) error {
 	return r0
 }
 
+// HoldOrderNonce provides a mock function with given fields: addr, nonce, h
+func (_m *OrderService) HoldOrderNonce(addr common.Address, nonce *big.Int, h common.Hash) error {
+	ret := _m.Called(addr, nonce, h)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, *big.Int, common.Hash) error); ok {
+		r0 = rf(addr, nonce, h)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewOrder provides a mock function with given fields: o
 func (_m *OrderService) NewOrder(o *types.Order) error {
 	ret := _m.Called(o)
@@ -184,61 +350,53 @@ func (_m *OrderService) NewOrder(o *types.Order) error {
 	return r0
 }
 
-// RelayOrderUpdate provides a mock function with given fields: res
-func (_m *OrderService) RelayOrderUpdate(res *types.EngineResponse) {
-	_m.Called(res)
-}
+// NewOrders provides a mock function with given fields: orders
+func (_m *OrderService) NewOrders(orders []*types.Order) ([]*types.OrderBatchResult, error) {
+	ret := _m.Called(orders)
 
-// RelayTradeUpdate provides a mock function with given fields: res
-func (_m *OrderService) RelayTradeUpdate(res *types.EngineResponse) {
-	_m.Called(res)
-}
-
-// RelayUpdateOverSocket provides a mock function with given fields: res
-func (_m *OrderService) RelayUpdateOverSocket(res *types.EngineResponse) {
-	_m.Called(res)
-}
-
-// Rollback provides a mock function with given fields: res
-func (_m *OrderService) Rollback(res *types.EngineResponse) *types.EngineResponse {
-	ret := _m.Called(res)
-
-	var r0 *types.EngineResponse
-	if rf, ok := ret.Get(0).(func(*types.EngineResponse) *types.EngineResponse); ok {
-		r0 = rf(res)
+	var r0 []*types.OrderBatchResult
+	if rf, ok := ret.Get(0).(func([]*types.Order) []*types.OrderBatchResult); ok {
+		r0 = rf(orders)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*types.EngineResponse)
+			r0 = ret.Get(0).([]*types.OrderBatchResult)
 		}
 	}
 
-	return r0
-}
-
-// RollbackOrder provides a mock function with given fields: o
-func (_m *OrderService) RollbackOrder(o *types.Order) error {
-	ret := _m.Called(o)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*types.Order) error); ok {
-		r0 = rf(o)
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]*types.Order) error); ok {
+		r1 = rf(orders)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// RollbackTrade provides a mock function with given fields: o, t
-func (_m *OrderService) RollbackTrade(o *types.Order, t *types.Trade) error {
-	ret := _m.Called(o, t)
+// ReleaseOrderNonce provides a mock function with given fields: addr, nonce, h
+func (_m *OrderService) ReleaseOrderNonce(addr common.Address, nonce *big.Int, h common.Hash) {
+	_m.Called(addr, nonce, h)
+}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*types.Order, *types.Trade) error); ok {
-		r0 = rf(o, t)
+// ReplaceOrder provides a mock function with given fields: ctx, r
+func (_m *OrderService) ReplaceOrder(ctx context.Context, r *types.OrderReplace) (*types.OrderReplaceResult, error) {
+	ret := _m.Called(ctx, r)
+
+	var r0 *types.OrderReplaceResult
+	if rf, ok := ret.Get(0).(func(context.Context, *types.OrderReplace) *types.OrderReplaceResult); ok {
+		r0 = rf(ctx, r)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.OrderReplaceResult)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *types.OrderReplace) error); ok {
+		r1 = rf(ctx, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
@@ -2,27 +2,73 @@
 
 package mocks
 
-import (
-	common "github.com/ethereum/go-ethereum/common"
-	mock "github.com/stretchr/testify/mock"
-	"github.com/tomochain/tomox-sdk/ws"
-)
+import common "github.com/ethereum/go-ethereum/common"
+
+import mock "github.com/stretchr/testify/mock"
+import types "github.com/tomochain/tomox-sdk/types"
+import ws "github.com/tomochain/tomox-sdk/ws"
 
 // OrderBookService is an autogenerated mock type for the OrderBookService type
 type OrderBookService struct {
 	mock.Mock
 }
 
+// GetAggregatedOrderBook provides a mock function with given fields: bt, qt, opts
+func (_m *OrderBookService) GetAggregatedOrderBook(bt common.Address, qt common.Address, opts types.OrderBookOptions) (*types.OrderBook, error) {
+	ret := _m.Called(bt, qt, opts)
+
+	var r0 *types.OrderBook
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, types.OrderBookOptions) *types.OrderBook); ok {
+		r0 = rf(bt, qt, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.OrderBook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, types.OrderBookOptions) error); ok {
+		r1 = rf(bt, qt, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDbOrderBook provides a mock function with given fields: bt, qt
+func (_m *OrderBookService) GetDbOrderBook(bt common.Address, qt common.Address) (*types.OrderBook, error) {
+	ret := _m.Called(bt, qt)
+
+	var r0 *types.OrderBook
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *types.OrderBook); ok {
+		r0 = rf(bt, qt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.OrderBook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
+		r1 = rf(bt, qt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOrderBook provides a mock function with given fields: bt, qt
-func (_m *OrderBookService) GetOrderBook(bt common.Address, qt common.Address) (map[string]interface{}, error) {
+func (_m *OrderBookService) GetOrderBook(bt common.Address, qt common.Address) (*types.OrderBook, error) {
 	ret := _m.Called(bt, qt)
 
-	var r0 map[string]interface{}
-	if rf, ok := ret.Get(0).(func(common.Address, common.Address) map[string]interface{}); ok {
+	var r0 *types.OrderBook
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *types.OrderBook); ok {
 		r0 = rf(bt, qt)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(map[string]interface{})
+			r0 = ret.Get(0).(*types.OrderBook)
 		}
 	}
 
@@ -36,12 +82,65 @@ func (_m *OrderBookService) GetOrderBook(bt common.Address, qt common.Address) (
 	return r0, r1
 }
 
-// Subscribe provides a mock function with given fields: conn, bt, qt
-func (_m *OrderBookService) Subscribe(conn *ws.Client, bt common.Address, qt common.Address) {
-	_m.Called(conn, bt, qt)
+// GetRawOrderBook provides a mock function with given fields: bt, qt
+func (_m *OrderBookService) GetRawOrderBook(bt common.Address, qt common.Address) (*types.RawOrderBook, error) {
+	ret := _m.Called(bt, qt)
+
+	var r0 *types.RawOrderBook
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *types.RawOrderBook); ok {
+		r0 = rf(bt, qt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.RawOrderBook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
+		r1 = rf(bt, qt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResyncOrderBook provides a mock function with given fields: c, bt, qt
+func (_m *OrderBookService) ResyncOrderBook(c *ws.Client, bt common.Address, qt common.Address) {
+	_m.Called(c, bt, qt)
+}
+
+// ResyncRawOrderBook provides a mock function with given fields: c, bt, qt
+func (_m *OrderBookService) ResyncRawOrderBook(c *ws.Client, bt common.Address, qt common.Address) {
+	_m.Called(c, bt, qt)
+}
+
+// SubscribeOrderBook provides a mock function with given fields: c, bt, qt, opts
+func (_m *OrderBookService) SubscribeOrderBook(c *ws.Client, bt common.Address, qt common.Address, opts types.OrderBookOptions) {
+	_m.Called(c, bt, qt, opts)
+}
+
+// SubscribeRawOrderBook provides a mock function with given fields: c, bt, qt
+func (_m *OrderBookService) SubscribeRawOrderBook(c *ws.Client, bt common.Address, qt common.Address) {
+	_m.Called(c, bt, qt)
+}
+
+// UnsubscribeOrderBook provides a mock function with given fields: c
+func (_m *OrderBookService) UnsubscribeOrderBook(c *ws.Client) {
+	_m.Called(c)
+}
+
+// UnsubscribeOrderBookChannel provides a mock function with given fields: c, bt, qt
+func (_m *OrderBookService) UnsubscribeOrderBookChannel(c *ws.Client, bt common.Address, qt common.Address) {
+	_m.Called(c, bt, qt)
+}
+
+// UnsubscribeRawOrderBook provides a mock function with given fields: c
+func (_m *OrderBookService) UnsubscribeRawOrderBook(c *ws.Client) {
+	_m.Called(c)
 }
 
-// Unsubscribe provides a mock function with given fields: conn, bt, qt
-func (_m *OrderBookService) Unsubscribe(conn *ws.Client, bt common.Address, qt common.Address) {
-	_m.Called(conn, bt, qt)
+// UnsubscribeRawOrderBookChannel provides a mock function with given fields: c, bt, qt
+func (_m *OrderBookService) UnsubscribeRawOrderBookChannel(c *ws.Client, bt common.Address, qt common.Address) {
+	_m.Called(c, bt, qt)
 }
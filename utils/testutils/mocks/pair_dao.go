@@ -2,11 +2,11 @@
 
 package mocks
 
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
 
 import mock "github.com/stretchr/testify/mock"
 import types "github.com/tomochain/tomox-sdk/types"
+import primitive "go.mongodb.org/mongo-driver/bson/primitive"
 
 // PairDao is an autogenerated mock type for the PairDao type
 type PairDao struct {
@@ -27,6 +27,34 @@ func (_m *PairDao) Create(o *types.Pair) error {
 	return r0
 }
 
+// DeleteByToken provides a mock function with given fields: baseAddress, quoteAddress
+func (_m *PairDao) DeleteByToken(baseAddress common.Address, quoteAddress common.Address) error {
+	ret := _m.Called(baseAddress, quoteAddress)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) error); ok {
+		r0 = rf(baseAddress, quoteAddress)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByTokenAndCoinbase provides a mock function with given fields: baseAddress, quoteAddress, addr
+func (_m *PairDao) DeleteByTokenAndCoinbase(baseAddress common.Address, quoteAddress common.Address, addr common.Address) error {
+	ret := _m.Called(baseAddress, quoteAddress, addr)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address) error); ok {
+		r0 = rf(baseAddress, quoteAddress, addr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetActivePairs provides a mock function with given fields:
 func (_m *PairDao) GetActivePairs() ([]*types.Pair, error) {
 	ret := _m.Called()
@@ -50,6 +78,29 @@ func (_m *PairDao) GetActivePairs() ([]*types.Pair, error) {
 	return r0, r1
 }
 
+// GetActivePairsByCoinbase provides a mock function with given fields: addr
+func (_m *PairDao) GetActivePairsByCoinbase(addr common.Address) ([]*types.Pair, error) {
+	ret := _m.Called(addr)
+
+	var r0 []*types.Pair
+	if rf, ok := ret.Get(0).(func(common.Address) []*types.Pair); ok {
+		r0 = rf(addr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Pair)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(addr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields:
 func (_m *PairDao) GetAll() ([]types.Pair, error) {
 	ret := _m.Called()
@@ -73,12 +124,35 @@ func (_m *PairDao) GetAll() ([]types.Pair, error) {
 	return r0, r1
 }
 
+// GetAllByCoinbase provides a mock function with given fields: addr
+func (_m *PairDao) GetAllByCoinbase(addr common.Address) ([]types.Pair, error) {
+	ret := _m.Called(addr)
+
+	var r0 []types.Pair
+	if rf, ok := ret.Get(0).(func(common.Address) []types.Pair); ok {
+		r0 = rf(addr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Pair)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(addr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: id
-func (_m *PairDao) GetByID(id bson.ObjectId) (*types.Pair, error) {
+func (_m *PairDao) GetByID(id primitive.ObjectID) (*types.Pair, error) {
 	ret := _m.Called(id)
 
 	var r0 *types.Pair
-	if rf, ok := ret.Get(0).(func(bson.ObjectId) *types.Pair); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID) *types.Pair); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
@@ -87,7 +161,7 @@ func (_m *PairDao) GetByID(id bson.ObjectId) (*types.Pair, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bson.ObjectId) error); ok {
+	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
 		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
@@ -164,3 +238,49 @@ func (_m *PairDao) GetByTokenSymbols(baseTokenSymbol string, quoteTokenSymbol st
 
 	return r0, r1
 }
+
+// GetListedPairs provides a mock function with given fields:
+func (_m *PairDao) GetListedPairs() ([]types.Pair, error) {
+	ret := _m.Called()
+
+	var r0 []types.Pair
+	if rf, ok := ret.Get(0).(func() []types.Pair); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Pair)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUnlistedPairs provides a mock function with given fields:
+func (_m *PairDao) GetUnlistedPairs() ([]types.Pair, error) {
+	ret := _m.Called()
+
+	var r0 []types.Pair
+	if rf, ok := ret.Get(0).(func() []types.Pair); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Pair)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -2,11 +2,11 @@
 
 package mocks
 
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
-import mock "github.com/stretchr/testify/mock"
 
+import mock "github.com/stretchr/testify/mock"
 import types "github.com/tomochain/tomox-sdk/types"
+import primitive "go.mongodb.org/mongo-driver/bson/primitive"
 
 // PairService is an autogenerated mock type for the PairService type
 type PairService struct {
@@ -27,16 +27,108 @@ func (_m *PairService) Create(pair *types.Pair) error {
 	return r0
 }
 
+// CreatePairs provides a mock function with given fields: token
+func (_m *PairService) CreatePairs(token common.Address) ([]*types.Pair, error) {
+	ret := _m.Called(token)
+
+	var r0 []*types.Pair
+	if rf, ok := ret.Get(0).(func(common.Address) []*types.Pair); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Pair)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields:
-func (_m *PairService) GetAll() ([]*types.Pair, error) {
+func (_m *PairService) GetAll() ([]types.Pair, error) {
 	ret := _m.Called()
 
-	var r0 []*types.Pair
-	if rf, ok := ret.Get(0).(func() []*types.Pair); ok {
+	var r0 []types.Pair
+	if rf, ok := ret.Get(0).(func() []types.Pair); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Pair)
+			r0 = ret.Get(0).([]types.Pair)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllByCoinbase provides a mock function with given fields: addr
+func (_m *PairService) GetAllByCoinbase(addr common.Address) ([]types.Pair, error) {
+	ret := _m.Called(addr)
+
+	var r0 []types.Pair
+	if rf, ok := ret.Get(0).(func(common.Address) []types.Pair); ok {
+		r0 = rf(addr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Pair)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(addr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllMarketStats provides a mock function with given fields:
+func (_m *PairService) GetAllMarketStats() ([]*types.PairData, error) {
+	ret := _m.Called()
+
+	var r0 []*types.PairData
+	if rf, ok := ret.Get(0).(func() []*types.PairData); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.PairData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllTokenPairData provides a mock function with given fields:
+func (_m *PairService) GetAllTokenPairData() ([]*types.PairData, error) {
+	ret := _m.Called()
+
+	var r0 []*types.PairData
+	if rf, ok := ret.Get(0).(func() []*types.PairData); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.PairData)
 		}
 	}
 
@@ -51,11 +143,11 @@ func (_m *PairService) GetAll() ([]*types.Pair, error) {
 }
 
 // GetByID provides a mock function with given fields: id
-func (_m *PairService) GetByID(id bson.ObjectId) (*types.Pair, error) {
+func (_m *PairService) GetByID(id primitive.ObjectID) (*types.Pair, error) {
 	ret := _m.Called(id)
 
 	var r0 *types.Pair
-	if rf, ok := ret.Get(0).(func(bson.ObjectId) *types.Pair); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID) *types.Pair); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
@@ -64,7 +156,7 @@ func (_m *PairService) GetByID(id bson.ObjectId) (*types.Pair, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bson.ObjectId) error); ok {
+	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
 		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
@@ -96,14 +188,62 @@ func (_m *PairService) GetByTokenAddress(bt common.Address, qt common.Address) (
 	return r0, r1
 }
 
-func (_m *PairService) GetTokenPairData(bt, qt common.Address) ([]*types.Tick, error) {
+// GetListedPairs provides a mock function with given fields:
+func (_m *PairService) GetListedPairs() ([]types.Pair, error) {
+	ret := _m.Called()
+
+	var r0 []types.Pair
+	if rf, ok := ret.Get(0).(func() []types.Pair); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Pair)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMarketStats provides a mock function with given fields: bt, qt
+func (_m *PairService) GetMarketStats(bt common.Address, qt common.Address) (*types.PairData, error) {
+	ret := _m.Called(bt, qt)
+
+	var r0 *types.PairData
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *types.PairData); ok {
+		r0 = rf(bt, qt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.PairData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
+		r1 = rf(bt, qt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenPairData provides a mock function with given fields: bt, qt
+func (_m *PairService) GetTokenPairData(bt common.Address, qt common.Address) (*types.PairData, error) {
 	ret := _m.Called(bt, qt)
-	var r0 []*types.Tick
-	if rf, ok := ret.Get(0).(func(common.Address, common.Address) []*types.Tick); ok {
+
+	var r0 *types.PairData
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *types.PairData); ok {
 		r0 = rf(bt, qt)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Tick)
+			r0 = ret.Get(0).(*types.PairData)
 		}
 	}
 
@@ -117,15 +257,16 @@ func (_m *PairService) GetTokenPairData(bt, qt common.Address) ([]*types.Tick, e
 	return r0, r1
 }
 
-func (_m *PairService) GetAllTokenPairData() ([]*types.Tick, error) {
+// GetUnlistedPairs provides a mock function with given fields:
+func (_m *PairService) GetUnlistedPairs() ([]types.Pair, error) {
 	ret := _m.Called()
 
-	var r0 []*types.Tick
-	if rf, ok := ret.Get(0).(func() []*types.Tick); ok {
+	var r0 []types.Pair
+	if rf, ok := ret.Get(0).(func() []types.Pair); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Tick)
+			r0 = ret.Get(0).([]types.Pair)
 		}
 	}
 
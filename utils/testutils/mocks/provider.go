@@ -2,24 +2,24 @@
 
 package mocks
 
-import big "math/big"
 import common "github.com/ethereum/go-ethereum/common"
+import types "github.com/ethereum/go-ethereum/core/types"
 
 import mock "github.com/stretchr/testify/mock"
-import types "github.com/ethereum/go-ethereum/core/types"
+import big "math/big"
 
 // EthereumProvider is an autogenerated mock type for the EthereumProvider type
 type EthereumProvider struct {
 	mock.Mock
 }
 
-// Allowance provides a mock function with given fields: owner, spender, token
-func (_m *EthereumProvider) Allowance(owner common.Address, spender common.Address, token common.Address) (*big.Int, error) {
-	ret := _m.Called(owner, spender, token)
+// Balance provides a mock function with given fields: owner, token
+func (_m *EthereumProvider) Balance(owner common.Address, token common.Address) (*big.Int, error) {
+	ret := _m.Called(owner, token)
 
 	var r0 *big.Int
-	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address) *big.Int); ok {
-		r0 = rf(owner, spender, token)
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *big.Int); ok {
+		r0 = rf(owner, token)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*big.Int)
@@ -27,8 +27,8 @@ func (_m *EthereumProvider) Allowance(owner common.Address, spender common.Addre
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address, common.Address, common.Address) error); ok {
-		r1 = rf(owner, spender, token)
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
+		r1 = rf(owner, token)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,22 +59,20 @@ func (_m *EthereumProvider) BalanceOf(owner common.Address, token common.Address
 	return r0, r1
 }
 
-// ExchangeAllowance provides a mock function with given fields: owner, token
-func (_m *EthereumProvider) ExchangeAllowance(owner common.Address, token common.Address) (*big.Int, error) {
-	ret := _m.Called(owner, token)
+// Decimals provides a mock function with given fields: token
+func (_m *EthereumProvider) Decimals(token common.Address) (uint8, error) {
+	ret := _m.Called(token)
 
-	var r0 *big.Int
-	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *big.Int); ok {
-		r0 = rf(owner, token)
+	var r0 uint8
+	if rf, ok := ret.Get(0).(func(common.Address) uint8); ok {
+		r0 = rf(token)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*big.Int)
-		}
+		r0 = ret.Get(0).(uint8)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
-		r1 = rf(owner, token)
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(token)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -126,13 +124,34 @@ func (_m *EthereumProvider) GetPendingNonceAt(a common.Address) (uint64, error)
 	return r0, r1
 }
 
-// WaitMined provides a mock function with given fields: hash
-func (_m *EthereumProvider) WaitMined(hash common.Hash) (*types.Receipt, error) {
-	ret := _m.Called(hash)
+// Symbol provides a mock function with given fields: token
+func (_m *EthereumProvider) Symbol(token common.Address) (string, error) {
+	ret := _m.Called(token)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(common.Address) string); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WaitMined provides a mock function with given fields: h
+func (_m *EthereumProvider) WaitMined(h common.Hash) (*types.Receipt, error) {
+	ret := _m.Called(h)
 
 	var r0 *types.Receipt
 	if rf, ok := ret.Get(0).(func(common.Hash) *types.Receipt); ok {
-		r0 = rf(hash)
+		r0 = rf(h)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*types.Receipt)
@@ -141,7 +160,7 @@ func (_m *EthereumProvider) WaitMined(hash common.Hash) (*types.Receipt, error)
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
-		r1 = rf(hash)
+		r1 = rf(h)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2,11 +2,11 @@
 
 package mocks
 
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
 
 import mock "github.com/stretchr/testify/mock"
 import types "github.com/tomochain/tomox-sdk/types"
+import primitive "go.mongodb.org/mongo-driver/bson/primitive"
 
 // TokenDao is an autogenerated mock type for the TokenDao type
 type TokenDao struct {
@@ -27,6 +27,34 @@ func (_m *TokenDao) Create(token *types.Token) error {
 	return r0
 }
 
+// DeleteByToken provides a mock function with given fields: contractAddress
+func (_m *TokenDao) DeleteByToken(contractAddress common.Address) error {
+	ret := _m.Called(contractAddress)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address) error); ok {
+		r0 = rf(contractAddress)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByTokenAndCoinbase provides a mock function with given fields: contractAddress, addr
+func (_m *TokenDao) DeleteByTokenAndCoinbase(contractAddress common.Address, addr common.Address) error {
+	ret := _m.Called(contractAddress, addr)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) error); ok {
+		r0 = rf(contractAddress, addr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Drop provides a mock function with given fields:
 func (_m *TokenDao) Drop() error {
 	ret := _m.Called()
@@ -64,6 +92,29 @@ func (_m *TokenDao) GetAll() ([]types.Token, error) {
 	return r0, r1
 }
 
+// GetAllByCoinbase provides a mock function with given fields: addr
+func (_m *TokenDao) GetAllByCoinbase(addr common.Address) ([]types.Token, error) {
+	ret := _m.Called(addr)
+
+	var r0 []types.Token
+	if rf, ok := ret.Get(0).(func(common.Address) []types.Token); ok {
+		r0 = rf(addr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Token)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(addr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBaseTokens provides a mock function with given fields:
 func (_m *TokenDao) GetBaseTokens() ([]types.Token, error) {
 	ret := _m.Called()
@@ -87,13 +138,13 @@ func (_m *TokenDao) GetBaseTokens() ([]types.Token, error) {
 	return r0, r1
 }
 
-// GetByAddress provides a mock function with given fields: owner
-func (_m *TokenDao) GetByAddress(owner common.Address) (*types.Token, error) {
-	ret := _m.Called(owner)
+// GetByAddress provides a mock function with given fields: addr
+func (_m *TokenDao) GetByAddress(addr common.Address) (*types.Token, error) {
+	ret := _m.Called(addr)
 
 	var r0 *types.Token
 	if rf, ok := ret.Get(0).(func(common.Address) *types.Token); ok {
-		r0 = rf(owner)
+		r0 = rf(addr)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*types.Token)
@@ -102,7 +153,7 @@ func (_m *TokenDao) GetByAddress(owner common.Address) (*types.Token, error) {
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
-		r1 = rf(owner)
+		r1 = rf(addr)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -111,11 +162,11 @@ func (_m *TokenDao) GetByAddress(owner common.Address) (*types.Token, error) {
 }
 
 // GetByID provides a mock function with given fields: id
-func (_m *TokenDao) GetByID(id bson.ObjectId) (*types.Token, error) {
+func (_m *TokenDao) GetByID(id primitive.ObjectID) (*types.Token, error) {
 	ret := _m.Called(id)
 
 	var r0 *types.Token
-	if rf, ok := ret.Get(0).(func(bson.ObjectId) *types.Token); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID) *types.Token); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
@@ -124,7 +175,7 @@ func (_m *TokenDao) GetByID(id bson.ObjectId) (*types.Token, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bson.ObjectId) error); ok {
+	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
 		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
@@ -155,3 +206,45 @@ func (_m *TokenDao) GetQuoteTokens() ([]types.Token, error) {
 
 	return r0, r1
 }
+
+// UpdateByToken provides a mock function with given fields: contractAddress, token
+func (_m *TokenDao) UpdateByToken(contractAddress common.Address, token *types.Token) error {
+	ret := _m.Called(contractAddress, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, *types.Token) error); ok {
+		r0 = rf(contractAddress, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateByTokenAndCoinbase provides a mock function with given fields: contractAddress, addr, token
+func (_m *TokenDao) UpdateByTokenAndCoinbase(contractAddress common.Address, addr common.Address, token *types.Token) error {
+	ret := _m.Called(contractAddress, addr, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, *types.Token) error); ok {
+		r0 = rf(contractAddress, addr, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateFiatPriceBySymbol provides a mock function with given fields: symbol, price
+func (_m *TokenDao) UpdateFiatPriceBySymbol(symbol string, price float64) error {
+	ret := _m.Called(symbol, price)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, float64) error); ok {
+		r0 = rf(symbol, price)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
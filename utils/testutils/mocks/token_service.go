@@ -2,11 +2,11 @@
 
 package mocks
 
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
 
 import mock "github.com/stretchr/testify/mock"
 import types "github.com/tomochain/tomox-sdk/types"
+import primitive "go.mongodb.org/mongo-driver/bson/primitive"
 
 // TokenService is an autogenerated mock type for the TokenService type
 type TokenService struct {
@@ -50,6 +50,29 @@ func (_m *TokenService) GetAll() ([]types.Token, error) {
 	return r0, r1
 }
 
+// GetAllByCoinbase provides a mock function with given fields: addr
+func (_m *TokenService) GetAllByCoinbase(addr common.Address) ([]types.Token, error) {
+	ret := _m.Called(addr)
+
+	var r0 []types.Token
+	if rf, ok := ret.Get(0).(func(common.Address) []types.Token); ok {
+		r0 = rf(addr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.Token)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(addr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBaseTokens provides a mock function with given fields:
 func (_m *TokenService) GetBaseTokens() ([]types.Token, error) {
 	ret := _m.Called()
@@ -73,13 +96,13 @@ func (_m *TokenService) GetBaseTokens() ([]types.Token, error) {
 	return r0, r1
 }
 
-// GetByAddress provides a mock function with given fields: addr
-func (_m *TokenService) GetByAddress(addr common.Address) (*types.Token, error) {
-	ret := _m.Called(addr)
+// GetByAddress provides a mock function with given fields: a
+func (_m *TokenService) GetByAddress(a common.Address) (*types.Token, error) {
+	ret := _m.Called(a)
 
 	var r0 *types.Token
 	if rf, ok := ret.Get(0).(func(common.Address) *types.Token); ok {
-		r0 = rf(addr)
+		r0 = rf(a)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*types.Token)
@@ -88,7 +111,7 @@ func (_m *TokenService) GetByAddress(addr common.Address) (*types.Token, error)
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
-		r1 = rf(addr)
+		r1 = rf(a)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -97,11 +120,11 @@ func (_m *TokenService) GetByAddress(addr common.Address) (*types.Token, error)
 }
 
 // GetByID provides a mock function with given fields: id
-func (_m *TokenService) GetByID(id bson.ObjectId) (*types.Token, error) {
+func (_m *TokenService) GetByID(id primitive.ObjectID) (*types.Token, error) {
 	ret := _m.Called(id)
 
 	var r0 *types.Token
-	if rf, ok := ret.Get(0).(func(bson.ObjectId) *types.Token); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID) *types.Token); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
@@ -110,7 +133,7 @@ func (_m *TokenService) GetByID(id bson.ObjectId) (*types.Token, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bson.ObjectId) error); ok {
+	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
 		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
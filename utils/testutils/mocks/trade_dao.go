@@ -2,11 +2,12 @@
 
 package mocks
 
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
 
 import mock "github.com/stretchr/testify/mock"
+import interfaces "github.com/tomochain/tomox-sdk/interfaces"
 import types "github.com/tomochain/tomox-sdk/types"
+import bson "go.mongodb.org/mongo-driver/bson"
 
 // TradeDao is an autogenerated mock type for the TradeDao type
 type TradeDao struct {
@@ -61,6 +62,29 @@ func (_m *TradeDao) Drop() {
 	_m.Called()
 }
 
+// FindAndModify provides a mock function with given fields: h, t
+func (_m *TradeDao) FindAndModify(h common.Hash, t *types.Trade) (*types.Trade, error) {
+	ret := _m.Called(h, t)
+
+	var r0 *types.Trade
+	if rf, ok := ret.Get(0).(func(common.Hash, *types.Trade) *types.Trade); ok {
+		r0 = rf(h, t)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Trade)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Hash, *types.Trade) error); ok {
+		r1 = rf(h, t)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields:
 func (_m *TradeDao) GetAll() ([]types.Trade, error) {
 	ret := _m.Called()
@@ -245,6 +269,29 @@ func (_m *TradeDao) GetByUserAddress(a common.Address) ([]*types.Trade, error) {
 	return r0, r1
 }
 
+// GetLatestTrade provides a mock function with given fields: bt, qt
+func (_m *TradeDao) GetLatestTrade(bt common.Address, qt common.Address) (*types.Trade, error) {
+	ret := _m.Called(bt, qt)
+
+	var r0 *types.Trade
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) *types.Trade); ok {
+		r0 = rf(bt, qt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Trade)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
+		r1 = rf(bt, qt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNTradesByPairAddress provides a mock function with given fields: bt, qt, n
 func (_m *TradeDao) GetNTradesByPairAddress(bt common.Address, qt common.Address, n int) ([]*types.Trade, error) {
 	ret := _m.Called(bt, qt, n)
@@ -268,13 +315,13 @@ func (_m *TradeDao) GetNTradesByPairAddress(bt common.Address, qt common.Address
 	return r0, r1
 }
 
-// GetSortedTrades provides a mock function with given fields: bt, qt, n
-func (_m *TradeDao) GetSortedTrades(bt common.Address, qt common.Address, n int) ([]*types.Trade, error) {
-	ret := _m.Called(bt, qt, n)
+// GetSortedTrades provides a mock function with given fields: bt, qt, from, to, n
+func (_m *TradeDao) GetSortedTrades(bt common.Address, qt common.Address, from int64, to int64, n int) ([]*types.Trade, error) {
+	ret := _m.Called(bt, qt, from, to, n)
 
 	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(common.Address, common.Address, int) []*types.Trade); ok {
-		r0 = rf(bt, qt, n)
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, int64, int64, int) []*types.Trade); ok {
+		r0 = rf(bt, qt, from, to, n)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*types.Trade)
@@ -282,8 +329,84 @@ func (_m *TradeDao) GetSortedTrades(bt common.Address, qt common.Address, n int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address, common.Address, int) error); ok {
-		r1 = rf(bt, qt, n)
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, int64, int64, int) error); ok {
+		r1 = rf(bt, qt, from, to, n)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSortedTradesByUserAddress provides a mock function with given fields: a, bt, qt, from, to, limit
+func (_m *TradeDao) GetSortedTradesByUserAddress(a common.Address, bt common.Address, qt common.Address, from int64, to int64, limit ...int) ([]*types.Trade, error) {
+	_va := make([]interface{}, len(limit))
+	for _i := range limit {
+		_va[_i] = limit[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, a, bt, qt, from, to)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*types.Trade
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address, int64, int64, ...int) []*types.Trade); ok {
+		r0 = rf(a, bt, qt, from, to, limit...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Trade)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, common.Address, int64, int64, ...int) error); ok {
+		r1 = rf(a, bt, qt, from, to, limit...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTradeByTime provides a mock function with given fields: dateFrom, dateTo, pageOffset, pageSize
+func (_m *TradeDao) GetTradeByTime(dateFrom int64, dateTo int64, pageOffset int, pageSize int) ([]*types.Trade, error) {
+	ret := _m.Called(dateFrom, dateTo, pageOffset, pageSize)
+
+	var r0 []*types.Trade
+	if rf, ok := ret.Get(0).(func(int64, int64, int, int) []*types.Trade); ok {
+		r0 = rf(dateFrom, dateTo, pageOffset, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Trade)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int64, int, int) error); ok {
+		r1 = rf(dateFrom, dateTo, pageOffset, pageSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrades provides a mock function with given fields: tradeSpec, sortedBy, pageOffset, pageSize
+func (_m *TradeDao) GetTrades(tradeSpec *types.TradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.TradeRes, error) {
+	ret := _m.Called(tradeSpec, sortedBy, pageOffset, pageSize)
+
+	var r0 *types.TradeRes
+	if rf, ok := ret.Get(0).(func(*types.TradeSpec, []string, int, int) *types.TradeRes); ok {
+		r0 = rf(tradeSpec, sortedBy, pageOffset, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.TradeRes)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*types.TradeSpec, []string, int, int) error); ok {
+		r1 = rf(tradeSpec, sortedBy, pageOffset, pageSize)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -314,6 +437,29 @@ func (_m *TradeDao) GetTradesByPairAddress(bt common.Address, qt common.Address,
 	return r0, r1
 }
 
+// GetTradesUserHistory provides a mock function with given fields: a, tradeSpec, sortedBy, pageOffset, pageSize
+func (_m *TradeDao) GetTradesUserHistory(a common.Address, tradeSpec *types.TradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.TradeRes, error) {
+	ret := _m.Called(a, tradeSpec, sortedBy, pageOffset, pageSize)
+
+	var r0 *types.TradeRes
+	if rf, ok := ret.Get(0).(func(common.Address, *types.TradeSpec, []string, int, int) *types.TradeRes); ok {
+		r0 = rf(a, tradeSpec, sortedBy, pageOffset, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.TradeRes)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(common.Address, *types.TradeSpec, []string, int, int) error); ok {
+		r1 = rf(a, tradeSpec, sortedBy, pageOffset, pageSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: t
 func (_m *TradeDao) Update(t *types.Trade) error {
 	ret := _m.Called(t)
@@ -415,3 +561,26 @@ func (_m *TradeDao) UpdateTradeStatusesByOrderHashes(status string, hashes ...co
 
 	return r0, r1
 }
+
+// Watch provides a mock function with given fields: resumeAfter
+func (_m *TradeDao) Watch(resumeAfter []byte) (interfaces.ChangeStream, error) {
+	ret := _m.Called(resumeAfter)
+
+	var r0 interfaces.ChangeStream
+	if rf, ok := ret.Get(0).(func([]byte) interfaces.ChangeStream); ok {
+		r0 = rf(resumeAfter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interfaces.ChangeStream)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]byte) error); ok {
+		r1 = rf(resumeAfter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -13,22 +13,22 @@ type TradeService struct {
 	mock.Mock
 }
 
-// GetByHash provides a mock function with given fields: hash
-func (_m *TradeService) GetByHash(hash common.Hash) (*types.Trade, error) {
-	ret := _m.Called(hash)
+// GetAllTradesByPairAddress provides a mock function with given fields: bt, qt
+func (_m *TradeService) GetAllTradesByPairAddress(bt common.Address, qt common.Address) ([]*types.Trade, error) {
+	ret := _m.Called(bt, qt)
 
-	var r0 *types.Trade
-	if rf, ok := ret.Get(0).(func(common.Hash) *types.Trade); ok {
-		r0 = rf(hash)
+	var r0 []*types.Trade
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address) []*types.Trade); ok {
+		r0 = rf(bt, qt)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*types.Trade)
+			r0 = ret.Get(0).([]*types.Trade)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
-		r1 = rf(hash)
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
+		r1 = rf(bt, qt)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -36,22 +36,22 @@ func (_m *TradeService) GetByHash(hash common.Hash) (*types.Trade, error) {
 	return r0, r1
 }
 
-// GetByOrderHash provides a mock function with given fields: hash
-func (_m *TradeService) GetByOrderHash(hash common.Hash) ([]*types.Trade, error) {
-	ret := _m.Called(hash)
+// GetByHash provides a mock function with given fields: h
+func (_m *TradeService) GetByHash(h common.Hash) (*types.Trade, error) {
+	ret := _m.Called(h)
 
-	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(common.Hash) []*types.Trade); ok {
-		r0 = rf(hash)
+	var r0 *types.Trade
+	if rf, ok := ret.Get(0).(func(common.Hash) *types.Trade); ok {
+		r0 = rf(h)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Trade)
+			r0 = ret.Get(0).(*types.Trade)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
-		r1 = rf(hash)
+		r1 = rf(h)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,13 +59,13 @@ func (_m *TradeService) GetByOrderHash(hash common.Hash) ([]*types.Trade, error)
 	return r0, r1
 }
 
-// GetByPairAddress provides a mock function with given fields: bt, qt
-func (_m *TradeService) GetByPairAddress(bt common.Address, qt common.Address) ([]*types.Trade, error) {
-	ret := _m.Called(bt, qt)
+// GetByMakerOrderHash provides a mock function with given fields: h
+func (_m *TradeService) GetByMakerOrderHash(h common.Hash) ([]*types.Trade, error) {
+	ret := _m.Called(h)
 
 	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(common.Address, common.Address) []*types.Trade); ok {
-		r0 = rf(bt, qt)
+	if rf, ok := ret.Get(0).(func(common.Hash) []*types.Trade); ok {
+		r0 = rf(h)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*types.Trade)
@@ -73,8 +73,8 @@ func (_m *TradeService) GetByPairAddress(bt common.Address, qt common.Address) (
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
-		r1 = rf(bt, qt)
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
+		r1 = rf(h)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -82,13 +82,13 @@ func (_m *TradeService) GetByPairAddress(bt common.Address, qt common.Address) (
 	return r0, r1
 }
 
-// GetByPairName provides a mock function with given fields: p
-func (_m *TradeService) GetByPairName(p string) ([]*types.Trade, error) {
-	ret := _m.Called(p)
+// GetByOrderHashes provides a mock function with given fields: h
+func (_m *TradeService) GetByOrderHashes(h []common.Hash) ([]*types.Trade, error) {
+	ret := _m.Called(h)
 
 	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(string) []*types.Trade); ok {
-		r0 = rf(p)
+	if rf, ok := ret.Get(0).(func([]common.Hash) []*types.Trade); ok {
+		r0 = rf(h)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*types.Trade)
@@ -96,8 +96,8 @@ func (_m *TradeService) GetByPairName(p string) ([]*types.Trade, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(p)
+	if rf, ok := ret.Get(1).(func([]common.Hash) error); ok {
+		r1 = rf(h)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -105,13 +105,13 @@ func (_m *TradeService) GetByPairName(p string) ([]*types.Trade, error) {
 	return r0, r1
 }
 
-// GetByUserAddress provides a mock function with given fields: addr
-func (_m *TradeService) GetByUserAddress(addr common.Address) ([]*types.Trade, error) {
-	ret := _m.Called(addr)
+// GetByPairName provides a mock function with given fields: p
+func (_m *TradeService) GetByPairName(p string) ([]*types.Trade, error) {
+	ret := _m.Called(p)
 
 	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(common.Address) []*types.Trade); ok {
-		r0 = rf(addr)
+	if rf, ok := ret.Get(0).(func(string) []*types.Trade); ok {
+		r0 = rf(p)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*types.Trade)
@@ -119,8 +119,8 @@ func (_m *TradeService) GetByUserAddress(addr common.Address) ([]*types.Trade, e
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
-		r1 = rf(addr)
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(p)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -128,11 +128,12 @@ func (_m *TradeService) GetByUserAddress(addr common.Address) ([]*types.Trade, e
 	return r0, r1
 }
 
-func (_m *TradeService) GetByOrderHashes(h []common.Hash) ([]*types.Trade, error) {
+// GetByTakerOrderHash provides a mock function with given fields: h
+func (_m *TradeService) GetByTakerOrderHash(h common.Hash) ([]*types.Trade, error) {
 	ret := _m.Called(h)
 
 	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func([]common.Hash) []*types.Trade); ok {
+	if rf, ok := ret.Get(0).(func(common.Hash) []*types.Trade); ok {
 		r0 = rf(h)
 	} else {
 		if ret.Get(0) != nil {
@@ -141,7 +142,7 @@ func (_m *TradeService) GetByOrderHashes(h []common.Hash) ([]*types.Trade, error
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func([]common.Hash) error); ok {
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
 		r1 = rf(h)
 	} else {
 		r1 = ret.Error(1)
@@ -150,13 +151,13 @@ func (_m *TradeService) GetByOrderHashes(h []common.Hash) ([]*types.Trade, error
 	return r0, r1
 }
 
-// GetTrades provides a mock function with given fields: bt, qt
-func (_m *TradeService) GetByMakerOrderHash(h common.Hash) ([]*types.Trade, error) {
-	ret := _m.Called(h)
+// GetByUserAddress provides a mock function with given fields: a
+func (_m *TradeService) GetByUserAddress(a common.Address) ([]*types.Trade, error) {
+	ret := _m.Called(a)
 
 	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(common.Hash) []*types.Trade); ok {
-		r0 = rf(h)
+	if rf, ok := ret.Get(0).(func(common.Address) []*types.Trade); ok {
+		r0 = rf(a)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*types.Trade)
@@ -164,8 +165,8 @@ func (_m *TradeService) GetByMakerOrderHash(h common.Hash) ([]*types.Trade, erro
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
-		r1 = rf(h)
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(a)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -173,13 +174,13 @@ func (_m *TradeService) GetByMakerOrderHash(h common.Hash) ([]*types.Trade, erro
 	return r0, r1
 }
 
-func (_m *TradeService) GetSortedTradesByUserAddress(a common.Address, limit ...int) ([]*types.Trade, error) {
-
-	ret := _m.Called(a, limit)
+// GetSortedTrades provides a mock function with given fields: bt, qt, from, to, n
+func (_m *TradeService) GetSortedTrades(bt common.Address, qt common.Address, from int64, to int64, n int) ([]*types.Trade, error) {
+	ret := _m.Called(bt, qt, from, to, n)
 
 	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(common.Address, ...int) []*types.Trade); ok {
-		r0 = rf(a, limit...)
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, int64, int64, int) []*types.Trade); ok {
+		r0 = rf(bt, qt, from, to, n)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*types.Trade)
@@ -187,8 +188,8 @@ func (_m *TradeService) GetSortedTradesByUserAddress(a common.Address, limit ...
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address, ...int) error); ok {
-		r1 = rf(a, limit...)
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, int64, int64, int) error); ok {
+		r1 = rf(bt, qt, from, to, n)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -196,12 +197,20 @@ func (_m *TradeService) GetSortedTradesByUserAddress(a common.Address, limit ...
 	return r0, r1
 }
 
-func (_m *TradeService) GetSortedTrades(bt, qt common.Address, n int) ([]*types.Trade, error) {
-	ret := _m.Called(bt, qt)
+// GetSortedTradesByUserAddress provides a mock function with given fields: a, bt, qt, from, to, limit
+func (_m *TradeService) GetSortedTradesByUserAddress(a common.Address, bt common.Address, qt common.Address, from int64, to int64, limit ...int) ([]*types.Trade, error) {
+	_va := make([]interface{}, len(limit))
+	for _i := range limit {
+		_va[_i] = limit[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, a, bt, qt, from, to)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(common.Address, common.Address) []*types.Trade); ok {
-		r0 = rf(bt, qt)
+	if rf, ok := ret.Get(0).(func(common.Address, common.Address, common.Address, int64, int64, ...int) []*types.Trade); ok {
+		r0 = rf(a, bt, qt, from, to, limit...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*types.Trade)
@@ -209,8 +218,8 @@ func (_m *TradeService) GetSortedTrades(bt, qt common.Address, n int) ([]*types.
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
-		r1 = rf(bt, qt)
+	if rf, ok := ret.Get(1).(func(common.Address, common.Address, common.Address, int64, int64, ...int) error); ok {
+		r1 = rf(a, bt, qt, from, to, limit...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -218,21 +227,22 @@ func (_m *TradeService) GetSortedTrades(bt, qt common.Address, n int) ([]*types.
 	return r0, r1
 }
 
-func (_m *TradeService) GetByTakerOrderHash(h common.Hash) ([]*types.Trade, error) {
-	ret := _m.Called(h)
+// GetTrades provides a mock function with given fields: tradeSpec, sortedBy, pageOffset, pageSize
+func (_m *TradeService) GetTrades(tradeSpec *types.TradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.TradeRes, error) {
+	ret := _m.Called(tradeSpec, sortedBy, pageOffset, pageSize)
 
-	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(common.Hash) []*types.Trade); ok {
-		r0 = rf(h)
+	var r0 *types.TradeRes
+	if rf, ok := ret.Get(0).(func(*types.TradeSpec, []string, int, int) *types.TradeRes); ok {
+		r0 = rf(tradeSpec, sortedBy, pageOffset, pageSize)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Trade)
+			r0 = ret.Get(0).(*types.TradeRes)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
-		r1 = rf(h)
+	if rf, ok := ret.Get(1).(func(*types.TradeSpec, []string, int, int) error); ok {
+		r1 = rf(tradeSpec, sortedBy, pageOffset, pageSize)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -240,21 +250,22 @@ func (_m *TradeService) GetByTakerOrderHash(h common.Hash) ([]*types.Trade, erro
 	return r0, r1
 }
 
-func (_m *TradeService) GetAllTradesByPairAddress(bt, qt common.Address) ([]*types.Trade, error) {
-	ret := _m.Called(bt, qt)
+// GetTradesUserHistory provides a mock function with given fields: a, tradeSpec, sortedBy, pageOffset, pageSize
+func (_m *TradeService) GetTradesUserHistory(a common.Address, tradeSpec *types.TradeSpec, sortedBy []string, pageOffset int, pageSize int) (*types.TradeRes, error) {
+	ret := _m.Called(a, tradeSpec, sortedBy, pageOffset, pageSize)
 
-	var r0 []*types.Trade
-	if rf, ok := ret.Get(0).(func(common.Address, common.Address) []*types.Trade); ok {
-		r0 = rf(bt, qt)
+	var r0 *types.TradeRes
+	if rf, ok := ret.Get(0).(func(common.Address, *types.TradeSpec, []string, int, int) *types.TradeRes); ok {
+		r0 = rf(a, tradeSpec, sortedBy, pageOffset, pageSize)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*types.Trade)
+			r0 = ret.Get(0).(*types.TradeRes)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(common.Address, common.Address) error); ok {
-		r1 = rf(bt, qt)
+	if rf, ok := ret.Get(1).(func(common.Address, *types.TradeSpec, []string, int, int) error); ok {
+		r1 = rf(a, tradeSpec, sortedBy, pageOffset, pageSize)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -262,26 +273,22 @@ func (_m *TradeService) GetAllTradesByPairAddress(bt, qt common.Address) ([]*typ
 	return r0, r1
 }
 
-// Subscribe provides a mock function with given fields: conn, bt, qt
-func (_m *TradeService) Unsubscribe(c *ws.Client) {
-	_m.Called(c)
+// RegisterNotify provides a mock function with given fields: fn
+func (_m *TradeService) RegisterNotify(fn func(*types.Trade)) {
+	_m.Called(fn)
 }
 
-// Unsubscribe provides a mock function with given fields: conn, bt, qt
-func (_m *TradeService) UnsubscribeChannel(c *ws.Client, bt, qt common.Address) {
+// Subscribe provides a mock function with given fields: c, bt, qt
+func (_m *TradeService) Subscribe(c *ws.Client, bt common.Address, qt common.Address) {
 	_m.Called(c, bt, qt)
 }
 
-// UpdateTradeTxHash provides a mock function with given fields: tr, txHash
-func (_m *TradeService) UpdateTradeTxHash(tr *types.Trade, txHash common.Hash) error {
-	ret := _m.Called(tr, txHash)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*types.Trade, common.Hash) error); ok {
-		r0 = rf(tr, txHash)
-	} else {
-		r0 = ret.Error(0)
-	}
+// Unsubscribe provides a mock function with given fields: c
+func (_m *TradeService) Unsubscribe(c *ws.Client) {
+	_m.Called(c)
+}
 
-	return r0
+// UnsubscribeChannel provides a mock function with given fields: c, bt, qt
+func (_m *TradeService) UnsubscribeChannel(c *ws.Client, bt common.Address, qt common.Address) {
+	_m.Called(c, bt, qt)
 }
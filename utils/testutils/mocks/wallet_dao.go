@@ -2,11 +2,11 @@
 
 package mocks
 
-import bson "github.com/globalsign/mgo/bson"
 import common "github.com/ethereum/go-ethereum/common"
 
 import mock "github.com/stretchr/testify/mock"
 import types "github.com/tomochain/tomox-sdk/types"
+import primitive "go.mongodb.org/mongo-driver/bson/primitive"
 
 // WalletDao is an autogenerated mock type for the WalletDao type
 type WalletDao struct {
@@ -74,11 +74,11 @@ func (_m *WalletDao) GetByAddress(addr common.Address) (*types.Wallet, error) {
 }
 
 // GetByID provides a mock function with given fields: id
-func (_m *WalletDao) GetByID(id bson.ObjectId) (*types.Wallet, error) {
+func (_m *WalletDao) GetByID(id primitive.ObjectID) (*types.Wallet, error) {
 	ret := _m.Called(id)
 
 	var r0 *types.Wallet
-	if rf, ok := ret.Get(0).(func(bson.ObjectId) *types.Wallet); ok {
+	if rf, ok := ret.Get(0).(func(primitive.ObjectID) *types.Wallet); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
@@ -87,7 +87,7 @@ func (_m *WalletDao) GetByID(id bson.ObjectId) (*types.Wallet, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(bson.ObjectId) error); ok {
+	if rf, ok := ret.Get(1).(func(primitive.ObjectID) error); ok {
 		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
@@ -5,13 +5,13 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/types"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func GetTestOrder1() types.Order {
 	return types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:              objectID("537f700b537461b70c5f0000"),
 		UserAddress:     common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:       common.HexToAddress("0xe41d2489571d322189246dafa5ebde1f4699f498"),
@@ -36,7 +36,7 @@ func GetTestOrder1() types.Order {
 
 func GetTestOrder2() types.Order {
 	return types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:              objectID("537f700b537461b70c5f0000"),
 		UserAddress:     common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:       common.HexToAddress("0x4bc89ac6f1c55ea645294f3fed949813a768ac6d"),
@@ -61,7 +61,7 @@ func GetTestOrder2() types.Order {
 
 func GetTestOrder3() types.Order {
 	return types.Order{
-		ID:              bson.ObjectIdHex("537f700b537461b70c5f0000"),
+		ID:              objectID("537f700b537461b70c5f0000"),
 		UserAddress:     common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		ExchangeAddress: common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:       common.HexToAddress("0x4bc89ac6f1c55ea645294f3fed949813a768ac6d"),
@@ -83,3 +83,9 @@ func GetTestOrder3() types.Order {
 		UpdatedAt: time.Unix(1405544146, 0),
 	}
 }
+
+// objectID returns the ObjectID of a hex string, which is valid in the test data
+func objectID(hex string) primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(hex)
+	return id
+}
@@ -4,13 +4,12 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/globalsign/mgo/bson"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
 func GetTestTrade1() types.Trade {
 	return types.Trade{
-		ID:             bson.ObjectIdHex("537f700b537461b70c5f0001"),
+		ID:             objectID("537f700b537461b70c5f0001"),
 		Maker:          common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		Taker:          common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:      common.HexToAddress("0xa114dd77c888aa2edb699de4faa2afbe4575ffd3"),
@@ -26,7 +25,7 @@ func GetTestTrade1() types.Trade {
 
 func GetTestTrade2() types.Trade {
 	return types.Trade{
-		ID:             bson.ObjectIdHex("537f700b537461b70c5f0007"),
+		ID:             objectID("537f700b537461b70c5f0007"),
 		Maker:          common.HexToAddress("0x7a9f3cd060ab180f36c17fe6bdf9974f577d77aa"),
 		Taker:          common.HexToAddress("0xae55690d4b079460e6ac28aaa58c9ec7b73a7485"),
 		BaseToken:      common.HexToAddress("0xa114dd77c888aa2edb699de4faa2afbe4575ffd3"),
@@ -0,0 +1,79 @@
+package ws
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/errors"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// NewLoginChallenge issues a new nonce for the connection to sign. A nonce can only be used
+// for one login attempt, and is replaced by the next challenge
+func (c *Client) NewLoginChallenge() (*types.LoginChallenge, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, err
+	}
+
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+
+	c.nonce = hex.EncodeToString(b)
+	return &types.LoginChallenge{Nonce: c.nonce}, nil
+}
+
+// Login binds the connection to the address that signed the nonce of its last challenge.
+// A connection can not log in with another address once logged in
+func (c *Client) Login(l *types.Login) error {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+
+	nonce := c.nonce
+	c.nonce = ""
+
+	if nonce == "" || l.Nonce != nonce {
+		return errors.Unauthorized("Invalid login nonce")
+	}
+
+	err := l.VerifySignature()
+	if err != nil {
+		return errors.Unauthorized(err.Error())
+	}
+
+	if c.address != nil && *c.address != l.Address {
+		return errors.Unauthorized("Connection is already logged in with another address")
+	}
+
+	c.address = &l.Address
+	return nil
+}
+
+// Address returns the address the connection is logged in with
+func (c *Client) Address() (common.Address, bool) {
+	c.authLock.Lock()
+	defer c.authLock.Unlock()
+
+	if c.address == nil {
+		return common.Address{}, false
+	}
+
+	return *c.address, true
+}
+
+// authorize returns an error unless the connection is logged in with the address. The
+// private channels only stream the events of an address to its connections
+func (c *Client) authorize(a common.Address) error {
+	addr, ok := c.Address()
+	if !ok {
+		return errors.Unauthorized("Login is required to subscribe to the events of an address")
+	}
+
+	if addr != a {
+		return errors.Unauthorized("Connection is logged in with another address")
+	}
+
+	return nil
+}
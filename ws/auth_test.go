@@ -0,0 +1,45 @@
+package ws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func TestClientLogin(t *testing.T) {
+	c := &Client{}
+	w := types.NewWallet()
+	other := types.NewWallet()
+
+	assert.NotNil(t, c.authorize(w.Address))
+
+	challenge, err := c.NewLoginChallenge()
+	assert.Nil(t, err)
+
+	// signed by another wallet
+	l := &types.Login{Address: w.Address, Nonce: challenge.Nonce}
+	l.Signature, _ = other.SignHash(l.ComputeHash())
+	assert.NotNil(t, c.Login(l))
+
+	// the nonce was used by the failed attempt
+	l.Signature, _ = w.SignHash(l.ComputeHash())
+	assert.NotNil(t, c.Login(l))
+
+	challenge, _ = c.NewLoginChallenge()
+	l = &types.Login{Address: w.Address, Nonce: challenge.Nonce}
+	l.Signature, _ = w.SignHash(l.ComputeHash())
+	assert.Nil(t, c.Login(l))
+
+	addr, ok := c.Address()
+	assert.True(t, ok)
+	assert.Equal(t, w.Address, addr)
+	assert.Nil(t, c.authorize(w.Address))
+	assert.NotNil(t, c.authorize(other.Address))
+
+	// a logged in connection can not switch to another address
+	challenge, _ = c.NewLoginChallenge()
+	l = &types.Login{Address: other.Address, Nonce: challenge.Nonce}
+	l.Signature, _ = other.SignHash(l.ComputeHash())
+	assert.NotNil(t, c.Login(l))
+}
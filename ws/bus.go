@@ -0,0 +1,113 @@
+package ws
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// Bus carries the websocket events between the replicas of the server. When a bus is set, the
+// events are published on it instead of being streamed to the clients, and every replica, the
+// publishing one included, streams the events it receives to the clients connected to it. The
+// events must be received in the order they are published, as the orderbook updates are
+// numbered by each replica when it streams them
+type Bus interface {
+	PublishWebsocketEvent(m *types.BusMessage) error
+	SubscribeWebsocketEvents(fn func(m *types.BusMessage)) error
+}
+
+var bus Bus
+
+// SetBus subscribes to the events of a bus, and publishes the events on it from now on
+func SetBus(b Bus) error {
+	err := b.SubscribeWebsocketEvents(deliver)
+	if err != nil {
+		return err
+	}
+
+	bus = b
+	return nil
+}
+
+// publish publishes an event on the bus, and tells whether it was published. The event is
+// streamed to the clients of this replica only if there is no bus or if it can not be published
+func publish(channel string, id string, msgType types.SubscriptionEvent, payload interface{}) bool {
+	if bus == nil {
+		return false
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		logger.Error(err)
+		return false
+	}
+
+	err = bus.PublishWebsocketEvent(&types.BusMessage{
+		Channel: channel,
+		ID:      id,
+		Type:    msgType,
+		Payload: b,
+	})
+
+	if err != nil {
+		logger.Error(err)
+		return false
+	}
+
+	return true
+}
+
+// deliver streams an event received from the bus to the clients connected to this replica
+func deliver(m *types.BusMessage) {
+	switch m.Channel {
+	case OrderBookChannel:
+		ob := &types.OrderBook{}
+		if err := json.Unmarshal(m.Payload, ob); err != nil {
+			logger.Error(err)
+			return
+		}
+
+		GetOrderBookSocket().broadcastUpdate(m.ID, ob)
+	case RawOrderBookChannel:
+		ob := &types.RawOrderBook{}
+		if err := json.Unmarshal(m.Payload, ob); err != nil {
+			logger.Error(err)
+			return
+		}
+
+		GetRawOrderBookSocket().broadcastUpdate(m.ID, ob)
+	case LendingOrderBookChannel:
+		ob := &types.LendingOrderBook{}
+		if err := json.Unmarshal(m.Payload, ob); err != nil {
+			logger.Error(err)
+			return
+		}
+
+		GetLendingOrderBookSocket().broadcastUpdate(m.ID, ob)
+	case TradeChannel:
+		GetTradeSocket().broadcastMessage(m.ID, m.Payload)
+	case LendingTradeChannel:
+		GetLendingTradeSocket().broadcastMessage(m.ID, m.Payload)
+	case OHLCVChannel:
+		GetOHLCVSocket().broadcastOHLCV(m.ID, m.Payload)
+	case LendingOhlcvChannel:
+		GetLendingOhlcvSocket().broadcastLendingOhlcv(m.ID, m.Payload)
+	case PriceBoardChannel:
+		GetPriceBoardSocket().broadcastMessage(m.ID, m.Payload)
+	case LendingPriceBoardChannel:
+		GetLendingPriceBoardSocket().broadcastMessage(m.ID, m.Payload)
+	case MarketsChannel:
+		GetMarketSocket().broadcastMessage(m.ID, m.Payload)
+	case LendingMarketsChannel:
+		GetLendingMarketSocket().broadcastMessage(m.ID, m.Payload)
+	case OrderChannel:
+		sendOrderMessage(m.Type, common.HexToAddress(m.ID), m.Payload)
+	case LendingOrderChannel:
+		sendLendingOrderMessage(m.Type, common.HexToAddress(m.ID), m.Payload)
+	case NotificationChannel:
+		sendNotificationMessage(m.Type, common.HexToAddress(m.ID), m.Payload)
+	default:
+		logger.Errorf("Unknown websocket bus channel: %s", m.Channel)
+	}
+}
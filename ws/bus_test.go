@@ -0,0 +1,102 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+// testBus delivers the published events at once, through their JSON encoding as RabbitMQ does
+type testBus struct {
+	published []*types.BusMessage
+	fn        func(m *types.BusMessage)
+}
+
+func (b *testBus) PublishWebsocketEvent(m *types.BusMessage) error {
+	b.published = append(b.published, m)
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	received := &types.BusMessage{}
+	err = json.Unmarshal(data, received)
+	if err != nil {
+		return err
+	}
+
+	b.fn(received)
+	return nil
+}
+
+func (b *testBus) SubscribeWebsocketEvents(fn func(m *types.BusMessage)) error {
+	b.fn = fn
+	return nil
+}
+
+func newTestClient() *Client {
+	c := NewClient(nil)
+	c.send = make(chan types.WebsocketMessage, 10)
+	return c
+}
+
+func TestBusBroadcastUpdate(t *testing.T) {
+	b := &testBus{}
+	err := SetBus(b)
+	assert.Nil(t, err)
+	defer func() { bus = nil }()
+
+	c := newTestClient()
+	socket := GetOrderBookSocket()
+	socket.Subscribe("BUS/TOMO", c)
+
+	socket.BroadcastUpdate("BUS/TOMO", &types.OrderBook{
+		PairName: "BUS/TOMO",
+		Bids:     []map[string]string{{"pricepoint": "100", "amount": "1"}},
+	})
+
+	assert.Equal(t, 1, len(b.published))
+	assert.Equal(t, OrderBookChannel, b.published[0].Channel)
+	assert.Equal(t, "BUS/TOMO", b.published[0].ID)
+
+	// the update is numbered by the replica that streams it
+	m := <-c.send
+	assert.Equal(t, OrderBookChannel, m.Channel)
+	assert.Equal(t, types.UPDATE, m.Event.Type)
+
+	ob := m.Event.Payload.(*types.OrderBook)
+	assert.Equal(t, uint64(1), ob.Sequence)
+	assert.Equal(t, []map[string]string{{"pricepoint": "100", "amount": "1"}}, ob.Bids)
+	assert.Equal(t, uint64(1), socket.Sequence("BUS/TOMO"))
+}
+
+func TestBusSendOrderMessage(t *testing.T) {
+	b := &testBus{}
+	err := SetBus(b)
+	assert.Nil(t, err)
+	defer func() { bus = nil }()
+
+	a := common.HexToAddress("0x1e7bb4d1e0a3d4e6c5e8d4f0a3c5b7e9d1f3a5c7")
+	c := newTestClient()
+	c.address = &a
+
+	err = RegisterOrderConnection(a, c)
+	assert.Nil(t, err)
+
+	SendOrderMessage(types.ORDER_FILLED, a, map[string]string{"hash": "0x1"})
+
+	assert.Equal(t, 1, len(b.published))
+	assert.Equal(t, a.Hex(), b.published[0].ID)
+
+	m := <-c.send
+	assert.Equal(t, OrderChannel, m.Channel)
+	assert.Equal(t, types.SubscriptionEvent(types.ORDER_FILLED), m.Event.Type)
+
+	payload, err := json.Marshal(m.Event.Payload)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"hash": "0x1"}`, string(payload))
+}
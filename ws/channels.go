@@ -17,6 +17,7 @@ const (
 	DepositChannel      = "deposit"
 	MarketsChannel      = "markets"
 	NotificationChannel = "notification"
+	AuthChannel         = "auth"
 
 	// Lending channel
 	LendingOrderChannel        = "lending_orders"
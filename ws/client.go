@@ -1,10 +1,12 @@
 package ws
 
 import (
+	"context"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/websocket"
+	"github.com/tomochain/tomox-sdk/errors"
 	"github.com/tomochain/tomox-sdk/types"
 )
 
@@ -12,12 +14,23 @@ type Client struct {
 	*websocket.Conn
 	mu   sync.Mutex
 	send chan types.WebsocketMessage
+	ip   string
+
+	// authLock protects the login nonce and the address the connection is logged in with
+	authLock sync.Mutex
+	nonce    string
+	address  *common.Address
+
+	// ctx is done once the connection is closed
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 var unsubscribeHandlers map[*Client][]func(*Client)
 
 func NewClient(c *websocket.Conn) *Client {
 	conn := &Client{Conn: c, mu: sync.Mutex{}, send: make(chan types.WebsocketMessage)}
+	conn.ctx, conn.cancel = context.WithCancel(context.Background())
 
 	if unsubscribeHandlers == nil {
 		unsubscribeHandlers = make(map[*Client][]func(*Client))
@@ -51,11 +64,18 @@ func (c *Client) SendMessage(channel string, msgType types.SubscriptionEvent, pa
 	c.send <- m
 }
 
+// Context returns a context which is done once the connection is closed, for the operations
+// run in the background on behalf of the client
+func (c *Client) Context() context.Context {
+	return c.ctx
+}
+
 func (c *Client) closeConnection() {
 	for _, unsub := range unsubscribeHandlers[c] {
 		go unsub(c)
 	}
 
+	c.cancel()
 	c.Close()
 }
 
@@ -65,6 +85,13 @@ func (c *Client) SendOrderErrorMessage(err error, h common.Hash) {
 		"hash":    h.Hex(),
 	}
 
+	if apiErr, ok := err.(*errors.APIError); ok {
+		p["code"] = apiErr.ErrorCode
+		if apiErr.Details != nil {
+			p["details"] = apiErr.Details
+		}
+	}
+
 	e := types.WebsocketEvent{
 		Type:    "ERROR",
 		Payload: p,
@@ -87,6 +114,10 @@ func (c *Client) SendLendingOrderErrorMessage(err error, h common.Hash) {
 		"hash":    h.Hex(),
 	}
 
+	if apiErr, ok := err.(*errors.APIError); ok {
+		p["code"] = apiErr.ErrorCode
+	}
+
 	e := types.WebsocketEvent{
 		Type:    "ERROR",
 		Payload: p,
@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/tomochain/tomox-sdk/app"
 	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/ratelimit"
 )
 
 const (
@@ -37,6 +38,7 @@ func ConnectionEndpoint(w http.ResponseWriter, r *http.Request) {
 	}
 
 	c := NewClient(conn)
+	c.ip = remoteIP(r)
 	c.SetCloseHandler(closeHandler(c))
 
 	go readHandler(c)
@@ -83,6 +85,14 @@ func readHandler(c *Client) {
 
 		logger.Infof("%v", msg.String())
 
+		if ok, retry := c.allowMessage(); !ok {
+			c.SendMessage(msg.Channel, types.ERROR, map[string]interface{}{
+				"message":    "Too many requests",
+				"retryAfter": ratelimit.RetryAfter(retry),
+			})
+			continue
+		}
+
 		if socketChannels[msg.Channel] == nil {
 			c.SendMessage(msg.Channel, types.ERROR, "INVALID_CHANNEL")
 			return
@@ -49,7 +49,13 @@ func DepositSocketUnsubscribeHandler(a common.Address) func(client *Client) {
 
 // RegisterDepositConnection registers a connection with and depositID.
 // It is called whenever a message is recieved over deposit channel
-func RegisterDepositConnection(a common.Address, c *Client) {
+// It returns an error unless the connection is logged in with the address
+func RegisterDepositConnection(a common.Address, c *Client) error {
+	err := c.authorize(a)
+	if err != nil {
+		return err
+	}
+
 	logger.Info("Registering new deposit connection")
 
 	if depositConnections == nil {
@@ -71,6 +77,8 @@ func RegisterDepositConnection(a common.Address, c *Client) {
 			logger.Info("Number of connections for this address: %v", len(depositConnections))
 		}
 	}
+
+	return nil
 }
 
 func SendDepositMessage(msgType types.SubscriptionEvent, a common.Address, payload interface{}) {
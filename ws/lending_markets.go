@@ -93,6 +93,14 @@ func (s *LendingMarketsSocket) Unsubscribe(c *Client) {
 
 // BroadcastMessage streams message to all the subscriptions subscribed to the pair
 func (s *LendingMarketsSocket) BroadcastMessage(channelID string, p interface{}) error {
+	if publish(LendingMarketsChannel, channelID, types.UPDATE, p) {
+		return nil
+	}
+
+	return s.broadcastMessage(channelID, p)
+}
+
+func (s *LendingMarketsSocket) broadcastMessage(channelID string, p interface{}) error {
 
 	for c, status := range s.subscriptions[channelID] {
 		if status {
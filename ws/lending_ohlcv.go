@@ -98,6 +98,14 @@ func (s *LendingOhlcvSocket) Unsubscribe(c *Client) {
 
 // BroadcastLendingOhlcv Message streams message to all the subscriptions subscribed to the pair
 func (s *LendingOhlcvSocket) BroadcastLendingOhlcv(channelID string, p interface{}) error {
+	if publish(LendingOhlcvChannel, channelID, types.UPDATE, p) {
+		return nil
+	}
+
+	return s.broadcastLendingOhlcv(channelID, p)
+}
+
+func (s *LendingOhlcvSocket) broadcastLendingOhlcv(channelID string, p interface{}) error {
 	for c, status := range s.subscriptions[channelID] {
 		if status {
 			s.SendUpdateMessage(c, p)
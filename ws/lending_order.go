@@ -53,7 +53,13 @@ func LendingOrderSocketUnsubscribeHandler(a common.Address) func(client *Client)
 
 // RegisterLendingOrderConnection registers a connection with and orderID.
 // It is called whenever a message is recieved over order channel
-func RegisterLendingOrderConnection(a common.Address, c *Client) {
+// It returns an error unless the connection is logged in with the address
+func RegisterLendingOrderConnection(a common.Address, c *Client) error {
+	err := c.authorize(a)
+	if err != nil {
+		return err
+	}
+
 	logger.Info("Registering new order connection")
 
 	if lendingOrderConnections == nil {
@@ -78,10 +84,20 @@ func RegisterLendingOrderConnection(a common.Address, c *Client) {
 			logger.Info("Number of connections for this address: %v", len(lendingOrderConnections))
 		}
 	}
+
+	return nil
 }
 
 // SendLendingOrderMessage send lending order message
 func SendLendingOrderMessage(msgType types.SubscriptionEvent, a common.Address, payload interface{}) {
+	if publish(LendingOrderChannel, a.Hex(), msgType, payload) {
+		return
+	}
+
+	sendLendingOrderMessage(msgType, a, payload)
+}
+
+func sendLendingOrderMessage(msgType types.SubscriptionEvent, a common.Address, payload interface{}) {
 	conn := GetLendingOrderConnections(a)
 	if conn == nil {
 		return
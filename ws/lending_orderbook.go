@@ -15,6 +15,7 @@ var lendingOrderbookSocket *LendingOrderBookSocket
 type LendingOrderBookSocket struct {
 	subscriptions     map[string]map[*Client]bool
 	subscriptionsList map[*Client][]string
+	sequences         *channelSequences
 }
 
 // NewLendingOrderBookSocket new lending order book instance
@@ -22,6 +23,7 @@ func NewLendingOrderBookSocket() *LendingOrderBookSocket {
 	return &LendingOrderBookSocket{
 		subscriptions:     make(map[string]map[*Client]bool),
 		subscriptionsList: make(map[*Client][]string),
+		sequences:         newChannelSequences(),
 	}
 }
 
@@ -110,6 +112,27 @@ func (s *LendingOrderBookSocket) SendMessage(c *Client, msgType types.Subscripti
 	c.SendMessage(LendingOrderBookChannel, msgType, p)
 }
 
+// BroadcastUpdate numbers a lending orderbook update with the next sequence of the channel and streams it
+func (s *LendingOrderBookSocket) BroadcastUpdate(channelID string, ob *types.LendingOrderBook) {
+	if publish(LendingOrderBookChannel, channelID, types.UPDATE, ob) {
+		return
+	}
+
+	s.broadcastUpdate(channelID, ob)
+}
+
+func (s *LendingOrderBookSocket) broadcastUpdate(channelID string, ob *types.LendingOrderBook) {
+	s.sequences.next(channelID, func(sequence uint64) {
+		ob.Sequence = sequence
+		s.BroadcastMessage(channelID, ob)
+	})
+}
+
+// Sequence returns the sequence of the last update streamed on a channel
+func (s *LendingOrderBookSocket) Sequence(channelID string) uint64 {
+	return s.sequences.current(channelID)
+}
+
 // SendInitMessage sends INIT message on orderbook channel on subscription event
 func (s *LendingOrderBookSocket) SendInitMessage(c *Client, data interface{}) {
 	c.SendMessage(LendingOrderBookChannel, types.INIT, data)
@@ -91,6 +91,14 @@ func (s *LendingPriceBoardSocket) Unsubscribe(c *Client) {
 
 // BroadcastMessage streams message to all the subscriptions subscribed to the pair
 func (s *LendingPriceBoardSocket) BroadcastMessage(channelID string, p interface{}) error {
+	if publish(LendingPriceBoardChannel, channelID, types.UPDATE, p) {
+		return nil
+	}
+
+	return s.broadcastMessage(channelID, p)
+}
+
+func (s *LendingPriceBoardSocket) broadcastMessage(channelID string, p interface{}) error {
 
 	for c, status := range s.subscriptions[channelID] {
 		if status {
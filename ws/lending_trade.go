@@ -94,6 +94,14 @@ func (s *LendingTradeSocket) Unsubscribe(c *Client) {
 
 // BroadcastMessage broadcasts trade message to all subscribed sockets
 func (s *LendingTradeSocket) BroadcastMessage(channelID string, p interface{}) {
+	if publish(LendingTradeChannel, channelID, types.UPDATE, p) {
+		return
+	}
+
+	s.broadcastMessage(channelID, p)
+}
+
+func (s *LendingTradeSocket) broadcastMessage(channelID string, p interface{}) {
 	go func() {
 		for conn, active := range lendingTradeSocket.subscriptions[channelID] {
 			if active {
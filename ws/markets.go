@@ -91,6 +91,14 @@ func (s *MarketsSocket) Unsubscribe(c *Client) {
 
 // BroadcastMessage streams message to all the subscriptions subscribed to the pair
 func (s *MarketsSocket) BroadcastMessage(channelID string, p interface{}) error {
+	if publish(MarketsChannel, channelID, types.UPDATE, p) {
+		return nil
+	}
+
+	return s.broadcastMessage(channelID, p)
+}
+
+func (s *MarketsSocket) broadcastMessage(channelID string, p interface{}) error {
 
 	for c, status := range s.subscriptions[channelID] {
 		if status {
@@ -55,7 +55,13 @@ func NotificationSocketUnsubscribeHandler(a common.Address) func(client *Client)
 
 // RegisterNotificationConnection registers a connection with an user address
 // It is called whenever a message is received over notification channel
-func RegisterNotificationConnection(a common.Address, c *Client) {
+// It returns an error unless the connection is logged in with the address
+func RegisterNotificationConnection(a common.Address, c *Client) error {
+	err := c.authorize(a)
+	if err != nil {
+		return err
+	}
+
 	logger.Info("Registering new notification connection")
 
 	if notificationConnections == nil {
@@ -80,9 +86,19 @@ func RegisterNotificationConnection(a common.Address, c *Client) {
 			logger.Info("Number of connections for this address: %v", len(notificationConnections))
 		}
 	}
+
+	return nil
 }
 
 func SendNotificationMessage(msgType types.SubscriptionEvent, a common.Address, payload interface{}) {
+	if publish(NotificationChannel, a.Hex(), msgType, payload) {
+		return
+	}
+
+	sendNotificationMessage(msgType, a, payload)
+}
+
+func sendNotificationMessage(msgType types.SubscriptionEvent, a common.Address, payload interface{}) {
 	conn := GetNotificationConnections(a)
 	if conn == nil {
 		return
@@ -95,6 +95,14 @@ func (s *OHLCVSocket) Unsubscribe(c *Client) {
 
 // BroadcastOHLCV Message streams message to all the subscriptions subscribed to the pair
 func (s *OHLCVSocket) BroadcastOHLCV(channelID string, p interface{}) error {
+	if publish(OHLCVChannel, channelID, types.UPDATE, p) {
+		return nil
+	}
+
+	return s.broadcastOHLCV(channelID, p)
+}
+
+func (s *OHLCVSocket) broadcastOHLCV(channelID string, p interface{}) error {
 	for c, status := range s.subscriptions[channelID] {
 		if status {
 			s.SendUpdateMessage(c, p)
@@ -15,12 +15,16 @@ var orderbookSocket *OrderBookSocket
 type OrderBookSocket struct {
 	subscriptions     map[string]map[*Client]bool
 	subscriptionsList map[*Client][]string
+	sequences         *channelSequences
+	aggregations      map[string]*orderBookAggregation
 }
 
 func NewOrderBookSocket() *OrderBookSocket {
 	return &OrderBookSocket{
 		subscriptions:     make(map[string]map[*Client]bool),
 		subscriptionsList: make(map[*Client][]string),
+		sequences:         newChannelSequences(),
+		aggregations:      make(map[string]*orderBookAggregation),
 	}
 }
 
@@ -77,6 +81,8 @@ func (s *OrderBookSocket) UnsubscribeChannel(channelID string, c *Client) {
 		delete(s.subscriptions[channelID], c)
 	}
 	lockOderbook.Unlock()
+
+	s.unsubscribeAggregated(channelID, c)
 }
 
 func (s *OrderBookSocket) Unsubscribe(c *Client) {
@@ -107,6 +113,33 @@ func (s *OrderBookSocket) SendMessage(c *Client, msgType types.SubscriptionEvent
 	c.SendMessage(OrderBookChannel, msgType, p)
 }
 
+// BroadcastUpdate numbers an orderbook update with the next sequence of the channel and streams it
+func (s *OrderBookSocket) BroadcastUpdate(channelID string, ob *types.OrderBook) {
+	if publish(OrderBookChannel, channelID, types.UPDATE, ob) {
+		return
+	}
+
+	s.broadcastUpdate(channelID, ob)
+}
+
+func (s *OrderBookSocket) broadcastUpdate(channelID string, ob *types.OrderBook) {
+	s.sequences.next(channelID, func(sequence uint64) {
+		ob.Sequence = sequence
+
+		if a := s.aggregations[channelID]; a != nil {
+			s.broadcastAggregated(channelID, a, ob)
+			return
+		}
+
+		s.BroadcastMessage(channelID, ob)
+	})
+}
+
+// Sequence returns the sequence of the last update streamed on a channel
+func (s *OrderBookSocket) Sequence(channelID string) uint64 {
+	return s.sequences.current(channelID)
+}
+
 // SendInitMessage sends INIT message on orderbook channel on subscription event
 func (s *OrderBookSocket) SendInitMessage(c *Client, data interface{}) {
 	c.SendMessage(OrderBookChannel, types.INIT, data)
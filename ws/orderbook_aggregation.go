@@ -0,0 +1,216 @@
+package ws
+
+import (
+	"math/big"
+
+	"github.com/tomochain/tomox-sdk/types"
+	"github.com/tomochain/tomox-sdk/utils/math"
+)
+
+// orderBookAggregation holds the levels of an orderbook channel and the grouped orderbooks
+// last sent to the connections that subscribed to the channel with a depth or a grouping.
+// The levels are fetched when the first of these connections subscribes, and are then kept
+// up to date by the updates streamed on the channel
+type orderBookAggregation struct {
+	pairName string
+	bids     map[string]*big.Int
+	asks     map[string]*big.Int
+	views    map[*Client]*orderBookView
+}
+
+// orderBookView is the grouped orderbook of a connection
+type orderBookView struct {
+	step  *big.Int
+	depth int
+	bids  []map[string]string
+	asks  []map[string]string
+}
+
+func newOrderBookAggregation() *orderBookAggregation {
+	return &orderBookAggregation{views: make(map[*Client]*orderBookView)}
+}
+
+// seed replaces the levels of the aggregation with the levels of an orderbook snapshot
+func (a *orderBookAggregation) seed(ob *types.OrderBook) {
+	a.pairName = ob.PairName
+	a.bids = make(map[string]*big.Int)
+	a.asks = make(map[string]*big.Int)
+	a.apply(ob)
+}
+
+// apply sets the amounts of the levels of an orderbook update. A level with an amount
+// of 0 has been emptied
+func (a *orderBookAggregation) apply(ob *types.OrderBook) {
+	applyLevels(a.bids, ob.Bids)
+	applyLevels(a.asks, ob.Asks)
+}
+
+func applyLevels(amounts map[string]*big.Int, levels []map[string]string) {
+	for _, l := range levels {
+		amount := math.ToBigInt(l["amount"])
+		if amount.Sign() <= 0 {
+			delete(amounts, l["pricepoint"])
+			continue
+		}
+
+		amounts[l["pricepoint"]] = amount
+	}
+}
+
+// snapshot computes the grouped orderbook of a view and stores it as the last orderbook
+// sent to the connection
+func (a *orderBookAggregation) snapshot(v *orderBookView, sequence uint64) *types.OrderBook {
+	v.bids = types.AggregateOrderBookLevels(levelsOf(a.bids), types.BUY, v.step, v.depth)
+	v.asks = types.AggregateOrderBookLevels(levelsOf(a.asks), types.SELL, v.step, v.depth)
+
+	return &types.OrderBook{
+		PairName: a.pairName,
+		Bids:     v.bids,
+		Asks:     v.asks,
+		Sequence: sequence,
+	}
+}
+
+// update computes the grouped orderbook of a view and returns the levels that changed since
+// the last orderbook sent to the connection. A level that left the view is sent with an
+// amount of 0
+func (a *orderBookAggregation) update(v *orderBookView, sequence uint64) *types.OrderBook {
+	lastBids, lastAsks := v.bids, v.asks
+	a.snapshot(v, sequence)
+
+	return &types.OrderBook{
+		PairName: a.pairName,
+		Bids:     diffLevels(lastBids, v.bids, types.BUY),
+		Asks:     diffLevels(lastAsks, v.asks, types.SELL),
+		Sequence: sequence,
+	}
+}
+
+func levelsOf(amounts map[string]*big.Int) []map[string]string {
+	levels := []map[string]string{}
+	for pricepoint, amount := range amounts {
+		levels = append(levels, map[string]string{
+			"pricepoint": pricepoint,
+			"amount":     amount.String(),
+		})
+	}
+
+	return levels
+}
+
+func diffLevels(last, next []map[string]string, side string) []map[string]string {
+	changes := make(map[string]*big.Int)
+	for _, l := range last {
+		changes[l["pricepoint"]] = big.NewInt(0)
+	}
+
+	for _, l := range next {
+		changes[l["pricepoint"]] = math.ToBigInt(l["amount"])
+	}
+
+	for _, l := range last {
+		if changes[l["pricepoint"]].Cmp(math.ToBigInt(l["amount"])) == 0 {
+			delete(changes, l["pricepoint"])
+		}
+	}
+
+	return types.SortOrderBookLevels(changes, side, 0)
+}
+
+// SubscribeAggregated subscribes a connection to an orderbook channel grouped by price step
+// and cut to depth, and sends it the grouped orderbook. fetch is only called to get the
+// levels of the channel when no connection of the channel is grouped yet. No update is
+// streamed on the socket meanwhile, so that the connection misses none of them
+func (s *OrderBookSocket) SubscribeAggregated(channelID string, c *Client, step *big.Int, depth int, fetch func() (*types.OrderBook, error)) error {
+	var err error
+
+	s.sequences.with(channelID, func(sequence uint64) {
+		a := s.aggregations[channelID]
+		if a == nil {
+			var ob *types.OrderBook
+			ob, err = fetch()
+			if err != nil {
+				return
+			}
+
+			a = newOrderBookAggregation()
+			a.seed(ob)
+			s.aggregations[channelID] = a
+		}
+
+		err = s.Subscribe(channelID, c)
+		if err != nil {
+			return
+		}
+
+		v := &orderBookView{step: step, depth: depth}
+		a.views[c] = v
+		s.SendInitMessage(c, a.snapshot(v, sequence))
+	})
+
+	return err
+}
+
+// ResyncAggregated fetches the levels of an orderbook channel again and sends its grouped
+// orderbook to a connection that missed an update. It returns false if the connection
+// is not grouped on the channel
+func (s *OrderBookSocket) ResyncAggregated(channelID string, c *Client, fetch func() (*types.OrderBook, error)) (bool, error) {
+	var found bool
+	var err error
+
+	s.sequences.with(channelID, func(sequence uint64) {
+		a := s.aggregations[channelID]
+		if a == nil || a.views[c] == nil {
+			return
+		}
+
+		found = true
+
+		var ob *types.OrderBook
+		ob, err = fetch()
+		if err != nil {
+			return
+		}
+
+		a.seed(ob)
+		s.SendInitMessage(c, a.snapshot(a.views[c], sequence))
+	})
+
+	return found, err
+}
+
+// unsubscribeAggregated removes the grouped orderbook of a connection, and drops the levels
+// of the channel once no connection is grouped on it
+func (s *OrderBookSocket) unsubscribeAggregated(channelID string, c *Client) {
+	s.sequences.with(channelID, func(sequence uint64) {
+		a := s.aggregations[channelID]
+		if a == nil {
+			return
+		}
+
+		delete(a.views, c)
+		if len(a.views) == 0 {
+			delete(s.aggregations, channelID)
+		}
+	})
+}
+
+// broadcastAggregated applies an update to the levels of a channel and streams it. Grouped
+// connections receive the changes of their grouped orderbook, with the sequence of the update
+// even when nothing changed, and the other connections receive the update
+func (s *OrderBookSocket) broadcastAggregated(channelID string, a *orderBookAggregation, ob *types.OrderBook) {
+	a.apply(ob)
+
+	for c, status := range s.subscriptions[channelID] {
+		if !status {
+			continue
+		}
+
+		if v, ok := a.views[c]; ok {
+			s.SendUpdateMessage(c, a.update(v, ob.Sequence))
+			continue
+		}
+
+		s.SendUpdateMessage(c, ob)
+	}
+}
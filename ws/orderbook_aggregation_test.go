@@ -0,0 +1,41 @@
+package ws
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tomochain/tomox-sdk/types"
+)
+
+func TestOrderBookAggregationUpdate(t *testing.T) {
+	a := newOrderBookAggregation()
+	a.seed(&types.OrderBook{
+		PairName: "ZRX/TOMO",
+		Bids: []map[string]string{
+			{"pricepoint": "1012", "amount": "1"},
+			{"pricepoint": "1019", "amount": "2"},
+			{"pricepoint": "1005", "amount": "5"},
+		},
+	})
+
+	v := &orderBookView{step: big.NewInt(10), depth: 1}
+	ob := a.snapshot(v, 3)
+	assert.Equal(t, []map[string]string{{"pricepoint": "1010", "amount": "3"}}, ob.Bids)
+	assert.Equal(t, uint64(3), ob.Sequence)
+
+	a.apply(&types.OrderBook{Bids: []map[string]string{{"pricepoint": "1019", "amount": "0"}}})
+	ob = a.update(v, 4)
+	assert.Equal(t, []map[string]string{{"pricepoint": "1010", "amount": "1"}}, ob.Bids)
+
+	a.apply(&types.OrderBook{Bids: []map[string]string{{"pricepoint": "1012", "amount": "0"}}})
+	ob = a.update(v, 5)
+	assert.Equal(t, []map[string]string{
+		{"pricepoint": "1010", "amount": "0"},
+		{"pricepoint": "1000", "amount": "5"},
+	}, ob.Bids)
+
+	ob = a.update(v, 6)
+	assert.Equal(t, []map[string]string{}, ob.Bids)
+	assert.Equal(t, uint64(6), ob.Sequence)
+}
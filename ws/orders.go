@@ -55,7 +55,13 @@ func OrderSocketUnsubscribeHandler(a common.Address) func(client *Client) {
 
 // RegisterOrderConnection registers a connection with and orderID.
 // It is called whenever a message is recieved over order channel
-func RegisterOrderConnection(a common.Address, c *Client) {
+// It returns an error unless the connection is logged in with the address
+func RegisterOrderConnection(a common.Address, c *Client) error {
+	err := c.authorize(a)
+	if err != nil {
+		return err
+	}
+
 	logger.Info("Registering new order connection")
 
 	if orderConnections == nil {
@@ -80,9 +86,19 @@ func RegisterOrderConnection(a common.Address, c *Client) {
 			logger.Info("Number of connections for this address: %v", len(orderConnections))
 		}
 	}
+
+	return nil
 }
 
 func SendOrderMessage(msgType types.SubscriptionEvent, a common.Address, payload interface{}) {
+	if publish(OrderChannel, a.Hex(), msgType, payload) {
+		return
+	}
+
+	sendOrderMessage(msgType, a, payload)
+}
+
+func sendOrderMessage(msgType types.SubscriptionEvent, a common.Address, payload interface{}) {
 	conn := GetOrderConnections(a)
 	if conn == nil {
 		return
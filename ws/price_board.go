@@ -91,6 +91,14 @@ func (s *PriceBoardSocket) Unsubscribe(c *Client) {
 
 // BroadcastMessage streams message to all the subscriptions subscribed to the pair
 func (s *PriceBoardSocket) BroadcastMessage(channelID string, p interface{}) error {
+	if publish(PriceBoardChannel, channelID, types.UPDATE, p) {
+		return nil
+	}
+
+	return s.broadcastMessage(channelID, p)
+}
+
+func (s *PriceBoardSocket) broadcastMessage(channelID string, p interface{}) error {
 
 	for c, status := range s.subscriptions[channelID] {
 		if status {
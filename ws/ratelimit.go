@@ -0,0 +1,45 @@
+package ws
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/tomochain/tomox-sdk/utils/ratelimit"
+)
+
+var limiter *ratelimit.Limiter
+
+// SetRateLimiter sets the limiter of the messages of the websocket connections. The messages
+// are not limited if it is not set
+func SetRateLimiter(l *ratelimit.Limiter) {
+	limiter = l
+}
+
+// allowMessage takes a token for a message of the client in the websocket group, for its IP
+// and for its address once it is logged in
+func (c *Client) allowMessage() (bool, time.Duration) {
+	if limiter == nil {
+		return true, 0
+	}
+
+	if ok, retry := limiter.AllowIP(ratelimit.GroupWebsocket, c.ip); !ok {
+		return false, retry
+	}
+
+	if addr, ok := c.Address(); ok {
+		return limiter.AllowAddress(ratelimit.GroupWebsocket, addr)
+	}
+
+	return true, 0
+}
+
+// remoteIP returns the IP a websocket connection is opened from
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
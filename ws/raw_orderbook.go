@@ -12,12 +12,14 @@ var rawOrderBookSocket *RawOrderBookSocket
 type RawOrderBookSocket struct {
 	subscriptions     map[string]map[*Client]bool
 	subscriptionsList map[*Client][]string
+	sequences         *channelSequences
 }
 
 func NewRawOrderBookSocket() *RawOrderBookSocket {
 	return &RawOrderBookSocket{
 		subscriptions:     make(map[string]map[*Client]bool),
 		subscriptionsList: make(map[*Client][]string),
+		sequences:         newChannelSequences(),
 	}
 }
 
@@ -99,6 +101,27 @@ func (s *RawOrderBookSocket) BroadcastMessage(channelID string, p interface{}) e
 	return nil
 }
 
+// BroadcastUpdate numbers a raw orderbook update with the next sequence of the channel and streams it
+func (s *RawOrderBookSocket) BroadcastUpdate(channelID string, ob *types.RawOrderBook) {
+	if publish(RawOrderBookChannel, channelID, types.UPDATE, ob) {
+		return
+	}
+
+	s.broadcastUpdate(channelID, ob)
+}
+
+func (s *RawOrderBookSocket) broadcastUpdate(channelID string, ob *types.RawOrderBook) {
+	s.sequences.next(channelID, func(sequence uint64) {
+		ob.Sequence = sequence
+		s.BroadcastMessage(channelID, ob)
+	})
+}
+
+// Sequence returns the sequence of the last update streamed on a channel
+func (s *RawOrderBookSocket) Sequence(channelID string) uint64 {
+	return s.sequences.current(channelID)
+}
+
 // SendInitMessage sends INIT message on orderbookchannel on subscription event
 func (s *RawOrderBookSocket) SendInitMessage(c *Client, data interface{}) {
 	c.SendMessage(RawOrderBookChannel, types.INIT, data)
@@ -0,0 +1,45 @@
+package ws
+
+import (
+	"sync"
+)
+
+// channelSequences numbers the updates streamed on the channels of a socket. The sequence
+// of a channel starts at 1 and increases by one with every update, so that a client can
+// detect a missed update and request a new snapshot. Sequences are kept in memory and
+// start again from 1 when the server restarts
+type channelSequences struct {
+	mutex     sync.Mutex
+	sequences map[string]uint64
+}
+
+func newChannelSequences() *channelSequences {
+	return &channelSequences{sequences: make(map[string]uint64)}
+}
+
+// current returns the sequence of the last update streamed on a channel
+func (s *channelSequences) current(channelID string) uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.sequences[channelID]
+}
+
+// next calls send with the next sequence of a channel. Updates are sent one at a time,
+// so that they reach the clients in the order of their sequence
+func (s *channelSequences) next(channelID string, send func(sequence uint64)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.sequences[channelID]++
+	send(s.sequences[channelID])
+}
+
+// with calls fn with the sequence of the last update streamed on a channel. No update is
+// streamed on the socket while fn runs
+func (s *channelSequences) with(channelID string, fn func(sequence uint64)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	fn(s.sequences[channelID])
+}
@@ -0,0 +1,25 @@
+package ws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelSequences(t *testing.T) {
+	s := newChannelSequences()
+	assert.Equal(t, uint64(0), s.current("ZRX/TOMO"))
+
+	sent := []uint64{}
+	send := func(sequence uint64) {
+		sent = append(sent, sequence)
+	}
+
+	s.next("ZRX/TOMO", send)
+	s.next("ZRX/TOMO", send)
+	s.next("WETH/TOMO", send)
+
+	assert.Equal(t, []uint64{1, 2, 1}, sent)
+	assert.Equal(t, uint64(2), s.current("ZRX/TOMO"))
+	assert.Equal(t, uint64(1), s.current("WETH/TOMO"))
+}
@@ -90,6 +90,14 @@ func (s *TradeSocket) Unsubscribe(c *Client) {
 
 // BroadcastMessage broadcasts trade message to all subscribed sockets
 func (s *TradeSocket) BroadcastMessage(channelID string, p interface{}) {
+	if publish(TradeChannel, channelID, types.UPDATE, p) {
+		return
+	}
+
+	s.broadcastMessage(channelID, p)
+}
+
+func (s *TradeSocket) broadcastMessage(channelID string, p interface{}) {
 	go func() {
 		for conn, active := range tradeSocket.subscriptions[channelID] {
 			if active {